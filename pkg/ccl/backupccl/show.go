@@ -361,7 +361,7 @@ func backupShowerDefault(
 				schemaIDToName := make(map[descpb.ID]string)
 				schemaIDToName[keys.PublicSchemaID] = catconstants.PublicSchemaName
 				for i := range manifest.Descriptors {
-					_, db, _, schema := descpb.FromDescriptor(&manifest.Descriptors[i])
+					_, db, _, schema, _ := descpb.FromDescriptor(&manifest.Descriptors[i])
 					if db != nil {
 						if _, ok := dbIDToName[db.ID]; !ok {
 							dbIDToName[db.ID] = db.Name
@@ -567,6 +567,8 @@ func showPrivileges(descriptor *descpb.Descriptor) string {
 		objectType = privilege.Type
 	case catalog.Schema:
 		objectType = privilege.Schema
+	case catalog.Function:
+		objectType = privilege.Function
 	default:
 		return ""
 	}
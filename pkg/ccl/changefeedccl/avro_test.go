@@ -426,14 +426,18 @@ func TestAvroSchema(t *testing.T) {
 			`GEOMETRY`:          `["null","bytes"]`,
 			`INET`:              `["null","string"]`,
 			`INT8`:              `["null","long"]`,
+			`INT8RANGE`:         `["null","string"]`,
 			`INTERVAL`:          `["null","string"]`,
 			`JSONB`:             `["null","string"]`,
+			`JSONPATH`:          `["null","string"]`,
 			`STRING`:            `["null","string"]`,
 			`STRING COLLATE fr`: `["null","string"]`,
 			`TIME`:              `["null",{"type":"long","logicalType":"time-micros"}]`,
 			`TIMETZ`:            `["null","string"]`,
 			`TIMESTAMP`:         `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
 			`TIMESTAMPTZ`:       `["null",{"type":"long","logicalType":"timestamp-micros"}]`,
+			`TSQUERY`:           `["null","string"]`,
+			`TSVECTOR`:          `["null","string"]`,
 			`UUID`:              `["null","string"]`,
 			`VARBIT`:            `["null",{"type":"array","items":"long"}]`,
 
@@ -441,7 +445,7 @@ func TestAvroSchema(t *testing.T) {
 			`DECIMAL(3,2)`: `["null",{"type":"bytes","logicalType":"decimal","precision":3,"scale":2},"string"]`,
 		}
 
-		for _, typ := range append(types.Scalar, types.BoolArray, types.MakeCollatedString(types.String, `fr`), types.MakeBit(3)) {
+		for _, typ := range append(types.Scalar, types.BoolArray, types.Int8Range, types.MakeCollatedString(types.String, `fr`), types.MakeBit(3)) {
 			switch typ.Family() {
 			case types.OidFamily:
 				continue
@@ -162,6 +162,7 @@ func (c *rowFetcherCache) RowFetcherForTableDesc(
 	if err := rf.Init(
 		context.TODO(),
 		c.codec,
+		nil,   /* st */
 		false, /* reverse */
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
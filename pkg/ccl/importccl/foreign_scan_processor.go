@@ -0,0 +1,274 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfra"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowexec"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+)
+
+const foreignScanProcessorName = "foreignScanProcessor"
+
+// foreignScanWalltime is the timestamp used to generate the row IDs of the
+// rows of foreign tables. It is the epoch of the row IDs generated by IMPORT,
+// so that the row IDs only depend on the index of the file of each row and on
+// its position in the file.
+var foreignScanWalltime = time.Date(2015, time.January, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+
+// foreignScanProcessor reads the rows of a foreign table from its files. It
+// converts the rows to KVs with the readers used by IMPORT, in goroutines
+// started in Start(), and decodes them back with a row.Fetcher in Next().
+type foreignScanProcessor struct {
+	execinfra.ProcessorBase
+
+	flowCtx *execinfra.FlowCtx
+	spec    execinfrapb.ForeignScanSpec
+	desc    catalog.TableDescriptor
+
+	// filter is the deserialized filter of the spec.
+	filter execinfrapb.ExprHelper
+
+	fetcher row.Fetcher
+	alloc   rowenc.DatumAlloc
+	kvs     row.SpanKVFetcher
+	// fetching is set while the fetcher is decoding a batch of KVs.
+	fetching bool
+
+	kvCh            chan row.KVBatch
+	readersGroup    ctxgroup.Group
+	cancelAndWaitFn func()
+}
+
+var _ execinfra.Processor = &foreignScanProcessor{}
+var _ execinfra.RowSource = &foreignScanProcessor{}
+
+func newForeignScanProcessor(
+	flowCtx *execinfra.FlowCtx,
+	processorID int32,
+	spec execinfrapb.ForeignScanSpec,
+	post *execinfrapb.PostProcessSpec,
+	output execinfra.RowReceiver,
+) (execinfra.Processor, error) {
+	fs := &foreignScanProcessor{
+		flowCtx: flowCtx,
+		spec:    spec,
+		desc:    tabledesc.NewBuilder(&spec.Table).BuildImmutableTable(),
+	}
+	cols := fs.desc.PublicColumns()
+	typs := catalog.ColumnTypes(cols)
+	if err := fs.Init(fs, post, typs, flowCtx, processorID, output, nil, /* memMonitor */
+		execinfra.ProcStateOpts{
+			// This processor doesn't have any inputs to drain.
+			InputsToDrain: nil,
+			TrailingMetaCallback: func() []execinfrapb.ProducerMetadata {
+				fs.close()
+				return nil
+			},
+		}); err != nil {
+		return nil, err
+	}
+
+	semaCtx := tree.MakeSemaContext()
+	if err := fs.filter.Init(spec.Filter, typs, &semaCtx, fs.EvalCtx); err != nil {
+		return nil, err
+	}
+
+	var colIdxMap catalog.TableColMap
+	var valNeededForCol util.FastIntSet
+	for i, col := range cols {
+		colIdxMap.Set(col.GetID(), i)
+	}
+	for _, ord := range spec.NeededColumns {
+		valNeededForCol.Add(int(ord))
+	}
+	if err := fs.fetcher.Init(
+		flowCtx.EvalCtx.Context,
+		flowCtx.Codec(),
+		flowCtx.Cfg.Settings,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
+		false, /* isCheck */
+		&fs.alloc,
+		nil, /* memMonitor */
+		row.FetcherTableArgs{
+			Desc:            fs.desc,
+			Index:           fs.desc.GetPrimaryIndex(),
+			ColIdxMap:       colIdxMap,
+			Cols:            cols,
+			ValNeededForCol: valNeededForCol,
+		},
+	); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Start is part of the RowSource interface.
+func (fs *foreignScanProcessor) Start(ctx context.Context) {
+	ctx = fs.StartInternal(ctx, foreignScanProcessorName)
+
+	ctx, cancel := context.WithCancel(ctx)
+	fs.readersGroup = ctxgroup.WithContext(ctx)
+	fs.cancelAndWaitFn = func() {
+		cancel()
+		_ = fs.readersGroup.Wait()
+	}
+	fs.kvCh = make(chan row.KVBatch, 10)
+
+	spec := fs.readImportDataSpec()
+	evalCtx := fs.flowCtx.NewEvalCtx()
+	evalCtx.DB = fs.flowCtx.Cfg.DB
+	semaCtx := tree.MakeSemaContext()
+	conv, err := makeInputConverter(ctx, &semaCtx, spec, evalCtx, fs.kvCh, nil /* seqChunkProvider */)
+	if err != nil {
+		close(fs.kvCh)
+		fs.MoveToDraining(err)
+		return
+	}
+	if p, ok := conv.(*parquetInputReader); ok && fs.filter.Expr != nil {
+		p.setFilter(fs.filter.Expr)
+	}
+	conv.start(fs.readersGroup)
+
+	fs.readersGroup.GoCtx(func(ctx context.Context) error {
+		defer close(fs.kvCh)
+		if !fs.spec.Ordered {
+			return conv.readFiles(ctx, spec.Uri, nil /* resumePos */, spec.Format,
+				fs.flowCtx.Cfg.ExternalStorage, spec.User())
+		}
+		// The rows of each file are in the order of their row IDs, and the row
+		// IDs of the files are in the order of their indexes, so the files are
+		// read one at a time.
+		indexes := make([]int32, 0, len(spec.Uri))
+		for idx := range spec.Uri {
+			indexes = append(indexes, idx)
+		}
+		sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+		for _, idx := range indexes {
+			if err := conv.readFiles(ctx, map[int32]string{idx: spec.Uri[idx]}, nil, /* resumePos */
+				spec.Format, fs.flowCtx.Cfg.ExternalStorage, spec.User()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// readImportDataSpec returns the spec of an IMPORT reading the files of the
+// processor into its table.
+func (fs *foreignScanProcessor) readImportDataSpec() *execinfrapb.ReadImportDataSpec {
+	spec := &execinfrapb.ReadImportDataSpec{
+		Format: fs.spec.Format,
+		Tables: map[string]*execinfrapb.ReadImportDataSpec_ImportTable{
+			fs.desc.GetName(): {Desc: &fs.spec.Table},
+		},
+		Uri:           fs.spec.Uri,
+		WalltimeNanos: foreignScanWalltime,
+		UserProto:     fs.spec.UserProto,
+	}
+	if fs.spec.Ordered {
+		spec.ReaderParallelism = 1
+	}
+	return spec
+}
+
+// Next is part of the RowSource interface.
+func (fs *foreignScanProcessor) Next() (rowenc.EncDatumRow, *execinfrapb.ProducerMetadata) {
+	for fs.State == execinfra.StateRunning {
+		if fs.fetching {
+			row, _, _, err := fs.fetcher.NextRow(fs.Ctx)
+			if err != nil {
+				fs.MoveToDraining(err)
+				break
+			}
+			if row != nil {
+				if outRow := fs.ProcessRowHelper(row); outRow != nil {
+					return outRow, nil
+				}
+				continue
+			}
+			fs.fetching = false
+		}
+
+		batch, ok := <-fs.kvCh
+		if !ok {
+			fs.MoveToDraining(fs.readersGroup.Wait())
+			break
+		}
+		fs.kvs.KVs = fs.kvsInSpans(batch.KVs)
+		if len(fs.kvs.KVs) == 0 {
+			continue
+		}
+		if err := fs.fetcher.StartScanFrom(fs.Ctx, &fs.kvs); err != nil {
+			fs.MoveToDraining(err)
+			break
+		}
+		fs.fetching = true
+	}
+	return nil, fs.DrainHelper()
+}
+
+// kvsInSpans filters the KVs in place, keeping those whose key is in the spans
+// of the spec.
+func (fs *foreignScanProcessor) kvsInSpans(kvs []roachpb.KeyValue) []roachpb.KeyValue {
+	spans := fs.spec.Spans
+	if len(spans) == 0 {
+		return kvs
+	}
+	res := kvs[:0]
+	for _, kv := range kvs {
+		// The spans are ordered and don't overlap.
+		i := sort.Search(len(spans), func(i int) bool {
+			return kv.Key.Compare(spans[i].Key) < 0
+		})
+		if i == 0 {
+			continue
+		}
+		if span := spans[i-1]; span.ContainsKey(kv.Key) || span.Key.Equal(kv.Key) {
+			res = append(res, kv)
+		}
+	}
+	return res
+}
+
+func (fs *foreignScanProcessor) close() {
+	if fs.Closed {
+		return
+	}
+	if fs.cancelAndWaitFn != nil {
+		fs.cancelAndWaitFn()
+	}
+	fs.fetcher.Close(fs.Ctx)
+	fs.InternalClose()
+}
+
+// ConsumerClosed is part of the RowSource interface.
+func (fs *foreignScanProcessor) ConsumerClosed() {
+	fs.close()
+}
+
+func init() {
+	rowexec.NewForeignScanProcessor = newForeignScanProcessor
+}
@@ -0,0 +1,299 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/errors"
+	goparquet "github.com/fraugster/parquet-go"
+	"github.com/fraugster/parquet-go/parquet"
+)
+
+// parquetInputReader reads the rows of Parquet files. The columns of the
+// files are matched to the visible columns of the table by name.
+//
+// Parquet files store the minimum and maximum values of each column in each
+// row group. If the reader is given a filter, the row groups which can't
+// contain any row passing it are skipped. The rows keep the same row number
+// as when all the row groups are read, so that their row IDs don't depend on
+// the filter.
+type parquetInputReader struct {
+	importCtx *parallelImportContext
+	// bounds are the conjuncts of the filter which can be checked against the
+	// statistics of the row groups.
+	bounds []parquetColumnBound
+}
+
+var _ inputConverter = &parquetInputReader{}
+
+func newParquetInputReader(
+	semaCtx *tree.SemaContext,
+	kvCh chan row.KVBatch,
+	tableDesc catalog.TableDescriptor,
+	walltime int64,
+	evalCtx *tree.EvalContext,
+) *parquetInputReader {
+	return &parquetInputReader{
+		importCtx: &parallelImportContext{
+			semaCtx:   semaCtx,
+			walltime:  walltime,
+			evalCtx:   evalCtx,
+			tableDesc: tableDesc,
+			kvCh:      kvCh,
+		},
+	}
+}
+
+// setFilter sets the filter used to skip row groups. Its IndexedVars
+// reference the public columns of the table by ordinal.
+func (p *parquetInputReader) setFilter(filter tree.TypedExpr) {
+	p.bounds = makeParquetColumnBounds(filter, p.importCtx.tableDesc, p.bounds[:0])
+}
+
+func (p *parquetInputReader) start(group ctxgroup.Group) {}
+
+func (p *parquetInputReader) readFiles(
+	ctx context.Context,
+	dataFiles map[int32]string,
+	resumePos map[int32]int64,
+	format roachpb.IOFileFormat,
+	makeExternalStorage cloud.ExternalStorageFactory,
+	user security.SQLUsername,
+) error {
+	return readInputFiles(ctx, dataFiles, resumePos, format, p.readFile, makeExternalStorage, user)
+}
+
+func (p *parquetInputReader) readFile(
+	ctx context.Context, input *fileReader, inputIdx int32, resumePos int64, rejected chan string,
+) error {
+	// Parquet files are read from their footer, so they need to be seekable.
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	reader, err := goparquet.NewFileReader(bytes.NewReader(data))
+	if err != nil {
+		return errors.Wrap(err, "reading parquet file")
+	}
+
+	conv, err := makeDatumConverter(ctx, p.importCtx, &importFileContext{source: inputIdx})
+	if err != nil {
+		return err
+	}
+	colIdxByName := make(map[string]int)
+	for i, col := range conv.VisibleCols {
+		colIdxByName[col.GetName()] = i
+	}
+	fileCols := reader.Columns()
+	colIdx := make([]int, len(fileCols))
+	for i, col := range fileCols {
+		idx, ok := colIdxByName[lexbase.NormalizeName(col.Name())]
+		if !ok {
+			idx = -1
+		}
+		colIdx[i] = idx
+	}
+
+	timestamp := timestampAfterEpoch(p.importCtx.walltime)
+	var rowNum int64
+	for i := 0; i < reader.RowGroupCount(); i++ {
+		// SeekToRowGroup takes the 1-based position of the row group.
+		if err := reader.SeekToRowGroup(i + 1); err != nil {
+			return errors.Wrap(err, "reading parquet row group")
+		}
+		rowGroup := reader.CurrentRowGroup()
+		if !p.rowGroupMayMatch(rowGroup, conv.EvalCtx) {
+			rowNum += rowGroup.NumRows
+			continue
+		}
+		for j := int64(0); j < rowGroup.NumRows; j++ {
+			rowNum++
+			record, err := reader.NextRow()
+			if err != nil {
+				return errors.Wrap(err, "reading parquet row")
+			}
+			for k := range conv.Datums {
+				conv.Datums[k] = tree.DNull
+			}
+			for k, col := range fileCols {
+				idx := colIdx[k]
+				if idx < 0 {
+					continue
+				}
+				v, ok := record[col.Name()]
+				if !ok {
+					continue
+				}
+				datum, err := nativeToDatum(v, conv.VisibleColTypes[idx], nil /* avroT */, conv.EvalCtx)
+				if err != nil {
+					return newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
+				}
+				conv.Datums[idx] = datum
+			}
+			rowIndex := int64(timestamp) + rowNum
+			if err := conv.Row(ctx, inputIdx, rowIndex); err != nil {
+				return newImportRowError(err, fmt.Sprintf("%v", record), rowNum)
+			}
+		}
+	}
+	return conv.SendBatch(ctx)
+}
+
+// rowGroupMayMatch returns whether the row group may contain a row passing the
+// filter of the reader, according to the statistics of its columns.
+func (p *parquetInputReader) rowGroupMayMatch(
+	rowGroup *parquet.RowGroup, evalCtx *tree.EvalContext,
+) bool {
+	for _, bound := range p.bounds {
+		for _, chunk := range rowGroup.Columns {
+			md := chunk.MetaData
+			if md == nil || md.Statistics == nil || len(md.PathInSchema) != 1 ||
+				lexbase.NormalizeName(md.PathInSchema[0]) != bound.colName {
+				continue
+			}
+			minVal := decodeParquetStatistic(md.Type, md.Statistics.MinValue, bound.typ)
+			maxVal := decodeParquetStatistic(md.Type, md.Statistics.MaxValue, bound.typ)
+			if minVal != nil && maxVal != nil && !bound.mayMatch(evalCtx, minVal, maxVal) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parquetColumnBound is a conjunct of a filter of the form <column> <op>
+// <constant>, which can be checked against the minimum and maximum values of
+// the column in a row group.
+type parquetColumnBound struct {
+	colName string
+	typ     *types.T
+	op      tree.ComparisonOperatorSymbol
+	val     tree.Datum
+}
+
+// mayMatch returns whether a value between minVal and maxVal may satisfy the
+// bound.
+func (b *parquetColumnBound) mayMatch(evalCtx *tree.EvalContext, minVal, maxVal tree.Datum) bool {
+	switch b.op {
+	case tree.EQ:
+		return minVal.Compare(evalCtx, b.val) <= 0 && maxVal.Compare(evalCtx, b.val) >= 0
+	case tree.LT:
+		return minVal.Compare(evalCtx, b.val) < 0
+	case tree.LE:
+		return minVal.Compare(evalCtx, b.val) <= 0
+	case tree.GT:
+		return maxVal.Compare(evalCtx, b.val) > 0
+	case tree.GE:
+		return maxVal.Compare(evalCtx, b.val) >= 0
+	}
+	return true
+}
+
+// parquetFlippedOps maps the comparison operators supported by
+// parquetColumnBound to the operator to use when their operands are swapped.
+var parquetFlippedOps = map[tree.ComparisonOperatorSymbol]tree.ComparisonOperatorSymbol{
+	tree.EQ: tree.EQ,
+	tree.LT: tree.GT,
+	tree.LE: tree.GE,
+	tree.GT: tree.LT,
+	tree.GE: tree.LE,
+}
+
+// makeParquetColumnBounds appends to bounds the conjuncts of the filter which
+// compare a column of a type with Parquet statistics to a constant.
+func makeParquetColumnBounds(
+	filter tree.TypedExpr, table catalog.TableDescriptor, bounds []parquetColumnBound,
+) []parquetColumnBound {
+	switch t := filter.(type) {
+	case *tree.AndExpr:
+		bounds = makeParquetColumnBounds(t.TypedLeft(), table, bounds)
+		return makeParquetColumnBounds(t.TypedRight(), table, bounds)
+	case *tree.ParenExpr:
+		return makeParquetColumnBounds(t.TypedInnerExpr(), table, bounds)
+	case *tree.ComparisonExpr:
+		op, ok := parquetFlippedOps[t.Operator.Symbol]
+		if !ok {
+			return bounds
+		}
+		ivar, isVar := t.Left.(*tree.IndexedVar)
+		val, isConst := t.Right.(tree.Datum)
+		if isVar && isConst {
+			op = t.Operator.Symbol
+		} else {
+			ivar, isVar = t.Right.(*tree.IndexedVar)
+			val, isConst = t.Left.(tree.Datum)
+		}
+		cols := table.PublicColumns()
+		if !isVar || !isConst || val == tree.DNull || ivar.Idx >= len(cols) {
+			return bounds
+		}
+		col := cols[ivar.Idx]
+		switch col.GetType().Family() {
+		case types.IntFamily, types.FloatFamily, types.StringFamily:
+		default:
+			return bounds
+		}
+		if val.ResolvedType().Family() != col.GetType().Family() {
+			return bounds
+		}
+		return append(bounds, parquetColumnBound{
+			colName: col.GetName(),
+			typ:     col.GetType(),
+			op:      op,
+			val:     val,
+		})
+	}
+	return bounds
+}
+
+// decodeParquetStatistic decodes the minimum or maximum value of a column in
+// a row group, which is encoded as a value of the physical type of the
+// column. It returns nil if the value can't be decoded as a datum of the given
+// type.
+func decodeParquetStatistic(physical parquet.Type, b []byte, typ *types.T) tree.Datum {
+	if b == nil {
+		return nil
+	}
+	switch typ.Family() {
+	case types.IntFamily:
+		switch {
+		case physical == parquet.Type_INT32 && len(b) == 4:
+			return tree.NewDInt(tree.DInt(int32(binary.LittleEndian.Uint32(b))))
+		case physical == parquet.Type_INT64 && len(b) == 8:
+			return tree.NewDInt(tree.DInt(int64(binary.LittleEndian.Uint64(b))))
+		}
+	case types.FloatFamily:
+		switch {
+		case physical == parquet.Type_FLOAT && len(b) == 4:
+			return tree.NewDFloat(tree.DFloat(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		case physical == parquet.Type_DOUBLE && len(b) == 8:
+			return tree.NewDFloat(tree.DFloat(math.Float64frombits(binary.LittleEndian.Uint64(b))))
+		}
+	case types.StringFamily:
+		if physical == parquet.Type_BYTE_ARRAY {
+			return tree.NewDString(string(b))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Licensed as a CockroachDB Enterprise file under the Cockroach Community
+// License (the "License"); you may not use this file except in compliance with
+// the License. You may obtain a copy of the License at
+//
+//     https://github.com/cockroachdb/cockroach/blob/master/licenses/CCL.txt
+
+package importccl
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/fraugster/parquet-go/parquet"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParquetRowGroupMayMatch(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	table := tabledesc.NewBuilder(&descpb.TableDescriptor{
+		Name: "t",
+		Columns: []descpb.ColumnDescriptor{
+			{ID: 1, Name: "a", Type: types.Int},
+			{ID: 2, Name: "b", Type: types.String},
+			{ID: 3, Name: "c", Type: types.Bool},
+		},
+	}).BuildImmutableTable()
+	evalCtx := tree.MakeTestingEvalContext(cluster.MakeTestingClusterSettings())
+	defer evalCtx.Stop(context.Background())
+
+	int64Stat := func(v int64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, uint64(v))
+		return b
+	}
+	// The row group contains values of a between 10 and 20, and values of b
+	// between "m" and "p".
+	rowGroup := &parquet.RowGroup{
+		NumRows: 100,
+		Columns: []*parquet.ColumnChunk{
+			{MetaData: &parquet.ColumnMetaData{
+				Type:         parquet.Type_INT64,
+				PathInSchema: []string{"A"},
+				Statistics:   &parquet.Statistics{MinValue: int64Stat(10), MaxValue: int64Stat(20)},
+			}},
+			{MetaData: &parquet.ColumnMetaData{
+				Type:         parquet.Type_BYTE_ARRAY,
+				PathInSchema: []string{"b"},
+				Statistics:   &parquet.Statistics{MinValue: []byte("m"), MaxValue: []byte("p")},
+			}},
+		},
+	}
+
+	a := tree.NewTypedOrdinalReference(0, types.Int)
+	b := tree.NewTypedOrdinalReference(1, types.String)
+	c := tree.NewTypedOrdinalReference(2, types.Bool)
+	cmp := func(op tree.ComparisonOperatorSymbol, left, right tree.TypedExpr) tree.TypedExpr {
+		return tree.NewTypedComparisonExpr(tree.MakeComparisonOperator(op), left, right)
+	}
+	and := func(left, right tree.TypedExpr) tree.TypedExpr {
+		return tree.NewTypedAndExpr(left, right)
+	}
+	i := func(v int64) tree.TypedExpr { return tree.NewDInt(tree.DInt(v)) }
+	s := func(v string) tree.TypedExpr { return tree.NewDString(v) }
+
+	testCases := []struct {
+		name     string
+		filter   tree.TypedExpr
+		numBound int
+		mayMatch bool
+	}{
+		{"eq inside", cmp(tree.EQ, a, i(15)), 1, true},
+		{"eq below", cmp(tree.EQ, a, i(5)), 1, false},
+		{"eq min", cmp(tree.EQ, a, i(10)), 1, true},
+		{"lt min", cmp(tree.LT, a, i(10)), 1, false},
+		{"le min", cmp(tree.LE, a, i(10)), 1, true},
+		{"gt max", cmp(tree.GT, a, i(20)), 1, false},
+		{"ge max", cmp(tree.GE, a, i(20)), 1, true},
+		{"flipped", cmp(tree.LT, i(20), a), 1, false},
+		{"string", cmp(tree.EQ, b, s("n")), 1, true},
+		{"string above", cmp(tree.GT, b, s("q")), 1, false},
+		{"and", and(cmp(tree.GE, a, i(12)), cmp(tree.LT, b, s("a"))), 2, false},
+		{"and inside", and(cmp(tree.GE, a, i(12)), cmp(tree.LT, b, s("n"))), 2, true},
+		{"or", tree.NewTypedOrExpr(cmp(tree.LT, a, i(0)), cmp(tree.LT, b, s("a"))), 0, true},
+		{"unsupported type", cmp(tree.EQ, c, tree.DBoolTrue), 0, true},
+		{"unsupported op", cmp(tree.NE, a, i(15)), 0, true},
+		{"null", cmp(tree.EQ, a, tree.DNull), 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &parquetInputReader{importCtx: &parallelImportContext{tableDesc: table}}
+			p.setFilter(tc.filter)
+			require.Len(t, p.bounds, tc.numBound)
+			require.Equal(t, tc.mayMatch, p.rowGroupMayMatch(rowGroup, &evalCtx))
+		})
+	}
+}
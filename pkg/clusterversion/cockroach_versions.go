@@ -285,6 +285,58 @@ const (
 	// AlterSystemTableStatisticsAddAvgSizeCol adds the column avgSize to the
 	// table system.table_statistics that contains a new statistic.
 	AlterSystemTableStatisticsAddAvgSizeCol
+	// RowLevelTTL is the version where we allow row level TTL tables.
+	RowLevelTTL
+	// NotificationsTable adds the system.notifications table, which backs
+	// LISTEN and NOTIFY.
+	NotificationsTable
+	// PreparedTransactionsTable adds the system.prepared_transactions table,
+	// which tracks the transactions prepared with PREPARE TRANSACTION.
+	PreparedTransactionsTable
+	// SkipLockedWaitPolicy allows reads to use the SKIP LOCKED wait policy,
+	// which nodes running older versions do not know how to handle.
+	SkipLockedWaitPolicy
+	// UserDefinedFunctions allows the creation of user-defined functions, whose
+	// descriptors nodes running older versions cannot decode.
+	UserDefinedFunctions
+	// StoredProcedures allows the creation of procedures, whose function
+	// descriptors have the is_procedure field that nodes running older
+	// versions do not know.
+	StoredProcedures
+	// DeferrableConstraints allows constraints to be declared DEFERRABLE, which
+	// nodes running older versions do not know how to check.
+	DeferrableConstraints
+	// TextSearchTypes allows columns of the TSVECTOR and TSQUERY types, which nodes
+	// running older versions cannot decode.
+	TextSearchTypes
+	// JsonpathType allows columns of the JSONPATH type, which nodes running older
+	// versions cannot decode.
+	JsonpathType
+	// RangeTypes allows columns of the built-in range types, which nodes running
+	// older versions cannot decode.
+	RangeTypes
+	// TrigramInvertedIndexes allows inverted indexes on the trigrams of string
+	// columns, which nodes running older versions cannot maintain.
+	TrigramInvertedIndexes
+	// DomainTypes allows the creation of domains, whose type descriptors nodes
+	// running older versions cannot decode.
+	DomainTypes
+	// CompositeTypes allows the creation of composite types, whose type
+	// descriptors nodes running older versions cannot decode.
+	CompositeTypes
+	// ForeignTables allows the creation of foreign servers and foreign tables,
+	// which nodes running older versions would treat as regular tables.
+	ForeignTables
+	// SharedLocks allows SELECT FOR SHARE to acquire shared locks, which the
+	// lock tables of nodes running older versions do not know how to handle.
+	SharedLocks
+	// LogicalReplication allows the creation of publications, which nodes
+	// running older versions drop from database descriptors, and of replication
+	// slots, whose jobs they cannot adopt.
+	LogicalReplication
+	// AdvisoryLocks allows advisory locks, which acquire shared locks and write
+	// to a keyspace that nodes running older versions do not know about.
+	AdvisoryLocks
 
 	// *************************************************
 	// Step (1): Add new versions here.
@@ -490,6 +542,74 @@ var versionsSingleton = keyedVersions{
 		Key:     AlterSystemTableStatisticsAddAvgSizeCol,
 		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 12},
 	},
+	{
+		Key:     RowLevelTTL,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 14},
+	},
+	{
+		Key:     NotificationsTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 16},
+	},
+	{
+		Key:     PreparedTransactionsTable,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 18},
+	},
+	{
+		Key:     SkipLockedWaitPolicy,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 20},
+	},
+	{
+		Key:     UserDefinedFunctions,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 22},
+	},
+	{
+		Key:     StoredProcedures,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 24},
+	},
+	{
+		Key:     DeferrableConstraints,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 26},
+	},
+	{
+		Key:     TextSearchTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 28},
+	},
+	{
+		Key:     JsonpathType,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 30},
+	},
+	{
+		Key:     RangeTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 32},
+	},
+	{
+		Key:     TrigramInvertedIndexes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 34},
+	},
+	{
+		Key:     DomainTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 36},
+	},
+	{
+		Key:     CompositeTypes,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 38},
+	},
+	{
+		Key:     ForeignTables,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 40},
+	},
+	{
+		Key:     SharedLocks,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 42},
+	},
+	{
+		Key:     LogicalReplication,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 44},
+	},
+	{
+		Key:     AdvisoryLocks,
+		Version: roachpb.Version{Major: 21, Minor: 2, Internal: 46},
+	},
 
 	// *************************************************
 	// Step (2): Add new versions here.
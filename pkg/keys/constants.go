@@ -244,6 +244,10 @@ var (
 	// NodeLivenessKeyMax is the maximum value for any node liveness key.
 	NodeLivenessKeyMax = NodeLivenessPrefix.PrefixEnd()
 	//
+	// AdvisoryLockPrefix specifies the key prefix under which SQL sessions lay
+	// down the intents that hold advisory locks, which are never committed.
+	AdvisoryLockPrefix = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("advisory-lock-")))
+	//
 	// BootstrapVersionKey is the key at which clusters bootstrapped with a version
 	// > 1.0 persist the version at which they were bootstrapped.
 	BootstrapVersionKey = roachpb.Key(makeKey(SystemPrefix, roachpb.RKey("bootstrap-version")))
@@ -406,6 +410,8 @@ const (
 	TenantUsageTableID                  = 45
 	SQLInstancesTableID                 = 46
 	SpanConfigurationsTableID           = 47
+	NotificationsTableID                = 48
+	PreparedTransactionsTableID         = 49
 
 	// CommentType is type for system.comments
 	DatabaseCommentType   = 0
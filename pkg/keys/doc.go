@@ -245,6 +245,7 @@ var _ = [...]interface{}{
 	// 	replicated across the cluster.
 	SystemPrefix,
 	NodeLivenessPrefix,  // "\x00liveness-"
+	AdvisoryLockPrefix,  // "advisory-lock-"
 	BootstrapVersionKey, // "bootstrap-version"
 	descIDGenerator,     // "desc-idgen"
 	NodeIDGenerator,     // "node-idgen"
@@ -163,6 +163,12 @@ func (e sqlEncoder) ZoneKey(id uint32) roachpb.Key {
 	return MakeFamilyKey(k, uint32(ZonesTableConfigColumnID))
 }
 
+// AdvisoryLockKeyPrefix returns the key prefix under which SQL sessions hold
+// advisory locks.
+func (e sqlEncoder) AdvisoryLockKeyPrefix() roachpb.Key {
+	return append(e.TenantPrefix(), AdvisoryLockPrefix...)
+}
+
 // MigrationKeyPrefix returns the key prefix to store all migration details.
 func (e sqlEncoder) MigrationKeyPrefix() roachpb.Key {
 	return append(e.TenantPrefix(), MigrationPrefix...)
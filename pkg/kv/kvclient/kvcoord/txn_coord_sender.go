@@ -56,6 +56,14 @@ const (
 	// two - transaction definitely cleaned up, and transaction potentially
 	// cleaned up.
 	txnFinalized
+
+	// txnPrepared means that an EndTxn(commit=true, prepare=true) has been
+	// executed successfully, moving the transaction to the PREPARED status. The
+	// prepared transaction retains its locks but is no longer owned by this
+	// TxnCoordSender: it can only be committed or rolled back through a new
+	// TxnCoordSender created from the prepared transaction proto. Further
+	// batches, including rollbacks, are rejected.
+	txnPrepared
 )
 
 // A TxnCoordSender is the production implementation of client.TxnSender. It is
@@ -121,6 +129,17 @@ type TxnCoordSender struct {
 		// This field is only populated on rootTxns.
 		userPriority roachpb.UserPriority
 
+		// isoLevel is the txn's isolation level. This field is only populated on
+		// rootTxns.
+		isoLevel kv.IsolationLevel
+
+		// stmtSavepoint is set between calls to BeginStatement and EndStatement
+		// for transactions running under ReadCommitted isolation. It captures the
+		// state of the transaction at the beginning of the current statement, so
+		// that a write-write conflict encountered by the statement can be handled
+		// by rolling back to it instead of restarting the whole transaction.
+		stmtSavepoint *savepoint
+
 		// commitWaitDeferred is set to true when the transaction commit-wait
 		// state is deferred and should not be run automatically. Instead, the
 		// caller of DeferCommitWait has assumed responsibility for performing
@@ -202,12 +221,19 @@ func newRootTxnCoordSender(
 ) kv.TxnSender {
 	txn.AssertInitialized(context.TODO())
 
-	if txn.Status != roachpb.PENDING {
+	switch txn.Status {
+	case roachpb.PENDING:
+		if txn.Sequence != 0 {
+			log.Fatalf(context.TODO(), "cannot initialize root txn with seq != 0: %s", txn)
+		}
+	case roachpb.PREPARED:
+		// A prepared transaction is being resumed in order to commit or roll it
+		// back. Its commit timestamp was fixed when it was prepared.
+		txn.ReadTimestamp = txn.WriteTimestamp
+		txn.CommitTimestampFixed = true
+	default:
 		log.Fatalf(context.TODO(), "unexpected non-pending txn in RootTransactionalSender: %s", txn)
 	}
-	if txn.Sequence != 0 {
-		log.Fatalf(context.TODO(), "cannot initialize root txn with seq != 0: %s", txn)
-	}
 
 	tcs := &TxnCoordSender{
 		typ:                   kv.RootTxn,
@@ -276,6 +302,13 @@ func newRootTxnCoordSender(
 	tcs.connectInterceptors()
 
 	tcs.mu.txn.Update(txn)
+	if txn.Status == roachpb.PREPARED {
+		// Seed the lock footprint with the locks held by the prepared
+		// transaction so that they are attached to its EndTxn request and
+		// resolved when the transaction is committed or rolled back.
+		tcs.interceptorAlloc.txnPipeliner.lockFootprint.insert(txn.LockSpans...)
+		tcs.mu.txn.LockSpans = nil
+	}
 	return tcs
 }
 
@@ -442,6 +475,13 @@ func (tc *TxnCoordSender) finalizeNonLockingTxnLocked(
 			ba.Txn = txn
 			return tc.updateStateLocked(ctx, ba, nil /* br */, pErr)
 		}
+		if et.Prepare {
+			// A transaction which has not acquired any locks has nothing to
+			// retain, so it is prepared without writing a transaction record.
+			tc.mu.txn.Status = roachpb.PREPARED
+			tc.prepareAndCleanupTxnLocked(ctx)
+			return nil
+		}
 		// Mark the transaction as committed so that, in case this commit is done by
 		// the closure passed to db.Txn()), db.Txn() doesn't attempt to commit again.
 		// Also so that the correct metric gets incremented.
@@ -516,10 +556,14 @@ func (tc *TxnCoordSender) Send(
 	pErr = tc.updateStateLocked(ctx, ba, br, pErr)
 
 	// If we succeeded to commit, or we attempted to rollback, we move to
-	// txnFinalized.
+	// txnFinalized. If we succeeded to prepare, we move to txnPrepared.
 	if req, ok := ba.GetArg(roachpb.EndTxn); ok {
 		et := req.(*roachpb.EndTxnRequest)
-		if (et.Commit && pErr == nil) || !et.Commit {
+		if et.Prepare {
+			if pErr == nil {
+				tc.prepareAndCleanupTxnLocked(ctx)
+			}
+		} else if (et.Commit && pErr == nil) || !et.Commit {
 			tc.finalizeAndCleanupTxnLocked(ctx)
 			if et.Commit {
 				if err := tc.maybeCommitWait(ctx, false /* deferred */); err != nil {
@@ -662,7 +706,8 @@ func (tc *TxnCoordSender) maybeCommitWait(ctx context.Context, deferred bool) er
 func (tc *TxnCoordSender) maybeRejectClientLocked(
 	ctx context.Context, ba *roachpb.BatchRequest,
 ) *roachpb.Error {
-	if ba != nil && ba.IsSingleAbortTxnRequest() && tc.mu.txn.Status != roachpb.COMMITTED {
+	if ba != nil && ba.IsSingleAbortTxnRequest() && tc.mu.txn.Status != roachpb.COMMITTED &&
+		tc.mu.txnState != txnPrepared {
 		// As a special case, we allow rollbacks to be sent at any time. Any
 		// rollback attempt moves the TxnCoordSender state to txnFinalized, but higher
 		// layers are free to retry rollbacks if they want (and they do, for
@@ -672,6 +717,10 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 		// committed, to avoid sending the rollback concurrently with the
 		// txnCommitter asynchronously making the commit explicit. See:
 		// https://github.com/cockroachdb/cockroach/issues/68643
+		//
+		// We also reject this if the transaction was prepared through this
+		// TxnCoordSender, since a prepared transaction must outlive the
+		// coordinator which prepared it.
 		return nil
 	}
 
@@ -691,6 +740,11 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 			reason = roachpb.TransactionStatusError_REASON_TXN_COMMITTED
 		}
 		return roachpb.NewErrorWithTxn(roachpb.NewTransactionStatusError(reason, msg), &tc.mu.txn)
+	case txnPrepared:
+		msg := fmt.Sprintf("client already prepared the transaction. "+
+			"Trying to execute: %s", ba.Summary())
+		return roachpb.NewErrorWithTxn(roachpb.NewTransactionStatusError(
+			roachpb.TransactionStatusError_REASON_UNKNOWN, msg), &tc.mu.txn)
 	}
 
 	// Check the transaction proto state, along with any finalized transaction
@@ -718,6 +772,12 @@ func (tc *TxnCoordSender) maybeRejectClientLocked(
 			ctx, abortedErr, roachpb.NormalUserPriority, tc.clock)
 		return roachpb.NewError(roachpb.NewTransactionRetryWithProtoRefreshError(
 			abortedErr.String(), tc.mu.txn.ID, newTxn))
+	case protoStatus == roachpb.PREPARED && hbObservedStatus == roachpb.PENDING:
+		// The transaction was prepared through another TxnCoordSender and is
+		// being resumed. It can only be committed or rolled back.
+		if ba != nil && !ba.IsSingleEndTxnRequest() {
+			return roachpb.NewErrorf("cannot execute %s in prepared transaction %s", ba.Summary(), tc.mu.txn)
+		}
 	case protoStatus != roachpb.PENDING || hbObservedStatus != roachpb.PENDING:
 		// The transaction proto is in an unexpected state.
 		return roachpb.NewErrorf(
@@ -735,6 +795,14 @@ func (tc *TxnCoordSender) finalizeAndCleanupTxnLocked(ctx context.Context) {
 	tc.cleanupTxnLocked(ctx)
 }
 
+// prepareAndCleanupTxnLocked marks the transaction state as prepared and
+// closes all interceptors. This stops the heartbeat loop, which must not
+// outlive the coordinator's ownership of the transaction.
+func (tc *TxnCoordSender) prepareAndCleanupTxnLocked(ctx context.Context) {
+	tc.mu.txnState = txnPrepared
+	tc.cleanupTxnLocked(ctx)
+}
+
 // cleanupTxnLocked closes all the interceptors.
 func (tc *TxnCoordSender) cleanupTxnLocked(ctx context.Context) {
 	if tc.mu.closed {
@@ -803,6 +871,12 @@ func (tc *TxnCoordSender) handleRetryableErrLocked(
 	errTxnID := pErr.GetTxn().ID
 	newTxn := roachpb.PrepareTransactionForRetry(ctx, pErr, tc.mu.userPriority, tc.clock)
 
+	// ReadCommitted transactions retry individual statements instead of
+	// restarting when possible.
+	if tc.canRetryStatementLocked(pErr, newTxn) {
+		return tc.retryStatementLocked(ctx, pErr, newTxn)
+	}
+
 	// We'll pass a TransactionRetryWithProtoRefreshError up to the next layer.
 	retErr := roachpb.NewTransactionRetryWithProtoRefreshError(
 		pErr.String(),
@@ -983,6 +1057,27 @@ func (tc *TxnCoordSender) SetUserPriority(pri roachpb.UserPriority) error {
 	return nil
 }
 
+// SetIsolationLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) SetIsolationLevel(isoLevel kv.IsolationLevel) error {
+	if tc.typ != kv.RootTxn {
+		return errors.AssertionFailedf("cannot set isolation level on non-root txn")
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.mu.active && isoLevel != tc.mu.isoLevel {
+		return errors.New("cannot change the isolation level of a running transaction")
+	}
+	tc.mu.isoLevel = isoLevel
+	return nil
+}
+
+// IsolationLevel is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) IsolationLevel() kv.IsolationLevel {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.isoLevel
+}
+
 // SetDebugName is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) SetDebugName(name string) {
 	tc.mu.Lock()
@@ -1112,6 +1207,13 @@ func (tc *TxnCoordSender) Epoch() enginepb.TxnEpoch {
 	return tc.mu.txn.Epoch
 }
 
+// Key is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) Key() roachpb.Key {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.mu.txn.Key
+}
+
 // IsLocking is part of the client.TxnSender interface.
 func (tc *TxnCoordSender) IsLocking() bool {
 	tc.mu.Lock()
@@ -1289,6 +1391,25 @@ func (tc *TxnCoordSender) GetSteppingMode(ctx context.Context) (curMode kv.Stepp
 	return curMode
 }
 
+// GetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) GetReadSeqNum() enginepb.TxnSeq {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.interceptorAlloc.txnSeqNumAllocator.readSeq
+}
+
+// SetReadSeqNum is part of the TxnSender interface.
+func (tc *TxnCoordSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if seq < 0 || seq > tc.interceptorAlloc.txnSeqNumAllocator.writeSeq {
+		return errors.AssertionFailedf("invalid read seq num < 0 || > writeSeq (%d): %d",
+			tc.interceptorAlloc.txnSeqNumAllocator.writeSeq, seq)
+	}
+	tc.interceptorAlloc.txnSeqNumAllocator.readSeq = seq
+	return nil
+}
+
 // ManualRefresh is part of the TxnSender interface.
 func (tc *TxnCoordSender) ManualRefresh(ctx context.Context) error {
 	tc.mu.Lock()
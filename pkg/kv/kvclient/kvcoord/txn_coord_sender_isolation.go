@@ -0,0 +1,168 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvcoord
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// A transaction running under the ReadCommitted isolation level is executed
+// as a sequence of statements, each delimited by calls to BeginStatement and
+// EndStatement. Compared to a Serializable transaction, such a transaction
+// differs in three ways:
+//
+// 1. Every statement reads from a fresh snapshot. BeginStatement forwards the
+//    transaction's read and write timestamps to the current time and resets
+//    its uncertainty interval.
+//
+// 2. Reads are only refreshed for the duration of the statement that performed
+//    them. EndStatement refreshes the statement's reads if its writes were
+//    pushed (so that the statement's writes remain consistent with what it
+//    read) and then discards them from the refresh footprint. As a result,
+//    forwarding the transaction's timestamp between statements or on commit
+//    never requires a refresh, and the transaction never has to restart
+//    because of a failed refresh of a previous statement's reads.
+//
+// 3. Retryable errors encountered while a statement is running (write-write
+//    conflicts, uncertainty errors, failed statement-level refreshes) are
+//    handled by rolling back the transaction to a savepoint taken by
+//    BeginStatement and forwarding its timestamp, without bumping the epoch.
+//    The TransactionRetryWithProtoRefreshError returned to the client carries
+//    a transaction with an unchanged ID and epoch, which indicates that only
+//    the statement needs to be retried.
+//
+// Errors that cannot be addressed by rolling back the statement (e.g. the
+// failure of a pipelined write performed by a previous statement or an
+// aborted transaction) restart the transaction as usual.
+
+// BeginStatement is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) BeginStatement(ctx context.Context) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.mu.isoLevel != kv.ReadCommitted {
+		return nil
+	}
+	if err := tc.assertNotFinalized(); err != nil {
+		return err
+	}
+	if tc.mu.txnState == txnError {
+		return tc.mu.storedErr.GoError()
+	}
+	if tc.mu.stmtSavepoint != nil {
+		return errors.AssertionFailedf("BeginStatement called while a statement is in progress")
+	}
+
+	// Establish a new read snapshot for the statement, unless the transaction
+	// is pinned to a fixed timestamp.
+	if !tc.mu.txn.CommitTimestampFixed {
+		now := tc.clock.Now()
+		tc.mu.txn.WriteTimestamp.Forward(now)
+		tc.mu.txn.ReadTimestamp.Forward(tc.mu.txn.WriteTimestamp)
+		tc.mu.txn.WriteTooOld = false
+		// Values observed on other nodes before the new snapshot was taken no
+		// longer bound the uncertainty of the statement's reads.
+		tc.mu.txn.GlobalUncertaintyLimit.Forward(now.Add(tc.clock.MaxOffset().Nanoseconds(), 0))
+		tc.mu.txn.ResetObservedTimestamps()
+	}
+	tc.interceptorAlloc.txnSpanRefresher.resetForStatementLocked(tc.mu.txn.ReadTimestamp)
+
+	tc.mu.stmtSavepoint = tc.createSavepointLocked(ctx)
+	return nil
+}
+
+// EndStatement is part of the client.TxnSender interface.
+func (tc *TxnCoordSender) EndStatement(ctx context.Context) error {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.mu.isoLevel != kv.ReadCommitted {
+		return nil
+	}
+	defer func() {
+		tc.mu.stmtSavepoint = nil
+		tc.interceptorAlloc.txnSpanRefresher.resetForStatementLocked(tc.mu.txn.ReadTimestamp)
+	}()
+	if tc.mu.txnState != txnPending || tc.mu.stmtSavepoint == nil {
+		return nil
+	}
+	if tc.mu.txn.ReadTimestamp == tc.mu.txn.WriteTimestamp {
+		return nil
+	}
+
+	// The statement's writes have been pushed above its read snapshot. Refresh
+	// the statement's reads before they are discarded, so that the writes are
+	// consistent with the data that they were derived from. If the refresh
+	// fails, the statement is rolled back and needs to be retried.
+	var ba roachpb.BatchRequest
+	ba.Txn = tc.mu.txn.Clone()
+	const force = true
+	refreshedBa, pErr := tc.interceptorAlloc.txnSpanRefresher.maybeRefreshPreemptivelyLocked(ctx, ba, force)
+	if pErr != nil {
+		pErr = tc.updateStateLocked(ctx, ba, nil, pErr)
+	} else {
+		var br roachpb.BatchResponse
+		br.Txn = refreshedBa.Txn
+		pErr = tc.updateStateLocked(ctx, ba, &br, nil)
+	}
+	return pErr.GoError()
+}
+
+// canRetryStatementLocked returns whether the given retryable error can be
+// handled by retrying the current statement of a ReadCommitted transaction.
+func (tc *TxnCoordSender) canRetryStatementLocked(
+	pErr *roachpb.Error, newTxn roachpb.Transaction,
+) bool {
+	if tc.typ != kv.RootTxn || tc.mu.isoLevel != kv.ReadCommitted || tc.mu.stmtSavepoint == nil {
+		return false
+	}
+	if newTxn.ID != tc.mu.txn.ID || tc.mu.txn.CommitTimestampFixed {
+		// The transaction was aborted, or its timestamp cannot be moved.
+		return false
+	}
+	if tErr, ok := pErr.GetDetail().(*roachpb.TransactionRetryError); ok &&
+		tErr.Reason == roachpb.RETRY_ASYNC_WRITE_FAILURE {
+		// The failed write might have been performed by a previous statement.
+		return false
+	}
+	return tc.checkSavepointLocked(tc.mu.stmtSavepoint) == nil
+}
+
+// retryStatementLocked rolls back the transaction to the savepoint taken at the
+// beginning of the current statement and forwards its timestamp past the
+// conflict described by pErr. newTxn is the transaction that would have been
+// used for a full restart.
+func (tc *TxnCoordSender) retryStatementLocked(
+	ctx context.Context, pErr *roachpb.Error, newTxn roachpb.Transaction,
+) *roachpb.TransactionRetryWithProtoRefreshError {
+	log.VEventf(ctx, 2, "rolling back statement for retry after: %s", pErr)
+	tc.metrics.StatementRetries.Inc(1)
+
+	// Pick up the information carried by the error (observed timestamps,
+	// priority, pushed timestamps) without moving to a new epoch.
+	if errTxn := pErr.GetTxn(); errTxn != nil && errTxn.Epoch == tc.mu.txn.Epoch {
+		tc.mu.txn.Update(errTxn)
+	}
+	tc.mu.txn.UpgradePriority(newTxn.Priority)
+	tc.mu.txn.Refresh(newTxn.WriteTimestamp)
+
+	tc.rollbackToSavepointLocked(ctx, tc.mu.stmtSavepoint)
+	tc.interceptorAlloc.txnSpanRefresher.resetForStatementLocked(tc.mu.txn.ReadTimestamp)
+	tc.mu.txnState = txnPending
+
+	return roachpb.NewTransactionRetryWithProtoRefreshError(
+		pErr.String(), tc.mu.txn.ID, tc.mu.txn)
+}
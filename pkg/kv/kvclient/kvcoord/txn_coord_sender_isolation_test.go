@@ -0,0 +1,122 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package kvcoord
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadCommittedStatementRetry verifies that a ReadCommitted transaction
+// reads from a new snapshot in every statement and that a write-write conflict
+// causes only the conflicting statement to be rolled back, without restarting
+// the transaction.
+func TestReadCommittedStatementRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	keyA, keyB := roachpb.Key("a"), roachpb.Key("b")
+	require.NoError(t, db.Put(ctx, keyB, "b0"))
+
+	txn := db.NewTxn(ctx, "read committed")
+	require.NoError(t, txn.SetIsolationLevel(kv.ReadCommitted))
+	require.Equal(t, kv.ReadCommitted, txn.IsolationLevel())
+	txnID := txn.ID()
+	tc := txn.Sender().(*TxnCoordSender)
+	retriesBefore := tc.metrics.StatementRetries.Count()
+
+	// runStmt runs fn as a single statement of the transaction.
+	runStmt := func(fn func() error) error {
+		require.NoError(t, txn.BeginStatement(ctx))
+		err := fn()
+		if endErr := txn.EndStatement(ctx); err == nil {
+			err = endErr
+		}
+		return err
+	}
+	getB := func() string {
+		var res string
+		require.NoError(t, runStmt(func() error {
+			kv, err := txn.Get(ctx, keyB)
+			if err != nil {
+				return err
+			}
+			res = string(kv.ValueBytes())
+			return nil
+		}))
+		return res
+	}
+
+	require.NoError(t, runStmt(func() error { return txn.Put(ctx, keyA, "a1") }))
+	require.Equal(t, "b0", getB())
+
+	// A value committed after the previous statement is visible to the next
+	// one.
+	require.NoError(t, db.Put(ctx, keyB, "b1"))
+	require.Equal(t, "b1", getB())
+
+	// A statement whose reads are invalidated by a concurrent write before it
+	// writes is rolled back and needs to be retried, but the transaction keeps
+	// its epoch and the writes of its previous statements.
+	err := runStmt(func() error {
+		if _, err := txn.Get(ctx, keyB); err != nil {
+			return err
+		}
+		require.NoError(t, db.Put(ctx, keyB, "b2"))
+		return txn.Put(ctx, keyB, "b-stale")
+	})
+	require.True(t, errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)), "%+v", err)
+	require.Equal(t, txnID, txn.ID())
+	require.Equal(t, enginepb.TxnEpoch(0), txn.Epoch())
+	require.Greater(t, tc.metrics.StatementRetries.Count(), retriesBefore)
+
+	// The retry of the statement observes the conflicting write.
+	require.Equal(t, "b2", getB())
+	require.NoError(t, runStmt(func() error { return txn.Put(ctx, keyB, "b3") }))
+	require.NoError(t, txn.Commit(ctx))
+
+	for key, exp := range map[string]string{"a": "a1", "b": "b3"} {
+		kv, err := db.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, exp, string(kv.ValueBytes()))
+	}
+}
+
+// TestReadCommittedIsolationLevelImmutable verifies that the isolation level
+// of a transaction cannot be changed once it has performed operations.
+func TestReadCommittedIsolationLevelImmutable(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	txn := db.NewTxn(ctx, "test")
+	require.NoError(t, txn.Put(ctx, "a", "b"))
+	require.Error(t, txn.SetIsolationLevel(kv.ReadCommitted))
+	require.Equal(t, kv.Serializable, txn.IsolationLevel())
+	require.NoError(t, txn.Rollback(ctx))
+}
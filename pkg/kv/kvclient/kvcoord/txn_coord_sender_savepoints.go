@@ -79,16 +79,21 @@ func (tc *TxnCoordSender) CreateSavepoint(ctx context.Context) (kv.SavepointToke
 		return &initialSavepoint, nil
 	}
 
+	return tc.createSavepointLocked(ctx), nil
+}
+
+// createSavepointLocked creates a savepoint capturing the current state of the
+// (active) transaction.
+func (tc *TxnCoordSender) createSavepointLocked(ctx context.Context) *savepoint {
 	s := &savepoint{
-		active: true, // we've handled the not-active case above
+		active: true,
 		txnID:  tc.mu.txn.ID,
 		epoch:  tc.mu.txn.Epoch,
 	}
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.createSavepointLocked(ctx, s)
 	}
-
-	return s, nil
+	return s
 }
 
 // RollbackToSavepoint is part of the client.TxnSender interface.
@@ -138,6 +143,14 @@ func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s kv.Savepoin
 
 	tc.mu.active = sp.active
 
+	tc.rollbackToSavepointLocked(ctx, sp)
+	return nil
+}
+
+// rollbackToSavepointLocked restores the interceptors' state from the given
+// savepoint and marks all the writes performed since the savepoint was created
+// as ignored.
+func (tc *TxnCoordSender) rollbackToSavepointLocked(ctx context.Context, sp *savepoint) {
 	for _, reqInt := range tc.interceptorStack {
 		reqInt.rollbackToSavepointLocked(ctx, *sp)
 	}
@@ -150,8 +163,6 @@ func (tc *TxnCoordSender) RollbackToSavepoint(ctx context.Context, s kv.Savepoin
 				Start: sp.seqNum + 1, End: tc.interceptorAlloc.txnSeqNumAllocator.writeSeq,
 			})
 	}
-
-	return nil
 }
 
 // ReleaseSavepoint is part of the client.TxnSender interface.
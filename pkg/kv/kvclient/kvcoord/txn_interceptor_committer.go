@@ -216,6 +216,10 @@ func (tc *txnCommitter) SendLocked(
 	switch br.Txn.Status {
 	case roachpb.STAGING:
 		// Continue with STAGING-specific validation and cleanup.
+	case roachpb.PREPARED:
+		// The transaction was prepared. Preparing is never performed in
+		// parallel with writes, so there's nothing left to validate.
+		return br, nil
 	case roachpb.COMMITTED:
 		// The transaction is explicitly committed. This is possible if all
 		// in-flight writes were sent to the same range as the EndTxn request,
@@ -323,7 +327,9 @@ func (tc *txnCommitter) sendLockedWithElidedEndTxn(
 	// request was optimized away. The caller may still inspect the transaction
 	// struct, so we manually update it here to emulate a true transaction.
 	status := roachpb.ABORTED
-	if et.Commit {
+	if et.Prepare {
+		status = roachpb.PREPARED
+	} else if et.Commit {
 		status = roachpb.COMMITTED
 	}
 	br.Txn = cloneWithStatus(br.Txn, status)
@@ -365,6 +371,13 @@ func (tc *txnCommitter) canCommitInParallel(
 		return false
 	}
 
+	// If the transaction is being prepared, we don't allow it to be staged.
+	// A prepared transaction must have a PREPARED transaction record, so it
+	// can't rely on the implicit commit condition.
+	if et.Prepare {
+		return false
+	}
+
 	// If the transaction has a commit trigger, we don't allow it to commit in
 	// parallel with writes. There's no fundamental reason for this restriction,
 	// but for now it's not worth the complication.
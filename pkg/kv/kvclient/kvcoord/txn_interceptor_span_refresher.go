@@ -652,6 +652,18 @@ func (sr *txnSpanRefresher) epochBumpedLocked() {
 	sr.refreshedTimestamp.Reset()
 }
 
+// resetForStatementLocked discards the refresh spans collected so far and
+// forwards the refreshed timestamp to the given read timestamp. It is used by
+// ReadCommitted transactions, which only refresh the reads performed by the
+// current statement.
+func (sr *txnSpanRefresher) resetForStatementLocked(readTimestamp hlc.Timestamp) {
+	sr.refreshFootprint.clear()
+	sr.refreshInvalid = false
+	if !sr.refreshedTimestamp.IsEmpty() {
+		sr.refreshedTimestamp.Forward(readTimestamp)
+	}
+}
+
 // createSavepointLocked is part of the txnInterceptor interface.
 func (sr *txnSpanRefresher) createSavepointLocked(ctx context.Context, s *savepoint) {
 	s.refreshSpans = make([]roachpb.Span, len(sr.refreshFootprint.asSlice()))
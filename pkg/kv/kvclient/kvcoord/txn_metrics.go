@@ -51,6 +51,10 @@ type TxnMetrics struct {
 	RestartsTxnPush                telemetry.CounterWithMetric
 	RestartsUnknown                telemetry.CounterWithMetric
 
+	// StatementRetries is the number of times a statement of a ReadCommitted
+	// transaction was retried instead of restarting the transaction.
+	StatementRetries *metric.Counter
+
 	// End transaction failure counters.
 	RollbacksFailed      *metric.Counter
 	AsyncRollbacksFailed *metric.Counter
@@ -246,6 +250,12 @@ var (
 		Measurement: "Restarted Transactions",
 		Unit:        metric.Unit_COUNT,
 	}
+	metaStatementRetries = metric.Metadata{
+		Name:        "txn.statement_retries",
+		Help:        "Number of statement retries performed by read committed transactions",
+		Measurement: "Retries",
+		Unit:        metric.Unit_COUNT,
+	}
 	metaRollbacksFailed = metric.Metadata{
 		Name:        "txn.rollbacks.failed",
 		Help:        "Number of KV transaction that failed to send final abort",
@@ -288,6 +298,7 @@ func MakeTxnMetrics(histogramWindow time.Duration) TxnMetrics {
 		RestartsTxnAborted:             telemetry.NewCounterWithMetric(metaRestartsTxnAborted),
 		RestartsTxnPush:                telemetry.NewCounterWithMetric(metaRestartsTxnPush),
 		RestartsUnknown:                telemetry.NewCounterWithMetric(metaRestartsUnknown),
+		StatementRetries:               metric.NewCounter(metaStatementRetries),
 		RollbacksFailed:                metric.NewCounter(metaRollbacksFailed),
 		AsyncRollbacksFailed:           metric.NewCounter(metaAsyncRollbacksFailed),
 	}
@@ -770,6 +770,7 @@ func TestTxnWaitPolicies(t *testing.T) {
 
 	testutils.RunTrueAndFalse(t, "highPriority", func(t *testing.T, highPriority bool) {
 		key := []byte("b")
+		require.NoError(t, s.DB.Put(ctx, key, "old value"))
 		txn := s.DB.NewTxn(ctx, "test txn")
 		require.NoError(t, txn.Put(ctx, key, "new value"))
 
@@ -818,6 +819,29 @@ func TestTxnWaitPolicies(t *testing.T) {
 		require.True(t, errors.As(err, &wiErr))
 		require.Equal(t, roachpb.WriteIntentError_REASON_WAIT_POLICY, wiErr.Reason)
 
+		// SkipLocked wait policy.
+		type skipRes struct {
+			res []kv.Result
+			err error
+		}
+		skipC := make(chan skipRes)
+		go func() {
+			var b kv.Batch
+			b.Header.UserPriority = pri
+			b.Header.WaitPolicy = lock.WaitPolicy_SkipLocked
+			b.Get(key)
+			err := s.DB.Run(ctx, &b)
+			skipC <- skipRes{res: b.Results, err: err}
+		}()
+
+		// Should return successfully immediately, without blocking, and without
+		// returning the locked key. Priority does not matter.
+		res := <-skipC
+		require.NoError(t, res.err)
+		require.Len(t, res.res, 1)
+		require.Len(t, res.res[0].Rows, 1)
+		require.False(t, res.res[0].Rows[0].Exists())
+
 		// Let blocked requests proceed.
 		require.NoError(t, txn.Commit(ctx))
 		if !highPriority {
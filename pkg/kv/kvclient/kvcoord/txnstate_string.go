@@ -11,11 +11,12 @@ func _() {
 	_ = x[txnPending-0]
 	_ = x[txnError-1]
 	_ = x[txnFinalized-2]
+	_ = x[txnPrepared-3]
 }
 
-const _txnState_name = "txnPendingtxnErrortxnFinalized"
+const _txnState_name = "txnPendingtxnErrortxnFinalizedtxnPrepared"
 
-var _txnState_index = [...]uint8{0, 10, 18, 30}
+var _txnState_index = [...]uint8{0, 10, 18, 30, 41}
 
 func (i txnState) String() string {
 	if i < 0 || i >= txnState(len(_txnState_index)-1) {
@@ -79,8 +79,9 @@ func declareKeysEndTxn(
 	}
 
 	// If the request is intending to finalize the transaction record then it
-	// needs to declare a few extra keys.
-	if !et.IsParallelCommit() {
+	// needs to declare a few extra keys. Requests which stage or prepare the
+	// transaction record do not resolve any locks.
+	if !et.IsParallelCommit() && !et.Prepare {
 		// All requests that intend on resolving local locks need to depend on
 		// the range descriptor because they need to determine which locks are
 		// within the local range.
@@ -184,7 +185,9 @@ func EndTxn(
 	ms := cArgs.Stats
 	reply := resp.(*roachpb.EndTxnResponse)
 
-	if err := VerifyTransaction(h, args, roachpb.PENDING, roachpb.STAGING, roachpb.ABORTED); err != nil {
+	if err := VerifyTransaction(
+		h, args, roachpb.PENDING, roachpb.STAGING, roachpb.ABORTED, roachpb.PREPARED,
+	); err != nil {
 		return result.Result{}, err
 	}
 	if args.Require1PC {
@@ -196,6 +199,9 @@ func EndTxn(
 	if args.Commit && args.Poison {
 		return result.Result{}, errors.AssertionFailedf("cannot poison during a committing EndTxn request")
 	}
+	if args.Prepare && !args.Commit {
+		return result.Result{}, errors.AssertionFailedf("cannot prepare during a rollback EndTxn request")
+	}
 
 	key := keys.TransactionKey(h.Txn.Key, h.Txn.ID)
 
@@ -281,6 +287,20 @@ func EndTxn(
 					"programming error: epoch regression: %d", h.Txn.Epoch)
 			}
 
+		case roachpb.PREPARED:
+			// The transaction was prepared and is now being committed or rolled
+			// back, possibly by a different coordinator than the one that
+			// prepared it.
+			if args.Prepare {
+				return result.Result{}, roachpb.NewTransactionStatusError(
+					roachpb.TransactionStatusError_REASON_UNKNOWN,
+					"already prepared")
+			}
+			if h.Txn.Epoch != reply.Txn.Epoch {
+				return result.Result{}, errors.AssertionFailedf(
+					"programming error: epoch mismatch with prepared txn: %d", h.Txn.Epoch)
+			}
+
 		default:
 			return result.Result{}, errors.AssertionFailedf("bad txn status: %s", reply.Txn)
 		}
@@ -290,11 +310,43 @@ func EndTxn(
 	}
 
 	// Attempt to commit or abort the transaction per the args.Commit parameter.
-	if args.Commit {
+	if args.Commit && recordAlreadyExisted && existingTxn.Status == roachpb.PREPARED {
+		// The commit timestamp of a prepared transaction was fixed when it was
+		// prepared, at which point it was verified to be committable. Verify it
+		// again like for any other commit, in case the committing transaction
+		// has a deadline or the record was pushed in the meantime. The
+		// prepared transaction can't be retried, so it is left PREPARED and can
+		// only be rolled back.
+		if retry, reason, extraMsg := IsEndTxnTriggeringRetryError(reply.Txn, args); retry {
+			return result.Result{}, roachpb.NewTransactionRetryError(reason, extraMsg)
+		}
+		reply.Txn.Status = roachpb.COMMITTED
+	} else if args.Commit {
 		if retry, reason, extraMsg := IsEndTxnTriggeringRetryError(reply.Txn, args); retry {
 			return result.Result{}, roachpb.NewTransactionRetryError(reason, extraMsg)
 		}
 
+		// If the transaction is being prepared, write the prepared transaction
+		// record and return without running commit triggers or resolving local
+		// locks. The locks are retained until the transaction is committed or
+		// rolled back.
+		if args.Prepare {
+			if ct := args.InternalCommitTrigger; ct != nil {
+				err := errors.Errorf("cannot prepare transaction with a commit trigger: %+v", ct)
+				return result.Result{}, err
+			}
+			if args.IsParallelCommit() {
+				return result.Result{}, errors.AssertionFailedf(
+					"cannot prepare transaction in parallel with its writes")
+			}
+
+			reply.Txn.Status = roachpb.PREPARED
+			if err := updatePreparedTxn(ctx, readWriter, ms, key, args, reply.Txn); err != nil {
+				return result.Result{}, err
+			}
+			return result.Result{}, nil
+		}
+
 		// If the transaction needs to be staged as part of an implicit commit
 		// before being explicitly committed, write the staged transaction
 		// record and return without running commit triggers or resolving local
@@ -555,6 +607,23 @@ func updateStagingTxn(
 	return storage.MVCCPutProto(ctx, readWriter, ms, key, hlc.Timestamp{}, nil /* txn */, &txnRecord)
 }
 
+// updatePreparedTxn persists the PREPARED transaction record with updated
+// status (and possibly timestamp). The record retains the transaction's lock
+// spans so that its locks can be resolved once it is committed or rolled back.
+func updatePreparedTxn(
+	ctx context.Context,
+	readWriter storage.ReadWriter,
+	ms *enginepb.MVCCStats,
+	key []byte,
+	args *roachpb.EndTxnRequest,
+	txn *roachpb.Transaction,
+) error {
+	txn.LockSpans = args.LockSpans
+	txn.InFlightWrites = nil
+	txnRecord := txn.AsRecord()
+	return storage.MVCCPutProto(ctx, readWriter, ms, key, hlc.Timestamp{}, nil /* txn */, &txnRecord)
+}
+
 // updateFinalizedTxn persists the COMMITTED or ABORTED transaction record with
 // updated status (and possibly timestamp). If we've already resolved all locks
 // locally, we actually delete the record right away - no use in keeping it
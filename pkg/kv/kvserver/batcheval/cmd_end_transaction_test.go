@@ -63,6 +63,8 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 	restartedAndPushedHeaderTxn.WriteTimestamp.Forward(ts3)
 	committedHeaderTxn := txn.Clone()
 	committedHeaderTxn.Status = roachpb.COMMITTED
+	preparedHeaderTxn := txn.Clone()
+	preparedHeaderTxn.Status = roachpb.PREPARED
 
 	pendingRecord := func() *roachpb.TransactionRecord {
 		record := txn.AsRecord()
@@ -82,6 +84,19 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 		record.LockSpans = intents
 		return &record
 	}()
+	preparedRecord := func() *roachpb.TransactionRecord {
+		record := txn.AsRecord()
+		record.Status = roachpb.PREPARED
+		record.LockSpans = intents
+		return &record
+	}()
+	pushedPreparedRecord := func() *roachpb.TransactionRecord {
+		record := txn.AsRecord()
+		record.Status = roachpb.PREPARED
+		record.LockSpans = intents
+		record.WriteTimestamp.Forward(ts2)
+		return &record
+	}()
 	abortedRecord := func() *roachpb.TransactionRecord {
 		record := txn.AsRecord()
 		record.Status = roachpb.ABORTED
@@ -97,6 +112,7 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 		// Request state.
 		headerTxn      *roachpb.Transaction
 		commit         bool
+		prepare        bool
 		noLockSpans    bool
 		inFlightWrites []roachpb.SequencedWrite
 		deadline       *hlc.Timestamp
@@ -892,6 +908,99 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 			// Expected result.
 			expError: "TransactionAbortedError(ABORT_REASON_ABORTED_RECORD_FOUND)",
 		},
+		{
+			// Standard case where a transaction is prepared.
+			name: "record pending, try prepare",
+			// Replica state.
+			existingTxn: pendingRecord,
+			// Request state.
+			headerTxn: headerTxn,
+			commit:    true,
+			prepare:   true,
+			// Expected result.
+			expTxn: preparedRecord,
+		},
+		{
+			// A pushed transaction can't be prepared, like it can't be
+			// committed.
+			name: "record pending, try prepare at pushed timestamp",
+			// Replica state.
+			existingTxn: pendingRecord,
+			// Request state.
+			headerTxn: pushedHeaderTxn,
+			commit:    true,
+			prepare:   true,
+			// Expected result.
+			expError: "TransactionRetryError: retry txn (RETRY_SERIALIZABLE)",
+		},
+		{
+			name: "record pending, try prepare after deadline",
+			// Replica state.
+			existingTxn: pendingRecord,
+			// Request state.
+			headerTxn: headerTxn,
+			commit:    true,
+			prepare:   true,
+			deadline:  &ts,
+			// Expected result.
+			expError: "TransactionRetryError: retry txn (RETRY_COMMIT_DEADLINE_EXCEEDED",
+		},
+		{
+			// Standard case where a prepared transaction is committed, possibly
+			// by another coordinator.
+			name: "record prepared, try commit",
+			// Replica state.
+			existingTxn: preparedRecord,
+			// Request state.
+			headerTxn: preparedHeaderTxn,
+			commit:    true,
+			// Expected result.
+			expTxn: committedRecord,
+		},
+		{
+			name: "record prepared, try prepare",
+			// Replica state.
+			existingTxn: preparedRecord,
+			// Request state.
+			headerTxn: preparedHeaderTxn,
+			commit:    true,
+			prepare:   true,
+			// Expected result.
+			expError: "TransactionStatusError: already prepared (REASON_UNKNOWN)",
+		},
+		{
+			// The commit of a prepared transaction goes through the same
+			// timestamp checks as any other commit.
+			name: "record prepared at pushed timestamp, try commit",
+			// Replica state.
+			existingTxn: pushedPreparedRecord,
+			// Request state.
+			headerTxn: preparedHeaderTxn,
+			commit:    true,
+			// Expected result.
+			expError: "TransactionRetryError: retry txn (RETRY_SERIALIZABLE)",
+		},
+		{
+			name: "record prepared, try commit after deadline",
+			// Replica state.
+			existingTxn: preparedRecord,
+			// Request state.
+			headerTxn: preparedHeaderTxn,
+			commit:    true,
+			deadline:  &ts,
+			// Expected result.
+			expError: "TransactionRetryError: retry txn (RETRY_COMMIT_DEADLINE_EXCEEDED",
+		},
+		{
+			name: "record prepared, try rollback",
+			// Replica state.
+			existingTxn: preparedRecord,
+			// Request state.
+			headerTxn: preparedHeaderTxn,
+			commit:    false,
+			// Expected result.
+			expTxn: abortedRecord,
+		},
 	}
 	for _, c := range testCases {
 		t.Run(c.name, func(t *testing.T) {
@@ -918,6 +1027,7 @@ func TestEndTxnUpdatesTransactionRecord(t *testing.T) {
 			req := roachpb.EndTxnRequest{
 				RequestHeader: roachpb.RequestHeader{Key: txn.Key},
 				Commit:        c.commit,
+				Prepare:       c.prepare,
 
 				InFlightWrites: c.inFlightWrites,
 				Deadline:       c.deadline,
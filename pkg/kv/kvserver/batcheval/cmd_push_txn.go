@@ -239,6 +239,12 @@ func PushTxn(
 	var reason string
 
 	switch {
+	case reply.PusheeTxn.Status == roachpb.PREPARED:
+		// A prepared transaction can't be pushed, regardless of the pusher's
+		// priority. Its commit timestamp is fixed and only its client can
+		// commit or roll it back, so the pusher must wait for it to do so.
+		reason = "pushee is prepared"
+		pusherWins = false
 	case txnwait.IsExpired(cArgs.EvalCtx.Clock().Now(), &reply.PusheeTxn):
 		reason = "pushee is expired"
 		// When cleaning up, actually clean up (as opposed to simply pushing
@@ -14,6 +14,8 @@ import (
 	"context"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -66,6 +68,15 @@ func DefaultDeclareIsolatedKeys(
 		}
 	}
 	latchSpans.AddMVCC(access, req.Header().Span(), timestamp)
+	if roachpb.IsReadOnly(req) && roachpb.IsLocking(req) &&
+		roachpb.LockingStrength(req) == lock.Shared {
+		// Reads that acquire Shared locks hold write latches, like other locking
+		// reads, so that they are serialized with conflicting lock acquisitions
+		// during evaluation. However, they declare read-only lock spans so that
+		// they do not conflict with Shared locks held by other transactions in
+		// the lock table. See concurrency.Request.ReadLockStrength.
+		access = spanset.SpanReadOnly
+	}
 	lockSpans.AddNonMVCC(access, req.Header().Span())
 }
 
@@ -108,4 +119,8 @@ type CommandArgs struct {
 	// *Stats should be mutated to reflect any writes made by the command.
 	Stats                 *enginepb.MVCCStats
 	LocalUncertaintyLimit hlc.Timestamp
+	// Concurrency is the request's concurrency guard. It is consulted by
+	// requests with the SkipLocked wait policy to determine whether keys are
+	// locked by conflicting transactions. May be nil.
+	Concurrency *concurrency.Guard
 }
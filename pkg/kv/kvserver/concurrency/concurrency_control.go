@@ -412,6 +412,16 @@ type Request struct {
 	// passed to SequenceReq. Only supplied to SequenceReq if the method is
 	// not also passed an exiting Guard.
 	LockSpans *spanset.SpanSet
+
+	// The strength with which the request accesses the keys in its read-only
+	// LockSpans. Non-locking reads access these keys with lock.None strength
+	// and only conflict with Exclusive locks held at or below their timestamp.
+	// Reads that acquire Shared locks (e.g. SELECT ... FOR SHARE) declare
+	// read-only LockSpans and access them with lock.Shared strength, meaning
+	// that they conflict with all Exclusive locks held by other transactions
+	// but not with Shared locks. Keys in read-write LockSpans are always
+	// accessed with lock.Exclusive strength.
+	ReadLockStrength lock.Strength
 }
 
 // Guard is returned from Manager.SequenceReq. The guard is passed back in to
@@ -603,11 +613,12 @@ type lockTable interface {
 	// the lockTable initially. It must only be called in the evaluation phase
 	// before calling Dequeue, which means all the latches needed by the request
 	// are held. The key must be in the request's SpanSet with the appropriate
-	// SpanAccess: currently the strength is always Exclusive, so the span
-	// containing this key must be SpanReadWrite. This contract ensures that the
-	// lock is not held in a conflicting manner by a different transaction.
-	// Acquiring a lock that is already held by this transaction upgrades the
-	// lock's timestamp and strength, if necessary.
+	// SpanAccess: for Exclusive locks, the span containing this key must be
+	// SpanReadWrite. For Shared locks, which are always Unreplicated, the span
+	// may instead be SpanReadOnly if the request's ReadLockStrength is Shared.
+	// This contract ensures that the lock is not held in a conflicting manner
+	// by a different transaction. Acquiring a lock that is already held by this
+	// transaction upgrades the lock's timestamp and strength, if necessary.
 	//
 	// For replicated locks, this must be called after the corresponding write
 	// intent has been applied to the replicated state machine.
@@ -713,6 +724,15 @@ type lockTableGuard interface {
 	// so this checking is practically only going to find unreplicated locks
 	// that conflict.
 	CheckOptimisticNoConflicts(*spanset.SpanSet) (ok bool)
+
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked or
+	// reserved by a conflicting transaction in the lock table snapshot taken by
+	// the guard, given the caller's own desired locking strength. If so, true
+	// is returned. If the key is locked, the lock holder is also returned.
+	// Otherwise, if the key is only reserved, nil is also returned. It is used
+	// by requests with the SkipLocked wait policy to skip over locked keys
+	// during evaluation.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
 }
 
 // lockTableWaiter is concerned with waiting in lock wait-queues for locks held
@@ -295,6 +295,16 @@ func (m *managerImpl) sequenceReqWithGuard(ctx context.Context, g *Guard) (Respo
 			}
 			log.Event(ctx, "optimistically scanning lock table for conflicting locks")
 			g.ltg = m.lt.ScanOptimistic(g.Req)
+		} else if g.Req.WaitPolicy == lock.WaitPolicy_SkipLocked {
+			// Requests that skip locked keys never wait on conflicting locks.
+			// Instead, they take a snapshot of the lock table that is consulted
+			// during evaluation to skip over keys that are locked by conflicting
+			// transactions. See Guard.IsKeyLockedByConflictingTxn.
+			if g.ltg != nil {
+				panic("SkipLocked request should not have a non-nil lockTableGuard")
+			}
+			log.Event(ctx, "scanning lock table for locks to skip")
+			g.ltg = m.lt.ScanOptimistic(g.Req)
 		} else {
 			// Scan for conflicting locks.
 			log.Event(ctx, "scanning lock table for conflicting locks")
@@ -485,7 +495,7 @@ func (m *managerImpl) HandleTransactionPushError(
 
 // OnLockAcquired implements the LockManager interface.
 func (m *managerImpl) OnLockAcquired(ctx context.Context, acq *roachpb.LockAcquisition) {
-	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, lock.Exclusive, acq.Durability); err != nil {
+	if err := m.lt.AcquireLock(&acq.Txn, acq.Key, acq.Strength, acq.Durability); err != nil {
 		log.Fatalf(ctx, "%v", err)
 	}
 }
@@ -678,6 +688,25 @@ func (g *Guard) CheckOptimisticNoConflicts(
 	return false
 }
 
+// IsKeyLockedByConflictingTxn returns whether the specified key is locked or
+// reserved by a conflicting transaction, given the caller's own desired
+// locking strength. If so, true is returned. If the key is locked, the lock
+// holder is also returned. Otherwise, if the key is only reserved, nil is also
+// returned. The method consults the snapshot of the lock table taken when the
+// request was sequenced, so it may miss locks acquired after that point that
+// are not also visible to the request as intents.
+//
+// The method is used by requests with the SkipLocked wait policy to skip over
+// locked keys during evaluation.
+func (g *Guard) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, strength lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	if g.ltg == nil {
+		return false, nil
+	}
+	return g.ltg.IsKeyLockedByConflictingTxn(key, strength)
+}
+
 // CheckOptimisticNoLatchConflicts checks that the declared latch spans for
 // the request do not have a conflicting latch.
 func (g *Guard) CheckOptimisticNoLatchConflicts() (ok bool) {
@@ -64,6 +64,8 @@ import (
 //
 // check-opt-no-conflicts req=<req-name>
 //
+// is-key-locked-by-conflicting-txn req=<req-name> key=<key> strength=<strength>
+//
 // on-lock-acquired  req=<req-name> key=<key> [seq=<seq>] [dur=r|u]
 // on-lock-updated   req=<req-name> txn=<txn-name> key=<key> status=[committed|aborted|pending] [ts=<int>[,<int>]]
 // on-txn-updated    txn=<txn-name> status=[committed|aborted|pending] [ts=<int>[,<int>]]
@@ -172,6 +174,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 				// Each roachpb.Request is provided on an indented line.
 				reqs, reqUnions := scanRequests(t, d, c)
 				latchSpans, lockSpans := c.collectSpans(t, txn, ts, reqs)
+				ba := roachpb.BatchRequest{Requests: reqUnions}
 
 				c.requestsByName[reqName] = concurrency.Request{
 					Txn:       txn,
@@ -184,6 +187,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 					Requests:               reqUnions,
 					LatchSpans:             latchSpans,
 					LockSpans:              lockSpans,
+					ReadLockStrength:       ba.ReadLockStrength(),
 				}
 				return ""
 
@@ -324,6 +328,25 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 				latchSpans, lockSpans := c.collectSpans(t, g.Req.Txn, g.Req.Timestamp, reqs)
 				return fmt.Sprintf("no-conflicts: %t", g.CheckOptimisticNoConflicts(latchSpans, lockSpans))
 
+			case "is-key-locked-by-conflicting-txn":
+				var reqName string
+				d.ScanArgs(t, "req", &reqName)
+				g, ok := c.guardsByReqName[reqName]
+				if !ok {
+					d.Fatalf(t, "unknown request: %s", reqName)
+				}
+				var key string
+				d.ScanArgs(t, "key", &key)
+				strength := scanLockStrength(t, d)
+				if ok, txn := g.IsKeyLockedByConflictingTxn(roachpb.Key(key), strength); ok {
+					holder := "<nil>"
+					if txn != nil {
+						holder = txn.ID.String()
+					}
+					return fmt.Sprintf("locked: true, holder: %s", holder)
+				}
+				return "locked: false"
+
 			case "on-lock-acquired":
 				var reqName string
 				d.ScanArgs(t, "req", &reqName)
@@ -347,8 +370,9 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 					dur = scanLockDurability(t, d)
 				}
 
-				// Confirm that the request has a corresponding write request.
+				// Confirm that the request has a corresponding locking request.
 				found := false
+				var str lock.Strength
 				for _, ru := range guard.Req.Requests {
 					req := ru.GetInner()
 					keySpan := roachpb.Span{Key: roachpb.Key(key)}
@@ -356,6 +380,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 						req.Header().Span().Contains(keySpan) &&
 						req.Header().Sequence == seqNum {
 						found = true
+						str = roachpb.LockingStrength(req)
 						break
 					}
 				}
@@ -368,7 +393,7 @@ func TestConcurrencyManagerBasic(t *testing.T) {
 
 				mon.runSync("acquire lock", func(ctx context.Context) {
 					log.Eventf(ctx, "txn %s @ %s", txn.ID.Short(), key)
-					acq := roachpb.MakeLockAcquisition(txnAcquire, roachpb.Key(key), dur)
+					acq := roachpb.MakeLockAcquisition(txnAcquire, roachpb.Key(key), dur, str)
 					m.OnLockAcquired(ctx, &acq)
 				})
 				return c.waitAndCollect(t, mon)
@@ -69,12 +69,34 @@ func scanWaitPolicy(t *testing.T, d *datadriven.TestData, required bool) lock.Wa
 		return lock.WaitPolicy_Block
 	case "error":
 		return lock.WaitPolicy_Error
+	case "skip-locked":
+		return lock.WaitPolicy_SkipLocked
 	default:
 		d.Fatalf(t, "unknown wait policy: %s", policy)
 		return 0
 	}
 }
 
+func scanLockStrength(t *testing.T, d *datadriven.TestData) lock.Strength {
+	var strS string
+	d.ScanArgs(t, "strength", &strS)
+	return parseLockStrength(t, d, strS)
+}
+
+func parseLockStrength(t *testing.T, d *datadriven.TestData, strS string) lock.Strength {
+	switch strS {
+	case "none":
+		return lock.None
+	case "shared":
+		return lock.Shared
+	case "exclusive":
+		return lock.Exclusive
+	default:
+		d.Fatalf(t, "unknown lock strength: %s", strS)
+		return 0
+	}
+}
+
 func scanSingleRequest(
 	t *testing.T, d *datadriven.TestData, line string, txns map[string]*roachpb.Transaction,
 ) roachpb.Request {
@@ -99,6 +121,13 @@ func scanSingleRequest(
 		}
 		return v
 	}
+	maybeGetStrength := func() lock.Strength {
+		s, ok := fields["strength"]
+		if !ok {
+			return lock.None
+		}
+		return parseLockStrength(t, d, s)
+	}
 	maybeGetSeq := func() enginepb.TxnSeq {
 		s, ok := fields["seq"]
 		if !ok {
@@ -116,6 +145,7 @@ func scanSingleRequest(
 		var r roachpb.GetRequest
 		r.Sequence = maybeGetSeq()
 		r.Key = roachpb.Key(mustGetField("key"))
+		r.KeyLocking = maybeGetStrength()
 		return &r
 
 	case "scan":
@@ -125,6 +155,7 @@ func scanSingleRequest(
 		if v, ok := fields["endkey"]; ok {
 			r.EndKey = roachpb.Key(v)
 		}
+		r.KeyLocking = maybeGetStrength()
 		return &r
 
 	case "put":
@@ -341,6 +341,7 @@ type lockTableGuardImpl struct {
 	txn                *enginepb.TxnMeta
 	ts                 hlc.Timestamp
 	spans              *spanset.SpanSet
+	readStrength       lock.Strength
 	maxWaitQueueLength int
 
 	// Snapshots of the trees for which this request has some spans. Note that
@@ -530,6 +531,31 @@ func (g *lockTableGuardImpl) CheckOptimisticNoConflicts(spanSet *spanset.SpanSet
 	return true
 }
 
+func (g *lockTableGuardImpl) IsKeyLockedByConflictingTxn(
+	key roachpb.Key, str lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	ss := spanset.SpanGlobal
+	if keys.IsLocal(key) {
+		ss = spanset.SpanLocal
+	}
+	tree := g.tableSnapshot[ss]
+	iter := tree.MakeIter()
+	iter.SeekGE(&lockState{key: key})
+	if !iter.Valid() || !iter.Cur().key.Equal(key) {
+		// No lock on key.
+		return false, nil
+	}
+	l := iter.Cur()
+	if str == lock.Exclusive && g.readStrength == lock.Shared {
+		// The caller does not distinguish between locking reads of different
+		// strengths, so use the strength that the request declared for the key.
+		if sa, _, err := findAccessInSpans(key, g.spans); err == nil {
+			str = g.strengthForAccess(sa)
+		}
+	}
+	return l.isLockedByConflictingTxn(g, str)
+}
+
 func (g *lockTableGuardImpl) notify() {
 	select {
 	case g.mu.signal <- struct{}{}:
@@ -559,6 +585,25 @@ func (g *lockTableGuardImpl) isSameTxnAsReservation(ws waitingState) bool {
 	return !ws.held && g.isSameTxn(ws.txn)
 }
 
+// strengthForAccess returns the lock strength with which the request accesses
+// the keys in its spans with the specified access.
+func (g *lockTableGuardImpl) strengthForAccess(sa spanset.SpanAccess) lock.Strength {
+	if sa == spanset.SpanReadWrite {
+		return lock.Exclusive
+	}
+	return g.readStrength
+}
+
+// strengthAtKey returns the strongest lock strength with which the request
+// accesses the specified key. The key must be in the request's spans.
+func (g *lockTableGuardImpl) strengthAtKey(key roachpb.Key) lock.Strength {
+	sa, _, err := findAccessInSpans(key, g.spans)
+	if err != nil {
+		panic(err)
+	}
+	return g.strengthForAccess(sa)
+}
+
 // Finds the next lock, after the current one, to actively wait at. If it
 // finds the next lock the request starts actively waiting there, else it is
 // told that it is done waiting. lockTableImpl.finalizedTxnCache is used to
@@ -722,10 +767,15 @@ type lockState struct {
 
 	// Invariant summary (see detailed comments below):
 	// - both holder.locked and waitQ.reservation != nil cannot be true.
+	// - both holder.locked and len(sharedHolders) > 0 cannot be true.
+	// - both len(sharedHolders) > 0 and waitQ.reservation != nil cannot be
+	//   true.
 	// - if holder.locked and multiple holderInfos have txn != nil: all the
 	//   txns must have the same txn.ID.
+	// - all the txns in sharedHolders have distinct txn.IDs.
 	// - !holder.locked => waitingReaders.Len() == 0. That is, readers wait
-	//   only if the lock is held. They do not wait for a reservation.
+	//   only if the lock is held exclusively. They do not wait for a shared
+	//   lock or a reservation.
 	// - If reservation != nil, that request is not in queuedWriters.
 
 	// Information about whether the lock is held and the holder. We track
@@ -738,6 +788,14 @@ type lockState struct {
 		holder [lock.MaxDurability + 1]lockHolderInfo
 	}
 
+	// Information about the transactions that hold the lock with Shared
+	// strength, in the order in which they acquired it. Shared locks are only
+	// acquired by locking reads, so they are always unreplicated. Shared locks
+	// are compatible with each other, but not with an Exclusive lock held by a
+	// different transaction, so a transaction that holds a Shared lock and
+	// then acquires an Exclusive lock on the same key is moved into holder.
+	sharedHolders []lockHolderInfo
+
 	// Information about the requests waiting on the lock.
 	lockWaitQueue
 
@@ -756,10 +814,10 @@ type lockWaitQueue struct {
 	// A not-held lock can be "reserved". A reservation is just a claim that
 	// prevents multiple requests from racing when the lock is released. A
 	// reservation by req2 can be broken by req1 is req1 has a smaller seqNum
-	// than req2. Only requests that specify SpanReadWrite for a key can make
-	// reservations. This means a reservation can only be made when the lock is
-	// not held, since the reservation (which can acquire an Exclusive lock) and
-	// the lock holder (which is an Exclusive lock) conflict.
+	// than req2. Only requests that specify SpanReadWrite for a key, or that
+	// acquire Shared locks, can make reservations. A reservation can only be
+	// made when the lock is not held, since the reservation is treated as if it
+	// could acquire an Exclusive lock.
 	//
 	// Read reservations are not permitted due to the complexities discussed in
 	// the review for #43740. Additionally, reads do not queue for their turn at
@@ -780,8 +838,7 @@ type lockWaitQueue struct {
 	// seqnums but at another key req2 wants to read and req1 wants to write and
 	// since req2 does not wait in the queue it acquires a read reservation
 	// before req1. See the discussion at the end of this comment section on how
-	// the behavior will extend when we start supporting Shared and Upgrade
-	// locks.
+	// the behavior extends to Shared locks.
 	//
 	// Non-transactional requests can do both reads and writes but cannot be
 	// depended on since they don't have a transaction that can be pushed.
@@ -843,44 +900,34 @@ type lockWaitQueue struct {
 	//   This is a deadlock caused by the lock table unless req2 partially
 	//   breaks the reservation at A.
 	//
-	// Extension for Shared and Upgrade locks:
+	// Shared locks:
 	// There are 3 aspects to consider: holders; reservers; the dependencies
 	// that need to be captured when waiting.
 	//
 	// - Holders: only shared locks are compatible with themselves, so there can
 	//   be one of (a) no holder (b) multiple shared lock holders, (c) one
-	//   exclusive holder, (d) one upgrade holder. Non-locking reads will
-	//   wait in waitingReaders for only an incompatible exclusive holder.
-	//
-	// - Reservers: This follows the same pattern as holders. Non-locking reads
-	//   do not wait on reservers.
+	//   exclusive holder. Non-locking reads will wait in waitingReaders for
+	//   only an incompatible exclusive holder. They never wait for shared lock
+	//   holders.
 	//
-	// - Queueing and dependencies: All potential lockers and non-transactional
-	//   writers will wait in the same queue. A sequence of consecutive requests
-	//   that have the potential to acquire a shared lock will jointly reserve
-	//   that shared lock. Such requests cannot jump ahead of requests with a
-	//   lower seqnum just because there is currently a shared lock reservation
-	//   (this can cause lockTable induced deadlocks). Such joint reservations
-	//   can be partially broken by a waiter desiring an exclusive or upgrade
-	//   lock. Like the current code, non-transactional writes will wait for
-	//   reservations that have a lower sequence num, but not make their own
-	//   reservation. Additionally, they can partially break joint reservations.
+	// - Reservers: requests that have the potential to acquire a shared lock
+	//   (i.e. locking reads with a ReadLockStrength of Shared) queue in
+	//   queuedWriters and make reservations in the same way that writers do.
+	//   This keeps them ordered with respect to writers when the lock is
+	//   released. When a shared lock is acquired, all queued requests that
+	//   want a shared lock are compatible with the new holder, so they stop
+	//   waiting. Joint reservations are not supported: a sequence of
+	//   consecutive shared lockers in the queue acquires the lock one at a
+	//   time until the first of them acquires it.
 	//
-	//   Reservations that are (partially or fully) broken cause requests to
-	//   reenter the queue as inactive waiters. This is no different than the
-	//   current behavior. Each request can specify the same key in spans for
-	//   ReadOnly, ReadShared, ReadUpgrade, ReadWrite. The spans will be
-	//   iterated over in decreasing order of strength, to only wait at a lock
-	//   at the highest strength (this is similar to the current behavior using
-	//   accessDecreasingStrength).
-	//
-	//   For dependencies, a waiter desiring an exclusive or upgrade lock always
-	//   conflicts with the holder(s) or reserver(s) so that is the dependency
-	//   that will be captured. A waiter desiring a shared lock may encounter a
-	//   situation where it does not conflict with the holder(s) or reserver(s)
-	//   since those are also shared lockers. In that case it will depend on the
-	//   first waiter since that waiter must be desiring a lock that is
-	//   incompatible with a shared lock.
+	// - Queueing and dependencies: a request desiring a shared lock does not
+	//   wait at a lock that is only held with shared strength, even when there
+	//   are queued requests desiring an exclusive lock. This favors concurrency
+	//   among readers over fairness to writers. A request desiring an exclusive
+	//   lock waits for all shared lock holders from other transactions and
+	//   captures a dependency on the first of them. Once the lock is only held
+	//   by a single transaction's shared lock, requests from that transaction
+	//   stop waiting.
 
 	reservation *lockTableGuardImpl
 
@@ -958,6 +1005,26 @@ func (l *lockState) safeFormat(sb *redact.StringBuilder, finalizedTxnCache *txnC
 		sb.Printf("txn: %v, ts: %v, seq: %v\n",
 			redact.Safe(txn.ID), redact.Safe(ts), redact.Safe(txn.Sequence))
 	}
+	writeHolderDetails := func(sb *redact.StringBuilder, h *lockHolderInfo) {
+		if finalizedTxnCache != nil {
+			finalizedTxn, ok := finalizedTxnCache.get(h.txn.ID)
+			if ok {
+				var statusStr string
+				switch finalizedTxn.Status {
+				case roachpb.COMMITTED:
+					statusStr = "committed"
+				case roachpb.ABORTED:
+					statusStr = "aborted"
+				}
+				sb.Printf("[holder finalized: %s] ", redact.Safe(statusStr))
+			}
+		}
+		sb.Printf("epoch: %d, seqs: [%d", redact.Safe(h.txn.Epoch), redact.Safe(h.seqs[0]))
+		for j := 1; j < len(h.seqs); j++ {
+			sb.Printf(", %d", redact.Safe(h.seqs[j]))
+		}
+		sb.SafeString("]")
+	}
 	writeHolderInfo := func(sb *redact.StringBuilder, txn *enginepb.TxnMeta, ts hlc.Timestamp) {
 		sb.Printf("  holder: txn: %v, ts: %v, info: ", redact.Safe(txn.ID), redact.Safe(ts))
 		first := true
@@ -975,29 +1042,21 @@ func (l *lockState) safeFormat(sb *redact.StringBuilder, finalizedTxnCache *txnC
 			} else {
 				sb.SafeString("unrepl ")
 			}
-			if finalizedTxnCache != nil {
-				finalizedTxn, ok := finalizedTxnCache.get(h.txn.ID)
-				if ok {
-					var statusStr string
-					switch finalizedTxn.Status {
-					case roachpb.COMMITTED:
-						statusStr = "committed"
-					case roachpb.ABORTED:
-						statusStr = "aborted"
-					}
-					sb.Printf("[holder finalized: %s] ", redact.Safe(statusStr))
-				}
-			}
-			sb.Printf("epoch: %d, seqs: [%d", redact.Safe(h.txn.Epoch), redact.Safe(h.seqs[0]))
-			for j := 1; j < len(h.seqs); j++ {
-				sb.Printf(", %d", redact.Safe(h.seqs[j]))
-			}
-			sb.SafeString("]")
+			writeHolderDetails(sb, h)
 		}
 		sb.SafeString("\n")
 	}
-	txn, ts := l.getLockHolder()
-	if txn == nil {
+	writeSharedHolderInfo := func(sb *redact.StringBuilder, h *lockHolderInfo) {
+		sb.Printf("  shared holder: txn: %v, ts: %v, info: unrepl ",
+			redact.Safe(h.txn.ID), redact.Safe(h.ts))
+		writeHolderDetails(sb, h)
+		sb.SafeString("\n")
+	}
+	if len(l.sharedHolders) > 0 {
+		for i := range l.sharedHolders {
+			writeSharedHolderInfo(sb, &l.sharedHolders[i])
+		}
+	} else if txn, ts := l.getLockHolder(); txn == nil {
 		sb.Printf("  res: req: %d, ", l.reservation.seqNum)
 		writeResInfo(sb, l.reservation.txn, l.reservation.ts)
 	} else {
@@ -1045,7 +1104,7 @@ func (l *lockState) addToMetrics(m *LockTableMetrics) {
 	}
 	lm := LockMetrics{
 		Key:            l.key,
-		Held:           l.holder.locked,
+		Held:           l.isHeld(),
 		WaitingReaders: int64(l.waitingReaders.Len()),
 		WaitingWriters: int64(l.queuedWriters.Len()),
 	}
@@ -1084,6 +1143,10 @@ func (l *lockState) informActiveWaiters() {
 	if lockHolderTxn, _ := l.getLockHolder(); lockHolderTxn != nil {
 		waitForState.txn = lockHolderTxn
 		waitForState.held = true
+	} else if len(l.sharedHolders) > 0 {
+		// Refined for each waiter below.
+		waitForState.txn = l.sharedHolders[0].txn
+		waitForState.held = true
 	} else {
 		waitForState.txn = l.reservation.txn
 		if !findDistinguished && l.distinguishedWaiter.isSameTxnAsReservation(waitForState) {
@@ -1118,6 +1181,12 @@ func (l *lockState) informActiveWaiters() {
 		}
 		g := qg.guard
 		state := waitForState
+		if len(l.sharedHolders) > 0 {
+			// Wait for a shared lock holder from a different transaction.
+			if txn := l.conflictingSharedHolder(g.txn); txn != nil {
+				state.txn = txn
+			}
+		}
 		if g.isSameTxnAsReservation(state) {
 			state.kind = waitSelf
 		} else {
@@ -1141,12 +1210,31 @@ func (l *lockState) informActiveWaiters() {
 // part of the specified transaction.
 // REQUIRES: l.mu is locked.
 func (l *lockState) releaseWritersFromTxn(txn *enginepb.TxnMeta) {
+	l.releaseWriters(func(g *lockTableGuardImpl) bool {
+		return g.isSameTxn(txn)
+	})
+}
+
+// releaseSharedLockers removes all waiting writers for the lockState that want
+// to acquire a Shared lock on its key, since they are compatible with a lock
+// that is held with Shared strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) releaseSharedLockers() {
+	l.releaseWriters(func(g *lockTableGuardImpl) bool {
+		return g.strengthAtKey(l.key) == lock.Shared
+	})
+}
+
+// releaseWriters removes all waiting writers for the lockState for which the
+// provided function returns true.
+// REQUIRES: l.mu is locked.
+func (l *lockState) releaseWriters(release func(*lockTableGuardImpl) bool) {
 	for e := l.queuedWriters.Front(); e != nil; {
 		qg := e.Value.(*queuedGuard)
 		curr := e
 		e = e.Next()
 		g := qg.guard
-		if g.isSameTxn(txn) {
+		if release(g) {
 			if qg.active {
 				if g == l.distinguishedWaiter {
 					l.distinguishedWaiter = nil
@@ -1193,7 +1281,7 @@ func (l *lockState) tryMakeNewDistinguished() {
 // reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) isEmptyLock() bool {
-	if !l.holder.locked && l.reservation == nil {
+	if !l.isHeld() && l.reservation == nil {
 		for i := range l.holder.holder {
 			if !l.holder.holder[i].isEmpty() {
 				panic("lockState with !locked but non-zero lockHolderInfo")
@@ -1207,6 +1295,95 @@ func (l *lockState) isEmptyLock() bool {
 	return false
 }
 
+// Returns true iff the lock is currently held, with either Exclusive or Shared
+// strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) isHeld() bool {
+	return l.holder.locked || len(l.sharedHolders) > 0
+}
+
+// Returns the first transaction holding the lock with Shared strength that is
+// not the specified transaction, or nil if there is no such transaction. A
+// nil txn, which corresponds to a non-transactional request, conflicts with
+// all shared lock holders.
+// REQUIRES: l.mu is locked.
+func (l *lockState) conflictingSharedHolder(txn *enginepb.TxnMeta) *enginepb.TxnMeta {
+	for i := range l.sharedHolders {
+		h := &l.sharedHolders[i]
+		if txn == nil || h.txn.ID != txn.ID {
+			return h.txn
+		}
+	}
+	return nil
+}
+
+// Returns the index of the specified transaction in sharedHolders, or -1 if it
+// does not hold the lock with Shared strength.
+// REQUIRES: l.mu is locked.
+func (l *lockState) findSharedHolder(id uuid.UUID) int {
+	for i := range l.sharedHolders {
+		if l.sharedHolders[i].txn.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Removes the shared lock holder at the specified index.
+// REQUIRES: l.mu is locked.
+func (l *lockState) removeSharedHolder(i int) {
+	copy(l.sharedHolders[i:], l.sharedHolders[i+1:])
+	l.sharedHolders[len(l.sharedHolders)-1] = lockHolderInfo{}
+	l.sharedHolders = l.sharedHolders[:len(l.sharedHolders)-1]
+}
+
+// Called after one or more shared lock holders were removed from the lock.
+// Returns whether the lockState can be garbage collected.
+// REQUIRES: l.mu is locked.
+func (l *lockState) sharedHoldersRemoved() (gc bool) {
+	if len(l.sharedHolders) == 0 {
+		return l.lockIsFree()
+	}
+	if len(l.sharedHolders) == 1 {
+		// If there are waiting requests from the remaining holder's txn, they
+		// no longer need to wait.
+		l.releaseWritersFromTxn(l.sharedHolders[0].txn)
+	}
+	// Active waiters may need to be told about who they are waiting for.
+	l.informActiveWaiters()
+	return false
+}
+
+// Transitions a lock that is held with Shared strength by the transaction with
+// the given id to be held with Exclusive strength by the same transaction, in
+// preparation for the transaction acquiring an Exclusive lock on the key. The
+// transaction's shared lock is retained as an unreplicated exclusive lock.
+// Returns an error if the lock is held with Shared strength by a different
+// transaction.
+// REQUIRES: l.mu is locked.
+func (l *lockState) promoteSharedLock(id uuid.UUID) error {
+	if len(l.sharedHolders) == 0 {
+		return nil
+	}
+	if len(l.sharedHolders) > 1 || l.sharedHolders[0].txn.ID != id {
+		return errors.AssertionFailedf(
+			"existing shared lock cannot be promoted while held by a different transaction")
+	}
+	l.holder.locked = true
+	l.holder.holder[lock.Unreplicated] = l.sharedHolders[0]
+	l.sharedHolders[0] = lockHolderInfo{}
+	l.sharedHolders = l.sharedHolders[:0]
+	// Requests that want to acquire a shared lock now conflict with the lock
+	// holder, but they are not in the queue since they did not conflict with
+	// the shared lock. They will discover the exclusive lock the next time they
+	// scan. Active waiters need to be told that they are waiting for the
+	// exclusive lock holder, and waiters from the same txn no longer need to
+	// wait.
+	l.releaseWritersFromTxn(l.holder.holder[lock.Unreplicated].txn)
+	l.informActiveWaiters()
+	return nil
+}
+
 // Returns true iff the lock is currently held by the transaction with the
 // given id.
 // REQUIRES: l.mu is locked.
@@ -1248,13 +1425,17 @@ func (l *lockState) getLockHolder() (*enginepb.TxnMeta, hlc.Timestamp) {
 	return l.holder.holder[index].txn, l.holder.holder[index].ts
 }
 
-// Removes the current lock holder from the lock.
+// Removes the current lock holder(s) from the lock.
 // REQUIRES: l.mu is locked.
 func (l *lockState) clearLockHolder() {
 	l.holder.locked = false
 	for i := range l.holder.holder {
 		l.holder.holder[i] = lockHolderInfo{}
 	}
+	for i := range l.sharedHolders {
+		l.sharedHolders[i] = lockHolderInfo{}
+	}
+	l.sharedHolders = l.sharedHolders[:0]
 }
 
 // Decides whether the request g with access sa should actively wait at this
@@ -1326,6 +1507,14 @@ func (l *lockState) tryActiveWait(
 		return false, false
 	}
 
+	str := g.strengthForAccess(sa)
+	if str == lock.Shared {
+		// Requests that want to acquire a Shared lock queue and push in the same
+		// way as writers. They only differ from writers in that they are
+		// compatible with other Shared locks, which is handled below.
+		sa = spanset.SpanReadWrite
+	}
+
 	// Lock is not empty.
 	lockHolderTxn, lockHolderTS := l.getLockHolder()
 	if lockHolderTxn != nil && g.isSameTxn(lockHolderTxn) {
@@ -1353,6 +1542,40 @@ func (l *lockState) tryActiveWait(
 		}
 	}
 
+	if len(l.sharedHolders) > 0 {
+		// Shared locks are only held unreplicated, so the locks of finalized
+		// transactions can be released immediately.
+		removed := false
+		for i := 0; i < len(l.sharedHolders); {
+			if _, ok := g.lt.finalizedTxnCache.get(l.sharedHolders[i].txn.ID); ok {
+				l.removeSharedHolder(i)
+				removed = true
+			} else {
+				i++
+			}
+		}
+		if removed && l.sharedHoldersRemoved() {
+			// Empty lock.
+			return false, true
+		}
+		// If all shared lock holders were removed, there is now a reservation
+		// holder, which may be the caller itself, so fall through to the
+		// processing below.
+	}
+	if len(l.sharedHolders) > 0 {
+		// Shared locks are compatible with non-locking reads and with requests
+		// that want to acquire a Shared lock, including those that are queued
+		// behind requests that want to acquire an Exclusive lock.
+		if str != lock.Exclusive {
+			return false, false
+		}
+		lockHolderTxn = l.conflictingSharedHolder(g.txn)
+		if lockHolderTxn == nil {
+			// Only locked by this txn.
+			return false, false
+		}
+	}
+
 	if sa == spanset.SpanReadOnly {
 		if lockHolderTxn == nil {
 			// Reads only care about locker, not a reservation.
@@ -1539,6 +1762,12 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 	if l.isEmptyLock() {
 		return true
 	}
+	str := g.strengthForAccess(sa)
+	if len(l.sharedHolders) > 0 {
+		// Shared locks only conflict with requests that want to acquire an
+		// Exclusive lock.
+		return str != lock.Exclusive || l.conflictingSharedHolder(g.txn) == nil
+	}
 	// Lock is not empty.
 	lockHolderTxn, lockHolderTS := l.getLockHolder()
 	if lockHolderTxn == nil {
@@ -1563,22 +1792,77 @@ func (l *lockState) isNonConflictingLock(g *lockTableGuardImpl, sa spanset.SpanA
 	// path. A conflict with a finalized txn will be noticed when retrying
 	// pessimistically.
 
-	if sa == spanset.SpanReadOnly && g.ts.Less(lockHolderTS) {
+	if str == lock.None && g.ts.Less(lockHolderTS) {
 		return true
 	}
 	// Conflicts.
 	return false
 }
 
+// isLockedByConflictingTxn returns whether the lock is held or reserved by a
+// transaction that conflicts with the request g, given the strength with which
+// the request intends to access the key. Non-locking reads only conflict with
+// locks held at or below their read timestamp and ignore reservations. Shared
+// locks only conflict with requests that intend to acquire an Exclusive lock.
+// If the lock is held by a conflicting transaction, the lock holder is also
+// returned.
+// Acquires l.mu.
+func (l *lockState) isLockedByConflictingTxn(
+	g *lockTableGuardImpl, str lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// It is possible that this lock is empty and has not yet been deleted.
+	if l.isEmptyLock() {
+		return false, nil
+	}
+	if len(l.sharedHolders) > 0 {
+		if str != lock.Exclusive {
+			return false, nil
+		}
+		if txn := l.conflictingSharedHolder(g.txn); txn != nil {
+			return true, txn
+		}
+		return false, nil
+	}
+	lockHolderTxn, lockHolderTS := l.getLockHolder()
+	if lockHolderTxn != nil {
+		if g.isSameTxn(lockHolderTxn) {
+			// Already locked by this txn.
+			return false, nil
+		}
+		if str == lock.None && g.ts.Less(lockHolderTS) {
+			// Non-locking reads below the lock's timestamp do not conflict.
+			return false, nil
+		}
+		return true, lockHolderTxn
+	}
+	if str == lock.None {
+		// Non-locking reads only care about the lock holder, not a reservation.
+		return false, nil
+	}
+	if l.reservation != nil && !g.isSameTxn(l.reservation.txn) {
+		return true, nil
+	}
+	return false, nil
+}
+
 // Acquires this lock. Returns the list of guards that are done actively
 // waiting at this key -- these will be requests from the same transaction
 // that is acquiring the lock.
 // Acquires l.mu.
 func (l *lockState) acquireLock(
-	_ lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
+	str lock.Strength, durability lock.Durability, txn *enginepb.TxnMeta, ts hlc.Timestamp,
 ) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	if str == lock.Shared {
+		return l.acquireSharedLock(txn, ts)
+	}
+	if err := l.promoteSharedLock(txn.ID); err != nil {
+		return err
+	}
 	if l.holder.locked {
 		// Already held.
 		beforeTxn, beforeTs := l.getLockHolder()
@@ -1701,6 +1985,69 @@ func (l *lockState) acquireLock(
 	return nil
 }
 
+// Acquires this lock with Shared strength. Shared locks are always
+// unreplicated.
+// REQUIRES: l.mu is locked.
+func (l *lockState) acquireSharedLock(txn *enginepb.TxnMeta, ts hlc.Timestamp) error {
+	if l.holder.locked {
+		if l.isLockedBy(txn.ID) {
+			// The transaction already holds an Exclusive lock, which is stronger.
+			return nil
+		}
+		return errors.AssertionFailedf("existing lock cannot be acquired by different transaction")
+	}
+	if idx := l.findSharedHolder(txn.ID); idx >= 0 {
+		// Already held with Shared strength by this transaction.
+		h := &l.sharedHolders[idx]
+		if h.txn.Epoch < txn.Epoch {
+			// Clear the sequences for the older epoch.
+			h.seqs = h.seqs[:0]
+		}
+		if i := sort.Search(len(h.seqs), func(i int) bool {
+			return h.seqs[i] >= txn.Sequence
+		}); i == len(h.seqs) || h.seqs[i] != txn.Sequence {
+			h.seqs = append(h.seqs, 0)
+			copy(h.seqs[i+1:], h.seqs[i:])
+			h.seqs[i] = txn.Sequence
+		}
+		h.txn = txn
+		// See the comment in acquireLock about forwarding the timestamp.
+		h.ts.Forward(ts)
+		return nil
+	}
+	// Not already held by this transaction, so may be reserved by this request.
+	// See the comment in acquireLock about broken reservations.
+	if l.reservation != nil {
+		if l.reservation.txn.ID != txn.ID {
+			// Reservation is broken.
+			qg := &queuedGuard{
+				guard:  l.reservation,
+				active: false,
+			}
+			l.queuedWriters.PushFront(qg)
+		} else {
+			l.reservation.mu.Lock()
+			delete(l.reservation.mu.locks, l)
+			l.reservation.mu.Unlock()
+		}
+		l.reservation = nil
+	}
+	l.sharedHolders = append(l.sharedHolders, lockHolderInfo{
+		txn:  txn,
+		ts:   ts,
+		seqs: []enginepb.TxnSeq{txn.Sequence},
+	})
+
+	// If there are waiting requests from the same txn, or waiting requests that
+	// want to acquire a Shared lock, they no longer need to wait.
+	l.releaseWritersFromTxn(txn)
+	l.releaseSharedLockers()
+
+	// Inform active waiters since lock has transitioned to held.
+	l.informActiveWaiters()
+	return nil
+}
+
 // A replicated lock held by txn with timestamp ts was discovered by guard g
 // where g is trying to access this key with access sa.
 // Acquires l.mu.
@@ -1717,6 +2064,15 @@ func (l *lockState) discoveredLock(
 	if notRemovable {
 		l.notRemovable++
 	}
+	if sa == spanset.SpanReadOnly && g.readStrength == lock.Shared {
+		// Requests that want to acquire a Shared lock queue like writers. See
+		// tryActiveWait.
+		sa = spanset.SpanReadWrite
+	}
+	if err := l.promoteSharedLock(txn.ID); err != nil {
+		return errors.AssertionFailedf(
+			"discovered lock by different transaction (%s) than existing shared lock: %s", txn, l)
+	}
 	if l.holder.locked {
 		if !l.isLockedBy(txn.ID) {
 			return errors.AssertionFailedf(
@@ -1911,6 +2267,9 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
 		// tryActiveWait due to the txn being in the finalizedTxnCache.
 		return false, true
 	}
+	if len(l.sharedHolders) > 0 {
+		return l.tryUpdateSharedLock(up)
+	}
 	if !l.isLockedBy(up.Txn.ID) {
 		return false, false
 	}
@@ -1987,6 +2346,39 @@ func (l *lockState) tryUpdateLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
 	return true, false
 }
 
+// Tries to update the lock when it is held with Shared strength: noop if this
+// lock is not held by the transaction, else the transaction's shared lock is
+// updated or released. Returns whether the lockState can be garbage collected,
+// and whether it was held by the txn.
+// REQUIRES: l.mu is locked.
+func (l *lockState) tryUpdateSharedLock(up *roachpb.LockUpdate) (heldByTxn, gc bool) {
+	i := l.findSharedHolder(up.Txn.ID)
+	if i < 0 {
+		return false, false
+	}
+	h := &l.sharedHolders[i]
+	txn := &up.Txn
+	release := up.Status.IsFinalized() || txn.Epoch > h.txn.Epoch
+	if !release && txn.Epoch == h.txn.Epoch {
+		h.seqs = removeIgnored(h.seqs, up.IgnoredSeqNums)
+		release = len(h.seqs) == 0
+	}
+	if release {
+		l.removeSharedHolder(i)
+		return true, l.sharedHoldersRemoved()
+	}
+	// Shared locks do not conflict with non-locking reads, so no waiter cares
+	// about the lock's timestamp. It is tracked for consistency with exclusive
+	// locks, using the same rules as tryUpdateLock.
+	if h.ts.Less(txn.WriteTimestamp) {
+		h.ts = txn.WriteTimestamp
+		if txn.Epoch == h.txn.Epoch {
+			h.txn = txn
+		}
+	}
+	return true, false
+}
+
 // The lock holder timestamp has increased. Some of the waiters may no longer
 // need to wait.
 // REQUIRES: l.mu is locked.
@@ -2117,7 +2509,7 @@ func (l *lockState) tryFreeLockOnReplicatedAcquire() bool {
 // waiters, but there cannot be a reservation.
 // REQUIRES: l.mu is locked.
 func (l *lockState) lockIsFree() (gc bool) {
-	if l.holder.locked {
+	if l.isHeld() {
 		panic("called lockIsFree on lock with holder")
 	}
 	if l.reservation != nil {
@@ -2237,6 +2629,7 @@ func (t *lockTableImpl) newGuardForReq(req Request) *lockTableGuardImpl {
 	g.txn = req.txnMeta()
 	g.ts = req.Timestamp
 	g.spans = req.LockSpans
+	g.readStrength = req.ReadLockStrength
 	g.maxWaitQueueLength = req.MaxLockWaitQueueLength
 	g.sa = spanset.NumSpanAccess - 1
 	g.index = -1
@@ -2398,8 +2791,14 @@ func (t *lockTableImpl) AcquireLock(
 		// If not enabled, don't track any locks.
 		return nil
 	}
-	if strength != lock.Exclusive {
-		return errors.AssertionFailedf("lock strength not Exclusive")
+	switch strength {
+	case lock.Exclusive:
+	case lock.Shared:
+		if durability != lock.Unreplicated {
+			return errors.AssertionFailedf("Shared lock not Unreplicated")
+		}
+	default:
+		return errors.AssertionFailedf("lock strength not Exclusive or Shared")
 	}
 	ss := spanset.SpanGlobal
 	if keys.IsLocal(key) {
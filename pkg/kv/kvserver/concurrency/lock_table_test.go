@@ -55,10 +55,11 @@ new-txn txn=<name> ts=<int>[,<int>] epoch=<int> [seq=<int>]
 
  Creates a TxnMeta.
 
-new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [max-lock-wait-queue-length=<int>]
+new-request r=<name> txn=<name>|none ts=<int>[,<int>] spans=r|w@<start>[,<end>]+... [max-lock-wait-queue-length=<int>] [read-strength=none|shared]
 ----
 
- Creates a Request.
+ Creates a Request. The read-strength is the strength with which the request
+ accesses the keys in its read spans.
 
 scan r=<name>
 ----
@@ -74,11 +75,12 @@ start-waiting: <bool>
  Calls lockTable.ScanOptimistic. The request must not have an existing guard.
  If a guard is returned, stores it for later use.
 
-acquire r=<name> k=<key> durability=r|u
+acquire r=<name> k=<key> durability=r|u [strength=exclusive|shared]
 ----
 <error string>
 
- Acquires lock for the request, using the existing guard for that request.
+ Acquires lock for the request, using the existing guard for that request. The
+ lock is acquired with Exclusive strength, unless otherwise specified.
 
 release txn=<name> span=<start>[,<end>]
 ----
@@ -254,12 +256,17 @@ func TestLockTableBasic(t *testing.T) {
 				if d.HasArg("max-lock-wait-queue-length") {
 					d.ScanArgs(t, "max-lock-wait-queue-length", &maxLockWaitQueueLength)
 				}
+				readStrength := lock.None
+				if d.HasArg("read-strength") {
+					readStrength = scanLockStrength(t, d, "read-strength")
+				}
 				spans := scanSpans(t, d, ts)
 				req := Request{
 					Timestamp:              ts,
 					MaxLockWaitQueueLength: maxLockWaitQueueLength,
 					LatchSpans:             spans,
 					LockSpans:              spans,
+					ReadLockStrength:       readStrength,
 				}
 				if txnMeta != nil {
 					// Update the transaction's timestamp, if necessary. The transaction
@@ -318,7 +325,11 @@ func TestLockTableBasic(t *testing.T) {
 				if s[0] == 'r' {
 					durability = lock.Replicated
 				}
-				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), lock.Exclusive, durability); err != nil {
+				strength := lock.Exclusive
+				if d.HasArg("strength") {
+					strength = scanLockStrength(t, d, "strength")
+				}
+				if err := lt.AcquireLock(&req.Txn.TxnMeta, roachpb.Key(key), strength, durability); err != nil {
 					return err.Error()
 				}
 				return lt.String()
@@ -574,6 +585,22 @@ func getSpan(t *testing.T, d *datadriven.TestData, str string) roachpb.Span {
 	return span
 }
 
+func scanLockStrength(t *testing.T, d *datadriven.TestData, key string) lock.Strength {
+	var s string
+	d.ScanArgs(t, key, &s)
+	switch s {
+	case "none":
+		return lock.None
+	case "shared":
+		return lock.Shared
+	case "exclusive":
+		return lock.Exclusive
+	default:
+		d.Fatalf(t, "incorrect lock strength: %s", s)
+		return 0
+	}
+}
+
 func scanSpans(t *testing.T, d *datadriven.TestData, ts hlc.Timestamp) *spanset.SpanSet {
 	spans := &spanset.SpanSet{}
 	var spansStr string
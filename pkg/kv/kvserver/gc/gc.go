@@ -634,6 +634,10 @@ func processLocalKeyRange(
 			info.TransactionSpanGCAborted++
 		case roachpb.COMMITTED:
 			info.TransactionSpanGCCommitted++
+		case roachpb.PREPARED:
+			// Prepared transactions must be retained until their client commits
+			// or rolls them back, however long that takes.
+			return nil
 		default:
 			panic(fmt.Sprintf("invalid transaction state: %s", txn))
 		}
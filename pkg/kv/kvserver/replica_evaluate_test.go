@@ -664,6 +664,7 @@ func TestEvaluateBatch(t *testing.T) {
 				d.MockEvalCtx.EvalContext(),
 				&d.ms,
 				&d.ba,
+				nil, /* g */
 				hlc.Timestamp{},
 				d.readOnly,
 			)
@@ -16,6 +16,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/batcheval"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/kvserverpb"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/spanset"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
@@ -407,15 +408,16 @@ func (r *Replica) executeBatchWithConcurrencyRetries(
 		// returns a request guard that must be eventually released.
 		var resp []roachpb.ResponseUnion
 		g, resp, pErr = r.concMgr.SequenceReq(ctx, g, concurrency.Request{
-			Txn:             ba.Txn,
-			Timestamp:       ba.Timestamp,
-			Priority:        ba.UserPriority,
-			ReadConsistency: ba.ReadConsistency,
-			WaitPolicy:      ba.WaitPolicy,
-			LockTimeout:     ba.LockTimeout,
-			Requests:        ba.Requests,
-			LatchSpans:      latchSpans, // nil if g != nil
-			LockSpans:       lockSpans,  // nil if g != nil
+			Txn:              ba.Txn,
+			Timestamp:        ba.Timestamp,
+			Priority:         ba.UserPriority,
+			ReadConsistency:  ba.ReadConsistency,
+			WaitPolicy:       ba.WaitPolicy,
+			LockTimeout:      ba.LockTimeout,
+			Requests:         ba.Requests,
+			LatchSpans:       latchSpans, // nil if g != nil
+			LockSpans:        lockSpans,  // nil if g != nil
+			ReadLockStrength: ba.ReadLockStrength(),
 		}, requestEvalKind)
 		if pErr != nil {
 			return nil, pErr
@@ -900,8 +902,12 @@ func (r *Replica) collectSpans(
 	}
 
 	// Note that we are letting locking readers be considered for optimistic
-	// evaluation. This is correct, though not necessarily beneficial.
+	// evaluation. This is correct, though not necessarily beneficial. Readers
+	// that skip locked keys are not considered, since they consult a snapshot
+	// of the lock table during evaluation instead of checking for conflicts
+	// after the fact.
 	considerOptEval := ba.IsReadOnly() && ba.IsAllTransactional() && ba.Header.MaxSpanRequestKeys > 0 &&
+		ba.WaitPolicy != lock.WaitPolicy_SkipLocked &&
 		optimisticEvalLimitedScans.Get(&r.ClusterSettings().SV)
 	// When considerOptEval, these are computed below and used to decide whether
 	// to actually do optimistic evaluation.
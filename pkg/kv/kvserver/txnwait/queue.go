@@ -89,8 +89,13 @@ func TxnExpiration(txn *roachpb.Transaction) hlc.Timestamp {
 	return txn.LastActive().Add(TxnLivenessThreshold.Nanoseconds(), 0)
 }
 
-// IsExpired is true if the given transaction is expired.
+// IsExpired is true if the given transaction is expired. Prepared transactions
+// never expire, because they are not heartbeated while they wait for their
+// client to commit or roll them back.
 func IsExpired(now hlc.Timestamp, txn *roachpb.Transaction) bool {
+	if txn.Status == roachpb.PREPARED {
+		return false
+	}
 	return TxnExpiration(txn).Less(now)
 }
 
@@ -632,6 +637,12 @@ func (q *Queue) MaybeWaitForPush(
 				log.VEventf(ctx, 1, "pushing expired txn %s", req.PusheeTxn.ID.Short())
 				return nil, nil
 			}
+			if updatedPushee.Status == roachpb.PREPARED {
+				// A prepared txn won't expire, but we still periodically query it
+				// in case we miss the update which finalizes it.
+				pusheeTxnTimer.Reset(TxnLivenessThreshold)
+				continue
+			}
 			// Set the timer to check for the pushee txn's expiration.
 			expiration := TxnExpiration(updatedPushee).GoTime()
 			now := q.cfg.Clock.Now().GoTime()
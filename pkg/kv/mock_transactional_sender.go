@@ -87,6 +87,16 @@ func (m *MockTransactionalSender) SetDebugName(name string) {
 	m.txn.Name = name
 }
 
+// SetIsolationLevel is part of the TxnSender interface.
+func (m *MockTransactionalSender) SetIsolationLevel(IsolationLevel) error {
+	panic("unimplemented")
+}
+
+// IsolationLevel is part of the TxnSender interface.
+func (m *MockTransactionalSender) IsolationLevel() IsolationLevel {
+	return Serializable
+}
+
 // String is part of the TxnSender interface.
 func (m *MockTransactionalSender) String() string {
 	return m.txn.String()
@@ -160,6 +170,9 @@ func (m *MockTransactionalSender) ReleaseSavepoint(context.Context, SavepointTok
 // Epoch is part of the TxnSender interface.
 func (m *MockTransactionalSender) Epoch() enginepb.TxnEpoch { panic("unimplemented") }
 
+// Key is part of the TxnSender interface.
+func (m *MockTransactionalSender) Key() roachpb.Key { return m.txn.Key }
+
 // IsLocking is part of the TxnSender interface.
 func (m *MockTransactionalSender) IsLocking() bool { return false }
 
@@ -207,11 +220,31 @@ func (m *MockTransactionalSender) GetSteppingMode(context.Context) SteppingMode
 	return SteppingDisabled
 }
 
+// GetReadSeqNum is part of the TxnSender interface.
+func (m *MockTransactionalSender) GetReadSeqNum() enginepb.TxnSeq {
+	return 0
+}
+
+// SetReadSeqNum is part of the TxnSender interface.
+func (m *MockTransactionalSender) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	return nil
+}
+
 // ManualRefresh is part of the TxnSender interface.
 func (m *MockTransactionalSender) ManualRefresh(ctx context.Context) error {
 	panic("unimplemented")
 }
 
+// BeginStatement is part of the TxnSender interface.
+func (m *MockTransactionalSender) BeginStatement(ctx context.Context) error {
+	return nil
+}
+
+// EndStatement is part of the TxnSender interface.
+func (m *MockTransactionalSender) EndStatement(ctx context.Context) error {
+	return nil
+}
+
 // DeferCommitWait is part of the TxnSender interface.
 func (m *MockTransactionalSender) DeferCommitWait(ctx context.Context) func(context.Context) error {
 	panic("unimplemented")
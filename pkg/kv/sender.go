@@ -12,6 +12,7 @@ package kv
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -129,6 +130,13 @@ type TxnSender interface {
 	// SetDebugName sets the txn's debug name.
 	SetDebugName(name string)
 
+	// SetIsolationLevel sets the txn's isolation level. It can only be called
+	// before the transaction has performed any operations.
+	SetIsolationLevel(IsolationLevel) error
+
+	// IsolationLevel returns the txn's isolation level.
+	IsolationLevel() IsolationLevel
+
 	// String returns a string representation of the txn.
 	String() string
 
@@ -260,6 +268,10 @@ type TxnSender interface {
 	// Epoch returns the txn's epoch.
 	Epoch() enginepb.TxnEpoch
 
+	// Key returns the txn's anchor key, which determines the location of its
+	// transaction record. It is empty until the txn acquires its first lock.
+	Key() roachpb.Key
+
 	// IsLocking returns whether the transaction has begun acquiring locks.
 	IsLocking() bool
 
@@ -300,6 +312,17 @@ type TxnSender interface {
 	// for use in tests and assertion checks.
 	GetSteppingMode(ctx context.Context) (curMode SteppingMode)
 
+	// GetReadSeqNum returns the read sequence number of the transaction, i.e.
+	// the sequencing point established by the last call to Step().
+	GetReadSeqNum() enginepb.TxnSeq
+
+	// SetReadSeqNum sets the read sequence number of the transaction. It is
+	// used to temporarily rewind reads to a previously established sequencing
+	// point (for example, to let a SQL cursor observe the data as of the time
+	// it was declared). The sequence number must not be greater than the
+	// current write sequence number.
+	SetReadSeqNum(seq enginepb.TxnSeq) error
+
 	// ManualRefresh attempts to refresh a transactions read timestamp up to its
 	// provisional commit timestamp. In the case that the two are already the
 	// same, it is a no-op. The reason one might want to do that is to ensure
@@ -314,6 +337,26 @@ type TxnSender interface {
 	// before the merge transaction completed.
 	ManualRefresh(ctx context.Context) error
 
+	// BeginStatement marks the start of a new statement in a transaction
+	// running under the ReadCommitted isolation level. The transaction's read
+	// snapshot is advanced to the current time and a statement-level savepoint
+	// is established. If a write-write conflict is encountered before the
+	// matching EndStatement() call, the transaction is rolled back to that
+	// savepoint instead of being restarted, and the returned retryable error
+	// (which does not carry a new epoch) indicates that only the statement
+	// needs to be retried.
+	//
+	// The method is a no-op for Serializable transactions.
+	BeginStatement(ctx context.Context) error
+
+	// EndStatement marks the end of the statement started by the last
+	// BeginStatement() call. The reads performed by the statement are
+	// refreshed if necessary and then dropped from the transaction's refresh
+	// footprint, so that they cannot cause the transaction to retry later.
+	//
+	// The method is a no-op for Serializable transactions.
+	EndStatement(ctx context.Context) error
+
 	// DeferCommitWait defers the transaction's commit-wait operation, passing
 	// responsibility of commit-waiting from the TxnSender to the caller of this
 	// method. The method returns a function which the caller must eventually
@@ -339,6 +382,33 @@ const (
 	SteppingEnabled SteppingMode = true
 )
 
+// IsolationLevel is the isolation level of a transaction.
+type IsolationLevel int
+
+const (
+	// Serializable is the default isolation level. A Serializable transaction
+	// reads from a single snapshot and refreshes its reads (or restarts) when
+	// its commit timestamp is pushed.
+	Serializable IsolationLevel = iota
+
+	// ReadCommitted is a weaker isolation level in which each statement reads
+	// from its own snapshot taken when the statement begins. Reads are not
+	// refreshed across statements, and write-write conflicts cause only the
+	// current statement to be retried.
+	ReadCommitted
+)
+
+func (l IsolationLevel) String() string {
+	switch l {
+	case Serializable:
+		return "SERIALIZABLE"
+	case ReadCommitted:
+		return "READ COMMITTED"
+	default:
+		return fmt.Sprintf("IsolationLevel(%d)", int(l))
+	}
+}
+
 // SavepointToken represents a savepoint.
 type SavepointToken interface {
 	// Initial returns true if this savepoint has been created before performing
@@ -73,6 +73,7 @@ type Txn struct {
 		ID           uuid.UUID
 		debugName    string
 		userPriority roachpb.UserPriority
+		isoLevel     IsolationLevel
 
 		// previousIDs holds the set of all previous IDs that the Txn's Proto has
 		// had across transaction aborts. This allows us to determine if a given
@@ -170,6 +171,24 @@ func NewTxnFromProto(
 	return txn
 }
 
+// NewPreparedTxn returns a root transaction for finishing a transaction which
+// was prepared with Prepare, possibly by a different client or gateway. The
+// proto is the prepared transaction's record, as returned by a QueryTxn
+// request. The returned transaction can only be committed or rolled back.
+func NewPreparedTxn(
+	ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID, proto *roachpb.Transaction,
+) (*Txn, error) {
+	if proto.Status != roachpb.PREPARED {
+		return nil, errors.AssertionFailedf("can't resume non-PREPARED transaction: %s", proto)
+	}
+	proto.AssertInitialized(ctx)
+	txn := &Txn{db: db, typ: RootTxn, gatewayNodeID: gatewayNodeID}
+	txn.mu.ID = proto.ID
+	txn.mu.userPriority = roachpb.NormalUserPriority
+	txn.mu.sender = db.factory.RootTransactionalSender(proto, txn.mu.userPriority)
+	return txn, nil
+}
+
 // NewLeafTxn instantiates a new leaf transaction.
 func NewLeafTxn(
 	ctx context.Context, db *DB, gatewayNodeID roachpb.NodeID, tis *roachpb.LeafTxnInputState,
@@ -216,6 +235,15 @@ func (txn *Txn) Epoch() enginepb.TxnEpoch {
 	return txn.mu.sender.Epoch()
 }
 
+// Key returns the anchor key of the transaction, which determines the location
+// of its transaction record. It is empty until the transaction acquires its
+// first lock.
+func (txn *Txn) Key() roachpb.Key {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.Key()
+}
+
 // statusLocked returns the txn proto status field.
 func (txn *Txn) statusLocked() roachpb.TransactionStatus {
 	return txn.mu.sender.TxnStatus()
@@ -279,6 +307,33 @@ func (txn *Txn) TestingSetPriority(priority enginepb.TxnPriority) {
 	txn.mu.Unlock()
 }
 
+// SetIsolationLevel sets the transaction's isolation level. Transactions
+// default to Serializable isolation. The isolation level must be set before
+// any operations are performed on the transaction.
+func (txn *Txn) SetIsolationLevel(isoLevel IsolationLevel) error {
+	if txn.typ != RootTxn {
+		return errors.AssertionFailedf("SetIsolationLevel() called on leaf txn")
+	}
+
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	if txn.mu.isoLevel == isoLevel {
+		return nil
+	}
+	if err := txn.mu.sender.SetIsolationLevel(isoLevel); err != nil {
+		return err
+	}
+	txn.mu.isoLevel = isoLevel
+	return nil
+}
+
+// IsolationLevel returns the transaction's isolation level.
+func (txn *Txn) IsolationLevel() IsolationLevel {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.isoLevel
+}
+
 // UserPriority returns the transaction's user priority.
 func (txn *Txn) UserPriority() roachpb.UserPriority {
 	txn.mu.Lock()
@@ -677,6 +732,37 @@ func (txn *Txn) commit(ctx context.Context) error {
 	return pErr.GoError()
 }
 
+// Prepare sends an EndTxnRequest with Commit=true and Prepare=true, moving the
+// transaction to the PREPARED state. A prepared transaction retains its locks
+// and can no longer be aborted by conflicting transactions, but it is not yet
+// committed. It must later be committed or rolled back through a transaction
+// created with NewPreparedTxn; txn itself cannot be used to send any more
+// commands after this call, including a rollback.
+//
+// Transactions with commit triggers cannot be prepared.
+func (txn *Txn) Prepare(ctx context.Context) error {
+	if txn.typ != RootTxn {
+		return errors.WithContextTags(errors.AssertionFailedf("Prepare() called on leaf txn"), ctx)
+	}
+	if txn.systemConfigTrigger || len(txn.commitTriggers) > 0 {
+		return errors.New("cannot prepare a transaction with commit triggers")
+	}
+
+	var ba roachpb.BatchRequest
+	et := endTxnReq(true /* commit */, txn.deadline(), false /* hasTrigger */).(*roachpb.EndTxnRequest)
+	et.Prepare = true
+	ba.Add(et)
+	_, pErr := txn.Send(ctx, ba)
+	return pErr.GoError()
+}
+
+// IsPrepared returns true iff the transaction has the prepared status.
+func (txn *Txn) IsPrepared() bool {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.statusLocked() == roachpb.PREPARED
+}
+
 // CleanupOnError cleans up the transaction as a result of an error.
 func (txn *Txn) CleanupOnError(ctx context.Context, err error) {
 	if txn.typ != RootTxn {
@@ -1394,6 +1480,13 @@ func (txn *Txn) replaceRootSenderIfTxnAbortedLocked(
 	// prevSteppingMode := txn.mu.sender.GetSteppingMode(ctx)
 	txn.mu.sender = txn.db.factory.RootTransactionalSender(newTxn, txn.mu.userPriority)
 	// txn.mu.sender.ConfigureStepping(ctx, prevSteppingMode)
+	if txn.mu.isoLevel != Serializable {
+		// The isolation level is a property of the SQL transaction, so it
+		// carries over to the new incarnation.
+		if err := txn.mu.sender.SetIsolationLevel(txn.mu.isoLevel); err != nil {
+			log.Fatalf(ctx, "%+v", err)
+		}
+	}
 }
 
 func (txn *Txn) recordPreviousTxnIDLocked(prevTxnID uuid.UUID) {
@@ -1555,6 +1648,38 @@ func (txn *Txn) ConfigureStepping(ctx context.Context, mode SteppingMode) (prevM
 	return txn.mu.sender.ConfigureStepping(ctx, mode)
 }
 
+// GetReadSeqNum gets the read sequence point for the current transaction.
+func (txn *Txn) GetReadSeqNum() enginepb.TxnSeq {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.GetReadSeqNum()
+}
+
+// SetReadSeqNum sets the read sequence point for the current transaction.
+func (txn *Txn) SetReadSeqNum(seq enginepb.TxnSeq) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.SetReadSeqNum(seq)
+}
+
+// BeginStatement notifies the transaction that a new statement is starting.
+// For ReadCommitted transactions, this establishes a new read snapshot for the
+// statement. See TxnSender.BeginStatement for details.
+func (txn *Txn) BeginStatement(ctx context.Context) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.BeginStatement(ctx)
+}
+
+// EndStatement notifies the transaction that the statement started by the
+// last BeginStatement call has finished. See TxnSender.EndStatement for
+// details.
+func (txn *Txn) EndStatement(ctx context.Context) error {
+	txn.mu.Lock()
+	defer txn.mu.Unlock()
+	return txn.mu.sender.EndStatement(ctx)
+}
+
 // CreateSavepoint establishes a savepoint.
 // This method is only valid when called on RootTxns.
 func (txn *Txn) CreateSavepoint(ctx context.Context) (SavepointToken, error) {
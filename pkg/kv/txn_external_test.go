@@ -12,6 +12,7 @@ package kv_test
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"sync/atomic"
 	"testing"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/testutils"
 	"github.com/cockroachdb/cockroach/pkg/testutils/kvclientutils"
@@ -446,3 +448,143 @@ func testTxnNegotiateAndSendDoesNotBlock(t *testing.T, multiRange, strict, route
 	atomic.StoreInt32(&done, 1)
 	require.NoError(t, g.Wait())
 }
+
+// TestPreparedTxn tests that a prepared transaction retains its locks, can't be
+// pushed even once its coordinator stops heartbeating it, and can be committed
+// or rolled back through a different coordinator.
+func TestPreparedTxn(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	// Shorten the liveness threshold so that a transaction which is no longer
+	// heartbeated is quickly considered expired.
+	defer txnwait.TestingOverrideTxnLivenessThreshold(50 * time.Millisecond)()
+
+	s, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	scratch, err := s.ScratchRange()
+	require.NoError(t, err)
+
+	testutils.RunTrueAndFalse(t, "commit", func(t *testing.T, commit bool) {
+		key := append(scratch[:len(scratch):len(scratch)], fmt.Sprint(commit)...)
+		txn := db.NewTxn(ctx, "prepared")
+		require.NoError(t, txn.Put(ctx, key, "val"))
+		require.NoError(t, txn.Prepare(ctx))
+		require.True(t, txn.IsPrepared())
+
+		// The coordinator which prepared the transaction can no longer finish it.
+		require.Error(t, txn.Rollback(ctx))
+
+		// A conflicting high-priority reader can neither push nor abort the
+		// prepared transaction, so it blocks on its lock.
+		readCtx, cancel := context.WithTimeout(ctx, 500*time.Millisecond)
+		defer cancel()
+		err := db.Txn(readCtx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetUserPriority(roachpb.MaxUserPriority); err != nil {
+				return err
+			}
+			_, err := txn.Get(ctx, key)
+			return err
+		})
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error: %v", err)
+
+		// Resume the prepared transaction from its record and finish it.
+		meta := txn.TestingCloneTxn().TxnMeta
+		var b kv.Batch
+		b.AddRawRequest(&roachpb.QueryTxnRequest{
+			RequestHeader: roachpb.RequestHeader{Key: meta.Key},
+			Txn:           meta,
+		})
+		require.NoError(t, db.Run(ctx, &b))
+		record := b.RawResponse().Responses[0].GetQueryTxn().QueriedTxn
+		require.Equal(t, roachpb.PREPARED, record.Status)
+
+		resumed, err := kv.NewPreparedTxn(ctx, db, s.NodeID(), &record)
+		require.NoError(t, err)
+		if commit {
+			require.NoError(t, resumed.Commit(ctx))
+		} else {
+			require.NoError(t, resumed.Rollback(ctx))
+		}
+
+		res, err := db.Get(ctx, key)
+		require.NoError(t, err)
+		if commit {
+			require.Equal(t, []byte("val"), res.ValueBytes())
+		} else {
+			require.False(t, res.Exists())
+		}
+	})
+}
+
+// TestPreparedTxnPushed tests that a transaction whose write timestamp was
+// pushed before it was prepared is prepared at the pushed timestamp if it can
+// refresh its reads, and can't be prepared otherwise.
+func TestPreparedTxnPushed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	ctx := context.Background()
+
+	s, _, db := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	scratch, err := s.ScratchRange()
+	require.NoError(t, err)
+
+	testutils.RunTrueAndFalse(t, "refresh", func(t *testing.T, refresh bool) {
+		key := append(scratch[:len(scratch):len(scratch)], fmt.Sprint(refresh, "-a")...)
+		readKey := append(scratch[:len(scratch):len(scratch)], fmt.Sprint(refresh, "-b")...)
+		txn := db.NewTxn(ctx, "prepared")
+		_, err := txn.Get(ctx, readKey)
+		require.NoError(t, err)
+		require.NoError(t, txn.Put(ctx, key, "val"))
+		readTS := txn.ReadTimestamp()
+		if !refresh {
+			// The transaction can't refresh its read above the timestamp at which
+			// the key is written.
+			require.NoError(t, db.Put(ctx, readKey, "other"))
+		}
+
+		// A high-priority reader pushes the write timestamp of the transaction.
+		require.NoError(t, db.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+			if err := txn.SetUserPriority(roachpb.MaxUserPriority); err != nil {
+				return err
+			}
+			_, err := txn.Get(ctx, key)
+			return err
+		}))
+
+		err = txn.Prepare(ctx)
+		if !refresh {
+			require.True(t, errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)),
+				"unexpected error: %v", err)
+			require.False(t, txn.IsPrepared())
+			require.NoError(t, txn.Rollback(ctx))
+			return
+		}
+		require.NoError(t, err)
+		require.True(t, txn.IsPrepared())
+		prepared := txn.TestingCloneTxn()
+		require.True(t, readTS.Less(prepared.WriteTimestamp))
+
+		// The prepared transaction is committed at the pushed timestamp.
+		meta := prepared.TxnMeta
+		var b kv.Batch
+		b.AddRawRequest(&roachpb.QueryTxnRequest{
+			RequestHeader: roachpb.RequestHeader{Key: meta.Key},
+			Txn:           meta,
+		})
+		require.NoError(t, db.Run(ctx, &b))
+		rec := b.RawResponse().Responses[0].GetQueryTxn().QueriedTxn
+		require.Equal(t, roachpb.PREPARED, rec.Status)
+		require.Equal(t, prepared.WriteTimestamp, rec.WriteTimestamp)
+		resumed, err := kv.NewPreparedTxn(ctx, db, s.NodeID(), &rec)
+		require.NoError(t, err)
+		require.NoError(t, resumed.Commit(ctx))
+
+		kvs, err := db.Scan(ctx, key, key.Next(), 0 /* maxRows */)
+		require.NoError(t, err)
+		require.Len(t, kvs, 1)
+		require.Equal(t, prepared.WriteTimestamp, kvs[0].Value.Timestamp)
+	})
+}
@@ -136,6 +136,18 @@ var migrations = []migration.Migration{
 		NoPrecondition,
 		alterSystemTableStatisticsAddAvgSize,
 	),
+	migration.NewTenantMigration(
+		"add the system.notifications table",
+		toCV(clusterversion.NotificationsTable),
+		NoPrecondition,
+		notificationsTableMigration,
+	),
+	migration.NewTenantMigration(
+		"add the system.prepared_transactions table",
+		toCV(clusterversion.PreparedTransactionsTable),
+		NoPrecondition,
+		preparedTransactionsTableMigration,
+	),
 }
 
 func init() {
@@ -88,6 +88,7 @@ const (
 	updatesTSCacheOnErr             // commands which make read data available on errors
 	needsRefresh                    // commands which require refreshes to avoid serializable retries
 	canBackpressure                 // commands which deserve backpressure when a Range grows too large
+	canSkipLocked                   // commands which can evaluate under the SkipLocked wait policy
 )
 
 // IsReadOnly returns true iff the request is read-only. A request is
@@ -137,6 +138,22 @@ func LockingDurability(args Request) lock.Durability {
 	return lock.Replicated
 }
 
+// LockingStrength returns the strength of the locks acquired by the request.
+// Locking reads acquire locks with the strength specified in their KeyLocking
+// field, while all other locking requests acquire Exclusive locks. The
+// function assumes that IsLocking(args).
+func LockingStrength(args Request) lock.Strength {
+	switch t := args.(type) {
+	case *GetRequest:
+		return t.KeyLocking
+	case *ScanRequest:
+		return t.KeyLocking
+	case *ReverseScanRequest:
+		return t.KeyLocking
+	}
+	return lock.Exclusive
+}
+
 // IsIntentWrite returns true if the request produces write intents at
 // the request's sequence number when used within a transaction.
 func IsIntentWrite(args Request) bool {
@@ -176,6 +193,12 @@ func CanBackpressure(args Request) bool {
 	return (args.flags() & canBackpressure) != 0
 }
 
+// CanSkipLocked returns whether the command can evaluate under the
+// SkipLocked wait policy.
+func CanSkipLocked(args Request) bool {
+	return (args.flags() & canSkipLocked) != 0
+}
+
 // Request is an interface for RPC requests.
 type Request interface {
 	protoutil.Message
@@ -1169,7 +1192,7 @@ func flagForLockStrength(l lock.Strength) int {
 
 func (gr *GetRequest) flags() int {
 	maybeLocking := flagForLockStrength(gr.KeyLocking)
-	return isRead | isTxn | maybeLocking | updatesTSCache | needsRefresh
+	return isRead | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 func (*PutRequest) flags() int {
@@ -1244,12 +1267,12 @@ func (*RevertRangeRequest) flags() int { return isWrite | isRange }
 
 func (sr *ScanRequest) flags() int {
 	maybeLocking := flagForLockStrength(sr.KeyLocking)
-	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh
+	return isRead | isRange | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 func (rsr *ReverseScanRequest) flags() int {
 	maybeLocking := flagForLockStrength(rsr.KeyLocking)
-	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh
+	return isRead | isRange | isReverse | isTxn | maybeLocking | updatesTSCache | needsRefresh | canSkipLocked
 }
 
 // EndTxn updates the timestamp cache to prevent replays.
@@ -170,6 +170,7 @@ func (s *StatementStatistics) Add(other *StatementStatistics) {
 
 	if s.SensitiveInfo.MostRecentPlanTimestamp.Before(other.SensitiveInfo.MostRecentPlanTimestamp) {
 		s.SensitiveInfo = other.SensitiveInfo
+		s.IndexRecommendations = other.IndexRecommendations
 	}
 
 	if s.LastExecTimestamp.Before(other.LastExecTimestamp) {
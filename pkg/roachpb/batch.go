@@ -170,6 +170,24 @@ func (ba *BatchRequest) IsLocking() bool {
 	return ba.hasFlag(isLocking)
 }
 
+// ReadLockStrength returns the strength with which the read-only requests in
+// the BatchRequest lock the keys that they read. It returns lock.Shared if the
+// batch contains a read-only request that acquires Shared locks and lock.None
+// otherwise. Read-only requests that acquire Exclusive locks are not
+// considered, since they access their keys as writers do.
+func (ba *BatchRequest) ReadLockStrength() lock.Strength {
+	if !ba.IsLocking() {
+		return lock.None
+	}
+	for _, union := range ba.Requests {
+		req := union.GetInner()
+		if IsReadOnly(req) && IsLocking(req) && LockingStrength(req) == lock.Shared {
+			return lock.Shared
+		}
+	}
+	return lock.None
+}
+
 // IsIntentWrite returns true iff the BatchRequest contains an intent write.
 func (ba *BatchRequest) IsIntentWrite() bool {
 	return ba.hasFlag(isIntentWrite)
@@ -696,5 +714,12 @@ func (ba BatchRequest) ValidateForEvaluation() error {
 			return errors.AssertionFailedf("WriteTooOld set but no offset in timestamps. txn: %s", ba.Txn)
 		}
 	}
+	if ba.WaitPolicy == lock.WaitPolicy_SkipLocked {
+		for _, ru := range ba.Requests {
+			if r := ru.GetInner(); !CanSkipLocked(r) {
+				return errors.AssertionFailedf("batch with SkipLocked wait policy contains %s request", r.Method())
+			}
+		}
+	}
 	return nil
 }
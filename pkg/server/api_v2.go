@@ -145,6 +145,7 @@ func (a *apiV2Server) registerRoutes(innerMux *mux.Router, authMux http.Handler)
 		{"health/", a.health, false, regularRole, noOption},
 		{"users/", a.listUsers, true, regularRole, noOption},
 		{"events/", a.listEvents, true, adminRole, noOption},
+		{"index_recommendations/", a.listIndexRecommendations, true, regularRole, noOption},
 		{"databases/", a.listDatabases, true, regularRole, noOption},
 		{"databases/{database_name:[\\w.]+}/", a.databaseDetails, true, regularRole, noOption},
 		{"databases/{database_name:[\\w.]+}/grants/", a.databaseGrants, true, regularRole, noOption},
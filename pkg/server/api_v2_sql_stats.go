@@ -0,0 +1,191 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+)
+
+// An index recommended by the optimizer, aggregated over the statements it
+// was recommended for.
+//
+// swagger:model indexRecommendation
+type indexRecommendation struct {
+	// Statement is the CREATE INDEX statement of the recommended index.
+	Statement string `json:"statement"`
+	// FingerprintIDs are the hex-encoded fingerprint IDs of the statements the
+	// index was recommended for.
+	FingerprintIDs []string `json:"fingerprint_ids"`
+	// ExecutionCount is the number of executions of those statements.
+	ExecutionCount int64 `json:"execution_count"`
+	// WorkloadImpact is the total service latency of those statements, in
+	// seconds. Recommendations are ordered by it.
+	WorkloadImpact float64 `json:"workload_impact"`
+}
+
+// Response for listIndexRecommendations.
+//
+// swagger:model indexRecommendationsResponse
+type indexRecommendationsResponse struct {
+	Recommendations []indexRecommendation `json:"recommendations"`
+
+	// The continuation token, for use in the next paginated call in the `offset`
+	// parameter.
+	Next int `json:"next,omitempty"`
+}
+
+// swagger:operation GET /index_recommendations/ listIndexRecommendations
+//
+// List index recommendations
+//
+// Lists the indexes recommended by the optimizer for the statements in the
+// statement statistics, ordered by their workload impact: the total service
+// latency of the statements each index was recommended for. Requires the
+// admin role or the VIEWACTIVITY role option.
+//
+// ---
+// parameters:
+// - name: start
+//   type: integer
+//   in: query
+//   description: Only consider statistics aggregated at or after this time,
+//     in seconds since Unix epoch.
+//   required: false
+// - name: end
+//   type: integer
+//   in: query
+//   description: Only consider statistics aggregated at or before this time,
+//     in seconds since Unix epoch.
+//   required: false
+// - name: limit
+//   type: integer
+//   in: query
+//   description: Maximum number of results to return in this call.
+//   required: false
+// - name: offset
+//   type: integer
+//   in: query
+//   description: Continuation token for results after a past limited run.
+//   required: false
+// produces:
+// - application/json
+// responses:
+//   "200":
+//     description: Index recommendations response
+//     schema:
+//       "$ref": "#/definitions/indexRecommendationsResponse"
+func (a *apiV2Server) listIndexRecommendations(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getSimplePaginationValues(r)
+	ctx := r.Context()
+	username := getSQLUsername(ctx)
+	ctx = a.admin.server.AnnotateCtx(ctx)
+	queryValues := r.URL.Query()
+
+	if ok, err := a.hasViewActivityPermission(ctx, username); err != nil {
+		apiV2InternalError(ctx, err, w)
+		return
+	} else if !ok {
+		http.Error(w, "user not allowed to access this endpoint", http.StatusForbidden)
+		return
+	}
+
+	var start, end int64
+	for _, p := range []struct {
+		name string
+		val  *int64
+	}{{"start", &start}, {"end", &end}} {
+		if s := queryValues.Get(p.name); len(s) > 0 {
+			v, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid %s value", p.name), http.StatusBadRequest)
+				return
+			}
+			*p.val = v
+		}
+	}
+
+	whereClause, qargs := getFilterAndParams(getTimeFromSeconds(start), getTimeFromSeconds(end))
+	query := fmt.Sprintf(
+		`SELECT
+				rec,
+				array_agg(DISTINCT encode(fingerprint_id, 'hex')),
+				sum((statistics->'statistics'->>'cnt')::INT8)::INT8,
+				sum(
+					(statistics->'statistics'->>'cnt')::FLOAT8 *
+					(statistics->'statistics'->'svcLat'->>'mean')::FLOAT8
+				) AS impact
+			FROM crdb_internal.statement_statistics, unnest(index_recommendations) AS rec
+			%s
+			GROUP BY rec
+			ORDER BY impact DESC, rec`, whereClause)
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", len(qargs)+1)
+		qargs = append(qargs, limit)
+		if offset > 0 {
+			query += fmt.Sprintf(" OFFSET $%d", len(qargs)+1)
+			qargs = append(qargs, offset)
+		}
+	}
+	it, err := a.admin.server.sqlServer.internalExecutor.QueryIteratorEx(
+		ctx, "index-recommendations", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.NodeUserName()},
+		query, qargs...,
+	)
+	if err != nil {
+		apiV2InternalError(ctx, err, w)
+		return
+	}
+
+	resp := indexRecommendationsResponse{Recommendations: []indexRecommendation{}}
+	var ok bool
+	for ok, err = it.Next(ctx); ok; ok, err = it.Next(ctx) {
+		row := it.Cur()
+		rec := indexRecommendation{
+			Statement:      string(tree.MustBeDString(row[0])),
+			ExecutionCount: int64(tree.MustBeDInt(row[2])),
+			WorkloadImpact: float64(tree.MustBeDFloat(row[3])),
+		}
+		for _, id := range tree.MustBeDArray(row[1]).Array {
+			rec.FingerprintIDs = append(rec.FingerprintIDs, string(tree.MustBeDString(id)))
+		}
+		sort.Strings(rec.FingerprintIDs)
+		resp.Recommendations = append(resp.Recommendations, rec)
+	}
+	if err != nil {
+		apiV2InternalError(ctx, err, w)
+		return
+	}
+	if limit > 0 && len(resp.Recommendations) >= limit {
+		resp.Next = offset + len(resp.Recommendations)
+	}
+	writeJSONResponse(ctx, w, 200, resp)
+}
+
+// hasViewActivityPermission returns whether the logged-in user of a request
+// may view the statistics of other users' statements: admins, and users
+// with the VIEWACTIVITY role option.
+func (a *apiV2Server) hasViewActivityPermission(
+	ctx context.Context, username security.SQLUsername,
+) (bool, error) {
+	if isAdmin, err := a.status.privilegeChecker.hasAdminRole(ctx, username); err != nil || isAdmin {
+		return isAdmin, err
+	}
+	return a.status.privilegeChecker.hasRoleOption(ctx, username, roleoption.VIEWACTIVITY)
+}
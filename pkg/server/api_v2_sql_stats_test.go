@@ -0,0 +1,111 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlstats"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexRecommendationsV2(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	ts, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			SQLStatsKnobs: &sqlstats.TestingKnobs{
+				AOSTClause: "AS OF SYSTEM TIME '-1us'",
+			},
+		},
+	})
+	defer ts.Stopper().Stop(ctx)
+
+	conn, err := sqlDB.Conn(ctx)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	db := sqlutils.MakeSQLRunner(conn)
+	db.Exec(t, `SET index_recommendations_enabled = true`)
+	db.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, i INT, s STRING)`)
+	db.Exec(t, `ALTER TABLE t INJECT STATISTICS '[
+		{"columns": ["k"], "created_at": "2018-01-01 1:00:00.00000+00:00", "row_count": 100000, "distinct_count": 100000},
+		{"columns": ["i"], "created_at": "2018-01-01 1:00:00.00000+00:00", "row_count": 100000, "distinct_count": 1000},
+		{"columns": ["s"], "created_at": "2018-01-01 1:00:00.00000+00:00", "row_count": 100000, "distinct_count": 1000}
+	]'`)
+	// Two statements get the same recommendation, and a third one gets
+	// another.
+	for i := 0; i < 3; i++ {
+		db.Exec(t, `SELECT k FROM t WHERE i = 1`)
+	}
+	db.Exec(t, `SELECT count(*) FROM t WHERE i = 1`)
+	db.Exec(t, `SELECT * FROM t WHERE i > 10 AND i < 20`)
+
+	get := func(client http.Client, query string) (int, indexRecommendationsResponse) {
+		req, err := http.NewRequest("GET", ts.AdminURL()+apiV2Path+"index_recommendations/"+query, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		defer func() { require.NoError(t, resp.Body.Close()) }()
+		var rr indexRecommendationsResponse
+		if resp.StatusCode == 200 {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&rr))
+		}
+		return resp.StatusCode, rr
+	}
+
+	client, err := ts.GetAdminAuthenticatedHTTPClient()
+	require.NoError(t, err)
+	code, rr := get(client, "")
+	require.Equal(t, 200, code)
+	recs := make(map[string]indexRecommendation)
+	for i, rec := range rr.Recommendations {
+		if i > 0 {
+			require.GreaterOrEqual(t, rr.Recommendations[i-1].WorkloadImpact, rec.WorkloadImpact)
+		}
+		recs[rec.Statement] = rec
+	}
+	rec, ok := recs["CREATE INDEX ON defaultdb.public.t (i);"]
+	require.True(t, ok, "recommendations: %+v", rr.Recommendations)
+	require.Len(t, rec.FingerprintIDs, 2, "recommendations: %+v", rr.Recommendations)
+	require.Equal(t, int64(4), rec.ExecutionCount)
+	require.Greater(t, rec.WorkloadImpact, 0.0)
+	rec, ok = recs["CREATE INDEX ON defaultdb.public.t (i) STORING (s);"]
+	require.True(t, ok, "recommendations: %+v", rr.Recommendations)
+	require.Len(t, rec.FingerprintIDs, 1)
+	require.Equal(t, int64(1), rec.ExecutionCount)
+
+	code, rr = get(client, "?limit=1")
+	require.Equal(t, 200, code)
+	require.Len(t, rr.Recommendations, 1)
+	require.Equal(t, 1, rr.Next)
+
+	// Users without the VIEWACTIVITY role option may not see the statements of
+	// other users.
+	client, err = ts.GetAuthenticatedHTTPClient(false /* isAdmin */)
+	require.NoError(t, err)
+	code, _ = get(client, "")
+	require.Equal(t, http.StatusForbidden, code)
+	db.Exec(t, `ALTER USER `+authenticatedUserNoAdmin+` VIEWACTIVITY`)
+	code, rr = get(client, "")
+	require.Equal(t, 200, code)
+	require.Len(t, rr.Recommendations, len(recs))
+}
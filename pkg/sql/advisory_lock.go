@@ -0,0 +1,761 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/serverpb"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// AcquireAdvisoryLock implements the tree.AdvisoryLocker interface.
+func (p *planner) AcquireAdvisoryLock(
+	ctx context.Context, key tree.AdvisoryLockKey, exclusive, xact, wait bool,
+) (bool, error) {
+	if err := checkAdvisoryLocksSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return false, err
+	}
+	k, err := p.makeAdvisoryLockKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return p.advisoryLocks.acquire(ctx, k, exclusive, xact, wait, p.SessionData().LockTimeout)
+}
+
+// checkAdvisoryLocksSupported returns an error until the cluster version which
+// allows advisory locks is finalized. Nodes running older versions cannot
+// handle the shared locks acquired by advisory locks, nor the keys which
+// advisory locks write under keys.AdvisoryLockPrefix.
+func checkAdvisoryLocksSupported(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.AdvisoryLocks) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use advisory locks",
+			clusterversion.AdvisoryLocks)
+	}
+	return nil
+}
+
+// ReleaseAdvisoryLock implements the tree.AdvisoryLocker interface.
+func (p *planner) ReleaseAdvisoryLock(
+	ctx context.Context, key tree.AdvisoryLockKey, exclusive bool,
+) (bool, error) {
+	k, err := p.makeAdvisoryLockKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	released, err := p.advisoryLocks.release(ctx, k, exclusive)
+	if err != nil {
+		return false, err
+	}
+	if !released {
+		mode := "ShareLock"
+		if exclusive {
+			mode = "ExclusiveLock"
+		}
+		p.BufferClientNotice(ctx, pgnotice.NewWithSeverityf("WARNING",
+			"you don't own a lock of type %s", mode))
+	}
+	return released, nil
+}
+
+// ReleaseAllAdvisoryLocks implements the tree.AdvisoryLocker interface.
+func (p *planner) ReleaseAllAdvisoryLocks(ctx context.Context) error {
+	p.advisoryLocks.releaseAll(ctx)
+	return nil
+}
+
+// makeAdvisoryLockKey qualifies the key of an advisory lock with the ID of
+// the current database, since advisory locks are scoped to databases.
+func (p *planner) makeAdvisoryLockKey(
+	ctx context.Context, key tree.AdvisoryLockKey,
+) (advisoryLockKey, error) {
+	k := advisoryLockKey{AdvisoryLockKey: key}
+	if name := p.CurrentDatabase(); name != "" {
+		db, err := p.Descriptors().GetImmutableDatabaseByName(
+			ctx, p.txn, name, tree.DatabaseLookupFlags{},
+		)
+		if err != nil {
+			return advisoryLockKey{}, err
+		}
+		if db != nil {
+			k.dbID = db.GetID()
+		}
+	}
+	return k, nil
+}
+
+// advisoryLockKey identifies an advisory lock across the tenant.
+type advisoryLockKey struct {
+	tree.AdvisoryLockKey
+	// dbID is the ID of the database in which the lock was acquired, or 0 if
+	// the session had no current database.
+	dbID descpb.ID
+}
+
+// advisoryLockHolds counts the times an advisory lock is held by a session in
+// one mode.
+type advisoryLockHolds struct {
+	session int
+	xact    int
+}
+
+func (h advisoryLockHolds) held() bool {
+	return h.session > 0 || h.xact > 0
+}
+
+// advisoryLock is an advisory lock held by a session.
+type advisoryLock struct {
+	// txn is the KV transaction which holds the KV lock on the key of the
+	// advisory lock. It is kept open until the session releases the advisory
+	// lock in all modes. Its heartbeats keep the lock held while the session
+	// is alive, and the lock is released once they stop if the node fails.
+	txn *kv.Txn
+	// exclusive is set if the KV lock is held in exclusive mode. A lock held in
+	// both modes remains exclusive until it is released in all modes.
+	exclusive bool
+	// shared and excl count the holds of the lock in each mode.
+	shared, excl advisoryLockHolds
+}
+
+// advisoryLocks tracks the advisory locks held by a session.
+//
+// Advisory locks are implemented with write intents, which the transaction of
+// each advisory lock lays down under a key prefix that is specific to the
+// database and the key of the advisory lock, and which are never committed.
+// Intents are replicated, so the locks survive lease transfers and range
+// merges, unlike the unreplicated locks acquired by locking reads. The value of
+// the intents is the ID of the session which holds the lock.
+//
+// An exclusive lock is an intent on the exclusive key of the advisory lock.
+// A shared lock is an intent on a shared key of the advisory lock that is
+// specific to the transaction, so that shared locks do not conflict with each
+// other. Each mode then checks for holders of the other mode with a locking
+// read, which waits for intents regardless of their timestamp: an exclusive
+// lock scans the shared keys, and a shared lock reads the exclusive key.
+// Since both modes write their intent before they check for the other mode,
+// two conflicting acquisitions cannot both miss each other.
+//
+// The KV layer can't detect deadlocks between sessions, since each lock is held
+// by a transaction of its own, which doesn't wait for anything. A session which
+// waits for a lock for longer than advisoryLockDeadlockDetectionDelay records
+// the lock it waits for under a key specific to the session, and follows the
+// sessions which hold the lock to the locks they wait for in turn. If it finds
+// itself, the acquisition fails.
+//
+// Only the session's goroutine acquires and releases locks. The mutex allows
+// other goroutines to list the locks of the session.
+//
+// A nil *advisoryLocks is used by the planners that aren't associated with a
+// session; it does not support advisory locks.
+type advisoryLocks struct {
+	db       *kv.DB
+	codec    keys.SQLCodec
+	settings *cluster.Settings
+	nodeID   roachpb.NodeID
+	// sessionID is the ID of the session, which is the value of the intents
+	// of its locks.
+	sessionID ClusterWideID
+
+	mu struct {
+		syncutil.Mutex
+		locks map[advisoryLockKey]*advisoryLock
+	}
+}
+
+// advisoryLockDeadlockDetectionDelay is the delay before a session waiting
+// for an advisory lock checks whether it is deadlocked.
+var advisoryLockDeadlockDetectionDelay = settings.RegisterDurationSetting(
+	"sql.advisory_lock.deadlock_detection_delay",
+	"the delay before a session waiting for an advisory lock checks whether it is deadlocked",
+	// The value is analogous to Postgres' deadlock_timeout setting.
+	time.Second,
+	settings.NonNegativeDuration,
+)
+
+func (l *advisoryLocks) init(
+	db *kv.DB, codec keys.SQLCodec, settings *cluster.Settings, nodeID roachpb.NodeID,
+) {
+	l.db = db
+	l.codec = codec
+	l.settings = settings
+	l.nodeID = nodeID
+	l.mu.locks = make(map[advisoryLockKey]*advisoryLock)
+}
+
+// acquire acquires an advisory lock in the given mode, waiting for
+// conflicting holders to release it unless wait is false, in which case it
+// returns false if the lock is not available.
+func (l *advisoryLocks) acquire(
+	ctx context.Context,
+	key advisoryLockKey,
+	exclusive, xact, wait bool,
+	lockTimeout time.Duration,
+) (bool, error) {
+	if l == nil {
+		return false, pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks are not supported by this connection")
+	}
+	al := l.mu.locks[key]
+	if al == nil || (exclusive && !al.exclusive) {
+		txn := kv.NewTxn(ctx, l.db, l.nodeID)
+		txn.SetDebugName("advisory lock")
+		// The transaction must not be aborted by the transactions that push
+		// it, apart from the deadlock detection of the KV layer.
+		if err := txn.SetUserPriority(roachpb.MaxUserPriority); err != nil {
+			return false, err
+		}
+		// A shared lock held by the session is promoted by acquiring the lock
+		// in exclusive mode in a new transaction, which must not wait for the
+		// shared key of the session.
+		var ownSharedKey roachpb.Key
+		if al != nil {
+			ownSharedKey = l.sharedKey(key, al.txn)
+		}
+		acquire := l.acquireKVLock
+		if wait {
+			acquire = l.acquireKVLockDetectingDeadlocks
+		}
+		if err := acquire(
+			ctx, txn, key, exclusive, wait, lockTimeout, ownSharedKey,
+		); err != nil {
+			rollbackAdvisoryLockTxn(ctx, txn)
+			return convertAdvisoryLockError(err)
+		}
+		var sharedTxn *kv.Txn
+		if al == nil {
+			al = &advisoryLock{}
+		} else {
+			sharedTxn = al.txn
+		}
+		l.mu.Lock()
+		al.txn = txn
+		al.exclusive = exclusive
+		l.mu.locks[key] = al
+		l.mu.Unlock()
+		if sharedTxn != nil {
+			// The exclusive lock covers the shared lock, which is released.
+			rollbackAdvisoryLockTxn(ctx, sharedTxn)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	holds := &al.shared
+	if exclusive {
+		holds = &al.excl
+	}
+	if xact {
+		holds.xact++
+	} else {
+		holds.session++
+	}
+	return true, nil
+}
+
+// acquireKVLock acquires the KV lock of an advisory lock in the given
+// transaction. ownSharedKey, if set, is the shared key of the session, which
+// an exclusive lock does not wait for.
+func (l *advisoryLocks) acquireKVLock(
+	ctx context.Context,
+	txn *kv.Txn,
+	key advisoryLockKey,
+	exclusive, wait bool,
+	lockTimeout time.Duration,
+	ownSharedKey roachpb.Key,
+) error {
+	newBatch := func() *kv.Batch {
+		b := txn.NewBatch()
+		if !wait {
+			b.Header.WaitPolicy = lock.WaitPolicy_Error
+		}
+		b.Header.LockTimeout = lockTimeout
+		return b
+	}
+
+	// Write the intent of the lock before checking for holders of the other
+	// mode. The requests of a batch may be evaluated in parallel on different
+	// ranges, so the check is sent in a separate batch once the intent has been
+	// laid down.
+	exclusiveKey := l.exclusiveKey(key)
+	intentKey := exclusiveKey
+	if !exclusive {
+		intentKey = l.sharedKey(key, txn)
+	}
+	b := newBatch()
+	b.Put(intentKey, l.sessionID.GetBytes())
+	if err := txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	// Write the record of the transaction right away rather than on its first
+	// heartbeat. Once the lease of the range of its record is transferred, a
+	// transaction without a record is aborted by the transactions which push
+	// it, which would release the lock. The intent key is the anchor of the
+	// transaction, since it is the first key the transaction writes.
+	b = txn.NewBatch()
+	b.AddRawRequest(&roachpb.HeartbeatTxnRequest{
+		RequestHeader: roachpb.RequestHeader{Key: intentKey},
+		Now:           l.db.Clock().Now(),
+	})
+	if err := txn.Run(ctx, b); err != nil {
+		return err
+	}
+
+	b = newBatch()
+	if exclusive {
+		sharedKeys := l.sharedKeyPrefix(key)
+		spans := []roachpb.Span{{Key: sharedKeys, EndKey: sharedKeys.PrefixEnd()}}
+		if ownSharedKey != nil {
+			spans = []roachpb.Span{
+				{Key: sharedKeys, EndKey: ownSharedKey},
+				{Key: ownSharedKey.Next(), EndKey: sharedKeys.PrefixEnd()},
+			}
+		}
+		for _, sp := range spans {
+			b.AddRawRequest(&roachpb.ScanRequest{
+				RequestHeader: roachpb.RequestHeaderFromSpan(sp),
+				KeyLocking:    lock.Exclusive,
+			})
+		}
+	} else {
+		b.AddRawRequest(&roachpb.GetRequest{
+			RequestHeader: roachpb.RequestHeader{Key: exclusiveKey},
+			KeyLocking:    lock.Shared,
+		})
+	}
+	return txn.Run(ctx, b)
+}
+
+// acquireKVLockDetectingDeadlocks acquires the KV lock of an advisory lock
+// like acquireKVLock, but fails with a deadlock error if the session finds
+// that it waits for itself.
+func (l *advisoryLocks) acquireKVLockDetectingDeadlocks(
+	ctx context.Context,
+	txn *kv.Txn,
+	key advisoryLockKey,
+	exclusive, wait bool,
+	lockTimeout time.Duration,
+	ownSharedKey roachpb.Key,
+) error {
+	acquireCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	acquired := make(chan struct{})
+	detected := make(chan bool, 1)
+	go func() {
+		deadlocked := l.detectDeadlock(ctx, acquired, key, exclusive)
+		if deadlocked {
+			cancel()
+		}
+		detected <- deadlocked
+	}()
+	err := l.acquireKVLock(acquireCtx, txn, key, exclusive, wait, lockTimeout, ownSharedKey)
+	close(acquired)
+	if deadlocked := <-detected; deadlocked && err != nil {
+		return errors.WithDetail(
+			pgerror.New(pgcode.DeadlockDetected, "deadlock detected"),
+			"The advisory lock is held by a session which waits for a lock held by this session.")
+	}
+	return err
+}
+
+// detectDeadlock waits for the acquisition of an advisory lock to finish, and
+// checks whether the session is deadlocked once the acquisition has waited for
+// advisoryLockDeadlockDetectionDelay. It returns true if the session waits for
+// itself, in which case the acquisition must fail.
+func (l *advisoryLocks) detectDeadlock(
+	ctx context.Context, acquired <-chan struct{}, key advisoryLockKey, exclusive bool,
+) bool {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	timer.Reset(advisoryLockDeadlockDetectionDelay.Get(&l.settings.SV))
+	select {
+	case <-timer.C:
+		timer.Read = true
+	case <-acquired:
+		return false
+	}
+
+	// The lock which the session waits for is recorded until the acquisition
+	// finishes, so that the sessions which wait for the session find it.
+	// Each session records what it waits for before looking for what the others
+	// wait for, so at least one of the sessions of a deadlock finds it.
+	waitTxn := kv.NewTxn(ctx, l.db, l.nodeID)
+	waitTxn.SetDebugName("advisory lock wait")
+	defer rollbackAdvisoryLockTxn(ctx, waitTxn)
+	waitKey := l.waitKey(l.sessionID)
+	err := waitTxn.Put(ctx, waitKey, encodeAdvisoryLockWait(l.encodeKey(key), exclusive))
+	if err == nil {
+		// Reading the record back waits for the pipelined write to lay down its
+		// intent, which the other sessions read without waiting.
+		_, err = waitTxn.Get(ctx, waitKey)
+	}
+	if err != nil {
+		log.Warningf(ctx, "error recording advisory lock wait: %v", err)
+		<-acquired
+		return false
+	}
+	deadlocked, err := l.findDeadlock(ctx, key, exclusive)
+	if err != nil {
+		log.Warningf(ctx, "error detecting advisory lock deadlock: %v", err)
+	}
+	if !deadlocked {
+		<-acquired
+	}
+	return deadlocked
+}
+
+// findDeadlock returns whether the session waits for itself: starting from the
+// advisory lock which it waits for, it follows the sessions which hold each
+// lock to the locks they wait for in turn, until it finds itself or runs out of
+// locks to follow. The session's own intents on the lock it waits for don't
+// make it wait.
+func (l *advisoryLocks) findDeadlock(
+	ctx context.Context, key advisoryLockKey, exclusive bool,
+) (bool, error) {
+	visited := make(map[ClusterWideID]struct{})
+	type wait struct {
+		prefix    roachpb.Key
+		exclusive bool
+	}
+	waits := []wait{{prefix: l.encodeKey(key), exclusive: exclusive}}
+	for first := true; len(waits) > 0; first = false {
+		w := waits[len(waits)-1]
+		waits = waits[:len(waits)-1]
+		holders, err := l.holders(ctx, w.prefix, w.exclusive)
+		if err != nil {
+			return false, err
+		}
+		for _, h := range holders {
+			if h == l.sessionID {
+				if first {
+					continue
+				}
+				return true, nil
+			}
+			if _, ok := visited[h]; ok {
+				continue
+			}
+			visited[h] = struct{}{}
+			prefix, exclusive, ok, err := l.waitOf(ctx, h)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				waits = append(waits, wait{prefix: prefix, exclusive: exclusive})
+			}
+		}
+	}
+	return false, nil
+}
+
+// holders returns the sessions whose intents make an acquisition of the
+// advisory lock with the given key prefix wait: an exclusive acquisition waits
+// for all holders, and a shared one for the exclusive holder. Intents are read
+// without waiting for them.
+func (l *advisoryLocks) holders(
+	ctx context.Context, prefix roachpb.Key, exclusive bool,
+) ([]ClusterWideID, error) {
+	var b kv.Batch
+	b.Header.ReadConsistency = roachpb.READ_UNCOMMITTED
+	if exclusive {
+		b.AddRawRequest(&roachpb.ScanRequest{
+			RequestHeader: roachpb.RequestHeader{Key: prefix, EndKey: prefix.PrefixEnd()},
+		})
+	} else {
+		b.AddRawRequest(&roachpb.GetRequest{
+			RequestHeader: roachpb.RequestHeader{Key: encoding.EncodeUvarintAscending(prefix, 0)},
+		})
+	}
+	if err := l.db.Run(ctx, &b); err != nil {
+		return nil, err
+	}
+	var values []roachpb.Value
+	switch resp := b.RawResponse().Responses[0].GetInner().(type) {
+	case *roachpb.ScanResponse:
+		for _, kv := range resp.IntentRows {
+			values = append(values, kv.Value)
+		}
+	case *roachpb.GetResponse:
+		if resp.IntentValue != nil {
+			values = append(values, *resp.IntentValue)
+		}
+	}
+	holders := make([]ClusterWideID, 0, len(values))
+	for i := range values {
+		b, err := values[i].GetBytes()
+		if err != nil {
+			return nil, err
+		}
+		holders = append(holders, BytesToClusterWideID(b))
+	}
+	return holders, nil
+}
+
+// waitOf returns the key prefix of the advisory lock which the given session
+// waits for, and whether it waits for it in exclusive mode. ok is false if the
+// session doesn't wait for a lock.
+func (l *advisoryLocks) waitOf(
+	ctx context.Context, sessionID ClusterWideID,
+) (prefix roachpb.Key, exclusive, ok bool, _ error) {
+	var b kv.Batch
+	b.Header.ReadConsistency = roachpb.READ_UNCOMMITTED
+	b.AddRawRequest(&roachpb.GetRequest{
+		RequestHeader: roachpb.RequestHeader{Key: l.waitKey(sessionID)},
+	})
+	if err := l.db.Run(ctx, &b); err != nil {
+		return nil, false, false, err
+	}
+	v := b.RawResponse().Responses[0].GetGet().IntentValue
+	if v == nil {
+		return nil, false, false, nil
+	}
+	buf, err := v.GetBytes()
+	if err != nil || len(buf) == 0 {
+		return nil, false, false, err
+	}
+	return buf[1:], buf[0] == 1, true, nil
+}
+
+// encodeAdvisoryLockWait encodes the value under which a session records the
+// advisory lock it waits for.
+func encodeAdvisoryLockWait(prefix roachpb.Key, exclusive bool) []byte {
+	buf := make([]byte, 0, len(prefix)+1)
+	if exclusive {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	return append(buf, prefix...)
+}
+
+// convertAdvisoryLockError converts an error encountered while acquiring the
+// KV lock of an advisory lock. It returns false and no error if the lock was
+// not available and the acquisition did not wait.
+func convertAdvisoryLockError(err error) (bool, error) {
+	if wiErr := (*roachpb.WriteIntentError)(nil); errors.As(err, &wiErr) {
+		switch wiErr.Reason {
+		case roachpb.WriteIntentError_REASON_WAIT_POLICY:
+			return false, nil
+		case roachpb.WriteIntentError_REASON_LOCK_TIMEOUT:
+			return false, pgerror.New(pgcode.LockNotAvailable,
+				"canceling statement due to lock timeout on advisory lock")
+		}
+	}
+	// Errors of the transaction of the lock must not be confused with errors
+	// of the SQL transaction, which may be retried.
+	if errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)) {
+		return false, pgerror.Wrap(errors.Handled(err), pgcode.LockNotAvailable,
+			"could not obtain advisory lock")
+	}
+	return false, err
+}
+
+// The keys of advisory locks and of the locks which sessions wait for are
+// prefixed with the following values under the advisory lock key prefix of the
+// tenant.
+const (
+	advisoryLockKeysPrefix = iota
+	advisoryLockWaitKeysPrefix
+)
+
+// encodeKey returns the prefix of the KV keys of an advisory lock.
+func (l *advisoryLocks) encodeKey(key advisoryLockKey) roachpb.Key {
+	k := encoding.EncodeUvarintAscending(l.codec.AdvisoryLockKeyPrefix(), advisoryLockKeysPrefix)
+	k = encoding.EncodeUvarintAscending(k, uint64(key.dbID))
+	k = encoding.EncodeUvarintAscending(k, uint64(key.ObjSubID))
+	k = encoding.EncodeUvarintAscending(k, uint64(key.ClassID))
+	return encoding.EncodeUvarintAscending(k, uint64(key.ObjID))
+}
+
+// exclusiveKey returns the KV key on which an advisory lock is held in
+// exclusive mode.
+func (l *advisoryLocks) exclusiveKey(key advisoryLockKey) roachpb.Key {
+	return encoding.EncodeUvarintAscending(l.encodeKey(key), 0)
+}
+
+// sharedKeyPrefix returns the prefix of the KV keys on which an advisory lock
+// is held in shared mode.
+func (l *advisoryLocks) sharedKeyPrefix(key advisoryLockKey) roachpb.Key {
+	return encoding.EncodeUvarintAscending(l.encodeKey(key), 1)
+}
+
+// sharedKey returns the KV key on which the given transaction holds an
+// advisory lock in shared mode.
+func (l *advisoryLocks) sharedKey(key advisoryLockKey, txn *kv.Txn) roachpb.Key {
+	return encoding.EncodeBytesAscending(l.sharedKeyPrefix(key), txn.ID().GetBytes())
+}
+
+// waitKey returns the KV key under which the given session records the
+// advisory lock it waits for.
+func (l *advisoryLocks) waitKey(sessionID ClusterWideID) roachpb.Key {
+	k := encoding.EncodeUvarintAscending(l.codec.AdvisoryLockKeyPrefix(), advisoryLockWaitKeysPrefix)
+	return encoding.EncodeBytesAscending(k, sessionID.GetBytes())
+}
+
+// release releases one session-level hold of an advisory lock in the given
+// mode. It returns false if the session holds no such lock.
+func (l *advisoryLocks) release(
+	ctx context.Context, key advisoryLockKey, exclusive bool,
+) (bool, error) {
+	if l == nil {
+		return false, pgerror.New(pgcode.FeatureNotSupported,
+			"advisory locks are not supported by this connection")
+	}
+	al := l.mu.locks[key]
+	if al == nil {
+		return false, nil
+	}
+	l.mu.Lock()
+	holds := &al.shared
+	if exclusive {
+		holds = &al.excl
+	}
+	if holds.session == 0 {
+		l.mu.Unlock()
+		return false, nil
+	}
+	holds.session--
+	l.mu.Unlock()
+	l.maybeRemove(ctx, key, al)
+	return true, nil
+}
+
+// releaseAll releases all session-level holds of advisory locks.
+func (l *advisoryLocks) releaseAll(ctx context.Context) {
+	if l == nil {
+		return
+	}
+	for key, al := range l.mu.locks {
+		l.mu.Lock()
+		al.shared.session = 0
+		al.excl.session = 0
+		l.mu.Unlock()
+		l.maybeRemove(ctx, key, al)
+	}
+}
+
+// heldByTxn returns whether the session holds transaction-level advisory
+// locks.
+func (l *advisoryLocks) heldByTxn() bool {
+	for _, al := range l.mu.locks {
+		if al.shared.xact > 0 || al.excl.xact > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// onTxnFinish is called when the transaction finishes or restarts, and
+// releases the transaction-level holds of advisory locks.
+func (l *advisoryLocks) onTxnFinish(ctx context.Context) {
+	for key, al := range l.mu.locks {
+		if al.shared.xact == 0 && al.excl.xact == 0 {
+			continue
+		}
+		l.mu.Lock()
+		al.shared.xact = 0
+		al.excl.xact = 0
+		l.mu.Unlock()
+		l.maybeRemove(ctx, key, al)
+	}
+}
+
+// close releases all advisory locks held by the session.
+func (l *advisoryLocks) close(ctx context.Context) {
+	for key, al := range l.mu.locks {
+		l.remove(ctx, key, al)
+	}
+}
+
+// maybeRemove releases the KV lock of an advisory lock which is no longer
+// held by the session in any mode.
+func (l *advisoryLocks) maybeRemove(ctx context.Context, key advisoryLockKey, al *advisoryLock) {
+	if !al.shared.held() && !al.excl.held() {
+		l.remove(ctx, key, al)
+	}
+}
+
+// remove releases the KV lock of an advisory lock.
+func (l *advisoryLocks) remove(ctx context.Context, key advisoryLockKey, al *advisoryLock) {
+	l.mu.Lock()
+	delete(l.mu.locks, key)
+	l.mu.Unlock()
+	rollbackAdvisoryLockTxn(ctx, al.txn)
+}
+
+func rollbackAdvisoryLockTxn(ctx context.Context, txn *kv.Txn) {
+	if err := txn.Rollback(ctx); err != nil {
+		log.Warningf(ctx, "error releasing advisory lock: %v", err)
+	}
+}
+
+// serialize returns the advisory locks held by the session, with one entry
+// for each mode in which a lock is held.
+func (l *advisoryLocks) serialize() []serverpb.AdvisoryLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var res []serverpb.AdvisoryLock
+	for key, al := range l.mu.locks {
+		for _, m := range []struct {
+			holds     advisoryLockHolds
+			exclusive bool
+		}{{al.shared, false}, {al.excl, true}} {
+			if !m.holds.held() {
+				continue
+			}
+			res = append(res, serverpb.AdvisoryLock{
+				DatabaseID:       uint32(key.dbID),
+				ClassID:          key.ClassID,
+				ObjID:            key.ObjID,
+				ObjSubID:         key.ObjSubID,
+				Exclusive:        m.exclusive,
+				SessionHolds:     int32(m.holds.session),
+				TransactionHolds: int32(m.holds.xact),
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := &res[i], &res[j]
+		if a.DatabaseID != b.DatabaseID {
+			return a.DatabaseID < b.DatabaseID
+		}
+		if a.ObjSubID != b.ObjSubID {
+			return a.ObjSubID < b.ObjSubID
+		}
+		if a.ClassID != b.ClassID {
+			return a.ClassID < b.ClassID
+		}
+		if a.ObjID != b.ObjID {
+			return a.ObjID < b.ObjID
+		}
+		return !a.Exclusive && b.Exclusive
+	})
+	return res
+}
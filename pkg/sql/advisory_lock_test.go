@@ -0,0 +1,180 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"bytes"
+	"context"
+	gosql "database/sql"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/testcluster"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAdvisoryLocksReleasedOnSessionClose verifies that the advisory locks
+// held by a session are released when its connection is closed.
+func TestAdvisoryLocksReleasedOnSessionClose(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(sqlDB)
+
+	for _, lockFn := range []string{"pg_advisory_lock", "pg_advisory_lock_shared"} {
+		t.Run(lockFn, func(t *testing.T) {
+			holder := serverutils.OpenDBConn(
+				t, s.ServingSQLAddr(), "defaultdb", false /* insecure */, s.Stopper(),
+			)
+			holder.SetMaxOpenConns(1)
+			_, err := holder.Exec(`SELECT ` + lockFn + `(1)`)
+			require.NoError(t, err)
+
+			db.CheckQueryResults(t,
+				`SELECT count(*) FROM crdb_internal.node_advisory_locks WHERE objid = 1`,
+				[][]string{{"1"}},
+			)
+			db.CheckQueryResults(t, `SELECT pg_try_advisory_lock(1)`, [][]string{{"false"}})
+
+			require.NoError(t, holder.Close())
+			db.CheckQueryResultsRetry(t,
+				`SELECT count(*) FROM crdb_internal.node_advisory_locks WHERE objid = 1`,
+				[][]string{{"0"}},
+			)
+			db.CheckQueryResults(t,
+				`SELECT pg_try_advisory_lock(1) AND pg_advisory_unlock(1)`, [][]string{{"true"}},
+			)
+		})
+	}
+}
+
+// TestAdvisoryLocksSurviveLeaseTransfer verifies that the advisory locks held
+// by a session remain held when the lease of the range in which their KV locks
+// are held is transferred to another node.
+func TestAdvisoryLocksSurviveLeaseTransfer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	tc := testcluster.StartTestCluster(t, 3, base.TestClusterArgs{})
+	defer tc.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(tc.ServerConn(1))
+
+	desc := tc.LookupRangeOrFatal(t, keys.SystemSQLCodec.AdvisoryLockKeyPrefix())
+
+	for _, lockFn := range []string{"pg_advisory_lock", "pg_advisory_lock_shared"} {
+		t.Run(lockFn, func(t *testing.T) {
+			holder := serverutils.OpenDBConn(
+				t, tc.Server(0).ServingSQLAddr(), "defaultdb", false /* insecure */, tc.Stopper(),
+			)
+			defer holder.Close()
+			holder.SetMaxOpenConns(1)
+			_, err := holder.Exec(`SELECT ` + lockFn + `(1)`)
+			require.NoError(t, err)
+
+			leaseholder, err := tc.FindRangeLeaseHolder(desc, nil /* hint */)
+			require.NoError(t, err)
+			target := tc.Target(0)
+			if leaseholder == target {
+				target = tc.Target(2)
+			}
+			tc.TransferRangeLeaseOrFatal(t, desc, target)
+
+			db.CheckQueryResults(t, `SELECT pg_try_advisory_lock(1)`, [][]string{{"false"}})
+
+			_, err = holder.Exec(`SELECT pg_advisory_unlock_all()`)
+			require.NoError(t, err)
+			db.CheckQueryResults(t,
+				`SELECT pg_try_advisory_lock(1) AND pg_advisory_unlock(1)`, [][]string{{"true"}},
+			)
+		})
+	}
+}
+
+// TestAdvisoryLocksKeyspace verifies that the keyspace in which advisory locks
+// are held belongs to the tenant, and is outside of the system config span and
+// of the node liveness span.
+func TestAdvisoryLocksKeyspace(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	for _, codec := range []keys.SQLCodec{
+		keys.SystemSQLCodec, keys.MakeSQLCodec(roachpb.MakeTenantID(10)),
+	} {
+		prefix := codec.AdvisoryLockKeyPrefix()
+		require.True(t, bytes.HasPrefix(prefix, codec.TenantPrefix()))
+		require.False(t, keys.SystemConfigSpan.ContainsKey(prefix))
+		require.False(t, keys.NodeLivenessSpan.ContainsKey(prefix))
+	}
+}
+
+// TestAdvisoryLocksDeadlock verifies that a deadlock between two sessions
+// waiting for each other's advisory locks is detected, and that only the
+// acquisition which detects it fails.
+func TestAdvisoryLocksDeadlock(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	db := sqlutils.MakeSQLRunner(sqlDB)
+	db.Exec(t, `SET CLUSTER SETTING sql.advisory_lock.deadlock_detection_delay = '10ms'`)
+
+	var conns [2]*gosql.DB
+	for i := range conns {
+		conns[i] = serverutils.OpenDBConn(
+			t, s.ServingSQLAddr(), "defaultdb", false /* insecure */, s.Stopper(),
+		)
+		defer conns[i].Close()
+		conns[i].SetMaxOpenConns(1)
+		_, err := conns[i].Exec(`SELECT pg_advisory_lock($1)`, i)
+		require.NoError(t, err)
+	}
+
+	// Each session waits for the lock of the other.
+	type result struct {
+		session int
+		err     error
+	}
+	results := make(chan result, len(conns))
+	for i := range conns {
+		go func(i int) {
+			_, err := conns[i].Exec(`SELECT pg_advisory_lock($1)`, 1-i)
+			results <- result{session: i, err: err}
+		}(i)
+	}
+
+	// The session which detects the deadlock still holds its own lock, which
+	// the other session acquires once it is released.
+	res := <-results
+	pqErr := (*pq.Error)(nil)
+	require.True(t, errors.As(res.err, &pqErr), "%v", res.err)
+	require.Equal(t, pgcode.DeadlockDetected, pgcode.MakeCode(string(pqErr.Code)))
+	_, err := conns[res.session].Exec(`SELECT pg_advisory_unlock_all()`)
+	require.NoError(t, err)
+	res = <-results
+	require.NoError(t, res.err)
+	db.CheckQueryResults(t,
+		`SELECT count(*) FROM crdb_internal.node_advisory_locks`, [][]string{{"2"}})
+}
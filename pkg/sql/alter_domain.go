@@ -0,0 +1,196 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
+	"github.com/cockroachdb/errors"
+)
+
+type alterDomainNode struct {
+	n    *tree.AlterDomain
+	desc *typedesc.Mutable
+}
+
+// alterDomainNode implements planNode. We set n here to satisfy the linter.
+var _ planNode = &alterDomainNode{n: nil}
+
+func (p *planner) AlterDomain(ctx context.Context, n *tree.AlterDomain) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER DOMAIN",
+	); err != nil {
+		return nil, err
+	}
+
+	// Resolve the domain.
+	_, desc, err := p.ResolveMutableTypeDescriptor(ctx, n.Domain, true /* required */)
+	if err != nil {
+		return nil, err
+	}
+	if desc.Kind != descpb.TypeDescriptor_DOMAIN {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%q is not a domain", tree.AsStringWithFQNames(n.Domain, &p.semaCtx.Annotations))
+	}
+
+	// The user needs ownership privilege to alter the domain.
+	if err := p.canModifyType(ctx, desc); err != nil {
+		return nil, err
+	}
+
+	return &alterDomainNode{
+		n:    n,
+		desc: desc,
+	}, nil
+}
+
+func (n *alterDomainNode) startExec(params runParams) error {
+	telemetry.Inc(n.n.Cmd.TelemetryCounter())
+
+	config := n.desc.DomainConfig
+	switch t := n.n.Cmd.(type) {
+	case *tree.AlterDomainAddConstraint:
+		name := string(t.Constraint.Name)
+		if name == "" {
+			name = generateDomainCheckName(n.desc.Name, config.Checks)
+		}
+		if err := addDomainCheck(params, config, name, t.Constraint.Check); err != nil {
+			return err
+		}
+		// Verify that the values already stored in columns of the domain satisfy
+		// the new constraint.
+		expr, err := parser.ParseExpr(config.Checks[len(config.Checks)-1].Expr)
+		if err != nil {
+			return err
+		}
+		if err := params.p.validateDomainColumns(
+			params.ctx, n.desc, expr, pgcode.CheckViolation, "values that violate the new constraint",
+		); err != nil {
+			return err
+		}
+	case *tree.AlterDomainDropConstraint:
+		if !n.desc.DropDomainCheck(string(t.Constraint)) {
+			if !t.IfExists {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"constraint %q of domain %q does not exist", t.Constraint, n.desc.Name)
+			}
+			params.p.BufferClientNotice(
+				params.ctx,
+				pgnotice.Newf("constraint %q of domain %q does not exist, skipping", t.Constraint, n.desc.Name),
+			)
+			return nil
+		}
+	case *tree.AlterDomainSetNotNull:
+		if config.NotNull {
+			return nil
+		}
+		// Verify that the columns of the domain do not contain NULL values.
+		expr := &tree.IsNotNullExpr{Expr: tree.NewUnresolvedName(schemaexpr.DomainValueName)}
+		if err := params.p.validateDomainColumns(
+			params.ctx, n.desc, expr, pgcode.NotNullViolation, "null values",
+		); err != nil {
+			return err
+		}
+		config.NotNull = true
+	case *tree.AlterDomainDropNotNull:
+		if !config.NotNull {
+			return nil
+		}
+		config.NotNull = false
+	default:
+		return errors.AssertionFailedf("unknown alter domain cmd %s", t)
+	}
+
+	if err := params.p.writeTypeSchemaChange(
+		params.ctx, n.desc, tree.AsStringWithFQNames(n.n, params.p.Ann()),
+	); err != nil {
+		return err
+	}
+
+	// Write a log event.
+	return params.p.logEvent(params.ctx,
+		n.desc.ID,
+		&eventpb.AlterType{
+			TypeName: tree.AsStringWithFQNames(n.n.Domain, params.p.Ann()),
+		})
+}
+
+// validateDomainColumns verifies that all the values stored in the columns of
+// the given domain satisfy the given domain constraint expression, in which
+// the value being checked is referred to as VALUE. If a violating value is
+// found, an error with the given code which mentions the given description of
+// the violating values is returned.
+func (p *planner) validateDomainColumns(
+	ctx context.Context,
+	desc *typedesc.Mutable,
+	checkExpr tree.Expr,
+	code pgcode.Code,
+	violation string,
+) error {
+	domainOID := typedesc.TypeIDToOID(desc.ID)
+	for _, id := range desc.ReferencingDescriptorIDs {
+		tableDesc, err := p.Descriptors().GetImmutableTableByID(
+			ctx, p.txn, id, tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		if !tableDesc.IsTable() {
+			continue
+		}
+		for _, col := range tableDesc.PublicColumns() {
+			if col.GetType().Oid() != domainOID {
+				continue
+			}
+			colName := col.ColName()
+			expr, err := schemaexpr.ReplaceDomainValue(checkExpr, func() tree.Expr {
+				return &tree.ColumnItem{ColumnName: colName}
+			})
+			if err != nil {
+				return err
+			}
+			queryStr := fmt.Sprintf(`SELECT 1 FROM [%d AS t] WHERE NOT (%s) LIMIT 1`,
+				tableDesc.GetID(), tree.AsStringWithFlags(expr, tree.FmtSerializable))
+			log.Infof(ctx, "validating domain constraint with query %q", queryStr)
+
+			rows, err := p.ExecCfg().InternalExecutor.QueryRow(
+				ctx, "validate domain constraint", p.txn, queryStr,
+			)
+			if err != nil {
+				return err
+			}
+			if rows.Len() > 0 {
+				return pgerror.Newf(code, "column %q of table %q contains %s",
+					col.GetName(), tableDesc.GetName(), violation)
+			}
+		}
+	}
+	return nil
+}
+
+func (n *alterDomainNode) Next(params runParams) (bool, error) { return false, nil }
+func (n *alterDomainNode) Values() tree.Datums                 { return tree.Datums{} }
+func (n *alterDomainNode) Close(ctx context.Context)           {}
+func (n *alterDomainNode) ReadingOwnWrites()                   {}
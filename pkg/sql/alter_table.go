@@ -18,14 +18,17 @@ import (
 	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/paramparse"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
@@ -82,6 +85,9 @@ func (p *planner) AlterTable(ctx context.Context, n *tree.AlterTable) (planNode,
 	if tableDesc == nil {
 		return newZeroNode(nil /* columns */), nil
 	}
+	if err := checkNotForeignTable(tableDesc, "ALTER TABLE"); err != nil {
+		return nil, err
+	}
 
 	// This check for CREATE privilege is kept for backwards compatibility.
 	if err := p.CheckPrivilege(ctx, tableDesc, privilege.CREATE); err != nil {
@@ -156,7 +162,11 @@ func (n *alterTableNode) startExec(params runParams) error {
 			"%q was not resolved as a table but is %T", resolved, resolved)
 	}
 
-	for i, cmd := range n.n.Cmds {
+	// Some commands (e.g. setting or resetting row-level TTL) expand into
+	// further commands, which are appended to cmds as they are processed.
+	cmds := n.n.Cmds
+	for i := 0; i < len(cmds); i++ {
+		cmd := cmds[i]
 		telemetry.Inc(cmd.TelemetryCounter())
 
 		if !n.tableDesc.HasPrimaryKey() && !isAlterCmdValidWithoutPrimaryKey(cmd) {
@@ -217,6 +227,9 @@ func (n *alterTableNode) startExec(params runParams) error {
 					}
 					continue
 				}
+				if d.Deferrable != tree.NotDeferrableConstraint {
+					return errDeferrableUniqueIndex
+				}
 
 				if d.PrimaryKey {
 					// Translate this operation into an ALTER PRIMARY KEY command.
@@ -487,6 +500,18 @@ func (n *alterTableNode) startExec(params runParams) error {
 				}
 			}
 
+			if n.tableDesc.HasRowLevelTTL() && t.Column == colinfo.TTLDefaultExpirationColumnName {
+				return errors.WithHintf(
+					pgerror.Newf(
+						pgcode.InvalidColumnReference,
+						"cannot drop column %s while row level TTL is active",
+						t.Column,
+					),
+					"use ALTER TABLE %s RESET (ttl) instead",
+					tree.Name(n.tableDesc.GetName()),
+				)
+			}
+
 			colToDrop, err := n.tableDesc.FindColumnWithName(t.Column)
 			if err != nil {
 				if t.IfExists {
@@ -884,7 +909,7 @@ func (n *alterTableNode) startExec(params runParams) error {
 					"column %q in the middle of being dropped", t.GetColumn())
 			}
 			// Apply mutations to copy of column descriptor.
-			if err := applyColumnMutation(params.ctx, n.tableDesc, col, t, params, n.n.Cmds, tn); err != nil {
+			if err := applyColumnMutation(params.ctx, n.tableDesc, col, t, params, cmds, tn); err != nil {
 				return err
 			}
 			descriptorChanged = true
@@ -953,6 +978,53 @@ func (n *alterTableNode) startExec(params runParams) error {
 			}
 			descriptorChanged = descriptorChanged || changed
 
+		case *tree.AlterTableSetStorageParams:
+			oldTTL := n.tableDesc.GetRowLevelTTL()
+			if oldTTL != nil {
+				ttlCopy := *oldTTL
+				oldTTL = &ttlCopy
+			}
+			if err := paramparse.ApplyStorageParameters(
+				params.ctx,
+				params.p.SemaCtx(),
+				params.EvalContext(),
+				t.StorageParams,
+				paramparse.NewTableStorageParamObserver(n.tableDesc),
+			); err != nil {
+				return err
+			}
+			descriptorChanged = true
+			extraCmds, err := handleTTLStorageParamChange(
+				params, tn, n, oldTTL, n.tableDesc.GetRowLevelTTL(),
+			)
+			if err != nil {
+				return err
+			}
+			cmds = append(cmds, extraCmds...)
+
+		case *tree.AlterTableResetStorageParams:
+			oldTTL := n.tableDesc.GetRowLevelTTL()
+			if oldTTL != nil {
+				ttlCopy := *oldTTL
+				oldTTL = &ttlCopy
+			}
+			if err := paramparse.ResetStorageParameters(
+				params.ctx,
+				params.EvalContext(),
+				t.Params,
+				paramparse.NewTableStorageParamObserver(n.tableDesc),
+			); err != nil {
+				return err
+			}
+			descriptorChanged = true
+			extraCmds, err := handleTTLStorageParamChange(
+				params, tn, n, oldTTL, n.tableDesc.GetRowLevelTTL(),
+			)
+			if err != nil {
+				return err
+			}
+			cmds = append(cmds, extraCmds...)
+
 		case *tree.AlterTableInjectStats:
 			sd, ok := n.statsData[i]
 			if !ok {
@@ -1761,3 +1833,95 @@ func (p *planner) tryRemoveFKBackReferences(
 	tableDesc.InboundFKs = tableDesc.InboundFKs[:sliceIdx]
 	return nil
 }
+
+// handleTTLStorageParamChange keeps the TTL schedule and the automatic
+// expiration column of a table in sync with its row-level TTL configuration
+// after a SET or RESET of its storage parameters. It returns any additional
+// commands required to update the expiration column.
+func handleTTLStorageParamChange(
+	params runParams,
+	tn *tree.TableName,
+	n *alterTableNode,
+	before, after *descpb.TableDescriptor_RowLevelTTL,
+) (tree.AlterTableCmds, error) {
+	tableDesc := n.tableDesc
+	switch {
+	case before == nil && after == nil:
+		return nil, nil
+
+	case before == nil && after != nil:
+		if err := checkTTLEnabledForCluster(params.ctx, params.ExecCfg().Settings); err != nil {
+			return nil, err
+		}
+		def, err := rowLevelTTLAutomaticColumnDef(after)
+		if err != nil {
+			return nil, err
+		}
+		sj, err := CreateRowLevelTTLScheduledJob(
+			params.ctx,
+			params.ExecCfg(),
+			params.p.txn,
+			params.p.User(),
+			tableDesc.GetID(),
+			after,
+		)
+		if err != nil {
+			return nil, err
+		}
+		after.ScheduleID = sj.ScheduleID()
+		// The column is added immediately rather than as a subsequent command,
+		// as the descriptor is validated before the next command is run.
+		addCol := &tree.AlterTableAddColumn{ColumnDef: def}
+		if err := params.p.addColumnImpl(params, n, tn, tableDesc, addCol); err != nil {
+			return nil, err
+		}
+		return nil, nil
+
+	case before != nil && after == nil:
+		telemetry.Inc(sqltelemetry.RowLevelTTLDropped)
+		if err := params.p.deleteRowLevelTTLSchedule(params.ctx, before); err != nil {
+			return nil, err
+		}
+		return tree.AlterTableCmds{
+			&tree.AlterTableDropColumn{Column: colinfo.TTLDefaultExpirationColumnName},
+		}, nil
+	}
+
+	// The TTL configuration was modified in place; the schedule remains the
+	// same, but its cron expression and the column expressions may change.
+	after.ScheduleID = before.ScheduleID
+	var cmds tree.AlterTableCmds
+	if before.DurationExpr != after.DurationExpr {
+		intervalExpr, err := parser.ParseExpr(after.DurationExpr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unexpected expression for TTL duration")
+		}
+		cmds = append(
+			cmds,
+			&tree.AlterTableSetDefault{
+				Column:  colinfo.TTLDefaultExpirationColumnName,
+				Default: rowLevelTTLAutomaticColumnExpr(intervalExpr),
+			},
+			&tree.AlterTableSetOnUpdate{
+				Column: colinfo.TTLDefaultExpirationColumnName,
+				Expr:   rowLevelTTLAutomaticColumnExpr(intervalExpr),
+			},
+		)
+	}
+	if before.DeletionCronOrDefault() != after.DeletionCronOrDefault() {
+		env := jobSchedulerEnv(params.ExecCfg())
+		sj, err := jobs.LoadScheduledJob(
+			params.ctx, env, after.ScheduleID, params.ExecCfg().InternalExecutor, params.p.txn,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := sj.SetSchedule(after.DeletionCronOrDefault()); err != nil {
+			return nil, err
+		}
+		if err := sj.Update(params.ctx, params.ExecCfg().InternalExecutor, params.p.txn); err != nil {
+			return nil, err
+		}
+	}
+	return cmds, nil
+}
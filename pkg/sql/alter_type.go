@@ -77,6 +77,32 @@ func (p *planner) AlterType(ctx context.Context, n *tree.AlterType) (planNode, e
 		}
 	case descpb.TypeDescriptor_ENUM:
 		sqltelemetry.IncrementEnumCounter(sqltelemetry.EnumAlter)
+	case descpb.TypeDescriptor_DOMAIN:
+		// Domains can only be renamed, moved or have their owner changed using
+		// the alter type command.
+		switch n.Cmd.(type) {
+		case *tree.AlterTypeRename, *tree.AlterTypeSetSchema, *tree.AlterTypeOwner:
+		case *tree.AlterTypeRenameAttribute, *tree.AlterTypeAlterAttributes:
+			return nil, pgerror.Newf(
+				pgcode.WrongObjectType,
+				"%q is not a composite type",
+				tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations))
+		default:
+			return nil, errors.WithHint(
+				pgerror.Newf(
+					pgcode.WrongObjectType,
+					"%q is not an enum",
+					tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations)),
+				"use ALTER DOMAIN to modify the constraints of a domain")
+		}
+	case descpb.TypeDescriptor_COMPOSITE:
+		switch n.Cmd.(type) {
+		case *tree.AlterTypeAddValue, *tree.AlterTypeRenameValue, *tree.AlterTypeDropValue:
+			return nil, pgerror.Newf(
+				pgcode.WrongObjectType,
+				"%q is not an enum",
+				tree.AsStringWithFQNames(n.Type, &p.semaCtx.Annotations))
+		}
 	case descpb.TypeDescriptor_TABLE_IMPLICIT_RECORD_TYPE:
 		return nil, pgerror.Newf(
 			pgcode.WrongObjectType,
@@ -127,6 +153,10 @@ func (n *alterTypeNode) startExec(params runParams) error {
 		eventLogDone = true // done inside alterTypeOwner().
 	case *tree.AlterTypeDropValue:
 		err = params.p.dropEnumValue(params.ctx, n.desc, t.Val)
+	case *tree.AlterTypeRenameAttribute:
+		err = params.p.renameCompositeAttribute(params.ctx, n.desc, t, tree.AsStringWithFQNames(n.n, params.p.Ann()))
+	case *tree.AlterTypeAlterAttributes:
+		err = params.p.alterCompositeAttributes(params.ctx, n.desc, t.Actions, tree.AsStringWithFQNames(n.n, params.p.Ann()))
 	default:
 		err = errors.AssertionFailedf("unknown alter type cmd %s", t)
 	}
@@ -214,6 +244,88 @@ func (p *planner) dropEnumValue(
 	return p.writeTypeSchemaChange(ctx, desc, desc.Name)
 }
 
+func (p *planner) renameCompositeAttribute(
+	ctx context.Context, desc *typedesc.Mutable, node *tree.AlterTypeRenameAttribute, jobDesc string,
+) error {
+	if desc.Kind != descpb.TypeDescriptor_COMPOSITE {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a composite type", desc.Name)
+	}
+	elem := desc.GetCompositeAttribute(string(node.Name))
+	if elem == nil {
+		return pgerror.Newf(pgcode.UndefinedColumn, "attribute %q does not exist", node.Name)
+	}
+	if desc.GetCompositeAttribute(string(node.NewName)) != nil {
+		return pgerror.Newf(pgcode.DuplicateColumn, "attribute %q already exists", node.NewName)
+	}
+	// Renaming an attribute does not change the representation of the values
+	// of the type, so it is allowed even if the type is in use.
+	elem.ElementLabel = string(node.NewName)
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
+func (p *planner) alterCompositeAttributes(
+	ctx context.Context,
+	desc *typedesc.Mutable,
+	actions tree.AlterTypeAttributeActions,
+	jobDesc string,
+) error {
+	if desc.Kind != descpb.TypeDescriptor_COMPOSITE {
+		return pgerror.Newf(pgcode.WrongObjectType, "%q is not a composite type", desc.Name)
+	}
+	// Adding, dropping or changing the type of an attribute changes the
+	// representation of the values of the type, which is not supported when
+	// the type is in use.
+	if len(desc.ReferencingDescriptorIDs) > 0 {
+		dependentNames, err := p.getFullyQualifiedTableNamesFromIDs(ctx, desc.ReferencingDescriptorIDs)
+		if err != nil {
+			return errors.Wrapf(err, "type %q has dependent objects", desc.Name)
+		}
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			"cannot alter type %q because other objects (%v) still depend on it",
+			desc.Name,
+			dependentNames,
+		)
+	}
+	for _, action := range actions {
+		switch t := action.(type) {
+		case *tree.AlterTypeAddAttribute:
+			if desc.GetCompositeAttribute(string(t.Name)) != nil {
+				return pgerror.Newf(pgcode.DuplicateColumn, "attribute %q already exists", t.Name)
+			}
+			typ, err := p.resolveCompositeAttributeType(ctx, t.Type)
+			if err != nil {
+				return err
+			}
+			desc.AddCompositeAttribute(string(t.Name), typ)
+		case *tree.AlterTypeDropAttribute:
+			if !desc.DropCompositeAttribute(string(t.Name)) {
+				if t.IfExists {
+					p.BufferClientNotice(
+						ctx,
+						pgnotice.Newf("attribute %q does not exist, skipping", t.Name),
+					)
+					continue
+				}
+				return pgerror.Newf(pgcode.UndefinedColumn, "attribute %q does not exist", t.Name)
+			}
+		case *tree.AlterTypeAlterAttributeType:
+			elem := desc.GetCompositeAttribute(string(t.Name))
+			if elem == nil {
+				return pgerror.Newf(pgcode.UndefinedColumn, "attribute %q does not exist", t.Name)
+			}
+			typ, err := p.resolveCompositeAttributeType(ctx, t.Type)
+			if err != nil {
+				return err
+			}
+			elem.ElementType = typ
+		default:
+			return errors.AssertionFailedf("unknown alter type attribute action %T", t)
+		}
+	}
+	return p.writeTypeSchemaChange(ctx, desc, jobDesc)
+}
+
 func (p *planner) renameType(ctx context.Context, n *alterTypeNode, newName string) error {
 	err := catalogkv.CheckObjectCollision(
 		ctx,
@@ -138,6 +138,7 @@ func (cb *ColumnBackfiller) init(
 	return cb.fetcher.Init(
 		evalCtx.Context,
 		evalCtx.Codec,
+		evalCtx.Settings,
 		false, /* reverse */
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
@@ -806,6 +807,7 @@ func (ib *IndexBackfiller) BuildIndexEntriesChunk(
 	if err := fetcher.Init(
 		ib.evalCtx.Context,
 		ib.evalCtx.Codec,
+		ib.evalCtx.Settings,
 		false, /* reverse */
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
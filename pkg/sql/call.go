@@ -0,0 +1,478 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// callNode executes a stored procedure. The statements of the body of the
+// procedure are run through the internal executor on behalf of the session
+// user, in the transaction of the CALL statement.
+//
+// When CALL is sent on its own in a simple query outside of an explicit
+// transaction, the body may contain COMMIT and ROLLBACK statements. As in
+// Postgres, these end the transaction of the session: the statements before
+// the first transaction control statement run in the transaction of the CALL
+// statement, and the connExecutor commits or rolls it back through its state
+// machine once the callNode finishes. The CALL statement is then executed
+// again in a new transaction, which resumes the procedure after the
+// transaction control statement (see procedureCall). Transaction control is
+// not allowed inside an explicit transaction, in a query string with several
+// statements, or in a portal, since portals are closed with the transaction.
+//
+// Note that schema changes performed by the body of a procedure do not wait
+// for their jobs to complete.
+type callNode struct {
+	n    *tree.Call
+	proc catalog.FunctionDescriptor
+	// args are the typed argument expressions of the call, in the order of the
+	// arguments of the procedure. They are nil when the callNode resumes a
+	// procedure which ended a transaction.
+	args    tree.TypedExprs
+	columns colinfo.ResultColumns
+
+	run struct {
+		row  tree.Datums
+		done bool
+	}
+}
+
+// procedureCall tracks the execution of a procedure which ended the
+// transaction of its CALL statement with COMMIT or ROLLBACK. It belongs to the
+// connExecutor and outlives the transactions of the session.
+type procedureCall struct {
+	// canEndTxn is set by the connExecutor if the statement being executed may
+	// end its transaction, i.e. it is the only statement of a simple query.
+	canEndTxn bool
+
+	// proc is the procedure being executed, or nil if no procedure ended a
+	// transaction.
+	proc    catalog.FunctionDescriptor
+	columns colinfo.ResultColumns
+	// stmts are the statements of the body of the procedure, with the
+	// references to its arguments replaced by their values.
+	stmts []tree.Statement
+	// start is the index of the first statement to run in the current
+	// transaction.
+	start int
+	// txnControl is the COMMIT or ROLLBACK statement which ends the current
+	// transaction, if the procedure reached one. It is applied by the
+	// connExecutor once the CALL statement finishes.
+	txnControl tree.Statement
+	// next is the index of the statement following txnControl.
+	next int
+}
+
+// inProgress returns whether a procedure ended a transaction and has
+// statements left to run.
+func (c *procedureCall) inProgress() bool {
+	return c.proc != nil
+}
+
+// rollback returns whether the procedure ended the current transaction with
+// ROLLBACK.
+func (c *procedureCall) rollback() bool {
+	_, ok := c.txnControl.(*tree.RollbackTransaction)
+	return ok
+}
+
+// advance is called by the connExecutor once the state machine processed the
+// outcome of a CALL statement, and returns the advance code to use in place
+// of the given one. If the procedure ended the transaction of the statement
+// successfully, the statement stays in place so that the rest of the procedure
+// runs in a new transaction. In all other cases the state of the procedure is
+// kept only while the statement is executed again.
+func (c *procedureCall) advance(code advanceCode, failed bool) advanceCode {
+	switch {
+	case code == stayInPlace || code == rewind:
+		// The statement starts a transaction or is retried, and resumes the
+		// procedure from the same statement.
+		c.txnControl = nil
+	case c.txnControl != nil && !failed:
+		c.start, c.txnControl = c.next, nil
+		return stayInPlace
+	default:
+		*c = procedureCall{canEndTxn: c.canEndTxn}
+	}
+	return code
+}
+
+// Call executes a stored procedure.
+// Privileges: None.
+func (p *planner) Call(ctx context.Context, n *tree.Call) (planNode, error) {
+	if call := p.procedureCall; call != nil && call.inProgress() {
+		// The procedure ended the previous transaction; resume it without
+		// resolving it again.
+		return &callNode{n: n, proc: call.proc, columns: call.columns}, nil
+	}
+	name, ok := n.Proc.Func.FunctionReference.(*tree.UnresolvedName)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.WrongObjectType, "%s is not a procedure", n.Proc.Func.String())
+	}
+	if n.Proc.Type != 0 || n.Proc.Filter != nil || len(n.Proc.OrderBy) > 0 || n.Proc.WindowDef != nil {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not an aggregate or window function", name)
+	}
+
+	// Avoid leasing the descriptor of the procedure, since the body may end
+	// the transaction of the CALL statement, and may itself modify the
+	// procedure.
+	searchPath := p.SessionData().SearchPath
+	var fns []catalog.FunctionDescriptor
+	var err error
+	p.runWithOptions(resolveFlags{skipCache: true}, func() {
+		fns, err = p.resolveUserDefinedFunctions(ctx, name, searchPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	overloads := make([]tree.Overload, 0, len(fns))
+	procs := make(map[oid.Oid]catalog.FunctionDescriptor, len(fns))
+	for _, fn := range fns {
+		if !fn.GetIsProcedure() {
+			continue
+		}
+		o, err := funcdesc.MakeOverload(fn)
+		if err != nil {
+			return nil, err
+		}
+		overloads = append(overloads, o)
+		procs[o.Oid] = fn
+	}
+	if len(overloads) == 0 {
+		_, builtinErr := name.ResolveFunction(searchPath)
+		if len(fns) > 0 || builtinErr == nil {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%s is not a procedure", name),
+				"To call a function, use SELECT.")
+		}
+		return nil, pgerror.Newf(pgcode.UndefinedFunction, "procedure %s does not exist", name)
+	}
+
+	// Resolve the overload using a copy of the expression which references the
+	// definition of the procedures, so that it is not cached in the statement.
+	proc := *n.Proc
+	proc.Func = tree.ResolvableFunctionReference{
+		FunctionReference: tree.NewUDFFunctionDefinition(fns[0].GetName(), overloads),
+	}
+	typedExpr, err := tree.TypeCheck(ctx, &proc, &p.semaCtx, types.Any)
+	if err != nil {
+		return nil, err
+	}
+	typedProc, ok := typedExpr.(*tree.FuncExpr)
+	if !ok {
+		return nil, errors.AssertionFailedf("expected function expression, found %T", typedExpr)
+	}
+	desc, ok := procs[typedProc.ResolvedOverload().Oid]
+	if !ok {
+		return nil, errors.AssertionFailedf("unexpected overload for procedure %s", name)
+	}
+	args := make(tree.TypedExprs, len(typedProc.Exprs))
+	for i := range typedProc.Exprs {
+		args[i] = typedProc.Exprs[i].(tree.TypedExpr)
+	}
+	return &callNode{
+		n:       n,
+		proc:    desc,
+		args:    args,
+		columns: procedureOutputColumns(desc.GetArgs()),
+	}, nil
+}
+
+func (n *callNode) startExec(params runParams) error {
+	p := params.p
+	evalCtx := p.EvalContext()
+	call := p.procedureCall
+	var stmts []tree.Statement
+	start := 0
+	if call != nil && call.inProgress() {
+		stmts, start = call.stmts, call.start
+	} else {
+		procArgs := n.proc.GetArgs()
+		args := make(tree.Datums, len(n.args))
+		for i := range n.args {
+			d, err := n.args[i].Eval(evalCtx)
+			if err != nil {
+				return err
+			}
+			if args[i], err = tree.PerformAssignmentCast(evalCtx, d, procArgs[i].Type); err != nil {
+				return err
+			}
+		}
+		var err error
+		if stmts, err = makeProcedureStatements(n.proc, args); err != nil {
+			return err
+		}
+		if err := checkProcedureTxnControl(evalCtx, call, stmts); err != nil {
+			return err
+		}
+	}
+
+	// Run the statements up to the next transaction control statement, if
+	// any, in the transaction of the CALL statement.
+	end := len(stmts)
+	for i := start; i < len(stmts); i++ {
+		if isProcedureTxnControl(stmts[i]) {
+			end = i
+			break
+		}
+	}
+	searchPath := p.SessionData().SearchPath
+	override := sessiondata.InternalExecutorOverride{
+		User:       p.User(),
+		Database:   p.CurrentDatabase(),
+		SearchPath: &searchPath,
+	}
+	ie := p.ExecCfg().InternalExecutor
+	opName := "call-" + n.proc.GetName()
+	for i := start; i < end; i++ {
+		sql := tree.AsStringWithFlags(stmts[i], tree.FmtParsable)
+		if i < len(stmts)-1 || len(n.columns) == 0 {
+			if _, err := ie.ExecEx(params.ctx, opName, p.txn, override, sql); err != nil {
+				return err
+			}
+			continue
+		}
+		rows, cols, err := ie.QueryBufferedExWithCols(params.ctx, opName, p.txn, override, sql)
+		if err != nil {
+			return err
+		}
+		if n.run.row, err = n.makeOutputRow(evalCtx, rows, cols); err != nil {
+			return err
+		}
+	}
+	if end < len(stmts) {
+		// Let the connExecutor end the transaction, and execute the CALL
+		// statement again to run the rest of the procedure.
+		*call = procedureCall{
+			canEndTxn:  call.canEndTxn,
+			proc:       n.proc,
+			columns:    n.columns,
+			stmts:      stmts,
+			start:      start,
+			txnControl: stmts[end],
+			next:       end + 1,
+		}
+	}
+	return nil
+}
+
+// checkProcedureTxnControl returns an error if the given statements of the
+// body of a procedure contain transaction control statements, and the CALL
+// statement cannot end its transaction.
+func checkProcedureTxnControl(
+	evalCtx *tree.EvalContext, call *procedureCall, stmts []tree.Statement,
+) error {
+	if evalCtx.TxnImplicit && call != nil && call.canEndTxn {
+		return nil
+	}
+	for _, stmt := range stmts {
+		if !isProcedureTxnControl(stmt) {
+			continue
+		}
+		if !evalCtx.TxnImplicit {
+			return pgerror.Newf(pgcode.InvalidTransactionTermination,
+				"invalid transaction termination: %s is not allowed in a procedure "+
+					"called from a transaction block", stmt.StatementTag())
+		}
+		return pgerror.Newf(pgcode.InvalidTransactionTermination,
+			"invalid transaction termination: %s is only allowed in a procedure "+
+				"called alone in a simple query", stmt.StatementTag())
+	}
+	return nil
+}
+
+// makeOutputRow constructs the row returned by the procedure from the result
+// of the last statement of its body. As in Postgres, the output arguments are
+// set from the first row of the result, or are NULL if there is no such row.
+func (n *callNode) makeOutputRow(
+	evalCtx *tree.EvalContext, rows []tree.Datums, cols colinfo.ResultColumns,
+) (tree.Datums, error) {
+	if len(cols) != len(n.columns) {
+		return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in procedure %s: body returns %d columns, expected %d",
+			n.proc.GetName(), len(cols), len(n.columns))
+	}
+	row := make(tree.Datums, len(n.columns))
+	for i := range row {
+		if len(rows) == 0 {
+			row[i] = tree.DNull
+			continue
+		}
+		d, err := tree.PerformAssignmentCast(evalCtx, rows[0][i], n.columns[i].Typ)
+		if err != nil {
+			return nil, err
+		}
+		row[i] = d
+	}
+	return row, nil
+}
+
+func (n *callNode) Next(params runParams) (bool, error) {
+	if n.run.done || len(n.columns) == 0 {
+		return false, nil
+	}
+	n.run.done = true
+	if n.run.row == nil {
+		n.run.row = make(tree.Datums, len(n.columns))
+		for i := range n.run.row {
+			n.run.row[i] = tree.DNull
+		}
+	}
+	return true, nil
+}
+
+func (n *callNode) Values() tree.Datums       { return n.run.row }
+func (n *callNode) Close(ctx context.Context) {}
+
+// procedureOutputColumns returns the columns of the row returned by a
+// procedure with the given arguments, one for each OUT or INOUT argument.
+// Unnamed output arguments are named after their position among the output
+// arguments.
+func procedureOutputColumns(args []descpb.FunctionDescriptor_Argument) colinfo.ResultColumns {
+	var cols colinfo.ResultColumns
+	for _, arg := range args {
+		if arg.Class == descpb.FunctionDescriptor_Argument_IN {
+			continue
+		}
+		name := arg.Name
+		if name == "" {
+			name = fmt.Sprintf("column%d", len(cols)+1)
+		}
+		cols = append(cols, colinfo.ResultColumn{Name: name, Typ: arg.Type})
+	}
+	return cols
+}
+
+// isProcedureTxnControl returns whether the given statement of the body of a
+// procedure is a COMMIT or ROLLBACK statement.
+func isProcedureTxnControl(stmt tree.Statement) bool {
+	switch stmt.(type) {
+	case *tree.CommitTransaction, *tree.RollbackTransaction:
+		return true
+	}
+	return false
+}
+
+// parseProcedureBody parses the body of a stored procedure, which consists of
+// zero or more statements. Transaction control statements other than COMMIT
+// and ROLLBACK are not allowed.
+func parseProcedureBody(body string) ([]tree.Statement, error) {
+	parsed, err := parser.Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	stmts := make([]tree.Statement, len(parsed))
+	for i := range parsed {
+		stmt := parsed[i].AST
+		switch stmt.(type) {
+		case *tree.BeginTransaction, *tree.SetTransaction, *tree.Savepoint,
+			*tree.ReleaseSavepoint, *tree.RollbackToSavepoint, *tree.PrepareTransaction,
+			*tree.CommitPrepared, *tree.RollbackPrepared:
+			return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"%s is not allowed in a procedure", stmt.StatementTag())
+		}
+		stmts[i] = stmt
+	}
+	return stmts, nil
+}
+
+// validateProcedureBody checks that the body of the given procedure can be
+// parsed, and that the body of a procedure with output arguments ends with a
+// statement which returns rows.
+func validateProcedureBody(fn *descpb.FunctionDescriptor) error {
+	stmts, err := parseProcedureBody(fn.FunctionBody)
+	if err != nil {
+		return errors.Wrap(err, "invalid procedure body")
+	}
+	for _, stmt := range stmts {
+		var visitErr error
+		tree.SimpleStmtVisit(stmt, func(expr tree.Expr) (bool, tree.Expr, error) {
+			if p, ok := expr.(*tree.Placeholder); ok && int(p.Idx) >= len(fn.Args) {
+				visitErr = pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", p)
+			}
+			return visitErr == nil, expr, nil
+		})
+		if visitErr != nil {
+			return visitErr
+		}
+	}
+	if len(procedureOutputColumns(fn.Args)) == 0 {
+		return nil
+	}
+	if len(stmts) == 0 || stmts[len(stmts)-1].StatementReturnType() != tree.Rows {
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"procedure with output parameters must end with a statement which returns rows")
+	}
+	return nil
+}
+
+// makeProcedureStatements returns the statements of the body of the given
+// procedure, with all references to the arguments of the procedure, either
+// by position or by name, replaced with the given argument values. Note that
+// this means that argument names shadow column names in the body.
+func makeProcedureStatements(
+	proc catalog.FunctionDescriptor, args tree.Datums,
+) ([]tree.Statement, error) {
+	stmts, err := parseProcedureBody(proc.GetFunctionBody())
+	if err != nil {
+		return nil, err
+	}
+	procArgs := proc.GetArgs()
+	argExpr := func(i int) tree.Expr {
+		return &tree.CastExpr{Expr: args[i], Type: procArgs[i].Type, SyntaxMode: tree.CastShort}
+	}
+	for i := range stmts {
+		var visitErr error
+		stmts[i], _ = tree.SimpleStmtVisit(stmts[i], func(expr tree.Expr) (bool, tree.Expr, error) {
+			if visitErr != nil {
+				return false, expr, nil
+			}
+			switch t := expr.(type) {
+			case *tree.Placeholder:
+				if int(t.Idx) >= len(args) {
+					visitErr = pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", t)
+					return false, expr, nil
+				}
+				return false, argExpr(int(t.Idx)), nil
+			case *tree.UnresolvedName:
+				if t.NumParts != 1 || t.Star {
+					return false, expr, nil
+				}
+				for j := range procArgs {
+					if procArgs[j].Name != "" && procArgs[j].Name == t.Parts[0] {
+						return false, argExpr(j), nil
+					}
+				}
+			}
+			return true, expr, nil
+		})
+		if visitErr != nil {
+			return nil, visitErr
+		}
+	}
+	return stmts, nil
+}
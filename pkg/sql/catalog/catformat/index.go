@@ -116,7 +116,8 @@ func indexForDisplay(
 // inaccessible computed column, the computed column expression is formatted.
 // Otherwise, the column name is formatted. Each column is separated by commas
 // and includes the direction of the index if the index is not an inverted
-// index.
+// index, or the operator class of the inverted column if it is not the
+// default one.
 func FormatIndexElements(
 	ctx context.Context,
 	table catalog.TableDescriptor,
@@ -148,6 +149,8 @@ func FormatIndexElements(
 		if index.Type != descpb.IndexDescriptor_INVERTED {
 			f.WriteByte(' ')
 			f.WriteString(index.KeyColumnDirections[i].String())
+		} else if i == n-1 && index.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+			f.WriteString(" gin_trgm_ops")
 		}
 	}
 	return nil
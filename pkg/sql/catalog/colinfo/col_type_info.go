@@ -98,15 +98,39 @@ func ValidateColumnDefType(t *types.T) error {
 			return unimplemented.NewWithIssueDetailf(23468, t.String(),
 				"arrays of JSON unsupported as column type")
 		}
+		if f := t.ArrayContents().Family(); f == types.TSQueryFamily || f == types.TSVectorFamily {
+			// Text search arrays are not supported as a column type.
+			return unimplemented.NewWithIssueDetailf(7821, t.String(),
+				"arrays of %s unsupported as column type", t.ArrayContents())
+		}
+		if t.ArrayContents().Family() == types.JsonpathFamily {
+			// Jsonpath arrays are not supported as a column type.
+			return unimplemented.NewWithIssueDetailf(22513, t.String(),
+				"arrays of jsonpath unsupported as column type")
+		}
 		if err := types.CheckArrayElementType(t.ArrayContents()); err != nil {
 			return err
 		}
 		return ValidateColumnDefType(t.ArrayContents())
 
+	case types.TupleFamily:
+		// Only composite types, which have a stable definition stored in a type
+		// descriptor, can be used as column types.
+		if !t.IsComposite() {
+			return pgerror.Newf(pgcode.InvalidTableDefinition,
+				"value type %s cannot be used for table columns", t.String())
+		}
+		for _, elem := range t.TupleContents() {
+			if err := ValidateColumnDefType(elem); err != nil {
+				return err
+			}
+		}
+
 	case types.BitFamily, types.IntFamily, types.FloatFamily, types.BoolFamily, types.BytesFamily, types.DateFamily,
 		types.INetFamily, types.IntervalFamily, types.JsonFamily, types.OidFamily, types.TimeFamily,
 		types.TimestampFamily, types.TimestampTZFamily, types.UuidFamily, types.TimeTZFamily,
-		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily:
+		types.GeographyFamily, types.GeometryFamily, types.EnumFamily, types.Box2DFamily,
+		types.TSQueryFamily, types.TSVectorFamily, types.JsonpathFamily, types.RangeFamily:
 		// These types are OK.
 
 	default:
@@ -135,7 +159,15 @@ func ColumnTypeIsInvertedIndexable(t *types.T) bool {
 	}
 	family := t.Family()
 	return family == types.JsonFamily || family == types.ArrayFamily ||
-		family == types.GeographyFamily || family == types.GeometryFamily
+		family == types.GeographyFamily || family == types.GeometryFamily ||
+		family == types.TSVectorFamily
+}
+
+// ColumnTypeIsTrigramIndexable returns whether the type t is valid to be
+// indexed using an inverted index on the trigrams of its values, which is
+// requested with the gin_trgm_ops operator class.
+func ColumnTypeIsTrigramIndexable(t *types.T) bool {
+	return t.Family() == types.StringFamily
 }
 
 // MustBeValueEncoded returns true if columns of the given kind can only be value
@@ -149,7 +181,8 @@ func MustBeValueEncoded(semanticType *types.T) bool {
 		default:
 			return MustBeValueEncoded(semanticType.ArrayContents())
 		}
-	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily:
+	case types.JsonFamily, types.TupleFamily, types.GeographyFamily, types.GeometryFamily,
+		types.TSQueryFamily, types.TSVectorFamily, types.JsonpathFamily:
 		return true
 	}
 	return false
@@ -0,0 +1,15 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package colinfo
+
+// TTLDefaultExpirationColumnName is the column name representing the
+// expiration of a row for tables with row-level TTL.
+const TTLDefaultExpirationColumnName = "crdb_internal_expiration"
@@ -14,6 +14,7 @@ package dbdesc
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -220,6 +221,84 @@ func (desc *immutable) GetNonDroppedSchemaName(schemaID descpb.ID) string {
 	return ""
 }
 
+// GetFunctionOverloads implements the DatabaseDescriptor interface.
+func (desc *immutable) GetFunctionOverloads(
+	name string,
+) []descpb.DatabaseDescriptor_FunctionOverload {
+	return desc.Functions[name].Overloads
+}
+
+// ForEachFunctionOverload implements the DatabaseDescriptor interface.
+func (desc *immutable) ForEachFunctionOverload(
+	f func(name string, overload descpb.DatabaseDescriptor_FunctionOverload) error,
+) error {
+	for name, info := range desc.Functions {
+		for _, o := range info.Overloads {
+			if err := f(name, o); err != nil {
+				if iterutil.Done(err) {
+					return nil
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) GetPublication(
+	name string,
+) (descpb.DatabaseDescriptor_PublicationInfo, bool) {
+	pub, ok := desc.Publications[name]
+	return pub, ok
+}
+
+// ForEachPublication implements the DatabaseDescriptor interface.
+func (desc *immutable) ForEachPublication(
+	f func(name string, pub descpb.DatabaseDescriptor_PublicationInfo) error,
+) error {
+	names := make([]string, 0, len(desc.Publications))
+	for name := range desc.Publications {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := f(name, desc.Publications[name]); err != nil {
+			if iterutil.Done(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// GetServer implements the DatabaseDescriptor interface.
+func (desc *immutable) GetServer(name string) (descpb.DatabaseDescriptor_ServerInfo, bool) {
+	server, ok := desc.Servers[name]
+	return server, ok
+}
+
+// ForEachServer implements the DatabaseDescriptor interface.
+func (desc *immutable) ForEachServer(
+	f func(name string, server descpb.DatabaseDescriptor_ServerInfo) error,
+) error {
+	names := make([]string, 0, len(desc.Servers))
+	for name := range desc.Servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := f(name, desc.Servers[name]); err != nil {
+			if iterutil.Done(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // ValidateSelf validates that the database descriptor is well formed.
 // Checks include validate the database name, and verifying that there
 // is at least one read and write user.
@@ -241,6 +320,18 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 	if desc.IsMultiRegion() {
 		desc.validateMultiRegion(vea)
 	}
+
+	for name, pub := range desc.Publications {
+		vea.Report(catalog.ValidateName(name, "publication"))
+		if pub.AllTables && len(pub.TableIDs) > 0 {
+			vea.Report(errors.AssertionFailedf(
+				"publication %q includes all tables but has table IDs", errors.Safe(name)))
+		}
+	}
+
+	for name := range desc.Servers {
+		vea.Report(catalog.ValidateName(name, "server"))
+	}
 }
 
 // validateMultiRegion performs checks specific to multi-region DBs.
@@ -265,6 +356,11 @@ func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
 	for _, schema := range desc.Schemas {
 		ids.Add(schema.ID)
 	}
+	for _, fn := range desc.Functions {
+		for _, o := range fn.Overloads {
+			ids.Add(o.ID)
+		}
+	}
 	return ids, nil
 }
 
@@ -316,6 +412,27 @@ func (desc *immutable) ValidateTxnCommit(
 			report(errors.Errorf("schema parentID is actually %d", schemaDesc.GetParentID()))
 		}
 	}
+
+	// Check function references.
+	for fnName, fnInfo := range desc.Functions {
+		for _, o := range fnInfo.Overloads {
+			report := func(err error) {
+				vea.Report(errors.Wrapf(err, "function mapping entry %q (%d)",
+					errors.Safe(fnName), o.ID))
+			}
+			fnDesc, err := vdg.GetFunctionDescriptor(o.ID)
+			if err != nil {
+				report(err)
+				continue
+			}
+			if fnDesc.GetName() != fnName {
+				report(errors.Errorf("function name is actually %q", errors.Safe(fnDesc.GetName())))
+			}
+			if fnDesc.GetParentID() != desc.GetID() {
+				report(errors.Errorf("function parentID is actually %d", fnDesc.GetParentID()))
+			}
+		}
+	}
 }
 
 // MaybeIncrementVersion implements the MutableDescriptor interface.
@@ -395,6 +512,71 @@ func (desc *Mutable) AddDrainingName(name descpb.NameInfo) {
 	desc.DrainingNames = append(desc.DrainingNames, name)
 }
 
+// AddFunction adds a mapping entry for the function with the given name, ID
+// and schema to the database's functions mapping.
+func (desc *Mutable) AddFunction(name string, id descpb.ID, schemaID descpb.ID) {
+	if desc.Functions == nil {
+		desc.Functions = make(map[string]descpb.DatabaseDescriptor_FunctionInfo)
+	}
+	info := desc.Functions[name]
+	info.Overloads = append(info.Overloads, descpb.DatabaseDescriptor_FunctionOverload{
+		ID:       id,
+		SchemaID: schemaID,
+	})
+	desc.Functions[name] = info
+}
+
+// RemoveFunction removes the mapping entry for the function with the given
+// name and ID from the database's functions mapping.
+func (desc *Mutable) RemoveFunction(name string, id descpb.ID) {
+	info, ok := desc.Functions[name]
+	if !ok {
+		return
+	}
+	overloads := info.Overloads[:0]
+	for _, o := range info.Overloads {
+		if o.ID != id {
+			overloads = append(overloads, o)
+		}
+	}
+	if len(overloads) == 0 {
+		delete(desc.Functions, name)
+		return
+	}
+	info.Overloads = overloads
+	desc.Functions[name] = info
+}
+
+// SetPublication adds or replaces the entry for the publication with the given
+// name in the database's publications mapping.
+func (desc *Mutable) SetPublication(name string, pub descpb.DatabaseDescriptor_PublicationInfo) {
+	if desc.Publications == nil {
+		desc.Publications = make(map[string]descpb.DatabaseDescriptor_PublicationInfo)
+	}
+	desc.Publications[name] = pub
+}
+
+// RemovePublication removes the entry for the publication with the given name
+// from the database's publications mapping.
+func (desc *Mutable) RemovePublication(name string) {
+	delete(desc.Publications, name)
+}
+
+// SetServer adds or replaces the entry for the foreign server with the given
+// name in the database's servers mapping.
+func (desc *Mutable) SetServer(name string, server descpb.DatabaseDescriptor_ServerInfo) {
+	if desc.Servers == nil {
+		desc.Servers = make(map[string]descpb.DatabaseDescriptor_ServerInfo)
+	}
+	desc.Servers[name] = server
+}
+
+// RemoveServer removes the entry for the foreign server with the given name
+// from the database's servers mapping.
+func (desc *Mutable) RemoveServer(name string) {
+	delete(desc.Servers, name)
+}
+
 // UnsetMultiRegionConfig removes the stored multi-region config from the
 // database descriptor.
 func (desc *Mutable) UnsetMultiRegionConfig() {
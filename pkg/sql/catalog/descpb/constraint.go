@@ -120,3 +120,36 @@ type ConstraintDetail struct {
 	// Only populated for Check Constraints.
 	CheckConstraint *TableDescriptor_CheckConstraint
 }
+
+// Deferrability returns the deferrability of the constraint. Only foreign key
+// constraints and unique constraints without an index can be deferrable.
+func (c *ConstraintDetail) Deferrability() tree.ConstraintDeferrability {
+	switch {
+	case c.FK != nil:
+		return c.FK.Deferrability()
+	case c.UniqueWithoutIndexConstraint != nil:
+		return c.UniqueWithoutIndexConstraint.Deferrability()
+	}
+	return tree.NotDeferrableConstraint
+}
+
+// Deferrability returns the deferrability of the foreign key constraint.
+func (fk *ForeignKeyConstraint) Deferrability() tree.ConstraintDeferrability {
+	return constraintDeferrability(fk.Deferrable, fk.InitiallyDeferred)
+}
+
+// Deferrability returns the deferrability of the unique constraint.
+func (u *UniqueWithoutIndexConstraint) Deferrability() tree.ConstraintDeferrability {
+	return constraintDeferrability(u.Deferrable, u.InitiallyDeferred)
+}
+
+func constraintDeferrability(deferrable, initiallyDeferred bool) tree.ConstraintDeferrability {
+	switch {
+	case !deferrable:
+		return tree.NotDeferrableConstraint
+	case initiallyDeferred:
+		return tree.DeferrableInitiallyDeferred
+	default:
+		return tree.DeferrableInitiallyImmediate
+	}
+}
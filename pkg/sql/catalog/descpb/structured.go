@@ -252,6 +252,11 @@ func (desc *TableDescriptor) MaterializedView() bool {
 	return desc.IsMaterializedView
 }
 
+// IsForeignTable implements the TableDescriptor interface.
+func (desc *TableDescriptor) IsForeignTable() bool {
+	return desc.ForeignTable != nil
+}
+
 // IsPhysicalTable implements the TableDescriptor interface.
 func (desc *TableDescriptor) IsPhysicalTable() bool {
 	return desc.IsSequence() || (desc.IsTable() && !desc.IsVirtualTable()) || desc.MaterializedView()
@@ -384,3 +389,15 @@ func (ni NameInfo) GetParentSchemaID() ID {
 func (ni NameInfo) GetName() string {
 	return ni.Name
 }
+
+// DefaultTTLDeletionCron is the default cron expression used to schedule
+// row-level TTL deletion jobs.
+const DefaultTTLDeletionCron = "@hourly"
+
+// DeletionCronOrDefault returns the DeletionCron or the global default.
+func (rowLevelTTL *TableDescriptor_RowLevelTTL) DeletionCronOrDefault() string {
+	if override := rowLevelTTL.DeletionCron; override != "" {
+		return override
+	}
+	return DefaultTTLDeletionCron
+}
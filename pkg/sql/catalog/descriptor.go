@@ -44,6 +44,9 @@ const (
 
 	// Schema is for schema descriptors.
 	Schema = "schema"
+
+	// Function is for function descriptors.
+	Function = "function"
 )
 
 // MutationPublicationFilter is used by MakeFirstMutationPublic to filter the
@@ -161,7 +164,7 @@ type Descriptor interface {
 	// different set of capabilities represented by the PrivilegeDescriptor.
 	GetPrivileges() *descpb.PrivilegeDescriptor
 	// DescriptorType returns the type of this descriptor (like relation, type,
-	// schema, database).
+	// schema, database, function).
 	DescriptorType() DescriptorType
 	// GetAuditMode returns the audit mode for this descriptor. The audit mode
 	// describes what kind of auditing (logging) actions should be taken when
@@ -236,6 +239,27 @@ type DatabaseDescriptor interface {
 	// GetDefaultPrivilegeDescriptor returns the default privileges for this
 	// database.
 	GetDefaultPrivilegeDescriptor() DefaultPrivilegeDescriptor
+	// GetFunctionOverloads returns the entries of the functions mapping for the
+	// given function name.
+	GetFunctionOverloads(name string) []descpb.DatabaseDescriptor_FunctionOverload
+	// ForEachFunctionOverload iterates f over each entry of the functions
+	// mapping.
+	// iterutil.StopIteration is supported.
+	ForEachFunctionOverload(f func(name string, overload descpb.DatabaseDescriptor_FunctionOverload) error) error
+	// GetPublication returns the entry of the publications mapping for the
+	// given publication name, if it exists.
+	GetPublication(name string) (descpb.DatabaseDescriptor_PublicationInfo, bool)
+	// ForEachPublication iterates f over each entry of the publications
+	// mapping, in order of name.
+	// iterutil.StopIteration is supported.
+	ForEachPublication(f func(name string, pub descpb.DatabaseDescriptor_PublicationInfo) error) error
+	// GetServer returns the entry of the servers mapping for the given foreign
+	// server name, if it exists.
+	GetServer(name string) (descpb.DatabaseDescriptor_ServerInfo, bool)
+	// ForEachServer iterates f over each entry of the servers mapping, in
+	// order of name.
+	// iterutil.StopIteration is supported.
+	ForEachServer(f func(name string, server descpb.DatabaseDescriptor_ServerInfo) error) error
 }
 
 // TableDescriptor is an interface around the table descriptor types.
@@ -274,6 +298,12 @@ type TableDescriptor interface {
 	// MaterializedView returns whether or not this TableDescriptor is a
 	// MaterializedView.
 	MaterializedView() bool
+	// IsForeignTable returns whether or not this TableDescriptor is a foreign
+	// table, whose rows are read from external storage.
+	IsForeignTable() bool
+	// GetForeignTable returns the description of the external data read by
+	// the table. Only valid if IsForeignTable is true.
+	GetForeignTable() *descpb.TableDescriptor_ForeignTable
 	// IsAs returns true if the TableDescriptor describes a Table that was created
 	// with a CREATE TABLE AS command.
 	IsAs() bool
@@ -595,6 +625,11 @@ type TableDescriptor interface {
 	// GetRegionalByRowTableRegionColumnName returns the region column name of a
 	// REGIONAL BY ROW table.
 	GetRegionalByRowTableRegionColumnName() (tree.Name, error)
+
+	// GetRowLevelTTL returns the row-level TTL config for the table.
+	GetRowLevelTTL() *descpb.TableDescriptor_RowLevelTTL
+	// HasRowLevelTTL returns whether the table has row level TTL configured.
+	HasRowLevelTTL() bool
 }
 
 // TypeDescriptor will eventually be called typedesc.Descriptor.
@@ -187,6 +187,12 @@ func (tc *Collection) HasUncommittedTypes() bool {
 // Satisfy the linter.
 var _ = (*Collection).HasUncommittedTypes
 
+// HasUncommittedDescriptors returns true if the Collection contains
+// descriptors which were created or modified in the transaction.
+func (tc *Collection) HasUncommittedDescriptors() bool {
+	return tc.uncommitted.hasUncommittedDescriptors()
+}
+
 // AddUncommittedDescriptor adds an uncommitted descriptor modified in the
 // transaction to the Collection. The descriptor must either be a new descriptor
 // or carry the original version or carry the subsequent version to the original
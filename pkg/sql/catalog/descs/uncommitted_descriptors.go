@@ -283,6 +283,16 @@ func (ud *uncommittedDescriptors) hasUncommittedTables() (has bool) {
 	return has
 }
 
+func (ud *uncommittedDescriptors) hasUncommittedDescriptors() (has bool) {
+	_ = ud.iterateImmutableByID(func(desc catalog.Descriptor) error {
+		if has = desc.IsUncommittedVersion(); has {
+			return iterutil.StopIteration()
+		}
+		return nil
+	})
+	return has
+}
+
 func (ud *uncommittedDescriptors) hasUncommittedTypes() (has bool) {
 	_ = ud.iterateImmutableByID(func(desc catalog.Descriptor) error {
 		if _, has = desc.(catalog.TypeDescriptor); has {
@@ -0,0 +1,406 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package funcdesc contains the concrete implementations of
+// catalog.FunctionDescriptor.
+package funcdesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/oidext"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/redact"
+	"github.com/lib/pq/oid"
+)
+
+var _ catalog.FunctionDescriptor = (*immutable)(nil)
+var _ catalog.FunctionDescriptor = (*Mutable)(nil)
+var _ catalog.MutableDescriptor = (*Mutable)(nil)
+
+// immutable wraps a Function descriptor and provides methods on it.
+type immutable struct {
+	descpb.FunctionDescriptor
+
+	// isUncommittedVersion is set to true if this descriptor was created from
+	// a copy of a Mutable with an uncommitted version.
+	isUncommittedVersion bool
+}
+
+// Mutable is a mutable reference to a FunctionDescriptor.
+type Mutable struct {
+	immutable
+
+	ClusterVersion *immutable
+
+	// changed represents whether or not the descriptor was changed
+	// after RunPostDeserializationChanges.
+	changed bool
+}
+
+// NewMutableFunctionDescriptor returns a Mutable for a new function with the
+// given properties.
+func NewMutableFunctionDescriptor(
+	id descpb.ID,
+	parentID descpb.ID,
+	parentSchemaID descpb.ID,
+	name string,
+	args []descpb.FunctionDescriptor_Argument,
+	returnType *types.T,
+	privs *descpb.PrivilegeDescriptor,
+) *Mutable {
+	return &Mutable{
+		immutable: immutable{
+			FunctionDescriptor: descpb.FunctionDescriptor{
+				Name:           name,
+				ID:             id,
+				ParentID:       parentID,
+				ParentSchemaID: parentSchemaID,
+				Args:           args,
+				ReturnType:     returnType,
+				Volatility:     descpb.FunctionDescriptor_VOLATILE,
+				Version:        1,
+				Privileges:     privs,
+			},
+		},
+	}
+}
+
+// SafeMessage makes immutable a SafeMessager.
+func (desc *immutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.immutable", desc)
+}
+
+// SafeMessage makes Mutable a SafeMessager.
+func (desc *Mutable) SafeMessage() string {
+	return formatSafeMessage("funcdesc.Mutable", desc)
+}
+
+func formatSafeMessage(typeName string, desc catalog.FunctionDescriptor) string {
+	var buf redact.StringBuilder
+	buf.Printf(typeName + ": {")
+	catalog.FormatSafeDescriptorProperties(&buf, desc)
+	buf.Printf("}")
+	return buf.String()
+}
+
+var _ redact.SafeMessager = (*immutable)(nil)
+
+// GetDrainingNames implements the Descriptor interface. Functions are not
+// present in the namespace table and therefore never have draining names.
+func (desc *immutable) GetDrainingNames() []descpb.NameInfo {
+	return nil
+}
+
+// SetDrainingNames implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) SetDrainingNames(names []descpb.NameInfo) {
+	if len(names) > 0 {
+		panic(errors.AssertionFailedf("functions cannot have draining names"))
+	}
+}
+
+// AddDrainingName implements the MutableDescriptor interface.
+//
+// Deprecated: Do not use.
+func (desc *Mutable) AddDrainingName(descpb.NameInfo) {
+	panic(errors.AssertionFailedf("functions cannot have draining names"))
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *immutable) IsUncommittedVersion() bool {
+	return desc.isUncommittedVersion
+}
+
+// GetAuditMode implements the DescriptorProto interface.
+func (desc *immutable) GetAuditMode() descpb.TableDescriptor_AuditMode {
+	return descpb.TableDescriptor_DISABLED
+}
+
+// DescriptorType implements the DescriptorProto interface.
+func (desc *immutable) DescriptorType() catalog.DescriptorType {
+	return catalog.Function
+}
+
+// FuncDesc implements the FunctionDescriptor interface.
+func (desc *immutable) FuncDesc() *descpb.FunctionDescriptor {
+	return &desc.FunctionDescriptor
+}
+
+// ArgTypes implements the FunctionDescriptor interface.
+func (desc *immutable) ArgTypes() []*types.T {
+	ret := make([]*types.T, len(desc.Args))
+	for i := range desc.Args {
+		ret[i] = desc.Args[i].Type
+	}
+	return ret
+}
+
+// Public implements the Descriptor interface.
+func (desc *immutable) Public() bool {
+	return desc.State == descpb.DescriptorState_PUBLIC
+}
+
+// Adding implements the Descriptor interface.
+func (desc *immutable) Adding() bool {
+	return false
+}
+
+// Offline implements the Descriptor interface.
+func (desc *immutable) Offline() bool {
+	return desc.State == descpb.DescriptorState_OFFLINE
+}
+
+// Dropped implements the Descriptor interface.
+func (desc *immutable) Dropped() bool {
+	return desc.State == descpb.DescriptorState_DROP
+}
+
+// DescriptorProto wraps a FunctionDescriptor in a Descriptor.
+func (desc *immutable) DescriptorProto() *descpb.Descriptor {
+	return &descpb.Descriptor{
+		Union: &descpb.Descriptor_Function{
+			Function: &desc.FunctionDescriptor,
+		},
+	}
+}
+
+// NewBuilder implements the catalog.Descriptor interface.
+func (desc *immutable) NewBuilder() catalog.DescriptorBuilder {
+	return NewBuilder(desc.FuncDesc())
+}
+
+// ValidateSelf implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
+	// Validate local properties of the descriptor.
+	vea.Report(catalog.ValidateName(desc.GetName(), "function"))
+	if desc.GetID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid function ID %d", desc.GetID()))
+	}
+	if desc.GetParentID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parent ID %d", desc.GetParentID()))
+	}
+	if desc.GetParentSchemaID() == descpb.InvalidID {
+		vea.Report(errors.AssertionFailedf("invalid parent schema ID %d", desc.GetParentSchemaID()))
+	}
+	for i := range desc.Args {
+		if desc.Args[i].Type == nil {
+			vea.Report(errors.AssertionFailedf("missing type for argument %d", errors.Safe(i)))
+		}
+		if !desc.IsProcedure && desc.Args[i].Class != descpb.FunctionDescriptor_Argument_IN {
+			vea.Report(errors.AssertionFailedf("output argument %d in function", errors.Safe(i)))
+		}
+	}
+	if desc.ReturnType == nil {
+		vea.Report(errors.AssertionFailedf("missing return type"))
+	}
+	// The body of a procedure may be empty.
+	if desc.FunctionBody == "" && !desc.IsProcedure {
+		vea.Report(errors.AssertionFailedf("missing function body"))
+	}
+
+	// Validate the privilege descriptor.
+	vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Function))
+}
+
+// GetReferencedDescIDs returns the IDs of all descriptors referenced by
+// this descriptor, including itself.
+func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
+	ids := catalog.MakeDescriptorIDSet(desc.GetID(), desc.GetParentID())
+	if desc.GetParentSchemaID() != keys.PublicSchemaID {
+		ids.Add(desc.GetParentSchemaID())
+	}
+	return ids, nil
+}
+
+// ValidateCrossReferences implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateCrossReferences(
+	vea catalog.ValidationErrorAccumulator, vdg catalog.ValidationDescGetter,
+) {
+	// Check the parent database reference.
+	db, err := vdg.GetDatabaseDescriptor(desc.GetParentID())
+	if err != nil {
+		vea.Report(err)
+		return
+	}
+
+	// Check the parent schema reference.
+	if desc.GetParentSchemaID() != keys.PublicSchemaID {
+		schema, err := vdg.GetSchemaDescriptor(desc.GetParentSchemaID())
+		if err != nil {
+			vea.Report(err)
+		} else if schema.GetParentID() != desc.GetParentID() {
+			vea.Report(errors.AssertionFailedf("parent schema %d is in different database %d",
+				desc.GetParentSchemaID(), schema.GetParentID()))
+		}
+	}
+
+	// Check that the parent database has the correct entry in its functions
+	// mapping.
+	for _, o := range db.GetFunctionOverloads(desc.GetName()) {
+		if o.ID == desc.GetID() {
+			if o.SchemaID != desc.GetParentSchemaID() {
+				vea.Report(errors.AssertionFailedf(
+					"present in parent database [%d] functions mapping but under schema [%d]",
+					desc.GetParentID(), o.SchemaID))
+			}
+			return
+		}
+	}
+	vea.Report(errors.AssertionFailedf("not present in parent database [%d] functions mapping",
+		desc.GetParentID()))
+}
+
+// ValidateTxnCommit implements the catalog.Descriptor interface.
+func (desc *immutable) ValidateTxnCommit(
+	_ catalog.ValidationErrorAccumulator, _ catalog.ValidationDescGetter,
+) {
+	// No-op.
+}
+
+// MaybeIncrementVersion implements the MutableDescriptor interface.
+func (desc *Mutable) MaybeIncrementVersion() {
+	// Already incremented, no-op.
+	if desc.ClusterVersion == nil || desc.Version == desc.ClusterVersion.Version+1 {
+		return
+	}
+	desc.Version++
+	desc.ModificationTime = hlc.Timestamp{}
+}
+
+// OriginalName implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalName() string {
+	if desc.ClusterVersion == nil {
+		return ""
+	}
+	return desc.ClusterVersion.Name
+}
+
+// OriginalID implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalID() descpb.ID {
+	if desc.ClusterVersion == nil {
+		return descpb.InvalidID
+	}
+	return desc.ClusterVersion.ID
+}
+
+// OriginalVersion implements the MutableDescriptor interface.
+func (desc *Mutable) OriginalVersion() descpb.DescriptorVersion {
+	if desc.ClusterVersion == nil {
+		return 0
+	}
+	return desc.ClusterVersion.Version
+}
+
+// ImmutableCopy implements the MutableDescriptor interface.
+func (desc *Mutable) ImmutableCopy() catalog.Descriptor {
+	imm := NewBuilder(desc.FuncDesc()).BuildImmutable()
+	imm.(*immutable).isUncommittedVersion = desc.IsUncommittedVersion()
+	return imm
+}
+
+// IsNew implements the MutableDescriptor interface.
+func (desc *Mutable) IsNew() bool {
+	return desc.ClusterVersion == nil
+}
+
+// SetPublic implements the MutableDescriptor interface.
+func (desc *Mutable) SetPublic() {
+	desc.State = descpb.DescriptorState_PUBLIC
+	desc.OfflineReason = ""
+}
+
+// SetDropped implements the MutableDescriptor interface.
+func (desc *Mutable) SetDropped() {
+	desc.State = descpb.DescriptorState_DROP
+	desc.OfflineReason = ""
+}
+
+// SetOffline implements the MutableDescriptor interface.
+func (desc *Mutable) SetOffline(reason string) {
+	desc.State = descpb.DescriptorState_OFFLINE
+	desc.OfflineReason = reason
+}
+
+// IsUncommittedVersion implements the Descriptor interface.
+func (desc *Mutable) IsUncommittedVersion() bool {
+	return desc.IsNew() || desc.GetVersion() != desc.ClusterVersion.GetVersion()
+}
+
+// HasPostDeserializationChanges returns if the MutableDescriptor was changed after running
+// RunPostDeserializationChanges.
+func (desc *Mutable) HasPostDeserializationChanges() bool {
+	return desc.changed
+}
+
+// ToTreeVolatility converts the volatility of a function descriptor to a
+// tree.Volatility. Since tree.Volatility has no notion of leakproof functions
+// which are not immutable, the leakproof marking is only taken into account
+// for immutable functions.
+func ToTreeVolatility(
+	v descpb.FunctionDescriptor_Volatility, leakProof bool,
+) (tree.Volatility, error) {
+	switch v {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		if leakProof {
+			return tree.VolatilityLeakProof, nil
+		}
+		return tree.VolatilityImmutable, nil
+	case descpb.FunctionDescriptor_STABLE:
+		return tree.VolatilityStable, nil
+	case descpb.FunctionDescriptor_VOLATILE:
+		return tree.VolatilityVolatile, nil
+	}
+	return 0, errors.AssertionFailedf("unknown volatility %s", v)
+}
+
+// FuncIDToOID converts a function descriptor ID into a function OID. As for
+// user-defined types, the OIDs of user-defined functions are offset so that
+// they do not collide with the OIDs of builtin objects.
+func FuncIDToOID(id descpb.ID) oid.Oid {
+	return oid.Oid(id) + oidext.CockroachPredefinedOIDMax
+}
+
+// OIDToFuncID converts the OID of a user-defined function into the ID of its
+// descriptor.
+func OIDToFuncID(o oid.Oid) descpb.ID {
+	return descpb.ID(o - oidext.CockroachPredefinedOIDMax)
+}
+
+// MakeOverload constructs the tree.Overload which is used to resolve and
+// type check calls to the given function.
+func MakeOverload(desc catalog.FunctionDescriptor) (tree.Overload, error) {
+	volatility, err := ToTreeVolatility(desc.GetVolatility(), desc.GetLeakProof())
+	if err != nil {
+		return tree.Overload{}, err
+	}
+	args := desc.GetArgs()
+	argTypes := make(tree.ArgTypes, len(args))
+	for i := range args {
+		argTypes[i].Name = args[i].Name
+		argTypes[i].Typ = args[i].Type
+	}
+	return tree.Overload{
+		Types:                  argTypes,
+		ReturnType:             tree.FixedReturnType(desc.GetReturnType()),
+		Volatility:             volatility,
+		Oid:                    FuncIDToOID(desc.GetID()),
+		IsUDF:                  true,
+		Body:                   desc.GetFunctionBody(),
+		ReturnsNullOnNullInput: desc.GetNullInputBehavior() != descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT,
+	}, nil
+}
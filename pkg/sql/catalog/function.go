@@ -0,0 +1,44 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package catalog
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// FunctionDescriptor is an interface around the function descriptor types.
+type FunctionDescriptor interface {
+	Descriptor
+
+	// FuncDesc returns the backing protobuf for this function.
+	FuncDesc() *descpb.FunctionDescriptor
+
+	// GetArgs returns the arguments of the function.
+	GetArgs() []descpb.FunctionDescriptor_Argument
+	// GetReturnType returns the type of the value returned by the function.
+	GetReturnType() *types.T
+	// GetVolatility returns the volatility of the function.
+	GetVolatility() descpb.FunctionDescriptor_Volatility
+	// GetLeakProof returns whether the function is leakproof.
+	GetLeakProof() bool
+	// GetNullInputBehavior returns how the function behaves when called with
+	// NULL arguments.
+	GetNullInputBehavior() descpb.FunctionDescriptor_NullInputBehavior
+	// GetLang returns the language the function body is written in.
+	GetLang() descpb.FunctionDescriptor_Language
+	// GetFunctionBody returns the body of the function.
+	GetFunctionBody() string
+	// GetIsProcedure returns whether the function is a procedure.
+	GetIsProcedure() bool
+	// ArgTypes returns the types of the arguments of the function.
+	ArgTypes() []*types.T
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package schemaexpr
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+)
+
+// DomainValueName is the name by which the CHECK constraints of a domain refer
+// to the value being checked.
+const DomainValueName = "value"
+
+// ValidateDomainCheckExpr verifies that an expression is a valid CHECK
+// constraint of a domain over the given base type. If the expression is valid,
+// it returns the serialized expression.
+//
+// A domain CHECK expression is valid if all of the following are true:
+//
+//   - It results in a boolean.
+//   - It refers to no columns other than VALUE.
+//   - It does not include subqueries.
+//   - It does not include non-immutable, aggregate, window, or set returning
+//     functions.
+//
+func ValidateDomainCheckExpr(
+	ctx context.Context, expr tree.Expr, baseType *types.T, semaCtx *tree.SemaContext,
+) (string, error) {
+	// Replace VALUE with a dummyColumn of the base type so that the expression
+	// can be type-checked.
+	replacedExpr, err := ReplaceDomainValue(expr, func() tree.Expr {
+		return &dummyColumn{typ: baseType, name: DomainValueName}
+	})
+	if err != nil {
+		return "", err
+	}
+	typedExpr, err := SanitizeVarFreeExpr(
+		ctx, replacedExpr, types.Bool, "domain CHECK", semaCtx, tree.VolatilityImmutable,
+	)
+	if err != nil {
+		return "", err
+	}
+	return tree.Serialize(typedExpr), nil
+}
+
+// ParseDomainCheckExpr parses the serialized CHECK constraint expression of a
+// domain, and replaces the references to VALUE in it with expressions created
+// by makeValue.
+func ParseDomainCheckExpr(checkExpr string, makeValue func() tree.Expr) (tree.Expr, error) {
+	expr, err := parser.ParseExpr(checkExpr)
+	if err != nil {
+		return nil, err
+	}
+	return ReplaceDomainValue(expr, makeValue)
+}
+
+// ReplaceDomainValue replaces the references to VALUE in the given domain
+// CHECK constraint expression with expressions created by makeValue.
+func ReplaceDomainValue(expr tree.Expr, makeValue func() tree.Expr) (tree.Expr, error) {
+	return tree.SimpleVisit(expr, func(expr tree.Expr) (recurse bool, newExpr tree.Expr, err error) {
+		if name, ok := expr.(*tree.UnresolvedName); ok &&
+			name.NumParts == 1 && name.Parts[0] == DomainValueName {
+			return false, makeValue(), nil
+		}
+		return true, expr, nil
+	})
+}
@@ -51,6 +51,8 @@ func ShouldSplitAtDesc(rawDesc *roachpb.Value) bool {
 		return false
 	case *descpb.Descriptor_Schema:
 		return false
+	case *descpb.Descriptor_Function:
+		return false
 	default:
 		panic(errors.AssertionFailedf("unexpected descriptor type %#v", &desc))
 	}
@@ -637,6 +639,29 @@ CREATE TABLE system.span_configurations (
     CONSTRAINT check_bounds CHECK (start_key < end_key),
     FAMILY "primary" (start_key, end_key, config)
 )`
+
+	NotificationsTableSchema = `
+CREATE TABLE system.notifications (
+    id           UUID NOT NULL,
+    created      TIMESTAMPTZ NOT NULL,
+    pid          INT4 NOT NULL,
+    channel      STRING NOT NULL,
+    payload      STRING NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (id),
+    FAMILY "primary" (id, created, pid, channel, payload)
+)`
+
+	PreparedTransactionsTableSchema = `
+CREATE TABLE system.prepared_transactions (
+    global_id        STRING NOT NULL,
+    transaction_id   UUID NOT NULL,
+    transaction_key  BYTES NULL,
+    prepared         TIMESTAMPTZ NULL,
+    owner            STRING NOT NULL,
+    database         STRING NOT NULL,
+    CONSTRAINT "primary" PRIMARY KEY (global_id),
+    FAMILY "primary" (global_id, transaction_id, transaction_key, prepared, owner, database)
+)`
 )
 
 func pk(name string) descpb.IndexDescriptor {
@@ -2270,6 +2295,62 @@ var (
 		},
 	)
 
+	// NotificationsTable is the descriptor for the notifications table, which
+	// holds the notifications sent with NOTIFY until they are delivered to the
+	// listening sessions.
+	NotificationsTable = registerSystemTable(
+		NotificationsTableSchema,
+		systemTable(
+			catconstants.NotificationsTableName,
+			keys.NotificationsTableID,
+			[]descpb.ColumnDescriptor{
+				{Name: "id", ID: 1, Type: types.Uuid, Nullable: false},
+				{Name: "created", ID: 2, Type: types.TimestampTZ, Nullable: false},
+				{Name: "pid", ID: 3, Type: types.Int4, Nullable: false},
+				{Name: "channel", ID: 4, Type: types.String, Nullable: false},
+				{Name: "payload", ID: 5, Type: types.String, Nullable: false},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:            "primary",
+					ID:              0,
+					ColumnNames:     []string{"id", "created", "pid", "channel", "payload"},
+					ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4, 5},
+					DefaultColumnID: 0,
+				},
+			},
+			pk("id"),
+		))
+
+	// PreparedTransactionsTable is the descriptor for the prepared_transactions
+	// table, which maps the global identifiers of the transactions prepared
+	// with PREPARE TRANSACTION to their transaction records. The prepared time
+	// is NULL until the transaction is known to be prepared.
+	PreparedTransactionsTable = registerSystemTable(
+		PreparedTransactionsTableSchema,
+		systemTable(
+			catconstants.PreparedTransactionsTableName,
+			keys.PreparedTransactionsTableID,
+			[]descpb.ColumnDescriptor{
+				{Name: "global_id", ID: 1, Type: types.String, Nullable: false},
+				{Name: "transaction_id", ID: 2, Type: types.Uuid, Nullable: false},
+				{Name: "transaction_key", ID: 3, Type: types.Bytes, Nullable: true},
+				{Name: "prepared", ID: 4, Type: types.TimestampTZ, Nullable: true},
+				{Name: "owner", ID: 5, Type: types.String, Nullable: false},
+				{Name: "database", ID: 6, Type: types.String, Nullable: false},
+			},
+			[]descpb.ColumnFamilyDescriptor{
+				{
+					Name:            "primary",
+					ID:              0,
+					ColumnNames:     []string{"global_id", "transaction_id", "transaction_key", "prepared", "owner", "database"},
+					ColumnIDs:       []descpb.ColumnID{1, 2, 3, 4, 5, 6},
+					DefaultColumnID: 0,
+				},
+			},
+			pk("global_id"),
+		))
+
 	// UnleasableSystemDescriptors contains the system descriptors which cannot
 	// be leased. This includes the lease table itself, among others.
 	UnleasableSystemDescriptors = func(s []catalog.Descriptor) map[descpb.ID]catalog.Descriptor {
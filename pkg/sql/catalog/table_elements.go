@@ -127,6 +127,7 @@ type Index interface {
 	GetPredicate() string
 	GetType() descpb.IndexDescriptor_Type
 	GetGeoConfig() geoindex.Config
+	GetInvertedColumnKind() descpb.IndexDescriptor_InvertedColumnKind
 	GetVersion() descpb.IndexDescriptorVersion
 	GetEncodingType() descpb.IndexDescriptorEncodingType
 
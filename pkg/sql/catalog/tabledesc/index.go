@@ -201,6 +201,12 @@ func (w index) GetGeoConfig() geoindex.Config {
 	return w.desc.GeoConfig
 }
 
+// GetInvertedColumnKind returns the kind of keys stored for the inverted
+// column of the index.
+func (w index) GetInvertedColumnKind() descpb.IndexDescriptor_InvertedColumnKind {
+	return w.desc.InvertedColumnKind
+}
+
 // GetSharded returns the ShardedDescriptor in the index descriptor
 func (w index) GetSharded() descpb.ShardedDescriptor {
 	return w.desc.Sharded
@@ -1031,14 +1031,36 @@ func checkColumnsValidForIndex(tableDesc *Mutable, indexColNames []string) error
 	return nil
 }
 
-func checkColumnsValidForInvertedIndex(tableDesc *Mutable, indexColNames []string) error {
+func checkColumnsValidForInvertedIndex(
+	tableDesc *Mutable, indexColNames []string, kind descpb.IndexDescriptor_InvertedColumnKind,
+) error {
 	lastCol := len(indexColNames) - 1
 	for i, indexCol := range indexColNames {
 		for _, col := range tableDesc.NonDropColumns() {
 			if col.GetName() == indexCol {
 				// The last column indexed by an inverted index must be
-				// inverted indexable.
-				if i == lastCol && !colinfo.ColumnTypeIsInvertedIndexable(col.GetType()) {
+				// inverted indexable with the kind of the index.
+				if i == lastCol && kind == descpb.IndexDescriptor_TRIGRAM &&
+					!colinfo.ColumnTypeIsTrigramIndexable(col.GetType()) {
+					return pgerror.Newf(
+						pgcode.DatatypeMismatch,
+						"operator class gin_trgm_ops does not accept data type %s",
+						col.GetType().Name(),
+					)
+				}
+				if i == lastCol && kind == descpb.IndexDescriptor_DEFAULT &&
+					colinfo.ColumnTypeIsTrigramIndexable(col.GetType()) {
+					return errors.WithHint(
+						pgerror.Newf(
+							pgcode.UndefinedObject,
+							"data type %s has no default operator class for an inverted index",
+							col.GetType().Name(),
+						),
+						"use the gin_trgm_ops operator class to index the trigrams of the column",
+					)
+				}
+				if i == lastCol && kind == descpb.IndexDescriptor_DEFAULT &&
+					!colinfo.ColumnTypeIsInvertedIndexable(col.GetType()) {
 					return errors.WithHint(
 						pgerror.Newf(
 							pgcode.FeatureNotSupported,
@@ -1126,7 +1148,7 @@ func (desc *Mutable) AddSecondaryIndex(idx descpb.IndexDescriptor) error {
 			return err
 		}
 	} else {
-		if err := checkColumnsValidForInvertedIndex(desc, idx.KeyColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, idx.KeyColumnNames, idx.InvertedColumnKind); err != nil {
 			return err
 		}
 	}
@@ -1725,6 +1747,11 @@ func (desc *Mutable) MakeMutationComplete(m descpb.DescriptorMutation) error {
 			// indexes with the new indexes that have been backfilled already.
 			desc.SetPrimaryIndex(t.MaterializedViewRefresh.NewPrimaryIndex)
 			desc.SetPublicNonPrimaryIndexes(t.MaterializedViewRefresh.NewIndexes)
+			// Remember when the new data was computed, if there is any.
+			desc.LastRefreshTime = hlc.Timestamp{}
+			if t.MaterializedViewRefresh.ShouldBackfill {
+				desc.LastRefreshTime = t.MaterializedViewRefresh.AsOf
+			}
 		}
 
 	case descpb.DescriptorMutation_DROP:
@@ -1977,7 +2004,7 @@ func (desc *Mutable) AddIndexMutation(
 			return err
 		}
 	case descpb.IndexDescriptor_INVERTED:
-		if err := checkColumnsValidForInvertedIndex(desc, idx.KeyColumnNames); err != nil {
+		if err := checkColumnsValidForInvertedIndex(desc, idx.KeyColumnNames, idx.InvertedColumnKind); err != nil {
 			return err
 		}
 	}
@@ -2344,6 +2371,11 @@ func (desc *wrapper) IsLocalityGlobal() bool {
 	return desc.LocalityConfig.GetGlobal() != nil
 }
 
+// HasRowLevelTTL implements the TableDescriptor interface.
+func (desc *wrapper) HasRowLevelTTL() bool {
+	return desc.RowLevelTTL != nil
+}
+
 // GetRegionalByTableRegion implements the TableDescriptor interface.
 func (desc *wrapper) GetRegionalByTableRegion() (descpb.RegionName, error) {
 	if !desc.IsLocalityRegionalByTable() {
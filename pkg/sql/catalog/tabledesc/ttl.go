@@ -0,0 +1,138 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tabledesc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+	"github.com/gorhill/cronexpr"
+)
+
+// ErrTTLNonAscendingPrimaryKey is returned when row-level TTL is used on a
+// table whose primary key has non-ascending columns.
+var ErrTTLNonAscendingPrimaryKey = unimplemented.New(
+	"ttl non-ascending primary key",
+	"non-ascending ordering on PRIMARY KEYs are not supported with row level TTL",
+)
+
+// ValidateRowLevelTTL validates that the TTL options are valid.
+func ValidateRowLevelTTL(ttl *descpb.TableDescriptor_RowLevelTTL) error {
+	if ttl == nil {
+		return nil
+	}
+	if ttl.DurationExpr == "" {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"ttl_expire_after" must be set`,
+		)
+	}
+	if ttl.SelectBatchSize != 0 {
+		if err := ValidateTTLBatchSize("ttl_select_batch_size", ttl.SelectBatchSize); err != nil {
+			return err
+		}
+	}
+	if ttl.DeleteBatchSize != 0 {
+		if err := ValidateTTLBatchSize("ttl_delete_batch_size", ttl.DeleteBatchSize); err != nil {
+			return err
+		}
+	}
+	if ttl.RangeConcurrency != 0 {
+		if err := ValidateTTLRangeConcurrency("ttl_range_concurrency", ttl.RangeConcurrency); err != nil {
+			return err
+		}
+	}
+	if ttl.DeleteRateLimit != 0 {
+		if err := ValidateTTLRateLimit("ttl_delete_rate_limit", ttl.DeleteRateLimit); err != nil {
+			return err
+		}
+	}
+	if ttl.DeletionCron != "" {
+		if err := ValidateTTLCronExpr("ttl_job_cron", ttl.DeletionCron); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateTTLBatchSize validates the batch size of a TTL.
+func ValidateTTLBatchSize(key string, val int64) error {
+	if val <= 0 {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"%s" must be at least 1`,
+			key,
+		)
+	}
+	return nil
+}
+
+// ValidateTTLRangeConcurrency validates the range concurrency of a TTL.
+func ValidateTTLRangeConcurrency(key string, val int64) error {
+	if val <= 0 {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"%s" must be at least 1`,
+			key,
+		)
+	}
+	return nil
+}
+
+// ValidateTTLRateLimit validates the rate limit parameters of a TTL.
+func ValidateTTLRateLimit(key string, val int64) error {
+	if val <= 0 {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`"%s" must be at least 1`,
+			key,
+		)
+	}
+	return nil
+}
+
+// ValidateTTLCronExpr validates the cron expression of TTL.
+func ValidateTTLCronExpr(key string, str string) error {
+	if _, err := cronexpr.Parse(str); err != nil {
+		return pgerror.Wrapf(
+			err,
+			pgcode.InvalidParameterValue,
+			`invalid cron expression for "%s"`,
+			key,
+		)
+	}
+	return nil
+}
+
+// validateRowLevelTTL validates the row-level TTL configuration of the
+// table against its columns and primary index.
+func (desc *wrapper) validateRowLevelTTL() error {
+	ttl := desc.GetRowLevelTTL()
+	if ttl == nil {
+		return nil
+	}
+	if err := ValidateRowLevelTTL(ttl); err != nil {
+		return err
+	}
+	pk := desc.GetPrimaryIndex()
+	for i := 0; i < pk.NumKeyColumns(); i++ {
+		if pk.GetKeyColumnDirection(i) != descpb.IndexDescriptor_ASC {
+			return ErrTTLNonAscendingPrimaryKey
+		}
+	}
+	if _, err := desc.FindColumnWithName(colinfo.TTLDefaultExpirationColumnName); err != nil {
+		return errors.Wrapf(err, "expected column %s", colinfo.TTLDefaultExpirationColumnName)
+	}
+	return nil
+}
@@ -139,6 +139,14 @@ func (desc *wrapper) ValidateCrossReferences(
 			}
 		}
 
+		// Validate that the server of a foreign table exists.
+		if ft := desc.GetForeignTable(); ft != nil {
+			if _, ok := dbDesc.GetServer(ft.Server); !ok {
+				vea.Report(errors.AssertionFailedf("foreign table references missing server %q",
+					errors.Safe(ft.Server)))
+			}
+		}
+
 		// Validate table locality.
 		if err := multiregion.ValidateTableLocalityConfig(desc, dbDesc, vdg); err != nil {
 			vea.Report(errors.Wrap(err, "invalid locality config"))
@@ -361,6 +369,8 @@ func (desc *wrapper) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			desc.validateUniqueWithoutIndexConstraints(columnIDs),
 			desc.validateTableIndexes(columnNames),
 			desc.validatePartitioning(),
+			desc.validateRowLevelTTL(),
+			desc.validateForeignTable(),
 		}
 		hasErrs := false
 		for _, err := range newErrs {
@@ -1135,3 +1145,22 @@ func (desc *wrapper) validatePartitioning() error {
 		)
 	})
 }
+
+// validateForeignTable validates that a foreign table references a server,
+// and has no secondary indexes, since its rows aren't stored.
+func (desc *wrapper) validateForeignTable() error {
+	ft := desc.GetForeignTable()
+	if ft == nil {
+		return nil
+	}
+	if !desc.IsTable() {
+		return errors.AssertionFailedf("foreign table %q is not a table", errors.Safe(desc.Name))
+	}
+	if ft.Server == "" {
+		return errors.AssertionFailedf("foreign table %q has no server", errors.Safe(desc.Name))
+	}
+	if len(desc.Indexes) > 0 {
+		return errors.AssertionFailedf("foreign table %q has secondary indexes", errors.Safe(desc.Name))
+	}
+	return nil
+}
@@ -100,6 +100,7 @@ var validationMap = []struct {
 				status: todoIAmKnowinglyAddingTechDebt,
 				reason: "initial import: TODO(features): add validation"},
 			"IsMaterializedView": {status: thisFieldReferencesNoObjects},
+			"LastRefreshTime":    {status: thisFieldReferencesNoObjects},
 			"DependsOn": {
 				status: todoIAmKnowinglyAddingTechDebt,
 				reason: "initial import: TODO(features): add validation"},
@@ -126,6 +127,8 @@ var validationMap = []struct {
 			"LocalityConfig":                {status: iSolemnlySwearThisFieldIsValidated},
 			"PartitionAllBy":                {status: iSolemnlySwearThisFieldIsValidated},
 			"NewSchemaChangeJobID":          {status: iSolemnlySwearThisFieldIsValidated},
+			"RowLevelTTL":                   {status: iSolemnlySwearThisFieldIsValidated},
+			"ForeignTable":                  {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -165,6 +168,7 @@ var validationMap = []struct {
 			"GeoConfig":                   {status: thisFieldReferencesNoObjects},
 			"Predicate":                   {status: iSolemnlySwearThisFieldIsValidated},
 			"UseDeletePreservingEncoding": {status: thisFieldReferencesNoObjects},
+			"InvertedColumnKind":          {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -213,16 +217,20 @@ var validationMap = []struct {
 			"OnDelete":          {status: thisFieldReferencesNoObjects},
 			"OnUpdate":          {status: thisFieldReferencesNoObjects},
 			"Match":             {status: thisFieldReferencesNoObjects},
+			"Deferrable":        {status: thisFieldReferencesNoObjects},
+			"InitiallyDeferred": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
 		obj: descpb.UniqueWithoutIndexConstraint{},
 		fieldMap: map[string]validationStatusInfo{
-			"TableID":   {status: iSolemnlySwearThisFieldIsValidated},
-			"ColumnIDs": {status: iSolemnlySwearThisFieldIsValidated},
-			"Name":      {status: thisFieldReferencesNoObjects},
-			"Validity":  {status: thisFieldReferencesNoObjects},
-			"Predicate": {status: iSolemnlySwearThisFieldIsValidated},
+			"TableID":           {status: iSolemnlySwearThisFieldIsValidated},
+			"ColumnIDs":         {status: iSolemnlySwearThisFieldIsValidated},
+			"Name":              {status: thisFieldReferencesNoObjects},
+			"Validity":          {status: thisFieldReferencesNoObjects},
+			"Predicate":         {status: iSolemnlySwearThisFieldIsValidated},
+			"Deferrable":        {status: thisFieldReferencesNoObjects},
+			"InitiallyDeferred": {status: thisFieldReferencesNoObjects},
 		},
 	},
 	{
@@ -244,6 +252,8 @@ var validationMap = []struct {
 			"Privileges":               {status: iSolemnlySwearThisFieldIsValidated},
 			"OfflineReason":            {status: thisFieldReferencesNoObjects},
 			"RegionConfig":             {status: iSolemnlySwearThisFieldIsValidated},
+			"DomainConfig":             {status: iSolemnlySwearThisFieldIsValidated},
+			"CompositeConfig":          {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -260,6 +270,9 @@ var validationMap = []struct {
 			"OfflineReason":     {status: thisFieldReferencesNoObjects},
 			"RegionConfig":      {status: iSolemnlySwearThisFieldIsValidated},
 			"DefaultPrivileges": {status: iSolemnlySwearThisFieldIsValidated},
+			"Functions":         {status: iSolemnlySwearThisFieldIsValidated},
+			"Publications":      {status: iSolemnlySwearThisFieldIsValidated},
+			"Servers":           {status: iSolemnlySwearThisFieldIsValidated},
 		},
 	},
 	{
@@ -91,6 +91,9 @@ type immutable struct {
 	physicalReps    [][]byte
 	readOnlyMembers []bool
 
+	// domainData is used to fill user defined type metadata for DOMAINs.
+	domainData *types.DomainMetadata
+
 	// isUncommittedVersion is set to true if this descriptor was created from
 	// a copy of a Mutable with an uncommitted version.
 	isUncommittedVersion bool
@@ -416,6 +419,67 @@ func (desc *Mutable) AddEnumValue(node *tree.AlterTypeAddValue) error {
 	return nil
 }
 
+// AddDomainCheck adds a CHECK constraint to the domain.
+// AddDomainCheck assumes that the type is a domain, and that no constraint
+// with the same name exists already.
+func (desc *Mutable) AddDomainCheck(name, expr string) {
+	desc.DomainConfig.Checks = append(desc.DomainConfig.Checks, descpb.TypeDescriptor_DomainConfig_Check{
+		Name: name,
+		Expr: expr,
+	})
+}
+
+// DropDomainCheck removes the CHECK constraint with the given name from the
+// domain. It returns false if the domain has no such constraint.
+func (desc *Mutable) DropDomainCheck(name string) bool {
+	checks := desc.DomainConfig.Checks
+	for i := range checks {
+		if checks[i].Name == name {
+			desc.DomainConfig.Checks = append(checks[:i:i], checks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AddCompositeAttribute adds an attribute with the given label and type to the
+// end of the composite type. AddCompositeAttribute assumes that the type is a
+// composite type, and that no attribute with the same label exists already.
+func (desc *Mutable) AddCompositeAttribute(label string, typ *types.T) {
+	desc.CompositeConfig.Elements = append(desc.CompositeConfig.Elements, descpb.TypeDescriptor_CompositeConfig_Element{
+		ElementType:  typ,
+		ElementLabel: label,
+	})
+}
+
+// DropCompositeAttribute removes the attribute with the given label from the
+// composite type. It returns false if the type has no such attribute.
+func (desc *Mutable) DropCompositeAttribute(label string) bool {
+	elems := desc.CompositeConfig.Elements
+	for i := range elems {
+		if elems[i].ElementLabel == label {
+			desc.CompositeConfig.Elements = append(elems[:i:i], elems[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetCompositeAttribute returns the attribute of the composite type with the
+// given label, or nil if the type has no such attribute. The returned
+// attribute can be modified in place.
+func (desc *Mutable) GetCompositeAttribute(
+	label string,
+) *descpb.TypeDescriptor_CompositeConfig_Element {
+	elems := desc.CompositeConfig.Elements
+	for i := range elems {
+		if elems[i].ElementLabel == label {
+			return &elems[i]
+		}
+	}
+	return nil
+}
+
 // AddReferencingDescriptorID adds a new referencing descriptor ID to the
 // TypeDescriptor. It ensures that duplicates are not added.
 func (desc *Mutable) AddReferencingDescriptorID(new descpb.ID) {
@@ -506,6 +570,18 @@ func (desc *immutable) ValidateSelf(vea catalog.ValidationErrorAccumulator) {
 			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
 		}
 		desc.validateEnumMembers(vea)
+	case descpb.TypeDescriptor_DOMAIN:
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Type))
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		desc.validateDomainConfig(vea)
+	case descpb.TypeDescriptor_COMPOSITE:
+		vea.Report(catprivilege.Validate(*desc.Privileges, desc, privilege.Type))
+		if desc.RegionConfig != nil {
+			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
+		}
+		desc.validateCompositeConfig(vea)
 	case descpb.TypeDescriptor_ALIAS:
 		if desc.RegionConfig != nil {
 			vea.Report(errors.AssertionFailedf("found region config on %s type desc", desc.Kind.String()))
@@ -565,6 +641,57 @@ func (desc *immutable) validateEnumMembers(vea catalog.ValidationErrorAccumulato
 	return isSorted
 }
 
+// validateDomainConfig performs domain base type and constraint checks.
+func (desc *immutable) validateDomainConfig(vea catalog.ValidationErrorAccumulator) {
+	if desc.DomainConfig == nil {
+		vea.Report(errors.AssertionFailedf("no domain config on %s type desc", desc.Kind.String()))
+		return
+	}
+	if desc.DomainConfig.BaseType == nil {
+		vea.Report(errors.AssertionFailedf("DOMAIN type desc has nil base type"))
+	} else if desc.DomainConfig.BaseType.UserDefined() {
+		vea.Report(errors.AssertionFailedf(
+			"DOMAIN type desc has user defined base type %d", desc.DomainConfig.BaseType.Oid()))
+	}
+	// Ensure there are no duplicate or empty constraint names.
+	names := make(map[string]struct{}, len(desc.DomainConfig.Checks))
+	for _, check := range desc.DomainConfig.Checks {
+		if check.Name == "" {
+			vea.Report(errors.AssertionFailedf("domain check constraint %q has no name", check.Expr))
+		}
+		if _, ok := names[check.Name]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate domain check constraint %q", check.Name))
+		}
+		names[check.Name] = struct{}{}
+	}
+}
+
+// validateCompositeConfig performs composite type attribute checks.
+func (desc *immutable) validateCompositeConfig(vea catalog.ValidationErrorAccumulator) {
+	if desc.CompositeConfig == nil {
+		vea.Report(errors.AssertionFailedf("no composite config on %s type desc", desc.Kind.String()))
+		return
+	}
+	// Ensure there are no duplicate or empty attribute labels, and that the
+	// attribute types are valid.
+	labels := make(map[string]struct{}, len(desc.CompositeConfig.Elements))
+	for _, elem := range desc.CompositeConfig.Elements {
+		if elem.ElementLabel == "" {
+			vea.Report(errors.AssertionFailedf("composite type attribute has no label"))
+		}
+		if _, ok := labels[elem.ElementLabel]; ok {
+			vea.Report(errors.AssertionFailedf("duplicate composite type attribute %q", elem.ElementLabel))
+		}
+		labels[elem.ElementLabel] = struct{}{}
+		if elem.ElementType == nil {
+			vea.Report(errors.AssertionFailedf("composite type attribute %q has nil type", elem.ElementLabel))
+		} else if elem.ElementType.UserDefined() {
+			vea.Report(errors.AssertionFailedf(
+				"composite type attribute %q has user defined type %d", elem.ElementLabel, elem.ElementType.Oid()))
+		}
+	}
+}
+
 // GetReferencedDescIDs returns the IDs of all descriptors referenced by
 // this descriptor, including itself.
 func (desc *immutable) GetReferencedDescIDs() (catalog.DescriptorIDSet, error) {
@@ -609,7 +736,8 @@ func (desc *immutable) ValidateCrossReferences(
 
 	// Validate that the referenced types exist.
 	switch desc.GetKind() {
-	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
+	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM,
+		descpb.TypeDescriptor_DOMAIN, descpb.TypeDescriptor_COMPOSITE:
 		// Ensure that the referenced array type exists.
 		if _, err := vdg.GetTypeDescriptor(desc.GetArrayTypeID()); err != nil {
 			vea.Report(errors.Wrapf(err, "arrayTypeID %d does not exist for %q", desc.GetArrayTypeID(), desc.GetKind()))
@@ -724,6 +852,19 @@ func (desc *immutable) MakeTypesT(
 			return nil, err
 		}
 		return typ, nil
+	case descpb.TypeDescriptor_DOMAIN:
+		typ := types.MakeDomain(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), desc.DomainConfig.BaseType)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents, labels := desc.compositeContentsAndLabels()
+		typ := types.MakeComposite(TypeIDToOID(desc.GetID()), TypeIDToOID(desc.ArrayTypeID), contents, labels)
+		if err := desc.HydrateTypeInfoWithName(ctx, typ, name, res); err != nil {
+			return nil, err
+		}
+		return typ, nil
 	case descpb.TypeDescriptor_ALIAS:
 		// Hydrate the alias and return it.
 		if err := desc.HydrateTypeInfoWithName(ctx, desc.Alias, name, res); err != nil {
@@ -735,6 +876,19 @@ func (desc *immutable) MakeTypesT(
 	}
 }
 
+// compositeContentsAndLabels returns the types and labels of the attributes
+// of a composite type descriptor.
+func (desc *immutable) compositeContentsAndLabels() ([]*types.T, []string) {
+	elems := desc.CompositeConfig.Elements
+	contents := make([]*types.T, len(elems))
+	labels := make([]string, len(elems))
+	for i := range elems {
+		contents[i] = elems[i].ElementType
+		labels[i] = elems[i].ElementLabel
+	}
+	return contents, labels
+}
+
 // EnsureTypeIsHydrated makes sure that t is a fully-hydrated type.
 func EnsureTypeIsHydrated(
 	ctx context.Context, t *types.T, res catalog.TypeDescriptorResolver,
@@ -755,7 +909,7 @@ func EnsureTypeIsHydrated(
 		}
 		return elemTypDesc.HydrateTypeInfoWithName(ctx, t, &elemTypName, res)
 	}
-	if t.Family() == types.TupleFamily {
+	if t.Family() == types.TupleFamily && !t.IsComposite() {
 		for _, typ := range t.TupleContents() {
 			if err := maybeHydrateType(ctx, typ, res); err != nil {
 				return err
@@ -813,6 +967,20 @@ func (desc *immutable) HydrateTypeInfoWithName(
 			IsMemberReadOnly:        desc.readOnlyMembers,
 		}
 		return nil
+	case descpb.TypeDescriptor_DOMAIN:
+		if !typ.IsDomain() {
+			return errors.New("cannot hydrate a non-domain type with a domain type descriptor")
+		}
+		typ.TypeMeta.DomainData = desc.domainData
+		return nil
+	case descpb.TypeDescriptor_COMPOSITE:
+		if !typ.IsComposite() {
+			return errors.New("cannot hydrate a non-composite type with a composite type descriptor")
+		}
+		// The attributes of the type may have been renamed since typ was
+		// constructed, so they are refreshed from the descriptor.
+		typ.InternalType.TupleContents, typ.InternalType.TupleLabels = desc.compositeContentsAndLabels()
+		return nil
 	case descpb.TypeDescriptor_ALIAS:
 		if typ.UserDefined() {
 			switch typ.Family() {
@@ -974,6 +1142,14 @@ func GetTypeDescriptorClosure(typ *types.T) (map[descpb.ID]struct{}, error) {
 				ret[id] = struct{}{}
 			}
 		}
+		// Composite types also have an array type.
+		if typ.IsComposite() {
+			id, err := GetUserDefinedArrayTypeDescID(typ)
+			if err != nil {
+				return nil, err
+			}
+			ret[id] = struct{}{}
+		}
 	default:
 		// Otherwise, take the array type ID.
 		id, err := GetUserDefinedArrayTypeDescID(typ)
@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
 )
 
@@ -131,6 +132,18 @@ func makeImmutable(desc *descpb.TypeDescriptor) immutable {
 			immutDesc.readOnlyMembers[i] =
 				member.Capability == descpb.TypeDescriptor_EnumMember_READ_ONLY
 		}
+	case descpb.TypeDescriptor_DOMAIN:
+		if config := desc.DomainConfig; config != nil {
+			immutDesc.domainData = &types.DomainMetadata{
+				NotNull:    config.NotNull,
+				CheckNames: make([]string, len(config.Checks)),
+				CheckExprs: make([]string, len(config.Checks)),
+			}
+			for i := range config.Checks {
+				immutDesc.domainData.CheckNames[i] = config.Checks[i].Name
+				immutDesc.domainData.CheckExprs[i] = config.Checks[i].Expr
+			}
+		}
 	}
 
 	return immutDesc
@@ -590,6 +590,77 @@ func TestValidateTypeDesc(t *testing.T) {
 				Privileges:     defaultPrivileges,
 			},
 		},
+		{
+			`no domain config on DOMAIN type desc`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_DOMAIN,
+				Privileges:     defaultPrivileges,
+			},
+		},
+		{
+			`DOMAIN type desc has nil base type`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_DOMAIN,
+				DomainConfig:   &descpb.TypeDescriptor_DomainConfig{},
+				Privileges:     defaultPrivileges,
+			},
+		},
+		{
+			`duplicate domain check constraint "c"`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_DOMAIN,
+				DomainConfig: &descpb.TypeDescriptor_DomainConfig{
+					BaseType: types.Int,
+					Checks: []descpb.TypeDescriptor_DomainConfig_Check{
+						{Name: "c", Expr: "value > 0"},
+						{Name: "c", Expr: "value < 10"},
+					},
+				},
+				Privileges: defaultPrivileges,
+			},
+		},
+		{
+			`no composite config on COMPOSITE type desc`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_COMPOSITE,
+				ArrayTypeID:    104,
+				Privileges:     defaultPrivileges,
+			},
+		},
+		{
+			`duplicate composite type attribute "a"`,
+			descpb.TypeDescriptor{
+				Name:           "t",
+				ID:             typeDescID,
+				ParentID:       100,
+				ParentSchemaID: keys.PublicSchemaID,
+				Kind:           descpb.TypeDescriptor_COMPOSITE,
+				ArrayTypeID:    104,
+				CompositeConfig: &descpb.TypeDescriptor_CompositeConfig{
+					Elements: []descpb.TypeDescriptor_CompositeConfig_Element{
+						{ElementType: types.Int, ElementLabel: "a"},
+						{ElementType: types.String, ElementLabel: "a"},
+					},
+				},
+				Privileges: defaultPrivileges,
+			},
+		},
 		{
 			`referenced database ID 500: descriptor not found`,
 			descpb.TypeDescriptor{
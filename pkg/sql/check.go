@@ -27,7 +27,9 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/errors"
@@ -461,14 +463,19 @@ func checkMutationInput(
 
 	checks := tabDesc.ActiveChecks()
 	colIdx := 0
-	for i := range checks {
-		if !checkOrds.Contains(i) {
-			continue
-		}
-
+	for i, ok := checkOrds.Next(0); ok; i, ok = checkOrds.Next(i + 1) {
 		if res, err := tree.GetBool(checkVals[colIdx]); err != nil {
 			return err
 		} else if !res && checkVals[colIdx] != tree.DNull {
+			if i >= len(checks) {
+				// The check was synthesized from the type of a column, which is only
+				// done after the active checks.
+				synthesized, err := synthesizeChecks(tabDesc)
+				if err != nil {
+					return err
+				}
+				return synthesized[i-len(checks)].violationError()
+			}
 			// Failed to satisfy CHECK constraint, so unwrap the serialized
 			// check expression to display to the user.
 			expr, err := schemaexpr.FormatExprForDisplay(
@@ -487,3 +494,81 @@ func checkMutationInput(
 	}
 	return nil
 }
+
+// synthesizedCheck is a check constraint which is not stored in a table
+// descriptor, but is synthesized from the user defined type of a column.
+type synthesizedCheck struct {
+	// expr is the check expression, which refers to the column by name.
+	expr tree.Expr
+	// domain is the type of the column if the check enforces a constraint of a
+	// domain, and nil otherwise.
+	domain *types.T
+	// domainConstraint is the name of the domain CHECK constraint enforced by
+	// the check. It is empty if the check enforces NOT NULL for the domain.
+	domainConstraint string
+}
+
+// violationError returns the error reported when a value does not satisfy the
+// check.
+func (c *synthesizedCheck) violationError() error {
+	switch {
+	case c.domain == nil:
+		return pgerror.Newf(pgcode.CheckViolation, "failed to satisfy CHECK constraint (%s)", c.expr)
+	case c.domainConstraint == "":
+		return sqlerrors.NewDomainNotNullViolationError(c.domain.Name())
+	default:
+		return sqlerrors.NewDomainCheckViolationError(c.domain.Name(), c.domainConstraint)
+	}
+}
+
+// synthesizeChecks returns the check constraints synthesized for the user
+// defined types of the public columns of the given table. The optimizer
+// enforces these checks after the active checks of the table.
+func synthesizeChecks(desc catalog.TableDescriptor) ([]synthesizedCheck, error) {
+	var checks []synthesizedCheck
+	for _, col := range desc.PublicColumns() {
+		colType := col.GetType()
+		if !colType.UserDefined() {
+			continue
+		}
+		colName := col.ColName()
+		makeColumnItem := func() tree.Expr { return &tree.ColumnItem{ColumnName: colName} }
+		switch {
+		case colType.Family() == types.EnumFamily:
+			// We synthesize an (x IN (v1, v2, v3...)) check for enum types.
+			checks = append(checks, synthesizedCheck{
+				expr: &tree.ComparisonExpr{
+					Operator: tree.MakeComparisonOperator(tree.In),
+					Left:     makeColumnItem(),
+					Right:    tree.NewDTuple(colType, tree.MakeAllDEnumsInType(colType)...),
+				},
+			})
+		case colType.IsDomain():
+			// We synthesize an (x IS NOT NULL) check for domains that do not allow
+			// NULL values, and a check for each CHECK constraint of the domain, in
+			// which VALUE is replaced by x.
+			domainData := colType.TypeMeta.DomainData
+			if domainData == nil {
+				return nil, errors.AssertionFailedf("domain type %s is not hydrated", colType.SQLString())
+			}
+			if domainData.NotNull {
+				checks = append(checks, synthesizedCheck{
+					expr:   &tree.IsNotNullExpr{Expr: makeColumnItem()},
+					domain: colType,
+				})
+			}
+			for i := range domainData.CheckExprs {
+				expr, err := schemaexpr.ParseDomainCheckExpr(domainData.CheckExprs[i], makeColumnItem)
+				if err != nil {
+					return nil, err
+				}
+				checks = append(checks, synthesizedCheck{
+					expr:             expr,
+					domain:           colType,
+					domainConstraint: domainData.CheckNames[i],
+				})
+			}
+		}
+	}
+	return checks, nil
+}
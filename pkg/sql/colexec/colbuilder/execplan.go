@@ -315,6 +315,7 @@ func canWrap(mode sessiondatapb.VectorizeExecMode, spec *execinfrapb.ProcessorSp
 	case spec.Core.Filterer != nil:
 	case spec.Core.StreamIngestionData != nil:
 	case spec.Core.StreamIngestionFrontier != nil:
+	case spec.Core.ForeignScan != nil:
 	default:
 		return errors.AssertionFailedf("unexpected processor core %q", spec.Core)
 	}
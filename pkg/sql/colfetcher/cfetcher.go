@@ -24,6 +24,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -194,6 +195,8 @@ func (m colIdxMap) Swap(i, j int) {
 }
 
 type cFetcherArgs struct {
+	// st is used to check whether the cluster version allows shared locks.
+	st *cluster.Settings
 	// lockStrength represents the row-level locking mode to use when fetching
 	// rows.
 	lockStrength descpb.ScanLockingStrength
@@ -624,6 +627,7 @@ func (rf *cFetcher) StartScan(
 		rf.reverse,
 		batchBytesLimit,
 		firstBatchLimit,
+		rf.st,
 		rf.lockStrength,
 		rf.lockWaitPolicy,
 		rf.lockTimeout,
@@ -865,6 +865,13 @@ func (s *Server) newConnExecutor(
 	ex.transitionCtx.sessionTracing = &ex.sessionTracing
 
 	ex.extraTxnState.hasAdminRoleCache = HasAdminRoleCache{}
+	ex.extraTxnState.sqlCursors.savepoints = &ex.extraTxnState.savepoints
+	ex.extraTxnState.sqlListeners.registry = s.cfg.NotificationRegistry
+	if sender, ok := clientComm.(NotificationSender); ok {
+		ex.extraTxnState.sqlListeners.sender = sender
+	}
+	nodeID, _ := s.cfg.NodeID.OptionalNodeID()
+	ex.extraTxnState.advisoryLocks.init(s.cfg.DB, s.cfg.Codec, s.cfg.Settings, nodeID)
 
 	ex.initPlanner(ctx, &ex.planner)
 
@@ -927,6 +934,7 @@ func (s *Server) newConnExecutorWithTxn(
 		txn.ReadTimestamp().GoTime(),
 		nil, /* historicalTimestamp */
 		roachpb.UnspecifiedUserPriority,
+		kv.Serializable,
 		tree.ReadWrite,
 		txn,
 		ex.transitionCtx)
@@ -1055,7 +1063,12 @@ func (ex *connExecutor) close(ctx context.Context, closeType closeType) {
 			ctx, &ex.extraTxnState.prepStmtsNamespaceMemAcc,
 		)
 		ex.extraTxnState.prepStmtsNamespaceMemAcc.Close(ctx)
+		ex.extraTxnState.sqlCursors.closeAll(ctx)
+		ex.extraTxnState.sqlListeners.close()
 	}
+	// The advisory locks are released even if the session panicked, since
+	// their transactions would otherwise be heartbeated forever.
+	ex.extraTxnState.advisoryLocks.close(ctx)
 
 	if ex.sessionTracing.Enabled() {
 		if err := ex.sessionTracing.StopTracing(); err != nil {
@@ -1232,6 +1245,23 @@ type connExecutor struct {
 		// connExecutor's closure.
 		prepStmtsNamespaceMemAcc mon.BoundAccount
 
+		// sqlCursors contains the cursors declared in the session. Cursors are
+		// closed when the transaction that declared them finishes, except for
+		// WITH HOLD cursors, which remain open after the transaction commits.
+		sqlCursors cursorMap
+
+		// sqlListeners contains the channels the session listens on. LISTEN and
+		// UNLISTEN take effect when the transaction commits.
+		sqlListeners notificationListener
+
+		// advisoryLocks contains the advisory locks held by the session.
+		// Transaction-level locks are released when the transaction finishes.
+		advisoryLocks advisoryLocks
+
+		// deferredConstraints tracks the SET CONSTRAINTS modes and the deferred
+		// constraints that must be validated before the transaction commits.
+		deferredConstraints deferredConstraints
+
 		// shouldExecuteOnTxnFinish indicates that ex.onTxnFinish will be called
 		// when txn is finished (either committed or aborted). It is true when
 		// txn is started but can remain false when txn is executed within
@@ -1379,6 +1409,11 @@ type connExecutor struct {
 	// any. This is printed by high-level panic recovery.
 	curStmtAST tree.Statement
 
+	// procedureCall tracks the procedure called by the current statement if it
+	// ended its transaction, in which case the statement is executed again to
+	// run the rest of the procedure in a new transaction.
+	procedureCall procedureCall
+
 	sessionID ClusterWideID
 
 	// activated determines whether activate() was called already.
@@ -1538,6 +1573,13 @@ func (ex *connExecutor) resetExtraTxnState(ctx context.Context, ev txnEvent) err
 		delete(ex.extraTxnState.prepStmtsNamespace.portals, name)
 	}
 
+	// Close the cursors declared in the transaction, other than WITH HOLD
+	// cursors if the transaction committed.
+	ex.extraTxnState.sqlCursors.onTxnFinish(ctx, ev == txnCommit)
+	ex.extraTxnState.sqlListeners.onTxnFinish(ev == txnCommit)
+	ex.extraTxnState.advisoryLocks.onTxnFinish(ctx)
+	ex.extraTxnState.deferredConstraints.onTxnFinish(ev == txnRestart)
+
 	switch ev {
 	case txnCommit, txnRollback:
 		for name, p := range ex.extraTxnState.prepStmtsNamespaceAtTxnRewindPos.portals {
@@ -1665,6 +1707,7 @@ func (ex *connExecutor) run(
 	ex.onCancelSession = onCancel
 
 	ex.sessionID = ex.generateID()
+	ex.extraTxnState.advisoryLocks.sessionID = ex.sessionID
 	ex.server.cfg.SessionRegistry.register(ex.sessionID, ex)
 	ex.planner.extendedEvalCtx.setSessionID(ex.sessionID)
 	defer ex.server.cfg.SessionRegistry.deregister(ex.sessionID)
@@ -1720,6 +1763,10 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 			ex.machine.CurState(), pos, cmd)
 	}
 
+	// Only a statement sent alone in a simple query may end its transaction
+	// from a procedure; portals are closed with the transaction.
+	ex.procedureCall.canEndTxn = false
+
 	var ev fsm.Event
 	var payload fsm.EventPayload
 	var res ResultBase
@@ -1737,6 +1784,7 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 				return nil
 			}
 			ex.curStmtAST = tcmd.AST
+			ex.procedureCall.canEndTxn = !tcmd.MultiStatement
 
 			stmtRes := ex.clientComm.CreateStatementResult(
 				tcmd.AST,
@@ -1880,6 +1928,22 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+	case CopyOut:
+		copyRes := ex.clientComm.CreateCopyOutResult(
+			pos,
+			ex.sessionData().DataConversionConfig,
+			ex.sessionData().GetLocation(),
+		)
+		res = copyRes
+		ev, payload = ex.execCopyOut(ctx, tcmd, copyRes)
+	case StartReplication:
+		replRes := ex.clientComm.CreateReplicationResult(
+			pos,
+			ex.sessionData().DataConversionConfig,
+			ex.sessionData().GetLocation(),
+		)
+		res = replRes
+		ev, payload = ex.execStartReplication(ctx, tcmd, replRes)
 	case DrainRequest:
 		// We received a drain request. We terminate immediately if we're not in a
 		// transaction. If we are in a transaction, we'll finish as soon as a Sync
@@ -1913,6 +1977,14 @@ func (ex *connExecutor) execCmd(ctx context.Context) error {
 		}
 	}
 
+	// A procedure called by the statement may have ended its transaction, in
+	// which case the statement is executed again to run the rest of the
+	// procedure in a new transaction.
+	if ex.procedureCall.inProgress() {
+		advInfo.code = ex.procedureCall.advance(
+			advInfo.code, payloadHasError(payload) || res.Err() != nil)
+	}
+
 	// Decide if we need to close the result or not. We don't need to do it if
 	// we're staying in place or rewinding - the statement will be executed
 	// again.
@@ -2075,6 +2147,10 @@ func (ex *connExecutor) updateTxnRewindPosMaybe(
 				canAdvance = true
 			case CopyIn:
 				// Can't advance.
+			case CopyOut:
+				// Can't advance.
+			case StartReplication:
+				// Can't advance.
 			case DrainRequest:
 				canAdvance = true
 			case Flush:
@@ -2164,47 +2240,8 @@ func (ex *connExecutor) execCopyIn(
 		return ev, payload, nil
 	}
 
-	// If we're in an explicit txn, then the copying will be done within that
-	// txn. Otherwise, we tell the copyMachine to manage its own transactions
-	// and give it a closure to reset the accumulated extraTxnState.
-	var txnOpt copyTxnOpt
-	if isOpen {
-		txnOpt = copyTxnOpt{
-			txn:           ex.state.mu.txn,
-			txnTimestamp:  ex.state.sqlTimestamp,
-			stmtTimestamp: ex.server.cfg.Clock.PhysicalTime(),
-		}
-	} else {
-		txnOpt = copyTxnOpt{
-			resetExtraTxnState: func(ctx context.Context) error {
-				return ex.resetExtraTxnState(ctx, noEvent)
-			},
-		}
-	}
-
-	var monToStop *mon.BytesMonitor
-	defer func() {
-		if monToStop != nil {
-			monToStop.Stop(ctx)
-		}
-	}()
-	if isNoTxn {
-		// HACK: We're reaching inside ex.state and starting the monitor. Normally
-		// that's driven by the state machine, but we're bypassing the state machine
-		// here.
-		ex.state.mon.Start(ctx, ex.sessionMon, mon.BoundAccount{} /* reserved */)
-		monToStop = ex.state.mon
-	}
-	txnOpt.resetPlanner = func(ctx context.Context, p *planner, txn *kv.Txn, txnTS time.Time, stmtTS time.Time) {
-		// HACK: We're reaching inside ex.state and changing sqlTimestamp by hand.
-		// It is used by resetPlanner. Normally sqlTimestamp is updated by the
-		// state machine, but the copyMachine manages its own transactions without
-		// going through the state machine.
-		ex.state.sqlTimestamp = txnTS
-		ex.statsCollector.Reset(ex.applicationStats, ex.phaseTimes)
-		ex.initPlanner(ctx, p)
-		ex.resetPlanner(ctx, p, txn, stmtTS)
-	}
+	txnOpt, cleanup := ex.makeCopyTxnOpt(ctx, isOpen)
+	defer cleanup(ctx)
 	var cm copyMachineInterface
 	var err error
 	if isCopyToExternalStorage(cmd) {
@@ -2239,6 +2276,96 @@ func (ex *connExecutor) execCopyIn(
 	return nil, nil, nil
 }
 
+// execCopyOut handles the CopyTo statement. Unlike execCopyIn, the
+// pgwire.conn keeps control of the network connection: the rows are streamed
+// to the client through res.
+func (ex *connExecutor) execCopyOut(
+	ctx context.Context, cmd CopyOut, res CopyOutResult,
+) (fsm.Event, fsm.EventPayload) {
+	state := ex.machine.CurState()
+	_, isNoTxn := state.(stateNoTxn)
+	_, isOpen := state.(stateOpen)
+	if !isNoTxn && !isOpen {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{
+			err: sqlerrors.NewTransactionAbortedError("" /* customMsg */)}
+		return ev, payload
+	}
+	if isOpen {
+		// Make the writes of the previous statements in the transaction visible,
+		// like the sequence point established before executing any statement.
+		if err := ex.state.mu.txn.Step(ctx); err != nil {
+			ev := eventNonRetriableErr{IsCommit: fsm.False}
+			payload := eventNonRetriableErrPayload{err: err}
+			return ev, payload
+		}
+	}
+
+	txnOpt, cleanup := ex.makeCopyTxnOpt(ctx, isOpen)
+	defer cleanup(ctx)
+	cm := newCopyOutMachine(
+		cmd.Stmt, txnOpt, ex.server.cfg,
+		// execPlan
+		func(ctx context.Context, p *planner, res RestrictedCommandResult) error {
+			distributePlan := getPlanDistribution(
+				ctx, p, p.execCfg.NodeID, ex.sessionData().DistSQLMode, p.curPlan.main,
+			)
+			_, err := ex.execWithDistSQLEngine(
+				ctx, p, tree.Rows, res, distributePlan.WillDistribute(), nil, /* progressAtomic */
+			)
+			return err
+		},
+	)
+	if err := cm.run(ctx, res); err != nil {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	return nil, nil
+}
+
+// makeCopyTxnOpt returns the copyTxnOpt for a COPY statement. If we're in an
+// explicit txn, then the copying will be done within that txn. Otherwise, we
+// tell the copy machine to manage its own transactions and give it a closure
+// to reset the accumulated extraTxnState. The returned cleanup function needs
+// to be called once the copying is done.
+func (ex *connExecutor) makeCopyTxnOpt(
+	ctx context.Context, isOpen bool,
+) (_ copyTxnOpt, cleanup func(context.Context)) {
+	var txnOpt copyTxnOpt
+	cleanup = func(context.Context) {}
+	if isOpen {
+		txnOpt = copyTxnOpt{
+			txn:           ex.state.mu.txn,
+			txnTimestamp:  ex.state.sqlTimestamp,
+			stmtTimestamp: ex.server.cfg.Clock.PhysicalTime(),
+		}
+	} else {
+		txnOpt = copyTxnOpt{
+			resetExtraTxnState: func(ctx context.Context) error {
+				return ex.resetExtraTxnState(ctx, noEvent)
+			},
+		}
+		// HACK: We're reaching inside ex.state and starting the monitor. Normally
+		// that's driven by the state machine, but we're bypassing the state machine
+		// here.
+		ex.state.mon.Start(ctx, ex.sessionMon, mon.BoundAccount{} /* reserved */)
+		monToStop := ex.state.mon
+		cleanup = monToStop.Stop
+	}
+	txnOpt.resetPlanner = func(ctx context.Context, p *planner, txn *kv.Txn, txnTS time.Time, stmtTS time.Time) {
+		// HACK: We're reaching inside ex.state and changing sqlTimestamp by hand.
+		// It is used by resetPlanner. Normally sqlTimestamp is updated by the
+		// state machine, but the copy machine manages its own transactions without
+		// going through the state machine.
+		ex.state.sqlTimestamp = txnTS
+		ex.statsCollector.Reset(ex.applicationStats, ex.phaseTimes)
+		ex.initPlanner(ctx, p)
+		ex.resetPlanner(ctx, p, txn, stmtTS)
+	}
+	return txnOpt, cleanup
+}
+
 // stmtHasNoData returns true if describing a result of the input statement
 // type should return NoData.
 func stmtHasNoData(stmt tree.Statement) bool {
@@ -2289,10 +2416,14 @@ func (ex *connExecutor) getRewindTxnCapability() (rewindCapability, bool) {
 	}, true
 }
 
-// isCommit returns true if stmt is a "COMMIT" statement.
+// isCommit returns true if stmt is a "COMMIT" statement. PREPARE TRANSACTION
+// counts as a commit, since the transaction is finished by it.
 func isCommit(stmt tree.Statement) bool {
-	_, ok := stmt.(*tree.CommitTransaction)
-	return ok
+	switch stmt.(type) {
+	case *tree.CommitTransaction, *tree.PrepareTransaction:
+		return true
+	}
+	return false
 }
 
 var retriableMinTimestampBoundUnsatisfiableError = errors.Newf(
@@ -2379,9 +2510,10 @@ func (ex *connExecutor) setTransactionModes(
 			return err
 		}
 	}
-	if modes.Isolation != tree.UnspecifiedIsolation && modes.Isolation != tree.SerializableIsolation {
-		return errors.AssertionFailedf(
-			"unknown isolation level: %s", errors.Safe(modes.Isolation))
+	if modes.Isolation != tree.UnspecifiedIsolation {
+		if err := ex.state.setIsolationLevel(ex.txnIsolationLevelToKV(modes.Isolation)); err != nil {
+			return err
+		}
 	}
 	rwMode := modes.ReadWriteMode
 	if modes.AsOf.Expr != nil && asOfTs.IsEmpty() {
@@ -2423,6 +2555,33 @@ func (ex *connExecutor) txnPriorityWithSessionDefault(mode tree.UserPriority) ro
 	return txnPriorityToProto(mode)
 }
 
+// txnIsolationLevelToKV maps a SQL isolation level to the isolation level of
+// the KV transaction. READ COMMITTED is upgraded to SERIALIZABLE unless it is
+// enabled through the sql.txn.read_committed_isolation.enabled cluster setting.
+func (ex *connExecutor) txnIsolationLevelToKV(level tree.IsolationLevel) kv.IsolationLevel {
+	switch level {
+	case tree.UnspecifiedIsolation, tree.SerializableIsolation:
+		return kv.Serializable
+	case tree.ReadCommittedIsolation:
+		if readCommittedIsolationEnabled.Get(&ex.server.cfg.Settings.SV) {
+			return kv.ReadCommitted
+		}
+		return kv.Serializable
+	default:
+		log.Fatalf(context.Background(), "unknown isolation level: %s", level)
+	}
+	return kv.Serializable
+}
+
+func (ex *connExecutor) txnIsolationLevelWithSessionDefault(
+	level tree.IsolationLevel,
+) kv.IsolationLevel {
+	if level == tree.UnspecifiedIsolation {
+		level = tree.IsolationLevel(ex.sessionData().DefaultTxnIsolationLevel)
+	}
+	return ex.txnIsolationLevelToKV(level)
+}
+
 func (ex *connExecutor) readWriteModeWithSessionDefault(
 	mode tree.ReadWriteMode,
 ) tree.ReadWriteMode {
@@ -2477,6 +2636,7 @@ func (ex *connExecutor) initEvalCtx(ctx context.Context, evalCtx *extendedEvalCo
 			Tenant:                    p,
 			Regions:                   p,
 			JoinTokenCreator:          p,
+			AdvisoryLocker:            p,
 			PreparedStatementState:    &ex.extraTxnState.prepStmtsNamespace,
 			SessionDataStack:          ex.sessionDataStack,
 			Settings:                  ex.server.cfg.Settings,
@@ -2579,6 +2739,15 @@ func (ex *connExecutor) initPlanner(ctx context.Context, p *planner) {
 	p.sessionDataMutatorIterator = ex.dataMutatorIterator
 	p.noticeSender = nil
 	p.preparedStatements = ex.getPrepStmtsAccessor()
+	p.sqlCursors = &ex.extraTxnState.sqlCursors
+	p.sqlListeners = &ex.extraTxnState.sqlListeners
+	// Statements run by internal executors check all constraints immediately,
+	// since the transaction may be committed by someone else.
+	if ex.executorType != executorTypeInternal {
+		p.deferredConstraints = &ex.extraTxnState.deferredConstraints
+		p.advisoryLocks = &ex.extraTxnState.advisoryLocks
+		p.procedureCall = &ex.procedureCall
+	}
 
 	p.queryCacheSession.Init()
 	p.optPlanningCtx.init(p)
@@ -2907,6 +3076,7 @@ func (ex *connExecutor) serialize() serverpb.Session {
 		ActiveQueries:              activeQueries,
 		ActiveTxn:                  activeTxnInfo,
 		LastActiveQuery:            lastActiveQuery,
+		AdvisoryLocks:              ex.extraTxnState.advisoryLocks.serialize(),
 		ID:                         ex.sessionID.GetBytes(),
 		AllocBytes:                 ex.mon.AllocBytes(),
 		MaxAllocBytes:              ex.mon.MaximumBytes(),
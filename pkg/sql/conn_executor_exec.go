@@ -154,7 +154,7 @@ func (ex *connExecutor) execStmt(
 	if ex.sessionData().IdleInTransactionSessionTimeout > 0 {
 		startIdleInTransactionSessionTimeout := func() {
 			switch ast.(type) {
-			case *tree.CommitTransaction, *tree.RollbackTransaction:
+			case *tree.CommitTransaction, *tree.RollbackTransaction, *tree.PrepareTransaction:
 				// Do nothing, the transaction is completed, we do not want to start
 				// an idle timer.
 			default:
@@ -491,6 +491,12 @@ func (ex *connExecutor) execStmtInOpenState(
 			return
 		}
 		if os.ImplicitTxn.Get() {
+			if ex.procedureCall.rollback() {
+				// The procedure called by the statement ended its transaction with
+				// ROLLBACK.
+				retEv, retPayload = ex.rollbackSQLTransaction(ctx, ast)
+				return
+			}
 			retEv, retPayload = ex.handleAutoCommit(ctx, ast)
 			return
 		}
@@ -512,6 +518,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		ev, payload := ex.rollbackSQLTransaction(ctx, s)
 		return ev, payload, nil
 
+	case *tree.PrepareTransaction:
+		// PrepareTransaction is executed fully here; there's no plan for it. The
+		// transaction is finished from the point of view of the session.
+		ev, payload := ex.commitSQLTransaction(ctx, ast, ex.prepareSQLTransactionInternal)
+		return ev, payload, nil
+
 	case *tree.Savepoint:
 		return ex.execSavepointInOpenState(ctx, s, res)
 
@@ -697,7 +709,12 @@ func (ex *connExecutor) execStmtInOpenState(
 		ctx, stmtThresholdSpan = createRootOrChildSpan(ctx, "trace-stmt-threshold", ex.transitionCtx.tracer, tracing.WithRecording(tracing.RecordingVerbose))
 	}
 
-	if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+	dispatch := ex.dispatchToExecutionEngine
+	if !os.ImplicitTxn.Get() && ex.executorType == executorTypeExec &&
+		ex.state.mu.txn.IsolationLevel() == kv.ReadCommitted {
+		dispatch = ex.dispatchReadCommittedStmtToExecutionEngine
+	}
+	if err := dispatch(ctx, p, res); err != nil {
 		stmtThresholdSpan.Finish()
 		return nil, nil, err
 	}
@@ -860,6 +877,14 @@ func (ex *connExecutor) reportSessionDataChanges(fn func() error) error {
 func (ex *connExecutor) commitSQLTransactionInternal(
 	ctx context.Context, ast tree.Statement,
 ) error {
+	// Validate the deferred constraints violated by statements of the
+	// transaction.
+	if err := ex.extraTxnState.deferredConstraints.validatePending(
+		ctx, ex.server.cfg.InternalExecutor, ex.state.mu.txn, &ex.extraTxnState.descCollection, true, /* all */
+	); err != nil {
+		return err
+	}
+
 	if err := ex.createJobs(ctx); err != nil {
 		return err
 	}
@@ -878,6 +903,12 @@ func (ex *connExecutor) commitSQLTransactionInternal(
 		return err
 	}
 
+	// Materialize the WITH HOLD cursors declared in the transaction, since they
+	// can no longer read from it once it commits.
+	if err := ex.extraTxnState.sqlCursors.prepareForCommit(ctx, ex.sessionMon); err != nil {
+		return err
+	}
+
 	if err := ex.state.mu.txn.Commit(ctx); err != nil {
 		return err
 	}
@@ -927,6 +958,74 @@ func (ex *connExecutor) rollbackSQLTransaction(
 	return eventTxnFinishAborted{}, nil
 }
 
+// dispatchReadCommittedStmtToExecutionEngine is like dispatchToExecutionEngine,
+// but it is used for statements of explicit READ COMMITTED transactions. Each
+// execution of the statement is wrapped in kv.Txn.BeginStatement/EndStatement,
+// so that it reads from a fresh snapshot. If the statement encounters a
+// retryable error that only required the statement itself to be rolled back
+// (i.e. the transaction kept its epoch), the statement is transparently
+// executed again, as long as none of its results have been sent to the client.
+// Otherwise, the error is escalated to a restart of the whole transaction.
+func (ex *connExecutor) dispatchReadCommittedStmtToExecutionEngine(
+	ctx context.Context, p *planner, res RestrictedCommandResult,
+) error {
+	txn := ex.state.mu.txn
+	maxRetries := int(readCommittedMaxStmtRetries.Get(&ex.server.cfg.Settings.SV))
+	for attempt := 0; ; attempt++ {
+		txnID, epoch := txn.ID(), txn.Epoch()
+		if err := txn.BeginStatement(ctx); err != nil {
+			res.SetError(err)
+			return nil
+		}
+		if err := ex.dispatchToExecutionEngine(ctx, p, res); err != nil {
+			return err
+		}
+		if err := txn.EndStatement(ctx); err != nil && res.Err() == nil {
+			res.SetError(err)
+		}
+
+		var retryErr *roachpb.TransactionRetryWithProtoRefreshError
+		if !errors.As(res.Err(), &retryErr) ||
+			retryErr.TxnID != txnID || txn.ID() != txnID || txn.Epoch() != epoch {
+			// Either the statement succeeded, it failed with a non-retryable
+			// error, or the transaction was restarted by the KV layer.
+			return nil
+		}
+		if attempt < maxRetries && !tree.CanModifySchema(p.stmt.AST) && ex.rewindStmtResults(ctx, res) {
+			log.VEventf(ctx, 2, "retrying READ COMMITTED statement after: %v", retryErr)
+			continue
+		}
+		// The statement cannot be retried on its own. Restart the transaction,
+		// which will be retried by the client or by the connExecutor's automatic
+		// retry mechanism.
+		txn.ManualRestart(ctx, ex.server.cfg.Clock.Now())
+		return nil
+	}
+}
+
+// rewindStmtResults discards the results produced so far for the statement
+// currently being executed, so that it can be executed again. Returns false if
+// some of these results have already been delivered to the client, or if the
+// result does not support retries, in which case the statement cannot be
+// retried.
+func (ex *connExecutor) rewindStmtResults(ctx context.Context, res RestrictedCommandResult) bool {
+	_, pos, err := ex.stmtBuf.CurCmd()
+	if err != nil {
+		return false
+	}
+	cl := ex.clientComm.LockCommunication()
+	defer cl.Close()
+	if cl.ClientPos() >= pos {
+		return false
+	}
+	if err := res.ResetForStatementRetry(); err != nil {
+		log.VEventf(ctx, 2, "unable to retry READ COMMITTED statement: %v", err)
+		return false
+	}
+	cl.RTrim(ctx, pos)
+	return true
+}
+
 // dispatchToExecutionEngine executes the statement, writes the result to res
 // and returns an event for the connection's state machine.
 //
@@ -1489,12 +1588,14 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventStartExplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(s.Modes.UserPriority),
+				ex.txnIsolationLevelWithSessionDefault(s.Modes.Isolation),
 				mode,
 				sqlTs,
 				historicalTs,
 				ex.transitionCtx)
 	case *tree.CommitTransaction, *tree.ReleaseSavepoint,
-		*tree.RollbackTransaction, *tree.SetTransaction, *tree.Savepoint:
+		*tree.RollbackTransaction, *tree.SetTransaction, *tree.Savepoint,
+		*tree.PrepareTransaction:
 		return ex.makeErrEvent(errNoTransactionInProgress, ast)
 	default:
 		// NB: Implicit transactions are created with the session's default
@@ -1509,6 +1610,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 		return eventStartImplicitTxn,
 			makeEventTxnStartPayload(
 				ex.txnPriorityWithSessionDefault(tree.UnspecifiedUserPriority),
+				ex.txnIsolationLevelWithSessionDefault(tree.UnspecifiedIsolation),
 				mode,
 				sqlTs,
 				historicalTs,
@@ -1518,7 +1620,7 @@ func (ex *connExecutor) execStmtInNoTxnState(
 
 // execStmtInAbortedState executes a statement in a txn that's in state
 // Aborted or RestartWait. All statements result in error events except:
-// - COMMIT / ROLLBACK: aborts the current transaction.
+// - COMMIT / ROLLBACK / PREPARE TRANSACTION: aborts the current transaction.
 // - ROLLBACK TO SAVEPOINT / SAVEPOINT: reopens the current transaction,
 //   allowing it to be retried.
 func (ex *connExecutor) execStmtInAbortedState(
@@ -1540,9 +1642,10 @@ func (ex *connExecutor) execStmtInAbortedState(
 	}
 
 	switch s := ast.(type) {
-	case *tree.CommitTransaction, *tree.RollbackTransaction:
-		if _, ok := s.(*tree.CommitTransaction); ok {
-			// Note: Postgres replies to COMMIT of failed txn with "ROLLBACK" too.
+	case *tree.CommitTransaction, *tree.RollbackTransaction, *tree.PrepareTransaction:
+		if _, ok := s.(*tree.RollbackTransaction); !ok {
+			// Note: Postgres replies to COMMIT or PREPARE TRANSACTION of failed txn
+			// with "ROLLBACK" too.
 			res.ResetStmtType((*tree.RollbackTransaction)(nil))
 		}
 		return ex.rollbackSQLTransaction(ctx, s)
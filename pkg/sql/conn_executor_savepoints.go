@@ -131,6 +131,7 @@ func (ex *connExecutor) execRelease(
 	// Discard our savepoint and all further ones. Depending on what happens with
 	// the release below, we might add this savepoint back.
 	env.popToIdx(idx - 1)
+	ex.extraTxnState.sqlCursors.onReleaseSavepoint(idx)
 
 	// Pop all the savepoint SessionData objects, and then an extra element.
 	// We will restore the currSessionData on to the stack, as releasing still
@@ -207,6 +208,7 @@ func (ex *connExecutor) execRollbackToSavepointInOpenState(
 	if err := ex.popSavepointsToIdx(s, idx); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.sqlCursors.onRollbackToSavepoint(ctx, idx)
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
@@ -287,6 +289,7 @@ func (ex *connExecutor) execRollbackToSavepointInAbortedState(
 	if err := ex.state.mu.txn.RollbackToSavepoint(ctx, entry.kvToken); err != nil {
 		return ex.makeErrEvent(err, s)
 	}
+	ex.extraTxnState.sqlCursors.onRollbackToSavepoint(ctx, idx)
 
 	if entry.kvToken.Initial() {
 		return eventTxnRestart{}, nil
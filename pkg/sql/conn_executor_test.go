@@ -1392,6 +1392,77 @@ func TestInjectRetryErrors(t *testing.T) {
 	})
 }
 
+// TestReadCommittedStatementRetry verifies that a statement of a READ COMMITTED
+// transaction that encounters a retryable error is retried transparently,
+// without restarting the transaction or losing its previous writes.
+func TestReadCommittedStatementRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	filter := newDynamicRequestFilter()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			Store: &kvserver.StoreTestingKnobs{
+				TestingRequestFilter: filter.filter,
+			},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+	defer db.Close()
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, "SET CLUSTER SETTING sql.txn.read_committed_isolation.enabled = true")
+	sqlDB.Exec(t, "CREATE TABLE kv (k INT PRIMARY KEY, v INT)")
+	sqlDB.Exec(t, "INSERT INTO kv VALUES (1, 1)")
+	var tableID uint32
+	sqlDB.QueryRow(t, "SELECT 'kv'::regclass::oid").Scan(&tableID)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "BEGIN TRANSACTION ISOLATION LEVEL READ COMMITTED")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO kv VALUES (2, 2)")
+	require.NoError(t, err)
+
+	// Inject a retryable error into the first write of the next statement.
+	var injected int32
+	filter.setFilter(func(ctx context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+		if ba.Txn == nil {
+			return nil
+		}
+		req, ok := ba.GetArg(roachpb.Put)
+		if !ok {
+			return nil
+		}
+		_, id, err := keys.SystemSQLCodec.DecodeTablePrefix(req.Header().Key)
+		if err != nil || id != tableID || !atomic.CompareAndSwapInt32(&injected, 0, 1) {
+			return nil
+		}
+		return roachpb.NewErrorWithTxn(
+			roachpb.NewTransactionRetryError(roachpb.RETRY_REASON_UNKNOWN, "boom"), ba.Txn)
+	})
+	_, err = conn.ExecContext(ctx, "UPDATE kv SET v = v + 1 WHERE k = 1")
+	require.NoError(t, err)
+	filter.setFilter(nil)
+	require.Equal(t, int32(1), atomic.LoadInt32(&injected))
+
+	// Only the statement was retried. A restart of the transaction would have
+	// returned an error to the client, since the results of its first statement
+	// have already been delivered.
+	_, err = conn.ExecContext(ctx, "COMMIT")
+	require.NoError(t, err)
+
+	sqlDB.CheckQueryResults(t, "SELECT k, v FROM kv ORDER BY k", [][]string{{"1", "2"}, {"2", "2"}})
+	var stmtRetries int
+	sqlDB.QueryRow(t,
+		"SELECT value FROM crdb_internal.node_metrics WHERE name = 'txn.statement_retries'",
+	).Scan(&stmtRetries)
+	require.Equal(t, 1, stmtRetries)
+}
+
 // dynamicRequestFilter exposes a filter method which is a
 // kvserverbase.ReplicaRequestFilter but can be set dynamically.
 type dynamicRequestFilter struct {
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/col/coldata"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -136,6 +137,9 @@ type ExecStmt struct {
 	// stats reporting.
 	ParseStart time.Time
 	ParseEnd   time.Time
+	// MultiStatement is set if the statement was sent in a query string
+	// together with other statements.
+	MultiStatement bool
 }
 
 // command implements the Command interface.
@@ -332,6 +336,42 @@ func (CopyIn) String() string {
 
 var _ Command = CopyIn{}
 
+// CopyOut is the command for execution of the Copy-out pgwire subprotocol.
+type CopyOut struct {
+	Stmt *tree.CopyTo
+}
+
+// command implements the Command interface.
+func (CopyOut) command() string { return "copy out" }
+
+func (CopyOut) String() string {
+	return "CopyOut"
+}
+
+var _ Command = CopyOut{}
+
+// StartReplication is the command for the START_REPLICATION command of
+// replication connections. It streams the changes of a logical replication
+// slot to the client with the Copy-both subprotocol.
+type StartReplication struct {
+	Cmd *pgrepl.StartReplication
+	// Conn is the network connection. Streaming takes control of the connection
+	// in order to read the status updates sent by the client.
+	Conn pgwirebase.Conn
+	// Done is decremented once streaming finishes, signaling that control of
+	// the connection is being handed back to the network routine.
+	Done *sync.WaitGroup
+}
+
+// command implements the Command interface.
+func (StartReplication) command() string { return "start replication" }
+
+func (StartReplication) String() string {
+	return "StartReplication"
+}
+
+var _ Command = StartReplication{}
+
 // DrainRequest represents a notice that the server is draining and command
 // processing should stop soon.
 //
@@ -617,6 +657,18 @@ type ClientComm interface {
 	CreateEmptyQueryResult(pos CmdPos) EmptyQueryResult
 	// CreateCopyInResult creates a result for a Copy-in command.
 	CreateCopyInResult(pos CmdPos) CopyInResult
+	// CreateCopyOutResult creates a result for a Copy-out command.
+	CreateCopyOutResult(
+		pos CmdPos,
+		conv sessiondatapb.DataConversionConfig,
+		location *time.Location,
+	) CopyOutResult
+	// CreateReplicationResult creates a result for a StartReplication command.
+	CreateReplicationResult(
+		pos CmdPos,
+		conv sessiondatapb.DataConversionConfig,
+		location *time.Location,
+	) ReplicationResult
 	// CreateDrainResult creates a result for a Drain command.
 	CreateDrainResult(pos CmdPos) DrainResult
 
@@ -635,6 +687,19 @@ type ClientComm interface {
 	Flush(pos CmdPos) error
 }
 
+// NotificationSender is implemented by the ClientComms which can deliver
+// asynchronous notifications, sent with NOTIFY, to the client. pgwire.conn
+// implements this.
+type NotificationSender interface {
+	// BackendPID returns the process ID which identifies the session to the
+	// client.
+	BackendPID() int32
+	// SendNotification sends a notification on the given channel, sent by the
+	// session with the given process ID, to the client. It can be called
+	// concurrently with the other methods of the ClientComm.
+	SendNotification(pid int32, channel, payload string) error
+}
+
 // CommandResult represents the result of a statement. It which needs to be
 // ultimately delivered to the client. pgwire.conn implements this.
 type CommandResult interface {
@@ -710,6 +775,14 @@ type RestrictedCommandResult interface {
 	// ClientComm.createStatementResult.
 	ResetStmtType(stmt tree.Statement)
 
+	// ResetForStatementRetry clears the error, the rows affected count and the
+	// buffered notices and parameter updates of the result, so that the
+	// statement can be executed again. Any rows or other output already produced
+	// for the statement need to be discarded separately (see ClientLock.RTrim).
+	// An error is returned, and the result is left untouched, if the result
+	// does not support retrying the statement.
+	ResetForStatementRetry() error
+
 	// AddRow accumulates a result row.
 	//
 	// The implementation cannot hold on to the row slice; it needs to make a
@@ -812,6 +885,39 @@ type CopyInResult interface {
 	ResultBase
 }
 
+// CopyOutResult represents the result of a CopyOut command. Rows added to the
+// result are sent to the client as CopyData messages. Closing this result
+// finishes the copy with CopyDone and CommandComplete messages.
+type CopyOutResult interface {
+	RestrictedCommandResult
+	CommandResultClose
+
+	// BeginCopyOut sends the message initiating the Copy-out subprotocol. It
+	// informs the client about the columns of the rows that follow and, if
+	// requested by opts, sends the header. It must be called before AddRow.
+	BeginCopyOut(ctx context.Context, cols colinfo.ResultColumns, opts CopyOutOptions) error
+}
+
+// ReplicationResult represents the result of a StartReplication command. The
+// changes of the replication slot are sent to the client as CopyData
+// messages, which are flushed immediately. Closing this result finishes the
+// copy with CopyDone and CommandComplete messages.
+type ReplicationResult interface {
+	ResultBase
+
+	// BeginCopyBoth sends the message initiating the Copy-both subprotocol.
+	// It must be called before the other methods.
+	BeginCopyBoth(ctx context.Context) error
+	// SendXLogData sends a message of the pgoutput plugin. walStart is the
+	// position of the message in the stream and walEnd the current end of the
+	// stream.
+	SendXLogData(ctx context.Context, walStart, walEnd pgrepl.LSN, msg pgrepl.PGOutputMessage) error
+	// SendKeepalive sends a keepalive message, informing the client of the
+	// current end of the stream. If replyRequested is set, the client should
+	// reply with a status update.
+	SendKeepalive(ctx context.Context, walEnd pgrepl.LSN, replyRequested bool) error
+}
+
 // ClientLock is an interface returned by ClientComm.lockCommunication(). It
 // represents a lock on the delivery of results to a SQL client. While such a
 // lock is used, no more results are delivered. The lock itself can be used to
@@ -914,6 +1020,14 @@ func (r *streamingCommandResult) ResetStmtType(stmt tree.Statement) {
 	panic("unimplemented")
 }
 
+// ResetForStatementRetry is part of the RestrictedCommandResult interface.
+// The rows of a streamingCommandResult are handed to the consumer as soon as
+// they are produced, so they cannot be discarded in order to retry the
+// statement.
+func (r *streamingCommandResult) ResetForStatementRetry() error {
+	return errors.AssertionFailedf("statements of the internal executor cannot be retried")
+}
+
 // AddRow is part of the RestrictedCommandResult interface.
 func (r *streamingCommandResult) AddRow(ctx context.Context, row tree.Datums) error {
 	// AddRow() and IncrementRowsAffected() are never called on the same command
@@ -19,6 +19,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
 )
 
 func assertStmt(t *testing.T, cmd Command, exp string) {
@@ -347,3 +348,26 @@ func TestStmtBufBatching(t *testing.T) {
 		t.Fatalf("expected pos to be %d, got: %d", 9, pos)
 	}
 }
+
+// Test that a streamingCommandResult refuses to be reset for a statement retry,
+// since the rows it produced have already been handed to the consumer, and that
+// it is left untouched.
+func TestStreamingCommandResultResetForStatementRetry(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	expErr := errors.New("boom")
+	r := &streamingCommandResult{}
+	r.SetError(expErr)
+	r.IncrementRowsAffected(context.Background(), 3)
+
+	if err := r.ResetForStatementRetry(); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := r.Err(); !errors.Is(err, expErr) {
+		t.Fatalf("expected error %v, got: %v", expErr, err)
+	}
+	if n := r.RowsAffected(); n != 3 {
+		t.Fatalf("expected 3 rows affected, got: %d", n)
+	}
+}
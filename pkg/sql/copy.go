@@ -70,6 +70,9 @@ type copyMachine struct {
 	forceNotNull bool
 	csvInput     bytes.Buffer
 	csvReader    *csv.Reader
+	// csvSkipHeader is set if the first CSV record is a header line that is to
+	// be ignored. It is cleared once the header has been read.
+	csvSkipHeader bool
 	// buf is used to parse input data into rows. It also accumulates a partial
 	// row between protocol messages.
 	buf bytes.Buffer
@@ -144,6 +147,12 @@ func newCopyMachine(
 		c.delimiter = ','
 	}
 
+	if n.Options.Header {
+		if c.format != tree.CopyFormatCSV {
+			return nil, pgerror.New(pgcode.FeatureNotSupported, "COPY HEADER available only in CSV mode")
+		}
+		c.csvSkipHeader = true
+	}
 	if n.Options.Delimiter != nil {
 		if c.format == tree.CopyFormatBinary {
 			return nil, errors.Newf("DELIMITER unsupported in BINARY format")
@@ -444,6 +453,11 @@ func (c *copyMachine) readCSVData(ctx context.Context, final bool) (brk bool, er
 	if len(record) == 1 && record[0] == endOfData && c.buf.Len() == 0 {
 		return true, nil
 	}
+	if c.csvSkipHeader {
+		// The contents of the header line are ignored, like in Postgres.
+		c.csvSkipHeader = false
+		return false, nil
+	}
 	if err != nil {
 		return false, pgerror.Wrap(err, pgcode.BadCopyFileFormat,
 			"read CSV record")
@@ -0,0 +1,199 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// CopyOutOptions describes how the rows of a COPY TO statement are encoded
+// into CopyData messages.
+type CopyOutOptions struct {
+	Format tree.CopyFormat
+	// Delimiter separates the values of a row in the text and CSV formats.
+	Delimiter byte
+	// Null is the string that represents a NULL value in the text and CSV
+	// formats.
+	Null string
+	// Header, if set, causes a row with the column names to be sent before any
+	// data. It is only allowed in the CSV format.
+	Header bool
+}
+
+// copyOutMachine runs the query of a COPY TO statement and streams its
+// results to the client through a CopyOutResult.
+type copyOutMachine struct {
+	stmt   *tree.CopyTo
+	txnOpt copyTxnOpt
+	// p is the planner used to plan and execute the query. It is prepared by
+	// preparePlannerForCopy before use.
+	p planner
+	// execPlan runs the plan that has been set up on the planner, sending the
+	// resulting rows to res.
+	execPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error
+}
+
+func newCopyOutMachine(
+	stmt *tree.CopyTo,
+	txnOpt copyTxnOpt,
+	execCfg *ExecutorConfig,
+	execPlan func(ctx context.Context, p *planner, res RestrictedCommandResult) error,
+) *copyOutMachine {
+	return &copyOutMachine{
+		stmt:     stmt,
+		txnOpt:   txnOpt,
+		p:        planner{execCfg: execCfg, alloc: &rowenc.DatumAlloc{}},
+		execPlan: execPlan,
+	}
+}
+
+// run executes the COPY TO statement. The Copy-out subprotocol is only begun
+// once the query has been planned successfully, so that planning errors are
+// reported to the client like for any other statement.
+func (c *copyOutMachine) run(ctx context.Context, res CopyOutResult) (retErr error) {
+	cleanup := c.p.preparePlannerForCopy(ctx, c.txnOpt)
+	defer func() {
+		retErr = cleanup(ctx, retErr)
+	}()
+
+	opts, err := c.resolveOptions(ctx)
+	if err != nil {
+		return err
+	}
+	query, err := c.query(ctx)
+	if err != nil {
+		return err
+	}
+	c.p.stmt = Statement{}
+	c.p.stmt.AST = query
+	c.p.stmt.SQL = tree.AsString(query)
+	if err := c.p.makeOptimizerPlan(ctx); err != nil {
+		return err
+	}
+	defer c.p.curPlan.close(ctx)
+
+	if err := res.BeginCopyOut(ctx, c.p.curPlan.main.planColumns(), opts); err != nil {
+		return err
+	}
+	if err := c.execPlan(ctx, &c.p, res); err != nil {
+		return err
+	}
+	return res.Err()
+}
+
+// resolveOptions evaluates the options of the statement, using the same
+// defaults and restrictions as COPY FROM.
+func (c *copyOutMachine) resolveOptions(ctx context.Context) (CopyOutOptions, error) {
+	n := c.stmt
+	opts := CopyOutOptions{Format: n.Options.CopyFormat, Header: n.Options.Header}
+	switch opts.Format {
+	case tree.CopyFormatText:
+		opts.Null = `\N`
+		opts.Delimiter = '\t'
+	case tree.CopyFormatCSV:
+		opts.Null = ""
+		opts.Delimiter = ','
+	}
+
+	if n.Options.Destination != nil {
+		return opts, pgerror.New(pgcode.Syntax, "DESTINATION cannot be used with COPY TO")
+	}
+	if n.Options.Header && opts.Format != tree.CopyFormatCSV {
+		return opts, pgerror.New(pgcode.FeatureNotSupported, "COPY HEADER available only in CSV mode")
+	}
+	if n.Options.Delimiter != nil {
+		if opts.Format == tree.CopyFormatBinary {
+			return opts, errors.Newf("DELIMITER unsupported in BINARY format")
+		}
+		fn, err := c.p.TypeAsString(ctx, n.Options.Delimiter, "COPY")
+		if err != nil {
+			return opts, err
+		}
+		delim, err := fn()
+		if err != nil {
+			return opts, err
+		}
+		if len(delim) != 1 || !utf8.ValidString(delim) {
+			return opts, errors.Newf("delimiter must be a single-byte character")
+		}
+		opts.Delimiter = delim[0]
+	}
+	if n.Options.Null != nil {
+		if opts.Format == tree.CopyFormatBinary {
+			return opts, errors.Newf("NULL unsupported in BINARY format")
+		}
+		fn, err := c.p.TypeAsString(ctx, n.Options.Null, "COPY")
+		if err != nil {
+			return opts, err
+		}
+		opts.Null, err = fn()
+		if err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// query returns the statement whose results are copied out. For
+// COPY table TO STDOUT, it is a SELECT of the requested columns.
+func (c *copyOutMachine) query(ctx context.Context) (tree.Statement, error) {
+	n := c.stmt
+	if n.Statement != nil {
+		if n.Statement.StatementReturnType() != tree.Rows {
+			return nil, pgerror.New(pgcode.FeatureNotSupported,
+				"COPY query must have a RETURNING clause")
+		}
+		return n.Statement, nil
+	}
+
+	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveAnyTableKind)
+	_, tableDesc, err := resolver.ResolveExistingTableObject(ctx, &c.p, &n.Table, flags)
+	if err != nil {
+		return nil, err
+	}
+	if tableDesc.IsView() && !tableDesc.MaterializedView() {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "cannot copy from view %q", tableDesc.GetName()),
+			"Try the COPY (SELECT ...) TO variant.",
+		)
+	}
+	if tableDesc.IsSequence() {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "cannot copy from sequence %q", tableDesc.GetName()),
+			"Try the COPY (SELECT ...) TO variant.",
+		)
+	}
+
+	var exprs tree.SelectExprs
+	if len(n.Columns) == 0 {
+		exprs = tree.SelectExprs{tree.StarSelectExpr()}
+	} else {
+		exprs = make(tree.SelectExprs, len(n.Columns))
+		for i := range n.Columns {
+			exprs[i] = tree.SelectExpr{Expr: tree.NewUnresolvedName(string(n.Columns[i]))}
+		}
+	}
+	table := n.Table
+	return &tree.Select{
+		Select: &tree.SelectClause{
+			Exprs: exprs,
+			From:  tree.From{Tables: tree.TableExprs{&table}},
+		},
+	}, nil
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -157,6 +158,9 @@ var crdbInternal = virtualSchema{
 		catconstants.CrdbInternalDefaultPrivilegesTable:           crdbInternalDefaultPrivilegesTable,
 		catconstants.CrdbInternalActiveRangeFeedsTable:            crdbInternalActiveRangeFeedsTable,
 		catconstants.CrdbInternalTenantUsageDetailsViewID:         crdbInternalTenantUsageDetailsView,
+		catconstants.CrdbInternalCreateFunctionStmtsTableID:       crdbInternalCreateFunctionStmtsTable,
+		catconstants.CrdbInternalClusterAdvisoryLocksTableID:      crdbInternalClusterAdvisoryLocksTable,
+		catconstants.CrdbInternalLocalAdvisoryLocksTableID:        crdbInternalLocalAdvisoryLocksTable,
 	},
 	validWithNoDatabaseContext: true,
 }
@@ -1875,6 +1879,92 @@ func populateSessionsTable(
 	return nil
 }
 
+const advisoryLocksSchemaPattern = `
+CREATE TABLE crdb_internal.%s (
+  node_id           INT NOT NULL, -- the node on which the session holding the lock is running
+  session_id        STRING,       -- the ID of the session holding the lock
+  user_name         STRING,       -- the user of the session holding the lock
+  database_id       INT,          -- the ID of the database in which the lock was acquired
+  classid           INT,          -- the key of the lock, as in pg_locks
+  objid             INT,
+  objsubid          INT,
+  mode              STRING,       -- ExclusiveLock or ShareLock
+  session_holds     INT,          -- the number of session-level holds of the lock
+  transaction_holds INT           -- the number of transaction-level holds of the lock
+)
+`
+
+// crdbInternalLocalAdvisoryLocksTable exposes the advisory locks held by the
+// sessions on the current node. The results are dependent on the current
+// user.
+var crdbInternalLocalAdvisoryLocksTable = virtualSchemaTable{
+	comment: "advisory locks held by sessions visible by current user (RAM; local node only)",
+	schema:  fmt.Sprintf(advisoryLocksSchemaPattern, "node_advisory_locks"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		req, err := p.makeSessionsRequest(ctx)
+		if err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListLocalSessions(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateAdvisoryLocksTable(ctx, addRow, response)
+	},
+}
+
+// crdbInternalClusterAdvisoryLocksTable exposes the advisory locks held by
+// the sessions on the entire cluster. The results are dependent on the
+// current user.
+var crdbInternalClusterAdvisoryLocksTable = virtualSchemaTable{
+	comment: "advisory locks held by sessions visible by current user (cluster RPC; expensive!)",
+	schema:  fmt.Sprintf(advisoryLocksSchemaPattern, "cluster_advisory_locks"),
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		req, err := p.makeSessionsRequest(ctx)
+		if err != nil {
+			return err
+		}
+		response, err := p.extendedEvalCtx.SQLStatusServer.ListSessions(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return populateAdvisoryLocksTable(ctx, addRow, response)
+	},
+}
+
+func populateAdvisoryLocksTable(
+	ctx context.Context, addRow func(...tree.Datum) error, response *serverpb.ListSessionsResponse,
+) error {
+	for _, session := range response.Sessions {
+		sessionID := getSessionID(session)
+		for _, l := range session.AdvisoryLocks {
+			mode := "ShareLock"
+			if l.Exclusive {
+				mode = "ExclusiveLock"
+			}
+			if err := addRow(
+				tree.NewDInt(tree.DInt(session.NodeID)),
+				sessionID,
+				tree.NewDString(session.Username),
+				tree.NewDInt(tree.DInt(l.DatabaseID)),
+				tree.NewDInt(tree.DInt(l.ClassID)),
+				tree.NewDInt(tree.DInt(l.ObjID)),
+				tree.NewDInt(tree.DInt(l.ObjSubID)),
+				tree.NewDString(mode),
+				tree.NewDInt(tree.DInt(l.SessionHolds)),
+				tree.NewDInt(tree.DInt(l.TransactionHolds)),
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rpcErr := range response.Errors {
+		log.Warningf(ctx, "%v", rpcErr.Message)
+	}
+	return nil
+}
+
 var crdbInternalClusterContendedTablesView = virtualSchemaView{
 	schema: `
 CREATE VIEW crdb_internal.cluster_contended_tables (
@@ -2275,6 +2365,72 @@ CREATE TABLE crdb_internal.create_type_statements (
 				); err != nil {
 					return err
 				}
+			case descpb.TypeDescriptor_DOMAIN:
+				config := typeDesc.TypeDesc().DomainConfig
+				var constraints tree.DomainConstraintList
+				if config.NotNull {
+					constraints = append(constraints, tree.DomainConstraint{NotNull: true})
+				}
+				for _, check := range config.Checks {
+					expr, err := parser.ParseExpr(check.Expr)
+					if err != nil {
+						return err
+					}
+					constraints = append(constraints, tree.DomainConstraint{
+						Name:  tree.Name(check.Name),
+						Check: expr,
+					})
+				}
+				name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+				if err != nil {
+					return err
+				}
+				node := &tree.CreateType{
+					Variety:           tree.Domain,
+					TypeName:          name,
+					DomainBaseType:    config.BaseType,
+					DomainConstraints: constraints,
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+					tree.NewDString(db.GetName()),             // database_name
+					tree.NewDString(sc),                       // schema_name
+					tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+					tree.NewDString(typeDesc.GetName()),       // descriptor_name
+					tree.NewDString(tree.AsString(node)),      // create_statement
+					tree.DNull,
+				); err != nil {
+					return err
+				}
+			case descpb.TypeDescriptor_COMPOSITE:
+				config := typeDesc.TypeDesc().CompositeConfig
+				elems := make([]tree.CompositeTypeElem, len(config.Elements))
+				for i, elem := range config.Elements {
+					elems[i] = tree.CompositeTypeElem{
+						Label: tree.Name(elem.ElementLabel),
+						Type:  elem.ElementType,
+					}
+				}
+				name, err := tree.NewUnresolvedObjectName(2, [3]string{typeDesc.GetName(), sc}, 0)
+				if err != nil {
+					return err
+				}
+				node := &tree.CreateType{
+					Variety:           tree.Composite,
+					TypeName:          name,
+					CompositeTypeList: elems,
+				}
+				if err := addRow(
+					tree.NewDInt(tree.DInt(db.GetID())),       // database_id
+					tree.NewDString(db.GetName()),             // database_name
+					tree.NewDString(sc),                       // schema_name
+					tree.NewDInt(tree.DInt(typeDesc.GetID())), // descriptor_id
+					tree.NewDString(typeDesc.GetName()),       // descriptor_name
+					tree.NewDString(tree.AsString(node)),      // create_statement
+					tree.DNull,
+				); err != nil {
+					return err
+				}
 			case descpb.TypeDescriptor_MULTIREGION_ENUM:
 				// Multi-region enums are created implicitly, so we don't have create
 				// statements for them.
@@ -2289,6 +2445,38 @@ CREATE TABLE crdb_internal.create_type_statements (
 	},
 }
 
+var crdbInternalCreateFunctionStmtsTable = virtualSchemaTable{
+	comment: "CREATE statements for all user-defined functions accessible by the current user in current database (KV scan)",
+	schema: `
+CREATE TABLE crdb_internal.create_function_statements (
+  database_id        INT,
+  database_name      STRING,
+  schema_id          INT,
+  schema_name        STRING,
+  function_id        INT,
+  function_name      STRING,
+  create_statement   STRING
+)
+`,
+	populate: func(ctx context.Context, p *planner, db catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachFunctionDesc(ctx, p, db, func(db catalog.DatabaseDescriptor, sc string, fnDesc catalog.FunctionDescriptor) error {
+			node, err := makeCreateFunctionStatement(sc, fnDesc)
+			if err != nil {
+				return err
+			}
+			return addRow(
+				tree.NewDInt(tree.DInt(db.GetID())),                             // database_id
+				tree.NewDString(db.GetName()),                                   // database_name
+				tree.NewDInt(tree.DInt(fnDesc.GetParentSchemaID())),             // schema_id
+				tree.NewDString(sc),                                             // schema_name
+				tree.NewDInt(tree.DInt(fnDesc.GetID())),                         // function_id
+				tree.NewDString(fnDesc.GetName()),                               // function_name
+				tree.NewDString(tree.AsStringWithFlags(node, tree.FmtParsable)), // create_statement
+			)
+		})
+	},
+}
+
 var crdbInternalCreateSchemaStmtsTable = virtualSchemaTable{
 	comment: "CREATE statements for all user defined schemas accessible by the current user in current database (KV scan)",
 	schema: `
@@ -4959,7 +5147,8 @@ CREATE TABLE crdb_internal.statement_statistics (
     metadata                   JSONB NOT NULL,
     statistics                 JSONB NOT NULL,
     sampled_plan               JSONB NOT NULL,
-    aggregation_interval       INTERVAL NOT NULL
+    aggregation_interval       INTERVAL NOT NULL,
+    index_recommendations      STRING[] NOT NULL
 );`,
 	generator: func(ctx context.Context, p *planner, db catalog.DatabaseDescriptor, stopper *stop.Stopper) (virtualTableGenerator, cleanupFunc, error) {
 		// TODO(azhng): we want to eventually implement memory accounting within the
@@ -4993,7 +5182,7 @@ CREATE TABLE crdb_internal.statement_statistics (
 			Knobs:            execCfg.SQLStatsTestingKnobs,
 		}, memSQLStats)
 
-		row := make(tree.Datums, 8 /* number of columns for this virtual table */)
+		row := make(tree.Datums, 10 /* number of columns for this virtual table */)
 		worker := func(pusher rowPusher) error {
 			return sqlStats.IterateStatementStats(ctx, &sqlstats.IteratorOptions{
 				SortedAppNames: true,
@@ -5030,6 +5219,13 @@ CREATE TABLE crdb_internal.statement_statistics (
 					duration.MakeDuration(statistics.AggregationInterval.Nanoseconds(), 0, 0),
 					types.DefaultIntervalTypeMetadata)
 
+				indexRecommendations := tree.NewDArray(types.String)
+				for _, rec := range statistics.Stats.IndexRecommendations {
+					if err := indexRecommendations.Append(tree.NewDString(rec)); err != nil {
+						return err
+					}
+				}
+
 				row = row[:0]
 				row = append(row,
 					aggregatedTs,                        // aggregated_ts
@@ -5041,6 +5237,7 @@ CREATE TABLE crdb_internal.statement_statistics (
 					tree.NewDJSON(statisticsJSON),       // statistics
 					tree.NewDJSON(plan),                 // plan
 					aggInterval,                         // aggregation_interval
+					indexRecommendations,                // index_recommendations
 				)
 
 				return pusher.pushRow(row...)
@@ -0,0 +1,502 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+type createFunctionNode struct {
+	n      *tree.CreateFunction
+	dbDesc *dbdesc.Mutable
+	scDesc catalog.SchemaDescriptor
+}
+
+var _ planNode = &createFunctionNode{n: nil}
+
+// CreateFunction creates a user-defined function or procedure.
+// Privileges: CREATE on the database and schema.
+func (p *planner) CreateFunction(ctx context.Context, n *tree.CreateFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		n.StatementTag(),
+	); err != nil {
+		return nil, err
+	}
+	// Nodes running older versions cannot decode function descriptors, and
+	// don't know that a function descriptor can describe a procedure.
+	if n.IsProcedure && !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.StoredProcedures) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use stored procedures",
+			clusterversion.StoredProcedures)
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.UserDefinedFunctions) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use user-defined functions",
+			clusterversion.UserDefinedFunctions)
+	}
+
+	db, sc, prefix, err := p.ResolveTargetObject(ctx, n.FuncName)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, db, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if db.GetID() == keys.SystemDatabaseID {
+		return nil, errors.New("cannot create a function in the system database")
+	}
+	if sc.SchemaKind() == catalog.SchemaTemporary {
+		return nil, unimplemented.NewWithIssue(17511, "cannot create functions in temporary schemas")
+	}
+	scDesc, err := p.getNonTemporarySchemaForCreate(ctx, db, prefix.Schema())
+	if err != nil {
+		return nil, err
+	}
+	if err := p.canCreateOnSchema(
+		ctx, scDesc.GetID(), db.GetID(), p.User(), skipCheckPublicSchema,
+	); err != nil {
+		return nil, err
+	}
+
+	// Builtin functions take precedence over user-defined functions during
+	// resolution, so a user-defined function with the same name as a builtin
+	// could never be called.
+	name := n.FuncName.Object()
+	if _, ok := tree.FunDefs[name]; ok {
+		return nil, pgerror.Newf(pgcode.DuplicateFunction,
+			"function %q conflicts with a built-in function", name)
+	}
+
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(ctx, p.txn, db.GetName(),
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return nil, err
+	}
+	return &createFunctionNode{n: n, dbDesc: dbDesc, scDesc: scDesc}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE FUNCTION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createFunctionNode) ReadingOwnWrites() {}
+
+func (n *createFunctionNode) startExec(params runParams) error {
+	p := params.p
+	name := n.n.FuncName.Object()
+
+	args, err := p.resolveFunctionArgs(params.ctx, n.n.Args)
+	if err != nil {
+		return err
+	}
+	var returnType *types.T
+	if n.n.IsProcedure {
+		// The return type of a procedure is the record of its output arguments.
+		outCols := procedureOutputColumns(args)
+		outTypes := make([]*types.T, len(outCols))
+		outLabels := make([]string, len(outCols))
+		for i := range outCols {
+			outTypes[i] = outCols[i].Typ
+			outLabels[i] = outCols[i].Name
+		}
+		returnType = types.MakeLabeledTuple(outTypes, outLabels)
+	} else {
+		for i := range args {
+			if args[i].Class != descpb.FunctionDescriptor_Argument_IN {
+				return unimplemented.NewWithIssue(17511,
+					"OUT and INOUT arguments are only supported by procedures")
+			}
+		}
+		if returnType, err = p.resolveFunctionType(params.ctx, n.n.ReturnType); err != nil {
+			return err
+		}
+	}
+
+	// Determine the options of the function.
+	var volatility, leakProof, nullInput, lang, body bool
+	fn := descpb.FunctionDescriptor{
+		Args:        args,
+		ReturnType:  returnType,
+		Volatility:  descpb.FunctionDescriptor_VOLATILE,
+		IsProcedure: n.n.IsProcedure,
+	}
+	for _, option := range n.n.Options {
+		var seen *bool
+		if n.n.IsProcedure {
+			switch option.(type) {
+			case tree.FunctionLanguage, tree.FunctionBodyStr:
+			default:
+				return pgerror.New(pgcode.InvalidFunctionDefinition,
+					"invalid attribute in procedure definition")
+			}
+		}
+		switch t := option.(type) {
+		case tree.FunctionVolatility:
+			seen = &volatility
+			switch t {
+			case tree.FunctionImmutable:
+				fn.Volatility = descpb.FunctionDescriptor_IMMUTABLE
+			case tree.FunctionStable:
+				fn.Volatility = descpb.FunctionDescriptor_STABLE
+			case tree.FunctionVolatile:
+				fn.Volatility = descpb.FunctionDescriptor_VOLATILE
+			}
+		case tree.FunctionLeakproof:
+			seen = &leakProof
+			fn.LeakProof = bool(t)
+		case tree.FunctionNullInputBehavior:
+			seen = &nullInput
+			switch t {
+			case tree.FunctionCalledOnNullInput:
+				fn.NullInputBehavior = descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+			case tree.FunctionReturnsNullOnNullInput:
+				fn.NullInputBehavior = descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT
+			case tree.FunctionStrict:
+				fn.NullInputBehavior = descpb.FunctionDescriptor_STRICT
+			}
+		case tree.FunctionLanguage:
+			seen = &lang
+			if !strings.EqualFold(string(t), "sql") {
+				return unimplemented.NewWithIssuef(17511,
+					"functions in language %q are not supported", string(t))
+			}
+			fn.Lang = descpb.FunctionDescriptor_SQL
+		case tree.FunctionBodyStr:
+			seen = &body
+			fn.FunctionBody = string(t)
+		default:
+			return errors.AssertionFailedf("unexpected function option %T", t)
+		}
+		if *seen {
+			return pgerror.New(pgcode.Syntax, "conflicting or redundant options")
+		}
+		*seen = true
+	}
+	if !body {
+		return pgerror.New(pgcode.InvalidFunctionDefinition, "no function body specified")
+	}
+	if fn.LeakProof && fn.Volatility != descpb.FunctionDescriptor_IMMUTABLE {
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"cannot create leakproof function with non-immutable volatility")
+	}
+	if fn.IsProcedure {
+		if err := validateProcedureBody(&fn); err != nil {
+			return err
+		}
+	} else if err := p.validateFunctionBody(params.ctx, &fn); err != nil {
+		return err
+	}
+
+	// Look for an existing function with the same name and argument types in
+	// the target schema.
+	argTypes := make([]*types.T, len(args))
+	for i := range args {
+		argTypes[i] = args[i].Type
+	}
+	existing, err := p.getFunctionInSchema(params.ctx, n.dbDesc, n.scDesc, name, argTypes)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if !n.n.Replace {
+			return sqlerrors.NewFunctionAlreadyExistsError(funcSignature(name, argTypes))
+		}
+		if existing.GetIsProcedure() != fn.IsProcedure {
+			return errors.WithDetailf(
+				pgerror.New(pgcode.WrongObjectType, "cannot change routine kind"),
+				"%q is a %s.", name, routineKind(existing.GetIsProcedure()))
+		}
+		return p.replaceFunction(params.ctx, existing, &fn)
+	}
+
+	id, err := catalogkv.GenerateUniqueDescID(params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec)
+	if err != nil {
+		return err
+	}
+	privs := descpb.NewBasePrivilegeDescriptor(p.User())
+	desc := funcdesc.NewMutableFunctionDescriptor(
+		id, n.dbDesc.GetID(), n.scDesc.GetID(), name, args, returnType, privs,
+	)
+	desc.Volatility = fn.Volatility
+	desc.LeakProof = fn.LeakProof
+	desc.NullInputBehavior = fn.NullInputBehavior
+	desc.Lang = fn.Lang
+	desc.FunctionBody = fn.FunctionBody
+	desc.IsProcedure = fn.IsProcedure
+	if err := p.Descriptors().WriteDesc(
+		params.ctx, p.ExtendedEvalContext().Tracing.KVTracingEnabled(), desc, p.txn,
+	); err != nil {
+		return err
+	}
+
+	n.dbDesc.AddFunction(name, id, n.scDesc.GetID())
+	return p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc,
+		fmt.Sprintf("updating database %s for function %s", n.dbDesc.GetName(), name),
+	)
+}
+
+func (*createFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createFunctionNode) Close(context.Context)        {}
+
+// replaceFunction updates the existing function desc with the definition of
+// fn, as done by CREATE OR REPLACE FUNCTION.
+func (p *planner) replaceFunction(
+	ctx context.Context, desc *funcdesc.Mutable, fn *descpb.FunctionDescriptor,
+) error {
+	hasOwnership, err := p.HasOwnership(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if !hasOwnership {
+		return pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of function %s", desc.GetName())
+	}
+	if !desc.ReturnType.Identical(fn.ReturnType) {
+		if desc.IsProcedure {
+			return pgerror.New(pgcode.InvalidFunctionDefinition,
+				"cannot change output parameters of existing procedure")
+		}
+		return pgerror.New(pgcode.InvalidFunctionDefinition,
+			"cannot change return type of existing function")
+	}
+	for i := range desc.Args {
+		if desc.Args[i].Name != fn.Args[i].Name {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change name of input parameter %q", desc.Args[i].Name)
+		}
+		if desc.Args[i].Class != fn.Args[i].Class {
+			return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+				"cannot change mode of parameter %d", i+1)
+		}
+	}
+	desc.Volatility = fn.Volatility
+	desc.LeakProof = fn.LeakProof
+	desc.NullInputBehavior = fn.NullInputBehavior
+	desc.Lang = fn.Lang
+	desc.FunctionBody = fn.FunctionBody
+	return p.Descriptors().WriteDesc(
+		ctx, p.ExtendedEvalContext().Tracing.KVTracingEnabled(), desc, p.txn,
+	)
+}
+
+// resolveFunctionArgs resolves the arguments of a function. Arguments of
+// user-defined types are not supported.
+func (p *planner) resolveFunctionArgs(
+	ctx context.Context, funcArgs tree.FuncArgs,
+) ([]descpb.FunctionDescriptor_Argument, error) {
+	args := make([]descpb.FunctionDescriptor_Argument, len(funcArgs))
+	for i := range funcArgs {
+		typ, err := p.resolveFunctionType(ctx, funcArgs[i].Type)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = descpb.FunctionDescriptor_Argument{Name: string(funcArgs[i].Name), Type: typ}
+		switch funcArgs[i].Class {
+		case tree.FuncArgOut:
+			args[i].Class = descpb.FunctionDescriptor_Argument_OUT
+		case tree.FuncArgInOut:
+			args[i].Class = descpb.FunctionDescriptor_Argument_INOUT
+		}
+		for j := 0; j < i; j++ {
+			if args[i].Name != "" && args[i].Name == args[j].Name {
+				return nil, pgerror.Newf(pgcode.InvalidFunctionDefinition,
+					"parameter name %q used more than once", args[i].Name)
+			}
+		}
+	}
+	return args, nil
+}
+
+// resolveFunctionType resolves the type of an argument or of the return value
+// of a function.
+func (p *planner) resolveFunctionType(
+	ctx context.Context, ref tree.ResolvableTypeReference,
+) (*types.T, error) {
+	typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	if typ.UserDefined() {
+		return nil, unimplemented.NewWithIssuef(17511,
+			"user-defined types are not supported in function signatures: %s", typ.SQLString())
+	}
+	return typ, nil
+}
+
+// validateFunctionBody checks that the body of the given function is a valid
+// query which produces a single column whose type can be assigned to the
+// return type of the function.
+func (p *planner) validateFunctionBody(ctx context.Context, fn *descpb.FunctionDescriptor) error {
+	desc := funcdesc.NewBuilder(fn).BuildImmutableFunction()
+	o, err := funcdesc.MakeOverload(desc)
+	if err != nil {
+		return err
+	}
+	args := make(tree.Exprs, len(fn.Args))
+	for i := range args {
+		args[i] = tree.DNull
+	}
+	stmt, err := optbuilder.MakeUDFBodyQuery(&o, args)
+	if err != nil {
+		return err
+	}
+	stmt.Limit = &tree.Limit{Count: tree.NewDInt(0)}
+	searchPath := p.SessionData().SearchPath
+	_, cols, err := p.ExecCfg().InternalExecutor.QueryBufferedExWithCols(
+		ctx, "validate-function-body", p.txn,
+		sessiondata.InternalExecutorOverride{
+			User:       p.User(),
+			Database:   p.CurrentDatabase(),
+			SearchPath: &searchPath,
+		},
+		tree.AsStringWithFlags(stmt, tree.FmtParsable),
+	)
+	if err != nil {
+		return errors.Wrap(err, "invalid function body")
+	}
+	if len(cols) != 1 {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s", fn.ReturnType.SQLString())
+	}
+	if !tree.ValidCast(cols[0].Typ, fn.ReturnType, tree.CastContextAssignment) {
+		return pgerror.Newf(pgcode.InvalidFunctionDefinition,
+			"return type mismatch in function declared to return %s: body returns %s",
+			fn.ReturnType.SQLString(), cols[0].Typ.SQLString())
+	}
+	return nil
+}
+
+// getFunctionInSchema returns the mutable descriptor of the function with the
+// given name and argument types in the given schema, or nil if no such
+// function exists.
+func (p *planner) getFunctionInSchema(
+	ctx context.Context,
+	db catalog.DatabaseDescriptor,
+	sc catalog.SchemaDescriptor,
+	name string,
+	argTypes []*types.T,
+) (*funcdesc.Mutable, error) {
+	for _, o := range db.GetFunctionOverloads(name) {
+		if o.SchemaID != sc.GetID() {
+			continue
+		}
+		desc, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, o.ID,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return nil, err
+		}
+		if sameArgTypes(desc.ArgTypes(), argTypes) {
+			return desc, nil
+		}
+	}
+	return nil, nil
+}
+
+// funcSignature formats the signature of a function for error messages.
+func funcSignature(name string, argTypes []*types.T) string {
+	var buf strings.Builder
+	buf.WriteString(name)
+	buf.WriteByte('(')
+	for i, typ := range argTypes {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(typ.SQLString())
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// makeCreateFunctionStatement constructs the CREATE FUNCTION statement which
+// recreates the given function.
+func makeCreateFunctionStatement(
+	scName string, fn catalog.FunctionDescriptor,
+) (*tree.CreateFunction, error) {
+	name, err := tree.NewUnresolvedObjectName(2, [3]string{fn.GetName(), scName}, 0)
+	if err != nil {
+		return nil, err
+	}
+	node := &tree.CreateFunction{
+		FuncName:    name,
+		IsProcedure: fn.GetIsProcedure(),
+	}
+	for _, arg := range fn.GetArgs() {
+		funcArg := tree.FuncArg{Name: tree.Name(arg.Name), Type: arg.Type}
+		switch arg.Class {
+		case descpb.FunctionDescriptor_Argument_OUT:
+			funcArg.Class = tree.FuncArgOut
+		case descpb.FunctionDescriptor_Argument_INOUT:
+			funcArg.Class = tree.FuncArgInOut
+		}
+		node.Args = append(node.Args, funcArg)
+	}
+	if fn.GetIsProcedure() {
+		node.Options = tree.FunctionOptions{
+			tree.FunctionLanguage("sql"),
+			tree.FunctionBodyStr(fn.GetFunctionBody()),
+		}
+		return node, nil
+	}
+	node.ReturnType = fn.GetReturnType()
+	var volatility tree.FunctionVolatility
+	switch fn.GetVolatility() {
+	case descpb.FunctionDescriptor_IMMUTABLE:
+		volatility = tree.FunctionImmutable
+	case descpb.FunctionDescriptor_STABLE:
+		volatility = tree.FunctionStable
+	case descpb.FunctionDescriptor_VOLATILE:
+		volatility = tree.FunctionVolatile
+	default:
+		return nil, errors.AssertionFailedf("unknown volatility %s", fn.GetVolatility())
+	}
+	var nullInput tree.FunctionNullInputBehavior
+	switch fn.GetNullInputBehavior() {
+	case descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT:
+		nullInput = tree.FunctionCalledOnNullInput
+	case descpb.FunctionDescriptor_RETURNS_NULL_ON_NULL_INPUT:
+		nullInput = tree.FunctionReturnsNullOnNullInput
+	case descpb.FunctionDescriptor_STRICT:
+		nullInput = tree.FunctionStrict
+	default:
+		return nil, errors.AssertionFailedf("unknown null input behavior %s", fn.GetNullInputBehavior())
+	}
+	node.Options = tree.FunctionOptions{
+		tree.FunctionLanguage("sql"),
+		volatility,
+		tree.FunctionLeakproof(fn.GetLeakProof()),
+		nullInput,
+		tree.FunctionBodyStr(fn.GetFunctionBody()),
+	}
+	return node, nil
+}
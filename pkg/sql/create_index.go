@@ -63,6 +63,9 @@ func (p *planner) CreateIndex(ctx context.Context, n *tree.CreateIndex) (planNod
 	if tableDesc.IsView() && !tableDesc.MaterializedView() {
 		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a table or materialized view", tableDesc.Name)
 	}
+	if err := checkNotForeignTable(tableDesc, "CREATE INDEX"); err != nil {
+		return nil, err
+	}
 
 	if tableDesc.MaterializedView() {
 		if n.Sharded != nil {
@@ -203,6 +206,16 @@ func MakeIndexDescriptor(
 		CreatedExplicitly: true,
 	}
 
+	invertedColumnKind, err := getInvertedColumnKind(n.Columns, n.Inverted)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkInvertedColumnKindIsSupported(
+		params.ctx, params.EvalContext(), invertedColumnKind,
+	); err != nil {
+		return nil, err
+	}
+
 	if n.Inverted {
 		if n.Sharded != nil {
 			return nil, pgerror.New(pgcode.InvalidSQLStatementName, "inverted indexes don't support hash sharding")
@@ -217,6 +230,7 @@ func MakeIndexDescriptor(
 		}
 
 		indexDesc.Type = descpb.IndexDescriptor_INVERTED
+		indexDesc.InvertedColumnKind = invertedColumnKind
 		column, err := tableDesc.FindColumnWithName(n.Columns[len(n.Columns)-1].Column)
 		if err != nil {
 			return nil, err
@@ -295,6 +309,9 @@ func MakeIndexDescriptor(
 		if geoindex.IsGeographyConfig(&indexDesc.GeoConfig) {
 			telemetry.Inc(sqltelemetry.GeographyInvertedIndexCounter)
 		}
+		if indexDesc.InvertedColumnKind == descpb.IndexDescriptor_TRIGRAM {
+			telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
+		}
 		if indexDesc.IsPartial() {
 			telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 		}
@@ -354,6 +371,53 @@ func validateIndexColumnsExist(desc *tabledesc.Mutable, columns tree.IndexElemLi
 	return nil
 }
 
+// getInvertedColumnKind returns the kind of the inverted column of an index
+// with the given elements, which is determined by the operator class of the
+// last element. Only the last element of an inverted index may have an
+// operator class.
+func getInvertedColumnKind(
+	elems tree.IndexElemList, isInverted bool,
+) (descpb.IndexDescriptor_InvertedColumnKind, error) {
+	for i := range elems {
+		opClass := elems[i].OpClass
+		if opClass == "" {
+			continue
+		}
+		if !isInverted {
+			return 0, errors.WithHint(
+				pgerror.Newf(
+					pgcode.UndefinedObject,
+					"operator class %s is only allowed in an inverted index", opClass,
+				),
+				"use CREATE INVERTED INDEX or USING GIN to create an inverted index",
+			)
+		}
+		if i != len(elems)-1 {
+			return 0, pgerror.Newf(
+				pgcode.FeatureNotSupported,
+				"operator class %s is only allowed for the last column of an inverted index", opClass,
+			)
+		}
+		// The parser only accepts the gin_trgm_ops operator class.
+		return descpb.IndexDescriptor_TRIGRAM, nil
+	}
+	return descpb.IndexDescriptor_DEFAULT, nil
+}
+
+// checkInvertedColumnKindIsSupported returns an error if the given kind of
+// inverted index cannot be created until the cluster is upgraded.
+func checkInvertedColumnKindIsSupported(
+	ctx context.Context, evalCtx *tree.EvalContext, kind descpb.IndexDescriptor_InvertedColumnKind,
+) error {
+	if kind == descpb.IndexDescriptor_TRIGRAM &&
+		!evalCtx.Settings.Version.IsActive(ctx, clusterversion.TrigramInvertedIndexes) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use trigram inverted indexes",
+			clusterversion.TrigramInvertedIndexes)
+	}
+	return nil
+}
+
 // replaceExpressionElemsWithVirtualCols replaces each non-nil expression in
 // elems with an inaccessible virtual column with the same expression. If
 // isNewTable is true, the column is added directly to desc. Otherwise, the
@@ -446,7 +510,9 @@ func replaceExpressionElemsWithVirtualCols(
 						"see the documentation for more information about inverted indexes: "+docs.URL("inverted-indexes.html"),
 					)
 				}
-				if i == lastColumnIdx && !colinfo.ColumnTypeIsInvertedIndexable(typ) {
+				// The operator class of the last element determines the
+				// types it accepts, which is validated with the index.
+				if i == lastColumnIdx && elem.OpClass == "" && !colinfo.ColumnTypeIsInvertedIndexable(typ) {
 					return errors.WithHint(
 						pgerror.Newf(
 							pgcode.InvalidTableDefinition,
@@ -21,7 +21,11 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/docs"
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
 	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
@@ -51,6 +55,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/log/eventpb"
 	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
 	"github.com/lib/pq/oid"
 )
 
@@ -58,6 +63,8 @@ type createTableNode struct {
 	n          *tree.CreateTable
 	dbDesc     catalog.DatabaseDescriptor
 	sourcePlan planNode
+	// foreign is set when creating a foreign table.
+	foreign *descpb.TableDescriptor_ForeignTable
 }
 
 // ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
@@ -374,6 +381,13 @@ func (n *createTableNode) startExec(params runParams) error {
 		if err != nil {
 			return err
 		}
+		if n.foreign != nil {
+			if desc.ContainsUserDefinedTypes() {
+				return pgerror.New(pgcode.FeatureNotSupported,
+					"user-defined types are not supported on foreign tables")
+			}
+			desc.ForeignTable = n.foreign
+		}
 
 		if desc.Adding() {
 			// if this table and all its references are created in the same
@@ -397,6 +411,21 @@ func (n *createTableNode) startExec(params runParams) error {
 		}
 	}
 
+	if ttl := desc.RowLevelTTL; ttl != nil {
+		j, err := CreateRowLevelTTLScheduledJob(
+			params.ctx,
+			params.ExecCfg(),
+			params.p.txn,
+			params.p.User(),
+			desc.GetID(),
+			ttl,
+		)
+		if err != nil {
+			return err
+		}
+		ttl.ScheduleID = j.ScheduleID()
+	}
+
 	// Descriptor written to store here.
 	if err := params.p.createDescriptorWithID(
 		params.ctx,
@@ -610,6 +639,31 @@ const (
 	NonEmptyTable
 )
 
+// errDeferrableUniqueIndex is returned when a unique constraint that is
+// enforced by an index is declared DEFERRABLE. Uniqueness of an index is
+// enforced by the KV layer on every write, so it cannot be postponed.
+var errDeferrableUniqueIndex = errors.WithHint(
+	pgerror.New(pgcode.FeatureNotSupported, "unique constraints with an index cannot be DEFERRABLE"),
+	"use UNIQUE WITHOUT INDEX to declare a deferrable unique constraint",
+)
+
+// checkDeferrableConstraintsAllowed returns an error if a constraint is
+// declared DEFERRABLE before the cluster version that knows how to check it
+// at commit time is finalized.
+func checkDeferrableConstraintsAllowed(
+	ctx context.Context, evalCtx *tree.EvalContext, deferrability tree.ConstraintDeferrability,
+) error {
+	if deferrability == tree.NotDeferrableConstraint {
+		return nil
+	}
+	if !evalCtx.Settings.Version.IsActive(ctx, clusterversion.DeferrableConstraints) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use deferrable constraints",
+			clusterversion.DeferrableConstraints)
+	}
+	return nil
+}
+
 // addUniqueWithoutIndexColumnTableDef runs various checks on the given
 // ColumnTableDef before adding it as a UNIQUE WITHOUT INDEX constraint to the
 // given table descriptor.
@@ -634,6 +688,7 @@ func addUniqueWithoutIndexColumnTableDef(
 		string(d.Unique.ConstraintName),
 		[]string{string(d.Name)},
 		"", /* predicate */
+		tree.NotDeferrableConstraint,
 		ts,
 		validationBehavior,
 	); err != nil {
@@ -671,6 +726,9 @@ func addUniqueWithoutIndexTableDef(
 			"partitioned unique constraints without an index are not supported",
 		)
 	}
+	if err := checkDeferrableConstraintsAllowed(ctx, evalCtx, d.Deferrable); err != nil {
+		return err
+	}
 
 	// If there is a predicate, validate it.
 	var predicate string
@@ -690,7 +748,7 @@ func addUniqueWithoutIndexTableDef(
 		colNames[i] = string(d.Columns[i].Column)
 	}
 	if err := ResolveUniqueWithoutIndexConstraint(
-		ctx, desc, string(d.Name), colNames, predicate, ts, validationBehavior,
+		ctx, desc, string(d.Name), colNames, predicate, d.Deferrable, ts, validationBehavior,
 	); err != nil {
 		return err
 	}
@@ -710,6 +768,7 @@ func ResolveUniqueWithoutIndexConstraint(
 	constraintName string,
 	colNames []string,
 	predicate string,
+	deferrability tree.ConstraintDeferrability,
 	ts TableState,
 	validationBehavior tree.ValidationBehavior,
 ) error {
@@ -763,11 +822,13 @@ func ResolveUniqueWithoutIndexConstraint(
 	}
 
 	uc := descpb.UniqueWithoutIndexConstraint{
-		Name:      constraintName,
-		TableID:   tbl.ID,
-		ColumnIDs: columnIDs,
-		Predicate: predicate,
-		Validity:  validity,
+		Name:              constraintName,
+		TableID:           tbl.ID,
+		ColumnIDs:         columnIDs,
+		Predicate:         predicate,
+		Validity:          validity,
+		Deferrable:        deferrability != tree.NotDeferrableConstraint,
+		InitiallyDeferred: deferrability == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -817,6 +878,9 @@ func ResolveFK(
 	validationBehavior tree.ValidationBehavior,
 	evalCtx *tree.EvalContext,
 ) error {
+	if err := checkDeferrableConstraintsAllowed(ctx, evalCtx, d.Deferrable); err != nil {
+		return err
+	}
 	var originColSet catalog.TableColSet
 	originCols := make([]catalog.Column, len(d.FromCols))
 	for i, fromCol := range d.FromCols {
@@ -840,6 +904,10 @@ func ResolveFK(
 	if err != nil {
 		return err
 	}
+	if target.IsForeignTable() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"foreign keys cannot reference foreign table %q", target.GetName())
+	}
 	if target.ParentID != tbl.ParentID {
 		if !allowCrossDatabaseFKs.Get(&evalCtx.Settings.SV) {
 			return errors.WithHintf(
@@ -1022,6 +1090,8 @@ func ResolveFK(
 		OnDelete:            descpb.ForeignKeyReferenceActionValue[d.Actions.Delete],
 		OnUpdate:            descpb.ForeignKeyReferenceActionValue[d.Actions.Update],
 		Match:               descpb.CompositeKeyMatchMethodValue[d.Match],
+		Deferrable:          d.Deferrable != tree.NotDeferrableConstraint,
+		InitiallyDeferred:   d.Deferrable == tree.DeferrableInitiallyDeferred,
 	}
 
 	if ts == NewTable {
@@ -1274,11 +1344,34 @@ func NewTableDesc(
 		semaCtx,
 		evalCtx,
 		n.StorageParams,
-		&paramparse.TableStorageParamObserver{},
+		paramparse.NewTableStorageParamObserver(&desc),
 	); err != nil {
 		return nil, err
 	}
 
+	// Add the automatic expiration column for tables with row-level TTL,
+	// unless it has been explicitly specified (e.g. by SHOW CREATE output).
+	if ttl := desc.RowLevelTTL; ttl != nil {
+		if err := checkTTLEnabledForCluster(ctx, st); err != nil {
+			return nil, err
+		}
+		hasRowLevelTTLColumn := false
+		for _, def := range n.Defs {
+			if d, ok := def.(*tree.ColumnTableDef); ok && d.Name == colinfo.TTLDefaultExpirationColumnName {
+				hasRowLevelTTLColumn = true
+				break
+			}
+		}
+		if !hasRowLevelTTLColumn {
+			col, err := rowLevelTTLAutomaticColumnDef(ttl)
+			if err != nil {
+				return nil, err
+			}
+			n.Defs = append(n.Defs, col)
+			cdd = append(cdd, nil)
+		}
+	}
+
 	indexEncodingVersion := descpb.StrictIndexColumnIDGuaranteesVersion
 	isRegionalByRow := n.Locality != nil && n.Locality.LocalityLevel == tree.LocalityLevelRow
 
@@ -1686,8 +1779,16 @@ func NewTableDesc(
 				StoreColumnNames: d.Storing.ToStrings(),
 				Version:          indexEncodingVersion,
 			}
+			invertedColumnKind, err := getInvertedColumnKind(d.Columns, d.Inverted)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkInvertedColumnKindIsSupported(ctx, evalCtx, invertedColumnKind); err != nil {
+				return nil, err
+			}
 			if d.Inverted {
 				idx.Type = descpb.IndexDescriptor_INVERTED
+				idx.InvertedColumnKind = invertedColumnKind
 			}
 			columns := d.Columns
 			if d.Sharded != nil {
@@ -1777,6 +1878,9 @@ func NewTableDesc(
 				// We will add the unique constraint below.
 				break
 			}
+			if d.Deferrable != tree.NotDeferrableConstraint {
+				return nil, errDeferrableUniqueIndex
+			}
 			// If the index is named, ensure that the name is unique. Unnamed
 			// indexes will be given a unique auto-generated name later on when
 			// AllocateIDs is called.
@@ -1788,6 +1892,9 @@ func NewTableDesc(
 			if err := validateColumnsAreAccessible(&desc, d.Columns); err != nil {
 				return nil, err
 			}
+			if _, err := getInvertedColumnKind(d.Columns, false /* isInverted */); err != nil {
+				return nil, err
+			}
 			if err := replaceExpressionElemsWithVirtualCols(
 				ctx,
 				&desc,
@@ -2132,6 +2239,9 @@ func NewTableDesc(
 					telemetry.Inc(sqltelemetry.GeometryInvertedIndexCounter)
 				}
 			}
+			if idx.GetInvertedColumnKind() == descpb.IndexDescriptor_TRIGRAM {
+				telemetry.Inc(sqltelemetry.TrigramInvertedIndexCounter)
+			}
 			if idx.IsPartial() {
 				telemetry.Inc(sqltelemetry.PartialInvertedIndexCounter)
 			}
@@ -2402,6 +2512,7 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 						Columns: make(tree.IndexElemList, 0, len(c.ColumnIDs)),
 					},
 					WithoutIndex: true,
+					Deferrable:   c.Deferrability(),
 				}
 				colNames, err := td.NamesForColumnIDs(c.ColumnIDs)
 				if err != nil {
@@ -2457,6 +2568,9 @@ func replaceLikeTableOpts(n *tree.CreateTable, params runParams) (tree.TableDefs
 					if idx.GetKeyColumnDirection(j) == descpb.IndexDescriptor_DESC {
 						elem.Direction = tree.Descending
 					}
+					if j == numColumns-1 && idx.GetInvertedColumnKind() == descpb.IndexDescriptor_TRIGRAM {
+						elem.OpClass = "gin_trgm_ops"
+					}
 					indexDef.Columns = append(indexDef.Columns, elem)
 				}
 				for j := 0; j < idx.NumSecondaryStoredColumns(); j++ {
@@ -2677,6 +2791,98 @@ func regionalByRowDefaultColDef(
 	return c
 }
 
+func rowLevelTTLAutomaticColumnDef(
+	ttl *descpb.TableDescriptor_RowLevelTTL,
+) (*tree.ColumnTableDef, error) {
+	def := &tree.ColumnTableDef{
+		Name:   colinfo.TTLDefaultExpirationColumnName,
+		Type:   types.TimestampTZ,
+		Hidden: true,
+	}
+	intervalExpr, err := parser.ParseExpr(ttl.DurationExpr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unexpected expression for TTL duration")
+	}
+	def.DefaultExpr.Expr = rowLevelTTLAutomaticColumnExpr(intervalExpr)
+	def.OnUpdateExpr.Expr = rowLevelTTLAutomaticColumnExpr(intervalExpr)
+	def.Nullable.Nullability = tree.NotNull
+	return def, nil
+}
+
+func rowLevelTTLAutomaticColumnExpr(intervalExpr tree.Expr) tree.Expr {
+	return &tree.BinaryExpr{
+		Operator: tree.MakeBinaryOperator(tree.Plus),
+		Left:     &tree.FuncExpr{Func: tree.WrapFunction("current_timestamp")},
+		Right:    intervalExpr,
+	}
+}
+
+// newRowLevelTTLScheduledJob returns a *jobs.ScheduledJob for row level TTL
+// for a given table.
+func newRowLevelTTLScheduledJob(
+	env scheduledjobs.JobSchedulerEnv,
+	owner security.SQLUsername,
+	tblID descpb.ID,
+	ttl *descpb.TableDescriptor_RowLevelTTL,
+) (*jobs.ScheduledJob, error) {
+	sj := jobs.NewScheduledJob(env)
+	sj.SetScheduleLabel(fmt.Sprintf("row-level-ttl-%d", tblID))
+	sj.SetOwner(owner)
+	sj.SetScheduleDetails(jobspb.ScheduleDetails{
+		Wait: jobspb.ScheduleDetails_WAIT,
+		// If a job fails, try again at the allocated cron time.
+		OnError: jobspb.ScheduleDetails_RETRY_SCHED,
+	})
+
+	if err := sj.SetSchedule(ttl.DeletionCronOrDefault()); err != nil {
+		return nil, err
+	}
+	args := &jobspb.ScheduledRowLevelTTLArgs{
+		TableID: tblID,
+	}
+	any, err := pbtypes.MarshalAny(args)
+	if err != nil {
+		return nil, err
+	}
+	sj.SetExecutionDetails(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		jobspb.ExecutionArguments{Args: any},
+	)
+	return sj, nil
+}
+
+// CreateRowLevelTTLScheduledJob creates a new row-level TTL schedule for the
+// given table.
+func CreateRowLevelTTLScheduledJob(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	txn *kv.Txn,
+	owner security.SQLUsername,
+	tblID descpb.ID,
+	ttl *descpb.TableDescriptor_RowLevelTTL,
+) (*jobs.ScheduledJob, error) {
+	telemetry.Inc(sqltelemetry.RowLevelTTLCreated)
+	env := jobSchedulerEnv(execCfg)
+	j, err := newRowLevelTTLScheduledJob(env, owner, tblID, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if err := j.Create(ctx, execCfg.InternalExecutor, txn); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func checkTTLEnabledForCluster(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.RowLevelTTL) {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			"row level TTL is only available once the cluster is fully upgraded",
+		)
+	}
+	return nil
+}
+
 func hashShardedIndexesOnRegionalByRowError() error {
 	return pgerror.New(pgcode.FeatureNotSupported, "hash sharded indexes are not compatible with REGIONAL BY ROW tables")
 }
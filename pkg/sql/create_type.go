@@ -14,12 +14,15 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/enum"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
@@ -62,6 +65,20 @@ func (p *planner) CreateType(ctx context.Context, n *tree.CreateType) (planNode,
 	); err != nil {
 		return nil, err
 	}
+	// Nodes running older versions cannot decode the type descriptors of
+	// domains and composite types.
+	if n.Variety == tree.Domain &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.DomainTypes) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use domains",
+			clusterversion.DomainTypes)
+	}
+	if n.Variety == tree.Composite &&
+		!p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.CompositeTypes) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use composite types",
+			clusterversion.CompositeTypes)
+	}
 
 	// Resolve the desired new type name.
 	typeName, db, err := resolveNewTypeName(p.RunParams(ctx), n.TypeName)
@@ -105,6 +122,10 @@ func (n *createTypeNode) startExec(params runParams) error {
 	switch n.n.Variety {
 	case tree.Enum:
 		return params.p.createUserDefinedEnum(params, n)
+	case tree.Domain:
+		return params.p.createUserDefinedDomain(params, n)
+	case tree.Composite:
+		return params.p.createUserDefinedComposite(params, n)
 	default:
 		return unimplemented.NewWithIssue(25123, "CREATE TYPE")
 	}
@@ -210,7 +231,7 @@ func findFreeArrayTypeName(
 }
 
 // CreateEnumArrayTypeDesc creates a type descriptor for the array of the
-// given enum.
+// given enum, domain or composite type.
 func CreateEnumArrayTypeDesc(
 	params runParams,
 	typDesc *typedesc.Mutable,
@@ -225,6 +246,20 @@ func CreateEnumArrayTypeDesc(
 	switch t := typDesc.Kind; t {
 	case descpb.TypeDescriptor_ENUM, descpb.TypeDescriptor_MULTIREGION_ENUM:
 		elemTyp = types.MakeEnum(typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id))
+	case descpb.TypeDescriptor_DOMAIN:
+		elemTyp = types.MakeDomain(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), typDesc.DomainConfig.BaseType,
+		)
+	case descpb.TypeDescriptor_COMPOSITE:
+		contents := make([]*types.T, len(typDesc.CompositeConfig.Elements))
+		labels := make([]string, len(typDesc.CompositeConfig.Elements))
+		for i, elem := range typDesc.CompositeConfig.Elements {
+			contents[i] = elem.ElementType
+			labels[i] = elem.ElementLabel
+		}
+		elemTyp = types.MakeComposite(
+			typedesc.TypeIDToOID(typDesc.GetID()), typedesc.TypeIDToOID(id), contents, labels,
+		)
 	default:
 		return nil, errors.AssertionFailedf("cannot make array type for kind %s", t.String())
 	}
@@ -400,7 +435,18 @@ func (p *planner) createEnumWithID(
 	if err != nil {
 		return err
 	}
+	return p.createTypeDescWithArrayType(params, typeDesc, dbDesc, schema, typeName)
+}
 
+// createTypeDescWithArrayType creates the given new type descriptor along with
+// its implicit array type.
+func (p *planner) createTypeDescWithArrayType(
+	params runParams,
+	typeDesc *typedesc.Mutable,
+	dbDesc catalog.DatabaseDescriptor,
+	schema catalog.SchemaDescriptor,
+	typeName *tree.TypeName,
+) error {
 	// Create the implicit array type for this type before finishing the type.
 	arrayTypeID, err := p.createArrayType(params, typeName, typeDesc, dbDesc, schema.GetID())
 	if err != nil {
@@ -414,7 +460,7 @@ func (p *planner) createEnumWithID(
 	if err := p.createDescriptorWithID(
 		params.ctx,
 		catalogkeys.MakeObjectNameKey(params.ExecCfg().Codec, dbDesc.GetID(), schema.GetID(), typeName.Type()),
-		id,
+		typeDesc.GetID(),
 		typeDesc,
 		params.EvalContext().Settings,
 		typeName.String(),
@@ -430,6 +476,188 @@ func (p *planner) createEnumWithID(
 		})
 }
 
+func (p *planner) createUserDefinedDomain(params runParams, n *createTypeNode) error {
+	baseType, err := tree.ResolveType(params.ctx, n.n.DomainBaseType, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return err
+	}
+	switch {
+	case baseType.UserDefined():
+		return unimplemented.NewWithIssuef(27796,
+			"domains over user defined type %s are not supported", baseType.SQLString())
+	case baseType.Family() == types.ArrayFamily, baseType.Family() == types.TupleFamily:
+		return unimplemented.NewWithIssuef(27796,
+			"domains over type %s are not supported", baseType.SQLString())
+	case baseType.IsAmbiguous():
+		return pgerror.Newf(pgcode.DatatypeMismatch,
+			"%q is not a valid base type for a domain", baseType.SQLString())
+	}
+
+	config := &descpb.TypeDescriptor_DomainConfig{BaseType: baseType}
+	seenNull := false
+	for _, c := range n.n.DomainConstraints {
+		switch {
+		case c.Check != nil:
+			name := string(c.Name)
+			if name == "" {
+				name = generateDomainCheckName(n.typeName.Type(), config.Checks)
+			}
+			if err := addDomainCheck(params, config, name, c.Check); err != nil {
+				return err
+			}
+		case c.NotNull && seenNull, !c.NotNull && config.NotNull:
+			return pgerror.New(pgcode.Syntax, "conflicting NULL/NOT NULL constraints")
+		case c.NotNull:
+			config.NotNull = true
+		default:
+			seenNull = true
+		}
+	}
+
+	// Generate a key in the namespace table and a new id for this type.
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	privs := n.dbDesc.GetDefaultPrivilegeDescriptor().CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetID(),
+		params.p.User(), tree.Types, n.dbDesc.GetPrivileges(),
+	)
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:           n.typeName.Type(),
+		ID:             id,
+		ParentID:       n.dbDesc.GetID(),
+		ParentSchemaID: schema.GetID(),
+		Kind:           descpb.TypeDescriptor_DOMAIN,
+		DomainConfig:   config,
+		Version:        1,
+		Privileges:     privs,
+	}).BuildCreatedMutableType()
+	return p.createTypeDescWithArrayType(params, typeDesc, n.dbDesc, schema, n.typeName)
+}
+
+// addDomainCheck validates the given CHECK constraint expression of a domain
+// and adds it to the domain with the given name.
+func addDomainCheck(
+	params runParams, config *descpb.TypeDescriptor_DomainConfig, name string, expr tree.Expr,
+) error {
+	for i := range config.Checks {
+		if config.Checks[i].Name == name {
+			return pgerror.Newf(pgcode.DuplicateObject, "constraint %q already exists", name)
+		}
+	}
+	checkExpr, err := schemaexpr.ValidateDomainCheckExpr(
+		params.ctx, expr, config.BaseType, &params.p.semaCtx,
+	)
+	if err != nil {
+		return err
+	}
+	config.Checks = append(config.Checks, descpb.TypeDescriptor_DomainConfig_Check{
+		Name: name,
+		Expr: checkExpr,
+	})
+	return nil
+}
+
+// generateDomainCheckName generates a name for an unnamed CHECK constraint of
+// a domain which does not collide with the existing constraints. Like
+// Postgres, the name is <domain>_check, followed by a number if needed.
+func generateDomainCheckName(
+	domainName string, checks []descpb.TypeDescriptor_DomainConfig_Check,
+) string {
+	name := domainName + "_check"
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s%d", name, i)
+		}
+		inUse := false
+		for j := range checks {
+			if checks[j].Name == candidate {
+				inUse = true
+				break
+			}
+		}
+		if !inUse {
+			return candidate
+		}
+	}
+}
+
+func (p *planner) createUserDefinedComposite(params runParams, n *createTypeNode) error {
+	config := &descpb.TypeDescriptor_CompositeConfig{}
+	for _, elem := range n.n.CompositeTypeList {
+		for i := range config.Elements {
+			if config.Elements[i].ElementLabel == string(elem.Label) {
+				return pgerror.Newf(pgcode.DuplicateColumn,
+					"attribute %q specified more than once", elem.Label)
+			}
+		}
+		typ, err := p.resolveCompositeAttributeType(params.ctx, elem.Type)
+		if err != nil {
+			return err
+		}
+		config.Elements = append(config.Elements, descpb.TypeDescriptor_CompositeConfig_Element{
+			ElementType:  typ,
+			ElementLabel: string(elem.Label),
+		})
+	}
+
+	// Generate a key in the namespace table and a new id for this type.
+	schema, err := getCreateTypeParams(params, n.typeName, n.dbDesc)
+	if err != nil {
+		return err
+	}
+	id, err := catalogkv.GenerateUniqueDescID(
+		params.ctx, params.ExecCfg().DB, params.ExecCfg().Codec,
+	)
+	if err != nil {
+		return err
+	}
+
+	privs := n.dbDesc.GetDefaultPrivilegeDescriptor().CreatePrivilegesFromDefaultPrivileges(
+		n.dbDesc.GetID(),
+		params.p.User(), tree.Types, n.dbDesc.GetPrivileges(),
+	)
+	typeDesc := typedesc.NewBuilder(&descpb.TypeDescriptor{
+		Name:            n.typeName.Type(),
+		ID:              id,
+		ParentID:        n.dbDesc.GetID(),
+		ParentSchemaID:  schema.GetID(),
+		Kind:            descpb.TypeDescriptor_COMPOSITE,
+		CompositeConfig: config,
+		Version:         1,
+		Privileges:      privs,
+	}).BuildCreatedMutableType()
+	return p.createTypeDescWithArrayType(params, typeDesc, n.dbDesc, schema, n.typeName)
+}
+
+// resolveCompositeAttributeType resolves the type of an attribute of a
+// composite type, and checks that it can be stored in a table column.
+func (p *planner) resolveCompositeAttributeType(
+	ctx context.Context, ref tree.ResolvableTypeReference,
+) (*types.T, error) {
+	typ, err := tree.ResolveType(ctx, ref, p.semaCtx.GetTypeResolver())
+	if err != nil {
+		return nil, err
+	}
+	if typ.UserDefined() {
+		return nil, unimplemented.NewWithIssuef(27792,
+			"composite type attributes of user defined type %s are not supported", typ.SQLString())
+	}
+	if err := colinfo.ValidateColumnDefType(typ); err != nil {
+		return nil, err
+	}
+	return typ, nil
+}
+
 func (n *createTypeNode) Next(params runParams) (bool, error) { return false, nil }
 func (n *createTypeNode) Values() tree.Datums                 { return tree.Datums{} }
 func (n *createTypeNode) Close(ctx context.Context)           {}
@@ -223,11 +223,12 @@ func (n *createViewNode) startExec(params runParams) error {
 			// In particular,
 			// * mark the descriptor as a materialized view
 			// * mark the state as adding and remember the AsOf time to perform
-			//   the view query
+			//   the view query, which is also the time the data was last refreshed
 			// * use AllocateIDs to give the view descriptor a primary key
 			desc.IsMaterializedView = true
 			desc.State = descpb.DescriptorState_ADD
 			desc.CreateAsOfTime = params.p.Txn().ReadTimestamp()
+			desc.LastRefreshTime = desc.CreateAsOfTime
 			if err := desc.AllocateIDs(params.ctx); err != nil {
 				return err
 			}
@@ -0,0 +1,569 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// SetConstraints implements the SET CONSTRAINTS statement.
+// See https://www.postgresql.org/docs/current/sql-set-constraints.html for
+// details.
+func (p *planner) SetConstraints(ctx context.Context, n *tree.SetConstraints) (planNode, error) {
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if p.extendedEvalCtx.TxnImplicit {
+				p.BufferClientNotice(
+					ctx,
+					pgnotice.NewWithSeverityf(
+						"WARNING",
+						"SET CONSTRAINTS can only be used in transaction blocks",
+					),
+				)
+			}
+			for _, name := range n.Names {
+				if err := p.checkDeferrableConstraintExists(ctx, string(name)); err != nil {
+					return nil, err
+				}
+			}
+			p.deferredConstraints.setMode(n.All, n.Names, n.Deferred)
+			if !n.Deferred {
+				// The constraints which become immediate are validated right away.
+				if err := p.deferredConstraints.validatePending(
+					ctx, p.ExecCfg().InternalExecutor, p.txn, p.Descriptors(), false, /* all */
+				); err != nil {
+					return nil, err
+				}
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// checkDeferrableConstraintExists returns an error if there is no constraint
+// with the given name in the current database, or if none of the constraints
+// with that name is DEFERRABLE.
+func (p *planner) checkDeferrableConstraintExists(ctx context.Context, name string) error {
+	row, err := p.ExecCfg().InternalExecutor.QueryRowEx(
+		ctx, "check-deferrable-constraint", p.txn,
+		sessiondata.InternalExecutorOverride{User: p.User(), Database: p.CurrentDatabase()},
+		`SELECT bool_or(condeferrable) FROM pg_catalog.pg_constraint WHERE conname = $1`,
+		name,
+	)
+	if err != nil {
+		return err
+	}
+	if len(row) == 0 || row[0] == tree.DNull {
+		return pgerror.Newf(pgcode.UndefinedObject, "constraint %q does not exist", name)
+	}
+	if !bool(tree.MustBeDBool(row[0])) {
+		return pgerror.Newf(pgcode.WrongObjectType, "constraint %q is not deferrable", name)
+	}
+	return nil
+}
+
+// constraintMode is the checking mode of a DEFERRABLE constraint set by SET
+// CONSTRAINTS.
+type constraintMode int8
+
+const (
+	// constraintModeDefault means that the mode declared by the constraint
+	// (INITIALLY DEFERRED or INITIALLY IMMEDIATE) is used.
+	constraintModeDefault constraintMode = iota
+	constraintModeDeferred
+	constraintModeImmediate
+)
+
+// maxDeferredConstraintKeys is the maximum number of violating keys recorded
+// for a deferred constraint. Once more keys violated it, the whole constraint
+// is validated before the transaction commits instead.
+const maxDeferredConstraintKeys = 1000
+
+// deferredConstraints tracks the DEFERRABLE constraints of the current
+// transaction: the checking modes set by SET CONSTRAINTS, and the deferred
+// constraints that were violated by some statement of the transaction. The
+// keys which violated the latter must be checked again before the transaction
+// commits.
+//
+// A nil *deferredConstraints is used by the planners that aren't associated
+// with a session; all the constraints are checked immediately.
+type deferredConstraints struct {
+	// all is the mode set by the last SET CONSTRAINTS ALL statement.
+	all constraintMode
+	// modes contains the modes set by SET CONSTRAINTS for specific constraints
+	// since the last SET CONSTRAINTS ALL statement, keyed by constraint name.
+	modes map[string]constraintMode
+	// pending contains the deferred constraints that must be validated.
+	pending []pendingConstraint
+}
+
+// pendingConstraint is a deferred constraint which was violated by some
+// statement of the transaction.
+type pendingConstraint struct {
+	exec.DeferrableConstraint
+	// keys contains the distinct keys which violated the constraint. Only these
+	// keys are checked again, unless validateAll is set.
+	keys []tree.Datums
+	// keySet contains the string representations of keys.
+	keySet map[string]struct{}
+	// validateAll is set once more than maxDeferredConstraintKeys keys violated
+	// the constraint, in which case keys is cleared and all the rows of the
+	// table are validated.
+	validateAll bool
+}
+
+// isDeferred returns whether the checks of the given constraint are currently
+// deferred until the end of the transaction.
+func (d *deferredConstraints) isDeferred(c *exec.DeferrableConstraint) bool {
+	if d == nil {
+		return false
+	}
+	mode, ok := d.modes[c.Name]
+	if !ok {
+		mode = d.all
+	}
+	switch mode {
+	case constraintModeDeferred:
+		return true
+	case constraintModeImmediate:
+		return false
+	default:
+		return c.InitiallyDeferred
+	}
+}
+
+// addPending records that the given deferred constraint was violated by a
+// statement, with the key contained in the given row returned by its check.
+// The key must be checked again before the transaction commits. It returns
+// false if the whole constraint is going to be validated instead, in which
+// case the further violating keys need not be recorded.
+func (d *deferredConstraints) addPending(c *exec.DeferrableConstraint, row tree.Datums) bool {
+	var pc *pendingConstraint
+	for i := range d.pending {
+		if d.pending[i].TableID == c.TableID && d.pending[i].Name == c.Name {
+			pc = &d.pending[i]
+			break
+		}
+	}
+	if pc == nil {
+		d.pending = append(d.pending, pendingConstraint{DeferrableConstraint: *c})
+		pc = &d.pending[len(d.pending)-1]
+	}
+	if pc.validateAll {
+		return false
+	}
+	key := make(tree.Datums, len(c.KeyOrdinals))
+	for i, ord := range c.KeyOrdinals {
+		key[i] = row[ord]
+	}
+	keyStr := tree.AsString(&key)
+	if _, ok := pc.keySet[keyStr]; ok {
+		return true
+	}
+	if len(pc.keys) >= maxDeferredConstraintKeys {
+		pc.validateAll = true
+		pc.keys, pc.keySet = nil, nil
+		return false
+	}
+	if pc.keySet == nil {
+		pc.keySet = make(map[string]struct{})
+	}
+	pc.keySet[keyStr] = struct{}{}
+	pc.keys = append(pc.keys, key)
+	return true
+}
+
+// setMode applies a SET CONSTRAINTS statement.
+func (d *deferredConstraints) setMode(all bool, names tree.NameList, deferred bool) {
+	if d == nil {
+		return
+	}
+	mode := constraintModeImmediate
+	if deferred {
+		mode = constraintModeDeferred
+	}
+	if all {
+		d.all = mode
+		d.modes = nil
+		return
+	}
+	if d.modes == nil {
+		d.modes = make(map[string]constraintMode, len(names))
+	}
+	for _, name := range names {
+		d.modes[string(name)] = mode
+	}
+}
+
+// onTxnFinish is called when the transaction finishes or restarts. If it
+// restarted, the modes set by SET CONSTRAINTS are kept, since the statements
+// which set them are not necessarily retried.
+func (d *deferredConstraints) onTxnFinish(restarted bool) {
+	d.pending = nil
+	if !restarted {
+		d.all = constraintModeDefault
+		d.modes = nil
+	}
+}
+
+// validatePending validates the pending constraints. If all is false, only the
+// constraints which are no longer deferred are validated. The validated
+// constraints are removed from the pending list.
+func (d *deferredConstraints) validatePending(
+	ctx context.Context, ie *InternalExecutor, txn *kv.Txn, descsCol *descs.Collection, all bool,
+) error {
+	if d == nil || len(d.pending) == 0 {
+		return nil
+	}
+	var remaining []pendingConstraint
+	for i := range d.pending {
+		c := &d.pending[i]
+		if !all && d.isDeferred(&c.DeferrableConstraint) {
+			remaining = append(remaining, *c)
+			continue
+		}
+		if err := validateDeferredConstraint(ctx, ie, txn, descsCol, c); err != nil {
+			return err
+		}
+	}
+	d.pending = remaining
+	return nil
+}
+
+// validateDeferredConstraint verifies that the keys which violated the given
+// constraint no longer violate it, or that all the rows of the table satisfy
+// the constraint if too many keys violated it. It returns the same errors as
+// the checks performed by mutations.
+func validateDeferredConstraint(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	descsCol *descs.Collection,
+	c *pendingConstraint,
+) error {
+	flags := tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{AvoidCached: true}}
+	tbl, err := descsCol.GetImmutableTableByID(ctx, txn, descpb.ID(c.TableID), flags)
+	if err != nil {
+		return err
+	}
+	if tbl == nil || tbl.Dropped() {
+		// The table was dropped by the transaction.
+		return nil
+	}
+	for _, fk := range tbl.AllActiveAndInactiveForeignKeys() {
+		if fk.Name == c.Name {
+			target, err := descsCol.GetImmutableTableByID(ctx, txn, fk.ReferencedTableID, flags)
+			if err != nil {
+				return err
+			}
+			if c.validateAll {
+				return validateDeferredForeignKey(ctx, ie, txn, tbl, target, fk)
+			}
+			return validateDeferredForeignKeyKeys(ctx, ie, txn, tbl, target, fk, c.keys)
+		}
+	}
+	for i := range tbl.GetUniqueWithoutIndexConstraints() {
+		uc := &tbl.GetUniqueWithoutIndexConstraints()[i]
+		if uc.Name == c.Name {
+			if c.validateAll {
+				return validateDeferredUniqueConstraint(ctx, ie, txn, tbl, uc)
+			}
+			return validateDeferredUniqueConstraintKeys(ctx, ie, txn, tbl, uc, c.keys)
+		}
+	}
+	// The constraint was dropped by the transaction.
+	return nil
+}
+
+func validateDeferredForeignKey(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	srcTable, targetTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+) error {
+	nCols := len(fk.OriginColumnIDs)
+	if nCols > 1 && fk.Match == descpb.ForeignKeyReference_FULL {
+		query, _, err := matchFullUnacceptableKeyQuery(srcTable, fk, true /* limitResults */)
+		if err != nil {
+			return err
+		}
+		log.VEventf(ctx, 2, "validating deferred MATCH FULL FK %q with query %q", fk.Name, query)
+		values, err := ie.QueryRowEx(
+			ctx, "validate deferred fk", txn, sessiondata.NodeUserSessionDataOverride, query,
+		)
+		if err != nil {
+			return err
+		}
+		if values.Len() > 0 {
+			return mkDeferredFKErr(
+				srcTable, fk, "MATCH FULL does not allow mixing of null and nonnull key values.",
+			)
+		}
+	}
+
+	query, colNames, err := nonMatchingRowQuery(srcTable, fk, targetTable, true /* limitResults */)
+	if err != nil {
+		return err
+	}
+	log.VEventf(ctx, 2, "validating deferred FK %q with query %q", fk.Name, query)
+	values, err := ie.QueryRowEx(
+		ctx, "validate deferred fk", txn, sessiondata.NodeUserSessionDataOverride, query,
+	)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+	return mkDeferredFKNotPresentErr(srcTable, targetTable, fk, colNames[:nCols], values[:nCols])
+}
+
+// validateDeferredForeignKeyKeys verifies that the given keys, which violated
+// the foreign key constraint earlier in the transaction, no longer violate it:
+// either no row of the referencing table has the key anymore, or the
+// referenced table has a row with the key.
+func validateDeferredForeignKeyKeys(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	srcTable, targetTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	keys []tree.Datums,
+) error {
+	colNames, err := srcTable.NamesForColumnIDs(fk.OriginColumnIDs)
+	if err != nil {
+		return err
+	}
+	targetColNames, err := targetTable.NamesForColumnIDs(fk.ReferencedColumnIDs)
+	if err != nil {
+		return err
+	}
+	srcWhere := make([]string, len(colNames))
+	srcMixedNullsWhere := make([]string, len(colNames))
+	targetWhere := make([]string, len(colNames))
+	for i := range colNames {
+		srcWhere[i] = fmt.Sprintf("%s = $%d", tree.NameString(colNames[i]), i+1)
+		srcMixedNullsWhere[i] = fmt.Sprintf(
+			"%s IS NOT DISTINCT FROM $%d", tree.NameString(colNames[i]), i+1,
+		)
+		targetWhere[i] = fmt.Sprintf("%s = $%d", tree.NameString(targetColNames[i]), i+1)
+	}
+	query := fmt.Sprintf(
+		`SELECT 1 FROM [%[1]d AS src] WHERE %[2]s `+
+			`AND NOT EXISTS (SELECT 1 FROM [%[3]d AS target] WHERE %[4]s) LIMIT 1`,
+		srcTable.GetID(),                   // 1
+		strings.Join(srcWhere, " AND "),    // 2
+		targetTable.GetID(),                // 3
+		strings.Join(targetWhere, " AND "), // 4
+	)
+	mixedNullsQuery := fmt.Sprintf(
+		`SELECT 1 FROM [%d AS src] WHERE %s LIMIT 1`,
+		srcTable.GetID(), strings.Join(srcMixedNullsWhere, " AND "),
+	)
+	args := make([]interface{}, len(colNames))
+	for _, key := range keys {
+		numNulls := 0
+		for i := range key {
+			args[i] = key[i]
+			if key[i] == tree.DNull {
+				numNulls++
+			}
+		}
+		q := query
+		if numNulls > 0 {
+			// A key with NULLs can only violate a MATCH FULL constraint, and only
+			// if it also has non-NULL values.
+			if fk.Match != descpb.ForeignKeyReference_FULL || numNulls == len(key) {
+				continue
+			}
+			q = mixedNullsQuery
+		}
+		log.VEventf(ctx, 2, "validating deferred FK %q for key %v with query %q", fk.Name, key, q)
+		values, err := ie.QueryRowEx(
+			ctx, "validate deferred fk", txn, sessiondata.NodeUserSessionDataOverride, q, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if values.Len() == 0 {
+			continue
+		}
+		if numNulls > 0 {
+			return mkDeferredFKErr(
+				srcTable, fk, "MATCH FULL does not allow mixing of null and nonnull key values.",
+			)
+		}
+		return mkDeferredFKNotPresentErr(srcTable, targetTable, fk, colNames, key)
+	}
+	return nil
+}
+
+// mkDeferredFKNotPresentErr generates an error of the form:
+//   ERROR:  insert or update on table "child" violates foreign key constraint "foo"
+//   DETAIL: Key (child_p)=(2) is not present in table "parent".
+func mkDeferredFKNotPresentErr(
+	srcTable, targetTable catalog.TableDescriptor,
+	fk *descpb.ForeignKeyConstraint,
+	colNames []string,
+	key tree.Datums,
+) error {
+	var details bytes.Buffer
+	details.WriteString("Key (")
+	details.WriteString(strings.Join(colNames, ", "))
+	details.WriteString(")=(")
+	for i := range key {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(key[i].String())
+	}
+	details.WriteString(") is not present in table ")
+	lexbase.EncodeEscapedSQLIdent(&details, targetTable.GetName())
+	details.WriteByte('.')
+	return mkDeferredFKErr(srcTable, fk, details.String())
+}
+
+func mkDeferredFKErr(
+	srcTable catalog.TableDescriptor, fk *descpb.ForeignKeyConstraint, details string,
+) error {
+	var msg bytes.Buffer
+	msg.WriteString("insert or update on table ")
+	lexbase.EncodeEscapedSQLIdent(&msg, srcTable.GetName())
+	msg.WriteString(" violates foreign key constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, fk.Name)
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.ForeignKeyViolation, "%s", msg.String()),
+			fk.Name,
+		),
+		details,
+	)
+}
+
+func validateDeferredUniqueConstraint(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+) error {
+	query, colNames, err := duplicateRowQuery(
+		srcTable, uc.ColumnIDs, uc.Predicate, true, /* limitResults */
+	)
+	if err != nil {
+		return err
+	}
+	log.VEventf(ctx, 2, "validating deferred unique constraint %q with query %q", uc.Name, query)
+	values, err := ie.QueryRowEx(
+		ctx, "validate deferred unique constraint", txn, sessiondata.NodeUserSessionDataOverride, query,
+	)
+	if err != nil {
+		return err
+	}
+	if values.Len() == 0 {
+		return nil
+	}
+	return mkDeferredUniqueErr(uc, colNames, values)
+}
+
+// validateDeferredUniqueConstraintKeys verifies that the given keys, which
+// violated the unique constraint earlier in the transaction, no longer violate
+// it, i.e. that at most one row of the table has each of the keys.
+func validateDeferredUniqueConstraintKeys(
+	ctx context.Context,
+	ie *InternalExecutor,
+	txn *kv.Txn,
+	srcTable catalog.TableDescriptor,
+	uc *descpb.UniqueWithoutIndexConstraint,
+	keys []tree.Datums,
+) error {
+	colNames, err := srcTable.NamesForColumnIDs(uc.ColumnIDs)
+	if err != nil {
+		return err
+	}
+	where := make([]string, 0, len(colNames)+1)
+	for i := range colNames {
+		where = append(where, fmt.Sprintf("%s = $%d", tree.NameString(colNames[i]), i+1))
+	}
+	if uc.Predicate != "" {
+		where = append(where, fmt.Sprintf("(%s)", uc.Predicate))
+	}
+	query := fmt.Sprintf(
+		`SELECT count(*) FROM (SELECT 1 FROM [%d AS tbl] WHERE %s LIMIT 2)`,
+		srcTable.GetID(), strings.Join(where, " AND "),
+	)
+	args := make([]interface{}, len(colNames))
+	for _, key := range keys {
+		for i := range key {
+			args[i] = key[i]
+		}
+		log.VEventf(ctx, 2, "validating deferred unique constraint %q for key %v with query %q",
+			uc.Name, key, query)
+		values, err := ie.QueryRowEx(
+			ctx, "validate deferred unique constraint", txn, sessiondata.NodeUserSessionDataOverride,
+			query, args...,
+		)
+		if err != nil {
+			return err
+		}
+		if tree.MustBeDInt(values[0]) > 1 {
+			return mkDeferredUniqueErr(uc, colNames, key)
+		}
+	}
+	return nil
+}
+
+// mkDeferredUniqueErr generates an error of the form:
+//   ERROR:  duplicate key value violates unique constraint "foo"
+//   DETAIL: Key (k)=(2) already exists.
+func mkDeferredUniqueErr(
+	uc *descpb.UniqueWithoutIndexConstraint, colNames []string, key tree.Datums,
+) error {
+	var msg, details bytes.Buffer
+	msg.WriteString("duplicate key value violates unique constraint ")
+	lexbase.EncodeEscapedSQLIdent(&msg, uc.Name)
+	details.WriteString("Key (")
+	details.WriteString(strings.Join(colNames, ", "))
+	details.WriteString(")=(")
+	for i := range key {
+		if i > 0 {
+			details.WriteString(", ")
+		}
+		details.WriteString(key[i].String())
+	}
+	details.WriteString(") already exists.")
+	return errors.WithDetail(
+		pgerror.WithConstraintName(
+			pgerror.Newf(pgcode.UniqueViolation, "%s", msg.String()),
+			uc.Name,
+		),
+		details.String(),
+	)
+}
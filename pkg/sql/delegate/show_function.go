@@ -0,0 +1,55 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package delegate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+)
+
+func (d *delegator) delegateShowCreateFunction(n *tree.ShowCreateFunction) (tree.Statement, error) {
+	sqltelemetry.IncrementShowCounter(sqltelemetry.Create)
+
+	const showCreateQuery = `
+SELECT
+	function_name,
+	create_statement
+FROM
+	%[1]s.crdb_internal.create_function_statements
+WHERE
+	function_id IN (%[2]s)
+ORDER BY
+	function_id`
+
+	def, err := d.catalog.ResolveFunction(d.ctx, n.Name.ToUnresolvedName(), d.evalCtx.SessionData().SearchPath)
+	if err != nil {
+		return nil, err
+	}
+	if !def.IsUDF() {
+		return nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%s is a built-in function", tree.ErrString(n.Name))
+	}
+	dbName, err := d.getSpecifiedOrCurrentDatabase(tree.Name(n.Name.Catalog()))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(def.Definition))
+	for i, o := range def.Definition {
+		ids[i] = fmt.Sprintf("%d", funcdesc.OIDToFuncID(o.(*tree.Overload).Oid))
+	}
+	return parse(fmt.Sprintf(showCreateQuery, dbName.String(), strings.Join(ids, ", ")))
+}
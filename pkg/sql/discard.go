@@ -40,6 +40,15 @@ func (p *planner) Discard(ctx context.Context, s *tree.Discard) (planNode, error
 
 		// DEALLOCATE ALL
 		p.preparedStatements.DeleteAll(ctx)
+
+		// CLOSE ALL
+		p.sqlCursors.closeAll(ctx)
+
+		// UNLISTEN *
+		p.sqlListeners.unlistenAll()
+
+		// SELECT pg_advisory_unlock_all()
+		p.advisoryLocks.releaseAll(ctx)
 	default:
 		return nil, errors.AssertionFailedf("unknown mode for DISCARD: %d", s.Mode)
 	}
@@ -84,6 +84,8 @@ var aggregateFuncToNumArguments = map[execinfrapb.AggregatorSpec_Func]int{
 	execinfrapb.RegrCount:          2,
 	execinfrapb.RegrAvgx:           2,
 	execinfrapb.RegrAvgy:           2,
+	execinfrapb.RangeAgg:           1,
+	execinfrapb.RangeIntersectAgg:  1,
 }
 
 // TestAggregateFuncToNumArguments ensures that all aggregate functions are
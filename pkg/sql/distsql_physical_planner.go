@@ -2920,7 +2920,13 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 		plan, err = dsp.createPlanForExport(planCtx, n)
 
 	case *filterNode:
-		plan, err = dsp.createPhysPlanForPlanNode(planCtx, n.source.plan)
+		if scan, ok := n.source.plan.(*scanNode); ok && scan.desc.IsForeignTable() {
+			// Pass the filter to the foreign scans, so that they can skip the
+			// parts of the files which don't contain any matching row.
+			plan, err = dsp.createForeignScans(planCtx, scan, n.filter)
+		} else {
+			plan, err = dsp.createPhysPlanForPlanNode(planCtx, n.source.plan)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -2984,7 +2990,11 @@ func (dsp *DistSQLPlanner) createPhysPlanForPlanNode(
 		}
 
 	case *scanNode:
-		plan, err = dsp.createTableReaders(planCtx, n)
+		if n.desc.IsForeignTable() {
+			plan, err = dsp.createForeignScans(planCtx, n, nil /* filter */)
+		} else {
+			plan, err = dsp.createTableReaders(planCtx, n)
+		}
 
 	case *sortNode:
 		plan, err = dsp.createPhysPlanForPlanNode(planCtx, n.plan)
@@ -26,13 +26,33 @@ func (dsp *DistSQLPlanner) SetupAllNodesPlanning(
 	distribute := evalCtx.Codec.ForSystemTenant()
 	planCtx := dsp.NewPlanningCtx(ctx, evalCtx, nil /* planner */, nil /* txn */, distribute)
 
+	nodes, err := dsp.healthyNodes(ctx, planCtx, execCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Shuffle node order so that multiple IMPORTs done in parallel will not
+	// identically schedule CSV reading. For example, if there are 3 nodes and 4
+	// files, the first node will get 2 files while the other nodes will each get 1
+	// file. Shuffling will make that first node random instead of always the same.
+	rand.Shuffle(len(nodes), func(i, j int) {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	})
+	return planCtx, nodes, nil
+}
+
+// healthyNodes returns the nodes which are healthy and running a compatible
+// version, according to the planCtx. If the nodes can't be listed, as is the
+// case for secondary tenants, only the gateway node is returned.
+func (dsp *DistSQLPlanner) healthyNodes(
+	ctx context.Context, planCtx *PlanningCtx, execCfg *ExecutorConfig,
+) ([]roachpb.NodeID, error) {
 	ss, err := execCfg.NodesStatusServer.OptionalNodesStatusServer(47900)
 	if err != nil {
-		return planCtx, []roachpb.NodeID{dsp.gatewayNodeID}, nil //nolint:returnerrcheck
+		return []roachpb.NodeID{dsp.gatewayNodeID}, nil //nolint:returnerrcheck
 	}
 	resp, err := ss.ListNodesInternal(ctx, &serverpb.NodesRequest{})
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	// Because we're not going through the normal pathways, we have to set up the
 	// planCtx.NodeStatuses map ourselves. CheckNodeHealthAndVersion() will
@@ -46,12 +66,5 @@ func (dsp *DistSQLPlanner) SetupAllNodesPlanning(
 			nodes = append(nodes, nodeID)
 		}
 	}
-	// Shuffle node order so that multiple IMPORTs done in parallel will not
-	// identically schedule CSV reading. For example, if there are 3 nodes and 4
-	// files, the first node will get 2 files while the other nodes will each get 1
-	// file. Shuffling will make that first node random instead of always the same.
-	rand.Shuffle(len(nodes), func(i, j int) {
-		nodes[i], nodes[j] = nodes[j], nodes[i]
-	})
-	return planCtx, nodes, nil
+	return nodes, nil
 }
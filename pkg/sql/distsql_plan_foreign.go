@@ -0,0 +1,130 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"sort"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/physicalplan"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// createForeignScans generates a plan consisting of foreign scan processors,
+// which read the rows of a foreign table from its files. The files are
+// distributed among the nodes of the cluster, unless the plan is local or the
+// scan has a hard limit, in which case they are all read on the gateway.
+//
+// filter is an optional filter on the columns of the scanNode, which the
+// processors may use to skip parts of the files which don't contain any
+// matching row. It must still be applied to the rows of the scan.
+func (dsp *DistSQLPlanner) createForeignScans(
+	planCtx *PlanningCtx, n *scanNode, filter tree.TypedExpr,
+) (*PhysicalPlan, error) {
+	if n.reverse {
+		return nil, errors.AssertionFailedf("foreign tables can't be scanned in reverse")
+	}
+	ctx := planCtx.ctx
+	evalCtx := planCtx.ExtendedEvalCtx
+	foreign := n.desc.GetForeignTable()
+	_, dbDesc, err := evalCtx.Descs.GetImmutableDatabaseByID(
+		ctx, evalCtx.Txn, n.desc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	server, ok := dbDesc.GetServer(foreign.Server)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"server %q of foreign table %q does not exist", foreign.Server, n.desc.GetName())
+	}
+	format, err := makeForeignTableFormat(foreign.Options)
+	if err != nil {
+		return nil, err
+	}
+	user := evalCtx.SessionData().User()
+	files, err := foreignTableFiles(ctx, evalCtx.ExecCfg, user, server, foreign)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, pgerror.Newf(pgcode.UndefinedFile,
+			"no files found for foreign table %q", n.desc.GetName())
+	}
+
+	nodes := []roachpb.NodeID{dsp.gatewayNodeID}
+	if !planCtx.isLocal && n.hardLimit == 0 {
+		if nodes, err = dsp.healthyNodes(ctx, planCtx, evalCtx.ExecCfg); err != nil {
+			return nil, err
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+	}
+	if len(nodes) > len(files) {
+		nodes = nodes[:len(files)]
+	}
+
+	// scanNodeToTableOrdinalMap is a map from scan node column ordinal to
+	// foreign scan column ordinal.
+	scanNodeToTableOrdinalMap := toTableOrdinals(n.cols, n.desc, n.colCfg.visibility)
+	var filterExpr execinfrapb.Expression
+	if filter != nil {
+		if filterExpr, err = physicalplan.MakeExpression(
+			filter, planCtx, scanNodeToTableOrdinalMap,
+		); err != nil {
+			return nil, err
+		}
+	}
+	var post execinfrapb.PostProcessSpec
+	if n.hardLimit != 0 {
+		post.Limit = uint64(n.hardLimit)
+	}
+
+	corePlacement := make([]physicalplan.ProcessorCorePlacement, len(nodes))
+	for i, nodeID := range nodes {
+		spec := &execinfrapb.ForeignScanSpec{
+			Table:         *n.desc.TableDesc(),
+			Uri:           make(map[int32]string),
+			Format:        format,
+			NeededColumns: n.colCfg.wantedColumnsOrdinals,
+			Spans:         n.spans,
+			Filter:        filterExpr,
+			Ordered:       len(n.reqOrdering) > 0,
+			UserProto:     user.EncodeProto(),
+		}
+		for j := i; j < len(files); j += len(nodes) {
+			spec.Uri[int32(j)] = files[j]
+		}
+		corePlacement[i].NodeID = nodeID
+		corePlacement[i].EstimatedRowCount = n.estimatedRowCount
+		corePlacement[i].Core.ForeignScan = spec
+	}
+
+	p := planCtx.NewPhysicalPlan()
+	typs := catalog.ColumnTypes(n.desc.PublicColumns())
+	// Note: we will set a merge ordering below.
+	p.AddNoInputStage(corePlacement, post, typs, execinfrapb.Ordering{})
+
+	// The foreign scan processors output all the public columns of the table,
+	// so the columns of the scanNode are simply projected.
+	outCols := getOutputColumnsFromColsForScan(n.cols, scanNodeToTableOrdinalMap)
+	planToStreamColMap := make([]int, len(n.cols))
+	for i := range planToStreamColMap {
+		planToStreamColMap[i] = i
+	}
+	p.AddProjection(outCols, dsp.convertOrdering(n.reqOrdering, planToStreamColMap))
+	p.PlanToStreamColMap = planToStreamColMap
+	return p, nil
+}
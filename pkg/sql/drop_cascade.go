@@ -17,6 +17,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
@@ -36,6 +37,7 @@ type dropCascadeState struct {
 	toDeleteByID            map[descpb.ID]*toDelete
 	allTableObjectsToDelete []*tabledesc.Mutable
 	typesToDelete           []*typedesc.Mutable
+	functionsToDelete       []functionWithDbDesc
 
 	droppedNames []string
 }
@@ -45,6 +47,11 @@ type schemaWithDbDesc struct {
 	dbDesc *dbdesc.Mutable
 }
 
+type functionWithDbDesc struct {
+	fn     *funcdesc.Mutable
+	dbDesc *dbdesc.Mutable
+}
+
 func newDropCascadeState() *dropCascadeState {
 	return &dropCascadeState{
 		// We ensure droppedNames is not nil when creating the dropCascadeState.
@@ -66,6 +73,22 @@ func (d *dropCascadeState) collectObjectsInSchema(
 	for i := range names {
 		d.objectNamesToDelete = append(d.objectNamesToDelete, &names[i])
 	}
+	if err := db.ForEachFunctionOverload(func(
+		_ string, overload descpb.DatabaseDescriptor_FunctionOverload,
+	) error {
+		if overload.SchemaID != schema.GetID() {
+			return nil
+		}
+		fn, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, overload.ID,
+			tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+		if err != nil {
+			return err
+		}
+		d.functionsToDelete = append(d.functionsToDelete, functionWithDbDesc{fn: fn, dbDesc: db})
+		return nil
+	}); err != nil {
+		return err
+	}
 	d.schemasToDelete = append(d.schemasToDelete, schemaWithDbDesc{schema: schema, dbDesc: db})
 	return nil
 }
@@ -219,9 +242,21 @@ func (d *dropCascadeState) dropAllCollectedObjects(ctx context.Context, p *plann
 		}
 	}
 
+	// Finally, delete all of the functions.
+	for _, f := range d.functionsToDelete {
+		if err := p.dropFunctionImpl(ctx, f.dbDesc, f.fn); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// isEmpty returns whether no objects were collected for deletion.
+func (d *dropCascadeState) isEmpty() bool {
+	return len(d.objectNamesToDelete) == 0 && len(d.functionsToDelete) == 0
+}
+
 func (d *dropCascadeState) canDropType(
 	ctx context.Context, p *planner, typ *typedesc.Mutable,
 ) error {
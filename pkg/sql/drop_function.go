@@ -0,0 +1,206 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+type dropFunctionNode struct {
+	n      *tree.DropFunction
+	toDrop []functionWithDbDesc
+}
+
+var _ planNode = &dropFunctionNode{n: nil}
+
+// DropFunction drops user-defined functions or procedures.
+// Privileges: ownership of the functions.
+func (p *planner) DropFunction(ctx context.Context, n *tree.DropFunction) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		n.StatementTag(),
+	); err != nil {
+		return nil, err
+	}
+	kind := routineKind(n.IsProcedure)
+
+	node := &dropFunctionNode{n: n}
+	seen := make(map[descpb.ID]struct{})
+	for i := range n.Functions {
+		fn, db, err := p.resolveFunctionForDrop(ctx, &n.Functions[i], n.IsProcedure, n.IfExists)
+		if err != nil {
+			return nil, err
+		}
+		if fn == nil {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"%s %s does not exist, skipping", kind, tree.AsString(&n.Functions[i])))
+			continue
+		}
+		if _, ok := seen[fn.GetID()]; ok {
+			continue
+		}
+		seen[fn.GetID()] = struct{}{}
+		hasOwnership, err := p.HasOwnership(ctx, fn)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of %s %s", kind, fn.GetName())
+		}
+		node.toDrop = append(node.toDrop, functionWithDbDesc{fn: fn, dbDesc: db})
+	}
+	return node, nil
+}
+
+// resolveFunctionForDrop resolves the function referenced by a DROP FUNCTION
+// or DROP PROCEDURE statement. If the argument types are not specified, the
+// function name must be unique. If the function does not exist and ifExists is
+// true, a nil descriptor is returned.
+func (p *planner) resolveFunctionForDrop(
+	ctx context.Context, obj *tree.FuncObj, isProcedure bool, ifExists bool,
+) (*funcdesc.Mutable, *dbdesc.Mutable, error) {
+	kind := routineKind(isProcedure)
+	name := obj.FuncName.ToUnresolvedName()
+	fns, err := p.resolveUserDefinedFunctions(ctx, name, p.SessionData().SearchPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var match catalog.FunctionDescriptor
+	if obj.Args == nil {
+		if len(fns) > 1 {
+			return nil, nil, errors.WithHint(
+				pgerror.Newf(pgcode.AmbiguousFunction,
+					"%s name %q is not unique", kind, tree.ErrString(obj.FuncName)),
+				"Specify the argument list to select the function unambiguously.")
+		}
+		if len(fns) == 1 {
+			match = fns[0]
+		}
+	} else {
+		argTypes := make([]*types.T, len(obj.Args))
+		for i := range obj.Args {
+			typ, err := tree.ResolveType(ctx, obj.Args[i].Type, p.semaCtx.GetTypeResolver())
+			if err != nil {
+				return nil, nil, err
+			}
+			argTypes[i] = typ
+		}
+		for _, fn := range fns {
+			if sameArgTypes(fn.ArgTypes(), argTypes) {
+				match = fn
+				break
+			}
+		}
+	}
+	if match == nil {
+		if ifExists {
+			return nil, nil, nil
+		}
+		if isProcedure {
+			return nil, nil, pgerror.Newf(pgcode.UndefinedFunction,
+				"procedure %s does not exist", tree.AsString(obj))
+		}
+		return nil, nil, sqlerrors.NewUndefinedFunctionError(tree.AsString(obj))
+	}
+	if match.GetIsProcedure() != isProcedure {
+		return nil, nil, pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not a %s", tree.AsString(obj), kind)
+	}
+
+	fn, err := p.Descriptors().GetMutableFunctionByID(ctx, p.txn, match.GetID(),
+		tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}})
+	if err != nil {
+		return nil, nil, err
+	}
+	db, err := p.Descriptors().GetMutableDescriptorByID(ctx, fn.GetParentID(), p.txn)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbDesc, ok := db.(*dbdesc.Mutable)
+	if !ok {
+		return nil, nil, errors.AssertionFailedf("expected database descriptor, found %T", db)
+	}
+	return fn, dbDesc, nil
+}
+
+// routineKind returns the name of the kind of a user-defined function for
+// messages.
+func routineKind(isProcedure bool) string {
+	if isProcedure {
+		return "procedure"
+	}
+	return "function"
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP FUNCTION performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropFunctionNode) ReadingOwnWrites() {}
+
+func (n *dropFunctionNode) startExec(params runParams) error {
+	for _, f := range n.toDrop {
+		if err := params.p.dropFunctionImpl(params.ctx, f.dbDesc, f.fn); err != nil {
+			return err
+		}
+		if err := params.p.writeNonDropDatabaseChange(
+			params.ctx, f.dbDesc,
+			fmt.Sprintf("updating database %s for dropped function %s", f.dbDesc.GetName(), f.fn.GetName()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*dropFunctionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropFunctionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropFunctionNode) Close(context.Context)        {}
+
+// dropFunctionImpl drops the given function and removes it from the functions
+// mapping of its parent database. It is the responsibility of the caller to
+// write the database descriptor.
+func (p *planner) dropFunctionImpl(
+	ctx context.Context, db *dbdesc.Mutable, fn *funcdesc.Mutable,
+) error {
+	if fn.Dropped() {
+		return nil
+	}
+	// Write the dropped version of the descriptor so that it is known to the
+	// descriptor collection before deleting it, since functions do not need
+	// any asynchronous cleanup.
+	fn.SetDropped()
+	if err := p.Descriptors().WriteDesc(
+		ctx, p.ExtendedEvalContext().Tracing.KVTracingEnabled(), fn, p.txn,
+	); err != nil {
+		return err
+	}
+	if err := p.txn.Del(ctx, catalogkeys.MakeDescMetadataKey(p.ExecCfg().Codec, fn.GetID())); err != nil {
+		return err
+	}
+	db.RemoveFunction(fn.GetName(), fn.GetID())
+	return nil
+}
@@ -72,6 +72,9 @@ func (p *planner) DropTable(ctx context.Context, n *tree.DropTable) (planNode, e
 		if droppedDesc == nil {
 			continue
 		}
+		if err := checkTableMatchesForeign(droppedDesc, n.IsForeign); err != nil {
+			return nil, err
+		}
 
 		td[droppedDesc.ID] = toDelete{tn, droppedDesc}
 	}
@@ -300,6 +303,13 @@ func (p *planner) dropTableImpl(
 		droppedViews = append(droppedViews, qualifiedView.FQString())
 	}
 
+	// Remove the row-level TTL schedule, if one exists.
+	if ttl := tableDesc.GetRowLevelTTL(); ttl != nil {
+		if err := p.deleteRowLevelTTLSchedule(ctx, ttl); err != nil {
+			return droppedViews, err
+		}
+	}
+
 	err := p.removeTableComments(ctx, tableDesc)
 	if err != nil {
 		return droppedViews, err
@@ -325,6 +335,28 @@ func (p *planner) dropTableImpl(
 	return droppedViews, err
 }
 
+// deleteRowLevelTTLSchedule deletes the schedule of the given row-level TTL
+// configuration.
+func (p *planner) deleteRowLevelTTLSchedule(
+	ctx context.Context, ttl *descpb.TableDescriptor_RowLevelTTL,
+) error {
+	env := jobSchedulerEnv(p.ExecCfg())
+	if _, err := p.ExecCfg().InternalExecutor.ExecEx(
+		ctx,
+		"delete-row-level-ttl-schedule",
+		p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		fmt.Sprintf(
+			"DELETE FROM %s WHERE schedule_id = $1",
+			env.ScheduledJobsTableName(),
+		),
+		ttl.ScheduleID,
+	); err != nil {
+		return errors.Wrapf(err, "error deleting row-level TTL schedule %d", ttl.ScheduleID)
+	}
+	return nil
+}
+
 // unsplitRangesForTable unsplit any manually split ranges within the table span.
 func (p *planner) unsplitRangesForTable(ctx context.Context, tableDesc *tabledesc.Mutable) error {
 	// Gate this on being the system tenant because secondary tenants aren't
@@ -65,6 +65,15 @@ func (p *planner) DropType(ctx context.Context, n *tree.DropType) (planNode, err
 		if _, ok := node.toDrop[typeDesc.ID]; ok {
 			continue
 		}
+		isDomain := typeDesc.Kind == descpb.TypeDescriptor_DOMAIN
+		if n.IsDomain && !isDomain {
+			return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name)
+		}
+		if !n.IsDomain && isDomain {
+			return nil, errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%q is not a type", name),
+				"Use DROP DOMAIN to remove a domain.")
+		}
 		switch typeDesc.Kind {
 		case descpb.TypeDescriptor_ALIAS:
 			// The implicit array types are not directly droppable.
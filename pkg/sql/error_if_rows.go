@@ -26,6 +26,11 @@ type errorIfRowsNode struct {
 	// produced.
 	mkErr exec.MkErrFn
 
+	// deferrable is set if the node checks a DEFERRABLE constraint. If the
+	// constraint is deferred in the current transaction, the violating keys are
+	// recorded in the transaction state instead of causing an error.
+	deferrable *exec.DeferrableConstraint
+
 	nexted bool
 }
 
@@ -43,9 +48,24 @@ func (n *errorIfRowsNode) Next(params runParams) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	if ok {
+	if !ok {
+		return false, nil
+	}
+	if n.deferrable == nil || !params.p.deferredConstraints.isDeferred(n.deferrable) {
 		return false, n.mkErr(n.plan.Values())
 	}
+	// Record all the violating keys, which are checked again before the
+	// transaction commits.
+	for ok {
+		if !params.p.deferredConstraints.addPending(n.deferrable, n.plan.Values()) {
+			// Too many keys were recorded; the whole constraint is validated
+			// instead.
+			break
+		}
+		if ok, err = n.plan.Next(params); err != nil {
+			return false, err
+		}
+	}
 	return false, nil
 }
 
@@ -63,6 +63,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/execinfrapb"
 	"github.com/cockroachdb/cockroach/pkg/sql/gcjob/gcjobnotifier"
 	"github.com/cockroachdb/cockroach/pkg/sql/idxusage"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/optionalnodeliveness"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
@@ -188,6 +189,27 @@ var secondaryTenantZoneConfigsEnabled = settings.RegisterBoolSetting(
 	false,
 )
 
+// readCommittedIsolationEnabled controls whether transactions can run under
+// the READ COMMITTED isolation level. When disabled, transactions requesting
+// READ COMMITTED (or READ UNCOMMITTED) are upgraded to SERIALIZABLE.
+var readCommittedIsolationEnabled = settings.RegisterBoolSetting(
+	"sql.txn.read_committed_isolation.enabled",
+	"if true, transactions may use the READ COMMITTED isolation level; otherwise "+
+		"READ COMMITTED transactions are upgraded to SERIALIZABLE",
+	false,
+).WithPublic()
+
+// readCommittedMaxStmtRetries is the number of times a statement of a READ
+// COMMITTED transaction is retried after a conflict before the retry error is
+// returned to the transaction.
+var readCommittedMaxStmtRetries = settings.RegisterIntSetting(
+	"sql.txn.read_committed_isolation.max_statement_retries",
+	"the number of times a statement in a READ COMMITTED transaction is "+
+		"retried on a write-write conflict before the transaction is restarted",
+	10,
+	settings.NonNegativeInt,
+)
+
 // traceTxnThreshold can be used to log SQL transactions that take
 // longer than duration to complete. For example, traceTxnThreshold=1s
 // will log the trace for any transaction that takes 1s or longer. To
@@ -465,6 +487,12 @@ var propagateInputOrdering = settings.RegisterBoolSetting(
 	false,
 )
 
+var indexRecommendationsEnabled = settings.RegisterBoolSetting(
+	`sql.defaults.index_recommendations.enabled`,
+	`default value for the index_recommendations_enabled session variable`,
+	false,
+)
+
 // settingWorkMemBytes is a cluster setting that determines the maximum amount
 // of RAM that a processor can use.
 var settingWorkMemBytes = settings.RegisterByteSizeSetting(
@@ -1087,6 +1115,7 @@ type ExecutorConfig struct {
 	IndexUsageStatsTestingKnobs   *idxusage.TestingKnobs
 	SQLStatsTestingKnobs          *sqlstats.TestingKnobs
 	TelemetryLoggingTestingKnobs  *TelemetryLoggingTestingKnobs
+	TTLTestingKnobs               *TTLTestingKnobs
 	// HistogramWindowInterval is (server.Config).HistogramWindowInterval.
 	HistogramWindowInterval time.Duration
 
@@ -1107,6 +1136,10 @@ type ExecutorConfig struct {
 	// StmtDiagnosticsRecorder deals with recording statement diagnostics.
 	StmtDiagnosticsRecorder *stmtdiagnostics.Registry
 
+	// NotificationRegistry delivers the notifications sent with NOTIFY to the
+	// sessions which executed LISTEN.
+	NotificationRegistry *notify.Registry
+
 	ExternalIODirConfig base.ExternalIODirConfig
 
 	GCJobNotifier *gcjobnotifier.Notifier
@@ -1368,6 +1401,17 @@ var _ base.ModuleTestingKnobs = &BackupRestoreTestingKnobs{}
 // ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
 func (*BackupRestoreTestingKnobs) ModuleTestingKnobs() {}
 
+// TTLTestingKnobs contains knobs for the jobs of row-level TTL.
+type TTLTestingKnobs struct {
+	// AOSTDuration overrides how far in the past the expired rows are read.
+	AOSTDuration *time.Duration
+}
+
+var _ base.ModuleTestingKnobs = &TTLTestingKnobs{}
+
+// ModuleTestingKnobs implements the base.ModuleTestingKnobs interface.
+func (*TTLTestingKnobs) ModuleTestingKnobs() {}
+
 // StreamingTestingKnobs contains knobs for streaming behavior.
 type StreamingTestingKnobs struct {
 	// RunAfterReceivingEvent allows blocking the stream ingestion processor after
@@ -1562,6 +1606,10 @@ func checkResultType(typ *types.T) error {
 	case types.OidFamily:
 	case types.TupleFamily:
 	case types.EnumFamily:
+	case types.TSQueryFamily:
+	case types.TSVectorFamily:
+	case types.JsonpathFamily:
+	case types.RangeFamily:
 	case types.ArrayFamily:
 		if typ.ArrayContents().Family() == types.ArrayFamily {
 			// Technically we could probably return arrays of arrays to a
@@ -1734,6 +1782,9 @@ type SessionArgs struct {
 	// client.
 	RemoteAddr            net.Addr
 	ConnResultsBufferSize int64
+	// Replication is set for the replication connections, which accept the
+	// commands of the streaming replication protocol in addition to SQL.
+	Replication bool
 }
 
 // SessionRegistry stores a set of all sessions on this node.
@@ -2663,6 +2714,10 @@ func (m *sessionDataMutator) SetDefaultTransactionPriority(val tree.UserPriority
 	m.data.DefaultTxnPriority = int64(val)
 }
 
+func (m *sessionDataMutator) SetDefaultTransactionIsolationLevel(val tree.IsolationLevel) {
+	m.data.DefaultTxnIsolationLevel = int64(val)
+}
+
 func (m *sessionDataMutator) SetDefaultTransactionReadOnly(val bool) {
 	m.data.DefaultTxnReadOnly = val
 }
@@ -2910,6 +2965,14 @@ func (m *sessionDataMutator) SetExperimentalComputedColumnRewrites(val string) {
 	m.data.ExperimentalComputedColumnRewrites = val
 }
 
+func (m *sessionDataMutator) SetDefaultTextSearchConfig(name string) {
+	m.data.DefaultTextSearchConfig = name
+}
+
+func (m *sessionDataMutator) SetTrigramSimilarityThreshold(val float64) {
+	m.data.TrigramSimilarityThreshold = val
+}
+
 func (m *sessionDataMutator) SetNullOrderedLast(b bool) {
 	m.data.NullOrderedLast = b
 }
@@ -2942,6 +3005,10 @@ func (m *sessionDataMutator) SetInjectRetryErrorsEnabled(val bool) {
 	m.data.InjectRetryErrorsEnabled = val
 }
 
+func (m *sessionDataMutator) SetIndexRecommendationsEnabled(val bool) {
+	m.data.IndexRecommendationsEnabled = val
+}
+
 // Utility functions related to scrubbing sensitive information on SQL Stats.
 
 // quantizeCounts ensures that the Count field in the
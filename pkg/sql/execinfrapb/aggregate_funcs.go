@@ -58,4 +58,6 @@ const (
 	RegrCount          = AggregatorSpec_REGR_COUNT
 	RegrAvgx           = AggregatorSpec_REGR_AVGX
 	RegrAvgy           = AggregatorSpec_REGR_AVGY
+	RangeAgg           = AggregatorSpec_RANGE_AGG
+	RangeIntersectAgg  = AggregatorSpec_RANGE_INTERSECT_AGG
 )
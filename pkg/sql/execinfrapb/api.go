@@ -82,6 +82,11 @@ func (m *ReadImportDataSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
 }
 
+// User accesses the user field.
+func (m *ForeignScanSpec) User() security.SQLUsername {
+	return m.UserProto.Decode()
+}
+
 // User accesses the user field.
 func (m *ChangeAggregatorSpec) User() security.SQLUsername {
 	return m.UserProto.Decode()
@@ -496,6 +496,21 @@ func (c *ReadImportDataSpec) summary() (string, []string) {
 	return "ReadImportData", ss
 }
 
+// summary implements the diagramCellType interface.
+func (s *ForeignScanSpec) summary() (string, []string) {
+	details := []string{s.Table.Name}
+	uris := make([]string, 0, len(s.Uri))
+	for _, uri := range s.Uri {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+	details = append(details, uris...)
+	if !s.Filter.Empty() {
+		details = append(details, fmt.Sprintf("Filter: %s", s.Filter))
+	}
+	return "ForeignScan", details
+}
+
 // summary implements the diagramCellType interface.
 func (s *CSVWriterSpec) summary() (string, []string) {
 	return "CSVWriter", []string{s.Destination}
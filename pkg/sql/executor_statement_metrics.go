@@ -189,20 +189,21 @@ func (ex *connExecutor) recordStatementSummary(
 	}
 
 	recordedStmtStats := sqlstats.RecordedStmtStats{
-		AutoRetryCount:  automaticRetryCount,
-		RowsAffected:    rowsAffected,
-		ParseLatency:    parseLat,
-		PlanLatency:     planLat,
-		RunLatency:      runLat,
-		ServiceLatency:  svcLat,
-		OverheadLatency: execOverhead,
-		BytesRead:       stats.bytesRead,
-		RowsRead:        stats.rowsRead,
-		RowsWritten:     stats.rowsWritten,
-		Nodes:           getNodesFromPlanner(planner),
-		StatementType:   stmt.AST.StatementType(),
-		Plan:            planner.instrumentation.PlanForStats(ctx),
-		StatementError:  stmtErr,
+		AutoRetryCount:       automaticRetryCount,
+		RowsAffected:         rowsAffected,
+		ParseLatency:         parseLat,
+		PlanLatency:          planLat,
+		RunLatency:           runLat,
+		ServiceLatency:       svcLat,
+		OverheadLatency:      execOverhead,
+		BytesRead:            stats.bytesRead,
+		RowsRead:             stats.rowsRead,
+		RowsWritten:          stats.rowsWritten,
+		Nodes:                getNodesFromPlanner(planner),
+		StatementType:        stmt.AST.StatementType(),
+		Plan:                 planner.instrumentation.PlanForStats(ctx),
+		IndexRecommendations: planner.instrumentation.indexRecommendations,
+		StatementError:       stmtErr,
 	}
 
 	stmtFingerprintID, err :=
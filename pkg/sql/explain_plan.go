@@ -134,6 +134,15 @@ func (e *explainPlanNode) startExec(params runParams) error {
 			if e.options.Mode == tree.ExplainDistSQL {
 				rows = append(rows, "", fmt.Sprintf("Diagram: %s", diagramURL.String()))
 			}
+			if indexRecs := params.p.instrumentation.indexRecommendations; len(indexRecs) > 0 {
+				rows = append(rows, "", fmt.Sprintf("index recommendations: %d", len(indexRecs)))
+				for i, rec := range indexRecs {
+					rows = append(rows,
+						fmt.Sprintf("%d. type: index creation", i+1),
+						fmt.Sprintf("   SQL command: %s", rec),
+					)
+				}
+			}
 		}
 	}
 	v := params.p.newContainerValuesNode(colinfo.ExplainPlanColumns, 0)
@@ -26,6 +26,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
 )
@@ -237,6 +238,18 @@ func (*DummyEvalPlanner) DecodeGist(gist string) ([]string, error) {
 	return nil, errors.WithStack(errEvalPlanner)
 }
 
+// CreateReplicationSlot is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) CreateReplicationSlot(
+	ctx context.Context, slotName, plugin string, temporary bool,
+) (hlc.Timestamp, error) {
+	return hlc.Timestamp{}, errors.WithStack(errEvalPlanner)
+}
+
+// DropReplicationSlot is part of the EvalPlanner interface.
+func (*DummyEvalPlanner) DropReplicationSlot(ctx context.Context, slotName string) error {
+	return errors.WithStack(errEvalPlanner)
+}
+
 var _ tree.EvalPlanner = &DummyEvalPlanner{}
 
 var errEvalPlanner = pgerror.New(pgcode.ScalarOperationCannotRunWithoutFullSessionContext,
@@ -0,0 +1,594 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/cloud"
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+// cloudStorageWrapper is the name of the only foreign-data wrapper, which
+// reads the data of foreign tables from files in external storage.
+const cloudStorageWrapper = "cloud_storage"
+
+// The options of foreign servers and foreign tables.
+const (
+	foreignOptionLocation    = "location"
+	foreignOptionFormat      = "format"
+	foreignOptionCompression = "compression"
+
+	foreignOptionDelimiter      = "delimiter"
+	foreignOptionComment        = "comment"
+	foreignOptionNullIf         = "nullif"
+	foreignOptionSkip           = "skip"
+	foreignOptionStrictQuotes   = "strict_quotes"
+	foreignOptionFieldsTermBy   = "fields_terminated_by"
+	foreignOptionRowsTermBy     = "rows_terminated_by"
+	foreignOptionFieldsEnclBy   = "fields_enclosed_by"
+	foreignOptionFieldsEscBy    = "fields_escaped_by"
+	foreignOptionStrictValidate = "strict_validation"
+)
+
+var foreignServerOptionExpectValues = map[string]KVStringOptValidate{
+	foreignOptionLocation: KVStringOptRequireValue,
+}
+
+var foreignTableOptionExpectValues = map[string]KVStringOptValidate{
+	foreignOptionLocation:       KVStringOptRequireValue,
+	foreignOptionFormat:         KVStringOptRequireValue,
+	foreignOptionCompression:    KVStringOptRequireValue,
+	foreignOptionDelimiter:      KVStringOptRequireValue,
+	foreignOptionComment:        KVStringOptRequireValue,
+	foreignOptionNullIf:         KVStringOptRequireValue,
+	foreignOptionSkip:           KVStringOptRequireValue,
+	foreignOptionStrictQuotes:   KVStringOptRequireValue,
+	foreignOptionFieldsTermBy:   KVStringOptRequireValue,
+	foreignOptionRowsTermBy:     KVStringOptRequireValue,
+	foreignOptionFieldsEnclBy:   KVStringOptRequireValue,
+	foreignOptionFieldsEscBy:    KVStringOptRequireValue,
+	foreignOptionStrictValidate: KVStringOptRequireValue,
+}
+
+// foreignTableFormatOptions are the options of foreign tables which are
+// specific to the format of their files.
+var foreignTableFormatOptions = map[roachpb.IOFileFormat_FileFormat][]string{
+	roachpb.IOFileFormat_CSV: {
+		foreignOptionDelimiter, foreignOptionComment, foreignOptionNullIf, foreignOptionSkip,
+		foreignOptionStrictQuotes,
+	},
+	roachpb.IOFileFormat_MysqlOutfile: {
+		foreignOptionFieldsTermBy, foreignOptionRowsTermBy, foreignOptionFieldsEnclBy,
+		foreignOptionFieldsEscBy, foreignOptionNullIf, foreignOptionSkip,
+	},
+	roachpb.IOFileFormat_Avro: {
+		foreignOptionStrictValidate,
+	},
+}
+
+// checkForeignTablesSupported returns an error until the cluster version which
+// allows foreign servers and foreign tables is finalized. Nodes running older
+// versions drop the servers from database descriptors, and read foreign tables
+// as regular tables.
+func checkForeignTablesSupported(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.ForeignTables) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use foreign tables",
+			clusterversion.ForeignTables)
+	}
+	return nil
+}
+
+type createServerNode struct {
+	n       *tree.CreateServer
+	dbDesc  *dbdesc.Mutable
+	options func() (map[string]string, error)
+}
+
+var _ planNode = &createServerNode{n: nil}
+
+// CreateServer creates a foreign server in the current database.
+// Privileges: admin role.
+func (p *planner) CreateServer(ctx context.Context, n *tree.CreateServer) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE SERVER",
+	); err != nil {
+		return nil, err
+	}
+	if err := checkForeignTablesSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+	if err := p.RequireAdminRole(ctx, "CREATE SERVER"); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getMutableCurrentDatabaseFor(ctx, "foreign servers")
+	if err != nil {
+		return nil, err
+	}
+	if n.Wrapper != cloudStorageWrapper {
+		return nil, pgerror.Newf(pgcode.UndefinedObject,
+			"foreign-data wrapper %q does not exist", string(n.Wrapper))
+	}
+	options, err := p.TypeAsStringOpts(ctx, n.Options, foreignServerOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	return &createServerNode{n: n, dbDesc: dbDesc, options: options}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE SERVER performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createServerNode) ReadingOwnWrites() {}
+
+func (n *createServerNode) startExec(params runParams) error {
+	name := string(n.n.Name)
+	if _, ok := n.dbDesc.GetServer(name); ok {
+		if n.n.IfNotExists {
+			params.p.BufferClientNotice(params.ctx,
+				pgnotice.Newf("server %q already exists, skipping", name))
+			return nil
+		}
+		return pgerror.Newf(pgcode.DuplicateObject, "server %q already exists", name)
+	}
+	opts, err := n.options()
+	if err != nil {
+		return err
+	}
+	location, ok := opts[foreignOptionLocation]
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"option %q is required for foreign-data wrapper %q", foreignOptionLocation, cloudStorageWrapper)
+	}
+	if _, err := cloud.ExternalStorageConfFromURI(location, params.p.User()); err != nil {
+		return pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid %q value", foreignOptionLocation)
+	}
+	n.dbDesc.SetServer(name, descpb.DatabaseDescriptor_ServerInfo{
+		OwnerProto: params.p.User().EncodeProto(),
+		Wrapper:    string(n.n.Wrapper),
+		Options:    opts,
+	})
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*createServerNode) Next(runParams) (bool, error) { return false, nil }
+func (*createServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createServerNode) Close(context.Context)        {}
+
+type dropServerNode struct {
+	n      *tree.DropServer
+	dbDesc *dbdesc.Mutable
+	toDrop []string
+}
+
+var _ planNode = &dropServerNode{n: nil}
+
+// DropServer drops foreign servers. The servers must not be used by any
+// foreign table.
+// Privileges: ownership of the servers.
+func (p *planner) DropServer(ctx context.Context, n *tree.DropServer) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP SERVER",
+	); err != nil {
+		return nil, err
+	}
+	if n.DropBehavior == tree.DropCascade {
+		return nil, unimplemented.New("drop server cascade",
+			"DROP SERVER ... CASCADE is not supported")
+	}
+
+	dbDesc, err := p.getMutableCurrentDatabaseFor(ctx, "foreign servers")
+	if err != nil {
+		return nil, err
+	}
+	node := &dropServerNode{n: n, dbDesc: dbDesc}
+	for _, name := range n.Names {
+		if _, ok := dbDesc.GetServer(string(name)); !ok && n.IfExists {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"server %q does not exist, skipping", string(name)))
+			continue
+		}
+		if _, err := p.getServerForChange(ctx, dbDesc, string(name)); err != nil {
+			return nil, err
+		}
+		node.toDrop = append(node.toDrop, string(name))
+	}
+	if len(node.toDrop) == 0 {
+		return node, nil
+	}
+	tables, err := p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, dbDesc.GetID())
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		ft := table.GetForeignTable()
+		if ft == nil || table.Dropped() {
+			continue
+		}
+		for _, name := range node.toDrop {
+			if ft.Server == name {
+				return nil, errors.WithHint(
+					pgerror.Newf(pgcode.DependentObjectsStillExist,
+						"cannot drop server %q because foreign table %q depends on it",
+						name, table.GetName()),
+					"Drop the foreign tables of the server first.",
+				)
+			}
+		}
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP SERVER performs multiple KV operations on descriptors
+// and expects to see its own writes.
+func (n *dropServerNode) ReadingOwnWrites() {}
+
+func (n *dropServerNode) startExec(params runParams) error {
+	if len(n.toDrop) == 0 {
+		return nil
+	}
+	for _, name := range n.toDrop {
+		n.dbDesc.RemoveServer(name)
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*dropServerNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropServerNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropServerNode) Close(context.Context)        {}
+
+// CreateForeignTable creates a foreign table, whose rows are read from files
+// through a foreign server of its database. The table is created like a
+// table with the given columns and a hidden row ID primary key, whose values
+// are derived from the position of the rows in the files.
+// Privileges: CREATE on the database, and ownership of the server.
+func (p *planner) CreateForeignTable(
+	ctx context.Context, n *tree.CreateForeignTable,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE FOREIGN TABLE",
+	); err != nil {
+		return nil, err
+	}
+	if err := checkForeignTablesSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+
+	un := n.Table.ToUnresolvedObjectName()
+	dbDesc, _, prefix, err := p.ResolveTargetObject(ctx, un)
+	if err != nil {
+		return nil, err
+	}
+	n.Table.ObjectNamePrefix = prefix
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if _, err := p.getServerForChange(ctx, dbDesc, string(n.Server)); err != nil {
+		return nil, err
+	}
+
+	for _, def := range n.Defs {
+		if err := checkForeignTableDef(def); err != nil {
+			return nil, err
+		}
+	}
+	optionsFn, err := p.TypeAsStringOpts(ctx, n.Options, foreignTableOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	options, err := optionsFn()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := foreignTableLocation(options); err != nil {
+		return nil, err
+	}
+	if _, err := makeForeignTableFormat(options); err != nil {
+		return nil, err
+	}
+
+	return &createTableNode{
+		n: &tree.CreateTable{
+			IfNotExists: n.IfNotExists,
+			Table:       n.Table,
+			Defs:        n.Defs,
+		},
+		dbDesc: dbDesc,
+		foreign: &descpb.TableDescriptor_ForeignTable{
+			Server:  string(n.Server),
+			Options: options,
+		},
+	}, nil
+}
+
+// checkForeignTableDef checks that a definition of CREATE FOREIGN TABLE is
+// supported. Foreign tables only have columns, which can be nullable or not.
+func checkForeignTableDef(def tree.TableDef) error {
+	col, ok := def.(*tree.ColumnTableDef)
+	if !ok {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"constraints and indexes are not supported on foreign tables")
+	}
+	var unsupported string
+	switch {
+	case col.PrimaryKey.IsPrimaryKey || col.Unique.IsUnique || len(col.CheckExprs) > 0 ||
+		col.References.Table != nil:
+		unsupported = "constraints"
+	case col.IsSerial || col.DefaultExpr.Expr != nil || col.OnUpdateExpr.Expr != nil ||
+		col.GeneratedIdentity.IsGeneratedAsIdentity:
+		unsupported = "default values"
+	case col.Computed.Computed:
+		unsupported = "computed columns"
+	case col.Family.Name != "" || col.Family.Create:
+		unsupported = "column families"
+	case col.Hidden:
+		unsupported = "hidden columns"
+	default:
+		return nil
+	}
+	return pgerror.Newf(pgcode.FeatureNotSupported,
+		"%s are not supported on foreign tables", unsupported)
+}
+
+// checkTableMatchesForeign ensures that a foreign table is dropped with DROP
+// FOREIGN TABLE, and other tables with DROP TABLE.
+func checkTableMatchesForeign(desc catalog.TableDescriptor, wantForeign bool) error {
+	if desc.IsForeignTable() && !wantForeign {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is a foreign table", desc.GetName()),
+			"Use DROP FOREIGN TABLE to remove a foreign table.",
+		)
+	}
+	if !desc.IsForeignTable() && wantForeign {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%q is not a foreign table", desc.GetName()),
+			"Use DROP TABLE to remove a table.",
+		)
+	}
+	return nil
+}
+
+// checkNotForeignTable returns an error if the table is a foreign table, on
+// which the given statement isn't supported.
+func checkNotForeignTable(desc catalog.TableDescriptor, stmt string) error {
+	if desc.IsForeignTable() {
+		return pgerror.Newf(pgcode.WrongObjectType,
+			"%s is not supported on foreign table %q", stmt, desc.GetName())
+	}
+	return nil
+}
+
+// getServerForChange returns the foreign server with the given name, and
+// checks that the user owns it.
+func (p *planner) getServerForChange(
+	ctx context.Context, dbDesc catalog.DatabaseDescriptor, name string,
+) (descpb.DatabaseDescriptor_ServerInfo, error) {
+	server, ok := dbDesc.GetServer(name)
+	if !ok {
+		return server, pgerror.Newf(pgcode.UndefinedObject, "server %q does not exist", name)
+	}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return server, err
+	}
+	if hasAdmin {
+		return server, nil
+	}
+	owner := server.OwnerProto.Decode()
+	hasOwnership, err := p.checkRolePredicate(ctx, p.User(), func(role security.SQLUsername) bool {
+		return role == owner
+	})
+	if err != nil {
+		return server, err
+	}
+	if !hasOwnership {
+		return server, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of server %s", name)
+	}
+	return server, nil
+}
+
+// foreignTableLocation returns the location of the files of a foreign table,
+// relative to the location of its server.
+func foreignTableLocation(opts map[string]string) (string, error) {
+	location, ok := opts[foreignOptionLocation]
+	if !ok {
+		return "", pgerror.Newf(pgcode.InvalidParameterValue,
+			"option %q is required for foreign tables", foreignOptionLocation)
+	}
+	if cleaned := path.Clean(location); path.IsAbs(location) || cleaned == ".." ||
+		strings.HasPrefix(cleaned, "../") {
+		return "", pgerror.Newf(pgcode.InvalidParameterValue,
+			"%q must be a path relative to the location of the server", foreignOptionLocation)
+	}
+	return location, nil
+}
+
+// makeForeignTableFormat returns the format of the files of a foreign table,
+// as described by its options.
+func makeForeignTableFormat(opts map[string]string) (roachpb.IOFileFormat, error) {
+	var format roachpb.IOFileFormat
+	formatName, ok := opts[foreignOptionFormat]
+	if !ok {
+		return format, pgerror.Newf(pgcode.InvalidParameterValue,
+			"option %q is required for foreign tables", foreignOptionFormat)
+	}
+	switch strings.ToLower(formatName) {
+	case "csv":
+		format.Format = roachpb.IOFileFormat_CSV
+		format.Csv.Comma = ','
+	case "delimited":
+		format.Format = roachpb.IOFileFormat_MysqlOutfile
+		format.MysqlOut = roachpb.MySQLOutfileOptions{
+			RowSeparator:   '\n',
+			FieldSeparator: '\t',
+		}
+	case "avro":
+		format.Format = roachpb.IOFileFormat_Avro
+		format.Avro.Format = roachpb.AvroOptions_OCF
+	case "parquet":
+		format.Format = roachpb.IOFileFormat_Parquet
+	default:
+		return format, pgerror.Newf(pgcode.InvalidParameterValue,
+			"unsupported %q value: %q", foreignOptionFormat, formatName)
+	}
+
+	allowed := foreignTableFormatOptions[format.Format]
+	for name := range opts {
+		switch name {
+		case foreignOptionLocation, foreignOptionFormat, foreignOptionCompression:
+			continue
+		}
+		found := false
+		for _, option := range allowed {
+			found = found || option == name
+		}
+		if !found {
+			return format, pgerror.Newf(pgcode.InvalidParameterValue,
+				"option %q is not supported with format %q", name, formatName)
+		}
+	}
+
+	for name, value := range opts {
+		var err error
+		switch name {
+		case foreignOptionCompression:
+			found := false
+			for compressionName, compression := range roachpb.IOFileFormat_Compression_value {
+				if strings.EqualFold(compressionName, value) {
+					format.Compression = roachpb.IOFileFormat_Compression(compression)
+					found = true
+					break
+				}
+			}
+			if !found {
+				err = errors.Newf("unknown compression %q", value)
+			}
+		case foreignOptionDelimiter:
+			format.Csv.Comma, err = util.GetSingleRune(value)
+		case foreignOptionComment:
+			format.Csv.Comment, err = util.GetSingleRune(value)
+		case foreignOptionNullIf:
+			nullIf := value
+			format.Csv.NullEncoding = &nullIf
+			format.MysqlOut.NullEncoding = &nullIf
+		case foreignOptionSkip:
+			var skip uint64
+			skip, err = strconv.ParseUint(value, 10, 32)
+			format.Csv.Skip = uint32(skip)
+			format.MysqlOut.Skip = uint32(skip)
+		case foreignOptionStrictQuotes:
+			format.Csv.StrictQuotes, err = strconv.ParseBool(value)
+		case foreignOptionFieldsTermBy:
+			format.MysqlOut.FieldSeparator, err = util.GetSingleRune(value)
+		case foreignOptionRowsTermBy:
+			format.MysqlOut.RowSeparator, err = util.GetSingleRune(value)
+		case foreignOptionFieldsEnclBy:
+			format.MysqlOut.Enclose = roachpb.MySQLOutfileOptions_Always
+			format.MysqlOut.Encloser, err = util.GetSingleRune(value)
+		case foreignOptionFieldsEscBy:
+			format.MysqlOut.HasEscape = true
+			format.MysqlOut.Escape, err = util.GetSingleRune(value)
+		case foreignOptionStrictValidate:
+			format.Avro.StrictMode, err = strconv.ParseBool(value)
+		}
+		if err != nil {
+			return format, pgerror.Wrapf(err, pgcode.InvalidParameterValue, "invalid %q value", name)
+		}
+	}
+	return format, nil
+}
+
+// foreignTableFiles returns the URIs of the files of a foreign table, in
+// order of name. If the location of the table is a glob pattern, or a
+// directory, the files it matches are listed.
+func foreignTableFiles(
+	ctx context.Context,
+	execCfg *ExecutorConfig,
+	user security.SQLUsername,
+	server descpb.DatabaseDescriptor_ServerInfo,
+	table *descpb.TableDescriptor_ForeignTable,
+) ([]string, error) {
+	location, err := foreignTableLocation(table.Options)
+	if err != nil {
+		return nil, err
+	}
+	uri, err := url.Parse(server.Options[foreignOptionLocation])
+	if err != nil {
+		return nil, err
+	}
+	filePath := path.Join("/", uri.Path, location)
+	if strings.HasSuffix(location, "/") {
+		filePath += "/*"
+	}
+	prefix := cloud.GetPrefixBeforeWildcard(filePath)
+	if len(prefix) == len(filePath) {
+		uri.Path = filePath
+		return []string{uri.String()}, nil
+	}
+
+	pattern := filePath[len(prefix):]
+	uri.Path = prefix
+	es, err := execCfg.DistSQLSrv.ExternalStorageFromURI(ctx, uri.String(), user)
+	if err != nil {
+		return nil, err
+	}
+	defer es.Close()
+	var names []string
+	if err := es.List(ctx, "", "", func(name string) error {
+		ok, err := path.Match(pattern, name)
+		if ok {
+			names = append(names, name)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	files := make([]string, len(names))
+	for i, name := range names {
+		uri.Path = prefix + name
+		files[i] = uri.String()
+	}
+	return files, nil
+}
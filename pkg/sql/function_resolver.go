@@ -0,0 +1,145 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// ResolveFunction resolves the given function name to a function definition.
+// Builtin functions take precedence over user-defined functions. If no
+// builtin function with the given name exists, the user-defined functions of
+// the target database are searched, either in the explicitly specified schema
+// or in the schemas of the search path.
+func (p *planner) ResolveFunction(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) (*tree.FunctionDefinition, error) {
+	def, err := name.ResolveFunction(path)
+	if err == nil || pgerror.GetPGCode(err) != pgcode.UndefinedFunction {
+		return def, err
+	}
+	fns, udfErr := p.resolveUserDefinedFunctions(ctx, name, path)
+	if udfErr != nil {
+		return nil, udfErr
+	}
+	if len(fns) == 0 {
+		return nil, err
+	}
+	overloads := make([]tree.Overload, 0, len(fns))
+	for _, fn := range fns {
+		// Procedures can only be invoked with CALL.
+		if fn.GetIsProcedure() {
+			continue
+		}
+		o, err := funcdesc.MakeOverload(fn)
+		if err != nil {
+			return nil, err
+		}
+		overloads = append(overloads, o)
+	}
+	if len(overloads) == 0 {
+		return nil, errors.WithHint(
+			pgerror.Newf(pgcode.WrongObjectType, "%s is a procedure", name),
+			"To call a procedure, use CALL.")
+	}
+	return tree.NewUDFFunctionDefinition(fns[0].GetName(), overloads), nil
+}
+
+// resolveUserDefinedFunctions returns the user-defined functions with the
+// given name. If the name is not qualified with a schema, the schemas of the
+// search path are searched in order, and functions in earlier schemas shadow
+// functions with identical argument types in later schemas.
+func (p *planner) resolveUserDefinedFunctions(
+	ctx context.Context, name *tree.UnresolvedName, path sessiondata.SearchPath,
+) ([]catalog.FunctionDescriptor, error) {
+	if name.NumParts > 3 || name.Star {
+		return nil, nil
+	}
+	funcName, scName, dbName := name.Parts[0], name.Parts[1], name.Parts[2]
+	if dbName == "" {
+		dbName = p.CurrentDatabase()
+	}
+	if dbName == "" {
+		return nil, nil
+	}
+	db, err := p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, dbName,
+		tree.DatabaseLookupFlags{AvoidCached: p.avoidCachedDescriptors})
+	if err != nil || db == nil {
+		return nil, err
+	}
+	overloads := db.GetFunctionOverloads(funcName)
+	if len(overloads) == 0 {
+		return nil, nil
+	}
+
+	// Determine the names of the schemas of the overloads.
+	fnsBySchema := make(map[string][]catalog.FunctionDescriptor)
+	for _, o := range overloads {
+		sc, err := p.Descriptors().GetImmutableSchemaByID(ctx, p.txn, o.SchemaID,
+			tree.SchemaLookupFlags{Required: true, AvoidCached: p.avoidCachedDescriptors})
+		if err != nil {
+			return nil, err
+		}
+		fn, err := p.Descriptors().GetImmutableFunctionByID(ctx, p.txn, o.ID,
+			tree.ObjectLookupFlags{CommonLookupFlags: p.CommonLookupFlags(true /* required */)})
+		if err != nil {
+			return nil, err
+		}
+		fnsBySchema[sc.GetName()] = append(fnsBySchema[sc.GetName()], fn)
+	}
+
+	if scName != "" {
+		return fnsBySchema[scName], nil
+	}
+	var ret []catalog.FunctionDescriptor
+	iter := path.IterWithoutImplicitPGSchemas()
+	for scName, ok := iter.Next(); ok; scName, ok = iter.Next() {
+		for _, fn := range fnsBySchema[scName] {
+			shadowed := false
+			for _, other := range ret {
+				if sameArgTypes(fn.ArgTypes(), other.ArgTypes()) {
+					shadowed = true
+					break
+				}
+			}
+			if !shadowed {
+				ret = append(ret, fn)
+			}
+		}
+		// Avoid visiting a schema twice if it appears more than once in the
+		// search path.
+		delete(fnsBySchema, scName)
+	}
+	return ret, nil
+}
+
+// sameArgTypes returns whether the two given lists of argument types are
+// identical.
+func sameArgTypes(a, b []*types.T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Oid() != b[i].Oid() {
+			return false
+		}
+	}
+	return true
+}
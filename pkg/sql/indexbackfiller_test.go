@@ -382,6 +382,7 @@ INSERT INTO foo VALUES (1), (10), (100);
 		require.NoError(t, fetcher.Init(
 			ctx,
 			keys.SystemSQLCodec,
+			nil, /* st */
 			reverse,
 			descpb.ScanLockingStrength_FOR_NONE,
 			descpb.ScanLockingWaitPolicy_BLOCK,
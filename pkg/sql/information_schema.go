@@ -1256,6 +1256,9 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 				tbNameStr := tree.NewDString(table.GetName())
 
 				for conName, c := range conInfo {
+					deferrability := c.Deferrability()
+					isDeferrable := deferrability != tree.NotDeferrableConstraint
+					initiallyDeferred := deferrability == tree.DeferrableInitiallyDeferred
 					if err := addRow(
 						dbNameStr,                       // constraint_catalog
 						scNameStr,                       // constraint_schema
@@ -1264,8 +1267,8 @@ https://www.postgresql.org/docs/9.5/infoschema-table-constraints.html`,
 						scNameStr,                       // table_schema
 						tbNameStr,                       // table_name
 						tree.NewDString(string(c.Kind)), // constraint_type
-						yesOrNoDatum(false),             // is_deferrable
-						yesOrNoDatum(false),             // initially_deferred
+						yesOrNoDatum(isDeferrable),      // is_deferrable
+						yesOrNoDatum(initiallyDeferred), // initially_deferred
 					); err != nil {
 						return err
 					}
@@ -2220,6 +2223,45 @@ func forEachTypeDesc(
 	return nil
 }
 
+// forEachFunctionDesc retrieves all user-defined function descriptors
+// visible in the given database context and iterates through them. For each
+// function, fn is called with its database, schema name and descriptor.
+func forEachFunctionDesc(
+	ctx context.Context,
+	p *planner,
+	dbContext catalog.DatabaseDescriptor,
+	fn func(db catalog.DatabaseDescriptor, sc string, fnDesc catalog.FunctionDescriptor) error,
+) error {
+	descs, err := p.Descriptors().GetAllDescriptors(ctx, p.txn)
+	if err != nil {
+		return err
+	}
+	lCtx := newInternalLookupCtx(ctx, descs, dbContext,
+		catalogkv.NewOneLevelUncachedDescGetter(p.txn, p.execCfg.Codec))
+	for _, id := range lCtx.fnIDs {
+		fnDesc := lCtx.fnDescs[id]
+		dbDesc, err := lCtx.getDatabaseByID(fnDesc.GetParentID())
+		if err != nil {
+			continue
+		}
+		scName, err := lCtx.getSchemaNameByID(fnDesc.GetParentSchemaID())
+		if err != nil {
+			return err
+		}
+		canSeeDescriptor, err := userCanSeeDescriptor(ctx, p, fnDesc, dbDesc, false /* allowAdding */)
+		if err != nil {
+			return err
+		}
+		if !canSeeDescriptor {
+			continue
+		}
+		if err := fn(dbDesc, scName, fnDesc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // forEachTableDesc retrieves all table descriptors from the current
 // database and all system databases and iterates through them. For
 // each table, the function will call fn with its respective database
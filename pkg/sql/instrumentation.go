@@ -119,6 +119,11 @@ type instrumentationHelper struct {
 	// planGist is a compressed version of plan that can be converted (lossily)
 	// back into a logical plan or be used to get a plan hash.
 	planGist explain.PlanGist
+
+	// indexRecommendations contains the CREATE INDEX statements recommended by
+	// the optimizer. They are only built for EXPLAIN statements and for
+	// statements whose plan is saved for the statement statistics.
+	indexRecommendations []string
 }
 
 // outputMode indicates how the statement output needs to be populated (for
@@ -939,6 +939,20 @@ func (icc *internalClientComm) CreateCopyInResult(pos CmdPos) CopyInResult {
 	panic("unimplemented")
 }
 
+// CreateCopyOutResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateCopyOutResult(
+	pos CmdPos, conv sessiondatapb.DataConversionConfig, location *time.Location,
+) CopyOutResult {
+	panic("unimplemented")
+}
+
+// CreateReplicationResult is part of the ClientComm interface.
+func (icc *internalClientComm) CreateReplicationResult(
+	pos CmdPos, conv sessiondatapb.DataConversionConfig, location *time.Location,
+) ReplicationResult {
+	panic("unimplemented")
+}
+
 // CreateDrainResult is part of the ClientComm interface.
 func (icc *internalClientComm) CreateDrainResult(pos CmdPos) DrainResult {
 	panic("unimplemented")
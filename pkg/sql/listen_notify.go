@@ -0,0 +1,196 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+)
+
+// maxNotificationPayloadLen is the maximum length of the payload of a
+// notification, which is the same as in Postgres.
+const maxNotificationPayloadLen = 8000
+
+// Listen implements the LISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-listen.html for details.
+func (p *planner) Listen(ctx context.Context, n *tree.Listen) (planNode, error) {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return nil, err
+	}
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if err := p.sqlListeners.listen(ctx, string(n.ChannelName)); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// Unlisten implements the UNLISTEN statement.
+// See https://www.postgresql.org/docs/current/sql-unlisten.html for details.
+func (p *planner) Unlisten(ctx context.Context, n *tree.Unlisten) (planNode, error) {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return nil, err
+	}
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if n.Star {
+				p.sqlListeners.unlistenAll()
+			} else {
+				p.sqlListeners.unlisten(string(n.ChannelName))
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// Notify implements the NOTIFY statement.
+// See https://www.postgresql.org/docs/current/sql-notify.html for details.
+func (p *planner) Notify(ctx context.Context, n *tree.Notify) (planNode, error) {
+	if err := p.checkNotificationsEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if n.ChannelName == "" {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "channel name cannot be empty")
+	}
+	if len(n.Payload) >= maxNotificationPayloadLen {
+		return nil, pgerror.New(pgcode.InvalidParameterValue, "payload string too long")
+	}
+	if p.EvalContext().TxnReadOnly {
+		return nil, pgerror.New(pgcode.ReadOnlySQLTransaction,
+			"cannot execute NOTIFY in a read-only transaction")
+	}
+	return &delayedNode{
+		name: n.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if err := p.ExecCfg().NotificationRegistry.Notify(
+				ctx, p.txn, p.sqlListeners.backendPID(), string(n.ChannelName), n.Payload,
+			); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+func (p *planner) checkNotificationsEnabled(ctx context.Context) error {
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+		return pgerror.Newf(
+			pgcode.FeatureNotSupported,
+			"LISTEN and NOTIFY are only available once the cluster is fully upgraded",
+		)
+	}
+	return nil
+}
+
+// notificationListener tracks the channels a session listens on. LISTEN and
+// UNLISTEN only take effect when the transaction in which they are executed
+// commits.
+//
+// A nil *notificationListener is used by the planners that aren't associated
+// with a session; it does not support LISTEN.
+type notificationListener struct {
+	registry *notify.Registry
+	// sender delivers the notifications to the client. It is nil if the client
+	// connection does not support asynchronous notifications.
+	sender NotificationSender
+	// sub is created by the first LISTEN statement of the session.
+	sub *notify.Subscription
+	// pending contains the operations of the current transaction, which are
+	// applied in order when it commits.
+	pending []listenOp
+}
+
+// listenOp is a LISTEN or UNLISTEN operation.
+type listenOp struct {
+	channel  string
+	unlisten bool
+	// all is set for UNLISTEN *.
+	all bool
+}
+
+func (l *notificationListener) listen(ctx context.Context, channel string) error {
+	if l == nil || l.sender == nil {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"LISTEN is not supported by this connection")
+	}
+	if l.sub == nil {
+		sender := l.sender
+		sub, err := l.registry.Subscribe(ctx, func(_ context.Context, n notify.Notification) error {
+			return sender.SendNotification(n.PID, n.Channel, n.Payload)
+		})
+		if err != nil {
+			return err
+		}
+		l.sub = sub
+	}
+	l.pending = append(l.pending, listenOp{channel: channel})
+	return nil
+}
+
+// backendPID returns the process ID which identifies the session in the
+// notifications it sends, or 0 if the session has no client connection.
+func (l *notificationListener) backendPID() int32 {
+	if l == nil || l.sender == nil {
+		return 0
+	}
+	return l.sender.BackendPID()
+}
+
+func (l *notificationListener) unlisten(channel string) {
+	// A session which never executed LISTEN doesn't listen on any channel.
+	if l == nil || l.sub == nil {
+		return
+	}
+	l.pending = append(l.pending, listenOp{channel: channel, unlisten: true})
+}
+
+func (l *notificationListener) unlistenAll() {
+	if l == nil || l.sub == nil {
+		return
+	}
+	l.pending = append(l.pending, listenOp{unlisten: true, all: true})
+}
+
+// onTxnFinish is called when the transaction finishes. The pending operations
+// are applied if it committed, and discarded otherwise.
+func (l *notificationListener) onTxnFinish(committed bool) {
+	if committed && l.sub != nil {
+		for _, op := range l.pending {
+			switch {
+			case op.all:
+				l.sub.UnlistenAll()
+			case op.unlisten:
+				l.sub.Unlisten(op.channel)
+			default:
+				l.sub.Listen(op.channel)
+			}
+		}
+	}
+	l.pending = l.pending[:0]
+}
+
+// close stops listening on all channels.
+func (l *notificationListener) close() {
+	if l.sub != nil {
+		l.sub.Close()
+		l.sub = nil
+	}
+	l.pending = nil
+}
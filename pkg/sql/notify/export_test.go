@@ -0,0 +1,21 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import "context"
+
+// MaxQueuedNotifications exports maxQueuedNotifications for testing.
+const MaxQueuedNotifications = maxQueuedNotifications
+
+// MaybeRestartRangeFeed exports maybeRestartRangeFeed for testing.
+func (r *Registry) MaybeRestartRangeFeed(ctx context.Context) {
+	r.maybeRestartRangeFeed(ctx)
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+)
+
+var (
+	metaNotificationsDelivered = metric.Metadata{
+		Name:        "sql.notifications.delivered",
+		Help:        "Number of notifications delivered to the listening sessions",
+		Measurement: "Notifications",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+	metaNotificationsDropped = metric.Metadata{
+		Name:        "sql.notifications.dropped",
+		Help:        "Number of notifications dropped because too many notifications were waiting to be delivered to a session",
+		Measurement: "Notifications",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+	metaRangeFeedRestarts = metric.Metadata{
+		Name:        "sql.notifications.rangefeed_restarts",
+		Help:        "Number of times the rangefeed over the notifications table was restarted because it stopped making progress",
+		Measurement: "Restarts",
+		Unit:        metric.Unit_COUNT,
+		MetricType:  io_prometheus_client.MetricType_COUNTER,
+	}
+)
+
+// Metrics is a metric.Struct which holds the metrics of the Registry.
+type Metrics struct {
+	Delivered         *metric.Counter
+	Dropped           *metric.Counter
+	RangeFeedRestarts *metric.Counter
+}
+
+// MetricStruct makes Metrics a metric.Struct.
+func (m Metrics) MetricStruct() {}
+
+var _ metric.Struct = Metrics{}
+
+func makeMetrics() Metrics {
+	return Metrics{
+		Delivered:         metric.NewCounter(metaNotificationsDelivered),
+		Dropped:           metric.NewCounter(metaNotificationsDropped),
+		RangeFeedRestarts: metric.NewCounter(metaRangeFeedRestarts),
+	}
+}
@@ -0,0 +1,461 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package notify implements the delivery of the notifications sent with
+// NOTIFY to the sessions which are listening on their channels.
+//
+// Notifications are written to the system.notifications table in the
+// transaction which executes NOTIFY, so they only become visible, and are only
+// delivered, once that transaction commits. Every node which has listening
+// sessions watches the table with a rangefeed and dispatches the notifications
+// to the subscriptions of its sessions. Rows are deleted by a periodic cleanup
+// once they are older than sql.notifications.retention.
+//
+// Delivery is at least once: if the rangefeed is restarted, a notification may
+// be delivered more than once. It is not guaranteed either: the notifications
+// are dropped if too many of them are waiting to be delivered to a session, or
+// if the rangefeed falls behind by more than the retention period, in which
+// case it is restarted. Both are counted in the Metrics of the Registry.
+package notify
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/stop"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/logtags"
+)
+
+// retention is the duration for which notifications are kept in the
+// notifications table.
+var retention = settings.RegisterDurationSetting(
+	"sql.notifications.retention",
+	"the amount of time for which notifications sent with NOTIFY are kept "+
+		"before being deleted",
+	10*time.Minute,
+	settings.PositiveDuration,
+)
+
+const (
+	// cleanupInterval is the interval at which each node deletes the expired
+	// notifications.
+	cleanupInterval = time.Minute
+	// cleanupBatchSize is the maximum number of notifications deleted by a
+	// single statement.
+	cleanupBatchSize = 1000
+	// maxQueuedNotifications is the maximum number of notifications which are
+	// queued for delivery to a single subscription. Further notifications are
+	// dropped until the queue is drained.
+	maxQueuedNotifications = 10000
+)
+
+// Notification is a notification sent with NOTIFY.
+type Notification struct {
+	// PID is the process ID of the session which sent the notification, as
+	// reported to its client in the BackendKeyData message.
+	PID     int32
+	Channel string
+	Payload string
+}
+
+// Registry dispatches the notifications to the subscriptions of the sessions
+// on this node.
+type Registry struct {
+	codec    keys.SQLCodec
+	clock    *hlc.Clock
+	settings *cluster.Settings
+	f        *rangefeed.Factory
+	ie       sqlutil.InternalExecutor
+	stopper  *stop.Stopper
+	metrics  Metrics
+
+	mu struct {
+		syncutil.Mutex
+		// rf is the rangefeed over the notifications table. It is started when
+		// the first subscription is created and runs until the server stops,
+		// unless it is restarted by maybeRestartRangeFeed.
+		rf *rangefeed.RangeFeed
+		// frontier is the timestamp up to which rf has delivered all the
+		// notifications.
+		frontier hlc.Timestamp
+		dec      rowDecoder
+		subs     map[*Subscription]struct{}
+	}
+}
+
+// NewRegistry constructs a new Registry.
+func NewRegistry(
+	codec keys.SQLCodec,
+	clock *hlc.Clock,
+	st *cluster.Settings,
+	f *rangefeed.Factory,
+	ie sqlutil.InternalExecutor,
+	stopper *stop.Stopper,
+) *Registry {
+	r := &Registry{
+		codec:    codec,
+		clock:    clock,
+		settings: st,
+		f:        f,
+		ie:       ie,
+		stopper:  stopper,
+		metrics:  makeMetrics(),
+	}
+	r.mu.dec = makeRowDecoder()
+	r.mu.subs = make(map[*Subscription]struct{})
+	// The rangefeed may be restarted, so the closer closes whichever rangefeed
+	// is current when the server stops.
+	stopper.AddCloser(stop.CloserFn(func() {
+		r.mu.Lock()
+		rf := r.mu.rf
+		r.mu.rf = nil
+		r.mu.Unlock()
+		// Close waits for the callbacks, which acquire r.mu, to return.
+		if rf != nil {
+			rf.Close()
+		}
+	}))
+	return r
+}
+
+// Metrics returns the metrics of the registry.
+func (r *Registry) Metrics() Metrics {
+	return r.metrics
+}
+
+// Start starts the periodic deletion of the expired notifications and the
+// monitoring of the rangefeed.
+func (r *Registry) Start(ctx context.Context) {
+	ctx, _ = r.stopper.WithCancelOnQuiesce(ctx)
+	// NB: The only error that should occur here would be if the server were
+	// shutting down so let's swallow it.
+	_ = r.stopper.RunAsyncTask(ctx, "notifications-cleanup", r.cleanupLoop)
+}
+
+func (r *Registry) cleanupLoop(ctx context.Context) {
+	var timer timeutil.Timer
+	defer timer.Stop()
+	for {
+		timer.Reset(cleanupInterval)
+		select {
+		case <-timer.C:
+			timer.Read = true
+		case <-ctx.Done():
+			return
+		}
+		r.maybeRestartRangeFeed(ctx)
+		if !r.settings.Version.IsActive(ctx, clusterversion.NotificationsTable) {
+			continue
+		}
+		if err := r.deleteExpired(ctx); err != nil && ctx.Err() == nil {
+			log.Warningf(ctx, "error deleting expired notifications: %v", err)
+		}
+	}
+}
+
+// deleteExpired deletes the notifications which are older than the retention
+// period.
+func (r *Registry) deleteExpired(ctx context.Context) error {
+	for {
+		n, err := r.ie.ExecEx(ctx, "delete-expired-notifications", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			`DELETE FROM system.notifications WHERE created < now() - $1::INTERVAL LIMIT $2`,
+			retention.Get(&r.settings.SV).String(), cleanupBatchSize,
+		)
+		if err != nil || n < cleanupBatchSize {
+			return err
+		}
+	}
+}
+
+// Notify sends a notification on the given channel on behalf of the session
+// with the given process ID. The notification is written in the given
+// transaction, so it is only delivered to the listening sessions if the
+// transaction commits.
+func (r *Registry) Notify(
+	ctx context.Context, txn *kv.Txn, pid int32, channel, payload string,
+) error {
+	_, err := r.ie.ExecEx(ctx, "notify", txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`INSERT INTO system.notifications (id, created, pid, channel, payload) `+
+			`VALUES (gen_random_uuid(), now(), $1, $2, $3)`,
+		pid, channel, payload,
+	)
+	return err
+}
+
+// Subscribe creates a new subscription, which does not listen on any channel
+// yet. The notifications sent on the channels it listens on are delivered with
+// the given function, which is called from a separate goroutine one
+// notification at a time. The subscription must be closed when it is no
+// longer needed.
+func (r *Registry) Subscribe(
+	ctx context.Context, send func(context.Context, Notification) error,
+) (*Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mu.rf == nil {
+		if err := r.startRangeFeedLocked(r.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+	s := &Subscription{
+		r:            r,
+		send:         send,
+		notifyCh:     make(chan struct{}, 1),
+		stopCh:       make(chan struct{}),
+		dropLogEvery: log.Every(10 * time.Second),
+	}
+	s.mu.channels = make(map[string]hlc.Timestamp)
+	// The subscription outlives the statement which created it, so its
+	// goroutine doesn't use the statement's context.
+	taskCtx := logtags.WithTags(context.Background(), logtags.FromContext(ctx))
+	if err := r.stopper.RunAsyncTask(taskCtx, "notifications-delivery", s.run); err != nil {
+		return nil, err
+	}
+	r.mu.subs[s] = struct{}{}
+	return s, nil
+}
+
+// startRangeFeedLocked starts the rangefeed over the notifications table,
+// which delivers the notifications committed after the given timestamp.
+func (r *Registry) startRangeFeedLocked(startTS hlc.Timestamp) error {
+	tablePrefix := r.codec.TablePrefix(keys.NotificationsTableID)
+	tableSpan := roachpb.Span{
+		Key:    tablePrefix,
+		EndKey: tablePrefix.PrefixEnd(),
+	}
+	// The rangefeed is shared by all the sessions on this node and runs until
+	// the server stops.
+	ctx := logtags.AddTag(context.Background(), "notifications", nil)
+	rf, err := r.f.RangeFeed(ctx, "notifications", tableSpan, startTS, r.onValue,
+		rangefeed.WithOnFrontierAdvance(r.onFrontierAdvance))
+	if err != nil {
+		return err
+	}
+	r.mu.rf = rf
+	r.mu.frontier = startTS
+	return nil
+}
+
+// maybeRestartRangeFeed restarts the rangefeed over the notifications table
+// if its frontier has fallen behind by more than the retention period, or
+// starts it if it previously failed to restart. The rangefeed retries on
+// errors on its own, but it never recovers once its frontier falls behind the
+// GC threshold of the table. The notifications which were not delivered by
+// then are lost; they are also about to be deleted.
+func (r *Registry) maybeRestartRangeFeed(ctx context.Context) {
+	ret := retention.Get(&r.settings.SV)
+	r.mu.Lock()
+	rf, frontier := r.mu.rf, r.mu.frontier
+	if rf == nil && len(r.mu.subs) > 0 {
+		defer r.mu.Unlock()
+		if err := r.startRangeFeedLocked(r.clock.Now().Add(-ret.Nanoseconds(), 0)); err != nil {
+			log.Warningf(ctx, "failed to start the notifications rangefeed: %v", err)
+		}
+		return
+	}
+	lag := r.clock.PhysicalTime().Sub(frontier.GoTime())
+	if rf == nil || lag <= ret {
+		r.mu.Unlock()
+		return
+	}
+	r.mu.rf = nil
+	r.mu.Unlock()
+
+	log.Warningf(ctx, "restarting the notifications rangefeed, which is %s behind; "+
+		"the notifications sent since %s are not delivered", lag, frontier)
+	r.metrics.RangeFeedRestarts.Inc(1)
+	// Close waits for the callbacks, which acquire r.mu, to return.
+	rf.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// A subscription may have started a new rangefeed in the meantime.
+	if r.mu.rf != nil {
+		return
+	}
+	if err := r.startRangeFeedLocked(r.clock.Now().Add(-ret.Nanoseconds(), 0)); err != nil {
+		log.Warningf(ctx, "failed to restart the notifications rangefeed: %v", err)
+	}
+}
+
+// onFrontierAdvance is called by the rangefeed when its frontier advances.
+func (r *Registry) onFrontierAdvance(_ context.Context, ts hlc.Timestamp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mu.frontier = ts
+}
+
+// onValue is called by the rangefeed for every change to the notifications
+// table.
+func (r *Registry) onValue(ctx context.Context, kv *roachpb.RangeFeedValue) {
+	// Deletions of expired notifications need not be dispatched.
+	if !kv.Value.IsPresent() {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n, err := r.mu.dec.decodeRow(kv.Value)
+	if err != nil {
+		log.Warningf(ctx, "failed to decode notifications row %v: %v", kv.Key, err)
+		return
+	}
+	for s := range r.mu.subs {
+		s.maybeEnqueue(ctx, n, kv.Value.Timestamp)
+	}
+}
+
+// Subscription delivers the notifications sent on the channels a session
+// listens on.
+type Subscription struct {
+	r    *Registry
+	send func(context.Context, Notification) error
+
+	// notifyCh is signaled when notifications are added to the queue.
+	notifyCh chan struct{}
+	// stopCh is closed when the subscription is closed.
+	stopCh chan struct{}
+
+	// dropLogEvery limits the logging of dropped notifications.
+	dropLogEvery log.EveryN
+
+	mu struct {
+		syncutil.Mutex
+		// channels maps the channels the subscription listens on to the
+		// timestamp at which it started listening. Notifications committed
+		// before that timestamp are not delivered.
+		channels map[string]hlc.Timestamp
+		// queue contains the notifications which have not been delivered yet.
+		queue  []Notification
+		closed bool
+	}
+}
+
+// Listen starts listening on the given channel. Listening on a channel the
+// subscription already listens on is a no-op.
+func (s *Subscription) Listen(channel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.mu.channels[channel]; !ok {
+		s.mu.channels[channel] = s.r.clock.Now()
+	}
+}
+
+// Unlisten stops listening on the given channel.
+func (s *Subscription) Unlisten(channel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.mu.channels, channel)
+}
+
+// UnlistenAll stops listening on all channels.
+func (s *Subscription) UnlistenAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mu.channels = make(map[string]hlc.Timestamp)
+}
+
+// Channels returns the sorted names of the channels the subscription listens
+// on.
+func (s *Subscription) Channels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channels := make([]string, 0, len(s.mu.channels))
+	for c := range s.mu.channels {
+		channels = append(channels, c)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// Close closes the subscription. The notifications which have not been
+// delivered yet are discarded.
+func (s *Subscription) Close() {
+	s.r.mu.Lock()
+	delete(s.r.mu.subs, s)
+	s.r.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mu.closed {
+		return
+	}
+	s.mu.closed = true
+	s.mu.queue = nil
+	close(s.stopCh)
+}
+
+func (s *Subscription) maybeEnqueue(ctx context.Context, n Notification, ts hlc.Timestamp) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	listenTS, ok := s.mu.channels[n.Channel]
+	if !ok || ts.Less(listenTS) || s.mu.closed {
+		return
+	}
+	if len(s.mu.queue) >= maxQueuedNotifications {
+		s.r.metrics.Dropped.Inc(1)
+		if s.dropLogEvery.ShouldLog() {
+			log.Warningf(ctx, "dropping notification on channel %q: "+
+				"too many notifications are waiting to be delivered", n.Channel)
+		}
+		return
+	}
+	s.mu.queue = append(s.mu.queue, n)
+	select {
+	case s.notifyCh <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers the queued notifications until the subscription is closed.
+func (s *Subscription) run(ctx context.Context) {
+	ctx, cancel := s.r.stopper.WithCancelOnQuiesce(ctx)
+	defer cancel()
+	for {
+		select {
+		case <-s.notifyCh:
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+		for {
+			s.mu.Lock()
+			if len(s.mu.queue) == 0 {
+				s.mu.Unlock()
+				break
+			}
+			n := s.mu.queue[0]
+			s.mu.queue = s.mu.queue[1:]
+			s.mu.Unlock()
+			if err := s.send(ctx, n); err != nil {
+				log.VEventf(ctx, 2, "failed to deliver notification: %v", err)
+				continue
+			}
+			s.r.metrics.Delivered.Inc(1)
+		}
+	}
+}
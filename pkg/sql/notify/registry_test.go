@@ -0,0 +1,179 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/notify"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// subscribe creates a subscription which delivers the notifications to the
+// returned channel.
+func subscribe(
+	t *testing.T, r *notify.Registry,
+) (*notify.Subscription, <-chan notify.Notification) {
+	ch := make(chan notify.Notification, 1)
+	sub, err := r.Subscribe(context.Background(), func(ctx context.Context, n notify.Notification) error {
+		select {
+		case ch <- n:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	require.NoError(t, err)
+	return sub, ch
+}
+
+func waitForNotification(t *testing.T, ch <-chan notify.Notification) notify.Notification {
+	t.Helper()
+	select {
+	case n := <-ch:
+		return n
+	case <-time.After(testutils.DefaultSucceedsSoonDuration):
+		t.Fatal("timed out waiting for a notification")
+		return notify.Notification{}
+	}
+}
+
+// TestRegistryDelivery checks that the notifications are delivered to the
+// subscriptions listening on their channel, once they started listening.
+func TestRegistryDelivery(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	r := s.ExecutorConfig().(sql.ExecutorConfig).NotificationRegistry
+
+	sub, ch := subscribe(t, r)
+	defer sub.Close()
+
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 1, "foo", "before listening"))
+	sub.Listen("foo")
+	sub.Listen("foo")
+	require.Equal(t, []string{"foo"}, sub.Channels())
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 1, "bar", "other channel"))
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 42, "foo", "listening"))
+	require.Equal(t,
+		notify.Notification{PID: 42, Channel: "foo", Payload: "listening"},
+		waitForNotification(t, ch))
+
+	sub.Unlisten("foo")
+	sub.Listen("bar")
+	require.Equal(t, []string{"bar"}, sub.Channels())
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 1, "foo", "not listening"))
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 1, "bar", "listening"))
+	require.Equal(t,
+		notify.Notification{PID: 1, Channel: "bar", Payload: "listening"},
+		waitForNotification(t, ch))
+
+	sub.UnlistenAll()
+	require.Empty(t, sub.Channels())
+	require.EqualValues(t, 2, r.Metrics().Delivered.Count())
+}
+
+// TestRegistryDropsNotifications checks that the notifications are dropped,
+// and counted, when too many of them are waiting to be delivered to a
+// subscription.
+func TestRegistryDropsNotifications(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	r := s.ExecutorConfig().(sql.ExecutorConfig).NotificationRegistry
+
+	// The subscription doesn't deliver any notification until unblocked.
+	unblock := make(chan struct{})
+	sub, err := r.Subscribe(ctx, func(ctx context.Context, _ notify.Notification) error {
+		select {
+		case <-unblock:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	require.NoError(t, err)
+	defer sub.Close()
+	sub.Listen("foo")
+
+	const numNotifications = notify.MaxQueuedNotifications + 10
+	sqlutils.MakeSQLRunner(db).Exec(t,
+		`INSERT INTO system.notifications (id, created, pid, channel, payload) `+
+			`SELECT gen_random_uuid(), now(), 1, 'foo', '' FROM generate_series(1, $1)`,
+		numNotifications,
+	)
+	// One of the notifications may be waiting in the delivery function rather
+	// than in the queue.
+	testutils.SucceedsSoon(t, func() error {
+		if dropped := r.Metrics().Dropped.Count(); dropped < 9 {
+			return errors.Errorf("%d notifications dropped", dropped)
+		}
+		return nil
+	})
+	// Once unblocked, all the notifications are either delivered or dropped.
+	// Since the rangefeed may redeliver some of them, there can be more.
+	close(unblock)
+	testutils.SucceedsSoon(t, func() error {
+		delivered, dropped := r.Metrics().Delivered.Count(), r.Metrics().Dropped.Count()
+		if delivered+dropped < numNotifications {
+			return errors.Errorf("%d notifications delivered, %d dropped", delivered, dropped)
+		}
+		return nil
+	})
+}
+
+// TestRegistryRestartsRangeFeed checks that the rangefeed is restarted when it
+// falls behind by more than the retention period, and that the notifications
+// are delivered after it was restarted.
+func TestRegistryRestartsRangeFeed(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+	r := s.ExecutorConfig().(sql.ExecutorConfig).NotificationRegistry
+
+	sub, ch := subscribe(t, r)
+	defer sub.Close()
+	sub.Listen("foo")
+
+	// The rangefeed isn't restarted while its frontier is within the retention
+	// period.
+	r.MaybeRestartRangeFeed(ctx)
+	require.Zero(t, r.Metrics().RangeFeedRestarts.Count())
+
+	// The frontier of the rangefeed always lags behind by more than 1ms.
+	sqlutils.MakeSQLRunner(db).Exec(t, `SET CLUSTER SETTING sql.notifications.retention = '1ms'`)
+	r.MaybeRestartRangeFeed(ctx)
+	require.EqualValues(t, 1, r.Metrics().RangeFeedRestarts.Count())
+
+	require.NoError(t, r.Notify(ctx, nil /* txn */, 1, "foo", "after restart"))
+	require.Equal(t,
+		notify.Notification{PID: 1, Channel: "foo", Payload: "after restart"},
+		waitForNotification(t, ch))
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package notify
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/systemschema"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// rowDecoder decodes rows from the notifications table.
+type rowDecoder struct {
+	alloc     rowenc.DatumAlloc
+	colIdxMap catalog.TableColMap
+}
+
+func makeRowDecoder() rowDecoder {
+	return rowDecoder{
+		colIdxMap: row.ColIDtoRowIndexFromCols(
+			systemschema.NotificationsTable.PublicColumns(),
+		),
+	}
+}
+
+// decodeRow decodes the process ID, channel and payload of a row of the
+// system.notifications table. The value must be present.
+func (d *rowDecoder) decodeRow(v roachpb.Value) (Notification, error) {
+	tbl := systemschema.NotificationsTable
+	var n Notification
+	// All the non-key columns are stored in a single family, packed with
+	// diff-encoded column IDs followed by their values.
+	bytes, err := v.GetTuple()
+	if err != nil {
+		return Notification{}, err
+	}
+	var colIDDiff uint32
+	var lastColID descpb.ColumnID
+	var res tree.Datum
+	for len(bytes) > 0 {
+		_, _, colIDDiff, _, err = encoding.DecodeValueTag(bytes)
+		if err != nil {
+			return Notification{}, err
+		}
+		colID := lastColID + descpb.ColumnID(colIDDiff)
+		lastColID = colID
+		idx, ok := d.colIdxMap.Get(colID)
+		if !ok {
+			return Notification{}, errors.Errorf("unknown column: %v", colID)
+		}
+		res, bytes, err = rowenc.DecodeTableValue(&d.alloc, tbl.PublicColumns()[idx].GetType(), bytes)
+		if err != nil {
+			return Notification{}, err
+		}
+		switch colID {
+		case tbl.PublicColumns()[1].GetID(): // created
+		case tbl.PublicColumns()[2].GetID(): // pid
+			n.PID = int32(tree.MustBeDInt(res))
+		case tbl.PublicColumns()[3].GetID(): // channel
+			n.Channel = string(tree.MustBeDString(res))
+		case tbl.PublicColumns()[4].GetID(): // payload
+			n.Payload = string(tree.MustBeDString(res))
+		default:
+			return Notification{}, errors.Errorf("unknown column: %v", colID)
+		}
+	}
+	return n, nil
+}
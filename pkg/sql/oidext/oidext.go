@@ -36,6 +36,13 @@ const (
 	T__box2d     = oid.Oid(90005)
 )
 
+// OIDs in this block are postgres types that are missing from
+// `github.com/lib/pq/oid`, and have the same OIDs as in postgres.
+const (
+	T_jsonpath  = oid.Oid(4072)
+	T__jsonpath = oid.Oid(4073)
+)
+
 // ExtensionTypeName returns a mapping from extension oids
 // to their type name.
 var ExtensionTypeName = map[oid.Oid]string{
@@ -45,6 +52,8 @@ var ExtensionTypeName = map[oid.Oid]string{
 	T__geography: "_GEOGRAPHY",
 	T_box2d:      "BOX2D",
 	T__box2d:     "_BOX2D",
+	T_jsonpath:   "JSONPATH",
+	T__jsonpath:  "_JSONPATH",
 }
 
 // TypeName checks the name for a given type by first looking up oid.TypeName
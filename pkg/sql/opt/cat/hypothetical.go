@@ -0,0 +1,255 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package cat
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/errors"
+)
+
+// HypotheticalTable wraps a Table and extends it with hypothetical indexes.
+// A hypothetical index does not exist in the catalog, but the optimizer can
+// cost it as if it did, which allows it to determine whether creating the
+// index would speed up a query. The hypothetical indexes follow the public
+// indexes of the wrapped table, so the wrapped table must not have any mutation
+// indexes.
+type HypotheticalTable struct {
+	Table
+
+	hypotheticalIndexes []HypotheticalIndex
+}
+
+var _ Table = &HypotheticalTable{}
+
+// NewHypotheticalTable returns a HypotheticalTable that wraps the given table
+// and adds a non-unique secondary index for each of the given lists of key
+// columns. Each hypothetical index stores every column of the table that is not
+// part of its key, so that it can serve any query on its own.
+func NewHypotheticalTable(tab Table, indexKeyCols [][]IndexColumn) *HypotheticalTable {
+	if tab.DeletableIndexCount() != tab.IndexCount() {
+		panic(errors.AssertionFailedf(
+			"cannot add hypothetical indexes to table %s with mutation indexes", tab.Name(),
+		))
+	}
+	ht := &HypotheticalTable{
+		Table:               tab,
+		hypotheticalIndexes: make([]HypotheticalIndex, len(indexKeyCols)),
+	}
+
+	// Give the hypothetical indexes IDs that do not conflict with existing
+	// indexes.
+	var maxID StableID
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		if id := tab.Index(i).ID(); id > maxID {
+			maxID = id
+		}
+	}
+	for i := range indexKeyCols {
+		ht.hypotheticalIndexes[i].init(ht, tab.IndexCount()+i, maxID+StableID(i+1), indexKeyCols[i])
+	}
+	return ht
+}
+
+// IndexCount is part of the Table interface.
+func (ht *HypotheticalTable) IndexCount() int {
+	return ht.Table.IndexCount() + len(ht.hypotheticalIndexes)
+}
+
+// WritableIndexCount is part of the Table interface.
+func (ht *HypotheticalTable) WritableIndexCount() int {
+	return ht.IndexCount()
+}
+
+// DeletableIndexCount is part of the Table interface.
+func (ht *HypotheticalTable) DeletableIndexCount() int {
+	return ht.IndexCount()
+}
+
+// Index is part of the Table interface.
+func (ht *HypotheticalTable) Index(i IndexOrdinal) Index {
+	if n := ht.Table.IndexCount(); i >= n {
+		return &ht.hypotheticalIndexes[i-n]
+	}
+	return ht.Table.Index(i)
+}
+
+// HypotheticalIndex is an Index that does not exist in the catalog. See
+// HypotheticalTable.
+type HypotheticalIndex struct {
+	tab     *HypotheticalTable
+	ordinal IndexOrdinal
+	id      StableID
+	name    tree.Name
+
+	// cols contains the explicit key columns of the index, followed by the
+	// primary key columns that are not explicit key columns, followed by the
+	// stored columns.
+	cols []IndexColumn
+
+	// numExplicitKeyCols is the number of explicit key columns in cols.
+	numExplicitKeyCols int
+
+	// numKeyCols is the number of explicit and implicit key columns in cols.
+	numKeyCols int
+}
+
+var _ Index = &HypotheticalIndex{}
+
+func (hi *HypotheticalIndex) init(
+	tab *HypotheticalTable, ordinal IndexOrdinal, id StableID, keyCols []IndexColumn,
+) {
+	hi.tab = tab
+	hi.ordinal = ordinal
+	hi.id = id
+	hi.name = tree.Name(fmt.Sprintf("_hyp_%d", ordinal-tab.Table.IndexCount()+1))
+	hi.numExplicitKeyCols = len(keyCols)
+	hi.cols = append(make([]IndexColumn, 0, tab.ColumnCount()), keyCols...)
+
+	var keyOrds util.FastIntSet
+	for _, col := range keyCols {
+		keyOrds.Add(col.Ordinal())
+	}
+
+	// Non-unique secondary indexes have the primary key columns as an implicit
+	// key suffix.
+	primary := tab.Table.Index(PrimaryIndex)
+	for i, n := 0, primary.KeyColumnCount(); i < n; i++ {
+		col := primary.Column(i)
+		if !keyOrds.Contains(col.Ordinal()) {
+			keyOrds.Add(col.Ordinal())
+			hi.cols = append(hi.cols, IndexColumn{Column: col.Column})
+		}
+	}
+	hi.numKeyCols = len(hi.cols)
+
+	for i, n := 0, tab.ColumnCount(); i < n; i++ {
+		col := tab.Column(i)
+		if keyOrds.Contains(i) || col.Kind() != Ordinary || col.IsMutation() || col.IsVirtualComputed() {
+			continue
+		}
+		hi.cols = append(hi.cols, IndexColumn{Column: col})
+	}
+}
+
+// ExplicitKeyColumnCount returns the number of key columns that would need to
+// be listed in a CREATE INDEX statement for the index. They are followed by
+// the primary key columns that are implicitly part of the key.
+func (hi *HypotheticalIndex) ExplicitKeyColumnCount() int {
+	return hi.numExplicitKeyCols
+}
+
+// ID is part of the Index interface.
+func (hi *HypotheticalIndex) ID() StableID {
+	return hi.id
+}
+
+// Name is part of the Index interface.
+func (hi *HypotheticalIndex) Name() tree.Name {
+	return hi.name
+}
+
+// Table is part of the Index interface.
+func (hi *HypotheticalIndex) Table() Table {
+	return hi.tab
+}
+
+// Ordinal is part of the Index interface.
+func (hi *HypotheticalIndex) Ordinal() int {
+	return hi.ordinal
+}
+
+// IsUnique is part of the Index interface.
+func (hi *HypotheticalIndex) IsUnique() bool {
+	return false
+}
+
+// IsInverted is part of the Index interface.
+func (hi *HypotheticalIndex) IsInverted() bool {
+	return false
+}
+
+// ColumnCount is part of the Index interface.
+func (hi *HypotheticalIndex) ColumnCount() int {
+	return len(hi.cols)
+}
+
+// KeyColumnCount is part of the Index interface.
+func (hi *HypotheticalIndex) KeyColumnCount() int {
+	return hi.numKeyCols
+}
+
+// LaxKeyColumnCount is part of the Index interface.
+func (hi *HypotheticalIndex) LaxKeyColumnCount() int {
+	return hi.numKeyCols
+}
+
+// NonInvertedPrefixColumnCount is part of the Index interface.
+func (hi *HypotheticalIndex) NonInvertedPrefixColumnCount() int {
+	panic(errors.AssertionFailedf("non-inverted indexes do not have inverted prefix columns"))
+}
+
+// Column is part of the Index interface.
+func (hi *HypotheticalIndex) Column(i int) IndexColumn {
+	return hi.cols[i]
+}
+
+// InvertedColumn is part of the Index interface.
+func (hi *HypotheticalIndex) InvertedColumn() IndexColumn {
+	panic(errors.AssertionFailedf("non-inverted indexes do not have inverted columns"))
+}
+
+// Predicate is part of the Index interface.
+func (hi *HypotheticalIndex) Predicate() (string, bool) {
+	return "", false
+}
+
+// Zone is part of the Index interface. A new index inherits the zone
+// configuration of its table.
+func (hi *HypotheticalIndex) Zone() Zone {
+	return hi.tab.Table.Index(PrimaryIndex).Zone()
+}
+
+// Span is part of the Index interface. Hypothetical indexes do not have any
+// data, so their span is empty.
+func (hi *HypotheticalIndex) Span() roachpb.Span {
+	return roachpb.Span{}
+}
+
+// ImplicitPartitioningColumnCount is part of the Index interface.
+func (hi *HypotheticalIndex) ImplicitPartitioningColumnCount() int {
+	return 0
+}
+
+// GeoConfig is part of the Index interface.
+func (hi *HypotheticalIndex) GeoConfig() *geoindex.Config {
+	return nil
+}
+
+// Version is part of the Index interface.
+func (hi *HypotheticalIndex) Version() descpb.IndexDescriptorVersion {
+	return descpb.StrictIndexColumnIDGuaranteesVersion
+}
+
+// PartitionCount is part of the Index interface.
+func (hi *HypotheticalIndex) PartitionCount() int {
+	return 0
+}
+
+// Partition is part of the Index interface.
+func (hi *HypotheticalIndex) Partition(i int) Partition {
+	panic(errors.AssertionFailedf("hypothetical indexes are not partitioned"))
+}
@@ -159,7 +159,7 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 	md := b.mem.Metadata()
 	tab := md.Table(ins.Table)
 
-	//  - there are no self-referencing foreign keys;
+	//  - there are no self-referencing or deferrable foreign keys;
 	//  - all FK checks can be performed using direct lookups into unique indexes.
 	fkChecks := make([]exec.InsertFastPathFKCheck, len(ins.FKChecks))
 	for i := range ins.FKChecks {
@@ -169,6 +169,10 @@ func (b *Builder) tryBuildFastPathInsert(ins *memo.InsertExpr) (_ execPlan, ok b
 			return execPlan{}, false, nil
 		}
 		fk := tab.OutboundForeignKey(c.FKOrdinal)
+		if fk.Deferrability() != tree.NotDeferrableConstraint {
+			// The fast path reports violations immediately.
+			return execPlan{}, false, nil
+		}
 		lookupJoin, isLookupJoin := c.Check.(*memo.LookupJoinExpr)
 		if !isLookupJoin || lookupJoin.JoinType != opt.AntiJoinOp {
 			// Not a lookup anti-join.
@@ -410,7 +414,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
 	cnt := len(ups.InsertCols) + len(ups.FetchCols) + len(ups.UpdateCols) + len(ups.CheckCols) +
-		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 1
+		len(ups.PartialIndexPutCols) + len(ups.PartialIndexDelCols) + 2
 	colList := make(opt.ColList, 0, cnt)
 	colList = appendColsWhenPresent(colList, ups.InsertCols)
 	colList = appendColsWhenPresent(colList, ups.FetchCols)
@@ -418,6 +422,9 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		colList = append(colList, ups.CanaryCol)
 	}
+	if ups.DeleteCol != 0 {
+		colList = append(colList, ups.DeleteCol)
+	}
 	colList = appendColsWhenPresent(colList, ups.CheckCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexPutCols)
 	colList = appendColsWhenPresent(colList, ups.PartialIndexDelCols)
@@ -434,6 +441,10 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 	if ups.CanaryCol != 0 {
 		canaryCol = input.getNodeColumnOrdinal(ups.CanaryCol)
 	}
+	deleteCol := exec.NodeColumnOrdinal(-1)
+	if ups.DeleteCol != 0 {
+		deleteCol = input.getNodeColumnOrdinal(ups.DeleteCol)
+	}
 	insertColOrds := ordinalSetFromColList(ups.InsertCols)
 	fetchColOrds := ordinalSetFromColList(ups.FetchCols)
 	updateColOrds := ordinalSetFromColList(ups.UpdateCols)
@@ -445,6 +456,7 @@ func (b *Builder) buildUpsert(ups *memo.UpsertExpr) (execPlan, error) {
 		ups.ArbiterIndexes,
 		ups.ArbiterConstraints,
 		canaryCol,
+		deleteCol,
 		insertColOrds,
 		fetchColOrds,
 		updateColOrds,
@@ -490,9 +502,17 @@ func (b *Builder) buildDelete(del *memo.DeleteExpr) (execPlan, error) {
 	//
 	// TODO(andyk): Using ensureColumns here can result in an extra Render.
 	// Upgrade execution engine to not require this.
-	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols))
+	colList := make(opt.ColList, 0, len(del.FetchCols)+len(del.PartialIndexDelCols)+len(del.PassthroughCols))
 	colList = appendColsWhenPresent(colList, del.FetchCols)
 	colList = appendColsWhenPresent(colList, del.PartialIndexDelCols)
+	// The RETURNING clause of the Delete can refer to the columns in any of the
+	// USING tables. As a result, the Delete may need to passthrough those
+	// columns so the projection above can use them. They must come last, since
+	// the Delete returns any input columns that follow the partial index
+	// delete columns.
+	if del.NeedResults() {
+		colList = append(colList, del.PassthroughCols...)
+	}
 
 	input, err := b.buildMutationInput(del, del.Input, colList, &del.MutationPrivate)
 	if err != nil {
@@ -692,14 +712,19 @@ func (b *Builder) buildUniqueChecks(checks memo.UniqueChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
+		keyOrdinals := checkKeyOrdinals(query, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+			keyVals := make(tree.Datums, len(keyOrdinals))
+			for i, ord := range keyOrdinals {
+				keyVals[i] = row[ord]
 			}
 			return mkUniqueCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		deferrable := uniqueCheckDeferrable(md, c)
+		if deferrable != nil {
+			deferrable.KeyOrdinals = keyOrdinals
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -718,14 +743,19 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 			return err
 		}
 		// Wrap the query in an error node.
+		keyOrdinals := checkKeyOrdinals(query, c.KeyCols)
 		mkErr := func(row tree.Datums) error {
-			keyVals := make(tree.Datums, len(c.KeyCols))
-			for i, col := range c.KeyCols {
-				keyVals[i] = row[query.getNodeColumnOrdinal(col)]
+			keyVals := make(tree.Datums, len(keyOrdinals))
+			for i, ord := range keyOrdinals {
+				keyVals[i] = row[ord]
 			}
 			return mkFKCheckErr(md, c, keyVals)
 		}
-		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr)
+		deferrable := fkCheckDeferrable(md, c)
+		if deferrable != nil {
+			deferrable.KeyOrdinals = keyOrdinals
+		}
+		node, err := b.factory.ConstructErrorIfRows(query.root, mkErr, deferrable)
 		if err != nil {
 			return err
 		}
@@ -734,6 +764,57 @@ func (b *Builder) buildFKChecks(checks memo.FKChecksExpr) error {
 	return nil
 }
 
+// checkKeyOrdinals returns the ordinals of the given key columns in the rows
+// returned by a check query.
+func checkKeyOrdinals(query execPlan, keyCols opt.ColList) []exec.NodeColumnOrdinal {
+	ords := make([]exec.NodeColumnOrdinal, len(keyCols))
+	for i, col := range keyCols {
+		ords[i] = query.getNodeColumnOrdinal(col)
+	}
+	return ords
+}
+
+// uniqueCheckDeferrable returns the deferrable constraint enforced by the given
+// uniqueness check, or nil if the constraint is not DEFERRABLE.
+func uniqueCheckDeferrable(md *opt.Metadata, c *memo.UniqueChecksItem) *exec.DeferrableConstraint {
+	uc := md.TableMeta(c.Table).Table.Unique(c.CheckOrdinal)
+	return makeDeferrableConstraint(uc.TableID(), uc.Name(), uc.Deferrability())
+}
+
+// fkCheckDeferrable returns the deferrable constraint enforced by the given FK
+// check, or nil if the check must be performed immediately.
+func fkCheckDeferrable(md *opt.Metadata, c *memo.FKChecksItem) *exec.DeferrableConstraint {
+	var fk cat.ForeignKeyConstraint
+	if c.FKOutbound {
+		fk = md.TableMeta(c.OriginTable).Table.OutboundForeignKey(c.FKOrdinal)
+	} else {
+		fk = md.TableMeta(c.ReferencedTable).Table.InboundForeignKey(c.FKOrdinal)
+		// Like in Postgres, a RESTRICT action is checked immediately even if the
+		// constraint is deferred.
+		action := fk.UpdateReferenceAction()
+		if c.OpName == "delete" {
+			action = fk.DeleteReferenceAction()
+		}
+		if action == tree.Restrict {
+			return nil
+		}
+	}
+	return makeDeferrableConstraint(fk.OriginTableID(), fk.Name(), fk.Deferrability())
+}
+
+func makeDeferrableConstraint(
+	tabID cat.StableID, name string, deferrability tree.ConstraintDeferrability,
+) *exec.DeferrableConstraint {
+	if deferrability == tree.NotDeferrableConstraint {
+		return nil
+	}
+	return &exec.DeferrableConstraint{
+		TableID:           tabID,
+		Name:              name,
+		InitiallyDeferred: deferrability == tree.DeferrableInitiallyDeferred,
+	}
+}
+
 // mkUniqueCheckErr generates a user-friendly error describing a uniqueness
 // violation. The keyVals are the values that correspond to the
 // cat.UniqueConstraint columns.
@@ -561,6 +561,9 @@ func (b *Builder) scanParams(
 		return exec.ScanParams{}, opt.ColMap{}, pgerror.Newf(pgcode.ReadOnlySQLTransaction,
 			"cannot execute %s in a read-only transaction", locking.Strength.String())
 	}
+	if err := checkSkipLocked(tab, locking); err != nil {
+		return exec.ScanParams{}, opt.ColMap{}, err
+	}
 
 	needed, outputMap := b.getColumns(scan.Cols, scan.Table)
 
@@ -1687,9 +1690,18 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 
 	cols := join.Cols
 	needed, output := b.getColumns(cols, join.Table)
+
+	locking := join.Locking
+	if b.forceForUpdateLocking {
+		locking = forUpdateLocking
+	}
+	if err := checkSkipLocked(tab, locking); err != nil {
+		return execPlan{}, err
+	}
+
 	res := execPlan{outputCols: output}
 	res.root, err = b.factory.ConstructIndexJoin(
-		input.root, tab, keyCols, needed, res.reqOrdering(join),
+		input.root, tab, keyCols, needed, res.reqOrdering(join), locking,
 	)
 	if err != nil {
 		return execPlan{}, err
@@ -1698,6 +1710,18 @@ func (b *Builder) buildIndexJoin(join *memo.IndexJoinExpr) (execPlan, error) {
 	return res, nil
 }
 
+// checkSkipLocked returns an error if the SKIP LOCKED wait policy is used to
+// read from a table with multiple column families. The column families of a
+// row are stored under separate keys which can be locked independently, so
+// skipping locked keys could return partial rows.
+func checkSkipLocked(tab cat.Table, locking *tree.LockingItem) error {
+	if locking == nil || locking.WaitPolicy != tree.LockWaitSkip || tab.FamilyCount() <= 1 {
+		return nil
+	}
+	return unimplemented.NewWithIssuef(40476,
+		"SKIP LOCKED cannot be used on tables with multiple column families")
+}
+
 func (b *Builder) buildLookupJoin(join *memo.LookupJoinExpr) (execPlan, error) {
 	md := b.mem.Metadata()
 
@@ -556,6 +556,7 @@ func (e *emitter) emitNodeAttributes(n *Node) error {
 			}
 		}
 		ob.VAttr("key columns", strings.Join(cols, ", "))
+		e.emitLockingPolicy(a.Locking)
 
 	case groupByOp:
 		a := n.args.(*groupByArgs)
@@ -145,7 +145,16 @@ func getResultColumns(
 
 	case deleteOp:
 		a := args.(*deleteArgs)
-		return tableColumns(a.Table, a.ReturnCols), nil
+		cols := tableColumns(a.Table, a.ReturnCols)
+		if len(cols) == 0 {
+			return cols, nil
+		}
+		// Any input columns following the fetch columns and the partial index
+		// delete columns are passed through from the USING tables.
+		if n := a.FetchCols.Len() + partialIndexCount(a.Table); n < len(inputs[0]) {
+			cols = appendColumns(cols, inputs[0][n:]...)
+		}
+		return cols, nil
 
 	case opaqueOp:
 		if args.(*opaqueArgs).Metadata != nil {
@@ -202,6 +211,18 @@ func tableColumns(table cat.Table, ordinals exec.TableColumnOrdinalSet) colinfo.
 	return cols
 }
 
+// partialIndexCount returns the number of partial indexes on the table, each
+// of which has a partial index delete column in the input of a delete.
+func partialIndexCount(table cat.Table) int {
+	count := 0
+	for i, n := 0, table.DeletableIndexCount(); i < n; i++ {
+		if _, isPartial := table.Index(i).Predicate(); isPartial {
+			count++
+		}
+	}
+	return count
+}
+
 func joinColumns(
 	joinType descpb.JoinType, left, right colinfo.ResultColumns,
 ) colinfo.ResultColumns {
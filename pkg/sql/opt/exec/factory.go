@@ -268,6 +268,26 @@ type InsertFastPathFKCheck struct {
 // relevant row.
 type MkErrFn func(tree.Datums) error
 
+// DeferrableConstraint identifies a DEFERRABLE constraint enforced by an
+// ErrorIfRows node. If the constraint is deferred in the current transaction,
+// a violation is not reported right away; instead the violating keys are
+// checked again when the transaction commits.
+type DeferrableConstraint struct {
+	// TableID is the table on which the constraint is defined. For foreign
+	// keys, this is the origin (referencing) table.
+	TableID cat.StableID
+	// Name is the name of the constraint.
+	Name string
+	// InitiallyDeferred is true if the constraint was declared INITIALLY
+	// DEFERRED.
+	InitiallyDeferred bool
+	// KeyOrdinals are the ordinals of the columns of the rows returned by the
+	// check which contain the violating key, in the order of the columns of
+	// the constraint. For foreign keys, the key is the same in the origin and
+	// referenced tables.
+	KeyOrdinals []NodeColumnOrdinal
+}
+
 // ExplainFactory is an extension of Factory used when constructing a plan that
 // can be explained. It allows annotation of nodes with extra information.
 type ExplainFactory interface {
@@ -0,0 +1,347 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+)
+
+// FindIndexCandidateSet walks through the given expression and returns the
+// key columns of the indexes that could speed it up, grouped by table. The
+// expression should be normalized but not explored, so that it still contains
+// the filters, joins, orderings and groupings written in the query, rather
+// than the index scans and lookup joins that serve them.
+//
+// Candidates are built from:
+//
+//  1. Columns that are compared to constants in filters. Each such column is a
+//     candidate on its own. The columns of a table that are constrained to
+//     equal a constant are also combined into a single candidate, and that
+//     candidate is extended with each column of the table that is
+//     constrained to a range.
+//  2. Columns that are compared to columns of another table in join
+//     conditions, both on their own and combined with the other join columns
+//     of the same table.
+//  3. The columns of a required ordering, up to the first column that does
+//     not belong to the same table as the first.
+//  4. The grouping columns of each table in a GROUP BY or DISTINCT ON.
+//
+// Candidates whose key is a prefix of the key of an existing index are
+// omitted, as are candidates on tables that cannot be indexed (virtual and
+// foreign tables) or that currently have mutation indexes. The result is nil
+// if the expression contains a mutation.
+func FindIndexCandidateSet(rootExpr opt.Expr, md *opt.Metadata) map[cat.Table][][]cat.IndexColumn {
+	var ics indexCandidateSet
+	ics.md = md
+	if !ics.categorizeIndexCandidates(rootExpr) {
+		return nil
+	}
+	if len(ics.tables) == 0 {
+		return nil
+	}
+	res := make(map[cat.Table][][]cat.IndexColumn, len(ics.tables))
+	for _, tc := range ics.tables {
+		if len(tc.candidates) > 0 {
+			res[tc.tab] = tc.candidates
+		}
+	}
+	return res
+}
+
+// indexCandidateSet accumulates index candidates while walking an expression.
+type indexCandidateSet struct {
+	md *opt.Metadata
+
+	// tables contains the candidates of each table, in the order in which the
+	// tables were first encountered.
+	tables []*tableCandidates
+}
+
+// tableCandidates contains the index candidates of a single table.
+type tableCandidates struct {
+	tab        cat.Table
+	candidates [][]cat.IndexColumn
+
+	// seen contains the keys of the candidates, for deduplication.
+	seen map[string]struct{}
+}
+
+// categorizeIndexCandidates adds the candidates found in the given expression
+// and its descendants. It returns false if the expression contains a mutation,
+// in which case no recommendations should be made.
+func (ics *indexCandidateSet) categorizeIndexCandidates(expr opt.Expr) bool {
+	if opt.IsMutationOp(expr) {
+		return false
+	}
+	switch t := expr.(type) {
+	case *memo.SelectExpr:
+		ics.addFilterCandidates(t.Filters)
+
+	case *memo.SortExpr:
+		ics.addOrderingCandidate(t.ProvidedPhysical().Ordering)
+
+	case *memo.GroupByExpr:
+		ics.addGroupingCandidates(t.GroupingCols)
+
+	case *memo.DistinctOnExpr:
+		ics.addGroupingCandidates(t.GroupingCols)
+
+	default:
+		if opt.IsJoinOp(expr) {
+			ics.addJoinCandidates(*expr.Child(2).(*memo.FiltersExpr))
+		}
+	}
+	for i, n := 0, expr.ChildCount(); i < n; i++ {
+		if !ics.categorizeIndexCandidates(expr.Child(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// addFilterCandidates adds candidates for the columns that are compared to
+// constants in the given filters.
+func (ics *indexCandidateSet) addFilterCandidates(filters memo.FiltersExpr) {
+	var eqCols, rangeCols columnsByTable
+	var joinCols columnsByTable
+	for i := range filters {
+		ics.collectComparisons(filters[i].Condition, &eqCols, &rangeCols, &joinCols)
+	}
+
+	for _, cols := range eqCols {
+		for _, col := range cols.cols {
+			ics.addCandidate(cols.tabID, []cat.IndexColumn{col})
+		}
+		ics.addCandidate(cols.tabID, cols.cols)
+	}
+	for _, cols := range rangeCols {
+		eq := eqCols.get(cols.tabID)
+		for _, col := range cols.cols {
+			ics.addCandidate(cols.tabID, []cat.IndexColumn{col})
+			if len(eq) > 0 {
+				ics.addCandidate(cols.tabID, append(eq[:len(eq):len(eq)], col))
+			}
+		}
+	}
+	ics.addColumnCandidates(joinCols)
+}
+
+// addJoinCandidates adds candidates for the columns that are compared to
+// columns of another table in the given join conditions.
+func (ics *indexCandidateSet) addJoinCandidates(on memo.FiltersExpr) {
+	var eqCols, rangeCols, joinCols columnsByTable
+	for i := range on {
+		ics.collectComparisons(on[i].Condition, &eqCols, &rangeCols, &joinCols)
+	}
+	ics.addColumnCandidates(joinCols)
+}
+
+// addColumnCandidates adds a candidate for each of the given columns, and a
+// candidate for the columns of each table combined.
+func (ics *indexCandidateSet) addColumnCandidates(colsByTable columnsByTable) {
+	for _, cols := range colsByTable {
+		for _, col := range cols.cols {
+			ics.addCandidate(cols.tabID, []cat.IndexColumn{col})
+		}
+		ics.addCandidate(cols.tabID, cols.cols)
+	}
+}
+
+// collectComparisons adds the columns that the given condition compares to
+// constants or to columns of other tables to the given column lists.
+func (ics *indexCandidateSet) collectComparisons(
+	cond opt.ScalarExpr, eqCols, rangeCols, joinCols *columnsByTable,
+) {
+	switch t := cond.(type) {
+	case *memo.AndExpr:
+		ics.collectComparisons(t.Left, eqCols, rangeCols, joinCols)
+		ics.collectComparisons(t.Right, eqCols, rangeCols, joinCols)
+
+	case *memo.RangeExpr:
+		ics.collectComparisons(t.And, eqCols, rangeCols, joinCols)
+
+	case *memo.EqExpr:
+		left, leftOk := t.Left.(*memo.VariableExpr)
+		right, rightOk := t.Right.(*memo.VariableExpr)
+		switch {
+		case leftOk && rightOk:
+			if ics.md.ColumnMeta(left.Col).Table != ics.md.ColumnMeta(right.Col).Table {
+				ics.addColumn(joinCols, left.Col, false /* descending */)
+				ics.addColumn(joinCols, right.Col, false /* descending */)
+			}
+		case leftOk && memo.CanExtractConstDatum(t.Right):
+			ics.addColumn(eqCols, left.Col, false /* descending */)
+		case rightOk && memo.CanExtractConstDatum(t.Left):
+			ics.addColumn(eqCols, right.Col, false /* descending */)
+		}
+
+	case *memo.InExpr, *memo.IsExpr:
+		if v, ok := t.Child(0).(*memo.VariableExpr); ok && memo.CanExtractConstDatum(t.Child(1)) {
+			ics.addColumn(eqCols, v.Col, false /* descending */)
+		}
+
+	case *memo.LtExpr, *memo.LeExpr, *memo.GtExpr, *memo.GeExpr:
+		left, right := t.Child(0), t.Child(1)
+		if v, ok := left.(*memo.VariableExpr); ok && memo.CanExtractConstDatum(right) {
+			ics.addColumn(rangeCols, v.Col, false /* descending */)
+		} else if v, ok := right.(*memo.VariableExpr); ok && memo.CanExtractConstDatum(left) {
+			ics.addColumn(rangeCols, v.Col, false /* descending */)
+		}
+	}
+}
+
+// addOrderingCandidate adds a candidate for the longest prefix of the given
+// ordering that refers to the columns of a single table.
+func (ics *indexCandidateSet) addOrderingCandidate(ordering opt.Ordering) {
+	var cols columnsByTable
+	for _, oc := range ordering {
+		if len(cols) > 0 && ics.md.ColumnMeta(oc.ID()).Table != cols[0].tabID {
+			break
+		}
+		if !ics.addColumn(&cols, oc.ID(), oc.Descending()) {
+			break
+		}
+	}
+	if len(cols) > 0 {
+		ics.addCandidate(cols[0].tabID, cols[0].cols)
+	}
+}
+
+// addGroupingCandidates adds a candidate for the grouping columns of each
+// table.
+func (ics *indexCandidateSet) addGroupingCandidates(groupingCols opt.ColSet) {
+	var cols columnsByTable
+	groupingCols.ForEach(func(col opt.ColumnID) {
+		ics.addColumn(&cols, col, false /* descending */)
+	})
+	for i := range cols {
+		ics.addCandidate(cols[i].tabID, cols[i].cols)
+	}
+}
+
+// addColumn adds the given column to the list of columns of its table, if it
+// is a column of a table that can be indexed. It returns false if it is not.
+func (ics *indexCandidateSet) addColumn(
+	colsByTable *columnsByTable, colID opt.ColumnID, descending bool,
+) bool {
+	tabID := ics.md.ColumnMeta(colID).Table
+	if tabID == 0 {
+		return false
+	}
+	tab := ics.md.Table(tabID)
+	if tab.IsVirtualTable() || tab.IsForeignTable() || tab.DeletableIndexCount() != tab.IndexCount() {
+		return false
+	}
+	col := tab.Column(tabID.ColumnOrdinal(colID))
+	if col.Kind() != cat.Ordinary || col.IsMutation() || !colinfo.ColumnTypeIsIndexable(col.DatumType()) {
+		return false
+	}
+	colsByTable.add(tabID, cat.IndexColumn{Column: col, Descending: descending})
+	return true
+}
+
+// addCandidate adds an index candidate with the given key columns for the
+// given table, unless it is a duplicate or an existing index already has the
+// same key prefix.
+func (ics *indexCandidateSet) addCandidate(tabID opt.TableID, cols []cat.IndexColumn) {
+	if len(cols) == 0 {
+		return
+	}
+	tab := ics.md.Table(tabID)
+	for i, n := 0, tab.IndexCount(); i < n; i++ {
+		if hasKeyPrefix(tab.Index(i), cols) {
+			return
+		}
+	}
+
+	var tc *tableCandidates
+	for _, t := range ics.tables {
+		if t.tab.ID() == tab.ID() {
+			tc = t
+			break
+		}
+	}
+	if tc == nil {
+		tc = &tableCandidates{tab: tab, seen: make(map[string]struct{})}
+		ics.tables = append(ics.tables, tc)
+	}
+
+	var key strings.Builder
+	for _, col := range cols {
+		key.WriteString(string(col.ColName()))
+		if col.Descending {
+			key.WriteString(" DESC")
+		}
+		key.WriteByte(',')
+	}
+	if _, ok := tc.seen[key.String()]; ok {
+		return
+	}
+	tc.seen[key.String()] = struct{}{}
+	tc.candidates = append(tc.candidates, append([]cat.IndexColumn(nil), cols...))
+}
+
+// hasKeyPrefix returns true if the given columns are a prefix of the key
+// columns of the given index, with the same directions.
+func hasKeyPrefix(index cat.Index, cols []cat.IndexColumn) bool {
+	if index.IsInverted() || len(cols) > index.LaxKeyColumnCount() {
+		return false
+	}
+	if _, isPartial := index.Predicate(); isPartial {
+		return false
+	}
+	for i := range cols {
+		col := index.Column(i)
+		if col.Ordinal() != cols[i].Ordinal() || col.Descending != cols[i].Descending {
+			return false
+		}
+	}
+	return true
+}
+
+// columnsByTable contains lists of distinct columns, grouped by table in the
+// order in which the tables were first added.
+type columnsByTable []tableColumns
+
+type tableColumns struct {
+	tabID opt.TableID
+	cols  []cat.IndexColumn
+}
+
+func (c *columnsByTable) add(tabID opt.TableID, col cat.IndexColumn) {
+	for i := range *c {
+		tc := &(*c)[i]
+		if tc.tabID == tabID {
+			for j := range tc.cols {
+				if tc.cols[j].Ordinal() == col.Ordinal() {
+					return
+				}
+			}
+			tc.cols = append(tc.cols, col)
+			return
+		}
+	}
+	*c = append(*c, tableColumns{tabID: tabID, cols: []cat.IndexColumn{col}})
+}
+
+func (c columnsByTable) get(tabID opt.TableID) []cat.IndexColumn {
+	for i := range c {
+		if c[i].tabID == tabID {
+			return c[i].cols
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package indexrec
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil"
+)
+
+// Recommend returns CREATE INDEX statements for the indexes that would lower
+// the cost of the query in the given optimizer. The optimizer's memo must have
+// been built by the optbuilder, but not yet optimized.
+//
+// Recommend first normalizes the query and finds the index candidates (see
+// FindIndexCandidateSet). It then optimizes the query again with each table
+// replaced by a cat.HypotheticalTable that has the candidate indexes, so that
+// the optimizer costs plans that use them alongside the existing indexes. The
+// hypothetical indexes that are part of the lowest cost plan are recommended.
+//
+// The optimizer is left with a memo that refers to hypothetical tables, so it
+// must be re-initialized before it is used to plan the query for execution.
+func Recommend(ctx context.Context, o *xform.Optimizer, catalog cat.Catalog) (_ []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			// This code allows us to propagate internal errors without having to
+			// add error checks everywhere throughout the code. This is only
+			// possible because the code does not update shared state and does not
+			// manipulate locks.
+			if ok, e := errorutil.ShouldCatch(r); ok {
+				err = e
+			} else {
+				panic(r)
+			}
+		}
+	}()
+
+	f := o.Factory()
+	evalCtx := f.EvalContext()
+	savedMemo := o.DetachMemo()
+	copyMemo := func() {
+		var replaceFn norm.ReplaceFunc
+		replaceFn = func(e opt.Expr) opt.Expr {
+			return f.CopyAndReplaceDefault(e, replaceFn)
+		}
+		f.FoldingControl().AllowStableFolds()
+		f.CopyAndReplace(savedMemo.RootExpr().(memo.RelExpr), savedMemo.RootProps(), replaceFn)
+	}
+
+	// Optimize the memo with normalization rules only. This adds the sort
+	// enforcers for the required orderings, but does not yet replace filters and
+	// joins with index scans and lookup joins.
+	copyMemo()
+	o.NotifyOnMatchedRule(func(ruleName opt.RuleName) bool {
+		return ruleName.IsNormalize()
+	})
+	if _, err := o.Optimize(); err != nil {
+		return nil, err
+	}
+	candidates := FindIndexCandidateSet(f.Memo().RootExpr(), f.Metadata())
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	hypTables := make(map[cat.StableID]cat.Table, len(candidates))
+	for tab, indexKeyCols := range candidates {
+		hypTables[tab.ID()] = cat.NewHypotheticalTable(tab, indexKeyCols)
+	}
+
+	// Fully optimize the memo with the hypothetical indexes. Zigzag joins are
+	// disabled, since a single index with the key columns of both sides of the
+	// join would always be a better recommendation than two indexes.
+	o.Init(evalCtx, catalog)
+	copyMemo()
+	f.Metadata().UpdateTableMeta(hypTables)
+	o.NotifyOnMatchedRule(func(ruleName opt.RuleName) bool {
+		return ruleName != opt.GenerateZigzagJoins && ruleName != opt.GenerateInvertedIndexZigzagJoins
+	})
+	if _, err := o.Optimize(); err != nil {
+		return nil, err
+	}
+	return FindRecs(ctx, f.Memo().RootExpr(), f.Metadata(), catalog)
+}
+
+// FindRecs walks through the given optimized expression and returns a CREATE
+// INDEX statement for each hypothetical index that it uses. Each statement
+// stores the columns that the expression reads from the index besides its key
+// columns. The statements are ordered by table and by index.
+func FindRecs(
+	ctx context.Context, expr opt.Expr, md *opt.Metadata, catalog cat.Catalog,
+) ([]string, error) {
+	var recs indexRecs
+	recs.md = md
+	recs.walk(expr)
+
+	res := make([]string, 0, len(recs.indexes))
+	for _, tab := range md.AllTables() {
+		hypTab, ok := tab.Table.(*cat.HypotheticalTable)
+		if !ok {
+			continue
+		}
+		for i, n := hypTab.Table.IndexCount(), hypTab.IndexCount(); i < n; i++ {
+			index := hypTab.Index(i).(*cat.HypotheticalIndex)
+			storedOrds, ok := recs.indexes[index]
+			if !ok {
+				continue
+			}
+			// A table can be referenced more than once by a query, but each
+			// index is only recommended once.
+			delete(recs.indexes, index)
+
+			tn, err := catalog.FullyQualifiedName(ctx, hypTab.Table)
+			if err != nil {
+				return nil, err
+			}
+			createIndex := tree.CreateIndex{
+				Table:   tn,
+				Columns: make(tree.IndexElemList, index.ExplicitKeyColumnCount()),
+			}
+			for j := range createIndex.Columns {
+				col := index.Column(j)
+				createIndex.Columns[j].Column = col.ColName()
+				if col.Descending {
+					createIndex.Columns[j].Direction = tree.Descending
+				}
+			}
+			for j, m := index.KeyColumnCount(), index.ColumnCount(); j < m; j++ {
+				if col := index.Column(j); storedOrds.Contains(col.Ordinal()) {
+					createIndex.Storing = append(createIndex.Storing, col.ColName())
+				}
+			}
+			res = append(res, tree.AsString(&createIndex)+";")
+		}
+	}
+	return res, nil
+}
+
+// indexRecs collects the hypothetical indexes used by an expression, along
+// with the ordinals of the columns that are read from each of them.
+type indexRecs struct {
+	md      *opt.Metadata
+	indexes map[*cat.HypotheticalIndex]util.FastIntSet
+}
+
+func (ir *indexRecs) walk(expr opt.Expr) {
+	switch t := expr.(type) {
+	case *memo.ScanExpr:
+		ir.add(t.Table, t.Index, t.Cols)
+
+	case *memo.LookupJoinExpr:
+		ir.add(t.Table, t.Index, t.Cols)
+	}
+	for i, n := 0, expr.ChildCount(); i < n; i++ {
+		ir.walk(expr.Child(i))
+	}
+}
+
+// add records that the given columns are read from the given index, if it is
+// hypothetical. Columns of other tables are ignored.
+func (ir *indexRecs) add(tabID opt.TableID, indexOrd cat.IndexOrdinal, cols opt.ColSet) {
+	index, ok := ir.md.Table(tabID).Index(indexOrd).(*cat.HypotheticalIndex)
+	if !ok {
+		return
+	}
+	if ir.indexes == nil {
+		ir.indexes = make(map[*cat.HypotheticalIndex]util.FastIntSet)
+	}
+	ords := ir.indexes[index]
+	cols.ForEach(func(col opt.ColumnID) {
+		if ir.md.ColumnMeta(col).Table == tabID {
+			ords.Add(tabID.ColumnOrdinal(col))
+		}
+	})
+	ir.indexes[index] = ords
+}
@@ -345,6 +345,10 @@ func (j *jsonOrArrayFilterPlanner) extractInvertedFilterConditionFromLeaf(
 		if fetch, ok := t.Left.(*memo.FetchValExpr); ok {
 			invertedExpr = j.extractJSONFetchValEqCondition(evalCtx, fetch, t.Right)
 		}
+	case *memo.JsonPathExistsExpr:
+		invertedExpr = j.extractJSONPathCondition(t.Left, t.Right, false /* match */)
+	case *memo.TSMatchExpr:
+		invertedExpr = j.extractJSONPathCondition(t.Left, t.Right, true /* match */)
 	}
 
 	if invertedExpr == nil {
@@ -411,6 +415,37 @@ func (j *jsonOrArrayFilterPlanner) extractJSONOrArrayContainsCondition(
 	return getInvertedExprForJSONOrArrayIndexForContaining(evalCtx, d)
 }
 
+// extractJSONPathCondition extracts an InvertedExpression representing an
+// inverted filter over the planner's inverted index, based on a jsonb @?
+// jsonpath expression, or a jsonb @@ jsonpath expression if match is true.
+// The left expression must be a variable or expression referencing the
+// inverted column, and the right expression a constant jsonpath. Only the
+// comparisons of the path to constants are used to constrain the index, so
+// the InvertedExpression is never tight.
+func (j *jsonOrArrayFilterPlanner) extractJSONPathCondition(
+	left, right opt.ScalarExpr, match bool,
+) inverted.Expression {
+	if !isIndexColumn(j.tabID, j.index, left, j.computedColumns) ||
+		left.DataType().Family() != types.JsonFamily || !memo.CanExtractConstDatum(right) {
+		return inverted.NonInvertedColExpression{}
+	}
+	d, ok := tree.AsDJsonpath(memo.ExtractConstDatum(right))
+	if !ok {
+		return inverted.NonInvertedColExpression{}
+	}
+	var invertedExpr inverted.Expression
+	var err error
+	if match {
+		invertedExpr, err = d.EncodeMatchInvertedIndexSpans(nil /* inKey */)
+	} else {
+		invertedExpr, err = d.EncodeExistsInvertedIndexSpans(nil /* inKey */)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return invertedExpr
+}
+
 // extractJSONFetchValEqCondition extracts an InvertedExpression representing an
 // inverted filter over the planner's inverted index, based on equality between
 // a chain of fetch val expressions and a scalar expression. If an
@@ -0,0 +1,95 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package invertedidx
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/invertedexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+type trigramFilterPlanner struct {
+	tabID           opt.TableID
+	index           cat.Index
+	computedColumns map[opt.ColumnID]opt.ScalarExpr
+}
+
+var _ invertedFilterPlanner = &trigramFilterPlanner{}
+
+// extractInvertedFilterConditionFromLeaf is part of the invertedFilterPlanner
+// interface.
+func (t *trigramFilterPlanner) extractInvertedFilterConditionFromLeaf(
+	evalCtx *tree.EvalContext, expr opt.ScalarExpr,
+) (
+	invertedExpr inverted.Expression,
+	remainingFilters opt.ScalarExpr,
+	_ *invertedexpr.PreFiltererStateForInvertedFilterer,
+) {
+	var trigrams []string
+	var allMustMatch bool
+	switch e := expr.(type) {
+	case *memo.ModExpr:
+		// A string is similar to the constant only if it shares at least one
+		// trigram with it. If the similarity threshold is 0, every string is
+		// similar to the constant, and the index can't be used.
+		if evalCtx.SessionData().TrigramSimilarityThreshold <= 0 {
+			break
+		}
+		// % is commutative, so the index column may be on either side.
+		if s, ok := t.extractConstString(e.Left, e.Right); ok {
+			trigrams = trigram.MakeTrigrams(s)
+		} else if s, ok := t.extractConstString(e.Right, e.Left); ok {
+			trigrams = trigram.MakeTrigrams(s)
+		}
+	case *memo.LikeExpr:
+		if s, ok := t.extractConstString(e.Left, e.Right); ok {
+			trigrams, allMustMatch = trigram.MakeLikePatternTrigrams(s), true
+		}
+	case *memo.ILikeExpr:
+		// Trigrams are lowercased, so ILIKE is handled like LIKE.
+		if s, ok := t.extractConstString(e.Left, e.Right); ok {
+			trigrams, allMustMatch = trigram.MakeLikePatternTrigrams(s), true
+		}
+	case *memo.EqExpr:
+		if s, ok := t.extractConstString(e.Left, e.Right); ok {
+			trigrams, allMustMatch = trigram.MakeTrigrams(s), true
+		} else if s, ok := t.extractConstString(e.Right, e.Left); ok {
+			trigrams, allMustMatch = trigram.MakeTrigrams(s), true
+		}
+	}
+
+	if len(trigrams) == 0 {
+		// An inverted expression could not be extracted.
+		return inverted.NonInvertedColExpression{}, expr, nil
+	}
+
+	// Trigram spans are never tight, so the original filter must always be
+	// applied after the inverted index scan.
+	invertedExpr = trigram.EncodeInvertedIndexSpans(nil /* inKey */, trigrams, allMustMatch)
+
+	// We do not currently support pre-filtering for trigram indexes, so the
+	// returned pre-filter state is nil.
+	return invertedExpr, expr, nil
+}
+
+// extractConstString returns the constant string of val if col is the indexed
+// string column and val is a constant string.
+func (t *trigramFilterPlanner) extractConstString(col, val opt.ScalarExpr) (string, bool) {
+	if !isIndexColumn(t.tabID, t.index, col, t.computedColumns) || !memo.CanExtractConstDatum(val) {
+		return "", false
+	}
+	d, ok := tree.AsDString(memo.ExtractConstDatum(val))
+	return string(d), ok
+}
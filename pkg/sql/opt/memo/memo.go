@@ -151,6 +151,7 @@ type Memo struct {
 	disallowFullTableScans  bool
 	largeFullScanRows       float64
 	nullOrderedLast         bool
+	trigramSimThreshold     float64
 
 	// curRank is the highest currently in-use scalar expression rank.
 	curRank opt.ScalarRank
@@ -197,6 +198,7 @@ func (m *Memo) Init(evalCtx *tree.EvalContext) {
 		disallowFullTableScans:  evalCtx.SessionData().DisallowFullTableScans,
 		largeFullScanRows:       evalCtx.SessionData().LargeFullScanRows,
 		nullOrderedLast:         evalCtx.SessionData().NullOrderedLast,
+		trigramSimThreshold:     evalCtx.SessionData().TrigramSimilarityThreshold,
 	}
 	m.metadata.Init()
 	m.logPropsBuilder.init(evalCtx, m)
@@ -312,7 +314,8 @@ func (m *Memo) IsStale(
 		m.propagateInputOrdering != evalCtx.SessionData().PropagateInputOrdering ||
 		m.disallowFullTableScans != evalCtx.SessionData().DisallowFullTableScans ||
 		m.largeFullScanRows != evalCtx.SessionData().LargeFullScanRows ||
-		m.nullOrderedLast != evalCtx.SessionData().NullOrderedLast {
+		m.nullOrderedLast != evalCtx.SessionData().NullOrderedLast ||
+		m.trigramSimThreshold != evalCtx.SessionData().TrigramSimilarityThreshold {
 		return true, nil
 	}
 
@@ -272,6 +272,12 @@ func TestMemoIsStale(t *testing.T) {
 	evalCtx.SessionData().NullOrderedLast = false
 	notStale()
 
+	// Stale trigram similarity threshold.
+	evalCtx.SessionData().TrigramSimilarityThreshold = 0.5
+	stale()
+	evalCtx.SessionData().TrigramSimilarityThreshold = 0
+	notStale()
+
 	// Stale data sources and schema. Create new catalog so that data sources are
 	// recreated and can be modified independently.
 	catalog = testcat.New()
@@ -492,6 +492,19 @@ func (md *Metadata) Table(tabID TableID) cat.Table {
 	return md.TableMeta(tabID).Table
 }
 
+// UpdateTableMeta replaces the catalog table of every table metadata entry
+// whose table has an entry with the same ID in the given map. It is used to
+// swap in tables with hypothetical indexes when generating index
+// recommendations. The replacement tables must have the same columns as the
+// tables they replace.
+func (md *Metadata) UpdateTableMeta(tables map[cat.StableID]cat.Table) {
+	for i := range md.tables {
+		if tab, ok := tables[md.tables[i].Table.ID()]; ok {
+			md.tables[i].Table = tab
+		}
+	}
+}
+
 // AllTables returns the metadata for all tables. The result must not be
 // modified.
 func (md *Metadata) AllTables() []TableMeta {
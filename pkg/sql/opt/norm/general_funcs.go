@@ -64,6 +64,13 @@ func (c *CustomFuncs) IsTimestampTZ(scalar opt.ScalarExpr) bool {
 	return scalar.DataType().Family() == types.TimestampTZFamily
 }
 
+// IsRange returns true if the given scalar expression is of a range type. The
+// +, - and * operators on ranges are union, difference and intersection, which
+// cannot be moved across a comparison like their arithmetic counterparts.
+func (c *CustomFuncs) IsRange(scalar opt.ScalarExpr) bool {
+	return scalar.DataType().Family() == types.RangeFamily
+}
+
 // BoolType returns the boolean SQL type.
 func (c *CustomFuncs) BoolType() *types.T {
 	return types.Bool
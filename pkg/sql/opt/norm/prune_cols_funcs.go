@@ -77,6 +77,9 @@ func (c *CustomFuncs) neededMutationCols(
 	if private.CanaryCol != 0 {
 		cols.Add(private.CanaryCol)
 	}
+	if private.DeleteCol != 0 {
+		cols.Add(private.DeleteCol)
+	}
 
 	if private.WithID != 0 {
 		for i := range uniqueChecks {
@@ -126,6 +129,26 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 	}
 
+	// addDeleteCols adds the columns needed to delete existing rows.
+	addDeleteCols := func() {
+		// Add in all strict key columns from all indexes, since these are needed
+		// to compose the keys of rows to delete. Include mutation indexes, since
+		// it is necessary to delete rows even from indexes that are being added
+		// or dropped.
+		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
+			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
+		}
+
+		// Add inbound foreign keys that may require a check or cascade.
+		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
+			inboundFK := tabMeta.Table.InboundForeignKey(i)
+			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
+				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
+				cols.Add(tabMeta.MetaID.ColumnID(ord))
+			}
+		}
+	}
+
 	// Retain any FetchCols that are needed for ReturnCols. If a RETURN column
 	// is needed, then:
 	//   1. For Delete, the corresponding FETCH column is always needed, since
@@ -223,22 +246,13 @@ func (c *CustomFuncs) NeededMutationFetchCols(
 		}
 
 	case opt.DeleteOp:
-		// Add in all strict key columns from all indexes, since these are needed
-		// to compose the keys of rows to delete. Include mutation indexes, since
-		// it is necessary to delete rows even from indexes that are being added
-		// or dropped.
-		for i, n := 0, tabMeta.Table.DeletableIndexCount(); i < n; i++ {
-			cols.UnionWith(tabMeta.IndexKeyColumnsMapInverted(i))
-		}
+		addDeleteCols()
+	}
 
-		// Add inbound foreign keys that may require a check or cascade.
-		for i, n := 0, tabMeta.Table.InboundForeignKeyCount(); i < n; i++ {
-			inboundFK := tabMeta.Table.InboundForeignKey(i)
-			for j, m := 0, inboundFK.ColumnCount(); j < m; j++ {
-				ord := inboundFK.ReferencedColumnOrdinal(tabMeta.Table, j)
-				cols.Add(tabMeta.MetaID.ColumnID(ord))
-			}
-		}
+	// An Upsert built for a MERGE statement can also delete rows, so it needs
+	// the same columns as a Delete.
+	if op == opt.UpsertOp && private.DeleteCol != 0 {
+		addDeleteCols()
 	}
 
 	return cols
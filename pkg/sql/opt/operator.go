@@ -144,6 +144,8 @@ var ComparisonOpReverseMap = map[Operator]tree.ComparisonOperatorSymbol{
 	OverlapsOp:       tree.Overlaps,
 	BBoxCoversOp:     tree.RegMatch,
 	BBoxIntersectsOp: tree.Overlaps,
+	TSMatchOp:        tree.TSMatches,
+	JsonPathExistsOp: tree.JSONPathExists,
 }
 
 // BinaryOpReverseMap maps from an optimizer operator type to a semantic tree
@@ -162,6 +164,10 @@ var BinaryOpReverseMap = map[Operator]tree.BinaryOperatorSymbol{
 	ConcatOp:        tree.Concat,
 	LShiftOp:        tree.LShift,
 	RShiftOp:        tree.RShift,
+	DistanceOp:      tree.Distance,
+	OverLeftOp:      tree.OverLeft,
+	OverRightOp:     tree.OverRight,
+	AdjacentOp:      tree.Adjacent,
 	FetchValOp:      tree.JSONFetchVal,
 	FetchTextOp:     tree.JSONFetchText,
 	FetchValPathOp:  tree.JSONFetchValPath,
@@ -226,6 +232,8 @@ var AggregateOpReverseMap = map[Operator]string{
 	STUnionOp:             "st_union",
 	STCollectOp:           "st_collect",
 	STExtentOp:            "st_extent",
+	RangeAggOp:            "range_agg",
+	RangeIntersectAggOp:   "range_intersect_agg",
 }
 
 // WindowOpReverseMap maps from an optimizer operator type to the name of a
@@ -277,7 +285,8 @@ func ScalarOperatorTransmitsNulls(op Operator) bool {
 	case BitandOp, BitorOp, BitxorOp, PlusOp, MinusOp, MultOp, DivOp, FloorDivOp,
 		ModOp, PowOp, EqOp, NeOp, LtOp, GtOp, LeOp, GeOp, LikeOp, NotLikeOp, ILikeOp,
 		NotILikeOp, SimilarToOp, NotSimilarToOp, RegMatchOp, NotRegMatchOp, RegIMatchOp,
-		NotRegIMatchOp, ConstOp, BBoxCoversOp, BBoxIntersectsOp:
+		NotRegIMatchOp, ConstOp, BBoxCoversOp, BBoxIntersectsOp, TSMatchOp, JsonPathExistsOp,
+		DistanceOp, OverLeftOp, OverRightOp, AdjacentOp:
 		return true
 
 	default:
@@ -293,7 +302,7 @@ func BoolOperatorRequiresNotNullArgs(op Operator) bool {
 		EqOp, LtOp, LeOp, GtOp, GeOp, NeOp,
 		LikeOp, NotLikeOp, ILikeOp, NotILikeOp, SimilarToOp, NotSimilarToOp,
 		RegMatchOp, NotRegMatchOp, RegIMatchOp, NotRegIMatchOp, BBoxCoversOp,
-		BBoxIntersectsOp:
+		BBoxIntersectsOp, TSMatchOp, JsonPathExistsOp:
 		return true
 	}
 	return false
@@ -324,7 +333,7 @@ func AggregateIgnoresNulls(op Operator) bool {
 		PercentileContOp, STMakeLineOp, STCollectOp, STExtentOp, STUnionOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, RangeAggOp, RangeIntersectAggOp:
 		return true
 
 	case ArrayAggOp, ConcatAggOp, ConstAggOp, CountRowsOp, FirstAggOp, JsonAggOp,
@@ -350,7 +359,7 @@ func AggregateIsNullOnEmpty(op Operator) bool {
 		JsonObjectAggOp, JsonbObjectAggOp, StdDevPopOp, STCollectOp, STExtentOp, STUnionOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp:
+		RegressionSXYOp, RegressionSYYOp, RangeAggOp, RangeIntersectAggOp:
 		return true
 
 	case CountOp, CountRowsOp, RegressionCountOp:
@@ -378,7 +387,7 @@ func AggregateIsNeverNullOnNonNullInput(op Operator) bool {
 		StringAggOp, SumOp, SumIntOp, XorAggOp, PercentileDiscOp, PercentileContOp,
 		JsonObjectAggOp, JsonbObjectAggOp, StdDevPopOp, STCollectOp, STExtentOp, STUnionOp,
 		VarPopOp, CovarPopOp, RegressionAvgXOp, RegressionAvgYOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, RangeAggOp, RangeIntersectAggOp:
 		return true
 
 	case VarianceOp, StdDevOp, CorrOp, CovarSampOp, RegressionInterceptOp,
@@ -420,7 +429,8 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 
 	case AnyNotNullAggOp, BitAndAggOp, BitOrAggOp, BoolAndOp,
 		BoolOrOp, ConstAggOp, ConstNotNullAggOp, FirstAggOp,
-		MaxOp, MinOp, STMakeLineOp, STExtentOp, STUnionOp, SumOp, SumIntOp, XorAggOp:
+		MaxOp, MinOp, STMakeLineOp, STExtentOp, STUnionOp, SumOp, SumIntOp, XorAggOp,
+		RangeIntersectAggOp:
 		return inner == outer
 
 	case CountOp, CountRowsOp:
@@ -433,7 +443,7 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 		SqrDiffOp, STCollectOp, StdDevOp, StringAggOp, VarianceOp, StdDevPopOp,
 		VarPopOp, CovarPopOp, CovarSampOp, RegressionAvgXOp, RegressionAvgYOp,
 		RegressionInterceptOp, RegressionR2Op, RegressionSlopeOp, RegressionSXXOp,
-		RegressionSXYOp, RegressionSYYOp, RegressionCountOp:
+		RegressionSXYOp, RegressionSYYOp, RegressionCountOp, RangeAggOp:
 		return false
 
 	default:
@@ -446,7 +456,8 @@ func AggregatesCanMerge(inner, outer Operator) bool {
 func AggregateIgnoresDuplicates(op Operator) bool {
 	switch op {
 	case AnyNotNullAggOp, BitAndAggOp, BitOrAggOp, BoolAndOp, BoolOrOp,
-		ConstAggOp, ConstNotNullAggOp, FirstAggOp, MaxOp, MinOp, STExtentOp, STUnionOp:
+		ConstAggOp, ConstNotNullAggOp, FirstAggOp, MaxOp, MinOp, STExtentOp, STUnionOp,
+		RangeAggOp, RangeIntersectAggOp:
 		return true
 
 	case ArrayAggOp, AvgOp, ConcatAggOp, CountOp, CorrOp, CountRowsOp, SumIntOp,
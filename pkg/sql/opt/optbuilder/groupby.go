@@ -41,10 +41,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -100,6 +102,21 @@ type groupby struct {
 	// It is used to ensure that the builder does not throw a grouping error
 	// prematurely.
 	buildingGroupingCols bool
+
+	// groupingSets is set if the GROUP BY clause contains ROLLUP, CUBE or
+	// GROUPING SETS items which expand to more than one grouping set. Each set
+	// contains the aggOutScope grouping columns which it groups by; the other
+	// grouping columns are NULL in the rows that the set produces. For example:
+	//
+	//   SELECT a, b, count(*) FROM ab GROUP BY ROLLUP (a, b)
+	//
+	// has the grouping sets (a, b), (a) and ().
+	groupingSets []opt.ColSet
+
+	// groupingSetCol is the aggOutScope column which contains the ordinal in
+	// groupingSets of the grouping set that produced each row. It is only set
+	// if groupingSets is set.
+	groupingSetCol opt.ColumnID
 }
 
 // groupByStrSet is a set of stringified GROUP BY expressions that map to the
@@ -336,6 +353,69 @@ func (b *Builder) buildGroupingColumns(sel *tree.SelectClause, projectionsScope,
 
 	// Copy the grouping columns to the aggOutScope.
 	g.aggOutScope.appendColumns(g.groupingCols())
+
+	if g.groupingSets != nil {
+		b.buildGroupingSetCols(g)
+	}
+}
+
+// buildGroupingSetCols prepares the aggOutScope for an aggregation with
+// multiple grouping sets. Each grouping column which is not part of every
+// grouping set is replaced in the aggOutScope by a new column, which is NULL
+// in the rows produced by the grouping sets that don't contain it (see
+// buildGroupingSetsInput). A column which contains the ordinal of the grouping
+// set of each row is also added.
+func (b *Builder) buildGroupingSetCols(g *groupby) {
+	var inAllSets opt.ColSet
+	for i, set := range g.groupingSets {
+		if i == 0 {
+			inAllSets = set.Copy()
+		} else {
+			inAllSets.IntersectionWith(set)
+		}
+	}
+
+	groupingCols := g.groupingCols()
+	outCols := g.aggOutScope.cols[len(g.aggOutScope.cols)-len(groupingCols):]
+	md := b.factory.Metadata()
+	newIDs := make(map[opt.ColumnID]opt.ColumnID)
+	for i := range outCols {
+		col := &outCols[i]
+		if inAllSets.Contains(col.id) {
+			continue
+		}
+		// The same column can be projected by several grouping expressions.
+		newID, ok := newIDs[col.id]
+		if !ok {
+			newID = md.AddColumn(col.name.MetadataName(), col.typ)
+			newIDs[col.id] = newID
+		}
+		col.id = newID
+		col.scalar = nil
+	}
+	for inID, outID := range newIDs {
+		for i := range g.groupingSets {
+			if g.groupingSets[i].Contains(inID) {
+				g.groupingSets[i].Remove(inID)
+				g.groupingSets[i].Add(outID)
+			}
+		}
+	}
+
+	// The SELECT list, HAVING and ORDER BY expressions which match a GROUP BY
+	// expression must refer to the aggOutScope columns.
+	for str, col := range g.groupStrs {
+		for i := range groupingCols {
+			if col.id == groupingCols[i].id {
+				g.groupStrs[str] = &outCols[i]
+				break
+			}
+		}
+	}
+
+	g.groupingSetCol = b.synthesizeColumn(
+		g.aggOutScope, scopeColName("").WithMetadataName("grouping_set"), types.Int, nil, nil,
+	).id
 }
 
 // buildAggregation builds the aggregation operators and constructs the
@@ -354,6 +434,10 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// If there are any aggregates that are ordering sensitive, build the
 	// aggregations as window functions over each group.
 	if g.hasNonCommutativeAggregates() {
+		if g.groupingSets != nil {
+			panic(unimplemented.NewWithIssue(46280,
+				"ordered aggregates are not supported with ROLLUP, CUBE or GROUPING SETS"))
+		}
 		return b.buildAggregationAsWindow(groupingColSet, having, fromScope)
 	}
 
@@ -430,12 +514,16 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	// aggregate arguments, as well as any additional order by columns.
 	b.constructProjectForScope(fromScope, g.aggInScope)
 
-	g.aggOutScope.expr = b.constructGroupBy(
-		g.aggInScope.expr.(memo.RelExpr),
-		groupingColSet,
-		aggCols,
-		g.aggInScope.ordering,
-	)
+	if g.groupingSets != nil {
+		g.aggOutScope.expr = b.constructGroupingSetsGroupBy(g, aggCols)
+	} else {
+		g.aggOutScope.expr = b.constructGroupBy(
+			g.aggInScope.expr.(memo.RelExpr),
+			groupingColSet,
+			aggCols,
+			g.aggInScope.ordering,
+		)
+	}
 
 	// Wrap with having filter if it exists.
 	if having != nil {
@@ -447,6 +535,239 @@ func (b *Builder) buildAggregation(having opt.ScalarExpr, fromScope *scope) (out
 	return g.aggOutScope
 }
 
+// constructGroupingSetsGroupBy constructs the aggregation of a GROUP BY clause
+// with multiple grouping sets, using a single GroupBy rather than one per
+// grouping set. Each input row is replicated once per grouping set by joining
+// the input with a Values expression which contains the ordinals of the
+// grouping sets, and the grouping columns which are not part of the grouping
+// set of a row are replaced by NULL. The GroupBy then also groups by the
+// grouping set ordinal. For example:
+//
+//   SELECT a, b, count(*) FROM ab GROUP BY GROUPING SETS ((a, b), (a), (b))
+//
+// is built as:
+//
+//   group-by
+//    ├── grouping columns: a', b', grouping_set
+//    ├── project
+//    │    ├── inner-join (cross)
+//    │    │    ├── scan ab
+//    │    │    └── values (0), (1), (2) [as=grouping_set]
+//    │    └── projections
+//    │         ├── CASE WHEN grouping_set IN (0, 1) THEN a ELSE NULL END [as=a']
+//    │         └── CASE WHEN grouping_set IN (0, 2) THEN b ELSE NULL END [as=b']
+//    └── aggregations
+//         └── count-rows
+//
+// An empty grouping set produces a row even if the input is empty. If there is
+// one, the Values expression is left joined with the input instead, the
+// aggregates ignore the NULL-extended rows, and the groups which only contain
+// NULL-extended rows are filtered out for the other grouping sets.
+func (b *Builder) constructGroupingSetsGroupBy(g *groupby, aggCols []scopeColumn) memo.RelExpr {
+	f := b.factory
+	md := f.Metadata()
+	input := g.aggInScope.expr.(memo.RelExpr)
+
+	rows := make(memo.ScalarListExpr, len(g.groupingSets))
+	rowType := types.MakeTuple([]*types.T{types.Int})
+	var emptySets []int
+	for i, set := range g.groupingSets {
+		rows[i] = f.ConstructTuple(memo.ScalarListExpr{constructGroupingSetOrdinal(f, i)}, rowType)
+		if set.Empty() {
+			emptySets = append(emptySets, i)
+		}
+	}
+	sets := f.ConstructValues(rows, &memo.ValuesPrivate{
+		Cols: opt.ColList{g.groupingSetCol},
+		ID:   md.NextUniqueID(),
+	})
+
+	// The canary column is NULL in the NULL-extended rows of the left join.
+	var canaryCol opt.ColumnID
+	if emptySets != nil {
+		canaryCol = md.AddColumn("canary", types.Bool)
+		input = f.ConstructProject(
+			input,
+			memo.ProjectionsExpr{f.ConstructProjectionsItem(memo.TrueSingleton, canaryCol)},
+			input.Relational().OutputCols,
+		)
+		input = f.ConstructLeftJoin(sets, input, memo.TrueFilter, memo.EmptyJoinPrivate)
+	} else {
+		input = f.ConstructInnerJoin(input, sets, memo.TrueFilter, memo.EmptyJoinPrivate)
+	}
+
+	var projections memo.ProjectionsExpr
+	var groupingColSet opt.ColSet
+	groupingColSet.Add(g.groupingSetCol)
+	inCols := g.groupingCols()
+	outCols := g.aggOutScope.cols[len(g.aggs) : len(g.aggs)+len(inCols)]
+	for i := range inCols {
+		inID, outID := inCols[i].id, outCols[i].id
+		if groupingColSet.Contains(outID) {
+			continue
+		}
+		groupingColSet.Add(outID)
+		if inID == outID {
+			// The column is part of every grouping set.
+			continue
+		}
+		var ordinals []int
+		for j := range g.groupingSets {
+			if g.groupingSets[j].Contains(outID) {
+				ordinals = append(ordinals, j)
+			}
+		}
+		projections = append(projections, f.ConstructProjectionsItem(
+			f.ConstructCase(
+				memo.TrueSingleton,
+				memo.ScalarListExpr{f.ConstructWhen(
+					b.constructInGroupingSets(g, ordinals), f.ConstructVariable(inID),
+				)},
+				f.ConstructNull(outCols[i].typ),
+			),
+			outID,
+		))
+	}
+
+	var anyRowsCol opt.ColumnID
+	if emptySets != nil {
+		// Make the aggregates ignore the NULL-extended rows. The arguments of
+		// the aggregates are NULL in those rows, so this is only necessary for
+		// the aggregates which don't ignore NULLs. count(*) is replaced by a
+		// count of the canary column, which avoids the FILTER.
+		aggCols = append([]scopeColumn(nil), aggCols...)
+		for i := range aggCols {
+			filterCol := canaryCol
+			agg := aggCols[i].scalar
+			if agg.Op() == opt.CountRowsOp {
+				aggCols[i].scalar = f.ConstructCount(f.ConstructVariable(canaryCol))
+				continue
+			}
+			if opt.AggregateIgnoresNulls(memo.ExtractAggFunc(agg).Op()) {
+				continue
+			}
+			if filter, ok := agg.(*memo.AggFilterExpr); ok {
+				filterCol = md.AddColumn("filter", types.Bool)
+				projections = append(projections, f.ConstructProjectionsItem(
+					f.ConstructAnd(filter.Filter, f.ConstructVariable(canaryCol)), filterCol,
+				))
+				agg = filter.Input
+			}
+			aggCols[i].scalar = f.ConstructAggFilter(agg, f.ConstructVariable(filterCol))
+		}
+		anyRowsCol = md.AddColumn("any_rows", types.Bool)
+		aggCols = append(aggCols, scopeColumn{
+			id:     anyRowsCol,
+			scalar: f.ConstructBoolOr(f.ConstructVariable(canaryCol)),
+		})
+	}
+
+	input = f.ConstructProject(input, projections, input.Relational().OutputCols)
+	out := b.constructGroupBy(input, groupingColSet, aggCols, g.aggInScope.ordering)
+
+	if emptySets != nil {
+		out = f.ConstructSelect(out, memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructOr(f.ConstructVariable(anyRowsCol), b.constructInGroupingSets(g, emptySets)),
+		)})
+	}
+	return out
+}
+
+// constructInGroupingSets constructs a condition which is true for the rows
+// produced by the grouping sets with the given ordinals.
+func (b *Builder) constructInGroupingSets(g *groupby, ordinals []int) opt.ScalarExpr {
+	f := b.factory
+	setVar := f.ConstructVariable(g.groupingSetCol)
+	if len(ordinals) == 1 {
+		return f.ConstructEq(setVar, constructGroupingSetOrdinal(f, ordinals[0]))
+	}
+	elems := make(memo.ScalarListExpr, len(ordinals))
+	typs := make([]*types.T, len(ordinals))
+	for i, ord := range ordinals {
+		elems[i] = constructGroupingSetOrdinal(f, ord)
+		typs[i] = types.Int
+	}
+	return f.ConstructIn(setVar, f.ConstructTuple(elems, types.MakeTuple(typs)))
+}
+
+func constructGroupingSetOrdinal(f *norm.Factory, ord int) opt.ScalarExpr {
+	return f.ConstructConstVal(tree.NewDInt(tree.DInt(ord)), types.Int)
+}
+
+// buildGroupingFunc builds a call to the GROUPING function, which returns a bit
+// mask of the arguments which are not part of the grouping set of the current
+// row. The last argument corresponds to the least significant bit.
+func (b *Builder) buildGroupingFunc(
+	f *tree.FuncExpr, inScope *scope, colRefs *opt.ColSet,
+) opt.ScalarExpr {
+	if inScope.inAgg {
+		panic(pgerror.New(pgcode.Grouping, "aggregate function calls cannot contain GROUPING"))
+	}
+	if len(f.Exprs) > maxGroupingFuncArgs {
+		panic(pgerror.Newf(pgcode.TooManyArguments,
+			"GROUPING must have fewer than %d arguments", maxGroupingFuncArgs+1))
+	}
+	g := inScope.groupby
+	args := make([]opt.ColumnID, len(f.Exprs))
+	for i, e := range f.Exprs {
+		var col *scopeColumn
+		if g != nil && !g.buildingGroupingCols {
+			col = g.groupStrs[symbolicExprStr(e.(tree.TypedExpr))]
+		}
+		if col == nil {
+			panic(pgerror.New(pgcode.Grouping,
+				"arguments to GROUPING must be grouping expressions of the associated query level"))
+		}
+		args[i] = col.id
+	}
+
+	// Group the grouping sets by the value of the bit mask.
+	masks := make(map[int][]int)
+	var maskValues []int
+	if g.groupingSets != nil {
+		for i, set := range g.groupingSets {
+			mask := 0
+			for _, id := range args {
+				mask <<= 1
+				if !set.Contains(id) {
+					mask |= 1
+				}
+			}
+			if _, ok := masks[mask]; !ok {
+				maskValues = append(maskValues, mask)
+			}
+			masks[mask] = append(masks[mask], i)
+		}
+	}
+
+	constructMask := func(mask int) opt.ScalarExpr {
+		return b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(mask)), types.Int)
+	}
+	switch len(maskValues) {
+	case 0:
+		// All the arguments are part of the only grouping set.
+		return constructMask(0)
+	case 1:
+		return constructMask(maskValues[0])
+	}
+	if colRefs != nil {
+		colRefs.Add(g.groupingSetCol)
+	}
+	whens := make(memo.ScalarListExpr, 0, len(maskValues)-1)
+	for _, mask := range maskValues[:len(maskValues)-1] {
+		whens = append(whens, b.factory.ConstructWhen(
+			b.constructInGroupingSets(g, masks[mask]), constructMask(mask),
+		))
+	}
+	return b.factory.ConstructCase(
+		memo.TrueSingleton, whens, constructMask(maskValues[len(maskValues)-1]),
+	)
+}
+
+// maxGroupingFuncArgs is the maximum number of arguments of the GROUPING
+// function, which is the number of bits of its result in Postgres.
+const maxGroupingFuncArgs = 31
+
 // analyzeHaving analyzes the having clause and returns it as a typed
 // expression. fromScope contains the name bindings that are visible for this
 // HAVING clause (e.g., passed in from an enclosing statement).
@@ -507,15 +828,113 @@ func (b *Builder) buildGroupingList(
 	// used in an aggregate function`. The builder cannot know whether there is
 	// a grouping error until the grouping columns are fully built.
 	g.buildingGroupingCols = true
+
+	// The grouping sets of the GROUP BY clause are the cross product of the
+	// grouping sets of its items. For example,
+	//   GROUP BY a, ROLLUP (b, c)
+	// has the grouping sets (a, b, c), (a, b) and (a).
+	sets := []opt.ColSet{{}}
+	hasGroupingSets := false
 	for _, e := range groupBy {
-		b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		if gs, ok := e.(*tree.GroupingSet); ok {
+			hasGroupingSets = true
+			itemSets := b.buildGroupingSet(gs, selects, projectionsScope, fromScope)
+			if len(sets)*len(itemSets) > maxGroupingSets {
+				panic(errTooManyGroupingSets)
+			}
+			product := make([]opt.ColSet, 0, len(sets)*len(itemSets))
+			for _, set := range sets {
+				for _, itemSet := range itemSets {
+					product = append(product, set.Union(itemSet))
+				}
+			}
+			sets = product
+			continue
+		}
+		cols := b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		for i := range sets {
+			sets[i] = sets[i].Union(cols)
+		}
 	}
 	g.buildingGroupingCols = false
+
+	// A single grouping set groups by all the grouping columns, which is a
+	// regular aggregation.
+	if hasGroupingSets && len(sets) > 1 {
+		g.groupingSets = sets
+	}
+}
+
+// maxGroupingSets is the maximum number of grouping sets of a GROUP BY clause.
+const maxGroupingSets = 4096
+
+// maxCubeElements is the maximum number of elements of a CUBE, which expands to
+// 2^maxCubeElements grouping sets.
+const maxCubeElements = 12
+
+var errTooManyGroupingSets = pgerror.Newf(pgcode.ProgramLimitExceeded,
+	"too many grouping sets present (maximum %d)", maxGroupingSets)
+
+// buildGroupingSet builds the grouping columns of a ROLLUP, CUBE or GROUPING
+// SETS item of a GROUP BY clause, and returns the grouping sets it expands to.
+// Each grouping set contains the IDs of its aggInScope grouping columns.
+func (b *Builder) buildGroupingSet(
+	gs *tree.GroupingSet, selects tree.SelectExprs, projectionsScope, fromScope *scope,
+) []opt.ColSet {
+	g := fromScope.groupby
+	var sets []opt.ColSet
+	switch gs.Type {
+	case tree.RollupGroupingSet:
+		// ROLLUP (a, b, c) is equivalent to
+		// GROUPING SETS ((a, b, c), (a, b), (a), ()).
+		sets = make([]opt.ColSet, len(gs.Exprs)+1)
+		for i, e := range gs.Exprs {
+			sets[i+1] = sets[i].Union(b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope))
+		}
+		for i, j := 0, len(sets)-1; i < j; i, j = i+1, j-1 {
+			sets[i], sets[j] = sets[j], sets[i]
+		}
+
+	case tree.CubeGroupingSet:
+		// CUBE (a, b) is equivalent to GROUPING SETS ((a, b), (a), (b), ()).
+		if len(gs.Exprs) > maxCubeElements {
+			panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+				"CUBE is limited to %d elements", maxCubeElements))
+		}
+		elems := make([]opt.ColSet, len(gs.Exprs))
+		for i, e := range gs.Exprs {
+			elems[i] = b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope)
+		}
+		sets = make([]opt.ColSet, 0, 1<<len(elems))
+		for mask := (1 << len(elems)) - 1; mask >= 0; mask-- {
+			var set opt.ColSet
+			for i := range elems {
+				if mask&(1<<(len(elems)-1-i)) != 0 {
+					set.UnionWith(elems[i])
+				}
+			}
+			sets = append(sets, set)
+		}
+
+	case tree.GroupingSetsGroupingSet:
+		for _, e := range gs.Exprs {
+			if nested, ok := e.(*tree.GroupingSet); ok {
+				sets = append(sets, b.buildGroupingSet(nested, selects, projectionsScope, fromScope)...)
+			} else {
+				sets = append(sets, b.buildGrouping(e, selects, projectionsScope, fromScope, g.aggInScope))
+			}
+		}
+	}
+	if len(sets) > maxGroupingSets {
+		panic(errTooManyGroupingSets)
+	}
+	return sets
 }
 
 // buildGrouping builds a set of memo groups that represent a GROUP BY
 // expression. The expression (or expressions, if we have a star) is added to
-// groupStrs and to the aggInScope.
+// groupStrs and to the aggInScope. Returns the IDs of the grouping columns of
+// the expression.
 //
 //
 // groupBy          The given GROUP BY expression.
@@ -529,7 +948,7 @@ func (b *Builder) buildGroupingList(
 //                  as the aggregate function arguments.
 func (b *Builder) buildGrouping(
 	groupBy tree.Expr, selects tree.SelectExprs, projectionsScope, fromScope, aggInScope *scope,
-) {
+) opt.ColSet {
 	// Unwrap parenthesized expressions like "((a))" to "a".
 	groupBy = tree.StripParens(groupBy)
 	alias := ""
@@ -623,11 +1042,13 @@ func (b *Builder) buildGrouping(
 	exprs = flattenTuples(exprs)
 
 	// Finally, build each of the GROUP BY columns.
+	var cols opt.ColSet
 	for _, e := range exprs {
 		// If a grouping column has already been added, don't add it again.
 		// GROUP BY a, a is semantically equivalent to GROUP BY a.
 		exprStr := symbolicExprStr(e)
-		if _, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+		if col, ok := fromScope.groupby.groupStrs[exprStr]; ok {
+			cols.Add(col.id)
 			continue
 		}
 
@@ -637,7 +1058,9 @@ func (b *Builder) buildGrouping(
 		col := aggInScope.addColumn(scopeColName(tree.Name(alias)), e)
 		b.buildScalar(e, fromScope, aggInScope, col, nil)
 		fromScope.groupby.groupStrs[exprStr] = col
+		cols.Add(col.id)
 	}
+	return cols
 }
 
 // buildAggArg builds a scalar expression which is used as an input in some form
@@ -854,6 +1277,10 @@ func (b *Builder) constructAggregate(name string, args []opt.ScalarExpr) opt.Sca
 		return b.factory.ConstructSTExtent(args[0])
 	case "st_union", "st_memunion":
 		return b.factory.ConstructSTUnion(args[0])
+	case "range_agg":
+		return b.factory.ConstructRangeAgg(args[0])
+	case "range_intersect_agg":
+		return b.factory.ConstructRangeIntersectAgg(args[0])
 	case "xor_agg":
 		return b.factory.ConstructXorAgg(args[0])
 	case "json_agg":
@@ -903,6 +1330,10 @@ func newGroupingError(name tree.Name) error {
 // table. In that case, we can allow col as an "implicit" grouping column, even
 // if it is not specified in the query.
 func (b *Builder) allowImplicitGroupingColumn(colID opt.ColumnID, g *groupby) bool {
+	if g.groupingSets != nil {
+		// The PK columns could be NULL in some of the grouping sets.
+		return false
+	}
 	md := b.factory.Metadata()
 	colMeta := md.ColumnMeta(colID)
 	if colMeta.Table == 0 {
@@ -0,0 +1,553 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// mergeCardinalityErrText is the error text used when more than one source row
+// would modify the same target row in a MERGE statement.
+const mergeCardinalityErrText = "MERGE command cannot affect row a second time"
+
+// mergeArm holds the expressions built for a single WHEN clause of a MERGE
+// statement.
+type mergeArm struct {
+	action tree.MergeAction
+
+	// cond is true for the rows to which the arm applies. It combines the test
+	// of the canary column for MATCHED or NOT MATCHED with the optional AND
+	// condition of the clause.
+	cond opt.ScalarExpr
+
+	// vals contains one value for each column in the target table that is
+	// inserted or updated by the arm, indexed by column ordinal. Columns that
+	// the arm does not write are nil. vals is nil for DELETE and DO NOTHING
+	// arms.
+	vals []opt.ScalarExpr
+}
+
+// buildMerge builds a memo group for an UpsertOp expression that implements a
+// MERGE statement. The source is left-joined to the target table using the ON
+// condition, and each joined row is assigned the number of the first WHEN
+// clause that applies to it. The WHEN clauses then select the values to insert
+// or update using CASE expressions. For example:
+//
+//   CREATE TABLE t (a INT PRIMARY KEY, b INT)
+//   MERGE INTO t USING s ON t.a = s.x
+//   WHEN MATCHED AND s.y IS NULL THEN DELETE
+//   WHEN MATCHED THEN UPDATE SET b = s.y
+//   WHEN NOT MATCHED THEN INSERT VALUES (s.x, s.y)
+//
+// would create an input expression similar to this SQL:
+//
+//   SELECT
+//     CASE action WHEN 3 THEN s.x ELSE t.a END AS ins_a,
+//     CASE action WHEN 3 THEN s.y ELSE t.b END AS ins_b,
+//     t.a AS fetch_a,
+//     t.b AS fetch_b,
+//     CASE action WHEN 2 THEN s.y ELSE t.b END AS upd_b,
+//     CASE action WHEN 1 THEN true ELSE false END AS del
+//   FROM (
+//     SELECT *, CASE
+//       WHEN t.a IS NOT NULL AND s.y IS NULL THEN 1
+//       WHEN t.a IS NOT NULL THEN 2
+//       WHEN t.a IS NULL THEN 3
+//       ELSE 0
+//     END AS action
+//     FROM s LEFT JOIN t ON t.a = s.x
+//   )
+//   WHERE action != 0
+//
+// The Upsert operator inserts a new row when the canary column (t.a here) is
+// null. Otherwise, it deletes the existing row if the delete column is true, or
+// else updates it. Rows that are not acted upon, including those handled by DO
+// NOTHING clauses, are filtered out. An EnsureUpsertDistinctOn operator on the
+// primary key of the target table raises an error if the same target row would
+// be modified by more than one source row.
+func (b *Builder) buildMerge(merge *tree.Merge, inScope *scope) (outScope *scope) {
+	// Find which table we're working on, check the permissions. Existing values
+	// are always read in order to match source rows with target rows.
+	tab, depName, alias, refColumns := b.resolveTableForMutation(merge.Table, privilege.SELECT)
+
+	if refColumns != nil {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"cannot specify a list of column IDs with MERGE"))
+	}
+
+	// Check the privileges required by the WHEN clauses, and verify that every
+	// clause can be reached.
+	var hasInsert, hasUpdate, hasDelete bool
+	var unconditionalMatched, unconditionalNotMatched bool
+	for _, when := range merge.Whens {
+		if (when.Matched && unconditionalMatched) || (!when.Matched && unconditionalNotMatched) {
+			panic(pgerror.Newf(pgcode.Syntax,
+				"unreachable WHEN clause specified after unconditional WHEN clause"))
+		}
+		if when.Cond == nil {
+			if when.Matched {
+				unconditionalMatched = true
+			} else {
+				unconditionalNotMatched = true
+			}
+		}
+		switch when.Action.(type) {
+		case *tree.MergeInsert:
+			hasInsert = true
+		case *tree.MergeUpdate:
+			hasUpdate = true
+		case *tree.MergeDelete:
+			hasDelete = true
+		}
+	}
+	if hasInsert {
+		b.checkPrivilege(depName, tab, privilege.INSERT)
+	}
+	if hasUpdate {
+		b.checkPrivilege(depName, tab, privilege.UPDATE)
+	}
+	if hasDelete {
+		b.checkPrivilege(depName, tab, privilege.DELETE)
+	}
+
+	// Check if this table has already been mutated in another subquery.
+	b.checkMultipleMutations(tab, false /* simpleInsert */)
+
+	var mb mutationBuilder
+	mb.init(b, "merge", tab, alias)
+
+	// Build the joined input and the expressions of each WHEN clause.
+	arms := mb.buildInputForMerge(inScope, merge)
+
+	// Project the columns that will be inserted, updated and deleted, along
+	// with any default and computed columns.
+	mb.addMergeCols(arms, hasInsert, hasUpdate, hasDelete)
+
+	// Build the final upsert statement.
+	mb.buildMerge()
+
+	return mb.outScope
+}
+
+// buildInputForMerge left-joins the source of a MERGE statement to the target
+// table, and builds the conditions and values of each WHEN clause. It then
+// projects an action column that identifies the clause applied to each row,
+// filters out the rows that are not acted upon, and ensures that each target
+// row is modified at most once. See the buildMerge comment for more details.
+func (mb *mutationBuilder) buildInputForMerge(inScope *scope, merge *tree.Merge) []mergeArm {
+	var indexFlags *tree.IndexFlags
+	if source, ok := merge.Table.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
+		indexFlags = source.IndexFlags
+		telemetry.Inc(sqltelemetry.IndexHintUseCounter)
+	}
+
+	// Build the source of the MERGE.
+	sourceScope := mb.b.buildFromTables(tree.TableExprs{merge.Source}, noRowLocking, inScope)
+
+	// Fetch columns from a different instance of the table metadata, so that it's
+	// possible to remap columns.
+	//
+	// NOTE: Include mutation columns, but be careful to never use them for any
+	//       reason other than as "fetch columns". See buildScan comment.
+	mb.fetchScope = mb.b.buildScan(
+		mb.b.addTable(mb.tab, &mb.alias),
+		tableOrdinals(mb.tab, columnKinds{
+			includeMutations:       true,
+			includeSystem:          true,
+			includeInverted:        false,
+			includeVirtualComputed: true,
+		}),
+		indexFlags,
+		noRowLocking,
+		inScope,
+	)
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// Check that the same table name is not used by the source and the target.
+	mb.b.validateJoinTableNames(sourceScope, mb.fetchScope)
+
+	// Left-join the source rows to the target rows using the ON condition.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(sourceScope)
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	on := mb.b.resolveAndBuildScalar(
+		merge.On,
+		types.Bool,
+		exprKindOn,
+		tree.RejectGenerators|tree.RejectWindowApplications,
+		mb.outScope,
+	)
+	f := mb.b.factory
+	mb.outScope.expr = f.ConstructLeftJoin(
+		sourceScope.expr.(memo.RelExpr),
+		mb.fetchScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{f.ConstructFiltersItem(on)},
+		memo.EmptyJoinPrivate,
+	)
+
+	// Record a not-null "canary" column. After the left-join, this will be null
+	// if the source row did not match any target row. At least one not-null
+	// column must exist, since primary key columns are not-null.
+	mb.canaryColID = mb.fetchColIDs[findNotNullIndexCol(mb.tab.Index(cat.PrimaryIndex))]
+
+	// WHEN NOT MATCHED clauses can only refer to the source columns.
+	sourceOnlyScope := mb.outScope.replace()
+	sourceOnlyScope.appendColumnsFromScope(sourceScope)
+	sourceOnlyScope.expr = mb.outScope.expr
+
+	// Build the condition and values of each WHEN clause.
+	arms := make([]mergeArm, len(merge.Whens))
+	for i, when := range merge.Whens {
+		armScope := mb.outScope
+		canaryTest := f.ConstructIsNot(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		if !when.Matched {
+			armScope = sourceOnlyScope
+			canaryTest = f.ConstructIs(f.ConstructVariable(mb.canaryColID), memo.NullSingleton)
+		}
+
+		arms[i].action = when.Action
+		arms[i].cond = canaryTest
+		if when.Cond != nil {
+			cond := mb.b.resolveAndBuildScalar(
+				when.Cond, types.Bool, exprKindMergeWhen, tree.RejectSpecial, armScope,
+			)
+			arms[i].cond = f.ConstructAnd(canaryTest, cond)
+		}
+
+		switch t := when.Action.(type) {
+		case *tree.MergeUpdate:
+			arms[i].vals = mb.buildMergeUpdateVals(t.Exprs, armScope)
+		case *tree.MergeInsert:
+			arms[i].vals = mb.buildMergeInsertVals(t, armScope)
+		}
+	}
+
+	// Project the action column. DO NOTHING clauses and rows to which no clause
+	// applies map to zero.
+	whens := make(memo.ScalarListExpr, len(arms))
+	for i := range arms {
+		action := i + 1
+		if _, ok := arms[i].action.(*tree.MergeDoNothing); ok {
+			action = 0
+		}
+		whens[i] = f.ConstructWhen(arms[i].cond, mb.mergeActionConst(action))
+	}
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	actionCol := mb.b.synthesizeColumn(
+		projectionsScope,
+		scopeColName("").WithMetadataName("merge_action"),
+		types.Int,
+		nil, /* expr */
+		f.ConstructCase(memo.TrueSingleton, whens, mb.mergeActionConst(0)),
+	)
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+	mb.mergeActionColID = actionCol.id
+
+	// Filter out the rows that are not acted upon.
+	mb.outScope.expr = f.ConstructSelect(
+		mb.outScope.expr.(memo.RelExpr),
+		memo.FiltersExpr{f.ConstructFiltersItem(
+			f.ConstructNe(f.ConstructVariable(actionCol.id), mb.mergeActionConst(0)),
+		)},
+	)
+
+	// Ensure that each target row is modified at most once. Source rows that do
+	// not match have null primary key values, and are always distinct.
+	var pkCols opt.ColSet
+	primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+	for i, n := 0, primaryIndex.KeyColumnCount(); i < n; i++ {
+		pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+	}
+	mb.outScope.ordering = nil
+	mb.outScope = mb.b.buildDistinctOn(
+		pkCols, mb.outScope, true /* nullsAreDistinct */, mergeCardinalityErrText,
+	)
+
+	mb.targetColList = make(opt.ColList, 0, mb.tab.ColumnCount())
+	mb.targetColSet = opt.ColSet{}
+
+	return arms
+}
+
+// buildMergeUpdateVals builds the values assigned by the SET expressions of a
+// WHEN MATCHED THEN UPDATE clause.
+func (mb *mutationBuilder) buildMergeUpdateVals(
+	exprs tree.UpdateExprs, inScope *scope,
+) []opt.ScalarExpr {
+	// SET expressions should reject aggregates, generators, etc. The scope may
+	// still have the context of the ON or WHEN condition, so reset it.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE UPDATE SET", tree.RejectSpecial)
+	inScope.context = exprKindNone
+
+	for _, set := range exprs {
+		if _, ok := set.Expr.(*tree.Subquery); ok && set.Tuple {
+			panic(unimplemented.New("merge update subquery",
+				"multiple-column SET with a subquery is not supported in MERGE"))
+		}
+	}
+
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	mb.addTargetColsForUpdate(exprs)
+
+	vals := make([]opt.ScalarExpr, mb.tab.ColumnCount())
+	n := 0
+	for _, set := range exprs {
+		if set.Tuple {
+			for _, expr := range set.Expr.(*tree.Tuple).Exprs {
+				ord := mb.tabID.ColumnOrdinal(mb.targetColList[n])
+				vals[ord] = mb.buildMergeValue(expr, ord, inScope)
+				n++
+			}
+		} else {
+			ord := mb.tabID.ColumnOrdinal(mb.targetColList[n])
+			checkUpdateExpression(mb.tab.Column(ord), set)
+			vals[ord] = mb.buildMergeValue(set.Expr, ord, inScope)
+			n++
+		}
+	}
+	return vals
+}
+
+// buildMergeInsertVals builds the values inserted by a WHEN NOT MATCHED THEN
+// INSERT clause. Columns that are not explicitly targeted by the clause are
+// assigned their default values.
+func (mb *mutationBuilder) buildMergeInsertVals(
+	ins *tree.MergeInsert, inScope *scope,
+) []opt.ScalarExpr {
+	// VALUES expressions should reject aggregates, generators, etc. The scope
+	// may still have the context of the ON or WHEN condition, so reset it.
+	scalarProps := &mb.b.semaCtx.Properties
+	defer scalarProps.Restore(*scalarProps)
+	mb.b.semaCtx.Properties.Require("MERGE INSERT", tree.RejectSpecial)
+	inScope.context = exprKindNone
+
+	mb.targetColList = mb.targetColList[:0]
+	mb.targetColSet = opt.ColSet{}
+	if len(ins.Columns) != 0 {
+		mb.addTargetNamedColsForInsert(ins.Columns)
+		mb.checkNumCols(len(mb.targetColList), len(ins.Exprs))
+	} else if len(ins.Exprs) != 0 {
+		mb.addTargetTableColsForInsert(len(ins.Exprs))
+	}
+
+	vals := make([]opt.ScalarExpr, mb.tab.ColumnCount())
+	for i, colID := range mb.targetColList {
+		ord := mb.tabID.ColumnOrdinal(colID)
+		if _, ok := ins.Exprs[i].(tree.DefaultVal); !ok {
+			checkColumnIsNotGeneratedAlwaysAsIdentity(mb.tab.Column(ord))
+		}
+		vals[ord] = mb.buildMergeValue(ins.Exprs[i], ord, inScope)
+	}
+
+	// Use the default value for any remaining non-computed columns, including
+	// write-only mutation columns.
+	for ord := range vals {
+		col := mb.tab.Column(ord)
+		if kind := col.Kind(); kind != cat.Ordinary && kind != cat.WriteOnly {
+			continue
+		}
+		if vals[ord] == nil && !col.IsComputed() {
+			vals[ord] = mb.buildMergeValue(tree.DefaultVal{}, ord, inScope)
+		}
+	}
+	return vals
+}
+
+// buildMergeValue builds a value that is inserted into or assigned to the
+// table column with the given ordinal by a WHEN clause of a MERGE statement.
+func (mb *mutationBuilder) buildMergeValue(
+	expr tree.Expr, ord int, inScope *scope,
+) opt.ScalarExpr {
+	col := mb.tab.Column(ord)
+	if _, ok := expr.(tree.DefaultVal); ok {
+		expr = mb.parseDefaultExpr(mb.tabID.ColumnID(ord))
+	}
+	texpr := inScope.resolveType(expr, col.DatumType())
+	checkDatumTypeFitsColumnType(col, texpr.ResolvedType())
+	return mb.b.buildScalar(texpr, inScope, nil /* outScope */, nil /* outCol */, nil /* colRefs */)
+}
+
+// mergeActionConst returns a constant for the given value of the MERGE action
+// column.
+func (mb *mutationBuilder) mergeActionConst(action int) opt.ScalarExpr {
+	return mb.b.factory.ConstructConstVal(tree.NewDInt(tree.DInt(action)), types.Int)
+}
+
+// addMergeCols projects the insert, update and delete columns of a MERGE
+// statement. Each insert and update column is a CASE expression on the action
+// column that selects the value provided by the WHEN clause that applies to the
+// row, or else the existing value. It then adds any default and computed
+// columns that are not yet part of the target column list.
+func (mb *mutationBuilder) addMergeCols(arms []mergeArm, hasInsert, hasUpdate, hasDelete bool) {
+	f := mb.b.factory
+	projectionsScope := mb.outScope.replace()
+	projectionsScope.appendColumnsFromScope(mb.outScope)
+	action := f.ConstructVariable(mb.mergeActionColID)
+
+	for ord, n := 0, mb.tab.ColumnCount(); ord < n; ord++ {
+		col := mb.tab.Column(ord)
+		if kind := col.Kind(); kind != cat.Ordinary && kind != cat.WriteOnly {
+			continue
+		}
+		fetchColID := mb.fetchColIDs[ord]
+
+		var insertWhens, updateWhens memo.ScalarListExpr
+		for i := range arms {
+			if arms[i].vals == nil || arms[i].vals[ord] == nil {
+				continue
+			}
+			when := f.ConstructWhen(mb.mergeActionConst(i+1), arms[i].vals[ord])
+			if _, ok := arms[i].action.(*tree.MergeInsert); ok {
+				insertWhens = append(insertWhens, when)
+			} else {
+				updateWhens = append(updateWhens, when)
+			}
+		}
+
+		if len(insertWhens) > 0 {
+			scopeCol := mb.b.synthesizeColumn(
+				projectionsScope,
+				scopeColName(col.ColName()).WithMetadataName(fmt.Sprintf("insert_%s", col.ColName())),
+				col.DatumType(),
+				nil, /* expr */
+				f.ConstructCase(action, insertWhens, f.ConstructVariable(fetchColID)),
+			)
+			mb.insertColIDs[ord] = scopeCol.id
+		} else if !hasInsert {
+			// Rows are never inserted, so the insert columns are never used.
+			mb.insertColIDs[ord] = fetchColID
+		}
+
+		if len(updateWhens) > 0 {
+			scopeCol := mb.b.synthesizeColumn(
+				projectionsScope,
+				scopeColName(col.ColName()).WithMetadataName(fmt.Sprintf("update_%s", col.ColName())),
+				col.DatumType(),
+				nil, /* expr */
+				f.ConstructCase(action, updateWhens, f.ConstructVariable(fetchColID)),
+			)
+			mb.updateColIDs[ord] = scopeCol.id
+		}
+	}
+
+	if hasDelete {
+		var deleteWhens memo.ScalarListExpr
+		for i := range arms {
+			if _, ok := arms[i].action.(*tree.MergeDelete); ok {
+				deleteWhens = append(deleteWhens, f.ConstructWhen(mb.mergeActionConst(i+1), memo.TrueSingleton))
+			}
+		}
+		scopeCol := mb.b.synthesizeColumn(
+			projectionsScope,
+			scopeColName("").WithMetadataName("merge_delete"),
+			types.Bool,
+			nil, /* expr */
+			f.ConstructCase(action, deleteWhens, memo.FalseSingleton),
+		)
+		mb.deleteColID = scopeCol.id
+	}
+
+	mb.b.constructProjectForScope(mb.outScope, projectionsScope)
+	mb.outScope = projectionsScope
+
+	// Add additional columns for computed expressions that may depend on the
+	// updated columns, as well as mutation columns with default values.
+	if hasUpdate {
+		mb.addSynthesizedColsForUpdate()
+	}
+
+	// Add the computed columns that depend on the inserted values. The insert
+	// columns must temporarily be the only columns with table column names, so
+	// that the computed column expressions refer to them.
+	if hasInsert {
+		mb.setMergeColNames(mb.insertColIDs)
+		mb.addSynthesizedColsForInsert(true /* isUpsert */)
+
+		existingColIDs := make(opt.OptionalColList, len(mb.fetchColIDs))
+		for i := range existingColIDs {
+			existingColIDs[i] = mb.updateColIDs[i]
+			if existingColIDs[i] == 0 {
+				existingColIDs[i] = mb.fetchColIDs[i]
+			}
+		}
+		mb.setMergeColNames(existingColIDs)
+	}
+}
+
+// setMergeColNames names each of the given input columns after the
+// corresponding table column, and makes all other columns in scope anonymous.
+func (mb *mutationBuilder) setMergeColNames(colIDs opt.OptionalColList) {
+	var ords opt.ColMap
+	for ord, colID := range colIDs {
+		if colID != 0 {
+			ords.Set(int(colID), ord)
+		}
+	}
+	for i := range mb.outScope.cols {
+		col := &mb.outScope.cols[i]
+		if ord, ok := ords.Get(int(col.id)); ok {
+			col.name = scopeColName(mb.tab.Column(ord).ColName())
+		} else {
+			col.clearName()
+		}
+	}
+}
+
+// buildMerge constructs an Upsert operator for a MERGE statement.
+func (mb *mutationBuilder) buildMerge() {
+	// Merge input insert and update columns using CASE expressions.
+	mb.projectUpsertColumns()
+
+	// Disambiguate names so that references in any expressions, such as a
+	// check constraint, refer to the correct columns.
+	mb.disambiguateColumns()
+
+	// Add any check constraint boolean columns to the input.
+	mb.addCheckConstraintCols(false /* isUpdate */)
+
+	// Add the partial index predicate expressions to the table metadata.
+	// These expressions are used to prune fetch columns during
+	// normalization.
+	mb.b.addPartialIndexPredicatesForTable(mb.md.TableMeta(mb.tabID), nil /* scan */)
+
+	// Project partial index PUT and DEL boolean columns.
+	mb.projectPartialIndexPutAndDelCols()
+
+	mb.buildUniqueChecksForUpsert()
+
+	mb.buildFKChecksForUpsert()
+
+	mb.buildFKChecksForMergeDelete()
+
+	private := mb.makeMutationPrivate(false /* needResults */)
+	mb.outScope.expr = mb.b.factory.ConstructUpsert(
+		mb.outScope.expr, mb.uniqueChecks, mb.fkChecks, private,
+	)
+
+	mb.buildReturning(nil /* returning */)
+}
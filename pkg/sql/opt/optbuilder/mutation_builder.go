@@ -133,6 +133,16 @@ type mutationBuilder struct {
 	// an insert; otherwise it's an update.
 	canaryColID opt.ColumnID
 
+	// deleteColID is the ID of the boolean column that is used by MERGE
+	// statements to decide whether an existing row is deleted rather than
+	// updated. It is 0 if the mutation cannot delete rows this way.
+	deleteColID opt.ColumnID
+
+	// mergeActionColID is the ID of the column that holds, for each row of a
+	// MERGE statement, the 1-based number of the WHEN clause that applies to
+	// it.
+	mergeActionColID opt.ColumnID
+
 	// arbiters is the set of indexes and unique constraints that are used to
 	// detect conflicts for UPSERT and INSERT ON CONFLICT statements.
 	arbiters arbiterSet
@@ -183,8 +193,8 @@ type mutationBuilder struct {
 
 	// extraAccessibleCols stores all the columns that are available to the
 	// mutation that are not part of the target table. This is useful for
-	// UPDATE ... FROM queries, as the columns from the FROM tables must be
-	// made accessible to the RETURNING clause.
+	// UPDATE ... FROM and DELETE ... USING queries, as the columns from the
+	// FROM or USING tables must be made accessible to the RETURNING clause.
 	extraAccessibleCols []scopeColumn
 
 	// fkCheckHelper is used to prevent allocating the helper separately.
@@ -304,26 +314,7 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	// together with the table being updated.
 	fromClausePresent := len(from) > 0
 	if fromClausePresent {
-		fromScope := mb.b.buildFromTables(from, noRowLocking, inScope)
-
-		// Check that the same table name is not used multiple times.
-		mb.b.validateJoinTableNames(mb.fetchScope, fromScope)
-
-		// The FROM table columns can be accessed by the RETURNING clause of the
-		// query and so we have to make them accessible.
-		mb.extraAccessibleCols = fromScope.cols
-
-		// Add the columns in the FROM scope.
-		// We create a new scope so that fetchScope is not modified. It will be
-		// used later to build partial index predicate expressions, and we do
-		// not want ambiguities with column names in the FROM clause.
-		mb.outScope = mb.fetchScope.replace()
-		mb.outScope.appendColumnsFromScope(mb.fetchScope)
-		mb.outScope.appendColumnsFromScope(fromScope)
-
-		left := mb.fetchScope.expr.(memo.RelExpr)
-		right := fromScope.expr.(memo.RelExpr)
-		mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+		mb.buildJoinWithTables(inScope, from)
 	} else {
 		mb.outScope = mb.fetchScope
 	}
@@ -371,6 +362,34 @@ func (mb *mutationBuilder) buildInputForUpdate(
 	}
 }
 
+// buildJoinWithTables joins the scan of the target table in mb.fetchScope with
+// the given table expressions, which come from the FROM clause of an UPDATE or
+// the USING clause of a DELETE. The joined expression is stored in
+// mb.outScope, and the columns of the joined tables are stored in the mutation
+// builder so they can be made accessible to the RETURNING clause.
+func (mb *mutationBuilder) buildJoinWithTables(inScope *scope, tables tree.TableExprs) {
+	fromScope := mb.b.buildFromTables(tables, noRowLocking, inScope)
+
+	// Check that the same table name is not used multiple times.
+	mb.b.validateJoinTableNames(mb.fetchScope, fromScope)
+
+	// The joined table columns can be accessed by the RETURNING clause of the
+	// query and so we have to make them accessible.
+	mb.extraAccessibleCols = fromScope.cols
+
+	// Add the columns in the FROM scope.
+	// We create a new scope so that fetchScope is not modified. It will be
+	// used later to build partial index predicate expressions, and we do
+	// not want ambiguities with column names in the FROM clause.
+	mb.outScope = mb.fetchScope.replace()
+	mb.outScope.appendColumnsFromScope(mb.fetchScope)
+	mb.outScope.appendColumnsFromScope(fromScope)
+
+	left := mb.fetchScope.expr.(memo.RelExpr)
+	right := fromScope.expr.(memo.RelExpr)
+	mb.outScope.expr = mb.b.factory.ConstructInnerJoin(left, right, memo.TrueFilter, memo.EmptyJoinPrivate)
+}
+
 // buildInputForDelete constructs a Select expression from the fields in
 // the Delete operator, similar to this:
 //
@@ -381,9 +400,19 @@ func (mb *mutationBuilder) buildInputForUpdate(
 //   LIMIT <limit>
 //
 // All columns from the table to update are added to fetchColList.
+// If a USING clause is defined, the USING tables are joined with the target
+// table in the same way as the FROM tables of an UPDATE, and a target row
+// that matches multiple rows of the USING tables is only deleted once. The
+// columns of the USING tables are stored in the mutation builder so they can
+// be made accessible to the RETURNING clause.
 // TODO(andyk): Do needed column analysis to project fewer columns if possible.
 func (mb *mutationBuilder) buildInputForDelete(
-	inScope *scope, texpr tree.TableExpr, where *tree.Where, limit *tree.Limit, orderBy tree.OrderBy,
+	inScope *scope,
+	texpr tree.TableExpr,
+	using tree.TableExprs,
+	where *tree.Where,
+	limit *tree.Limit,
+	orderBy tree.OrderBy,
 ) {
 	var indexFlags *tree.IndexFlags
 	if source, ok := texpr.(*tree.AliasedTableExpr); ok && source.IndexFlags != nil {
@@ -412,7 +441,18 @@ func (mb *mutationBuilder) buildInputForDelete(
 		noRowLocking,
 		inScope,
 	)
-	mb.outScope = mb.fetchScope
+
+	// Set list of columns that will be fetched by the input expression.
+	mb.setFetchColIDs(mb.fetchScope.cols)
+
+	// If there is a USING clause present, we must join all the tables
+	// together with the table being deleted from.
+	usingClausePresent := len(using) > 0
+	if usingClausePresent {
+		mb.buildJoinWithTables(inScope, using)
+	} else {
+		mb.outScope = mb.fetchScope
+	}
 
 	// WHERE
 	mb.b.buildWhere(where, mb.outScope)
@@ -431,8 +471,20 @@ func (mb *mutationBuilder) buildInputForDelete(
 
 	mb.outScope = projectionsScope
 
-	// Set list of columns that will be fetched by the input expression.
-	mb.setFetchColIDs(mb.outScope.cols)
+	// Build a distinct on to ensure there is at most one row in the joined
+	// output for every row in the table, so that every row is deleted at most
+	// once. Unlike UPDATE ... FROM, hidden primary key columns such as the
+	// implicit rowid column are included, since a row that is deleted more than
+	// once would be counted more than once in the number of affected rows.
+	if usingClausePresent {
+		var pkCols opt.ColSet
+		primaryIndex := mb.tab.Index(cat.PrimaryIndex)
+		for i := 0; i < primaryIndex.KeyColumnCount(); i++ {
+			pkCols.Add(mb.fetchColIDs[primaryIndex.Column(i).Ordinal()])
+		}
+		mb.outScope = mb.b.buildDistinctOn(
+			pkCols, mb.outScope, false /* nullsAreDistinct */, "" /* errorOnDup */)
+	}
 }
 
 // addTargetColsByName adds one target column for each of the names in the given
@@ -1043,6 +1095,7 @@ func (mb *mutationBuilder) makeMutationPrivate(needResults bool) *memo.MutationP
 		FetchCols:           checkEmptyList(mb.fetchColIDs),
 		UpdateCols:          checkEmptyList(mb.updateColIDs),
 		CanaryCol:           mb.canaryColID,
+		DeleteCol:           mb.deleteColID,
 		ArbiterIndexes:      mb.arbiters.IndexOrdinals(),
 		ArbiterConstraints:  mb.arbiters.UniqueConstraintOrdinals(),
 		CheckCols:           checkEmptyList(mb.checkColIDs),
@@ -1494,6 +1547,9 @@ type checkInputScanType uint8
 const (
 	checkInputScanNewVals checkInputScanType = iota
 	checkInputScanFetchedVals
+	// checkInputScanDeletedVals scans the fetched values of only those rows
+	// that are deleted by a MERGE statement.
+	checkInputScanDeletedVals
 )
 
 // buildCheckInputScan constructs a WithScan that iterates over the input to the
@@ -1501,7 +1557,9 @@ const (
 // and uniqueness violations.
 //
 // The WithScan expression will scan either the new values or the fetched values
-// for the given table ordinals (which correspond to FK or unique columns).
+// for the given table ordinals (which correspond to FK or unique columns). For
+// checkInputScanDeletedVals, the WithScan is wrapped in a Select that keeps only
+// the rows deleted by a MERGE statement.
 //
 // Returns a scope containing the WithScan expression and the output columns
 // from the WithScan. The output columns map 1-to-1 to tabOrdinals. Also returns
@@ -1550,6 +1608,28 @@ func (mb *mutationBuilder) buildCheckInputScan(
 		}
 	}
 
+	if typ == checkInputScanDeletedVals {
+		// Also scan the delete column, and use it to filter out the rows that
+		// are not deleted.
+		f := mb.b.factory
+		deleteCol := mb.md.AddColumn("delete", types.Bool)
+		withScan := f.ConstructWithScan(&memo.WithScanPrivate{
+			With:    mb.withID,
+			InCols:  append(inputCols, mb.deleteColID),
+			OutCols: append(withScanScope.colList(), deleteCol),
+			ID:      f.Metadata().NextUniqueID(),
+		})
+		withScanScope.expr = f.ConstructProject(
+			f.ConstructSelect(
+				withScan,
+				memo.FiltersExpr{f.ConstructFiltersItem(f.ConstructVariable(deleteCol))},
+			),
+			memo.EmptyProjectionsExpr,
+			withScanScope.colSet(),
+		)
+		return withScanScope, notNullOutCols
+	}
+
 	withScanScope.expr = mb.b.factory.ConstructWithScan(&memo.WithScanPrivate{
 		With:    mb.withID,
 		InCols:  inputCols,
@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
 
@@ -463,6 +464,40 @@ func (mb *mutationBuilder) buildFKChecksForUpsert() {
 	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
 }
 
+// buildFKChecksForMergeDelete builds FK check queries for the rows that are
+// deleted by the WHEN MATCHED THEN DELETE clauses of a MERGE statement. These
+// are in addition to the checks built by buildFKChecksForUpsert for the rows
+// that are inserted or updated.
+//
+// Cascading actions are not supported; an error is raised if an inbound FK
+// has an ON DELETE action other than RESTRICT or NO ACTION.
+func (mb *mutationBuilder) buildFKChecksForMergeDelete() {
+	if mb.deleteColID == 0 || mb.tab.InboundForeignKeyCount() == 0 {
+		// No relevant FKs.
+		return
+	}
+
+	mb.ensureWithID()
+
+	h := &mb.fkCheckHelper
+	for i, n := 0, mb.tab.InboundForeignKeyCount(); i < n; i++ {
+		if !h.initWithInboundFK(mb, i) {
+			continue
+		}
+
+		if a := h.fk.DeleteReferenceAction(); a != tree.Restrict && a != tree.NoAction {
+			panic(unimplemented.Newf("merge delete cascade",
+				"MERGE with DELETE is not supported on a table referenced by a foreign key with ON DELETE %s",
+				a,
+			))
+		}
+
+		withScanScope, _ := mb.buildCheckInputScan(checkInputScanDeletedVals, h.tabOrdinals)
+		mb.fkChecks = append(mb.fkChecks, h.buildDeletionCheck(withScanScope.expr, withScanScope.colList()))
+	}
+	telemetry.Inc(sqltelemetry.ForeignKeyChecksUseCounter)
+}
+
 // outboundFKColsUpdated returns true if any of the FK columns for an outbound
 // constraint are being updated (according to updateColIDs).
 func (mb *mutationBuilder) outboundFKColsUpdated(fkOrdinal int) bool {
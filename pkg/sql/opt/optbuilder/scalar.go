@@ -15,6 +15,7 @@ import (
 	"fmt"
 
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/seqexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/lex"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt"
@@ -253,6 +254,9 @@ func (b *Builder) buildScalar(
 
 	case *tree.CastExpr:
 		texpr := t.Expr.(tree.TypedExpr)
+		if typ := t.ResolvedType(); typ.IsDomain() {
+			texpr = b.addDomainAssertions(texpr, typ)
+		}
 		arg := b.buildScalar(texpr, inScope, nil, nil, colRefs)
 		out = b.factory.ConstructCast(arg, t.ResolvedType())
 
@@ -446,6 +450,45 @@ func (b *Builder) buildScalar(
 	return b.finishBuildScalar(scalar, out, inScope, outScope, outCol)
 }
 
+// addDomainAssertions wraps the given expression, which is being cast to the
+// given domain type, in calls to builtins that enforce the NOT NULL and CHECK
+// constraints of the domain. The value checked by the constraints is the
+// expression cast to the base type of the domain.
+func (b *Builder) addDomainAssertions(texpr tree.TypedExpr, domain *types.T) tree.TypedExpr {
+	data := domain.TypeMeta.DomainData
+	if data == nil || (!data.NotNull && len(data.CheckExprs) == 0) {
+		return texpr
+	}
+	baseType := domain.DomainBaseType()
+	makeValue := func() tree.Expr {
+		return &tree.CastExpr{Expr: texpr, Type: baseType, SyntaxMode: tree.CastShort}
+	}
+	domainName := tree.NewDString(domain.Name())
+
+	expr := makeValue()
+	if data.NotNull {
+		expr = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.assert_domain_not_null"),
+			Exprs: tree.Exprs{expr, domainName},
+		}
+	}
+	for i := range data.CheckExprs {
+		check, err := schemaexpr.ParseDomainCheckExpr(data.CheckExprs[i], makeValue)
+		if err != nil {
+			panic(err)
+		}
+		expr = &tree.FuncExpr{
+			Func:  tree.WrapFunction("crdb_internal.assert_domain_check"),
+			Exprs: tree.Exprs{expr, check, domainName, tree.NewDString(data.CheckNames[i])},
+		}
+	}
+	typedExpr, err := tree.TypeCheck(b.ctx, expr, b.semaCtx, baseType)
+	if err != nil {
+		panic(err)
+	}
+	return typedExpr
+}
+
 func (b *Builder) hasSubOperator(t *tree.ComparisonExpr) bool {
 	return t.Operator.Symbol == tree.Any || t.Operator.Symbol == tree.All || t.Operator.Symbol == tree.Some
 }
@@ -502,6 +545,11 @@ func (b *Builder) buildFunction(
 		panic(errors.AssertionFailedf("window function should have been replaced"))
 	}
 
+	if def.Name == "grouping" {
+		out = b.buildGroupingFunc(f, inScope, colRefs)
+		return b.finishBuildScalar(f, out, inScope, outScope, outCol)
+	}
+
 	args := make(memo.ScalarListExpr, len(f.Exprs))
 	for i, pexpr := range f.Exprs {
 		args[i] = b.buildScalar(pexpr.(tree.TypedExpr), inScope, nil, nil, colRefs)
@@ -720,6 +768,10 @@ func (b *Builder) constructComparison(
 			return b.factory.ConstructBBoxIntersects(left, right)
 		}
 		return b.factory.ConstructOverlaps(left, right)
+	case tree.TSMatches:
+		return b.factory.ConstructTSMatch(left, right)
+	case tree.JSONPathExists:
+		return b.factory.ConstructJsonPathExists(left, right)
 	}
 	panic(errors.AssertionFailedf("unhandled comparison operator: %s", log.Safe(cmp.Operator)))
 }
@@ -754,6 +806,14 @@ func (b *Builder) constructBinary(
 		return b.factory.ConstructLShift(left, right)
 	case tree.RShift:
 		return b.factory.ConstructRShift(left, right)
+	case tree.Distance:
+		return b.factory.ConstructDistance(left, right)
+	case tree.OverLeft:
+		return b.factory.ConstructOverLeft(left, right)
+	case tree.OverRight:
+		return b.factory.ConstructOverRight(left, right)
+	case tree.Adjacent:
+		return b.factory.ConstructAdjacent(left, right)
 	case tree.JSONFetchText:
 		return b.factory.ConstructFetchText(left, right)
 	case tree.JSONFetchVal:
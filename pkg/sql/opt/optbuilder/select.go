@@ -11,6 +11,7 @@
 package optbuilder
 
 import (
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
@@ -492,6 +493,11 @@ func (b *Builder) buildScan(
 		}
 	}
 
+	if tab.IsForeignTable() && locking.isSet() {
+		panic(pgerror.Newf(pgcode.Syntax,
+			"%s not allowed with foreign tables", locking.get().Strength))
+	}
+
 	if tab.IsVirtualTable() {
 		if indexFlags != nil {
 			panic(pgerror.Newf(pgcode.Syntax,
@@ -1308,8 +1314,14 @@ func (b *Builder) validateLockingInFrom(
 		case tree.LockWaitBlock:
 			// Default. Block on conflicting locks.
 		case tree.LockWaitSkip:
-			panic(unimplementedWithIssueDetailf(40476, "",
-				"SKIP LOCKED lock wait policy is not supported"))
+			// Skip rows that are locked by other transactions. Nodes running
+			// older versions do not support the SKIP LOCKED wait policy, so it
+			// cannot be used until the upgrade is finalized.
+			if !b.evalCtx.Settings.Version.IsActive(b.ctx, clusterversion.SkipLockedWaitPolicy) {
+				panic(pgerror.Newf(pgcode.FeatureNotSupported,
+					"version %v must be finalized to use SKIP LOCKED",
+					clusterversion.SkipLockedWaitPolicy))
+			}
 		case tree.LockWaitError:
 			// Raise an error on conflicting locks.
 		default:
@@ -0,0 +1,260 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package optbuilder
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// udfArgsAlias is the alias of the single-row relation which holds the
+	// arguments of an inlined user-defined function.
+	udfArgsAlias = "$args"
+	// udfBodyAlias is the alias of the relation produced by the body of an
+	// inlined user-defined function.
+	udfBodyAlias = "$body"
+	// udfResultColName is the name of the column produced by the body of an
+	// inlined user-defined function.
+	udfResultColName = "$result"
+
+	// maxInlinedUDFs is the maximum number of calls to user-defined functions
+	// which can be inlined into a single statement. Since function bodies are
+	// inlined recursively, exceeding it usually indicates a recursive function.
+	maxInlinedUDFs = 1000
+)
+
+// resolveFunction resolves the function reference of the given function
+// expression. Builtin functions are cached in the reference, as done by
+// tree.ResolvableFunctionReference.Resolve. User-defined functions are not,
+// since their definitions can change between executions of a prepared
+// statement.
+func (b *Builder) resolveFunction(f *tree.FuncExpr) *tree.FunctionDefinition {
+	name, ok := f.Func.FunctionReference.(*tree.UnresolvedName)
+	// Scalar builders (see NewScalar) have no catalog and can only reference
+	// builtin functions.
+	if !ok || b.catalog == nil {
+		def, err := f.Func.Resolve(b.semaCtx.SearchPath)
+		if err != nil {
+			panic(err)
+		}
+		return def
+	}
+	def, err := b.catalog.ResolveFunction(b.ctx, name, b.semaCtx.SearchPath)
+	if err != nil {
+		panic(err)
+	}
+	if !def.IsUDF() {
+		f.Func.FunctionReference = def
+	}
+	return def
+}
+
+// replaceUDF replaces a call to a SQL user-defined function with a subquery
+// which evaluates the body of the function, effectively inlining the function
+// into the calling query. See MakeUDFQuery for the shape of the subquery.
+func (s *scope) replaceUDF(f *tree.FuncExpr, def *tree.FunctionDefinition) tree.Expr {
+	if s.builder.insideViewDef {
+		panic(unimplemented.NewWithIssue(17511, "user-defined functions are not supported in views"))
+	}
+	if f.Type != 0 || f.Filter != nil || len(f.OrderBy) > 0 || f.WindowDef != nil {
+		panic(pgerror.Newf(pgcode.WrongObjectType,
+			"%s() is not an aggregate or window function", def.Name))
+	}
+
+	s.builder.numInlinedUDFs++
+	if s.builder.numInlinedUDFs > maxInlinedUDFs {
+		panic(pgerror.Newf(pgcode.ProgramLimitExceeded,
+			"too many calls to user-defined functions; %s() may be recursive", def.Name))
+	}
+
+	// The memo cannot be reused, since the definition of the function can
+	// change without the query being invalidated.
+	s.builder.DisableMemoReuse = true
+
+	// Resolve the overload using a copy of the function expression which
+	// references the resolved definition, so that the definition is not cached
+	// in the original expression.
+	fCopy := *f
+	fCopy.Func = tree.ResolvableFunctionReference{FunctionReference: def}
+	expr := fCopy.Walk(s)
+	typedFunc, err := tree.TypeCheck(s.builder.ctx, expr, s.builder.semaCtx, types.Any)
+	if err != nil {
+		panic(err)
+	}
+	typedFuncExpr, ok := typedFunc.(*tree.FuncExpr)
+	if !ok {
+		panic(errors.AssertionFailedf("expected function expression, found %T", typedFunc))
+	}
+
+	stmt, err := MakeUDFQuery(typedFuncExpr.ResolvedOverload(), typedFuncExpr.Exprs)
+	if err != nil {
+		panic(err)
+	}
+	return s.replaceSubquery(
+		&tree.Subquery{Select: &tree.ParenSelect{Select: stmt}},
+		false /* wrapInTuple */, 1 /* desiredNumColumns */, noExtraColsAllowed,
+	)
+}
+
+// MakeUDFQuery constructs the query which evaluates a call to the SQL
+// user-defined function described by the given overload with the given
+// arguments. The body of the function is evaluated in a lateral join with a
+// single row which holds the arguments, so that the body can reference the
+// arguments either by name or by position:
+//
+//   SELECT "$result"::<return type>
+//   FROM (VALUES (<arg1>::<type1>, ...)) AS "$args"(<name1>, ...),
+//   LATERAL (<body>) AS "$body"("$result")
+//   [WHERE "$args".<name1> IS DISTINCT FROM NULL AND ...]
+//   LIMIT 1
+//
+// Unnamed arguments are named $1, $2, etc., and positional references in the
+// body are replaced with references to the corresponding argument columns.
+// The WHERE clause is only added for functions which return NULL on NULL
+// input. As in Postgres, the result of the function is the first row returned
+// by the body, or NULL if the body returns no rows.
+func MakeUDFQuery(o *tree.Overload, args tree.Exprs) (*tree.Select, error) {
+	sel, argNames, err := makeUDFSelect(o, args, tree.NameList{udfResultColName})
+	if err != nil {
+		return nil, err
+	}
+	sel.Exprs = tree.SelectExprs{{
+		Expr: &tree.CastExpr{
+			Expr:       tree.NewUnresolvedName(udfResultColName),
+			Type:       o.FixedReturnType(),
+			SyntaxMode: tree.CastShort,
+		},
+	}}
+	if len(args) > 0 && o.ReturnsNullOnNullInput {
+		var where tree.Expr
+		for i := range argNames {
+			cond := &tree.ComparisonExpr{
+				Operator: tree.MakeComparisonOperator(tree.IsDistinctFrom),
+				Left:     tree.NewUnresolvedName(udfArgsAlias, string(argNames[i])),
+				Right:    tree.DNull,
+			}
+			if where == nil {
+				where = cond
+			} else {
+				where = &tree.AndExpr{Left: where, Right: cond}
+			}
+		}
+		sel.Where = tree.NewWhere(tree.AstWhere, where)
+	}
+	return &tree.Select{
+		Select: sel,
+		Limit:  &tree.Limit{Count: tree.NewDInt(1)},
+	}, nil
+}
+
+// MakeUDFBodyQuery constructs a query which returns all the columns produced
+// by the body of the SQL user-defined function described by the given
+// overload, evaluated with the given arguments. It is used to validate the
+// body of a function when the function is created.
+func MakeUDFBodyQuery(o *tree.Overload, args tree.Exprs) (*tree.Select, error) {
+	sel, _, err := makeUDFSelect(o, args, nil /* bodyCols */)
+	if err != nil {
+		return nil, err
+	}
+	sel.Exprs = tree.SelectExprs{{
+		Expr: &tree.AllColumnsSelector{
+			TableName: &tree.UnresolvedObjectName{NumParts: 1, Parts: [3]string{udfBodyAlias}},
+		},
+	}}
+	return &tree.Select{Select: sel}, nil
+}
+
+// makeUDFSelect constructs a SELECT clause, without projections, whose FROM
+// clause evaluates the body of the given function as described in
+// MakeUDFQuery. It also returns the names of the argument columns.
+func makeUDFSelect(
+	o *tree.Overload, args tree.Exprs, bodyCols tree.NameList,
+) (*tree.SelectClause, tree.NameList, error) {
+	argTypes, ok := o.Types.(tree.ArgTypes)
+	if !ok || len(argTypes) != len(args) {
+		return nil, nil, errors.AssertionFailedf("unexpected arguments for user-defined function")
+	}
+	argNames := make(tree.NameList, len(argTypes))
+	for i := range argTypes {
+		if argTypes[i].Name != "" {
+			argNames[i] = tree.Name(argTypes[i].Name)
+		} else {
+			argNames[i] = tree.Name(fmt.Sprintf("$%d", i+1))
+		}
+	}
+
+	body, err := parseUDFBody(o.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Replace positional references to the arguments.
+	var visitErr error
+	newBody, _ := tree.SimpleStmtVisit(body, func(expr tree.Expr) (bool, tree.Expr, error) {
+		p, ok := expr.(*tree.Placeholder)
+		if !ok || visitErr != nil {
+			return visitErr == nil, expr, nil
+		}
+		if int(p.Idx) >= len(argNames) {
+			visitErr = pgerror.Newf(pgcode.UndefinedParameter, "there is no parameter %s", p)
+			return false, expr, nil
+		}
+		return false, tree.NewUnresolvedName(udfArgsAlias, string(argNames[p.Idx])), nil
+	})
+	if visitErr != nil {
+		return nil, nil, visitErr
+	}
+
+	bodyExpr := &tree.AliasedTableExpr{
+		Expr:    &tree.Subquery{Select: &tree.ParenSelect{Select: newBody.(*tree.Select)}},
+		Lateral: true,
+		As:      tree.AliasClause{Alias: udfBodyAlias, Cols: bodyCols},
+	}
+	sel := &tree.SelectClause{}
+	if len(args) == 0 {
+		sel.From.Tables = tree.TableExprs{bodyExpr}
+		return sel, argNames, nil
+	}
+	row := make(tree.Exprs, len(args))
+	for i := range args {
+		row[i] = &tree.CastExpr{Expr: args[i], Type: argTypes[i].Typ, SyntaxMode: tree.CastShort}
+	}
+	argsExpr := &tree.AliasedTableExpr{
+		Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{
+			Select: &tree.ValuesClause{Rows: []tree.Exprs{row}},
+		}}},
+		As: tree.AliasClause{Alias: udfArgsAlias, Cols: argNames},
+	}
+	sel.From.Tables = tree.TableExprs{argsExpr, bodyExpr}
+	return sel, argNames, nil
+}
+
+// parseUDFBody parses the body of a SQL user-defined function. Only bodies
+// which consist of a single SELECT statement are supported.
+func parseUDFBody(body string) (*tree.Select, error) {
+	stmt, err := parser.ParseOne(body)
+	if err != nil {
+		return nil, err
+	}
+	sel, ok := stmt.AST.(*tree.Select)
+	if !ok {
+		return nil, unimplemented.NewWithIssuef(17511,
+			"user-defined functions with %s statements in their body are not supported", stmt.AST.StatementTag())
+	}
+	return sel, nil
+}
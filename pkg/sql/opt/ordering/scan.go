@@ -114,6 +114,10 @@ func ScanPrivateCanProvide(
 		}
 		left, right = left+1, right+1
 	}
+	if direction == rev && md.Table(s.Table).IsForeignTable() {
+		// The files of foreign tables can only be read from the start.
+		return false, false
+	}
 	// If direction is either, we prefer forward scan.
 	return true, direction == rev
 }
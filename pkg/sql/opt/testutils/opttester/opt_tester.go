@@ -44,6 +44,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/cat"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/norm"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
@@ -62,6 +63,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/stop"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/treeprinter"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
 	"github.com/cockroachdb/datadriven"
 	"github.com/cockroachdb/errors"
 	"github.com/pmezard/go-difflib/difflib"
@@ -272,6 +274,7 @@ func New(catalog cat.Catalog, sql string) *OptTester {
 	ot.evalCtx.SessionData().LocalityOptimizedSearch = true
 	ot.evalCtx.SessionData().ReorderJoinsLimit = opt.DefaultJoinOrderLimit
 	ot.evalCtx.SessionData().InsertFastPath = true
+	ot.evalCtx.SessionData().TrigramSimilarityThreshold = trigram.DefaultSimilarityThreshold
 
 	return ot
 }
@@ -393,6 +396,12 @@ func New(catalog cat.Catalog, sql string) *OptTester {
 //    check-size will result in a test error if the rule application or memo
 //    group count exceeds the corresponding limit.
 //
+//  - index-recommendations
+//
+//    Builds an expression tree from a SQL query and outputs the CREATE INDEX
+//    statements for the hypothetical indexes that would lower its cost. See
+//    indexrec.Recommend.
+//
 // Supported flags:
 //
 //  - format: controls the formatting of expressions for build, opt, and
@@ -708,6 +717,13 @@ func (ot *OptTester) RunCommand(tb testing.TB, d *datadriven.TestData) string {
 		}
 		return result
 
+	case "index-recommendations":
+		result, err := ot.IndexRecommendations()
+		if err != nil {
+			d.Fatalf(tb, "%+v", err)
+		}
+		return result
+
 	default:
 		d.Fatalf(tb, "unsupported command: %s", d.Cmd)
 		return ""
@@ -1154,6 +1170,24 @@ func (ot *OptTester) Memo() (string, error) {
 	return o.FormatMemo(ot.Flags.MemoFormat), nil
 }
 
+// IndexRecommendations returns the index recommendations for the SQL query,
+// one per line.
+func (ot *OptTester) IndexRecommendations() (string, error) {
+	o := ot.makeOptimizer()
+	o.Factory().FoldingControl().AllowStableFolds()
+	if err := ot.buildExpr(o.Factory()); err != nil {
+		return "", err
+	}
+	recs, err := indexrec.Recommend(ot.ctx, o, ot.catalog)
+	if err != nil {
+		return "", err
+	}
+	if len(recs) == 0 {
+		return "No index recommendations.\n", nil
+	}
+	return strings.Join(recs, "\n") + "\n", nil
+}
+
 // Expr parses the input directly into an expression; see exprgen.Build.
 func (ot *OptTester) Expr() (opt.Expr, error) {
 	var f norm.Factory
@@ -467,8 +467,9 @@ func (c *CustomFuncs) GenerateLimitedGroupByScans(
 		// Construct an IndexJoin operator that provides the columns missing from
 		// the index.
 		input = c.e.f.ConstructIndexJoin(input, &memo.IndexJoinPrivate{
-			Table: sp.Table,
-			Cols:  sp.Cols,
+			Table:   sp.Table,
+			Cols:    sp.Cols,
+			Locking: sp.Locking,
 		})
 		// Reconstruct the GroupBy and Limit so the new expression in the memo is
 		// equivalent.
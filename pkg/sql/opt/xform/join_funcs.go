@@ -317,6 +317,10 @@ func (c *CustomFuncs) generateLookupJoinsImpl(
 		return
 	}
 	md := c.e.mem.Metadata()
+	// The rows of foreign tables can only be read by scanning their files.
+	if md.Table(scanPrivate.Table).IsForeignTable() {
+		return
+	}
 	inputProps := input.Relational()
 
 	leftEq, rightEq := memo.ExtractJoinEqualityColumns(inputProps.OutputCols, rightCols, on)
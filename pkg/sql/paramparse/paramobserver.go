@@ -15,9 +15,13 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/geo/geoindex"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/errors"
 )
@@ -67,11 +71,35 @@ type StorageParamObserver interface {
 	RunPostChecks() error
 }
 
+// ResetStorageParameters resets the given storage parameters using the
+// given observer.
+func ResetStorageParameters(
+	ctx context.Context,
+	evalCtx *tree.EvalContext,
+	params []string,
+	paramObserver *TableStorageParamObserver,
+) error {
+	for _, key := range params {
+		if err := paramObserver.Reset(evalCtx, key); err != nil {
+			return err
+		}
+	}
+	return paramObserver.RunPostChecks()
+}
+
 // TableStorageParamObserver observes storage parameters for tables.
-type TableStorageParamObserver struct{}
+type TableStorageParamObserver struct {
+	tableDesc *tabledesc.Mutable
+}
 
 var _ StorageParamObserver = (*TableStorageParamObserver)(nil)
 
+// NewTableStorageParamObserver returns a new TableStorageParamObserver which
+// applies storage parameters to the given table descriptor.
+func NewTableStorageParamObserver(tableDesc *tabledesc.Mutable) *TableStorageParamObserver {
+	return &TableStorageParamObserver{tableDesc: tableDesc}
+}
+
 func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tree.Datum) error {
 	val, err := DatumAsFloat(evalCtx, key, datum)
 	if err != nil {
@@ -89,8 +117,63 @@ func applyFillFactorStorageParam(evalCtx *tree.EvalContext, key string, datum tr
 	return nil
 }
 
+func boolFromDatum(evalCtx *tree.EvalContext, key string, datum tree.Datum) (bool, error) {
+	if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
+		return ParseBoolVar(key, stringVal)
+	}
+	s, err := GetSingleBool(key, datum)
+	if err != nil {
+		return false, err
+	}
+	return bool(*s), nil
+}
+
 // RunPostChecks implements the StorageParamObserver interface.
 func (a *TableStorageParamObserver) RunPostChecks() error {
+	return tabledesc.ValidateRowLevelTTL(a.tableDesc.GetRowLevelTTL())
+}
+
+// rowLevelTTL returns the row-level TTL configuration of the table,
+// initializing an empty one if none exists yet.
+func (a *TableStorageParamObserver) rowLevelTTL() *descpb.TableDescriptor_RowLevelTTL {
+	if a.tableDesc.RowLevelTTL == nil {
+		a.tableDesc.RowLevelTTL = &descpb.TableDescriptor_RowLevelTTL{}
+	}
+	return a.tableDesc.RowLevelTTL
+}
+
+func (a *TableStorageParamObserver) applyTTLExpireAfter(
+	evalCtx *tree.EvalContext, key string, datum tree.Datum,
+) error {
+	var d *tree.DInterval
+	if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
+		d, err = tree.ParseDInterval(evalCtx.GetIntervalStyle(), stringVal)
+		if err != nil {
+			return pgerror.Wrapf(
+				err,
+				pgcode.InvalidParameterValue,
+				`value of "%s" must be an interval`,
+				key,
+			)
+		}
+	} else {
+		var ok bool
+		if d, ok = datum.(*tree.DInterval); !ok {
+			return pgerror.Newf(
+				pgcode.InvalidParameterValue,
+				`value of "%s" must be an interval`,
+				key,
+			)
+		}
+	}
+	if d.Duration.Compare(duration.MakeDuration(0, 0, 0)) <= 0 {
+		return pgerror.Newf(
+			pgcode.InvalidParameterValue,
+			`value of "%s" must be greater than zero`,
+			key,
+		)
+	}
+	a.rowLevelTTL().DurationExpr = tree.Serialize(d)
 	return nil
 }
 
@@ -101,19 +184,80 @@ func (a *TableStorageParamObserver) Apply(
 	switch key {
 	case `fillfactor`:
 		return applyFillFactorStorageParam(evalCtx, key, datum)
-	case `autovacuum_enabled`:
-		var boolVal bool
-		if stringVal, err := DatumAsString(evalCtx, key, datum); err == nil {
-			boolVal, err = ParseBoolVar(key, stringVal)
-			if err != nil {
-				return err
-			}
+	case `ttl`:
+		boolVal, err := boolFromDatum(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if !boolVal {
+			return errors.WithHint(
+				pgerror.Newf(
+					pgcode.InvalidParameterValue,
+					`setting "%s = off" is not permitted`,
+					key,
+				),
+				"use RESET (ttl) to remove TTL from the table",
+			)
+		}
+		a.rowLevelTTL()
+		return nil
+	case `ttl_expire_after`:
+		return a.applyTTLExpireAfter(evalCtx, key, datum)
+	case `ttl_select_batch_size`, `ttl_delete_batch_size`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if err := tabledesc.ValidateTTLBatchSize(key, val); err != nil {
+			return err
+		}
+		if key == `ttl_select_batch_size` {
+			a.rowLevelTTL().SelectBatchSize = val
 		} else {
-			s, err := GetSingleBool(key, datum)
-			if err != nil {
-				return err
-			}
-			boolVal = bool(*s)
+			a.rowLevelTTL().DeleteBatchSize = val
+		}
+		return nil
+	case `ttl_range_concurrency`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if err := tabledesc.ValidateTTLRangeConcurrency(key, val); err != nil {
+			return err
+		}
+		a.rowLevelTTL().RangeConcurrency = val
+		return nil
+	case `ttl_delete_rate_limit`:
+		val, err := DatumAsInt(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if err := tabledesc.ValidateTTLRateLimit(key, val); err != nil {
+			return err
+		}
+		a.rowLevelTTL().DeleteRateLimit = val
+		return nil
+	case `ttl_job_cron`:
+		str, err := DatumAsString(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		if err := tabledesc.ValidateTTLCronExpr(key, str); err != nil {
+			return err
+		}
+		a.rowLevelTTL().DeletionCron = str
+		return nil
+	case `ttl_pause`:
+		boolVal, err := boolFromDatum(evalCtx, key, datum)
+		if err != nil {
+			return err
+		}
+		a.rowLevelTTL().Pause = boolVal
+		return nil
+	case `autovacuum_enabled`:
+		boolVal, err := boolFromDatum(evalCtx, key, datum)
+		if err != nil {
+			return err
 		}
 		if !boolVal && evalCtx != nil {
 			evalCtx.ClientNoticeSender.BufferClientNotice(
@@ -154,6 +298,60 @@ func (a *TableStorageParamObserver) Apply(
 	return errors.Errorf("invalid storage parameter %q", key)
 }
 
+// Reset resets the given storage parameter to its default value.
+func (a *TableStorageParamObserver) Reset(evalCtx *tree.EvalContext, key string) error {
+	switch key {
+	case `fillfactor`, `autovacuum_enabled`:
+		return nil
+	case `ttl`:
+		a.tableDesc.RowLevelTTL = nil
+		return nil
+	case `ttl_expire_after`:
+		if a.tableDesc.HasRowLevelTTL() {
+			return errors.WithHint(
+				pgerror.Newf(
+					pgcode.InvalidParameterValue,
+					`resetting "%s" is not permitted`,
+					key,
+				),
+				"use RESET (ttl) to remove TTL from the table",
+			)
+		}
+		return nil
+	case `ttl_select_batch_size`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.SelectBatchSize = 0
+		}
+		return nil
+	case `ttl_delete_batch_size`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.DeleteBatchSize = 0
+		}
+		return nil
+	case `ttl_range_concurrency`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.RangeConcurrency = 0
+		}
+		return nil
+	case `ttl_delete_rate_limit`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.DeleteRateLimit = 0
+		}
+		return nil
+	case `ttl_job_cron`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.DeletionCron = ""
+		}
+		return nil
+	case `ttl_pause`:
+		if ttl := a.tableDesc.RowLevelTTL; ttl != nil {
+			ttl.Pause = false
+		}
+		return nil
+	}
+	return errors.Errorf("invalid storage parameter %q", key)
+}
+
 // IndexStorageParamObserver observes storage parameters for indexes.
 type IndexStorageParamObserver struct {
 	IndexDesc *descpb.IndexDescriptor
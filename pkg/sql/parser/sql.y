@@ -33,6 +33,8 @@ import (
     "github.com/cockroachdb/cockroach/pkg/roachpb"
     "github.com/cockroachdb/cockroach/pkg/security"
     "github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+    "github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+    "github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
     "github.com/cockroachdb/cockroach/pkg/sql/privilege"
     "github.com/cockroachdb/cockroach/pkg/sql/roleoption"
     "github.com/cockroachdb/cockroach/pkg/sql/scanner"
@@ -300,6 +302,21 @@ func (u *sqlSymUnion) nameList() tree.NameList {
 func (u *sqlSymUnion) enumValueList() tree.EnumValueList {
     return u.val.(tree.EnumValueList)
 }
+func (u *sqlSymUnion) domainConstraint() tree.DomainConstraint {
+    return u.val.(tree.DomainConstraint)
+}
+func (u *sqlSymUnion) domainConstraintList() tree.DomainConstraintList {
+    return u.val.(tree.DomainConstraintList)
+}
+func (u *sqlSymUnion) compositeTypeList() []tree.CompositeTypeElem {
+    return u.val.([]tree.CompositeTypeElem)
+}
+func (u *sqlSymUnion) alterTypeAttributeAction() tree.AlterTypeAttributeAction {
+    return u.val.(tree.AlterTypeAttributeAction)
+}
+func (u *sqlSymUnion) alterTypeAttributeActions() tree.AlterTypeAttributeActions {
+    return u.val.(tree.AlterTypeAttributeActions)
+}
 func (u *sqlSymUnion) unresolvedName() *tree.UnresolvedName {
     return u.val.(*tree.UnresolvedName)
 }
@@ -486,6 +503,15 @@ func (u *sqlSymUnion) updateExpr() *tree.UpdateExpr {
 func (u *sqlSymUnion) updateExprs() tree.UpdateExprs {
     return u.val.(tree.UpdateExprs)
 }
+func (u *sqlSymUnion) mergeWhen() *tree.MergeWhen {
+    return u.val.(*tree.MergeWhen)
+}
+func (u *sqlSymUnion) mergeWhens() tree.MergeWhens {
+    return u.val.(tree.MergeWhens)
+}
+func (u *sqlSymUnion) mergeAction() tree.MergeAction {
+    return u.val.(tree.MergeAction)
+}
 func (u *sqlSymUnion) limit() *tree.Limit {
     return u.val.(*tree.Limit)
 }
@@ -648,6 +674,9 @@ func (u *sqlSymUnion) transactionModes() tree.TransactionModes {
 func (u *sqlSymUnion) compositeKeyMatchMethod() tree.CompositeKeyMatchMethod {
   return u.val.(tree.CompositeKeyMatchMethod)
 }
+func (u *sqlSymUnion) constraintDeferrability() tree.ConstraintDeferrability {
+  return u.val.(tree.ConstraintDeferrability)
+}
 func (u *sqlSymUnion) referenceAction() tree.ReferenceAction {
     return u.val.(tree.ReferenceAction)
 }
@@ -734,6 +763,36 @@ func (u *sqlSymUnion) alterDefaultPrivilegesTargetObject() tree.AlterDefaultPriv
 func (u *sqlSymUnion) setVar() *tree.SetVar {
     return u.val.(*tree.SetVar)
 }
+func (u *sqlSymUnion) cursorSensitivity() tree.CursorSensitivity {
+    return u.val.(tree.CursorSensitivity)
+}
+func (u *sqlSymUnion) cursorScrollOption() tree.CursorScrollOption {
+    return u.val.(tree.CursorScrollOption)
+}
+func (u *sqlSymUnion) cursorStmt() tree.CursorStmt {
+    return u.val.(tree.CursorStmt)
+}
+func (u *sqlSymUnion) funcArg() tree.FuncArg {
+    return u.val.(tree.FuncArg)
+}
+func (u *sqlSymUnion) funcArgClass() tree.FuncArgClass {
+    return u.val.(tree.FuncArgClass)
+}
+func (u *sqlSymUnion) funcArgs() tree.FuncArgs {
+    return u.val.(tree.FuncArgs)
+}
+func (u *sqlSymUnion) functionOption() tree.FunctionOption {
+    return u.val.(tree.FunctionOption)
+}
+func (u *sqlSymUnion) functionOptions() tree.FunctionOptions {
+    return u.val.(tree.FunctionOptions)
+}
+func (u *sqlSymUnion) funcObj() tree.FuncObj {
+    return u.val.(tree.FuncObj)
+}
+func (u *sqlSymUnion) funcObjs() tree.FuncObjs {
+    return u.val.(tree.FuncObjs)
+}
 %}
 
 // NB: the %token definitions must come before the %type definitions in this
@@ -753,15 +812,16 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 // below; search this file for "Keyword category lists".
 
 // Ordinary key words in alphabetical order.
-%token <str> ABORT ACCESS ACTION ADD ADMIN AFTER AGGREGATE
-%token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC
+%token <str> ABORT ABSOLUTE ACCESS ACTION ADD ADJACENT ADMIN AFTER AGGREGATE
+%token <str> ALL ALTER ALWAYS ANALYSE ANALYZE AND AND_AND ANY ANNOTATE_TYPE ARRAY AS ASC AT_AT
+%token <str> ASENSITIVE
 %token <str> ASYMMETRIC AT ATTRIBUTE AUTHORIZATION AUTOMATIC AVAILABILITY
 
-%token <str> BACKUP BACKUPS BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
+%token <str> BACKUP BACKUPS BACKWARD BEFORE BEGIN BETWEEN BIGINT BIGSERIAL BINARY BIT
 %token <str> BUCKET_COUNT
 %token <str> BOOLEAN BOTH BOX2D BUNDLE BY
 
-%token <str> CACHE CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
+%token <str> CACHE CALL CALLED CANCEL CANCELQUERY CASCADE CASE CAST CBRT CHANGEFEED CHAR
 %token <str> CHARACTER CHARACTERISTICS CHECK CLOSE
 %token <str> CLUSTER COALESCE COLLATE COLLATION COLUMN COLUMNS COMMENT COMMENTS COMMIT
 %token <str> COMMITTED COMPACT COMPLETE CONCAT CONCURRENTLY CONFIGURATION CONFIGURATIONS CONFIGURE
@@ -773,7 +833,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 
 %token <str> DATA DATABASE DATABASES DATE DAY DEBUG_PAUSE_ON DEC DECIMAL DEFAULT DEFAULTS
 %token <str> DEALLOCATE DECLARE DEFERRABLE DEFERRED DELETE DELIMITER DESC DESTINATION DETACHED
-%token <str> DISCARD DISTINCT DO DOMAIN DOUBLE DROP
+%token <str> DISCARD DISTANCE DISTINCT DO DOMAIN DOUBLE DROP
 
 %token <str> ELSE ENCODING ENCRYPTION_PASSPHRASE END ENUM ENUMS ESCAPE EXCEPT EXCLUDE EXCLUDING
 %token <str> EXISTS EXECUTE EXECUTION EXPERIMENTAL
@@ -783,62 +843,62 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 
 %token <str> FAILURE FALSE FAMILY FETCH FETCHVAL FETCHTEXT FETCHVAL_PATH FETCHTEXT_PATH
 %token <str> FILES FILTER
-%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FORCE_ZIGZAG FOREIGN FROM FULL FUNCTION FUNCTIONS
+%token <str> FIRST FLOAT FLOAT4 FLOAT8 FLOORDIV FOLLOWING FOR FORCE FORCE_INDEX FORCE_ZIGZAG FOREIGN FORWARD FROM FULL FUNCTION FUNCTIONS
 
 %token <str> GENERATED GEOGRAPHY GEOMETRY GEOMETRYM GEOMETRYZ GEOMETRYZM
 %token <str> GEOMETRYCOLLECTION GEOMETRYCOLLECTIONM GEOMETRYCOLLECTIONZ GEOMETRYCOLLECTIONZM
 %token <str> GLOBAL GOAL GRANT GRANTS GREATEST GROUP GROUPING GROUPS
 
-%token <str> HAVING HASH HIGH HISTOGRAM HOUR
+%token <str> HAVING HASH HEADER HIGH HISTOGRAM HOLD HOUR
 
 %token <str> IDENTITY
-%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
+%token <str> IF IFERROR IFNULL IGNORE_FOREIGN_KEYS ILIKE IMMEDIATE IMMUTABLE IMPORT IN INCLUDE INCLUDING INCREMENT INCREMENTAL
 %token <str> INET INET_CONTAINED_BY_OR_EQUALS
 %token <str> INET_CONTAINS_OR_EQUALS INDEX INDEXES INHERITS INJECT INITIALLY
-%token <str> INNER INSERT INT INTEGER
+%token <str> INNER INOUT INPUT INSENSITIVE INSERT INT INTEGER
 %token <str> INTERSECT INTERVAL INTO INTO_DB INVERTED IS ISERROR ISNULL ISOLATION
 
-%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS
+%token <str> JOB JOBS JOIN JSON JSONB JSON_SOME_EXISTS JSON_ALL_EXISTS JSON_PATH_EXISTS
 
 %token <str> KEY KEYS KMS KV
 
 %token <str> LANGUAGE LAST LATERAL LATEST LC_CTYPE LC_COLLATE
-%token <str> LEADING LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
+%token <str> LEADING LEAKPROOF LEASE LEAST LEFT LESS LEVEL LIKE LIMIT
 %token <str> LINESTRING LINESTRINGM LINESTRINGZ LINESTRINGZM
-%token <str> LIST LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
+%token <str> LIST LISTEN LOCAL LOCALITY LOCALTIME LOCALTIMESTAMP LOCKED LOGIN LOOKUP LOW LSHIFT
 
-%token <str> MATCH MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH
+%token <str> MATCH MATCHED MATERIALIZED MERGE MINVALUE MAXVALUE METHOD MINUTE MODIFYCLUSTERSETTING MONTH MOVE
 %token <str> MULTILINESTRING MULTILINESTRINGM MULTILINESTRINGZ MULTILINESTRINGZM
 %token <str> MULTIPOINT MULTIPOINTM MULTIPOINTZ MULTIPOINTZM
 %token <str> MULTIPOLYGON MULTIPOLYGONM MULTIPOLYGONZ MULTIPOLYGONZM
 
 %token <str> NAN NAME NAMES NATURAL NEVER NEW_DB_NAME NEXT NO NOCANCELQUERY NOCONTROLCHANGEFEED
 %token <str> NOCONTROLJOB NOCREATEDB NOCREATELOGIN NOCREATEROLE NOLOGIN NOMODIFYCLUSTERSETTING
-%token <str> NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NON_VOTERS NORMAL NOT NOTHING NOTNULL
+%token <str> NO_INDEX_JOIN NO_ZIGZAG_JOIN NO_FULL_SCAN NONE NON_VOTERS NORMAL NOT NOTHING NOTIFY NOTNULL
 %token <str> NOVIEWACTIVITY NOWAIT NULL NULLIF NULLS NUMERIC
 
 %token <str> OF OFF OFFSET OID OIDS OIDVECTOR ON ONLY OPT OPTION OPTIONS OR
-%token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OWNED OWNER OPERATOR
+%token <str> ORDER ORDINALITY OTHERS OUT OUTER OVER OVERLAPS OVERLAY OVERLEFT OVERRIGHT OWNED OWNER OPERATOR
 
 %token <str> PARENT PARTIAL PARTITION PARTITIONS PASSWORD PAUSE PAUSED PHYSICAL PLACEMENT PLACING
 %token <str> PLAN PLANS POINT POINTM POINTZ POINTZM POLYGON POLYGONM POLYGONZ POLYGONZM
-%token <str> POSITION PRECEDING PRECISION PREPARE PRESERVE PRIMARY PRIORITY PRIVILEGES
-%token <str> PROCEDURAL PUBLIC PUBLICATION
+%token <str> POSITION PRECEDING PRECISION PREPARE PREPARED PRESERVE PRIMARY PRIOR PRIORITY PRIVILEGES
+%token <str> PROCEDURAL PROCEDURE PUBLIC PUBLICATION
 
 %token <str> QUERIES QUERY
 
 %token <str> RANGE RANGES READ REAL REASON REASSIGN RECURSIVE RECURRING REF REFERENCES REFRESH
 %token <str> REGCLASS REGION REGIONAL REGIONS REGNAMESPACE REGPROC REGPROCEDURE REGROLE REGTYPE REINDEX
 %token <str> REMOVE_PATH RENAME REPEATABLE REPLACE REPLICATION
-%token <str> RELEASE RESET RESTORE RESTRICT RESTRICTED RESUME RETURNING RETRY REVISION_HISTORY
+%token <str> RELATIVE RELEASE RESET RESTORE RESTRICT RESTRICTED RESUME RETURNING RETURNS RETRY REVISION_HISTORY
 %token <str> REVOKE RIGHT ROLE ROLES ROLLBACK ROLLUP ROUTINES ROW ROWS RSHIFT RULE RUNNING
 
-%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
+%token <str> SAVEPOINT SCANS SCATTER SCHEDULE SCHEDULES SCHEMA SCHEMAS SCROLL SCRUB SEARCH SECOND SELECT SEQUENCE SEQUENCES
 %token <str> SERIALIZABLE SERVER SESSION SESSIONS SESSION_USER SET SETS SETTING SETTINGS
 %token <str> SHARE SHOW SIMILAR SIMPLE SKIP SKIP_LOCALITIES_CHECK SKIP_MISSING_FOREIGN_KEYS
 %token <str> SKIP_MISSING_SEQUENCES SKIP_MISSING_SEQUENCE_OWNERS SKIP_MISSING_VIEWS SMALLINT SMALLSERIAL SNAPSHOT SOME SPLIT SQL
 
-%token <str> START STATISTICS STATUS STDIN STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
+%token <str> STABLE START STATISTICS STATUS STDIN STDOUT STREAM STRICT STRING STORAGE STORE STORED STORING SUBSTRING
 %token <str> SURVIVE SURVIVAL SYMMETRIC SYNTAX SYSTEM SQRT SUBSCRIPTION STATEMENTS
 
 %token <str> TABLE TABLES TABLESPACE TEMP TEMPLATE TEMPORARY TENANT TESTING_RELOCATE EXPERIMENTAL_RELOCATE TEXT THEN
@@ -847,12 +907,12 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %token <str> TRUNCATE TRUSTED TYPE TYPES
 %token <str> TRACING
 
-%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLOGGED UNSPLIT
+%token <str> UNBOUNDED UNCOMMITTED UNION UNIQUE UNKNOWN UNLISTEN UNLOGGED UNSPLIT
 %token <str> UPDATE UPSERT UNTIL USE USER USERS USING UUID
 
-%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL VISIBLE VOTERS
+%token <str> VALID VALIDATE VALUE VALUES VARBIT VARCHAR VARIADIC VIEW VARYING VIEWACTIVITY VIRTUAL VISIBLE VOLATILE VOTERS
 
-%token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRITE
+%token <str> WHEN WHERE WINDOW WITH WITHIN WITHOUT WORK WRAPPER WRITE
 
 %token <str> YEAR
 
@@ -897,6 +957,8 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> alter_role_stmt
 %type <*tree.SetVar> set_or_reset_clause
 %type <tree.Statement> alter_type_stmt
+%type <tree.Statement> alter_domain_stmt
+%type <tree.Statement> alter_publication_stmt
 %type <tree.Statement> alter_schema_stmt
 %type <tree.Statement> alter_unsupported_stmt
 
@@ -975,6 +1037,8 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> comment_stmt
 %type <tree.Statement> commit_stmt
 %type <tree.Statement> copy_from_stmt
+%type <tree.Statement> copy_to_stmt
+%type <tree.Statement> copy_to_query
 
 %type <tree.Statement> create_stmt
 %type <tree.Statement> create_changefeed_stmt create_replication_stream_stmt
@@ -995,18 +1059,35 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <*tree.CreateStatsOptions> create_stats_option_list
 %type <*tree.CreateStatsOptions> create_stats_option
 
+%type <tree.Statement> create_func_stmt
+%type <tree.Statement> create_proc_stmt
+%type <tree.Statement> create_publication_stmt
+%type <tree.Statement> create_subscription_stmt
+%type <tree.Statement> create_server_stmt
+%type <tree.Statement> create_foreign_table_stmt
+%type <[]tree.KVOption> opt_foreign_options foreign_option_list
+%type <tree.KVOption> foreign_option
+%type <tree.Statement> opt_publication_for_tables
+%type <[]tree.KVOption> opt_publication_options
 %type <tree.Statement> create_type_stmt
+%type <tree.Statement> create_domain_stmt
 %type <tree.Statement> delete_stmt
 %type <tree.Statement> discard_stmt
 
 %type <tree.Statement> drop_stmt
 %type <tree.Statement> drop_ddl_stmt
 %type <tree.Statement> drop_database_stmt
+%type <tree.Statement> drop_func_stmt
+%type <tree.Statement> drop_proc_stmt
+%type <tree.Statement> drop_publication_stmt
+%type <tree.Statement> drop_subscription_stmt
+%type <tree.Statement> drop_server_stmt
 %type <tree.Statement> drop_index_stmt
 %type <tree.Statement> drop_role_stmt
 %type <tree.Statement> drop_schema_stmt
 %type <tree.Statement> drop_table_stmt
 %type <tree.Statement> drop_type_stmt
+%type <tree.Statement> drop_domain_stmt
 %type <tree.Statement> drop_view_stmt
 %type <tree.Statement> drop_sequence_stmt
 
@@ -1045,6 +1126,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Statement> set_session_stmt
 %type <tree.Statement> set_csetting_stmt
 %type <tree.Statement> set_transaction_stmt
+%type <tree.Statement> set_constraints_stmt
 %type <tree.Statement> set_exprs_internal
 %type <tree.Statement> generic_set
 %type <tree.Statement> set_rest_more
@@ -1098,13 +1180,38 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <*string> comment_text
 
 %type <tree.Statement> transaction_stmt
+%type <tree.Statement> prepare_transaction_stmt
+%type <tree.Statement> commit_prepared_stmt
+%type <tree.Statement> rollback_prepared_stmt
 %type <tree.Statement> truncate_stmt
+%type <tree.Statement> merge_stmt
 %type <tree.Statement> update_stmt
 %type <tree.Statement> upsert_stmt
 %type <tree.Statement> use_stmt
 
 %type <tree.Statement> close_cursor_stmt
 %type <tree.Statement> declare_cursor_stmt
+%type <tree.Statement> call_stmt
+%type <tree.Statement> listen_stmt
+%type <tree.Statement> notify_stmt
+%type <tree.Statement> unlisten_stmt
+%type <tree.Statement> fetch_cursor_stmt
+%type <tree.Statement> move_cursor_stmt
+%type <tree.CursorStmt> cursor_movement_specifier
+%type <bool> opt_hold opt_binary
+%type <bool> opt_or_replace
+
+%type <tree.FuncArgs> func_arg_list opt_func_arg_list func_args
+%type <tree.FuncArg> func_arg
+%type <tree.FuncArgClass> func_arg_class
+%type <tree.FunctionOptions> opt_create_func_opt_list create_func_opt_list
+%type <tree.FunctionOption> create_func_opt_item common_func_opt_item
+%type <tree.FuncObjs> function_with_argtypes_list
+%type <tree.FuncObj> function_with_argtypes
+%type <tree.CursorSensitivity> opt_sensitivity
+%type <tree.CursorScrollOption> opt_scroll
+%type <int64> opt_forward_backward forward_backward
+%type <int64> next_prior
 %type <tree.Statement> reindex_stmt
 
 %type <[]string> opt_incremental
@@ -1116,6 +1223,8 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <str> import_format
 %type <tree.StorageParam> storage_parameter
 %type <[]tree.StorageParam> storage_parameter_list opt_table_with opt_with_storage_parameter_list
+%type <str> storage_parameter_key
+%type <[]string> storage_parameter_key_list
 
 %type <*tree.Select> select_no_parens
 %type <tree.SelectStatement> select_clause select_with_parens simple_select values_clause table_clause simple_select_clause
@@ -1205,7 +1314,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.NameList> name_list privilege_list
 %type <[]int32> opt_array_bounds
 %type <tree.From> from_clause
-%type <tree.TableExprs> from_list rowsfrom_list opt_from_list
+%type <tree.TableExprs> from_list rowsfrom_list opt_from_list opt_using_clause
 %type <tree.TablePatterns> table_pattern_list single_table_pattern_list
 %type <tree.TableNames> table_name_list opt_locked_rels
 %type <tree.Exprs> expr_list opt_expr_list tuple1_ambiguous_values tuple1_unambiguous_values
@@ -1215,6 +1324,10 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.SelectExprs> target_list
 %type <tree.UpdateExprs> set_clause_list
 %type <*tree.UpdateExpr> set_clause multiple_set_clause
+%type <tree.MergeWhens> merge_when_list
+%type <*tree.MergeWhen> merge_when_clause
+%type <tree.MergeAction> merge_matched_action merge_not_matched_action
+%type <tree.Expr> opt_merge_when_condition
 %type <tree.ArraySubscripts> array_subscripts
 %type <tree.GroupBy> group_clause
 %type <tree.Exprs> group_by_list
@@ -1222,7 +1335,6 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <*tree.Limit> select_limit opt_select_limit
 %type <tree.TableNames> relation_expr_list
 %type <tree.ReturningClause> returning_clause
-%type <empty> opt_using_clause
 %type <tree.RefreshDataOption> opt_clear_data
 
 %type <[]tree.SequenceOption> sequence_option_list opt_sequence_option_list
@@ -1305,6 +1417,11 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.ResolvableTypeReference> typename simple_typename cast_target
 %type <*types.T> const_typename
 %type <*tree.AlterTypeAddValuePlacement> opt_add_val_placement
+%type <tree.DomainConstraint> domain_constraint domain_constraint_elem
+%type <tree.DomainConstraintList> opt_domain_constraint_list domain_constraint_list
+%type <[]tree.CompositeTypeElem> opt_composite_type_list composite_type_list
+%type <tree.AlterTypeAttributeAction> alter_attribute_action
+%type <tree.AlterTypeAttributeActions> alter_attribute_action_list
 %type <bool> opt_timezone
 %type <*types.T> numeric opt_numeric_modifiers
 %type <*types.T> opt_float
@@ -1335,6 +1452,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.Expr> string_or_placeholder_list
 %type <str> region_or_regions
 
+%type <str> bare_col_label
 %type <str> unreserved_keyword type_func_name_keyword type_func_name_no_crdb_extra_keyword type_func_name_crdb_extra_keyword
 %type <str> col_name_keyword reserved_keyword cockroachdb_extra_reserved_keyword extra_var_value
 
@@ -1349,6 +1467,8 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %type <tree.ColumnQualification> col_qualification_elem create_as_col_qualification_elem
 %type <tree.CompositeKeyMatchMethod> key_match
 %type <tree.ReferenceActions> reference_actions
+%type <tree.ConstraintDeferrability> opt_deferrable
+%type <bool> constraints_set_mode
 %type <tree.ReferenceAction> reference_action reference_on_delete reference_on_update
 
 %type <tree.Expr> func_application func_expr_common_subexpr special_function
@@ -1448,7 +1568,7 @@ func (u *sqlSymUnion) setVar() *tree.SetVar {
 %left      '|'
 %left      '#'
 %left      '&'
-%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND SQRT CBRT
+%left      LSHIFT RSHIFT INET_CONTAINS_OR_EQUALS INET_CONTAINED_BY_OR_EQUALS AND_AND AT_AT JSON_PATH_EXISTS DISTANCE OVERLEFT OVERRIGHT ADJACENT SQRT CBRT
 %left      OPERATOR // if changing the last token before OPERATOR, change all instances of %prec <last token>
 %left      '+' '-'
 %left      '*' '/' FLOORDIV '%'
@@ -1482,7 +1602,9 @@ stmt:
   HELPTOKEN { return helpWith(sqllex, "") }
 | preparable_stmt           // help texts in sub-rule
 | analyze_stmt              // EXTEND WITH HELP: ANALYZE
+| call_stmt                 // EXTEND WITH HELP: CALL
 | copy_from_stmt
+| copy_to_stmt
 | comment_stmt
 | execute_stmt              // EXTEND WITH HELP: EXECUTE
 | deallocate_stmt           // EXTEND WITH HELP: DEALLOCATE
@@ -1497,8 +1619,13 @@ stmt:
 | refresh_stmt              // EXTEND WITH HELP: REFRESH
 | nonpreparable_set_stmt    // help texts in sub-rule
 | transaction_stmt          // help texts in sub-rule
-| close_cursor_stmt
-| declare_cursor_stmt
+| close_cursor_stmt         // EXTEND WITH HELP: CLOSE
+| declare_cursor_stmt       // EXTEND WITH HELP: DECLARE
+| fetch_cursor_stmt         // EXTEND WITH HELP: FETCH
+| move_cursor_stmt          // EXTEND WITH HELP: MOVE
+| listen_stmt               // EXTEND WITH HELP: LISTEN
+| notify_stmt               // EXTEND WITH HELP: NOTIFY
+| unlisten_stmt             // EXTEND WITH HELP: UNLISTEN
 | reindex_stmt
 | /* EMPTY */
   {
@@ -1524,6 +1651,8 @@ alter_ddl_stmt:
 | alter_partition_stmt          // EXTEND WITH HELP: ALTER PARTITION
 | alter_schema_stmt             // EXTEND WITH HELP: ALTER SCHEMA
 | alter_type_stmt               // EXTEND WITH HELP: ALTER TYPE
+| alter_domain_stmt             // EXTEND WITH HELP: ALTER DOMAIN
+| alter_publication_stmt        // EXTEND WITH HELP: ALTER PUBLICATION
 | alter_default_privileges_stmt // EXTEND WITH HELP: ALTER DEFAULT PRIVILEGES
 
 // %Help: ALTER TABLE - change the definition of a table
@@ -1556,6 +1685,8 @@ alter_ddl_stmt:
 //   ALTER TABLE ... CONFIGURE ZONE <zoneconfig>
 //   ALTER TABLE ... SET SCHEMA <newschemaname>
 //   ALTER TABLE ... SET LOCALITY [REGIONAL BY [TABLE IN <region> | ROW] | GLOBAL]
+//   ALTER TABLE ... SET (storage_param = value, ...)
+//   ALTER TABLE ... RESET (storage_param, ...)
 //
 // Column qualifiers:
 //   [CONSTRAINT <constraintname>] {NULL | NOT NULL | UNIQUE | PRIMARY KEY | CHECK (<expr>) | DEFAULT <expr>}
@@ -2297,6 +2428,20 @@ alter_table_cmd:
       Stats: $3.expr(),
     }
   }
+  // ALTER TABLE <name> SET (storage_param = value, ...)
+| SET '(' storage_parameter_list ')'
+  {
+    $$.val = &tree.AlterTableSetStorageParams{
+      StorageParams: $3.storageParams(),
+    }
+  }
+  // ALTER TABLE <name> RESET (storage_param, ...)
+| RESET '(' storage_parameter_key_list ')'
+  {
+    $$.val = &tree.AlterTableResetStorageParams{
+      Params: $3.strs(),
+    }
+  }
 
 audit_mode:
   READ WRITE { $$.val = tree.AuditModeReadWrite }
@@ -2475,11 +2620,25 @@ alter_type_stmt:
   }
 | ALTER TYPE type_name RENAME ATTRIBUTE column_name TO column_name opt_drop_behavior
   {
-    return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE")
+    if $9.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterType{
+      Type: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterTypeRenameAttribute{
+        Name: tree.Name($6),
+        NewName: tree.Name($8),
+      },
+    }
   }
 | ALTER TYPE type_name alter_attribute_action_list
   {
-    return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE")
+    $$.val = &tree.AlterType{
+      Type: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterTypeAlterAttributes{
+        Actions: $4.alterTypeAttributeActions(),
+      },
+    }
   }
 | ALTER TYPE error // SHOW HELP: ALTER TYPE
 
@@ -2543,14 +2702,74 @@ role_spec_list:
 
 alter_attribute_action_list:
   alter_attribute_action
+  {
+    $$.val = tree.AlterTypeAttributeActions{$1.alterTypeAttributeAction()}
+  }
 | alter_attribute_action_list ',' alter_attribute_action
+  {
+    $$.val = append($1.alterTypeAttributeActions(), $3.alterTypeAttributeAction())
+  }
 
+// CASCADE only affects typed tables, which are not supported.
 alter_attribute_action:
-  ADD ATTRIBUTE column_name type_name opt_collate opt_drop_behavior
+  ADD ATTRIBUTE column_name typename opt_collate opt_drop_behavior
+  {
+    if $5 != "" {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE COLLATE")
+    }
+    if $6.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterTypeAddAttribute{
+      Name: tree.Name($3),
+      Type: $4.typeReference(),
+    }
+  }
 | DROP ATTRIBUTE column_name opt_drop_behavior
+  {
+    if $4.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterTypeDropAttribute{
+      Name: tree.Name($3),
+    }
+  }
 | DROP ATTRIBUTE IF EXISTS column_name opt_drop_behavior
-| ALTER ATTRIBUTE column_name TYPE type_name opt_collate opt_drop_behavior
-| ALTER ATTRIBUTE column_name SET DATA TYPE type_name opt_collate opt_drop_behavior
+  {
+    if $6.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterTypeDropAttribute{
+      Name: tree.Name($5),
+      IfExists: true,
+    }
+  }
+| ALTER ATTRIBUTE column_name TYPE typename opt_collate opt_drop_behavior
+  {
+    if $6 != "" {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE COLLATE")
+    }
+    if $7.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterTypeAlterAttributeType{
+      Name: tree.Name($3),
+      Type: $5.typeReference(),
+    }
+  }
+| ALTER ATTRIBUTE column_name SET DATA TYPE typename opt_collate opt_drop_behavior
+  {
+    if $8 != "" {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE COLLATE")
+    }
+    if $9.dropBehavior() == tree.DropCascade {
+      return unimplementedWithIssueDetail(sqllex, 48701, "ALTER TYPE ATTRIBUTE CASCADE")
+    }
+    $$.val = &tree.AlterTypeAlterAttributeType{
+      Name: tree.Name($3),
+      Type: $7.typeReference(),
+    }
+  }
 
 // %Help: REFRESH - recalculate a materialized view
 // %Category: Misc
@@ -3048,10 +3267,6 @@ alter_unsupported_stmt:
   {
     return unimplemented(sqllex, "alter function")
   }
-| ALTER DOMAIN error
-  {
-    return unimplemented(sqllex, "alter domain")
-  }
 | ALTER AGGREGATE error
   {
     return unimplemented(sqllex, "alter aggregate")
@@ -3241,6 +3456,42 @@ copy_from_stmt:
     return unimplemented(sqllex, "copy from unsupported format")
   }
 
+copy_to_stmt:
+  COPY table_name opt_column_list TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    name := $2.unresolvedObjectName().ToTableName()
+    $$.val = &tree.CopyTo{
+       Table: name,
+       Columns: $3.nameList(),
+       Options: *$6.copyOptions(),
+    }
+  }
+| COPY '(' copy_to_query ')' TO STDOUT opt_with_copy_options
+  {
+    /* FORCE DOC */
+    $$.val = &tree.CopyTo{
+       Statement: $3.stmt(),
+       Options: *$7.copyOptions(),
+    }
+  }
+| COPY table_name opt_column_list TO error
+  {
+    return unimplemented(sqllex, "copy to unsupported destination")
+  }
+
+// copy_to_query is the set of statements whose results can be copied out by
+// COPY (...) TO STDOUT. Data-modifying statements must use RETURNING.
+copy_to_query:
+  select_stmt
+  {
+    $$.val = $1.slct()
+  }
+| insert_stmt
+| update_stmt
+| delete_stmt
+| upsert_stmt
+
 opt_with_copy_options:
   opt_with copy_options_list
   {
@@ -3284,6 +3535,10 @@ copy_options:
   {
     $$.val = &tree.CopyOptions{Null: $2.expr()}
   }
+| HEADER
+  {
+    $$.val = &tree.CopyOptions{Header: true}
+  }
 
 // %Help: CANCEL
 // %Category: Group
@@ -3492,23 +3747,17 @@ create_unsupported:
 | CREATE CONSTRAINT TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create constraint") }
 | CREATE CONVERSION error { return unimplemented(sqllex, "create conversion") }
 | CREATE DEFAULT CONVERSION error { return unimplemented(sqllex, "create def conv") }
-| CREATE FOREIGN TABLE error { return unimplemented(sqllex, "create foreign table") }
 | CREATE FOREIGN DATA error { return unimplemented(sqllex, "create fdw") }
-| CREATE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
-| CREATE OR REPLACE FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "create function") }
 | CREATE opt_or_replace opt_trusted opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "create language " + $6) }
 | CREATE OPERATOR error { return unimplementedWithIssue(sqllex, 65017) }
-| CREATE PUBLICATION error { return unimplemented(sqllex, "create publication") }
 | CREATE opt_or_replace RULE error { return unimplemented(sqllex, "create rule") }
-| CREATE SERVER error { return unimplemented(sqllex, "create server") }
-| CREATE SUBSCRIPTION error { return unimplemented(sqllex, "create subscription") }
 | CREATE TABLESPACE error { return unimplementedWithIssueDetail(sqllex, 54113, "create tablespace") }
 | CREATE TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "create text") }
 | CREATE TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "create trigger") }
 
 opt_or_replace:
-  OR REPLACE {}
-| /* EMPTY */ {}
+  OR REPLACE { $$.val = true }
+| /* EMPTY */ { $$.val = false }
 
 opt_trusted:
   TRUSTED {}
@@ -3524,18 +3773,12 @@ drop_unsupported:
 | DROP CAST error { return unimplemented(sqllex, "drop cast") }
 | DROP COLLATION error { return unimplemented(sqllex, "drop collation") }
 | DROP CONVERSION error { return unimplemented(sqllex, "drop conversion") }
-| DROP DOMAIN error { return unimplementedWithIssueDetail(sqllex, 27796, "drop") }
 | DROP EXTENSION IF EXISTS name error { return unimplemented(sqllex, "drop extension " + $5) }
 | DROP EXTENSION name error { return unimplemented(sqllex, "drop extension " + $3) }
-| DROP FOREIGN TABLE error { return unimplemented(sqllex, "drop foreign table") }
 | DROP FOREIGN DATA error { return unimplemented(sqllex, "drop fdw") }
-| DROP FUNCTION error { return unimplementedWithIssueDetail(sqllex, 17511, "drop function") }
 | DROP opt_procedural LANGUAGE name error { return unimplementedWithIssueDetail(sqllex, 17511, "drop language " + $4) }
 | DROP OPERATOR error { return unimplemented(sqllex, "drop operator") }
-| DROP PUBLICATION error { return unimplemented(sqllex, "drop publication") }
 | DROP RULE error { return unimplemented(sqllex, "drop rule") }
-| DROP SERVER error { return unimplemented(sqllex, "drop server") }
-| DROP SUBSCRIPTION error { return unimplemented(sqllex, "drop subscription") }
 | DROP TEXT error { return unimplementedWithIssueDetail(sqllex, 7821, "drop text") }
 | DROP TRIGGER error { return unimplementedWithIssueDetail(sqllex, 28296, "drop") }
 
@@ -3548,8 +3791,15 @@ create_ddl_stmt:
 // Error case for both CREATE TABLE and CREATE TABLE ... AS in one
 | CREATE opt_persistence_temp_table TABLE error   // SHOW HELP: CREATE TABLE
 | create_type_stmt     // EXTEND WITH HELP: CREATE TYPE
+| create_domain_stmt   // EXTEND WITH HELP: CREATE DOMAIN
 | create_view_stmt     // EXTEND WITH HELP: CREATE VIEW
 | create_sequence_stmt // EXTEND WITH HELP: CREATE SEQUENCE
+| create_func_stmt     // EXTEND WITH HELP: CREATE FUNCTION
+| create_proc_stmt     // EXTEND WITH HELP: CREATE PROCEDURE
+| create_publication_stmt // EXTEND WITH HELP: CREATE PUBLICATION
+| create_subscription_stmt // EXTEND WITH HELP: CREATE SUBSCRIPTION
+| create_server_stmt   // EXTEND WITH HELP: CREATE SERVER
+| create_foreign_table_stmt // EXTEND WITH HELP: CREATE FOREIGN TABLE
 
 // %Help: CREATE STATISTICS - create a new table statistic
 // %Category: Misc
@@ -3764,7 +4014,7 @@ replication_options:
 
 // %Help: DELETE - delete rows from a table
 // %Category: DML
-// %Text: DELETE FROM <tablename> [WHERE <expr>]
+// %Text: DELETE FROM <tablename> [USING <tablerefs...>] [WHERE <expr>]
 //               [ORDER BY <exprs...>]
 //               [LIMIT <expr>]
 //               [RETURNING <exprs...>]
@@ -3775,6 +4025,7 @@ delete_stmt:
     $$.val = &tree.Delete{
       With: $1.with(),
       Table: $4.tblExpr(),
+      Using: $5.tblExprs(),
       Where: tree.NewWhere(tree.AstWhere, $6.expr()),
       OrderBy: $7.orderBy(),
       Limit: $8.limit(),
@@ -3784,8 +4035,14 @@ delete_stmt:
 | opt_with_clause DELETE error // SHOW HELP: DELETE
 
 opt_using_clause:
-  USING from_list { return unimplementedWithIssueDetail(sqllex, 40963, "delete using") }
-| /* EMPTY */ { }
+  USING from_list
+  {
+    $$.val = $2.tblExprs()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.TableExprs{}
+  }
 
 
 // %Help: DISCARD - reset the session to its initial state
@@ -3822,6 +4079,12 @@ drop_ddl_stmt:
 | drop_sequence_stmt // EXTEND WITH HELP: DROP SEQUENCE
 | drop_schema_stmt   // EXTEND WITH HELP: DROP SCHEMA
 | drop_type_stmt     // EXTEND WITH HELP: DROP TYPE
+| drop_domain_stmt   // EXTEND WITH HELP: DROP DOMAIN
+| drop_func_stmt     // EXTEND WITH HELP: DROP FUNCTION
+| drop_proc_stmt     // EXTEND WITH HELP: DROP PROCEDURE
+| drop_publication_stmt // EXTEND WITH HELP: DROP PUBLICATION
+| drop_subscription_stmt // EXTEND WITH HELP: DROP SUBSCRIPTION
+| drop_server_stmt   // EXTEND WITH HELP: DROP SERVER
 
 // %Help: DROP VIEW - remove a view
 // %Category: DDL
@@ -3873,7 +4136,7 @@ drop_sequence_stmt:
 
 // %Help: DROP TABLE - remove a table
 // %Category: DDL
-// %Text: DROP TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
+// %Text: DROP [FOREIGN] TABLE [IF EXISTS] <tablename> [, ...] [CASCADE | RESTRICT]
 // %SeeAlso: WEBDOCS/drop-table.html
 drop_table_stmt:
   DROP TABLE table_name_list opt_drop_behavior
@@ -3884,7 +4147,16 @@ drop_table_stmt:
   {
     $$.val = &tree.DropTable{Names: $5.tableNames(), IfExists: true, DropBehavior: $6.dropBehavior()}
   }
+| DROP FOREIGN TABLE table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTable{Names: $4.tableNames(), IfExists: false, DropBehavior: $5.dropBehavior(), IsForeign: true}
+  }
+| DROP FOREIGN TABLE IF EXISTS table_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropTable{Names: $6.tableNames(), IfExists: true, DropBehavior: $7.dropBehavior(), IsForeign: true}
+  }
 | DROP TABLE error // SHOW HELP: DROP TABLE
+| DROP FOREIGN TABLE error // SHOW HELP: DROP TABLE
 
 // %Help: DROP INDEX - remove an index
 // %Category: DDL
@@ -3934,6 +4206,88 @@ drop_database_stmt:
   }
 | DROP DATABASE error // SHOW HELP: DROP DATABASE
 
+// %Help: DROP FUNCTION - remove a function
+// %Category: DDL
+// %Text:
+// DROP FUNCTION [IF EXISTS] <func_name> [ ( [ [<argname>] <argtype> [, ...] ] ) ] [, ...]
+//   [CASCADE | RESTRICT]
+// %SeeAlso: CREATE FUNCTION, WEBDOCS/drop-function.html
+drop_func_stmt:
+  DROP FUNCTION function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP FUNCTION IF EXISTS function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP FUNCTION error // SHOW HELP: DROP FUNCTION
+
+// %Help: DROP PROCEDURE - remove a procedure
+// %Category: DDL
+// %Text:
+// DROP PROCEDURE [IF EXISTS] <proc_name> [ ( [ [<argmode>] [<argname>] <argtype> [, ...] ] ) ] [, ...]
+//   [CASCADE | RESTRICT]
+// %SeeAlso: CREATE PROCEDURE, CALL
+drop_proc_stmt:
+  DROP PROCEDURE function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $3.funcObjs(),
+      IfExists: false,
+      IsProcedure: true,
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP PROCEDURE IF EXISTS function_with_argtypes_list opt_drop_behavior
+  {
+    $$.val = &tree.DropFunction{
+      Functions: $5.funcObjs(),
+      IfExists: true,
+      IsProcedure: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP PROCEDURE error // SHOW HELP: DROP PROCEDURE
+
+function_with_argtypes_list:
+  function_with_argtypes
+  {
+    $$.val = tree.FuncObjs{$1.funcObj()}
+  }
+| function_with_argtypes_list ',' function_with_argtypes
+  {
+    $$.val = append($1.funcObjs(), $3.funcObj())
+  }
+
+function_with_argtypes:
+  db_object_name func_args
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName(), Args: $2.funcArgs()}
+  }
+| db_object_name
+  {
+    $$.val = tree.FuncObj{FuncName: $1.unresolvedObjectName()}
+  }
+
+func_args:
+  '(' func_arg_list ')'
+  {
+    $$.val = $2.funcArgs()
+  }
+| '(' ')'
+  {
+    $$.val = tree.FuncArgs{}
+  }
+
 // %Help: DROP TYPE - remove a type
 // %Category: DDL
 // %Text: DROP TYPE [IF EXISTS] <type_name> [, ...] [CASCASE | RESTRICT]
@@ -3956,6 +4310,30 @@ drop_type_stmt:
   }
 | DROP TYPE error // SHOW HELP: DROP TYPE
 
+// %Help: DROP DOMAIN - remove a domain
+// %Category: DDL
+// %Text: DROP DOMAIN [IF EXISTS] <type_name> [, ...] [CASCADE | RESTRICT]
+drop_domain_stmt:
+  DROP DOMAIN type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $3.unresolvedObjectNames(),
+      IfExists: false,
+      DropBehavior: $4.dropBehavior(),
+      IsDomain: true,
+    }
+  }
+| DROP DOMAIN IF EXISTS type_name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropType{
+      Names: $5.unresolvedObjectNames(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+      IsDomain: true,
+    }
+  }
+| DROP DOMAIN error // SHOW HELP: DROP DOMAIN
+
 target_types:
   type_name_list
   {
@@ -4151,6 +4529,7 @@ preparable_stmt:
 | truncate_stmt     // EXTEND WITH HELP: TRUNCATE
 | update_stmt       // EXTEND WITH HELP: UPDATE
 | upsert_stmt       // EXTEND WITH HELP: UPSERT
+| merge_stmt        // EXTEND WITH HELP: MERGE
 
 // These are statements that can be used as a data source using the special
 // syntax with brackets. These are a subset of preparable_stmt.
@@ -4478,7 +4857,7 @@ use_stmt:
 nonpreparable_set_stmt:
   set_transaction_stmt // EXTEND WITH HELP: SET TRANSACTION
 | set_exprs_internal   { /* SKIP DOC */ }
-| SET CONSTRAINTS error { return unimplemented(sqllex, "set constraints") }
+| set_constraints_stmt // EXTEND WITH HELP: SET CONSTRAINTS
 
 // SET SESSION / SET LOCAL / SET CLUSTER SETTING
 preparable_set_stmt:
@@ -4618,7 +4997,7 @@ set_exprs_internal:
 // %Text:
 // SET [SESSION] <var> { TO | = } <values...>
 // SET [SESSION] TIME ZONE <tz>
-// SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+// SET [SESSION] CHARACTERISTICS AS TRANSACTION ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 // SET [SESSION] TRACING { TO | = } { on | off | cluster | kv | results } [,...]
 //
 // %SeeAlso: SHOW SESSION, RESET, DISCARD, SHOW, SET CLUSTER SETTING, SET TRANSACTION, SET LOCAL
@@ -4663,7 +5042,7 @@ set_local_stmt:
 // SET [SESSION] TRANSACTION <txnparameters...>
 //
 // Transaction parameters:
-//    ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+//    ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 //    PRIORITY { LOW | NORMAL | HIGH }
 //    AS OF SYSTEM TIME <expr>
 //    [NOT] DEFERRABLE
@@ -4682,6 +5061,31 @@ set_transaction_stmt:
   }
 | SET SESSION TRANSACTION error // SHOW HELP: SET TRANSACTION
 
+// %Help: SET CONSTRAINTS - set the checking mode of deferrable constraints
+// %Category: Txn
+// %Text:
+// SET CONSTRAINTS { ALL | <name> [, ...] } { DEFERRED | IMMEDIATE }
+//
+// The mode applies to the DEFERRABLE constraints with the given names until
+// the end of the current transaction. The checks of deferred constraints are
+// performed when the transaction commits.
+//
+// %SeeAlso: SET TRANSACTION, CREATE TABLE, ALTER TABLE
+set_constraints_stmt:
+  SET CONSTRAINTS ALL constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{All: true, Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS name_list constraints_set_mode
+  {
+    $$.val = &tree.SetConstraints{Names: $3.nameList(), Deferred: $4.bool()}
+  }
+| SET CONSTRAINTS error // SHOW HELP: SET CONSTRAINTS
+
+constraints_set_mode:
+  DEFERRED  { $$.val = true }
+| IMMEDIATE { $$.val = false }
+
 generic_set:
   var_name to_or_eq var_list
   {
@@ -4811,11 +5215,11 @@ var_list:
 iso_level:
   READ UNCOMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | READ COMMITTED
   {
-    $$.val = tree.SerializableIsolation
+    $$.val = tree.ReadCommittedIsolation
   }
 | SNAPSHOT
   {
@@ -4924,87 +5328,348 @@ show_stmt:
 | show_full_scans_stmt
 | show_default_privileges_stmt // EXTEND WITH HELP: SHOW DEFAULT PRIVILEGES
 
-// Cursors are not yet supported by CockroachDB. CLOSE ALL is safe to no-op
-// since there will be no open cursors.
-close_cursor_stmt:
-	CLOSE ALL { }
-| CLOSE cursor_name { return unimplementedWithIssue(sqllex, 41412) }
-
-declare_cursor_stmt:
-	DECLARE { return unimplementedWithIssue(sqllex, 41412) }
+// %Help: LISTEN - listen for notifications on a channel
+// %Category: Misc
+// %Text: LISTEN <channel>
+// %SeeAlso: NOTIFY, UNLISTEN
+listen_stmt:
+  LISTEN name
+  {
+    $$.val = &tree.Listen{ChannelName: tree.Name($2)}
+  }
+| LISTEN error // SHOW HELP: LISTEN
 
-reindex_stmt:
-  REINDEX TABLE error
+// %Help: NOTIFY - send a notification on a channel
+// %Category: Misc
+// %Text: NOTIFY <channel> [, <payload>]
+// %SeeAlso: LISTEN, UNLISTEN
+notify_stmt:
+  NOTIFY name
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex table", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Notify{ChannelName: tree.Name($2)}
   }
-| REINDEX INDEX error
+| NOTIFY name ',' SCONST
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex index", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Notify{ChannelName: tree.Name($2), Payload: $4}
   }
-| REINDEX SCHEMA error
+| NOTIFY error // SHOW HELP: NOTIFY
+
+// %Help: UNLISTEN - stop listening for notifications
+// %Category: Misc
+// %Text: UNLISTEN { <channel> | * }
+// %SeeAlso: LISTEN, NOTIFY
+unlisten_stmt:
+  UNLISTEN name
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex schema", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Unlisten{ChannelName: tree.Name($2)}
   }
-| REINDEX DATABASE error
+| UNLISTEN '*'
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex database", "CockroachDB does not require reindexing.")
+    $$.val = &tree.Unlisten{Star: true}
   }
-| REINDEX SYSTEM error
+| UNLISTEN error // SHOW HELP: UNLISTEN
+
+// %Help: CLOSE - close a cursor
+// %Category: Misc
+// %Text: CLOSE { <name> | ALL }
+// %SeeAlso: DECLARE, FETCH, MOVE
+close_cursor_stmt:
+  CLOSE ALL
   {
-    /* SKIP DOC */
-    return purposelyUnimplemented(sqllex, "reindex system", "CockroachDB does not require reindexing.")
+    $$.val = &tree.CloseCursor{
+      All: true,
+    }
+  }
+| CLOSE cursor_name
+  {
+    $$.val = &tree.CloseCursor{
+      Name: tree.Name($2),
+    }
   }
+| CLOSE error // SHOW HELP: CLOSE
 
-// %Help: SHOW SESSION - display session variables
-// %Category: Cfg
-// %Text: SHOW [SESSION] { <var> | ALL }
-// %SeeAlso: WEBDOCS/show-vars.html
-show_session_stmt:
-  SHOW session_var         { $$.val = &tree.ShowVar{Name: $2} }
-| SHOW SESSION session_var { $$.val = &tree.ShowVar{Name: $3} }
-| SHOW SESSION error // SHOW HELP: SHOW SESSION
+// %Help: DECLARE - declare a cursor
+// %Category: Misc
+// %Text: DECLARE <name> [ BINARY ] [ ASENSITIVE | INSENSITIVE ] [ [ NO ] SCROLL ]
+//        CURSOR [ { WITH | WITHOUT } HOLD ] FOR <query>
+// %SeeAlso: CLOSE, FETCH, MOVE
+declare_cursor_stmt:
+  // Postgres accepts the cursor options in any order; only the canonical
+  // order is accepted here.
+  DECLARE cursor_name opt_binary opt_sensitivity opt_scroll CURSOR opt_hold FOR select_stmt
+  {
+    $$.val = &tree.DeclareCursor{
+      Binary: $3.bool(),
+      Name: tree.Name($2),
+      Sensitivity: $4.cursorSensitivity(),
+      Scroll: $5.cursorScrollOption(),
+      Hold: $7.bool(),
+      Select: $9.slct(),
+    }
+  }
+| DECLARE error // SHOW HELP: DECLARE
 
-session_var:
-  IDENT
-| IDENT session_var_parts
+opt_binary:
+  BINARY
   {
-    $$ = $1 + "." + strings.Join($2.strs(), ".")
+    $$.val = true
+  }
+| /* EMPTY */
+  {
+    $$.val = false
   }
-// Although ALL, SESSION_USER, DATABASE, LC_COLLATE, and LC_CTYPE are
-// identifiers for the purpose of SHOW, they lex as separate token types, so
-// they need separate rules.
-| ALL
-| DATABASE
-// SET NAMES is standard SQL for SET client_encoding.
-// See https://www.postgresql.org/docs/9.6/static/multibyte.html#AEN39236
-| NAMES { $$ = "client_encoding" }
-| ROLE
-| SESSION_USER
-| LC_COLLATE
-| LC_CTYPE
-// TIME ZONE is special: it is two tokens, but is really the identifier "TIME ZONE".
-| TIME ZONE { $$ = "timezone" }
-| TIME error // SHOW HELP: SHOW SESSION
 
-session_var_parts:
-  '.' IDENT
+opt_sensitivity:
+  INSENSITIVE
   {
-    $$.val = []string{$2}
+    $$.val = tree.Insensitive
   }
-| session_var_parts '.' IDENT
+| ASENSITIVE
   {
-    $$.val = append($1.strs(), $3)
+    $$.val = tree.Asensitive
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedSensitivity
   }
 
-// %Help: SHOW STATISTICS - display table statistics (experimental)
-// %Category: Experimental
-// %Text: SHOW STATISTICS [USING JSON] FOR TABLE <table_name>
-//
+opt_scroll:
+  SCROLL
+  {
+    $$.val = tree.Scroll
+  }
+| NO SCROLL
+  {
+    $$.val = tree.NoScroll
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.UnspecifiedScroll
+  }
+
+opt_hold:
+  WITH HOLD
+  {
+    $$.val = true
+  }
+| WITHOUT HOLD
+  {
+    $$.val = false
+  }
+| /* EMPTY */
+  {
+    $$.val = false
+  }
+
+// %Help: FETCH - fetch rows from a cursor
+// %Category: Misc
+// %Text: FETCH [ direction [ FROM | IN ] ] <name>
+//
+// direction can be one of:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>, <count>,
+//   ALL, FORWARD, FORWARD <count>, FORWARD ALL, BACKWARD, BACKWARD <count>,
+//   BACKWARD ALL
+//
+// %SeeAlso: CLOSE, DECLARE, MOVE
+fetch_cursor_stmt:
+  FETCH cursor_movement_specifier
+  {
+    $$.val = &tree.FetchCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| FETCH error // SHOW HELP: FETCH
+
+// %Help: MOVE - move a cursor without returning rows
+// %Category: Misc
+// %Text: MOVE [ direction [ FROM | IN ] ] <name>
+//
+// direction can be one of:
+//   NEXT, PRIOR, FIRST, LAST, ABSOLUTE <count>, RELATIVE <count>, <count>,
+//   ALL, FORWARD, FORWARD <count>, FORWARD ALL, BACKWARD, BACKWARD <count>,
+//   BACKWARD ALL
+//
+// %SeeAlso: CLOSE, DECLARE, FETCH
+move_cursor_stmt:
+  MOVE cursor_movement_specifier
+  {
+    $$.val = &tree.MoveCursor{
+      CursorStmt: $2.cursorStmt(),
+    }
+  }
+| MOVE error // SHOW HELP: MOVE
+
+cursor_movement_specifier:
+  cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($1),
+      Count: 1,
+    }
+  }
+| from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($2),
+      Count: 1,
+    }
+  }
+| next_prior opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| forward_backward opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      Count: $1.int64(),
+    }
+  }
+| opt_forward_backward signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      Count: $2.int64() * $1.int64(),
+    }
+  }
+| opt_forward_backward ALL opt_from_or_in cursor_name
+  {
+    fetchType := tree.FetchAll
+    count := $1.int64()
+    if count < 0 {
+      fetchType = tree.FetchBackwardAll
+    }
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: fetchType,
+    }
+  }
+| ABSOLUTE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchAbsolute,
+      Count: $2.int64(),
+    }
+  }
+| RELATIVE signed_iconst64 opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($4),
+      FetchType: tree.FetchRelative,
+      Count: $2.int64(),
+    }
+  }
+| FIRST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchFirst,
+    }
+  }
+| LAST opt_from_or_in cursor_name
+  {
+    $$.val = tree.CursorStmt{
+      Name: tree.Name($3),
+      FetchType: tree.FetchLast,
+    }
+  }
+
+next_prior:
+  NEXT  { $$.val = int64(1) }
+| PRIOR { $$.val = int64(-1) }
+
+opt_forward_backward:
+  forward_backward { $$.val = $1.int64() }
+| /* EMPTY */ { $$.val = int64(1) }
+
+forward_backward:
+  FORWARD  { $$.val = int64(1) }
+| BACKWARD { $$.val = int64(-1) }
+
+opt_from_or_in:
+  from_or_in { }
+| /* EMPTY */ { }
+
+from_or_in:
+  FROM { }
+| IN { }
+
+reindex_stmt:
+  REINDEX TABLE error
+  {
+    /* SKIP DOC */
+    return purposelyUnimplemented(sqllex, "reindex table", "CockroachDB does not require reindexing.")
+  }
+| REINDEX INDEX error
+  {
+    /* SKIP DOC */
+    return purposelyUnimplemented(sqllex, "reindex index", "CockroachDB does not require reindexing.")
+  }
+| REINDEX SCHEMA error
+  {
+    /* SKIP DOC */
+    return purposelyUnimplemented(sqllex, "reindex schema", "CockroachDB does not require reindexing.")
+  }
+| REINDEX DATABASE error
+  {
+    /* SKIP DOC */
+    return purposelyUnimplemented(sqllex, "reindex database", "CockroachDB does not require reindexing.")
+  }
+| REINDEX SYSTEM error
+  {
+    /* SKIP DOC */
+    return purposelyUnimplemented(sqllex, "reindex system", "CockroachDB does not require reindexing.")
+  }
+
+// %Help: SHOW SESSION - display session variables
+// %Category: Cfg
+// %Text: SHOW [SESSION] { <var> | ALL }
+// %SeeAlso: WEBDOCS/show-vars.html
+show_session_stmt:
+  SHOW session_var         { $$.val = &tree.ShowVar{Name: $2} }
+| SHOW SESSION session_var { $$.val = &tree.ShowVar{Name: $3} }
+| SHOW SESSION error // SHOW HELP: SHOW SESSION
+
+session_var:
+  IDENT
+| IDENT session_var_parts
+  {
+    $$ = $1 + "." + strings.Join($2.strs(), ".")
+  }
+// Although ALL, SESSION_USER, DATABASE, LC_COLLATE, and LC_CTYPE are
+// identifiers for the purpose of SHOW, they lex as separate token types, so
+// they need separate rules.
+| ALL
+| DATABASE
+// SET NAMES is standard SQL for SET client_encoding.
+// See https://www.postgresql.org/docs/9.6/static/multibyte.html#AEN39236
+| NAMES { $$ = "client_encoding" }
+| ROLE
+| SESSION_USER
+| LC_COLLATE
+| LC_CTYPE
+// TIME ZONE is special: it is two tokens, but is really the identifier "TIME ZONE".
+| TIME ZONE { $$ = "timezone" }
+| TIME error // SHOW HELP: SHOW SESSION
+
+session_var_parts:
+  '.' IDENT
+  {
+    $$.val = []string{$2}
+  }
+| session_var_parts '.' IDENT
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
+// %Help: SHOW STATISTICS - display table statistics (experimental)
+// %Category: Experimental
+// %Text: SHOW STATISTICS [USING JSON] FOR TABLE <table_name>
+//
 // Returns the available statistics for a table.
 // The statistics can include a histogram ID, which can
 // be used with SHOW HISTOGRAM.
@@ -5638,10 +6303,10 @@ show_transaction_stmt:
   }
 | SHOW TRANSACTION error // SHOW HELP: SHOW TRANSACTION
 
-// %Help: SHOW CREATE - display the CREATE statement for a table, sequence, view, or database
+// %Help: SHOW CREATE - display the CREATE statement for a table, sequence, view, database, or function
 // %Category: DDL
 // %Text:
-// SHOW CREATE [ TABLE | SEQUENCE | VIEW | DATABASE ] <object_name>
+// SHOW CREATE [ TABLE | SEQUENCE | VIEW | DATABASE | FUNCTION ] <object_name>
 // SHOW CREATE ALL SCHEMAS
 // SHOW CREATE ALL TABLES
 // SHOW CREATE ALL TYPES
@@ -5671,6 +6336,11 @@ show_create_stmt:
     /* SKIP DOC */
     $$.val = &tree.ShowCreate{Mode: tree.ShowCreateModeDatabase, Name: $4.unresolvedObjectName()}
 	}
+| SHOW CREATE FUNCTION db_object_name
+  {
+    /* SKIP DOC */
+    $$.val = &tree.ShowCreateFunction{Name: $4.unresolvedObjectName()}
+  }
 | SHOW CREATE ALL SCHEMAS
   {
     $$.val = &tree.ShowCreateAllSchemas{}
@@ -6473,6 +7143,20 @@ storage_parameter_list:
     $$.val = append($1.storageParams(), $3.storageParam())
   }
 
+storage_parameter_key:
+  name
+| SCONST
+
+storage_parameter_key_list:
+  storage_parameter_key
+  {
+    $$.val = []string{$1}
+  }
+| storage_parameter_key_list ',' storage_parameter_key
+  {
+    $$.val = append($1.strs(), $3)
+  }
+
 create_table_as_stmt:
   CREATE opt_persistence_temp_table TABLE table_name create_as_opt_col_list opt_table_with AS select_stmt opt_create_as_data opt_create_table_on_commit
   {
@@ -6974,6 +7658,9 @@ table_constraint:
 constraint_elem:
   CHECK '(' a_expr ')' opt_deferrable
   {
+    if $5.constraintDeferrability() != tree.NotDeferrableConstraint {
+      return setErr(sqllex, pgerror.New(pgcode.FeatureNotSupported, "CHECK constraints cannot be marked DEFERRABLE"))
+    }
     $$.val = &tree.CheckConstraintTableDef{
       Expr: $3.expr(),
     }
@@ -6989,6 +7676,7 @@ constraint_elem:
         PartitionByIndex: $7.partitionByIndex(),
         Predicate: $9.expr(),
       },
+      Deferrable: $8.constraintDeferrability(),
     }
   }
 | PRIMARY KEY '(' index_params ')' opt_hash_sharded
@@ -7011,6 +7699,7 @@ constraint_elem:
       ToCols: $8.nameList(),
       Match: $9.compositeKeyMatchMethod(),
       Actions: $10.referenceActions(),
+      Deferrable: $11.constraintDeferrability(),
     }
   }
 | EXCLUDE USING error
@@ -7121,12 +7810,12 @@ create_as_col_qualification_elem:
   }
 
 opt_deferrable:
-  /* EMPTY */ { /* no error */ }
-| DEFERRABLE { return unimplementedWithIssueDetail(sqllex, 31632, "deferrable") }
-| DEFERRABLE INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "def initially deferred") }
-| DEFERRABLE INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "def initially immediate") }
-| INITIALLY DEFERRED { return unimplementedWithIssueDetail(sqllex, 31632, "initially deferred") }
-| INITIALLY IMMEDIATE { return unimplementedWithIssueDetail(sqllex, 31632, "initially immediate") }
+  /* EMPTY */ { $$.val = tree.NotDeferrableConstraint }
+| DEFERRABLE { $$.val = tree.DeferrableInitiallyImmediate }
+| DEFERRABLE INITIALLY DEFERRED { $$.val = tree.DeferrableInitiallyDeferred }
+| DEFERRABLE INITIALLY IMMEDIATE { $$.val = tree.DeferrableInitiallyImmediate }
+| INITIALLY DEFERRED { $$.val = tree.DeferrableInitiallyDeferred }
+| INITIALLY IMMEDIATE { $$.val = tree.NotDeferrableConstraint }
 
 storing:
   COVERING
@@ -7527,122 +8216,572 @@ create_view_stmt:
       IfNotExists: true,
     }
   }
-| CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+| CREATE opt_temp opt_view_recursive VIEW error // SHOW HELP: CREATE VIEW
+
+role_option:
+  CREATEROLE
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCREATEROLE
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| LOGIN
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOLOGIN
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| CONTROLJOB
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCONTROLJOB
+  {
+   $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| CONTROLCHANGEFEED
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCONTROLCHANGEFEED
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| CREATEDB
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCREATEDB
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| CREATELOGIN
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCREATELOGIN
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| VIEWACTIVITY
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOVIEWACTIVITY
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| CANCELQUERY
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOCANCELQUERY
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| MODIFYCLUSTERSETTING
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| NOMODIFYCLUSTERSETTING
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+  }
+| password_clause
+| valid_until_clause
+
+role_options:
+  role_option
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| role_options role_option
+  {
+    $$.val = append($1.kvOptions(), $2.kvOption())
+  }
+
+opt_role_options:
+  opt_with role_options
+  {
+    $$.val = $2.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+valid_until_clause:
+  VALID UNTIL string_or_placeholder
+  {
+    $$.val = tree.KVOption{Key: tree.Name("valid until"), Value: $3.expr()}
+  }
+| VALID UNTIL NULL
+  {
+    $$.val = tree.KVOption{Key: tree.Name("valid until"), Value: tree.DNull}
+  }
+
+opt_view_recursive:
+  /* EMPTY */ { /* no error */ }
+| RECURSIVE { return unimplemented(sqllex, "create recursive view") }
+
+
+// %Help: CREATE PUBLICATION - define a new publication
+// %Category: DDL
+// %Text:
+// CREATE PUBLICATION <name>
+//   [ FOR TABLE <tablename> [, ...] | FOR ALL TABLES ]
+//   [ WITH ( <option> [= <value>] [, ...] ) ]
+//
+// Options:
+//   publish = '<operation>[, ...]' (insert, update, delete, truncate)
+// %SeeAlso: ALTER PUBLICATION, DROP PUBLICATION
+create_publication_stmt:
+  CREATE PUBLICATION name opt_publication_for_tables opt_publication_options
+  {
+    stmt := $4.stmt().(*tree.CreatePublication)
+    stmt.Name = tree.Name($3)
+    stmt.Options = $5.kvOptions()
+    $$.val = stmt
+  }
+| CREATE PUBLICATION error // SHOW HELP: CREATE PUBLICATION
+
+opt_publication_for_tables:
+  FOR TABLE table_name_list
+  {
+    $$.val = &tree.CreatePublication{Tables: $3.tableNames()}
+  }
+| FOR ALL TABLES
+  {
+    $$.val = &tree.CreatePublication{AllTables: true}
+  }
+| /* EMPTY */
+  {
+    $$.val = &tree.CreatePublication{}
+  }
+
+opt_publication_options:
+  WITH '(' kv_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+// %Help: ALTER PUBLICATION - change the definition of a publication
+// %Category: DDL
+// %Text:
+// ALTER PUBLICATION <name> ADD TABLE <tablename> [, ...]
+// ALTER PUBLICATION <name> DROP TABLE <tablename> [, ...]
+// ALTER PUBLICATION <name> SET TABLE <tablename> [, ...]
+// ALTER PUBLICATION <name> SET ( <option> [= <value>] [, ...] )
+// %SeeAlso: CREATE PUBLICATION, DROP PUBLICATION
+alter_publication_stmt:
+  ALTER PUBLICATION name ADD TABLE table_name_list
+  {
+    $$.val = &tree.AlterPublication{
+      Name: tree.Name($3),
+      Action: tree.AlterPublicationAddTables,
+      Tables: $6.tableNames(),
+    }
+  }
+| ALTER PUBLICATION name DROP TABLE table_name_list
+  {
+    $$.val = &tree.AlterPublication{
+      Name: tree.Name($3),
+      Action: tree.AlterPublicationDropTables,
+      Tables: $6.tableNames(),
+    }
+  }
+| ALTER PUBLICATION name SET TABLE table_name_list
+  {
+    $$.val = &tree.AlterPublication{
+      Name: tree.Name($3),
+      Action: tree.AlterPublicationSetTables,
+      Tables: $6.tableNames(),
+    }
+  }
+| ALTER PUBLICATION name SET '(' kv_option_list ')'
+  {
+    $$.val = &tree.AlterPublication{
+      Name: tree.Name($3),
+      Action: tree.AlterPublicationSetOptions,
+      Options: $6.kvOptions(),
+    }
+  }
+| ALTER PUBLICATION name RENAME error { return unimplemented(sqllex, "alter publication rename") }
+| ALTER PUBLICATION name OWNER error { return unimplemented(sqllex, "alter publication owner") }
+| ALTER PUBLICATION error // SHOW HELP: ALTER PUBLICATION
+
+// %Help: DROP PUBLICATION - remove a publication
+// %Category: DDL
+// %Text: DROP PUBLICATION [IF EXISTS] <name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE PUBLICATION, ALTER PUBLICATION
+drop_publication_stmt:
+  DROP PUBLICATION name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropPublication{
+      Names: $3.nameList(),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP PUBLICATION IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropPublication{
+      Names: $5.nameList(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP PUBLICATION error // SHOW HELP: DROP PUBLICATION
+
+// %Help: CREATE SUBSCRIPTION - define a new subscription
+// %Category: DDL
+// %Text:
+// CREATE SUBSCRIPTION <name>
+//   CONNECTION '<conninfo>'
+//   PUBLICATION <publication> [, ...]
+//   [ WITH ( <option> [= <value>] [, ...] ) ]
+//
+// Options:
+//   copy_data = 'true' | 'false'
+//   create_slot = 'true' | 'false'
+//   slot_name = '<slot>'
+// %SeeAlso: DROP SUBSCRIPTION, CREATE PUBLICATION
+create_subscription_stmt:
+  CREATE SUBSCRIPTION name CONNECTION SCONST PUBLICATION name_list opt_publication_options
+  {
+    $$.val = &tree.CreateSubscription{
+      Name: tree.Name($3),
+      Connection: tree.NewStrVal($5),
+      Publications: $7.nameList(),
+      Options: $8.kvOptions(),
+    }
+  }
+| CREATE SUBSCRIPTION error // SHOW HELP: CREATE SUBSCRIPTION
+
+// %Help: DROP SUBSCRIPTION - remove a subscription
+// %Category: DDL
+// %Text: DROP SUBSCRIPTION [IF EXISTS] <name> [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SUBSCRIPTION
+drop_subscription_stmt:
+  DROP SUBSCRIPTION name opt_drop_behavior
+  {
+    $$.val = &tree.DropSubscription{
+      Name: tree.Name($3),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP SUBSCRIPTION IF EXISTS name opt_drop_behavior
+  {
+    $$.val = &tree.DropSubscription{
+      Name: tree.Name($5),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP SUBSCRIPTION error // SHOW HELP: DROP SUBSCRIPTION
+
+// %Help: CREATE SERVER - define a new foreign server
+// %Category: DDL
+// %Text:
+// CREATE SERVER [IF NOT EXISTS] <name> FOREIGN DATA WRAPPER <wrapper>
+//   [ OPTIONS ( <option> '<value>' [, ...] ) ]
+//
+// Wrappers:
+//   cloud_storage  Files in external storage.
+//
+// Options:
+//   location  The base URI of the files of the foreign tables.
+// %SeeAlso: CREATE FOREIGN TABLE, DROP SERVER
+create_server_stmt:
+  CREATE SERVER name FOREIGN DATA WRAPPER name opt_foreign_options
+  {
+    $$.val = &tree.CreateServer{
+      Name: tree.Name($3),
+      Wrapper: tree.Name($7),
+      Options: $8.kvOptions(),
+    }
+  }
+| CREATE SERVER IF NOT EXISTS name FOREIGN DATA WRAPPER name opt_foreign_options
+  {
+    $$.val = &tree.CreateServer{
+      Name: tree.Name($6),
+      IfNotExists: true,
+      Wrapper: tree.Name($10),
+      Options: $11.kvOptions(),
+    }
+  }
+| CREATE SERVER error // SHOW HELP: CREATE SERVER
+
+// %Help: DROP SERVER - remove a foreign server
+// %Category: DDL
+// %Text: DROP SERVER [IF EXISTS] <name> [, ...] [CASCADE | RESTRICT]
+// %SeeAlso: CREATE SERVER
+drop_server_stmt:
+  DROP SERVER name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropServer{
+      Names: $3.nameList(),
+      DropBehavior: $4.dropBehavior(),
+    }
+  }
+| DROP SERVER IF EXISTS name_list opt_drop_behavior
+  {
+    $$.val = &tree.DropServer{
+      Names: $5.nameList(),
+      IfExists: true,
+      DropBehavior: $6.dropBehavior(),
+    }
+  }
+| DROP SERVER error // SHOW HELP: DROP SERVER
+
+// %Help: CREATE FOREIGN TABLE - define a table over external data
+// %Category: DDL
+// %Text:
+// CREATE FOREIGN TABLE [IF NOT EXISTS] <tablename> ( <colname> <type> [NULL | NOT NULL] [, ...] )
+//   SERVER <servername> [ OPTIONS ( <option> '<value>' [, ...] ) ]
+//
+// Options:
+//   location     The path of the file, or of the directory or glob pattern
+//                matching the files, relative to the location of the server.
+//   format       The format of the files: csv, delimited, avro or parquet.
+//   compression  The compression of the files: auto, none, gzip or bzip.
+//   The options of IMPORT for the format of the files are also accepted.
+// %SeeAlso: CREATE SERVER, DROP TABLE
+create_foreign_table_stmt:
+  CREATE FOREIGN TABLE table_name '(' opt_table_elem_list ')' SERVER name opt_foreign_options
+  {
+    $$.val = &tree.CreateForeignTable{
+      Table: $4.unresolvedObjectName().ToTableName(),
+      Defs: $6.tblDefs(),
+      Server: tree.Name($9),
+      Options: $10.kvOptions(),
+    }
+  }
+| CREATE FOREIGN TABLE IF NOT EXISTS table_name '(' opt_table_elem_list ')' SERVER name opt_foreign_options
+  {
+    $$.val = &tree.CreateForeignTable{
+      IfNotExists: true,
+      Table: $7.unresolvedObjectName().ToTableName(),
+      Defs: $9.tblDefs(),
+      Server: tree.Name($12),
+      Options: $13.kvOptions(),
+    }
+  }
+| CREATE FOREIGN TABLE error // SHOW HELP: CREATE FOREIGN TABLE
+
+opt_foreign_options:
+  OPTIONS '(' foreign_option_list ')'
+  {
+    $$.val = $3.kvOptions()
+  }
+| /* EMPTY */
+  {
+    $$.val = nil
+  }
+
+foreign_option_list:
+  foreign_option
+  {
+    $$.val = []tree.KVOption{$1.kvOption()}
+  }
+| foreign_option_list ',' foreign_option
+  {
+    $$.val = append($1.kvOptions(), $3.kvOption())
+  }
+
+foreign_option:
+  name SCONST
+  {
+    $$.val = tree.KVOption{Key: tree.Name($1), Value: tree.NewStrVal($2)}
+  }
 
-role_option:
-  CREATEROLE
+// %Help: CREATE FUNCTION - define a new function
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] FUNCTION <func_name> ( [ [<argname>] <argtype> [, ...] ] )
+//   RETURNS <rettype>
+//   { LANGUAGE SQL
+//   | { IMMUTABLE | STABLE | VOLATILE }
+//   | [NOT] LEAKPROOF
+//   | { CALLED ON NULL INPUT | RETURNS NULL ON NULL INPUT | STRICT }
+//   | AS '<definition>'
+//   } ...
+// %SeeAlso: DROP FUNCTION, SHOW CREATE, WEBDOCS/create-function.html
+create_func_stmt:
+  CREATE opt_or_replace FUNCTION db_object_name '(' opt_func_arg_list ')' RETURNS typename opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      Replace: $2.bool(),
+      FuncName: $4.unresolvedObjectName(),
+      Args: $6.funcArgs(),
+      ReturnType: $9.typeReference(),
+      Options: $10.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace FUNCTION error // SHOW HELP: CREATE FUNCTION
+
+opt_func_arg_list:
+  func_arg_list
+| /* EMPTY */
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgs(nil)
   }
-| NOCREATEROLE
+
+func_arg_list:
+  func_arg
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgs{$1.funcArg()}
   }
-| LOGIN
+| func_arg_list ',' func_arg
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = append($1.funcArgs(), $3.funcArg())
   }
-| NOLOGIN
+
+func_arg:
+  type_function_name typename
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArg{Name: tree.Name($1), Type: $2.typeReference()}
   }
-| CONTROLJOB
+| typename
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArg{Type: $1.typeReference()}
   }
-| NOCONTROLJOB
+| func_arg_class type_function_name typename
   {
-   $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArg{Name: tree.Name($2), Type: $3.typeReference(), Class: $1.funcArgClass()}
   }
-| CONTROLCHANGEFEED
+| func_arg_class typename
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArg{Type: $2.typeReference(), Class: $1.funcArgClass()}
   }
-| NOCONTROLCHANGEFEED
+
+func_arg_class:
+  IN
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgIn
   }
-| CREATEDB
+| OUT
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgOut
   }
-| NOCREATEDB
+| INOUT
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgInOut
   }
-| CREATELOGIN
+| IN OUT
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FuncArgInOut
   }
-| NOCREATELOGIN
+
+opt_create_func_opt_list:
+  create_func_opt_list
+| /* EMPTY */
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionOptions(nil)
   }
-| VIEWACTIVITY
+
+create_func_opt_list:
+  create_func_opt_item
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionOptions{$1.functionOption()}
   }
-| NOVIEWACTIVITY
+| create_func_opt_list create_func_opt_item
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = append($1.functionOptions(), $2.functionOption())
   }
-| CANCELQUERY
+
+create_func_opt_item:
+  AS SCONST
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionBodyStr($2)
   }
-| NOCANCELQUERY
+| LANGUAGE non_reserved_word_or_sconst
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionLanguage($2)
   }
-| MODIFYCLUSTERSETTING
+| common_func_opt_item
+
+common_func_opt_item:
+  CALLED ON NULL INPUT
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionCalledOnNullInput
   }
-| NOMODIFYCLUSTERSETTING
+| RETURNS NULL ON NULL INPUT
   {
-    $$.val = tree.KVOption{Key: tree.Name($1), Value: nil}
+    $$.val = tree.FunctionReturnsNullOnNullInput
   }
-| password_clause
-| valid_until_clause
-
-role_options:
-  role_option
+| STRICT
   {
-    $$.val = []tree.KVOption{$1.kvOption()}
+    $$.val = tree.FunctionStrict
   }
-| role_options role_option
+| IMMUTABLE
   {
-    $$.val = append($1.kvOptions(), $2.kvOption())
+    $$.val = tree.FunctionImmutable
   }
-
-opt_role_options:
-  opt_with role_options
+| STABLE
   {
-    $$.val = $2.kvOptions()
+    $$.val = tree.FunctionStable
   }
-| /* EMPTY */
+| VOLATILE
   {
-    $$.val = nil
+    $$.val = tree.FunctionVolatile
   }
-
-valid_until_clause:
-  VALID UNTIL string_or_placeholder
+| LEAKPROOF
   {
-    $$.val = tree.KVOption{Key: tree.Name("valid until"), Value: $3.expr()}
+    $$.val = tree.FunctionLeakproof(true)
   }
-| VALID UNTIL NULL
+| NOT LEAKPROOF
   {
-    $$.val = tree.KVOption{Key: tree.Name("valid until"), Value: tree.DNull}
+    $$.val = tree.FunctionLeakproof(false)
   }
 
-opt_view_recursive:
-  /* EMPTY */ { /* no error */ }
-| RECURSIVE { return unimplemented(sqllex, "create recursive view") }
+// %Help: CREATE PROCEDURE - define a new procedure
+// %Category: DDL
+// %Text:
+// CREATE [OR REPLACE] PROCEDURE <proc_name> ( [ [<argmode>] [<argname>] <argtype> [, ...] ] )
+//   { LANGUAGE SQL
+//   | AS '<definition>'
+//   } ...
+//
+// Argument modes:
+//   IN | OUT | INOUT
+// %SeeAlso: CALL, DROP PROCEDURE
+create_proc_stmt:
+  CREATE opt_or_replace PROCEDURE db_object_name '(' opt_func_arg_list ')' opt_create_func_opt_list
+  {
+    $$.val = &tree.CreateFunction{
+      Replace: $2.bool(),
+      IsProcedure: true,
+      FuncName: $4.unresolvedObjectName(),
+      Args: $6.funcArgs(),
+      Options: $8.functionOptions(),
+    }
+  }
+| CREATE opt_or_replace PROCEDURE error // SHOW HELP: CREATE PROCEDURE
 
+// %Help: CALL - invoke a procedure
+// %Category: Misc
+// %Text: CALL <proc_name> ( [ <argument> [, ...] ] )
+// %SeeAlso: CREATE PROCEDURE
+call_stmt:
+  CALL func_application
+  {
+    $$.val = &tree.Call{Proc: $2.expr().(*tree.FuncExpr)}
+  }
+| CALL error // SHOW HELP: CALL
 
 // %Help: CREATE TYPE -- create a type
 // %Category: DDL
-// %Text: CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// %Text:
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS ENUM (...)
+// CREATE TYPE [IF NOT EXISTS] <type_name> AS ( [<attribute_name> <type> [, ...]] )
 create_type_stmt:
   // Enum types.
   CREATE TYPE type_name AS ENUM '(' opt_enum_val_list ')'
@@ -7662,17 +8801,196 @@ create_type_stmt:
       IfNotExists: true,
     }
   }
+  // Composite types.
+| CREATE TYPE type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $6.compositeTypeList(),
+    }
+  }
+| CREATE TYPE IF NOT EXISTS type_name AS '(' opt_composite_type_list ')'
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $6.unresolvedObjectName(),
+      Variety: tree.Composite,
+      CompositeTypeList: $9.compositeTypeList(),
+      IfNotExists: true,
+    }
+  }
 | CREATE TYPE error // SHOW HELP: CREATE TYPE
-  // Record/Composite types.
-| CREATE TYPE type_name AS '(' error      { return unimplementedWithIssue(sqllex, 27792) }
   // Range types.
 | CREATE TYPE type_name AS RANGE error    { return unimplementedWithIssue(sqllex, 27791) }
   // Base (primitive) types.
 | CREATE TYPE type_name '(' error         { return unimplementedWithIssueDetail(sqllex, 27793, "base") }
   // Shell types, gateway to define base types using the previous syntax.
 | CREATE TYPE type_name                   { return unimplementedWithIssueDetail(sqllex, 27793, "shell") }
-  // Domain types.
-| CREATE DOMAIN type_name error           { return unimplementedWithIssueDetail(sqllex, 27796, "create") }
+
+// %Help: CREATE DOMAIN -- create a domain
+// %Category: DDL
+// %Text:
+// CREATE DOMAIN [IF NOT EXISTS] <type_name> [AS] <type> [<constraint> ...]
+//
+// Constraints:
+//   [CONSTRAINT <name>] CHECK (<expr>)
+//   [CONSTRAINT <name>] NOT NULL
+//   [CONSTRAINT <name>] NULL
+//
+// The value being checked is referred to as VALUE in check expressions.
+// %SeeAlso: ALTER DOMAIN, DROP DOMAIN
+create_domain_stmt:
+  CREATE DOMAIN type_name opt_as typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $3.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainBaseType: $5.typeReference(),
+      DomainConstraints: $6.domainConstraintList(),
+    }
+  }
+| CREATE DOMAIN IF NOT EXISTS type_name opt_as typename opt_domain_constraint_list
+  {
+    $$.val = &tree.CreateType{
+      TypeName: $6.unresolvedObjectName(),
+      Variety: tree.Domain,
+      DomainBaseType: $8.typeReference(),
+      DomainConstraints: $9.domainConstraintList(),
+      IfNotExists: true,
+    }
+  }
+| CREATE DOMAIN error // SHOW HELP: CREATE DOMAIN
+
+opt_composite_type_list:
+  composite_type_list
+| /* EMPTY */
+  {
+    $$.val = []tree.CompositeTypeElem{}
+  }
+
+composite_type_list:
+  name typename
+  {
+    $$.val = []tree.CompositeTypeElem{
+      {
+        Label: tree.Name($1),
+        Type: $2.typeReference(),
+      },
+    }
+  }
+| composite_type_list ',' name typename
+  {
+    $$.val = append($1.compositeTypeList(),
+      tree.CompositeTypeElem{
+        Label: tree.Name($3),
+        Type: $4.typeReference(),
+      },
+    )
+  }
+
+opt_as:
+  AS {}
+| /* EMPTY */ {}
+
+opt_domain_constraint_list:
+  domain_constraint_list
+| /* EMPTY */
+  {
+    $$.val = tree.DomainConstraintList(nil)
+  }
+
+domain_constraint_list:
+  domain_constraint
+  {
+    $$.val = tree.DomainConstraintList{$1.domainConstraint()}
+  }
+| domain_constraint_list domain_constraint
+  {
+    $$.val = append($1.domainConstraintList(), $2.domainConstraint())
+  }
+
+domain_constraint:
+  CONSTRAINT constraint_name domain_constraint_elem
+  {
+    c := $3.domainConstraint()
+    c.Name = tree.Name($2)
+    $$.val = c
+  }
+| domain_constraint_elem
+
+domain_constraint_elem:
+  CHECK '(' a_expr ')'
+  {
+    $$.val = tree.DomainConstraint{Check: $3.expr()}
+  }
+| NOT NULL
+  {
+    $$.val = tree.DomainConstraint{NotNull: true}
+  }
+| NULL
+  {
+    $$.val = tree.DomainConstraint{}
+  }
+| DEFAULT error { return unimplementedWithIssueDetail(sqllex, 27796, "default") }
+| COLLATE error { return unimplementedWithIssueDetail(sqllex, 27796, "collate") }
+
+// %Help: ALTER DOMAIN - change the definition of a domain
+// %Category: DDL
+// %Text: ALTER DOMAIN <type_name> <command>
+//
+// Commands:
+//   ALTER DOMAIN ... ADD [CONSTRAINT <name>] CHECK (<expr>)
+//   ALTER DOMAIN ... DROP CONSTRAINT [IF EXISTS] <name> [CASCADE | RESTRICT]
+//   ALTER DOMAIN ... { SET | DROP } NOT NULL
+// %SeeAlso: CREATE DOMAIN, DROP DOMAIN
+alter_domain_stmt:
+  ALTER DOMAIN type_name ADD domain_constraint
+  {
+    c := $5.domainConstraint()
+    if c.Check == nil {
+      return unimplementedWithIssueDetail(sqllex, 27796, "alter domain add not null")
+    }
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainAddConstraint{Constraint: c},
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        Constraint: tree.Name($6),
+        DropBehavior: $7.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name DROP CONSTRAINT IF EXISTS constraint_name opt_drop_behavior
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropConstraint{
+        IfExists: true,
+        Constraint: tree.Name($8),
+        DropBehavior: $9.dropBehavior(),
+      },
+    }
+  }
+| ALTER DOMAIN type_name SET NOT NULL
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainSetNotNull{},
+    }
+  }
+| ALTER DOMAIN type_name DROP NOT NULL
+  {
+    $$.val = &tree.AlterDomain{
+      Domain: $3.unresolvedObjectName(),
+      Cmd: &tree.AlterDomainDropNotNull{},
+    }
+  }
+| ALTER DOMAIN error // SHOW HELP: ALTER DOMAIN
 
 opt_enum_val_list:
   enum_val_list
@@ -7857,8 +9175,8 @@ index_elem_options:
     opClass := $1
     dir := $2.dir()
     nullsOrder := $3.nullsOrder()
-    if opClass != "" {
-      if opClass == "gin_trgm_ops" || opClass == "gist_trgm_ops" {
+    if opClass != "" && opClass != "gin_trgm_ops" {
+      if opClass == "gist_trgm_ops" {
         return unimplementedWithIssueDetail(sqllex, 41285, "index using " + opClass)
       }
       return unimplementedWithIssue(sqllex, 47420)
@@ -7872,7 +9190,7 @@ index_elem_options:
         return unimplementedWithIssue(sqllex, 6224)
       }
     }
-    $$.val = tree.IndexElem{Direction: dir, NullsOrder: nullsOrder}
+    $$.val = tree.IndexElem{OpClass: tree.Name(opClass), Direction: dir, NullsOrder: nullsOrder}
   }
 
 opt_class:
@@ -8449,10 +9767,13 @@ savepoint_stmt:
 
 // BEGIN / START / COMMIT / END / ROLLBACK / ...
 transaction_stmt:
-  begin_stmt    // EXTEND WITH HELP: BEGIN
-| commit_stmt   // EXTEND WITH HELP: COMMIT
-| rollback_stmt // EXTEND WITH HELP: ROLLBACK
-| abort_stmt    /* SKIP DOC */
+  begin_stmt               // EXTEND WITH HELP: BEGIN
+| commit_stmt              // EXTEND WITH HELP: COMMIT
+| rollback_stmt            // EXTEND WITH HELP: ROLLBACK
+| abort_stmt               /* SKIP DOC */
+| prepare_transaction_stmt // EXTEND WITH HELP: PREPARE TRANSACTION
+| commit_prepared_stmt     // EXTEND WITH HELP: COMMIT PREPARED
+| rollback_prepared_stmt   // EXTEND WITH HELP: ROLLBACK PREPARED
 
 // %Help: BEGIN - start a transaction
 // %Category: Txn
@@ -8461,7 +9782,7 @@ transaction_stmt:
 // START TRANSACTION [ <txnparameter> [[,] ...] ]
 //
 // Transaction parameters:
-//    ISOLATION LEVEL { SNAPSHOT | SERIALIZABLE }
+//    ISOLATION LEVEL { READ COMMITTED | SNAPSHOT | SERIALIZABLE }
 //    PRIORITY { LOW | NORMAL | HIGH }
 //
 // %SeeAlso: COMMIT, ROLLBACK, WEBDOCS/begin-transaction.html
@@ -8527,6 +9848,39 @@ opt_transaction:
   TRANSACTION {}
 | /* EMPTY */ {}
 
+// %Help: PREPARE TRANSACTION - prepare the current transaction for two-phase commit
+// %Category: Txn
+// %Text: PREPARE TRANSACTION <transaction id>
+// %SeeAlso: COMMIT PREPARED, ROLLBACK PREPARED
+prepare_transaction_stmt:
+  PREPARE TRANSACTION SCONST
+  {
+    $$.val = &tree.PrepareTransaction{Transaction: tree.NewStrVal($3)}
+  }
+| PREPARE TRANSACTION error // SHOW HELP: PREPARE TRANSACTION
+
+// %Help: COMMIT PREPARED - commit a transaction that was earlier prepared for two-phase commit
+// %Category: Txn
+// %Text: COMMIT PREPARED <transaction id>
+// %SeeAlso: PREPARE TRANSACTION, ROLLBACK PREPARED
+commit_prepared_stmt:
+  COMMIT PREPARED SCONST
+  {
+    $$.val = &tree.CommitPrepared{Transaction: tree.NewStrVal($3)}
+  }
+| COMMIT PREPARED error // SHOW HELP: COMMIT PREPARED
+
+// %Help: ROLLBACK PREPARED - cancel a transaction that was earlier prepared for two-phase commit
+// %Category: Txn
+// %Text: ROLLBACK PREPARED <transaction id>
+// %SeeAlso: PREPARE TRANSACTION, COMMIT PREPARED
+rollback_prepared_stmt:
+  ROLLBACK PREPARED SCONST
+  {
+    $$.val = &tree.RollbackPrepared{Transaction: tree.NewStrVal($3)}
+  }
+| ROLLBACK PREPARED error // SHOW HELP: ROLLBACK PREPARED
+
 savepoint_name:
   SAVEPOINT name
   {
@@ -9008,6 +10362,91 @@ multiple_set_clause:
     $$.val = &tree.UpdateExpr{Tuple: true, Names: $2.nameList(), Expr: $5.expr()}
   }
 
+// %Help: MERGE - conditionally insert, update or delete rows of a table
+// %Category: DML
+// %Text:
+// MERGE INTO <tablename> [[AS] <name>]
+//        USING <source> ON <expr>
+//        WHEN MATCHED [AND <expr>] THEN { UPDATE SET ... | DELETE | DO NOTHING }
+//        WHEN NOT MATCHED [AND <expr>] THEN
+//          { INSERT [( <colnames...> )] { VALUES ( <exprs...> ) | DEFAULT VALUES } | DO NOTHING }
+//        [...]
+// %SeeAlso: INSERT, UPSERT, UPDATE, DELETE
+merge_stmt:
+  opt_with_clause MERGE INTO table_expr_opt_alias_idx USING table_ref ON a_expr merge_when_list
+  {
+    $$.val = &tree.Merge{
+      With: $1.with(),
+      Table: $4.tblExpr(),
+      Source: $6.tblExpr(),
+      On: $8.expr(),
+      Whens: $9.mergeWhens(),
+    }
+  }
+| opt_with_clause MERGE error // SHOW HELP: MERGE
+
+merge_when_list:
+  merge_when_clause
+  {
+    $$.val = tree.MergeWhens{$1.mergeWhen()}
+  }
+| merge_when_list merge_when_clause
+  {
+    $$.val = append($1.mergeWhens(), $2.mergeWhen())
+  }
+
+merge_when_clause:
+  WHEN MATCHED opt_merge_when_condition THEN merge_matched_action
+  {
+    $$.val = &tree.MergeWhen{Matched: true, Cond: $3.expr(), Action: $5.mergeAction()}
+  }
+| WHEN NOT MATCHED opt_merge_when_condition THEN merge_not_matched_action
+  {
+    $$.val = &tree.MergeWhen{Cond: $4.expr(), Action: $6.mergeAction()}
+  }
+
+opt_merge_when_condition:
+  AND a_expr
+  {
+    $$.val = $2.expr()
+  }
+| /* EMPTY */
+  {
+    $$.val = tree.Expr(nil)
+  }
+
+merge_matched_action:
+  UPDATE SET set_clause_list
+  {
+    $$.val = &tree.MergeUpdate{Exprs: $3.updateExprs()}
+  }
+| DELETE
+  {
+    $$.val = &tree.MergeDelete{}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeDoNothing{}
+  }
+
+merge_not_matched_action:
+  INSERT VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeInsert{Exprs: $4.exprs()}
+  }
+| INSERT '(' insert_column_list ')' VALUES '(' expr_list ')'
+  {
+    $$.val = &tree.MergeInsert{Columns: $3.nameList(), Exprs: $7.exprs()}
+  }
+| INSERT DEFAULT VALUES
+  {
+    $$.val = &tree.MergeInsert{}
+  }
+| DO NOTHING
+  {
+    $$.val = &tree.MergeDoNothing{}
+  }
+
 // %Help: REASSIGN OWNED BY - change ownership of all objects
 // %Category: Priv
 // %Text: REASSIGN OWNED BY {<name> | CURRENT_USER | SESSION_USER}[,...]
@@ -9651,9 +11090,18 @@ group_by_list:
 // rather than reducing the conflicting unreserved_keyword rule.
 group_by_item:
   a_expr { $$.val = $1.expr() }
-| ROLLUP '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "rollup") }
-| CUBE '(' error { return unimplementedWithIssueDetail(sqllex, 46280, "cube") }
-| GROUPING SETS error { return unimplementedWithIssueDetail(sqllex, 46280, "grouping sets") }
+| ROLLUP '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.RollupGroupingSet, Exprs: $3.exprs()}
+  }
+| CUBE '(' expr_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.CubeGroupingSet, Exprs: $3.exprs()}
+  }
+| GROUPING SETS '(' group_by_list ')'
+  {
+    $$.val = &tree.GroupingSet{Type: tree.GroupingSetsGroupingSet, Exprs: $4.exprs()}
+  }
 
 having_clause:
   HAVING a_expr
@@ -11009,6 +12457,22 @@ a_expr:
   {
     $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.RShift), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr DISTANCE a_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.Distance), Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr OVERLEFT a_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.OverLeft), Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr OVERRIGHT a_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.OverRight), Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr ADJACENT a_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.Adjacent), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr FETCHVAL a_expr
   {
     $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.JSONFetchVal), Left: $1.expr(), Right: $3.expr()}
@@ -11037,6 +12501,14 @@ a_expr:
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.MakeComparisonOperator(tree.Overlaps), Left: $1.expr(), Right: $3.expr()}
   }
+| a_expr AT_AT a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.MakeComparisonOperator(tree.TSMatches), Left: $1.expr(), Right: $3.expr()}
+  }
+| a_expr JSON_PATH_EXISTS a_expr
+  {
+    $$.val = &tree.ComparisonExpr{Operator: tree.MakeComparisonOperator(tree.JSONPathExists), Left: $1.expr(), Right: $3.expr()}
+  }
 | a_expr INET_CONTAINS_OR_EQUALS a_expr
   {
     $$.val = &tree.FuncExpr{Func: tree.WrapFunction("inet_contains_or_equals"), Exprs: tree.Exprs{$1.expr(), $3.expr()}}
@@ -11358,6 +12830,22 @@ b_expr:
   {
     $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.RShift), Left: $1.expr(), Right: $3.expr()}
   }
+| b_expr DISTANCE b_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.Distance), Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr OVERLEFT b_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.OverLeft), Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr OVERRIGHT b_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.OverRight), Left: $1.expr(), Right: $3.expr()}
+  }
+| b_expr ADJACENT b_expr
+  {
+    $$.val = &tree.BinaryExpr{Operator: tree.MakeBinaryOperator(tree.Adjacent), Left: $1.expr(), Right: $3.expr()}
+  }
 | b_expr LESS_EQUALS b_expr
   {
     $$.val = &tree.ComparisonExpr{Operator: tree.MakeComparisonOperator(tree.LE), Left: $1.expr(), Right: $3.expr()}
@@ -11560,7 +13048,11 @@ d_expr:
   {
     $$.val = $2.expr()
   }
-| GROUPING '(' expr_list ')' { return unimplemented(sqllex, "d_expr grouping") }
+| GROUPING '(' expr_list ')'
+  {
+    $$.val = &tree.FuncExpr{Func: tree.WrapFunction("grouping"), Exprs: $3.exprs()}
+  }
+| GROUPING '(' error { return helpWithFunctionByName(sqllex, "grouping") }
 
 func_application:
   func_name '(' ')'
@@ -12193,6 +13685,10 @@ all_op:
 | CONTAINED_BY { $$.val = tree.MakeComparisonOperator(tree.ContainedBy) }
 | LSHIFT { $$.val = tree.MakeBinaryOperator(tree.LShift) }
 | RSHIFT { $$.val = tree.MakeBinaryOperator(tree.RShift) }
+| DISTANCE { $$.val = tree.MakeBinaryOperator(tree.Distance) }
+| OVERLEFT { $$.val = tree.MakeBinaryOperator(tree.OverLeft) }
+| OVERRIGHT { $$.val = tree.MakeBinaryOperator(tree.OverRight) }
+| ADJACENT { $$.val = tree.MakeBinaryOperator(tree.Adjacent) }
 | CONCAT { $$.val = tree.MakeBinaryOperator(tree.Concat) }
 | FETCHVAL { $$.val = tree.MakeBinaryOperator(tree.JSONFetchVal) }
 | FETCHTEXT { $$.val = tree.MakeBinaryOperator(tree.JSONFetchText) }
@@ -12204,6 +13700,8 @@ all_op:
 | REGIMATCH { $$.val = tree.MakeComparisonOperator(tree.RegIMatch) }
 | NOT_REGIMATCH { $$.val = tree.MakeComparisonOperator(tree.NotRegIMatch) }
 | AND_AND { $$.val = tree.MakeComparisonOperator(tree.Overlaps) }
+| AT_AT { $$.val = tree.MakeComparisonOperator(tree.TSMatches) }
+| JSON_PATH_EXISTS { $$.val = tree.MakeComparisonOperator(tree.JSONPathExists) }
 | '~' { $$.val = tree.MakeUnaryOperator(tree.UnaryComplement) }
 | SQRT { $$.val = tree.MakeUnaryOperator(tree.UnarySqrt) }
 | CBRT { $$.val = tree.MakeUnaryOperator(tree.UnaryCbrt) }
@@ -12578,6 +14076,13 @@ target_elem:
   {
     $$.val = tree.SelectExpr{Expr: $1.expr(), As: tree.UnrestrictedName($2)}
   }
+  // A few unreserved keywords which were previously plain identifiers are
+  // also accepted as column labels without AS, so that existing queries keep
+  // working.
+| a_expr bare_col_label
+  {
+    $$.val = tree.SelectExpr{Expr: $1.expr(), As: tree.UnrestrictedName($2)}
+  }
 | a_expr
   {
     $$.val = tree.SelectExpr{Expr: $1.expr()}
@@ -13089,6 +14594,11 @@ unrestricted_name:
 | type_func_name_keyword
 | reserved_keyword
 
+// Unreserved keywords which may be used as column labels without AS. See
+// target_elem.
+bare_col_label:
+  INPUT
+
 // Keyword category lists. Generally, every keyword present in the Postgres
 // grammar should appear in exactly one of these lists.
 //
@@ -13099,6 +14609,7 @@ unrestricted_name:
 // "Unreserved" keywords --- available for use as any kind of name.
 unreserved_keyword:
   ABORT
+| ABSOLUTE
 | ACTION
 | ACCESS
 | ADD
@@ -13107,12 +14618,14 @@ unreserved_keyword:
 | AGGREGATE
 | ALTER
 | ALWAYS
+| ASENSITIVE
 | AT
 | ATTRIBUTE
 | AUTOMATIC
 | AVAILABILITY
 | BACKUP
 | BACKUPS
+| BACKWARD
 | BEFORE
 | BEGIN
 | BINARY
@@ -13120,6 +14633,8 @@ unreserved_keyword:
 | BUNDLE
 | BY
 | CACHE
+| CALL
+| CALLED
 | CANCEL
 | CANCELQUERY
 | CASCADE
@@ -13196,6 +14711,7 @@ unreserved_keyword:
 | FORCE
 | FORCE_INDEX
 | FORCE_ZIGZAG
+| FORWARD
 | FUNCTION
 | FUNCTIONS
 | GENERATED
@@ -13211,11 +14727,14 @@ unreserved_keyword:
 | GRANTS
 | GROUPS
 | HASH
+| HEADER
 | HIGH
 | HISTOGRAM
+| HOLD
 | HOUR
 | IDENTITY
 | IMMEDIATE
+| IMMUTABLE
 | IMPORT
 | INCLUDE
 | INCLUDING
@@ -13224,6 +14743,8 @@ unreserved_keyword:
 | INDEXES
 | INHERITS
 | INJECT
+| INPUT
+| INSENSITIVE
 | INSERT
 | INTO_DB
 | INVERTED
@@ -13240,6 +14761,7 @@ unreserved_keyword:
 | LATEST
 | LC_COLLATE
 | LC_CTYPE
+| LEAKPROOF
 | LEASE
 | LESS
 | LEVEL
@@ -13248,6 +14770,7 @@ unreserved_keyword:
 | LINESTRINGZ
 | LINESTRINGZM
 | LIST
+| LISTEN
 | LOCAL
 | LOCKED
 | LOGIN
@@ -13255,6 +14778,7 @@ unreserved_keyword:
 | LOOKUP
 | LOW
 | MATCH
+| MATCHED
 | MATERIALIZED
 | MAXVALUE
 | MERGE
@@ -13275,6 +14799,7 @@ unreserved_keyword:
 | MULTIPOLYGONZ
 | MULTIPOLYGONZM
 | MONTH
+| MOVE
 | NAMES
 | NAN
 | NEVER
@@ -13282,6 +14807,7 @@ unreserved_keyword:
 | NEXT
 | NO
 | NORMAL
+| NOTIFY
 | NO_INDEX_JOIN
 | NO_ZIGZAG_JOIN
 | NO_FULL_SCAN
@@ -13329,9 +14855,12 @@ unreserved_keyword:
 | POLYGONZM
 | PRECEDING
 | PREPARE
+| PREPARED
 | PRESERVE
+| PRIOR
 | PRIORITY
 | PRIVILEGES
+| PROCEDURE
 | PUBLIC
 | PUBLICATION
 | QUERIES
@@ -13349,6 +14878,7 @@ unreserved_keyword:
 | REGIONAL
 | REGIONS
 | REINDEX
+| RELATIVE
 | RELEASE
 | RENAME
 | REPEATABLE
@@ -13360,6 +14890,7 @@ unreserved_keyword:
 | RESTRICTED
 | RESUME
 | RETRY
+| RETURNS
 | REVISION_HISTORY
 | REVOKE
 | ROLE
@@ -13380,6 +14911,7 @@ unreserved_keyword:
 | SCATTER
 | SCHEMA
 | SCHEMAS
+| SCROLL
 | SCRUB
 | SEARCH
 | SECOND
@@ -13403,10 +14935,12 @@ unreserved_keyword:
 | SNAPSHOT
 | SPLIT
 | SQL
+| STABLE
 | START
 | STATEMENTS
 | STATISTICS
 | STDIN
+| STDOUT
 | STORAGE
 | STORE
 | STORED
@@ -13439,6 +14973,7 @@ unreserved_keyword:
 | UNBOUNDED
 | UNCOMMITTED
 | UNKNOWN
+| UNLISTEN
 | UNLOGGED
 | UNSPLIT
 | UNTIL
@@ -13453,9 +14988,11 @@ unreserved_keyword:
 | VIEW
 | VIEWACTIVITY
 | VISIBLE
+| VOLATILE
 | VOTERS
 | WITHIN
 | WITHOUT
+| WRAPPER
 | WRITE
 | YEAR
 | ZONE
@@ -13493,6 +15030,7 @@ col_name_keyword:
 | IF
 | IFERROR
 | IFNULL
+| INOUT
 | INT
 | INTEGER
 | INTERVAL
@@ -17,11 +17,15 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"sort"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkv"
@@ -29,10 +33,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catprivilege"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/funcdesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/typedesc"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -594,6 +600,7 @@ var (
 	relKindView             = tree.NewDString("v")
 	relKindMaterializedView = tree.NewDString("m")
 	relKindSequence         = tree.NewDString("S")
+	relKindForeignTable     = tree.NewDString("f")
 
 	relPersistencePermanent = tree.NewDString("p")
 	relPersistenceTemporary = tree.NewDString("t")
@@ -618,6 +625,9 @@ https://www.postgresql.org/docs/9.5/catalog-pg-class.html`,
 		} else if table.IsSequence() {
 			relKind = relKindSequence
 			relAm = oidZero
+		} else if table.IsForeignTable() {
+			relKind = relKindForeignTable
+			relAm = oidZero
 		}
 		relPersistence := relPersistencePermanent
 		if table.IsTemporary() {
@@ -828,6 +838,9 @@ func populateTableConstraints(
 		consrc := tree.DNull
 		conbin := tree.DNull
 		condef := tree.DNull
+		deferrability := con.Deferrability()
+		condeferrable := tree.MakeDBool(tree.DBool(deferrability != tree.NotDeferrableConstraint))
+		condeferred := tree.MakeDBool(tree.DBool(deferrability == tree.DeferrableInitiallyDeferred))
 
 		// Determine constraint kind-specific fields.
 		var err error
@@ -923,6 +936,10 @@ func populateTableConstraints(
 				}
 				f.WriteString(strings.Join(colNames, ", "))
 				f.WriteByte(')')
+				if d := con.UniqueWithoutIndexConstraint.Deferrability(); d != tree.NotDeferrableConstraint {
+					f.WriteByte(' ')
+					f.WriteString(d.String())
+				}
 				if con.UniqueWithoutIndexConstraint.Validity != descpb.ConstraintValidity_Validated {
 					f.WriteString(" NOT VALID")
 				}
@@ -964,8 +981,8 @@ func populateTableConstraints(
 			dNameOrNull(conName), // conname
 			namespaceOid,         // connamespace
 			contype,              // contype
-			tree.DBoolFalse,      // condeferrable
-			tree.DBoolFalse,      // condeferred
+			condeferrable,        // condeferrable
+			condeferred,          // condeferred
 			tree.MakeDBool(tree.DBool(!con.Unvalidated)), // convalidated
 			tblOid,         // conrelid
 			oidZero,        // contypid
@@ -1634,36 +1651,96 @@ https://www.postgresql.org/docs/9.5/catalog-pg-extension.html`,
 }
 
 var pgCatalogForeignDataWrapperTable = virtualSchemaTable{
-	comment: `foreign data wrappers (empty - feature does not exist)
+	comment: `foreign data wrappers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-data-wrapper.html`,
 	schema: vtable.PGCatalogForeignDataWrapper,
 	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign data wrappers are not supported.
-		return nil
+		// The only foreign data wrapper is the built-in one reading files from
+		// external storage.
+		h := makeOidHasher()
+		return addRow(
+			h.ForeignDataWrapperOid(cloudStorageWrapper), // oid
+			tree.NewDName(cloudStorageWrapper),           // fdwname
+			h.UserOid(security.RootUserName()),           // fdwowner
+			oidZero,                                      // fdwhandler
+			oidZero,                                      // fdwvalidator
+			tree.DNull,                                   // fdwacl
+			tree.DNull,                                   // fdwoptions
+		)
 	},
-	unimplemented: true,
 }
 
 var pgCatalogForeignServerTable = virtualSchemaTable{
-	comment: `foreign servers (empty - feature does not exist)
+	comment: `foreign servers
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-server.html`,
 	schema: vtable.PGCatalogForeignServer,
-	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign servers are not supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, true, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				return db.ForEachServer(func(name string, server descpb.DatabaseDescriptor_ServerInfo) error {
+					options, err := foreignOptionsArray(server.Options)
+					if err != nil {
+						return err
+					}
+					return addRow(
+						h.ForeignServerOid(db.GetID(), name),    // oid
+						tree.NewDName(name),                     // srvname
+						h.UserOid(server.OwnerProto.Decode()),   // srvowner
+						h.ForeignDataWrapperOid(server.Wrapper), // srvfdw
+						tree.DNull,                              // srvtype
+						tree.DNull,                              // srvversion
+						tree.DNull,                              // srvacl
+						options,                                 // srvoptions
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogForeignTableTable = virtualSchemaTable{
-	comment: `foreign tables (empty  - feature does not exist)
+	comment: `foreign tables
 https://www.postgresql.org/docs/9.5/catalog-pg-foreign-table.html`,
 	schema: vtable.PGCatalogForeignTable,
-	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// Foreign tables are not supported.
-		return nil
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables are not foreign tables */
+			func(db catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
+				if !table.IsForeignTable() {
+					return nil
+				}
+				foreign := table.GetForeignTable()
+				options, err := foreignOptionsArray(foreign.Options)
+				if err != nil {
+					return err
+				}
+				return addRow(
+					tableOid(table.GetID()),                        // ftrelid
+					h.ForeignServerOid(db.GetID(), foreign.Server), // ftserver
+					options, // ftoptions
+				)
+			})
 	},
-	unimplemented: true,
+}
+
+// foreignOptionsArray returns the options of a foreign server or table as an
+// array of key=value strings, sorted by key.
+func foreignOptionsArray(options map[string]string) (tree.Datum, error) {
+	if len(options) == 0 {
+		return tree.DNull, nil
+	}
+	keys := make([]string, 0, len(options))
+	for k := range options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	arr := tree.NewDArray(types.String)
+	for _, k := range keys {
+		if err := arr.Append(tree.NewDString(k + "=" + options[k])); err != nil {
+			return nil, err
+		}
+	}
+	return arr, nil
 }
 
 func makeZeroedOidVector(size int) (tree.Datum, error) {
@@ -2102,16 +2179,60 @@ var (
 	_ = proArgModeIn
 	_ = proArgModeOut
 	_ = proArgModeTable
+
+	// proLangSQL is the OID of the sql language in Postgres, which is the
+	// language of all user-defined functions.
+	proLangSQL = tree.NewDOid(14)
 )
 
 var pgCatalogPreparedXactsTable = virtualSchemaTable{
-	comment: `prepared transactions (empty - feature does not exist)
+	comment: `prepared transactions
 https://www.postgresql.org/docs/9.6/view-pg-prepared-xacts.html`,
 	schema: vtable.PGCatalogPreparedXacts,
-	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PreparedTransactionsTable) {
+			return nil
+		}
+		rows, err := p.extendedEvalCtx.ExecCfg.InternalExecutor.QueryBufferedEx(
+			ctx,
+			"select-prepared-xacts",
+			p.EvalContext().Txn,
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			`SELECT global_id, prepared, owner, database, transaction_id, transaction_key
+FROM system.public.prepared_transactions`,
+		)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			// The prepared time is NULL if it is not known whether the transaction
+			// was prepared, which its record tells. A transaction without a
+			// record did not acquire any locks and was prepared by its gateway.
+			prepared := row[1]
+			if prepared == tree.DNull && row[5] != tree.DNull {
+				txnID := row[4].(*tree.DUuid).UUID
+				key := roachpb.Key(tree.MustBeDBytes(row[5]))
+				rec, err := queryPreparedTxnRecord(ctx, p.ExecCfg(), txnID, key, false /* abortExpired */)
+				if err != nil {
+					return err
+				}
+				if rec.Status != roachpb.PREPARED {
+					continue
+				}
+				prepared = tree.MustMakeDTimestampTZ(rec.WriteTimestamp.GoTime(), time.Microsecond)
+			}
+			if err := addRow(
+				tree.DNull, // transaction
+				row[0],     // gid
+				prepared,   // prepared
+				tree.NewDName(string(tree.MustBeDString(row[2]))), // owner
+				tree.NewDName(string(tree.MustBeDString(row[3]))), // database
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 // pgCatalogPreparedStatementsTable implements the pg_prepared_statements table.
@@ -2174,7 +2295,7 @@ https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
 	schema: vtable.PGCatalogProc,
 	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
 		h := makeOidHasher()
-		return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+		err := forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
 			func(db catalog.DatabaseDescriptor) error {
 				nspOid := h.NamespaceOid(db.GetID(), pgCatalogName)
 				for _, name := range builtins.AllBuiltinNames {
@@ -2297,20 +2418,129 @@ https://www.postgresql.org/docs/9.5/catalog-pg-proc.html`,
 				}
 				return nil
 			})
+		if err != nil {
+			return err
+		}
+		return forEachFunctionDesc(ctx, p, dbContext,
+			func(db catalog.DatabaseDescriptor, scName string, fn catalog.FunctionDescriptor) error {
+				return addPgProcUDFRow(h, db, scName, fn, addRow)
+			})
 	},
 }
 
+// addPgProcUDFRow adds the row describing the given user-defined function to
+// pg_proc.
+func addPgProcUDFRow(
+	h oidHasher,
+	db catalog.DatabaseDescriptor,
+	scName string,
+	fn catalog.FunctionDescriptor,
+	addRow func(...tree.Datum) error,
+) error {
+	volatility, err := funcdesc.ToTreeVolatility(fn.GetVolatility(), fn.GetLeakProof())
+	if err != nil {
+		return err
+	}
+	provolatile, _ := volatility.ToPostgres()
+	dArgTypes := tree.NewDArray(types.Oid)
+	dArgNames := tree.NewDArray(types.String)
+	dArgModes := tree.NewDArray(types.String)
+	hasArgNames, hasArgModes := false, false
+	for _, arg := range fn.GetArgs() {
+		if err := dArgTypes.Append(tree.NewDOid(tree.DInt(arg.Type.Oid()))); err != nil {
+			return err
+		}
+		if err := dArgNames.Append(tree.NewDString(arg.Name)); err != nil {
+			return err
+		}
+		hasArgNames = hasArgNames || arg.Name != ""
+		mode := "i"
+		switch arg.Class {
+		case descpb.FunctionDescriptor_Argument_OUT:
+			mode = "o"
+		case descpb.FunctionDescriptor_Argument_INOUT:
+			mode = "b"
+		}
+		if err := dArgModes.Append(tree.NewDString(mode)); err != nil {
+			return err
+		}
+		hasArgModes = hasArgModes || mode != "i"
+	}
+	argNames := tree.DNull
+	if hasArgNames {
+		argNames = dArgNames
+	}
+	// As in Postgres, the output arguments of procedures are included in
+	// proargtypes, and the modes of the arguments are only listed if some
+	// argument is not an input argument.
+	allArgTypes, argModes := tree.DNull, tree.DNull
+	if hasArgModes {
+		allArgTypes, argModes = dArgTypes, dArgModes
+	}
+	retType := tree.NewDOid(tree.DInt(fn.GetReturnType().Oid()))
+	kind := "f"
+	if fn.GetIsProcedure() {
+		kind = "p"
+		if len(fn.GetReturnType().TupleContents()) == 0 {
+			retType = tree.NewDOid(tree.DInt(oid.T_void))
+		}
+	}
+	isStrict := fn.GetNullInputBehavior() != descpb.FunctionDescriptor_CALLED_ON_NULL_INPUT
+	return addRow(
+		tree.NewDOid(tree.DInt(funcdesc.FuncIDToOID(fn.GetID()))), // oid
+		tree.NewDName(fn.GetName()),                               // proname
+		h.NamespaceOid(db.GetID(), scName),                        // pronamespace
+		getOwnerOID(fn),                                           // proowner
+		proLangSQL,                                                // prolang
+		tree.DNull,                                                // procost
+		tree.DNull,                                                // prorows
+		oidZero,                                                   // provariadic
+		tree.DNull,                                                // protransform
+		tree.DBoolFalse,                                           // proisagg
+		tree.DBoolFalse,                                           // proiswindow
+		tree.DBoolFalse,                                           // prosecdef
+		tree.MakeDBool(tree.DBool(fn.GetLeakProof())), // proleakproof
+		tree.MakeDBool(tree.DBool(isStrict)),          // proisstrict
+		tree.DBoolFalse,                               // proretset
+		tree.NewDString(provolatile),                  // provolatile
+		tree.DNull,                                    // proparallel
+		tree.NewDInt(tree.DInt(len(fn.GetArgs()))),    // pronargs
+		tree.NewDInt(tree.DInt(0)),                    // pronargdefaults
+		retType,                                       // prorettype
+		tree.NewDOidVectorFromDArray(dArgTypes),       // proargtypes
+		allArgTypes,                                   // proallargtypes
+		argModes,                                      // proargmodes
+		argNames,                                      // proargnames
+		tree.DNull,                                    // proargdefaults
+		tree.DNull,                                    // protrftypes
+		tree.NewDString(fn.GetFunctionBody()),         // prosrc
+		tree.DNull,                                    // probin
+		tree.DNull,                                    // proconfig
+		tree.DNull,                                    // proacl
+		tree.NewDString(kind),                         // prokind
+		tree.DNull,                                    // prosupport
+	)
+}
+
 var pgCatalogRangeTable = virtualSchemaTable{
-	comment: `range types (empty - feature does not exist)
+	comment: `range types
 https://www.postgresql.org/docs/9.5/catalog-pg-range.html`,
 	schema: vtable.PGCatalogRange,
 	populate: func(_ context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		// We currently do not support any range types, so this table is empty.
-		// This table should be populated when any range types are added to
-		// oidToDatum (and therefore pg_type).
+		for _, typ := range types.Ranges {
+			if err := addRow(
+				tree.NewDOid(tree.DInt(typ.Oid())),                 // rngtypid
+				tree.NewDOid(tree.DInt(typ.RangeContents().Oid())), // rngsubtype
+				oidZero, // rngcollation
+				oidZero, // rngsubopc
+				oidZero, // rngcanonical
+				oidZero, // rngsubdiff
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogRewriteTable = virtualSchemaTable{
@@ -2695,9 +2925,7 @@ var (
 	typTypeRange     = tree.NewDString("r")
 
 	// Avoid unused warning for constants.
-	_ = typTypeDomain
 	_ = typTypePseudo
-	_ = typTypeRange
 
 	// See https://www.postgresql.org/docs/9.6/static/catalog-pg-type.html#CATALOG-TYPCATEGORY-TABLE.
 	typCategoryArray       = tree.NewDString("A")
@@ -2818,9 +3046,25 @@ func addPGTypeRow(
 		builtinPrefix = "enum_"
 		typType = typTypeEnum
 	}
+	if typ.Family() == types.RangeFamily {
+		typType = typTypeRange
+	}
+	if typ.IsComposite() {
+		builtinPrefix = "record_"
+		typType = typTypeComposite
+	}
 	if cat == typCategoryPseudo {
 		typType = typTypePseudo
 	}
+	typNotNull := tree.DBoolFalse
+	typBaseType := oidZero
+	if typ.IsDomain() {
+		typType = typTypeDomain
+		typBaseType = tree.NewDOid(tree.DInt(typ.DomainBaseType().Oid()))
+		if domainData := typ.TypeMeta.DomainData; domainData != nil && domainData.NotNull {
+			typNotNull = tree.DBoolTrue
+		}
+	}
 	typname := typ.PGName()
 
 	return addRow(
@@ -2850,8 +3094,8 @@ func addPGTypeRow(
 
 		tree.DNull,      // typalign
 		tree.DNull,      // typstorage
-		tree.DBoolFalse, // typnotnull
-		oidZero,         // typbasetype
+		typNotNull,      // typnotnull
+		typBaseType,     // typbasetype
 		negOneVal,       // typtypmod
 		zeroVal,         // typndims
 		typColl(typ, h), // typcollation
@@ -3095,12 +3339,26 @@ https://www.postgresql.org/docs/9.5/catalog-pg-shseclabel.html`,
 }
 
 var pgCatalogPublicationRelTable = virtualSchemaTable{
-	comment: "pg_publication_rel was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationRel,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables explicitly included in publications
+https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html`,
+	schema: vtable.PgCatalogPublicationRel,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables can't be published */
+			func(db catalog.DatabaseDescriptor, _ string, table catalog.TableDescriptor) error {
+				return db.ForEachPublication(func(name string, pub descpb.DatabaseDescriptor_PublicationInfo) error {
+					if pub.AllTables || !publicationHasTable(pub, table.GetID()) {
+						return nil
+					}
+					pubOid := h.PublicationOid(db.GetID(), name)
+					return addRow(
+						h.PublicationRelOid(pubOid, table.GetID()), // oid
+						pubOid,                  // prpubid
+						tableOid(table.GetID()), // prrelid
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogConfigTable = virtualSchemaTable{
@@ -3185,12 +3443,24 @@ var pgCatalogTimezoneNamesTable = virtualSchemaTable{
 }
 
 var pgCatalogPublicationTablesTable = virtualSchemaTable{
-	comment: "pg_publication_tables was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublicationTables,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `tables included in publications
+https://www.postgresql.org/docs/13/view-pg-publication-tables.html`,
+	schema: vtable.PgCatalogPublicationTables,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		return forEachTableDesc(ctx, p, dbContext, hideVirtual, /* virtual tables can't be published */
+			func(db catalog.DatabaseDescriptor, scName string, table catalog.TableDescriptor) error {
+				return db.ForEachPublication(func(name string, pub descpb.DatabaseDescriptor_PublicationInfo) error {
+					if !publicationIncludesTable(pub, table) {
+						return nil
+					}
+					return addRow(
+						tree.NewDName(name),            // pubname
+						tree.NewDName(scName),          // schemaname
+						tree.NewDName(table.GetName()), // tablename
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogUserMappingsTable = virtualSchemaTable{
@@ -3265,12 +3535,28 @@ https://www.postgresql.org/docs/13/view-pg-shadow.html`,
 }
 
 var pgCatalogPublicationTable = virtualSchemaTable{
-	comment: "pg_publication was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogPublication,
-	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
-		return nil
+	comment: `publications created for logical replication
+https://www.postgresql.org/docs/13/catalog-pg-publication.html`,
+	schema: vtable.PgCatalogPublication,
+	populate: func(ctx context.Context, p *planner, dbContext catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		h := makeOidHasher()
+		return forEachDatabaseDesc(ctx, p, dbContext, false, /* requiresPrivileges */
+			func(db catalog.DatabaseDescriptor) error {
+				return db.ForEachPublication(func(name string, pub descpb.DatabaseDescriptor_PublicationInfo) error {
+					return addRow(
+						tree.MakeDBool(tree.DBool(pub.PublishUpdate)),   // pubupdate
+						h.PublicationOid(db.GetID(), name),              // oid
+						tree.MakeDBool(tree.DBool(pub.AllTables)),       // puballtables
+						tree.MakeDBool(tree.DBool(pub.PublishDelete)),   // pubdelete
+						tree.MakeDBool(tree.DBool(pub.PublishInsert)),   // pubinsert
+						tree.NewDName(name),                             // pubname
+						h.UserOid(pub.OwnerProto.Decode()),              // pubowner
+						tree.MakeDBool(tree.DBool(pub.PublishTruncate)), // pubtruncate
+						tree.DBoolFalse,                                 // pubviaroot
+					)
+				})
+			})
 	},
-	unimplemented: true,
 }
 
 var pgCatalogGroupTable = virtualSchemaTable{
@@ -3283,12 +3569,28 @@ var pgCatalogGroupTable = virtualSchemaTable{
 }
 
 var pgCatalogCursorsTable = virtualSchemaTable{
-	comment: "pg_cursors was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogCursors,
+	comment: `open cursors
+https://www.postgresql.org/docs/current/view-pg-cursors.html`,
+	schema: vtable.PgCatalogCursors,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		for name, c := range p.sqlCursors.list() {
+			ts, err := tree.MakeDTimestampTZ(c.created, time.Microsecond)
+			if err != nil {
+				return err
+			}
+			if err := addRow(
+				tree.DBoolFalse,                        // is_scrollable
+				tree.NewDString(name),                  // name
+				tree.NewDString(c.statement),           // statement
+				ts,                                     // creation_time
+				tree.DBoolFalse,                        // is_binary
+				tree.MakeDBool(tree.DBool(c.withHold)), // is_holdable
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogTsParserTable = virtualSchemaTable{
@@ -3301,12 +3603,50 @@ var pgCatalogTsParserTable = virtualSchemaTable{
 }
 
 var pgCatalogSubscriptionTable = virtualSchemaTable{
-	comment: "pg_subscription was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogSubscription,
+	comment: `logical replication subscriptions
+https://www.postgresql.org/docs/13/catalog-pg-subscription.html`,
+	schema: vtable.PgCatalogSubscription,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		subs, err := getSubscriptions(ctx, p.ExecCfg(), p.txn)
+		if err != nil {
+			return err
+		}
+		// Like in PostgreSQL, the connection strings, which may contain
+		// passwords, are only visible to admins.
+		isAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return err
+		}
+		h := makeOidHasher()
+		for i := range subs {
+			sub := &subs[i]
+			pubs := tree.NewDArray(types.String)
+			for _, pub := range sub.details.Publications {
+				if err := pubs.Append(tree.NewDString(pub)); err != nil {
+					return err
+				}
+			}
+			connInfo := tree.DNull
+			if isAdmin {
+				connInfo = tree.NewDString(sub.details.Connection)
+			}
+			enabled := sub.status != jobs.StatusPaused && sub.status != jobs.StatusPauseRequested
+			if err := addRow(
+				tree.NewDName(sub.details.SubscriptionName), // subname
+				pubs,                                // subpublications
+				tree.NewDName(sub.details.SlotName), // subslotname
+				tree.NewDString("off"),              // subsynccommit
+				h.SubscriptionOid(sub.details.DatabaseID, sub.details.SubscriptionName), // oid
+				connInfo,                            // subconninfo
+				dbOid(sub.details.DatabaseID),       // subdbid
+				tree.MakeDBool(tree.DBool(enabled)), // subenabled
+				h.UserOid(sub.owner),                // subowner
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogAmprocTable = virtualSchemaTable{
@@ -3454,12 +3794,47 @@ var pgCatalogLargeobjectTable = virtualSchemaTable{
 }
 
 var pgCatalogReplicationSlotsTable = virtualSchemaTable{
-	comment: "pg_replication_slots was created for compatibility and is currently unimplemented",
-	schema:  vtable.PgCatalogReplicationSlots,
+	comment: `replication slots
+https://www.postgresql.org/docs/13/view-pg-replication-slots.html`,
+	schema: vtable.PgCatalogReplicationSlots,
 	populate: func(ctx context.Context, p *planner, _ catalog.DatabaseDescriptor, addRow func(...tree.Datum) error) error {
+		slots, err := getReplicationSlots(ctx, p.ExecCfg(), p.txn)
+		if err != nil {
+			return err
+		}
+		now := p.txn.ReadTimestamp()
+		for i := range slots {
+			slot := &slots[i]
+			dbName := tree.DNull
+			if _, db, err := p.Descriptors().GetImmutableDatabaseByID(
+				ctx, p.txn, slot.details.DatabaseID, tree.DatabaseLookupFlags{},
+			); err != nil {
+				return err
+			} else if db != nil {
+				dbName = tree.NewDName(db.GetName())
+			}
+			lsn := tree.NewDString(pgrepl.LSNFromTimestamp(slot.restartPoint()).String())
+			if err := addRow(
+				tree.DNull,                         // safe_wal_size
+				tree.NewDString("reserved"),        // wal_status
+				tree.NewDName(slot.details.Plugin), // plugin
+				lsn,                                // restart_lsn
+				tree.DNull,                         // xmin
+				lsn,                                // confirmed_flush_lsn
+				dbName,                             // database
+				dbOid(slot.details.DatabaseID),     // datoid
+				tree.MakeDBool(tree.DBool(slot.active(now))), // active
+				tree.DNull,                           // catalog_xmin
+				tree.NewDName(slot.details.SlotName), // slot_name
+				tree.DNull,                           // active_pid
+				tree.NewDString("logical"),           // slot_type
+				tree.DBoolFalse,                      // temporary
+			); err != nil {
+				return err
+			}
+		}
 		return nil
 	},
-	unimplemented: true,
 }
 
 var pgCatalogInitPrivsTable = virtualSchemaTable{
@@ -3979,6 +4354,10 @@ var datumToTypeCategory = map[types.Family]*tree.DString{
 	types.OidFamily:         typCategoryNumeric,
 	types.UuidFamily:        typCategoryUserDefined,
 	types.INetFamily:        typCategoryNetworkAddr,
+	types.TSQueryFamily:     typCategoryUserDefined,
+	types.TSVectorFamily:    typCategoryUserDefined,
+	types.JsonpathFamily:    typCategoryUserDefined,
+	types.RangeFamily:       typCategoryRange,
 	types.UnknownFamily:     typCategoryUnknown,
 }
 
@@ -3987,6 +4366,9 @@ func typCategory(typ *types.T) tree.Datum {
 	if typ.Family() == types.ArrayFamily && typ.ArrayContents().Family() == types.AnyFamily {
 		return typCategoryPseudo
 	}
+	if typ.IsComposite() {
+		return typCategoryComposite
+	}
 	return datumToTypeCategory[typ.Family()]
 }
 
@@ -4199,6 +4581,11 @@ const (
 	enumEntryTypeTag
 	rewriteTypeTag
 	dbSchemaRoleTypeTag
+	publicationTypeTag
+	publicationRelTypeTag
+	foreignDataWrapperTypeTag
+	foreignServerTypeTag
+	subscriptionTypeTag
 )
 
 func (h oidHasher) writeTypeTag(tag oidTypeTag) {
@@ -4381,6 +4768,50 @@ func (h oidHasher) DBSchemaRoleOid(
 	return h.getOid()
 }
 
+// PublicationOid creates an OID for the publication with the given name in
+// the given database.
+func (h oidHasher) PublicationOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(publicationTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+// PublicationRelOid creates an OID for the membership of a table in a
+// publication, for pg_publication_rel.
+func (h oidHasher) PublicationRelOid(pubOid *tree.DOid, tableID descpb.ID) *tree.DOid {
+	h.writeTypeTag(publicationRelTypeTag)
+	h.writeOID(pubOid)
+	h.writeTable(tableID)
+	return h.getOid()
+}
+
+// ForeignDataWrapperOid creates an OID for the foreign data wrapper with the
+// given name.
+func (h oidHasher) ForeignDataWrapperOid(name string) *tree.DOid {
+	h.writeTypeTag(foreignDataWrapperTypeTag)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+// ForeignServerOid creates an OID for the foreign server with the given name
+// in the given database.
+func (h oidHasher) ForeignServerOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(foreignServerTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
+// SubscriptionOid creates an OID for the subscription with the given name in
+// the given database.
+func (h oidHasher) SubscriptionOid(dbID descpb.ID, name string) *tree.DOid {
+	h.writeTypeTag(subscriptionTypeTag)
+	h.writeDB(dbID)
+	h.writeStr(name)
+	return h.getOid()
+}
+
 func tableOid(id descpb.ID) *tree.DOid {
 	return tree.NewDOid(tree.DInt(id))
 }
@@ -0,0 +1,423 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+)
+
+// Command is a command of the replication protocol. Replication connections,
+// which are opened with the replication=database startup parameter, accept
+// these commands in addition to SQL statements.
+//
+// See https://www.postgresql.org/docs/13/protocol-replication.html.
+type Command interface {
+	replicationCommand()
+}
+
+// IdentifySystem is the IDENTIFY_SYSTEM command.
+type IdentifySystem struct{}
+
+// SnapshotAction is the action requested by CREATE_REPLICATION_SLOT for the
+// snapshot of the database at the point the slot starts.
+type SnapshotAction int
+
+const (
+	// SnapshotExport requests the snapshot to be exported. This is the
+	// default.
+	SnapshotExport SnapshotAction = iota
+	// SnapshotNothing requests the snapshot not to be exported.
+	SnapshotNothing
+	// SnapshotUse requests the snapshot to be used by the current transaction.
+	SnapshotUse
+)
+
+// CreateReplicationSlot is the CREATE_REPLICATION_SLOT command. Only logical
+// slots are supported.
+type CreateReplicationSlot struct {
+	SlotName  string
+	Temporary bool
+	Plugin    string
+	Snapshot  SnapshotAction
+}
+
+// DropReplicationSlot is the DROP_REPLICATION_SLOT command.
+type DropReplicationSlot struct {
+	SlotName string
+	Wait     bool
+}
+
+// StartReplication is the START_REPLICATION command, which starts streaming
+// the changes of a logical replication slot. Only logical slots are
+// supported.
+type StartReplication struct {
+	SlotName string
+	StartLSN LSN
+	// Options are the options passed to the output plugin, in order.
+	Options []Option
+}
+
+// Option is an option of a replication command.
+type Option struct {
+	Name  string
+	Value string
+}
+
+func (*IdentifySystem) replicationCommand()        {}
+func (*CreateReplicationSlot) replicationCommand() {}
+func (*DropReplicationSlot) replicationCommand()   {}
+func (*StartReplication) replicationCommand()      {}
+
+// ParseCommand parses a replication command. It returns nil if the statement
+// isn't a replication command, in which case it is a SQL statement.
+func ParseCommand(sql string) (Command, error) {
+	p := commandParser{s: scanner{in: sql}}
+	tok := p.s.peek()
+	if tok.kind != wordToken {
+		return nil, nil
+	}
+	var cmd Command
+	var err error
+	switch strings.ToUpper(tok.val) {
+	case "IDENTIFY_SYSTEM":
+		p.s.next()
+		cmd = &IdentifySystem{}
+	case "CREATE_REPLICATION_SLOT":
+		p.s.next()
+		cmd, err = p.parseCreateReplicationSlot()
+	case "DROP_REPLICATION_SLOT":
+		p.s.next()
+		cmd, err = p.parseDropReplicationSlot()
+	case "START_REPLICATION":
+		p.s.next()
+		cmd, err = p.parseStartReplication()
+	case "BASE_BACKUP", "TIMELINE_HISTORY", "READ_REPLICATION_SLOT":
+		return nil, unimplemented.Newf("replication "+strings.ToLower(tok.val),
+			"replication command %s is not supported", strings.ToUpper(tok.val))
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.s.next(); tok.kind == semicolonToken {
+		if tok := p.s.next(); tok.kind != eofToken {
+			return nil, p.syntaxError(tok)
+		}
+	} else if tok.kind != eofToken {
+		return nil, p.syntaxError(tok)
+	}
+	return cmd, nil
+}
+
+// commandParser is a recursive descent parser of replication commands. The
+// grammar is the one of src/backend/replication/repl_gram.y in PostgreSQL.
+type commandParser struct {
+	s scanner
+}
+
+// CREATE_REPLICATION_SLOT slot_name [ TEMPORARY ] { PHYSICAL | LOGICAL plugin }
+//   [ EXPORT_SNAPSHOT | NOEXPORT_SNAPSHOT | USE_SNAPSHOT | ( option [, ...] ) ]
+func (p *commandParser) parseCreateReplicationSlot() (Command, error) {
+	cmd := &CreateReplicationSlot{}
+	var err error
+	if cmd.SlotName, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if p.s.peekKeyword("TEMPORARY") {
+		p.s.next()
+		cmd.Temporary = true
+	}
+	if err := p.parseLogical(); err != nil {
+		return nil, err
+	}
+	if cmd.Plugin, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.s.peekKeyword("EXPORT_SNAPSHOT"):
+		p.s.next()
+		cmd.Snapshot = SnapshotExport
+	case p.s.peekKeyword("NOEXPORT_SNAPSHOT"):
+		p.s.next()
+		cmd.Snapshot = SnapshotNothing
+	case p.s.peekKeyword("USE_SNAPSHOT"):
+		p.s.next()
+		cmd.Snapshot = SnapshotUse
+	case p.s.peek().kind == lparenToken:
+		opts, err := p.parseOptions()
+		if err != nil {
+			return nil, err
+		}
+		for _, opt := range opts {
+			switch strings.ToLower(opt.Name) {
+			case "snapshot":
+				switch strings.ToLower(opt.Value) {
+				case "export":
+					cmd.Snapshot = SnapshotExport
+				case "nothing":
+					cmd.Snapshot = SnapshotNothing
+				case "use":
+					cmd.Snapshot = SnapshotUse
+				default:
+					return nil, pgerror.Newf(pgcode.InvalidParameterValue,
+						"unrecognized value for CREATE_REPLICATION_SLOT option %q: %q", "snapshot", opt.Value)
+				}
+			default:
+				return nil, pgerror.Newf(pgcode.Syntax,
+					"unrecognized option: %s", opt.Name)
+			}
+		}
+	}
+	return cmd, nil
+}
+
+// DROP_REPLICATION_SLOT slot_name [ WAIT ]
+func (p *commandParser) parseDropReplicationSlot() (Command, error) {
+	cmd := &DropReplicationSlot{}
+	var err error
+	if cmd.SlotName, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if p.s.peekKeyword("WAIT") {
+		p.s.next()
+		cmd.Wait = true
+	}
+	return cmd, nil
+}
+
+// START_REPLICATION SLOT slot_name LOGICAL XXX/XXX [ ( option [, ...] ) ]
+func (p *commandParser) parseStartReplication() (Command, error) {
+	cmd := &StartReplication{}
+	if !p.s.peekKeyword("SLOT") {
+		// Physical replication doesn't require a slot.
+		return nil, physicalReplicationError()
+	}
+	p.s.next()
+	var err error
+	if cmd.SlotName, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if err := p.parseLogical(); err != nil {
+		return nil, err
+	}
+	tok := p.s.next()
+	if tok.kind != wordToken {
+		return nil, p.syntaxError(tok)
+	}
+	if cmd.StartLSN, err = ParseLSN(tok.val); err != nil {
+		return nil, err
+	}
+	if p.s.peek().kind == lparenToken {
+		if cmd.Options, err = p.parseOptions(); err != nil {
+			return nil, err
+		}
+	}
+	return cmd, nil
+}
+
+// parseLogical parses the kind of a replication slot, which must be LOGICAL.
+func (p *commandParser) parseLogical() error {
+	tok := p.s.next()
+	switch {
+	case tok.isKeyword("LOGICAL"):
+		return nil
+	case tok.isKeyword("PHYSICAL"):
+		return physicalReplicationError()
+	default:
+		return p.syntaxError(tok)
+	}
+}
+
+// ( name [ 'value' ] [, ...] )
+func (p *commandParser) parseOptions() ([]Option, error) {
+	if tok := p.s.next(); tok.kind != lparenToken {
+		return nil, p.syntaxError(tok)
+	}
+	var opts []Option
+	for {
+		var opt Option
+		var err error
+		if opt.Name, err = p.parseIdent(); err != nil {
+			return nil, err
+		}
+		switch tok := p.s.peek(); tok.kind {
+		case stringToken, wordToken:
+			p.s.next()
+			opt.Value = tok.val
+		}
+		opts = append(opts, opt)
+		switch tok := p.s.next(); tok.kind {
+		case commaToken:
+		case rparenToken:
+			return opts, nil
+		default:
+			return nil, p.syntaxError(tok)
+		}
+	}
+}
+
+// parseIdent parses an identifier, which is either a bare word folded to
+// lower case or a double-quoted identifier.
+func (p *commandParser) parseIdent() (string, error) {
+	tok := p.s.next()
+	switch tok.kind {
+	case wordToken:
+		return strings.ToLower(tok.val), nil
+	case quotedIdentToken:
+		return tok.val, nil
+	default:
+		return "", p.syntaxError(tok)
+	}
+}
+
+func (p *commandParser) syntaxError(tok token) error {
+	if p.s.err != nil {
+		return p.s.err
+	}
+	if tok.kind == eofToken {
+		return pgerror.New(pgcode.Syntax, "syntax error: unexpected end of replication command")
+	}
+	return pgerror.Newf(pgcode.Syntax, "syntax error at or near %q", tok.val)
+}
+
+func physicalReplicationError() error {
+	return unimplemented.New("physical replication",
+		"physical replication is not supported; use a logical replication slot")
+}
+
+type tokenKind int
+
+const (
+	eofToken tokenKind = iota
+	wordToken
+	quotedIdentToken
+	stringToken
+	lparenToken
+	rparenToken
+	commaToken
+	semicolonToken
+	invalidToken
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+}
+
+func (t token) isKeyword(kw string) bool {
+	return t.kind == wordToken && strings.EqualFold(t.val, kw)
+}
+
+// scanner splits replication commands into tokens. Words are sequences of
+// letters, digits, underscores and slashes, so that LSNs are scanned as a
+// single word.
+type scanner struct {
+	in     string
+	pos    int
+	peeked *token
+	err    error
+}
+
+func (s *scanner) peek() token {
+	if s.peeked == nil {
+		tok := s.scan()
+		s.peeked = &tok
+	}
+	return *s.peeked
+}
+
+func (s *scanner) peekKeyword(kw string) bool {
+	return s.peek().isKeyword(kw)
+}
+
+func (s *scanner) next() token {
+	tok := s.peek()
+	s.peeked = nil
+	return tok
+}
+
+func (s *scanner) scan() token {
+	for s.pos < len(s.in) && isSpace(s.in[s.pos]) {
+		s.pos++
+	}
+	if s.pos == len(s.in) {
+		return token{kind: eofToken}
+	}
+	c := s.in[s.pos]
+	switch {
+	case c == '(':
+		s.pos++
+		return token{kind: lparenToken, val: "("}
+	case c == ')':
+		s.pos++
+		return token{kind: rparenToken, val: ")"}
+	case c == ',':
+		s.pos++
+		return token{kind: commaToken, val: ","}
+	case c == ';':
+		s.pos++
+		return token{kind: semicolonToken, val: ";"}
+	case c == '"':
+		return s.scanQuoted('"', quotedIdentToken)
+	case c == '\'':
+		return s.scanQuoted('\'', stringToken)
+	case isWordChar(c):
+		start := s.pos
+		for s.pos < len(s.in) && isWordChar(s.in[s.pos]) {
+			s.pos++
+		}
+		return token{kind: wordToken, val: s.in[start:s.pos]}
+	default:
+		s.pos++
+		return token{kind: invalidToken, val: string(c)}
+	}
+}
+
+// scanQuoted scans a quoted string in which the quote is escaped by doubling
+// it.
+func (s *scanner) scanQuoted(quote byte, kind tokenKind) token {
+	var b strings.Builder
+	s.pos++
+	for s.pos < len(s.in) {
+		c := s.in[s.pos]
+		s.pos++
+		if c != quote {
+			b.WriteByte(c)
+			continue
+		}
+		if s.pos < len(s.in) && s.in[s.pos] == quote {
+			b.WriteByte(quote)
+			s.pos++
+			continue
+		}
+		return token{kind: kind, val: b.String()}
+	}
+	if kind == quotedIdentToken {
+		s.err = pgerror.New(pgcode.Syntax, "unterminated quoted identifier")
+	} else {
+		s.err = pgerror.New(pgcode.Syntax, "unterminated quoted string")
+	}
+	return token{kind: invalidToken}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c == '/' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommand(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		in  string
+		cmd Command
+	}{
+		// SQL statements aren't replication commands.
+		{in: "SELECT 1", cmd: nil},
+		{in: "", cmd: nil},
+		{in: `"IDENTIFY_SYSTEM"`, cmd: nil},
+
+		{in: "IDENTIFY_SYSTEM", cmd: &IdentifySystem{}},
+		{in: "identify_system;", cmd: &IdentifySystem{}},
+
+		{
+			in:  "CREATE_REPLICATION_SLOT s LOGICAL pgoutput",
+			cmd: &CreateReplicationSlot{SlotName: "s", Plugin: "pgoutput"},
+		},
+		{
+			in:  `CREATE_REPLICATION_SLOT "My_Slot" TEMPORARY LOGICAL "pgoutput" NOEXPORT_SNAPSHOT`,
+			cmd: &CreateReplicationSlot{SlotName: "My_Slot", Temporary: true, Plugin: "pgoutput", Snapshot: SnapshotNothing},
+		},
+		{
+			in:  "CREATE_REPLICATION_SLOT S LOGICAL pgoutput USE_SNAPSHOT",
+			cmd: &CreateReplicationSlot{SlotName: "s", Plugin: "pgoutput", Snapshot: SnapshotUse},
+		},
+		{
+			in:  "CREATE_REPLICATION_SLOT s LOGICAL pgoutput (SNAPSHOT 'nothing')",
+			cmd: &CreateReplicationSlot{SlotName: "s", Plugin: "pgoutput", Snapshot: SnapshotNothing},
+		},
+
+		{in: "DROP_REPLICATION_SLOT s", cmd: &DropReplicationSlot{SlotName: "s"}},
+		{in: `DROP_REPLICATION_SLOT "s" WAIT`, cmd: &DropReplicationSlot{SlotName: "s", Wait: true}},
+
+		{
+			in:  "START_REPLICATION SLOT s LOGICAL 0/0",
+			cmd: &StartReplication{SlotName: "s"},
+		},
+		{
+			in: `START_REPLICATION SLOT "s" LOGICAL 16/B374D848 ("proto_version" '1', "publication_names" 'p1,"P2"', binary)`,
+			cmd: &StartReplication{
+				SlotName: "s",
+				StartLSN: 0x16B374D848,
+				Options: []Option{
+					{Name: "proto_version", Value: "1"},
+					{Name: "publication_names", Value: `p1,"P2"`},
+					{Name: "binary"},
+				},
+			},
+		},
+		{
+			in:  "START_REPLICATION SLOT s LOGICAL 0/1 (messages 'it''s')",
+			cmd: &StartReplication{SlotName: "s", StartLSN: 1, Options: []Option{{Name: "messages", Value: "it's"}}},
+		},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			cmd, err := ParseCommand(tc.in)
+			require.NoError(t, err)
+			require.Equal(t, tc.cmd, cmd)
+		})
+	}
+}
+
+func TestParseCommandErrors(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{in: "IDENTIFY_SYSTEM x", err: `syntax error at or near "x"`},
+		{in: "CREATE_REPLICATION_SLOT", err: `syntax error: unexpected end of replication command`},
+		{in: "CREATE_REPLICATION_SLOT s PHYSICAL", err: `unimplemented: physical replication is not supported; use a logical replication slot`},
+		{in: "CREATE_REPLICATION_SLOT s LOGICAL pgoutput (SNAPSHOT 'x')", err: `unrecognized value for CREATE_REPLICATION_SLOT option "snapshot": "x"`},
+		{in: "CREATE_REPLICATION_SLOT s LOGICAL pgoutput (foo)", err: `unrecognized option: foo`},
+		{in: "START_REPLICATION 0/0", err: `unimplemented: physical replication is not supported; use a logical replication slot`},
+		{in: "START_REPLICATION SLOT s LOGICAL x", err: `invalid input syntax for type pg_lsn: "x"`},
+		{in: "START_REPLICATION SLOT s LOGICAL 0/0 (a 'b'", err: `syntax error: unexpected end of replication command`},
+		{in: `START_REPLICATION SLOT "s`, err: `unterminated quoted identifier`},
+		{in: "START_REPLICATION SLOT s LOGICAL 0/0 (a 'b)", err: `unterminated quoted string`},
+		{in: "BASE_BACKUP", err: `unimplemented: replication command BASE_BACKUP is not supported`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			_, err := ParseCommand(tc.in)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+}
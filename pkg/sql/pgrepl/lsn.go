@@ -0,0 +1,109 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package pgrepl implements the parts of the PostgreSQL streaming replication
+// protocol which don't depend on the SQL layer: log sequence numbers, the
+// commands of replication connections and the messages exchanged while
+// streaming changes with the pgoutput logical decoding plugin.
+package pgrepl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+)
+
+// LSN is a PostgreSQL log sequence number, which identifies a position in the
+// stream of changes of a replication slot.
+//
+// CockroachDB has no write-ahead log shared by the whole cluster, so LSNs are
+// derived from the MVCC timestamps at which the changes were committed. The
+// high 60 bits of an LSN hold the wall time of a timestamp, in nanoseconds
+// since lsnEpoch, and the low 4 bits hold its logical component, so that
+// transactions committed at the same wall time get different LSNs. Logical
+// components which don't fit are capped, so a client resuming from such an
+// LSN may receive again some of the changes committed at that wall time.
+type LSN uint64
+
+// InvalidLSN is the zero LSN, which PostgreSQL uses to denote the absence of
+// a position.
+const InvalidLSN LSN = 0
+
+const (
+	// lsnEpoch is the wall time, in nanoseconds since the Unix epoch, which
+	// LSNs are relative to (2021-01-01 00:00:00 UTC). Relative to it, the wall
+	// times fit in the 60 bits of LSNs until 2057.
+	lsnEpoch = 1609459200 * int64(time.Second)
+	// lsnLogicalBits is the number of bits of LSNs which hold the logical
+	// component of timestamps.
+	lsnLogicalBits = 4
+	lsnMaxLogical  = 1<<lsnLogicalBits - 1
+)
+
+// ParseLSN parses an LSN in its textual form, which is two hexadecimal
+// numbers of up to 32 bits separated by a slash, e.g. 16/B374D848.
+func ParseLSN(s string) (LSN, error) {
+	slash := strings.IndexByte(s, '/')
+	if slash < 0 {
+		return 0, invalidLSNError(s)
+	}
+	hi, err := strconv.ParseUint(s[:slash], 16, 32)
+	if err != nil {
+		return 0, invalidLSNError(s)
+	}
+	lo, err := strconv.ParseUint(s[slash+1:], 16, 32)
+	if err != nil {
+		return 0, invalidLSNError(s)
+	}
+	return LSN(hi<<32 | lo), nil
+}
+
+func invalidLSNError(s string) error {
+	return pgerror.Newf(pgcode.InvalidTextRepresentation,
+		"invalid input syntax for type pg_lsn: %q", s)
+}
+
+// String formats the LSN in its textual form.
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint64(l)>>32, uint32(l))
+}
+
+// LSNFromTimestamp returns the LSN of the changes committed at the given
+// timestamp. LSNs increase with timestamps, and the timestamps before
+// lsnEpoch all map to InvalidLSN.
+func LSNFromTimestamp(ts hlc.Timestamp) LSN {
+	if ts.WallTime < lsnEpoch {
+		return InvalidLSN
+	}
+	logical := ts.Logical
+	if logical > lsnMaxLogical {
+		logical = lsnMaxLogical
+	}
+	// The LSNs are offset by one so that no timestamp after lsnEpoch maps to
+	// InvalidLSN.
+	return LSN(uint64(ts.WallTime-lsnEpoch)<<lsnLogicalBits|uint64(logical)) + 1
+}
+
+// Timestamp returns the lowest timestamp whose changes have the LSN.
+func (l LSN) Timestamp() hlc.Timestamp {
+	if l == InvalidLSN {
+		return hlc.Timestamp{}
+	}
+	l--
+	return hlc.Timestamp{
+		WallTime: int64(l>>lsnLogicalBits) + lsnEpoch,
+		Logical:  int32(l & lsnMaxLogical),
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLSN(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		in  string
+		lsn LSN
+		err string
+	}{
+		{in: "0/0", lsn: 0},
+		{in: "16/B374D848", lsn: 0x16B374D848},
+		{in: "16/b374d848", lsn: 0x16B374D848},
+		{in: "FFFFFFFF/FFFFFFFF", lsn: 0xFFFFFFFFFFFFFFFF},
+		{in: "", err: `invalid input syntax for type pg_lsn: ""`},
+		{in: "16", err: `invalid input syntax for type pg_lsn: "16"`},
+		{in: "16/", err: `invalid input syntax for type pg_lsn: "16/"`},
+		{in: "1/G", err: `invalid input syntax for type pg_lsn: "1/G"`},
+		{in: "100000000/0", err: `invalid input syntax for type pg_lsn: "100000000/0"`},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			lsn, err := ParseLSN(tc.in)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.lsn, lsn)
+		})
+	}
+}
+
+func TestLSNString(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	require.Equal(t, "0/0", InvalidLSN.String())
+	require.Equal(t, "16/B374D848", LSN(0x16B374D848).String())
+	lsn, err := ParseLSN(LSN(1636000000123456789).String())
+	require.NoError(t, err)
+	require.Equal(t, LSN(1636000000123456789), lsn)
+}
+
+func TestLSNTimestamp(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		ts  hlc.Timestamp
+		lsn LSN
+		// lowest is the lowest timestamp with the same LSN as ts.
+		lowest hlc.Timestamp
+	}{
+		{ts: hlc.Timestamp{}, lsn: InvalidLSN},
+		{ts: hlc.Timestamp{WallTime: lsnEpoch - 1, Logical: 3}, lsn: InvalidLSN},
+		{
+			ts:     hlc.Timestamp{WallTime: lsnEpoch},
+			lsn:    1,
+			lowest: hlc.Timestamp{WallTime: lsnEpoch},
+		},
+		{
+			ts:     hlc.Timestamp{WallTime: 1636000000123456789},
+			lsn:    0x5E4AB79369CD151,
+			lowest: hlc.Timestamp{WallTime: 1636000000123456789},
+		},
+		{
+			ts:     hlc.Timestamp{WallTime: 1636000000123456789, Logical: 3},
+			lsn:    0x5E4AB79369CD154,
+			lowest: hlc.Timestamp{WallTime: 1636000000123456789, Logical: 3},
+		},
+		// The logical components which don't fit are capped.
+		{
+			ts:     hlc.Timestamp{WallTime: 1636000000123456789, Logical: 20},
+			lsn:    0x5E4AB79369CD160,
+			lowest: hlc.Timestamp{WallTime: 1636000000123456789, Logical: 15},
+		},
+	} {
+		t.Run(tc.ts.String(), func(t *testing.T) {
+			lsn := LSNFromTimestamp(tc.ts)
+			require.Equal(t, tc.lsn, lsn)
+			require.Equal(t, tc.lowest, lsn.Timestamp())
+			require.True(t, lsn.Timestamp().LessEq(tc.ts))
+		})
+	}
+
+	// Transactions committed at the same wall time have different LSNs, in
+	// the order of their timestamps.
+	prev := LSNFromTimestamp(hlc.Timestamp{WallTime: 1636000000123456789})
+	for _, ts := range []hlc.Timestamp{
+		{WallTime: 1636000000123456789, Logical: 1},
+		{WallTime: 1636000000123456789, Logical: 2},
+		{WallTime: 1636000000123456790},
+	} {
+		lsn := LSNFromTimestamp(ts)
+		require.Less(t, uint64(prev), uint64(lsn))
+		prev = lsn
+	}
+}
@@ -0,0 +1,560 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// The messages sent by the server while streaming are wrapped in CopyData
+// messages, and start with one of these bytes.
+const (
+	// XLogDataByte starts the messages carrying a message of the output
+	// plugin.
+	XLogDataByte = 'w'
+	// PrimaryKeepaliveByte starts the keepalive messages.
+	PrimaryKeepaliveByte = 'k'
+)
+
+// The messages sent by the client while streaming are wrapped in CopyData
+// messages, and start with one of these bytes.
+const (
+	// StandbyStatusUpdateByte starts the messages reporting the progress of
+	// the client.
+	StandbyStatusUpdateByte = 'r'
+	// HotStandbyFeedbackByte starts the messages used by physical standbys,
+	// which are ignored.
+	HotStandbyFeedbackByte = 'h'
+)
+
+// PGOutputProtoVersion is the version of the pgoutput protocol which is
+// implemented.
+const PGOutputProtoVersion = 1
+
+// PGOutputMessage is a message of the pgoutput logical decoding plugin.
+//
+// See https://www.postgresql.org/docs/13/protocol-logicalrep-message-formats.html.
+type PGOutputMessage interface {
+	pgoutputMessage()
+}
+
+// Begin starts the changes of a transaction.
+type Begin struct {
+	// FinalLSN is the LSN of the commit of the transaction.
+	FinalLSN   LSN
+	CommitTime time.Time
+	XID        uint32
+}
+
+// Commit ends the changes of a transaction.
+type Commit struct {
+	CommitLSN  LSN
+	EndLSN     LSN
+	CommitTime time.Time
+}
+
+// Relation describes a table. It is sent before the first change to the
+// table in the stream, and again after each change of its schema.
+type Relation struct {
+	ID        oid.Oid
+	Namespace string
+	Name      string
+	Columns   []RelationColumn
+}
+
+// RelationColumn describes a column of a Relation.
+type RelationColumn struct {
+	// Key is set if the column is part of the replica identity of the table,
+	// which is its primary key.
+	Key     bool
+	Name    string
+	TypeOID oid.Oid
+	TypeMod int32
+}
+
+// Insert is a row inserted into a table.
+type Insert struct {
+	RelationID oid.Oid
+	New        tree.Datums
+}
+
+// Update is a row updated in a table. Updates never change the primary key
+// of a row, since they are streamed as a Delete followed by an Insert in that
+// case, so the old key is never sent.
+type Update struct {
+	RelationID oid.Oid
+	// OldKey is only set by PostgreSQL servers, when the update changed the
+	// primary key of the row. It is never encoded.
+	OldKey tree.Datums
+	New    tree.Datums
+}
+
+// Delete is a row deleted from a table. Only the columns of the primary key
+// of the old row are set; the other columns are NULL.
+type Delete struct {
+	RelationID oid.Oid
+	OldKey     tree.Datums
+}
+
+// Truncate is a truncation of tables. It is only sent by PostgreSQL servers.
+type Truncate struct {
+	RelationIDs     []oid.Oid
+	Cascade         bool
+	RestartIdentity bool
+}
+
+func (*Begin) pgoutputMessage()    {}
+func (*Commit) pgoutputMessage()   {}
+func (*Relation) pgoutputMessage() {}
+func (*Insert) pgoutputMessage()   {}
+func (*Update) pgoutputMessage()   {}
+func (*Delete) pgoutputMessage()   {}
+func (*Truncate) pgoutputMessage() {}
+
+// StandbyStatusUpdate is the message periodically sent by the client to
+// report its progress.
+type StandbyStatusUpdate struct {
+	// WriteLSN is the position up to which the client received the changes.
+	WriteLSN LSN
+	// FlushLSN is the position up to which the client durably stored the
+	// changes. The changes before it are not streamed again.
+	FlushLSN LSN
+	// ApplyLSN is the position up to which the client applied the changes.
+	ApplyLSN LSN
+	// ClientTime is the time at which the message was sent.
+	ClientTime time.Time
+	// ReplyRequested is set if the client requests a keepalive message.
+	ReplyRequested bool
+}
+
+// standbyStatusUpdateLen is the length of a standby status update, including
+// its leading byte.
+const standbyStatusUpdateLen = 1 + 8 + 8 + 8 + 8 + 1
+
+// ParseStandbyStatusUpdate parses a standby status update sent by the
+// client. The data must start with StandbyStatusUpdateByte.
+func ParseStandbyStatusUpdate(data []byte) (StandbyStatusUpdate, error) {
+	if len(data) < standbyStatusUpdateLen || data[0] != StandbyStatusUpdateByte {
+		return StandbyStatusUpdate{}, pgerror.New(pgcode.ProtocolViolation,
+			"invalid standby status update message")
+	}
+	data = data[1:]
+	return StandbyStatusUpdate{
+		WriteLSN:       LSN(binary.BigEndian.Uint64(data[0:8])),
+		FlushLSN:       LSN(binary.BigEndian.Uint64(data[8:16])),
+		ApplyLSN:       LSN(binary.BigEndian.Uint64(data[16:24])),
+		ClientTime:     TimeFromPGMicros(int64(binary.BigEndian.Uint64(data[24:32]))),
+		ReplyRequested: data[32] != 0,
+	}, nil
+}
+
+// Encode appends the message, including its leading byte, to buf.
+func (u StandbyStatusUpdate) Encode(buf []byte) []byte {
+	buf = append(buf, StandbyStatusUpdateByte)
+	buf = appendUint64(buf, uint64(u.WriteLSN))
+	buf = appendUint64(buf, uint64(u.FlushLSN))
+	buf = appendUint64(buf, uint64(u.ApplyLSN))
+	buf = appendUint64(buf, uint64(PGMicros(u.ClientTime)))
+	var reply byte
+	if u.ReplyRequested {
+		reply = 1
+	}
+	return append(buf, reply)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+// XLogData is the message sent by the server to carry a message of the output
+// plugin.
+type XLogData struct {
+	// WALStart is the position of the message.
+	WALStart LSN
+	// WALEnd is the position up to which the server sent the changes.
+	WALEnd LSN
+	// ServerTime is the time at which the message was sent.
+	ServerTime time.Time
+	// Data is the message of the output plugin, which can be parsed with
+	// ParsePGOutputMessage.
+	Data []byte
+}
+
+// ParseXLogData parses an XLogData message sent by the server. The data must
+// start with XLogDataByte.
+func ParseXLogData(data []byte) (XLogData, error) {
+	r := messageReader{data: data}
+	if r.byte() != XLogDataByte {
+		return XLogData{}, pgerror.New(pgcode.ProtocolViolation, "invalid XLogData message")
+	}
+	msg := XLogData{
+		WALStart:   LSN(r.uint64()),
+		WALEnd:     LSN(r.uint64()),
+		ServerTime: TimeFromPGMicros(int64(r.uint64())),
+	}
+	if r.err != nil {
+		return XLogData{}, pgerror.New(pgcode.ProtocolViolation, "invalid XLogData message")
+	}
+	msg.Data = r.data
+	return msg, nil
+}
+
+// PrimaryKeepalive is the keepalive message sent by the server.
+type PrimaryKeepalive struct {
+	// WALEnd is the position up to which the server sent the changes.
+	WALEnd LSN
+	// ServerTime is the time at which the message was sent.
+	ServerTime time.Time
+	// ReplyRequested is set if the server requests a standby status update
+	// right away.
+	ReplyRequested bool
+}
+
+// ParsePrimaryKeepalive parses a keepalive message sent by the server. The
+// data must start with PrimaryKeepaliveByte.
+func ParsePrimaryKeepalive(data []byte) (PrimaryKeepalive, error) {
+	r := messageReader{data: data}
+	if r.byte() != PrimaryKeepaliveByte {
+		return PrimaryKeepalive{}, pgerror.New(pgcode.ProtocolViolation,
+			"invalid primary keepalive message")
+	}
+	msg := PrimaryKeepalive{
+		WALEnd:         LSN(r.uint64()),
+		ServerTime:     TimeFromPGMicros(int64(r.uint64())),
+		ReplyRequested: r.byte() != 0,
+	}
+	if r.err != nil {
+		return PrimaryKeepalive{}, pgerror.New(pgcode.ProtocolViolation,
+			"invalid primary keepalive message")
+	}
+	return msg, nil
+}
+
+// The bytes starting the messages of the pgoutput plugin.
+const (
+	pgoutputBeginByte    = 'B'
+	pgoutputCommitByte   = 'C'
+	pgoutputOriginByte   = 'O'
+	pgoutputRelationByte = 'R'
+	pgoutputTypeByte     = 'Y'
+	pgoutputInsertByte   = 'I'
+	pgoutputUpdateByte   = 'U'
+	pgoutputDeleteByte   = 'D'
+	pgoutputTruncateByte = 'T'
+)
+
+// ParsePGOutputMessage parses a message of the pgoutput plugin, carried by an
+// XLogData message. The values of the tuples, which are in the text format,
+// are returned as strings, or as nil for the unchanged TOASTed values of
+// updated rows. Origin and Type messages, which only describe the changes
+// which follow them, are skipped: nil is returned.
+func ParsePGOutputMessage(data []byte) (PGOutputMessage, error) {
+	r := messageReader{data: data}
+	var msg PGOutputMessage
+	switch kind := r.byte(); kind {
+	case pgoutputBeginByte:
+		msg = &Begin{
+			FinalLSN:   LSN(r.uint64()),
+			CommitTime: TimeFromPGMicros(int64(r.uint64())),
+			XID:        r.uint32(),
+		}
+	case pgoutputCommitByte:
+		r.byte() // flags
+		msg = &Commit{
+			CommitLSN:  LSN(r.uint64()),
+			EndLSN:     LSN(r.uint64()),
+			CommitTime: TimeFromPGMicros(int64(r.uint64())),
+		}
+	case pgoutputOriginByte, pgoutputTypeByte:
+		return nil, nil
+	case pgoutputRelationByte:
+		rel := &Relation{ID: oid.Oid(r.uint32()), Namespace: r.string(), Name: r.string()}
+		r.byte() // replica identity
+		rel.Columns = make([]RelationColumn, r.uint16())
+		for i := range rel.Columns {
+			rel.Columns[i] = RelationColumn{
+				Key:     r.byte()&1 != 0,
+				Name:    r.string(),
+				TypeOID: oid.Oid(r.uint32()),
+				TypeMod: int32(r.uint32()),
+			}
+		}
+		msg = rel
+	case pgoutputInsertByte:
+		ins := &Insert{RelationID: oid.Oid(r.uint32())}
+		if r.byte() != 'N' {
+			return nil, invalidPGOutputMessageError(kind)
+		}
+		ins.New = r.tuple()
+		msg = ins
+	case pgoutputUpdateByte:
+		upd := &Update{RelationID: oid.Oid(r.uint32())}
+		tupleKind := r.byte()
+		if tupleKind == 'K' || tupleKind == 'O' {
+			upd.OldKey = r.tuple()
+			tupleKind = r.byte()
+		}
+		if tupleKind != 'N' {
+			return nil, invalidPGOutputMessageError(kind)
+		}
+		upd.New = r.tuple()
+		msg = upd
+	case pgoutputDeleteByte:
+		del := &Delete{RelationID: oid.Oid(r.uint32())}
+		if tupleKind := r.byte(); tupleKind != 'K' && tupleKind != 'O' {
+			return nil, invalidPGOutputMessageError(kind)
+		}
+		del.OldKey = r.tuple()
+		msg = del
+	case pgoutputTruncateByte:
+		n := int(r.uint32())
+		options := r.byte()
+		if n > len(r.data)/4 {
+			return nil, invalidPGOutputMessageError(kind)
+		}
+		trunc := &Truncate{RelationIDs: make([]oid.Oid, n)}
+		trunc.Cascade = options&1 != 0
+		trunc.RestartIdentity = options&2 != 0
+		for i := range trunc.RelationIDs {
+			trunc.RelationIDs[i] = oid.Oid(r.uint32())
+		}
+		msg = trunc
+	default:
+		return nil, pgerror.Newf(pgcode.ProtocolViolation,
+			"unexpected pgoutput message type %q", kind)
+	}
+	if r.err != nil {
+		return nil, errors.Wrap(r.err, "invalid pgoutput message")
+	}
+	return msg, nil
+}
+
+func invalidPGOutputMessageError(kind byte) error {
+	return pgerror.Newf(pgcode.ProtocolViolation, "invalid pgoutput message of type %q", kind)
+}
+
+// messageReader reads the fields of a message. Once it runs out of data, err
+// is set and the fields read are zero.
+type messageReader struct {
+	data []byte
+	err  error
+}
+
+// zeros is returned in place of the fixed-size fields which are past the end
+// of the message.
+var zeros [8]byte
+
+func (r *messageReader) next(n int) []byte {
+	if r.err == nil && len(r.data) < n {
+		r.err = pgerror.New(pgcode.ProtocolViolation, "message too short")
+	}
+	if r.err != nil {
+		if n <= len(zeros) {
+			return zeros[:n]
+		}
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *messageReader) byte() byte     { return r.next(1)[0] }
+func (r *messageReader) uint16() uint16 { return binary.BigEndian.Uint16(r.next(2)) }
+func (r *messageReader) uint32() uint32 { return binary.BigEndian.Uint32(r.next(4)) }
+func (r *messageReader) uint64() uint64 { return binary.BigEndian.Uint64(r.next(8)) }
+
+// string reads a null-terminated string.
+func (r *messageReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	for i, c := range r.data {
+		if c == 0 {
+			s := string(r.data[:i])
+			r.data = r.data[i+1:]
+			return s
+		}
+	}
+	r.err = pgerror.New(pgcode.ProtocolViolation, "unterminated string")
+	return ""
+}
+
+// tuple reads the values of a row.
+func (r *messageReader) tuple() tree.Datums {
+	row := make(tree.Datums, r.uint16())
+	for i := range row {
+		switch kind := r.byte(); kind {
+		case 'n':
+			row[i] = tree.DNull
+		case 'u':
+			// Unchanged TOASTed values are left nil.
+		case 't':
+			row[i] = tree.NewDString(string(r.next(int(r.uint32()))))
+		default:
+			if r.err == nil {
+				r.err = pgerror.Newf(pgcode.ProtocolViolation,
+					"unsupported tuple value kind %q", kind)
+			}
+			return nil
+		}
+	}
+	return row
+}
+
+// pgEpoch is the epoch of the timestamps of the replication protocol.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// PGMicros returns the number of microseconds between the PostgreSQL epoch
+// (2000-01-01) and the given time, which is how the replication protocol
+// encodes timestamps.
+func PGMicros(t time.Time) int64 {
+	return t.Sub(pgEpoch).Microseconds()
+}
+
+// TimeFromPGMicros is the inverse of PGMicros.
+func TimeFromPGMicros(micros int64) time.Time {
+	return pgEpoch.Add(time.Duration(micros) * time.Microsecond)
+}
+
+// PGOutputOptions are the options of the pgoutput plugin, passed to
+// START_REPLICATION.
+type PGOutputOptions struct {
+	// PublicationNames are the names of the publications whose tables are
+	// streamed.
+	PublicationNames []string
+}
+
+// ParsePGOutputOptions parses the options of the pgoutput plugin. The
+// options enabling features introduced after version 1 of the protocol
+// (binary, messages and streaming) can only be disabled.
+func ParsePGOutputOptions(opts []Option) (PGOutputOptions, error) {
+	var res PGOutputOptions
+	var sawVersion, sawPublications bool
+	for _, opt := range opts {
+		switch strings.ToLower(opt.Name) {
+		case "proto_version":
+			v, err := strconv.Atoi(opt.Value)
+			if err != nil {
+				return res, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid proto_version: %q", opt.Value)
+			}
+			if v != PGOutputProtoVersion {
+				return res, pgerror.Newf(pgcode.FeatureNotSupported,
+					"client sent proto_version=%d but we only support protocol %d",
+					v, PGOutputProtoVersion)
+			}
+			sawVersion = true
+		case "publication_names":
+			names, err := splitIdentifierList(opt.Value)
+			if err != nil {
+				return res, err
+			}
+			res.PublicationNames = names
+			sawPublications = true
+		case "binary", "messages", "streaming":
+			if enabled, ok := parseBool(opt.Value); !ok {
+				return res, pgerror.Newf(pgcode.InvalidParameterValue,
+					"invalid value for option %q: %q", opt.Name, opt.Value)
+			} else if enabled {
+				return res, pgerror.Newf(pgcode.FeatureNotSupported,
+					"option %q is not supported", opt.Name)
+			}
+		default:
+			return res, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized pgoutput option: %s", opt.Name)
+		}
+	}
+	if !sawVersion {
+		return res, pgerror.New(pgcode.InvalidParameterValue, "proto_version option missing")
+	}
+	if !sawPublications {
+		return res, pgerror.New(pgcode.InvalidParameterValue, "publication_names parameter missing")
+	}
+	return res, nil
+}
+
+// splitIdentifierList splits a comma-separated list of identifiers, which are
+// folded to lower case unless they are double-quoted.
+func splitIdentifierList(s string) ([]string, error) {
+	var names []string
+	for i := 0; ; {
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		var name string
+		if i < len(s) && s[i] == '"' {
+			var b strings.Builder
+			for i++; ; i++ {
+				if i >= len(s) {
+					return nil, invalidIdentifierListError(s)
+				}
+				if s[i] == '"' {
+					if i+1 < len(s) && s[i+1] == '"' {
+						i++
+					} else {
+						break
+					}
+				}
+				b.WriteByte(s[i])
+			}
+			i++
+			name = b.String()
+		} else {
+			start := i
+			for i < len(s) && s[i] != ',' && !isSpace(s[i]) {
+				i++
+			}
+			name = strings.ToLower(s[start:i])
+		}
+		if name == "" {
+			return nil, invalidIdentifierListError(s)
+		}
+		names = append(names, name)
+		for i < len(s) && isSpace(s[i]) {
+			i++
+		}
+		if i == len(s) {
+			return names, nil
+		}
+		if s[i] != ',' {
+			return nil, invalidIdentifierListError(s)
+		}
+		i++
+	}
+}
+
+func invalidIdentifierListError(s string) error {
+	return pgerror.Newf(pgcode.InvalidName, "invalid publication_names syntax: %q", s)
+}
+
+// parseBool parses a boolean option value the way PostgreSQL does. ok is
+// false if the value isn't a boolean.
+func parseBool(s string) (_ bool, ok bool) {
+	switch strings.ToLower(s) {
+	case "", "true", "on", "yes", "1":
+		return true, true
+	case "false", "off", "no", "0":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,255 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgrepl
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/lib/pq/oid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePGOutputOptions(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	for _, tc := range []struct {
+		opts  []Option
+		names []string
+		err   string
+	}{
+		{
+			opts:  []Option{{"proto_version", "1"}, {"publication_names", "p1"}},
+			names: []string{"p1"},
+		},
+		{
+			opts:  []Option{{"publication_names", ` P1 , "My ""Pub""",p3`}, {"proto_version", "1"}},
+			names: []string{"p1", `My "Pub"`, "p3"},
+		},
+		{
+			opts: []Option{
+				{"proto_version", "1"}, {"publication_names", "p"},
+				{"binary", "false"}, {"messages", "off"}, {"streaming", "0"},
+			},
+			names: []string{"p"},
+		},
+		{
+			opts: []Option{{"publication_names", "p"}},
+			err:  "proto_version option missing",
+		},
+		{
+			opts: []Option{{"proto_version", "1"}},
+			err:  "publication_names parameter missing",
+		},
+		{
+			opts: []Option{{"proto_version", "2"}, {"publication_names", "p"}},
+			err:  "client sent proto_version=2 but we only support protocol 1",
+		},
+		{
+			opts: []Option{{"proto_version", "1"}, {"publication_names", "p"}, {"binary", ""}},
+			err:  `option "binary" is not supported`,
+		},
+		{
+			opts: []Option{{"proto_version", "1"}, {"publication_names", "p,,q"}},
+			err:  `invalid publication_names syntax: "p,,q"`,
+		},
+		{
+			opts: []Option{{"proto_version", "1"}, {"publication_names", `"p`}},
+			err:  `invalid publication_names syntax: "\"p"`,
+		},
+		{
+			opts: []Option{{"proto_version", "1"}, {"publication_names", "p"}, {"origin", "any"}},
+			err:  "unrecognized pgoutput option: origin",
+		},
+	} {
+		res, err := ParsePGOutputOptions(tc.opts)
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.names, res.PublicationNames)
+	}
+}
+
+func TestParseStandbyStatusUpdate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	clientTime := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	data := make([]byte, 34)
+	data[0] = StandbyStatusUpdateByte
+	binary.BigEndian.PutUint64(data[1:], 3)
+	binary.BigEndian.PutUint64(data[9:], 2)
+	binary.BigEndian.PutUint64(data[17:], 1)
+	binary.BigEndian.PutUint64(data[25:], uint64(PGMicros(clientTime)))
+	data[33] = 1
+
+	update, err := ParseStandbyStatusUpdate(data)
+	require.NoError(t, err)
+	require.Equal(t, StandbyStatusUpdate{
+		WriteLSN:       3,
+		FlushLSN:       2,
+		ApplyLSN:       1,
+		ClientTime:     clientTime,
+		ReplyRequested: true,
+	}, update)
+
+	_, err = ParseStandbyStatusUpdate(data[:len(data)-1])
+	require.EqualError(t, err, "invalid standby status update message")
+}
+
+func TestEncodeStandbyStatusUpdate(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	update := StandbyStatusUpdate{
+		WriteLSN:   3,
+		FlushLSN:   2,
+		ApplyLSN:   1,
+		ClientTime: time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC),
+	}
+	data := update.Encode([]byte("prefix"))
+	require.Equal(t, "prefix", string(data[:6]))
+	res, err := ParseStandbyStatusUpdate(data[6:])
+	require.NoError(t, err)
+	require.Equal(t, update, res)
+}
+
+func TestParseServerMessages(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	serverTime := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	data := []byte{XLogDataByte}
+	data = appendUint64(data, 2)
+	data = appendUint64(data, 3)
+	data = appendUint64(data, uint64(PGMicros(serverTime)))
+	data = append(data, "Bpayload"...)
+	xlog, err := ParseXLogData(data)
+	require.NoError(t, err)
+	require.Equal(t, XLogData{
+		WALStart:   2,
+		WALEnd:     3,
+		ServerTime: serverTime,
+		Data:       []byte("Bpayload"),
+	}, xlog)
+	_, err = ParseXLogData(data[:20])
+	require.EqualError(t, err, "invalid XLogData message")
+
+	data = []byte{PrimaryKeepaliveByte}
+	data = appendUint64(data, 3)
+	data = appendUint64(data, uint64(PGMicros(serverTime)))
+	data = append(data, 1)
+	keepalive, err := ParsePrimaryKeepalive(data)
+	require.NoError(t, err)
+	require.Equal(t, PrimaryKeepalive{
+		WALEnd:         3,
+		ServerTime:     serverTime,
+		ReplyRequested: true,
+	}, keepalive)
+	_, err = ParsePrimaryKeepalive(data[:len(data)-1])
+	require.EqualError(t, err, "invalid primary keepalive message")
+}
+
+func TestParsePGOutputMessage(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	commitTime := time.Date(2021, time.June, 1, 12, 0, 0, 0, time.UTC)
+	micros := string(appendUint64(nil, uint64(PGMicros(commitTime))))
+	lsn := func(l LSN) string { return string(appendUint64(nil, uint64(l))) }
+	// The relation ID is 16384, and the tuples have the values 1 and 'a', 1
+	// and NULL, or 1 and an unchanged TOASTed value.
+	const rel = "\x00\x00\x40\x00"
+	const tuple = "\x00\x02t\x00\x00\x00\x011t\x00\x00\x00\x01a"
+	const nullTuple = "\x00\x02t\x00\x00\x00\x011n"
+	const toastTuple = "\x00\x02t\x00\x00\x00\x011u"
+	row := tree.Datums{tree.NewDString("1"), tree.NewDString("a")}
+
+	for _, tc := range []struct {
+		data string
+		msg  PGOutputMessage
+		err  string
+	}{
+		{
+			data: "B" + lsn(5) + micros + "\x00\x00\x00\x07",
+			msg:  &Begin{FinalLSN: 5, CommitTime: commitTime, XID: 7},
+		},
+		{
+			data: "C\x00" + lsn(5) + lsn(6) + micros,
+			msg:  &Commit{CommitLSN: 5, EndLSN: 6, CommitTime: commitTime},
+		},
+		{
+			data: "R" + rel + "public\x00t\x00d\x00\x02" +
+				"\x01k\x00\x00\x00\x00\x14\xff\xff\xff\xff" +
+				"\x00v\x00\x00\x00\x00\x19\xff\xff\xff\xff",
+			msg: &Relation{ID: 16384, Namespace: "public", Name: "t", Columns: []RelationColumn{
+				{Key: true, Name: "k", TypeOID: oid.T_int8, TypeMod: -1},
+				{Name: "v", TypeOID: oid.T_text, TypeMod: -1},
+			}},
+		},
+		{
+			data: "I" + rel + "N" + tuple,
+			msg:  &Insert{RelationID: 16384, New: row},
+		},
+		{
+			data: "U" + rel + "N" + toastTuple,
+			msg:  &Update{RelationID: 16384, New: tree.Datums{tree.NewDString("1"), nil}},
+		},
+		{
+			data: "U" + rel + "K" + nullTuple + "N" + tuple,
+			msg: &Update{
+				RelationID: 16384,
+				OldKey:     tree.Datums{tree.NewDString("1"), tree.DNull},
+				New:        row,
+			},
+		},
+		{
+			data: "D" + rel + "O" + tuple,
+			msg:  &Delete{RelationID: 16384, OldKey: row},
+		},
+		{
+			data: "T\x00\x00\x00\x02\x01" + rel + "\x00\x00\x40\x01",
+			msg:  &Truncate{RelationIDs: []oid.Oid{16384, 16385}, Cascade: true},
+		},
+		{
+			data: "Y" + rel + "public\x00t\x00",
+		},
+		{
+			data: "I" + rel + "K" + tuple,
+			err:  `invalid pgoutput message of type 'I'`,
+		},
+		{
+			data: "I" + rel + "N" + tuple[:len(tuple)-1],
+			err:  "invalid pgoutput message: message too short",
+		},
+		{
+			data: "I" + rel + "N\x00\x01b\x00\x00\x00\x01a",
+			err:  "invalid pgoutput message: unsupported tuple value kind 'b'",
+		},
+		{
+			data: "R" + rel + "public",
+			err:  "invalid pgoutput message: unterminated string",
+		},
+		{
+			data: "M",
+			err:  "unexpected pgoutput message type 'M'",
+		},
+	} {
+		msg, err := ParsePGOutputMessage([]byte(tc.data))
+		if tc.err != "" {
+			require.EqualError(t, err, tc.err)
+			continue
+		}
+		require.NoError(t, err)
+		require.Equal(t, tc.msg, msg)
+	}
+}
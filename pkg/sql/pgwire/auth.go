@@ -91,7 +91,7 @@ func (c *conn) handleAuthentication(
 	}
 
 	sendError := func(err error) error {
-		_ /* err */ = writeErr(ctx, &execCfg.Settings.SV, err, &c.msgBuilder, c.conn)
+		_ /* err */ = writeErr(ctx, &execCfg.Settings.SV, err, &c.msgBuilder, c.lockedWriter())
 		return err
 	}
 
@@ -217,7 +217,7 @@ func (c *conn) handleAuthentication(
 	ac.LogAuthOK(ctx)
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgAuth)
 	c.msgBuilder.putInt32(authOK)
-	return connClose, c.msgBuilder.finishMsg(c.conn)
+	return connClose, c.msgBuilder.finishMsg(c.lockedWriter())
 }
 
 // chooseDbRole uses the provided RoleMapper to map an incoming
@@ -530,5 +530,5 @@ func (p *authPipe) SendAuthRequest(authType int32, data []byte) error {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgAuth)
 	c.msgBuilder.putInt32(authType)
 	c.msgBuilder.write(data)
-	return c.msgBuilder.finishMsg(c.conn)
+	return c.msgBuilder.finishMsg(c.lockedWriter())
 }
@@ -318,6 +318,16 @@ func (r *commandResult) ResetStmtType(stmt tree.Statement) {
 	r.cmdCompleteTag = stmt.StatementTag()
 }
 
+// ResetForStatementRetry is part of the sql.RestrictedCommandResult interface.
+func (r *commandResult) ResetForStatementRetry() error {
+	r.assertNotReleased()
+	r.err = nil
+	r.rowsAffected = 0
+	r.buffer.notices = nil
+	r.buffer.paramStatusUpdates = nil
+	return nil
+}
+
 // release frees the commandResult and allows its memory to be reused.
 func (r *commandResult) release() {
 	*r = commandResult{released: true}
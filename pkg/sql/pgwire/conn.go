@@ -14,8 +14,11 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	crand "crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"net"
 	"strconv"
 	"sync"
@@ -28,6 +31,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
@@ -42,6 +46,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/netutil"
 	"github.com/cockroachdb/cockroach/pkg/util/ring"
+	"github.com/cockroachdb/cockroach/pkg/util/syncutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/errors"
 	"github.com/cockroachdb/logtags"
@@ -61,6 +66,13 @@ type conn struct {
 	sessionArgs sql.SessionArgs
 	metrics     *ServerMetrics
 
+	// backendPID and backendSecret are sent to the client in the
+	// BackendKeyData message. Sessions don't have a process ID, so random
+	// values are used; backendPID also identifies the session in the
+	// notifications it sends.
+	backendPID    int32
+	backendSecret int32
+
 	// startTime is the time when the connection attempt was first received
 	// by the server.
 	startTime time.Time
@@ -92,6 +104,13 @@ type conn struct {
 		// network connection.
 		buf    bytes.Buffer
 		tagBuf [64]byte
+		// connMu serializes the writes to the network connection which can
+		// happen concurrently with the command processing, namely asynchronous
+		// notifications. Each message is written with a single Write call, so
+		// holding the mutex around the writes prevents messages from being
+		// interleaved. All the writes go through lockedWriter(), which holds
+		// it.
+		connMu syncutil.Mutex
 	}
 
 	readBuf    pgwirebase.ReadBuffer
@@ -197,10 +216,42 @@ func newConn(
 	c.writerState.fi.buf = &c.writerState.buf
 	c.writerState.fi.lastFlushed = -1
 	c.msgBuilder.init(metrics.BytesOutCount)
+	c.backendPID, c.backendSecret = makeBackendKeyData()
 
 	return c
 }
 
+// makeBackendKeyData generates the process ID and the secret key of a
+// session. The process ID is positive, like in Postgres.
+func makeBackendKeyData() (pid int32, secret int32) {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		// crypto/rand doesn't fail on the supported platforms.
+		panic(errors.NewAssertionErrorWithWrappedErrf(err, "generating backend key data"))
+	}
+	pid = int32(binary.BigEndian.Uint32(b[:4])&math.MaxInt32) | 1
+	secret = int32(binary.BigEndian.Uint32(b[4:]))
+	return pid, secret
+}
+
+// lockedConnWriter is an io.Writer which writes to the network connection
+// while holding writerState.connMu.
+type lockedConnWriter conn
+
+// Write is part of the io.Writer interface.
+func (w *lockedConnWriter) Write(p []byte) (int, error) {
+	w.writerState.connMu.Lock()
+	defer w.writerState.connMu.Unlock()
+	return w.conn.Write(p)
+}
+
+// lockedWriter returns the writer through which all the writes to the network
+// connection must go, so that they are serialized with the asynchronous
+// notifications.
+func (c *conn) lockedWriter() io.Writer {
+	return (*lockedConnWriter)(c)
+}
+
 func (c *conn) setErr(err error) {
 	c.err.Store(err)
 }
@@ -555,7 +606,7 @@ func (c *conn) serveImpl(
 		log.Ops.Info(ctx, "closing existing connection while server is draining")
 		_ /* err */ = writeErr(ctx, &sqlServer.GetExecutorConfig().Settings.SV,
 			newAdminShutdownErr(ErrDrainingExistingConn), &c.msgBuilder, &c.writerState.buf)
-		_ /* n */, _ /* err */ = c.writerState.buf.WriteTo(c.conn)
+		_ /* n */, _ /* err */ = c.writerState.buf.WriteTo(c.lockedWriter())
 	}
 }
 
@@ -624,7 +675,7 @@ func (c *conn) processCommandsAsync(
 					_ = writeErr(
 						ctx, &sqlServer.GetExecutorConfig().Settings.SV, retErr,
 						&c.msgBuilder, &c.writerState.buf)
-					_ /* n */, _ /* err */ = c.writerState.buf.WriteTo(c.conn)
+					_ /* n */, _ /* err */ = c.writerState.buf.WriteTo(c.lockedWriter())
 					c.stmtBuf.Close()
 					// Send a ready for query to make sure the client can react.
 					// TODO(andrei, jordan): Why are we sending this exactly?
@@ -672,7 +723,7 @@ func (c *conn) sendParamStatus(param, value string) error {
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgParameterStatus)
 	c.msgBuilder.writeTerminatedString(param)
 	c.msgBuilder.writeTerminatedString(value)
-	return c.msgBuilder.finishMsg(c.conn)
+	return c.msgBuilder.finishMsg(c.lockedWriter())
 }
 
 func (c *conn) bufferParamStatus(param, value string) error {
@@ -700,7 +751,7 @@ func (c *conn) sendInitialConnData(
 	)
 	if err != nil {
 		_ /* err */ = writeErr(
-			ctx, &sqlServer.GetExecutorConfig().Settings.SV, err, &c.msgBuilder, c.conn)
+			ctx, &sqlServer.GetExecutorConfig().Settings.SV, err, &c.msgBuilder, c.lockedWriter())
 		return sql.ConnectionHandler{}, err
 	}
 
@@ -729,25 +780,26 @@ func (c *conn) sendInitialConnData(
 }
 
 // sendReadyForQuery sends the final messages of the connection handshake.
-// This includes a placeholder BackendKeyData message and a ServerMsgReady
-// message indicating that there is no active transaction.
+// This includes a BackendKeyData message and a ServerMsgReady message
+// indicating that there is no active transaction.
 func (c *conn) sendReadyForQuery() error {
-	// Send the client a dummy BackendKeyData message. This is necessary for
+	// Send the client the BackendKeyData message. This is necessary for
 	// compatibility with tools that require this message. This information is
 	// normally used by clients to send a CancelRequest message:
 	// https://www.postgresql.org/docs/9.6/static/protocol-flow.html#AEN112861
-	// CockroachDB currently ignores all CancelRequests.
+	// CockroachDB currently ignores all CancelRequests. The process ID is also
+	// reported in the notifications sent by the session.
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgBackendKeyData)
-	c.msgBuilder.putInt32(0)
-	c.msgBuilder.putInt32(0)
-	if err := c.msgBuilder.finishMsg(c.conn); err != nil {
+	c.msgBuilder.putInt32(c.backendPID)
+	c.msgBuilder.putInt32(c.backendSecret)
+	if err := c.msgBuilder.finishMsg(c.lockedWriter()); err != nil {
 		return err
 	}
 
 	// An initial ServerMsgReady message is part of the handshake.
 	c.msgBuilder.initMsg(pgwirebase.ServerMsgReady)
 	c.msgBuilder.writeByte(byte(sql.IdleTxnBlock))
-	if err := c.msgBuilder.finishMsg(c.conn); err != nil {
+	if err := c.msgBuilder.finishMsg(c.lockedWriter()); err != nil {
 		return err
 	}
 	return nil
@@ -766,6 +818,18 @@ func (c *conn) handleSimpleQuery(
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
 	}
 
+	if c.sessionArgs.Replication {
+		// Replication connections accept the commands of the replication
+		// protocol in addition to SQL statements.
+		cmd, err := pgrepl.ParseCommand(query)
+		if err != nil {
+			return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+		}
+		if cmd != nil {
+			return c.handleReplicationCommand(ctx, cmd, timeReceived, unqualifiedIntSize)
+		}
+	}
+
 	startParse := timeutil.Now()
 	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
 	if err != nil {
@@ -808,14 +872,23 @@ func (c *conn) handleSimpleQuery(
 			copyDone.Wait()
 			return nil
 		}
+		// The CopyTo statement doesn't take control of the connection; its rows
+		// are sent to the client through the result of the CopyOut command.
+		if cp, ok := stmts[i].AST.(*tree.CopyTo); ok {
+			if err := c.stmtBuf.Push(ctx, sql.CopyOut{Stmt: cp}); err != nil {
+				return err
+			}
+			continue
+		}
 
 		if err := c.stmtBuf.Push(
 			ctx,
 			sql.ExecStmt{
-				Statement:    stmts[i],
-				TimeReceived: timeReceived,
-				ParseStart:   startParse,
-				ParseEnd:     endParse,
+				Statement:      stmts[i],
+				TimeReceived:   timeReceived,
+				ParseStart:     startParse,
+				ParseEnd:       endParse,
+				MultiStatement: len(stmts) > 1,
 			}); err != nil {
 			return err
 		}
@@ -912,6 +985,11 @@ func (c *conn) handleParse(
 		// https://www.postgresql.org/message-id/flat/CAMsr%2BYGvp2wRx9pPSxaKFdaObxX8DzWse%2BOkWk2xpXSvT0rq-g%40mail.gmail.com#CAMsr+YGvp2wRx9pPSxaKFdaObxX8DzWse+OkWk2xpXSvT0rq-g@mail.gmail.com
 		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyFrom not supported in extended protocol mode")})
 	}
+	if _, ok := stmt.AST.(*tree.CopyTo); ok {
+		// Like COPY FROM, COPY TO is executed by a dedicated command that is only
+		// created by the simple protocol.
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: fmt.Errorf("CopyTo not supported in extended protocol mode")})
+	}
 
 	return c.stmtBuf.Push(
 		ctx,
@@ -1131,7 +1209,31 @@ func (c *conn) BeginCopyIn(
 	for range columns {
 		c.msgBuilder.putInt16(int16(format))
 	}
-	return c.msgBuilder.finishMsg(c.conn)
+	return c.msgBuilder.finishMsg(c.lockedWriter())
+}
+
+// BackendPID is part of the sql.NotificationSender interface.
+func (c *conn) BackendPID() int32 {
+	return c.backendPID
+}
+
+// SendNotification is part of the sql.NotificationSender interface.
+func (c *conn) SendNotification(pid int32, channel, payload string) error {
+	if err := c.GetErr(); err != nil {
+		return err
+	}
+	// The message is built in its own buffer since c.msgBuilder is used by the
+	// command processor.
+	b := newWriteBuffer(c.metrics.BytesOutCount)
+	b.initMsg(pgwirebase.ServerMsgNotificationResponse)
+	b.putInt32(pid)
+	b.writeTerminatedString(channel)
+	b.writeTerminatedString(payload)
+	if err := b.finishMsg(c.lockedWriter()); err != nil {
+		c.setErr(err)
+		return err
+	}
+	return nil
 }
 
 // SendCommandComplete is part of the pgwirebase.Conn interface.
@@ -1239,8 +1341,12 @@ func cookTag(
 		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 
 	case tree.Rows:
-		tag = append(tag, ' ')
-		tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
+		// As in Postgres, the tag of CALL does not include the number of rows
+		// returned by the procedure.
+		if tagStr != "CALL" {
+			tag = append(tag, ' ')
+			tag = strconv.AppendUint(tag, uint64(rowsAffected), 10)
+		}
 
 	case tree.Ack, tree.DDL:
 		if tagStr == "SELECT" {
@@ -1252,6 +1358,10 @@ func cookTag(
 		// Nothing to do. The CommandComplete message has been sent elsewhere.
 		panic(errors.AssertionFailedf("CopyIn statements should have been handled elsewhere " +
 			"and not produce results"))
+
+	case tree.CopyOut:
+		tag = append(tag, ' ')
+		tag = strconv.AppendInt(tag, int64(rowsAffected), 10)
 	default:
 		panic(errors.AssertionFailedf("unexpected result type %v", stmtType))
 	}
@@ -1532,7 +1642,7 @@ func (c *conn) Flush(pos sql.CmdPos) error {
 	// Make sure that the entire cmdStarts buffer is drained.
 	c.writerState.fi.cmdStarts.clear()
 
-	_ /* n */, err := c.writerState.buf.WriteTo(c.conn)
+	_ /* n */, err := c.writerState.buf.WriteTo(c.lockedWriter())
 	if err != nil {
 		c.setErr(err)
 		return err
@@ -1664,6 +1774,40 @@ func (c *conn) CreateCopyInResult(pos sql.CmdPos) sql.CopyInResult {
 	return c.newMiscResult(pos, noCompletionMsg)
 }
 
+// CreateCopyOutResult is part of the sql.ClientComm interface.
+func (c *conn) CreateCopyOutResult(
+	pos sql.CmdPos, conv sessiondatapb.DataConversionConfig, location *time.Location,
+) sql.CopyOutResult {
+	r := c.allocCommandResult()
+	*r = commandResult{
+		conn:           c,
+		conv:           conv,
+		location:       location,
+		pos:            pos,
+		typ:            commandComplete,
+		cmdCompleteTag: "COPY",
+		stmtType:       tree.CopyOut,
+	}
+	return &copyOutResult{commandResult: r}
+}
+
+// CreateReplicationResult is part of the sql.ClientComm interface.
+func (c *conn) CreateReplicationResult(
+	pos sql.CmdPos, conv sessiondatapb.DataConversionConfig, location *time.Location,
+) sql.ReplicationResult {
+	r := c.allocCommandResult()
+	*r = commandResult{
+		conn:           c,
+		conv:           conv,
+		location:       location,
+		pos:            pos,
+		typ:            commandComplete,
+		cmdCompleteTag: "START_REPLICATION",
+		stmtType:       tree.Ack,
+	}
+	return &replicationResult{commandResult: r}
+}
+
 // pgwireReader is an io.Reader that wraps a conn, maintaining its metrics as
 // it is consumed.
 type pgwireReader struct {
@@ -1625,7 +1625,7 @@ func TestSetSessionArguments(t *testing.T) {
 
 // TestCancelQuery uses the pgwire-level query cancellation protocol provided
 // by lib/pq to make sure that canceling a query has no effect, and makes sure
-// the dummy BackendKeyData does not cause problems.
+// the BackendKeyData does not cause problems.
 func TestCancelQuery(t *testing.T) {
 	defer leaktest.AfterTest(t)()
 	defer log.Scope(t).Close(t)
@@ -0,0 +1,221 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+// copyBinaryHeader is the header of the binary COPY format: the signature,
+// followed by the 32-bit flags field and the 32-bit header extension length,
+// both of which are zero.
+var copyBinaryHeader = []byte("PGCOPY\n\377\r\n\000\000\000\000\000\000\000\000\000")
+
+// copyOutResult is a commandResult that implements the Copy-out subprotocol
+// (COPY ... TO STDOUT). Each row is sent as a CopyData message, encoded in the
+// format requested by the statement, and the copy is finished with a CopyDone
+// message followed by the CommandComplete message when the result is closed.
+type copyOutResult struct {
+	*commandResult
+	opts sql.CopyOutOptions
+
+	// began is set once the CopyOutResponse message has been sent.
+	began bool
+	// scratch is used to build the text encoding of values before they are
+	// escaped into the CopyData messages.
+	scratch *writeBuffer
+}
+
+var _ sql.CopyOutResult = &copyOutResult{}
+
+// BeginCopyOut is part of the sql.CopyOutResult interface.
+func (r *copyOutResult) BeginCopyOut(
+	ctx context.Context, cols colinfo.ResultColumns, opts sql.CopyOutOptions,
+) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.GetErr(); err != nil {
+		return err
+	}
+	r.opts = opts
+	r.scratch = newWriteBuffer(r.conn.metrics.BytesOutCount)
+	r.types = make([]*types.T, len(cols))
+	for i, col := range cols {
+		r.types[i] = col.Typ
+	}
+
+	format := pgwirebase.FormatText
+	if opts.Format == tree.CopyFormatBinary {
+		format = pgwirebase.FormatBinary
+	}
+	b := &r.conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyOutResponse)
+	b.writeByte(byte(format))
+	b.putInt16(int16(len(cols)))
+	for range cols {
+		b.putInt16(int16(format))
+	}
+	if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+		return err
+	}
+	r.began = true
+
+	switch {
+	case opts.Format == tree.CopyFormatBinary:
+		b.initMsg(pgwirebase.ServerMsgCopyData)
+		b.write(copyBinaryHeader)
+		return b.finishMsg(&r.conn.writerState.buf)
+	case opts.Header:
+		b.initMsg(pgwirebase.ServerMsgCopyData)
+		for i, col := range cols {
+			if i > 0 {
+				b.writeByte(r.opts.Delimiter)
+			}
+			r.writeCSVValue([]byte(col.Name))
+		}
+		b.writeByte('\n')
+		return b.finishMsg(&r.conn.writerState.buf)
+	}
+	return nil
+}
+
+// AddRow is part of the sql.RestrictedCommandResult interface.
+func (r *copyOutResult) AddRow(ctx context.Context, row tree.Datums) error {
+	if !r.began {
+		return errors.AssertionFailedf("AddRow called before BeginCopyOut")
+	}
+	return r.addInternal(func() {
+		r.rowsAffected++
+		r.bufferCopyData(ctx, row)
+	})
+}
+
+// SupportsAddBatch is part of the sql.RestrictedCommandResult interface.
+func (r *copyOutResult) SupportsAddBatch() bool {
+	return false
+}
+
+// Close is part of the sql.CommandResultClose interface.
+func (r *copyOutResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
+	r.assertNotReleased()
+	// On error, the client expects the ErrorResponse message instead of
+	// CopyDone.
+	if r.began && r.err == nil {
+		b := &r.conn.msgBuilder
+		if r.opts.Format == tree.CopyFormatBinary {
+			// The binary format ends with a field count of -1.
+			b.initMsg(pgwirebase.ServerMsgCopyData)
+			b.putInt16(-1)
+			if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+				panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+			}
+		}
+		b.initMsg(pgwirebase.ServerMsgCopyDone)
+		if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	r.commandResult.Close(ctx, t)
+}
+
+// bufferCopyData serializes a row into a CopyData message and adds it to the
+// buffer.
+func (r *copyOutResult) bufferCopyData(ctx context.Context, row tree.Datums) {
+	b := &r.conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyData)
+	if r.opts.Format == tree.CopyFormatBinary {
+		b.putInt16(int16(len(row)))
+		for i, d := range row {
+			b.writeBinaryDatum(ctx, d, r.location, r.types[i])
+		}
+	} else {
+		for i, d := range row {
+			if i > 0 {
+				b.writeByte(r.opts.Delimiter)
+			}
+			if d == tree.DNull {
+				b.writeString(r.opts.Null)
+				continue
+			}
+			r.scratch.reset()
+			writeTextDatumNotNull(r.scratch, d, r.conv, r.location, encodingType(r.types[i]))
+			// Skip the length prefix written by writeTextDatumNotNull.
+			v := r.scratch.wrapped.Bytes()[4:]
+			if r.opts.Format == tree.CopyFormatCSV {
+				r.writeCSVValue(v)
+			} else {
+				r.writeTextValue(v)
+			}
+		}
+		b.writeByte('\n')
+	}
+	if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+		panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+	}
+}
+
+// writeTextValue writes v to the message being built, escaping the characters
+// that have a special meaning in the text format.
+func (r *copyOutResult) writeTextValue(v []byte) {
+	b := &r.conn.msgBuilder
+	for _, c := range v {
+		switch c {
+		case '\\':
+			b.writeString(`\\`)
+		case '\b':
+			b.writeString(`\b`)
+		case '\f':
+			b.writeString(`\f`)
+		case '\n':
+			b.writeString(`\n`)
+		case '\r':
+			b.writeString(`\r`)
+		case '\t':
+			b.writeString(`\t`)
+		case '\v':
+			b.writeString(`\v`)
+		default:
+			if c == r.opts.Delimiter {
+				b.writeByte('\\')
+			}
+			b.writeByte(c)
+		}
+	}
+}
+
+// writeCSVValue writes v to the message being built, quoting it if it could
+// otherwise be mistaken for a NULL, the end-of-data marker, or if it contains
+// a delimiter, quote or newline.
+func (r *copyOutResult) writeCSVValue(v []byte) {
+	b := &r.conn.msgBuilder
+	quote := string(v) == r.opts.Null || string(v) == `\.` ||
+		bytes.IndexByte(v, r.opts.Delimiter) >= 0 || bytes.ContainsAny(v, "\"\r\n")
+	if !quote {
+		b.write(v)
+		return
+	}
+	b.writeByte('"')
+	for _, c := range v {
+		if c == '"' {
+			b.writeByte('"')
+		}
+		b.writeByte(c)
+	}
+	b.writeByte('"')
+}
@@ -561,7 +561,7 @@ func TestPGPreparedQuery(t *testing.T) {
 				Results("users", "primary", false, 3, "isRole", "N/A", true, false),
 		}},
 		{"SHOW TABLES FROM system", []preparedQueryTest{
-			baseTest.Results("public", "comments", "table", gosql.NullString{}, 0, gosql.NullString{}).Others(35),
+			baseTest.Results("public", "comments", "table", gosql.NullString{}, 0, gosql.NullString{}).Others(37),
 		}},
 		{"SHOW SCHEMAS FROM system", []preparedQueryTest{
 			baseTest.Results("crdb_internal", gosql.NullString{}).Others(4),
@@ -2027,3 +2027,74 @@ func TestFailPrepareFailsTxn(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestPGNotifications checks that the notifications sent with NOTIFY are
+// delivered to the sessions listening on their channel once the sending
+// transaction commits.
+func TestPGNotifications(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	pgURL, cleanupFn := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanupFn()
+	conn, err := pgx.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	// waitForNotification returns the process ID of the notifying session.
+	waitForNotification := func(channel, payload string) uint32 {
+		t.Helper()
+		waitCtx, cancel := context.WithTimeout(ctx, testutils.DefaultSucceedsSoonDuration)
+		defer cancel()
+		n, err := conn.WaitForNotification(waitCtx)
+		require.NoError(t, err)
+		require.Equal(t, channel, n.Channel)
+		require.Equal(t, payload, n.Payload)
+		return n.PID
+	}
+
+	_, err = conn.Exec(ctx, "LISTEN foo")
+	require.NoError(t, err)
+
+	// The notifications are only delivered if the transaction commits.
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	_, err = tx.Exec("NOTIFY foo, 'rolled back'")
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback())
+	_, err = db.Exec("NOTIFY bar, 'other channel'")
+	require.NoError(t, err)
+	_, err = db.Exec("NOTIFY foo, 'committed'")
+	require.NoError(t, err)
+	otherPID := waitForNotification("foo", "committed")
+
+	// The notifications report the process ID sent by the notifying session in
+	// its BackendKeyData message.
+	require.NotZero(t, conn.PgConn().PID())
+	_, err = conn.Exec(ctx, "NOTIFY foo, 'self'")
+	require.NoError(t, err)
+	require.Equal(t, conn.PgConn().PID(), waitForNotification("foo", "self"))
+	require.NotEqual(t, conn.PgConn().PID(), otherPID)
+
+	// UNLISTEN only takes effect once the transaction commits.
+	_, err = conn.Exec(ctx, "BEGIN; UNLISTEN foo; ROLLBACK; LISTEN bar")
+	require.NoError(t, err)
+	_, err = db.Exec("NOTIFY foo, 'still listening'")
+	require.NoError(t, err)
+	waitForNotification("foo", "still listening")
+
+	_, err = conn.Exec(ctx, "UNLISTEN foo")
+	require.NoError(t, err)
+	_, err = db.Exec("NOTIFY foo, 'not listening'")
+	require.NoError(t, err)
+	_, err = db.Exec("NOTIFY bar")
+	require.NoError(t, err)
+	waitForNotification("bar", "")
+
+	_, err = db.Exec(fmt.Sprintf("NOTIFY foo, '%s'", strings.Repeat("a", 8000)))
+	require.True(t, testutils.IsError(err, "payload string too long"), "unexpected error: %v", err)
+}
@@ -33,8 +33,10 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/errors"
 	"github.com/dustin/go-humanize"
@@ -302,6 +304,10 @@ func validateArrayDimensions(nDimensions int, nElements int) error {
 func DecodeDatum(
 	evalCtx *tree.EvalContext, t *types.T, code FormatCode, b []byte,
 ) (tree.Datum, error) {
+	if t.IsDomain() {
+		// Values of a domain are encoded as values of its base type.
+		t = t.DomainBaseType()
+	}
 	id := t.Oid()
 	switch code {
 	case FormatText:
@@ -482,6 +488,35 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsquery:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSQuery(string(b))
+		case oid.T_tsvector:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDTSVector(string(b))
+		case oidext.T_jsonpath:
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			return tree.ParseDJsonpath(string(b))
+		}
+		if t.Family() == types.RangeFamily {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			r, _, err := tree.ParseDRangeFromString(evalCtx, string(b), t)
+			return r, err
+		}
+		if t.IsComposite() {
+			if err := validateStringBytes(b); err != nil {
+				return nil, err
+			}
+			d, _, err := tree.ParseDTupleFromString(evalCtx, string(b), t)
+			return d, err
 		}
 		if t.Family() == types.ArrayFamily {
 			// Arrays come in in their string form, so we parse them as such and later
@@ -729,6 +764,24 @@ func DecodeDatum(
 				return nil, err
 			}
 			return tree.ParseDJSON(string(b))
+		case oid.T_tsquery:
+			q, err := tsearch.DecodeTSQuery(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("invalid tsquery: %v", err)
+			}
+			return tree.NewDTSQuery(q), nil
+		case oid.T_tsvector:
+			v, err := tsearch.DecodeTSVector(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("invalid tsvector: %v", err)
+			}
+			return tree.NewDTSVector(v), nil
+		case oidext.T_jsonpath:
+			p, err := jsonpath.DecodePath(b)
+			if err != nil {
+				return nil, NewInvalidBinaryRepresentationErrorf("invalid jsonpath: %v", err)
+			}
+			return tree.NewDJsonpath(p), nil
 		case oid.T_varbit, oid.T_bit:
 			if len(b) < 4 {
 				return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
@@ -768,6 +821,12 @@ func DecodeDatum(
 			if t.Family() == types.ArrayFamily {
 				return decodeBinaryArray(evalCtx, t.ArrayContents(), b, code)
 			}
+			if t.Family() == types.RangeFamily {
+				return decodeBinaryRange(evalCtx, t, b)
+			}
+			if t.IsComposite() {
+				return decodeBinaryTuple(evalCtx, t, b)
+			}
 		}
 	default:
 		return nil, errors.AssertionFailedf(
@@ -977,6 +1036,11 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Da
 
 	totalLength := int32(len(b))
 	numberOfElements := int32(binary.BigEndian.Uint32(b[0:4]))
+	// The values of a composite type must have all of its attributes.
+	if t.IsComposite() && int(numberOfElements) != len(t.TupleContents()) {
+		return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+			"wrong number of columns: %d, expected %d", numberOfElements, len(t.TupleContents()))
+	}
 	typs := make([]*types.T, numberOfElements)
 	datums := make(tree.Datums, numberOfElements)
 	curByte := int32(4)
@@ -990,6 +1054,13 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Da
 
 		elementOID := int32(binary.BigEndian.Uint32(b[curByte : curByte+4]))
 		elementType := types.OidToType[oid.Oid(elementOID)]
+		if t.IsComposite() {
+			elementType = t.TupleContents()[curIdx]
+			if oid.Oid(elementOID) != elementType.Oid() {
+				return nil, pgerror.Newf(pgcode.DatatypeMismatch,
+					"wrong data type: %d, expected %d", elementOID, elementType.Oid())
+			}
+		}
 		typs[curIdx] = elementType
 		curByte = curByte + 4
 
@@ -1020,6 +1091,9 @@ func decodeBinaryTuple(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Da
 	}
 
 	tupleTyps := types.MakeTuple(typs)
+	if t.IsComposite() {
+		tupleTyps = t
+	}
 	return tree.NewDTuple(tupleTyps, datums...), nil
 
 }
@@ -1039,3 +1113,57 @@ const (
 	// AF_NET + 1.
 	PGBinaryIPv6family byte = 3
 )
+
+// These are the flags of the pgwire binary representation of ranges. See
+// https://github.com/postgres/postgres/blob/master/src/include/utils/rangetypes.h.
+const (
+	// PGBinaryRangeEmpty is set for the empty range.
+	PGBinaryRangeEmpty byte = 0x01
+	// PGBinaryRangeLowerInclusive is set if the lower bound is inclusive.
+	PGBinaryRangeLowerInclusive byte = 0x02
+	// PGBinaryRangeUpperInclusive is set if the upper bound is inclusive.
+	PGBinaryRangeUpperInclusive byte = 0x04
+	// PGBinaryRangeLowerInfinite is set if the lower bound is -infinity.
+	PGBinaryRangeLowerInfinite byte = 0x08
+	// PGBinaryRangeUpperInfinite is set if the upper bound is +infinity.
+	PGBinaryRangeUpperInfinite byte = 0x10
+)
+
+// decodeBinaryRange decodes the pgwire binary representation of a range of
+// type t.
+func decodeBinaryRange(evalCtx *tree.EvalContext, t *types.T, b []byte) (tree.Datum, error) {
+	if len(b) < 1 {
+		return nil, NewProtocolViolationErrorf("insufficient data: %d", len(b))
+	}
+	flags := b[0]
+	if flags&PGBinaryRangeEmpty != 0 {
+		return tree.NewDEmptyRange(t), nil
+	}
+	r := bytes.NewBuffer(b[1:])
+	var lower, upper tree.RangeBound
+	for _, bound := range []struct {
+		b         *tree.RangeBound
+		infinite  bool
+		inclusive bool
+	}{
+		{&lower, flags&PGBinaryRangeLowerInfinite != 0, flags&PGBinaryRangeLowerInclusive != 0},
+		{&upper, flags&PGBinaryRangeUpperInfinite != 0, flags&PGBinaryRangeUpperInclusive != 0},
+	} {
+		if bound.infinite {
+			continue
+		}
+		var vlen int32
+		if err := binary.Read(r, binary.BigEndian, &vlen); err != nil {
+			return nil, err
+		}
+		if vlen < 0 || int(vlen) > r.Len() {
+			return nil, NewInvalidBinaryRepresentationErrorf("invalid range bound length: %d", vlen)
+		}
+		val, err := DecodeDatum(evalCtx, t.RangeContents(), FormatBinary, r.Next(int(vlen)))
+		if err != nil {
+			return nil, err
+		}
+		*bound.b = tree.RangeBound{Val: val, Inclusive: bound.inclusive}
+	}
+	return tree.NewDRange(evalCtx, t, lower, upper)
+}
@@ -0,0 +1,247 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+// The bytes starting the messages of the pgoutput plugin.
+const (
+	pgoutputBeginByte    = 'B'
+	pgoutputCommitByte   = 'C'
+	pgoutputRelationByte = 'R'
+	pgoutputInsertByte   = 'I'
+	pgoutputUpdateByte   = 'U'
+	pgoutputDeleteByte   = 'D'
+)
+
+// replicaIdentityDefault is the replica identity of all the tables, which
+// identifies the rows by their primary key.
+const replicaIdentityDefault = 'd'
+
+// replicationResult is a commandResult that implements the Copy-both
+// subprotocol used by START_REPLICATION. Each message is sent as a CopyData
+// message and flushed right away, and the stream is finished with a CopyDone
+// message followed by the CommandComplete message when the result is closed.
+type replicationResult struct {
+	*commandResult
+
+	// began is set once the CopyBothResponse message has been sent.
+	began bool
+}
+
+var _ sql.ReplicationResult = &replicationResult{}
+
+// BeginCopyBoth is part of the sql.ReplicationResult interface.
+func (r *replicationResult) BeginCopyBoth(ctx context.Context) error {
+	r.assertNotReleased()
+	r.conn.writerState.fi.registerCmd(r.pos)
+	if err := r.conn.GetErr(); err != nil {
+		return err
+	}
+	b := &r.conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyBothResponse)
+	b.writeByte(byte(pgwirebase.FormatText))
+	b.putInt16(0) // number of columns
+	if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+		return err
+	}
+	r.began = true
+	return r.conn.Flush(r.pos)
+}
+
+// SendXLogData is part of the sql.ReplicationResult interface.
+func (r *replicationResult) SendXLogData(
+	ctx context.Context, walStart, walEnd pgrepl.LSN, msg pgrepl.PGOutputMessage,
+) error {
+	if !r.began {
+		return errors.AssertionFailedf("SendXLogData called before BeginCopyBoth")
+	}
+	b := &r.conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyData)
+	b.writeByte(pgrepl.XLogDataByte)
+	b.putInt64(int64(walStart))
+	b.putInt64(int64(walEnd))
+	b.putInt64(pgrepl.PGMicros(timeutil.Now()))
+	switch m := msg.(type) {
+	case *pgrepl.Begin:
+		b.writeByte(pgoutputBeginByte)
+		b.putInt64(int64(m.FinalLSN))
+		b.putInt64(pgrepl.PGMicros(m.CommitTime))
+		b.putInt32(int32(m.XID))
+	case *pgrepl.Commit:
+		b.writeByte(pgoutputCommitByte)
+		b.writeByte(0) // flags
+		b.putInt64(int64(m.CommitLSN))
+		b.putInt64(int64(m.EndLSN))
+		b.putInt64(pgrepl.PGMicros(m.CommitTime))
+	case *pgrepl.Relation:
+		b.writeByte(pgoutputRelationByte)
+		b.putInt32(int32(m.ID))
+		b.writeTerminatedString(m.Namespace)
+		b.writeTerminatedString(m.Name)
+		b.writeByte(replicaIdentityDefault)
+		b.putInt16(int16(len(m.Columns)))
+		for _, col := range m.Columns {
+			var flags byte
+			if col.Key {
+				flags = 1
+			}
+			b.writeByte(flags)
+			b.writeTerminatedString(col.Name)
+			b.putInt32(int32(col.TypeOID))
+			b.putInt32(col.TypeMod)
+		}
+	case *pgrepl.Insert:
+		b.writeByte(pgoutputInsertByte)
+		b.putInt32(int32(m.RelationID))
+		b.writeByte('N')
+		r.writeTuple(m.New)
+	case *pgrepl.Update:
+		b.writeByte(pgoutputUpdateByte)
+		b.putInt32(int32(m.RelationID))
+		b.writeByte('N')
+		r.writeTuple(m.New)
+	case *pgrepl.Delete:
+		b.writeByte(pgoutputDeleteByte)
+		b.putInt32(int32(m.RelationID))
+		b.writeByte('K')
+		r.writeTuple(m.OldKey)
+	default:
+		return errors.AssertionFailedf("unexpected pgoutput message %T", msg)
+	}
+	if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+		return err
+	}
+	return r.conn.Flush(r.pos)
+}
+
+// writeTuple writes the values of a row, in the text format.
+func (r *replicationResult) writeTuple(row tree.Datums) {
+	b := &r.conn.msgBuilder
+	b.putInt16(int16(len(row)))
+	for _, d := range row {
+		if d == tree.DNull {
+			b.writeByte('n')
+			continue
+		}
+		b.writeByte('t')
+		writeTextDatumNotNull(b, d, r.conv, r.location, encodingType(d.ResolvedType()))
+	}
+}
+
+// SendKeepalive is part of the sql.ReplicationResult interface.
+func (r *replicationResult) SendKeepalive(
+	ctx context.Context, walEnd pgrepl.LSN, replyRequested bool,
+) error {
+	if !r.began {
+		return errors.AssertionFailedf("SendKeepalive called before BeginCopyBoth")
+	}
+	b := &r.conn.msgBuilder
+	b.initMsg(pgwirebase.ServerMsgCopyData)
+	b.writeByte(pgrepl.PrimaryKeepaliveByte)
+	b.putInt64(int64(walEnd))
+	b.putInt64(pgrepl.PGMicros(timeutil.Now()))
+	var reply byte
+	if replyRequested {
+		reply = 1
+	}
+	b.writeByte(reply)
+	if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+		return err
+	}
+	return r.conn.Flush(r.pos)
+}
+
+// Close is part of the sql.CommandResultClose interface.
+func (r *replicationResult) Close(ctx context.Context, t sql.TransactionStatusIndicator) {
+	r.assertNotReleased()
+	// On error, the client expects the ErrorResponse message instead of
+	// CopyDone.
+	if r.began && r.err == nil {
+		b := &r.conn.msgBuilder
+		b.initMsg(pgwirebase.ServerMsgCopyDone)
+		if err := b.finishMsg(&r.conn.writerState.buf); err != nil {
+			panic(errors.AssertionFailedf("unexpected err from buffer: %s", err))
+		}
+	}
+	// The result is released by commandResult.Close.
+	c, pos := r.conn, r.pos
+	r.commandResult.Close(ctx, t)
+	// The network routine might still be blocked until the client ends the
+	// stream, so the results are flushed right away rather than when the
+	// ReadyForQuery message is sent.
+	_ = c.Flush(pos)
+}
+
+// handleReplicationCommand handles a command of the replication protocol
+// received by a replication connection. START_REPLICATION hands control of
+// the connection to the connExecutor, like COPY FROM, and blocks until the
+// client ends the stream. The other commands are executed as the equivalent
+// SQL statements, whose results have the columns of the results of the
+// commands in PostgreSQL.
+func (c *conn) handleReplicationCommand(
+	ctx context.Context, cmd pgrepl.Command, timeReceived time.Time, unqualifiedIntSize *types.T,
+) error {
+	var query string
+	switch cmd := cmd.(type) {
+	case *pgrepl.IdentifySystem:
+		query = `SELECT crdb_internal.cluster_id()::STRING AS systemid, 1:::INT4 AS timeline, ` +
+			`pg_current_wal_lsn() AS xlogpos, current_database() AS dbname`
+	case *pgrepl.CreateReplicationSlot:
+		// Snapshots can't be exported. Instead, the transaction executing the
+		// command reads the database at the consistent point of the slot.
+		query = fmt.Sprintf(
+			`SELECT slot_name::STRING AS slot_name, lsn AS consistent_point, `+
+				`NULL::STRING AS snapshot_name, %[2]s AS output_plugin `+
+				`FROM pg_create_logical_replication_slot(%[1]s, %[2]s, %[3]t)`,
+			lexbase.EscapeSQLString(cmd.SlotName), lexbase.EscapeSQLString(cmd.Plugin), cmd.Temporary,
+		)
+	case *pgrepl.DropReplicationSlot:
+		query = fmt.Sprintf(`SELECT pg_drop_replication_slot(%s)`, lexbase.EscapeSQLString(cmd.SlotName))
+	case *pgrepl.StartReplication:
+		done := sync.WaitGroup{}
+		done.Add(1)
+		if err := c.stmtBuf.Push(ctx, sql.StartReplication{Cmd: cmd, Conn: c, Done: &done}); err != nil {
+			return err
+		}
+		done.Wait()
+		return nil
+	default:
+		return errors.AssertionFailedf("unexpected replication command %T", cmd)
+	}
+
+	startParse := timeutil.Now()
+	stmts, err := c.parser.ParseWithInt(query, unqualifiedIntSize)
+	if err != nil {
+		return c.stmtBuf.Push(ctx, sql.SendError{Err: err})
+	}
+	endParse := timeutil.Now()
+	return c.stmtBuf.Push(ctx, sql.ExecStmt{
+		Statement:    stmts[0],
+		TimeReceived: timeReceived,
+		ParseStart:   startParse,
+		ParseEnd:     endParse,
+	})
+}
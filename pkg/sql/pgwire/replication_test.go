@@ -0,0 +1,169 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package pgwire_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLogicalReplication streams the changes of a table through a
+// replication connection, and checks the pgoutput messages received.
+func TestLogicalReplication(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{})
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	sqlDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	sqlDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	sqlDB.Exec(t, `CREATE TABLE u (k INT PRIMARY KEY)`)
+	sqlDB.Exec(t, `CREATE PUBLICATION p FOR TABLE t`)
+
+	pgURL, cleanup := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+	defer cleanup()
+	pgURL.Path = "defaultdb"
+	q := pgURL.Query()
+	q.Set("replication", "database")
+	pgURL.RawQuery = q.Encode()
+	conn, err := pgconn.Connect(ctx, pgURL.String())
+	require.NoError(t, err)
+	defer func() { _ = conn.Close(ctx) }()
+
+	res, err := conn.Exec(ctx, "IDENTIFY_SYSTEM").ReadAll()
+	require.NoError(t, err)
+	require.Len(t, res[0].Rows, 1)
+	require.Equal(t, "defaultdb", string(res[0].Rows[0][3]))
+
+	res, err = conn.Exec(ctx, "CREATE_REPLICATION_SLOT s LOGICAL pgoutput NOEXPORT_SNAPSHOT").ReadAll()
+	require.NoError(t, err)
+	require.Len(t, res[0].Rows, 1)
+	require.Equal(t, "s", string(res[0].Rows[0][0]))
+	require.Equal(t, "pgoutput", string(res[0].Rows[0][3]))
+
+	// SQL statements are accepted too.
+	res, err = conn.Exec(ctx, "SELECT slot_name FROM pg_replication_slots").ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "s", string(res[0].Rows[0][0]))
+
+	sqlDB.Exec(t, `INSERT INTO t VALUES (1, 'a')`)
+	sqlDB.Exec(t, `INSERT INTO u VALUES (1)`)
+	sqlDB.Exec(t, `UPDATE t SET v = NULL WHERE k = 1`)
+	sqlDB.Exec(t, `DELETE FROM t WHERE k = 1`)
+
+	require.NoError(t, conn.SendBytes(ctx, (&pgproto3.Query{
+		String: `START_REPLICATION SLOT s LOGICAL 0/0 (proto_version '1', publication_names 'p')`,
+	}).Encode(nil)))
+	msg, err := conn.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &pgproto3.CopyBothResponse{}, msg)
+
+	// Collect the pgoutput messages until the third transaction commits.
+	var kinds []byte
+	var tuples []string
+	for commits := 0; commits < 3; {
+		recvCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		msg, err := conn.ReceiveMessage(recvCtx)
+		cancel()
+		require.NoError(t, err)
+		data := msg.(*pgproto3.CopyData).Data
+		if data[0] == pgrepl.PrimaryKeepaliveByte {
+			continue
+		}
+		require.Equal(t, byte(pgrepl.XLogDataByte), data[0])
+		body := data[25:]
+		kinds = append(kinds, body[0])
+		switch body[0] {
+		case 'C':
+			commits++
+		case 'I', 'U', 'D':
+			tuples = append(tuples, decodeTuple(t, body[6:]))
+		case 'R':
+			require.Contains(t, string(body), "public\x00t\x00")
+		}
+	}
+	require.Equal(t, "BRICBUCBDC", string(kinds))
+	require.Equal(t, []string{"1,a", "1,NULL", "1,NULL"}, tuples)
+
+	// Confirm the changes, and end the stream.
+	status := make([]byte, 34)
+	status[0] = pgrepl.StandbyStatusUpdateByte
+	binary.BigEndian.PutUint64(status[9:], uint64(pgrepl.LSNFromTimestamp(s.Clock().Now())))
+	binary.BigEndian.PutUint64(status[25:], uint64(pgrepl.PGMicros(timeutil.Now())))
+	require.NoError(t, conn.SendBytes(ctx, (&pgproto3.CopyData{Data: status}).Encode(nil)))
+	require.NoError(t, conn.SendBytes(ctx, (&pgproto3.CopyDone{}).Encode(nil)))
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		require.NoError(t, err)
+		if _, ok := msg.(*pgproto3.CopyDone); ok {
+			break
+		}
+	}
+	msg, err = conn.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "START_REPLICATION", string(msg.(*pgproto3.CommandComplete).CommandTag))
+	msg, err = conn.ReceiveMessage(ctx)
+	require.NoError(t, err)
+	require.IsType(t, &pgproto3.ReadyForQuery{}, msg)
+
+	// The confirmed position is persisted when the slot is released.
+	sqlDB.CheckQueryResults(t,
+		`SELECT active, confirmed_flush_lsn::STRING != '0/0' FROM pg_replication_slots`,
+		[][]string{{"false", "true"}},
+	)
+	_, err = conn.Exec(ctx, "DROP_REPLICATION_SLOT s").ReadAll()
+	require.NoError(t, err)
+	sqlDB.CheckQueryResults(t, `SELECT count(*) FROM pg_replication_slots`, [][]string{{"0"}})
+}
+
+// decodeTuple decodes the tuple of a pgoutput message, returning its values
+// separated by commas.
+func decodeTuple(t *testing.T, b []byte) string {
+	n := int(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	var s string
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			s += ","
+		}
+		switch b[0] {
+		case 'n':
+			s += "NULL"
+			b = b[1:]
+		case 't':
+			l := int(binary.BigEndian.Uint32(b[1:]))
+			s += string(b[5 : 5+l])
+			b = b[5+l:]
+		default:
+			t.Fatalf("unexpected tuple value kind %q", b[0])
+		}
+	}
+	return s
+}
@@ -33,10 +33,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/log"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timetz"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -58,6 +60,7 @@ type pgType struct {
 }
 
 func pgTypeForParserType(t *types.T) pgType {
+	t = encodingType(t)
 	size := -1
 	if s, variable := tree.DatumTypeSize(t); !variable {
 		size = int(s)
@@ -68,6 +71,16 @@ func pgTypeForParserType(t *types.T) pgType {
 	}
 }
 
+// encodingType returns the type that is used to encode values of type t.
+// Values of a domain are sent to the client as values of the domain's base
+// type, the same way Postgres does.
+func encodingType(t *types.T) *types.T {
+	if t != nil && t.IsDomain() {
+		return t.DomainBaseType()
+	}
+	return t
+}
+
 func writeTextBool(b *writeBuffer, v bool) {
 	b.putInt32(1)
 	b.writeByte(tree.PgwireFormatBool(v))
@@ -147,7 +160,7 @@ func (b *writeBuffer) writeTextDatum(
 		b.putInt32(-1)
 		return
 	}
-	writeTextDatumNotNull(b, d, conv, sessionLoc, t)
+	writeTextDatumNotNull(b, d, conv, sessionLoc, encodingType(t))
 }
 
 // writeTextDatumNotNull writes d to the buffer when d is not null. Type t must
@@ -240,6 +253,19 @@ func writeTextDatumNotNull(
 	case *tree.DJSON:
 		b.writeLengthPrefixedString(v.JSON.String())
 
+	case *tree.DTSQuery:
+		b.writeLengthPrefixedString(v.TSQuery.String())
+
+	case *tree.DTSVector:
+		b.writeLengthPrefixedString(v.TSVector.String())
+
+	case *tree.DJsonpath:
+		b.writeLengthPrefixedString(v.Path.String())
+
+	case *tree.DRange:
+		b.textFormatter.FormatNode(v.InLocation(sessionLoc))
+		b.writeFromFmtCtx(b.textFormatter)
+
 	case *tree.DTuple:
 		b.textFormatter.FormatNode(v)
 		b.writeFromFmtCtx(b.textFormatter)
@@ -287,7 +313,7 @@ func (b *writeBuffer) writeTextColumnarElement(
 ) {
 	oldDCC := b.textFormatter.SetDataConversionConfig(conv)
 	defer b.textFormatter.SetDataConversionConfig(oldDCC)
-	typ := vecs.Vecs[vecIdx].Type()
+	typ := encodingType(vecs.Vecs[vecIdx].Type())
 	if log.V(2) {
 		log.Infof(ctx, "pgwire writing TEXT columnar element of type: %s", typ)
 	}
@@ -542,7 +568,7 @@ func (b *writeBuffer) writeBinaryDatum(
 		b.putInt32(-1)
 		return
 	}
-	writeBinaryDatumNotNull(ctx, b, d, sessionLoc, t)
+	writeBinaryDatumNotNull(ctx, b, d, sessionLoc, encodingType(t))
 }
 
 // writeBinaryDatumNotNull writes d to the buffer when d is not null. Type t
@@ -740,6 +766,56 @@ func writeBinaryDatumNotNull(
 	case *tree.DJSON:
 		writeBinaryJSON(b, v.JSON)
 
+	case *tree.DTSQuery:
+		encoded := tsearch.EncodeTSQuery(nil, v.TSQuery)
+		b.putInt32(int32(len(encoded)))
+		b.write(encoded)
+
+	case *tree.DTSVector:
+		encoded := tsearch.EncodeTSVector(nil, v.TSVector)
+		b.putInt32(int32(len(encoded)))
+		b.write(encoded)
+
+	case *tree.DJsonpath:
+		encoded := jsonpath.EncodePath(nil, v.Path)
+		b.putInt32(int32(len(encoded)))
+		b.write(encoded)
+
+	case *tree.DRange:
+		initialLen := b.Len()
+
+		// Reserve bytes for writing length later.
+		b.putInt32(int32(0))
+
+		// The flags are followed by the length-prefixed finite bounds.
+		var flags byte
+		switch {
+		case v.Empty:
+			flags |= pgwirebase.PGBinaryRangeEmpty
+		default:
+			if v.Lower.IsInfinite() {
+				flags |= pgwirebase.PGBinaryRangeLowerInfinite
+			} else if v.Lower.Inclusive {
+				flags |= pgwirebase.PGBinaryRangeLowerInclusive
+			}
+			if v.Upper.IsInfinite() {
+				flags |= pgwirebase.PGBinaryRangeUpperInfinite
+			} else if v.Upper.Inclusive {
+				flags |= pgwirebase.PGBinaryRangeUpperInclusive
+			}
+		}
+		b.writeByte(flags)
+		if !v.Empty {
+			for _, bound := range []tree.RangeBound{v.Lower, v.Upper} {
+				if !bound.IsInfinite() {
+					b.writeBinaryDatum(ctx, bound.Val, sessionLoc, t.RangeContents())
+				}
+			}
+		}
+
+		lengthToWrite := b.Len() - (initialLen + 4)
+		b.putInt32AtIndex(initialLen /* index to write at */, int32(lengthToWrite))
+
 	case *tree.DOid:
 		b.putInt32(4)
 		b.putInt32(int32(v.DInt))
@@ -754,7 +830,7 @@ func writeBinaryDatumNotNull(
 func (b *writeBuffer) writeBinaryColumnarElement(
 	ctx context.Context, vecs *coldata.TypedVecs, vecIdx int, rowIdx int, sessionLoc *time.Location,
 ) {
-	typ := vecs.Vecs[vecIdx].Type()
+	typ := encodingType(vecs.Vecs[vecIdx].Type())
 	if log.V(2) {
 		log.Infof(ctx, "pgwire writing BINARY columnar element of type: %s", typ)
 	}
@@ -48,6 +48,10 @@ func getPlanColumns(plan planNode, mut bool) colinfo.ResultColumns {
 	// Nodes that define their own schema.
 	case *delayedNode:
 		return n.columns
+	case *callNode:
+		return n.columns
+	case *fetchNode:
+		return n.columns
 	case *groupNode:
 		return n.columns
 	case *joinNode:
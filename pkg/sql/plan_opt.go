@@ -22,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/execbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/exec/explain"
+	"github.com/cockroachdb/cockroach/pkg/sql/opt/indexrec"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/memo"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/optbuilder"
 	"github.com/cockroachdb/cockroach/pkg/sql/opt/xform"
@@ -57,14 +58,14 @@ func (p *planner) prepareUsingOptimizer(ctx context.Context) (planFlags, error)
 		*tree.BeginTransaction,
 		*tree.CommentOnColumn, *tree.CommentOnConstraint, *tree.CommentOnDatabase, *tree.CommentOnIndex, *tree.CommentOnTable, *tree.CommentOnSchema,
 		*tree.CommitTransaction,
-		*tree.CopyFrom, *tree.CreateDatabase, *tree.CreateIndex, *tree.CreateView,
+		*tree.CopyFrom, *tree.CopyTo, *tree.CreateDatabase, *tree.CreateIndex, *tree.CreateView,
 		*tree.CreateSequence,
 		*tree.CreateStats,
 		*tree.Deallocate, *tree.Discard, *tree.DropDatabase, *tree.DropIndex,
 		*tree.DropTable, *tree.DropView, *tree.DropSequence, *tree.DropType,
 		*tree.Execute,
 		*tree.Grant, *tree.GrantRole,
-		*tree.Prepare,
+		*tree.Prepare, *tree.PrepareTransaction,
 		*tree.ReleaseSavepoint, *tree.RenameColumn, *tree.RenameDatabase,
 		*tree.RenameIndex, *tree.RenameTable, *tree.Revoke, *tree.RevokeRole,
 		*tree.RollbackToSavepoint, *tree.RollbackTransaction,
@@ -196,6 +197,10 @@ func (p *planner) makeOptimizerPlan(ctx context.Context) error {
 		return err
 	}
 
+	if p.SessionData().IndexRecommendationsEnabled && !p.SessionData().Internal {
+		opc.recordIndexRecommendations(ctx)
+	}
+
 	// Build the plan tree.
 	if mode := p.SessionData().ExperimentalDistSQLPlanningMode; mode != sessiondatapb.ExperimentalDistSQLPlanningOff {
 		planningMode := distSQLDefaultPlanning
@@ -304,13 +309,13 @@ func (opc *optPlanningCtx) reset() {
 	opc.optimizer.Init(p.EvalContext(), &opc.catalog)
 	opc.flags = 0
 
-	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE. We could
+	// We only allow memo caching for SELECT/INSERT/UPDATE/DELETE/MERGE. We could
 	// support it for all statements in principle, but it would increase the
 	// surface of potential issues (conditions we need to detect to invalidate a
 	// cached memo).
 	switch p.stmt.AST.(type) {
 	case *tree.ParenSelect, *tree.Select, *tree.SelectClause, *tree.UnionClause, *tree.ValuesClause,
-		*tree.Insert, *tree.Update, *tree.Delete, *tree.CannedOptPlan:
+		*tree.Insert, *tree.Update, *tree.Delete, *tree.Merge, *tree.CannedOptPlan:
 		// If the current transaction has uncommitted DDL statements, we cannot rely
 		// on descriptor versions for detecting a "stale" memo. This is because
 		// descriptor versions are bumped at most once per transaction, even if there
@@ -546,6 +551,49 @@ func (opc *optPlanningCtx) buildExecMemo(ctx context.Context) (_ *memo.Memo, _ e
 	return f.Memo(), nil
 }
 
+// recordIndexRecommendations records the indexes recommended by the optimizer
+// for the statement in the planner, if it is an EXPLAIN statement or its plan
+// is saved for the statement statistics. The recommendations are purely
+// informational, so a failure to make them is logged rather than returned.
+func (opc *optPlanningCtx) recordIndexRecommendations(ctx context.Context) {
+	p := opc.p
+	stmt := p.stmt.AST
+	if e, ok := stmt.(*tree.Explain); ok {
+		if e.Mode != tree.ExplainPlan || e.Flags[tree.ExplainFlagJSON] {
+			return
+		}
+		stmt = e.Statement
+	} else if !p.instrumentation.savePlanForStats || stmt.StatementReturnType() != tree.Rows {
+		return
+	}
+
+	indexRecs, err := opc.makeIndexRecommendations(ctx, stmt)
+	if err != nil {
+		log.Warningf(ctx, "unable to make index recommendations: %v", err)
+		return
+	}
+	p.instrumentation.indexRecommendations = indexRecs
+}
+
+// makeIndexRecommendations returns the CREATE INDEX statements for the indexes
+// that would lower the cost of the given statement. The statement is built and
+// optimized with a separate optimizer, so that the memo of the statement being
+// planned is not affected.
+func (opc *optPlanningCtx) makeIndexRecommendations(
+	ctx context.Context, stmt tree.Statement,
+) ([]string, error) {
+	p := opc.p
+	var o xform.Optimizer
+	o.Init(p.EvalContext(), &opc.catalog)
+	f := o.Factory()
+	f.FoldingControl().AllowStableFolds()
+	bld := optbuilder.New(ctx, &p.semaCtx, p.EvalContext(), &opc.catalog, f, stmt)
+	if err := bld.Build(); err != nil {
+		return nil, err
+	}
+	return indexrec.Recommend(ctx, &o, &opc.catalog)
+}
+
 // runExecBuilder execbuilds a plan using the given factory and stores the
 // result in planTop. If required, also captures explain data using the explain
 // factory.
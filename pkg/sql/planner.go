@@ -160,6 +160,27 @@ type planner struct {
 
 	preparedStatements preparedStatementsAccessor
 
+	// sqlCursors contains the cursors declared in the session.
+	sqlCursors sqlCursors
+
+	// sqlListeners contains the channels the session listens on. It is nil for
+	// planners which aren't associated with a session.
+	sqlListeners *notificationListener
+
+	// deferredConstraints tracks the DEFERRABLE constraints of the current
+	// transaction. It is nil for planners which aren't associated with a
+	// session, in which case all constraints are checked immediately.
+	deferredConstraints *deferredConstraints
+
+	// advisoryLocks contains the advisory locks held by the session. It is nil
+	// for planners which aren't associated with a session.
+	advisoryLocks *advisoryLocks
+
+	// procedureCall tracks the procedure which ended a transaction of the
+	// session, if any. It is nil for planners which aren't associated with a
+	// session, in which case procedures cannot end transactions.
+	procedureCall *procedureCall
+
 	// avoidCachedDescriptors, when true, instructs all code that
 	// accesses table/view descriptors to force reading the descriptors
 	// within the transaction. This is necessary to read descriptors
@@ -363,6 +384,7 @@ func newInternalPlanner(
 	p.extendedEvalCtx.Tenant = p
 	p.extendedEvalCtx.Regions = p
 	p.extendedEvalCtx.JoinTokenCreator = p
+	p.extendedEvalCtx.AdvisoryLocker = p
 	p.extendedEvalCtx.ClusterID = execCfg.ClusterID()
 	p.extendedEvalCtx.ClusterName = execCfg.RPCContext.ClusterName()
 	p.extendedEvalCtx.NodeID = execCfg.NodeID
@@ -370,6 +392,7 @@ func newInternalPlanner(
 
 	p.sessionDataMutatorIterator = smi
 	p.autoCommit = false
+	p.sqlCursors = emptySQLCursors{}
 
 	p.extendedEvalCtx.MemMetrics = memMetrics
 	p.extendedEvalCtx.ExecCfg = execCfg
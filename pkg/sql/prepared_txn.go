@@ -0,0 +1,413 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/txnwait"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// maxPreparedTxnGlobalIDLen is the maximum length of the identifier of a
+// prepared transaction, which is the same as in Postgres.
+const maxPreparedTxnGlobalIDLen = 200
+
+// prepareSQLTransactionInternal implements PREPARE TRANSACTION. It prepares
+// the KV transaction for two-phase commit and records it under its global
+// identifier in system.prepared_transactions, from where COMMIT PREPARED and
+// ROLLBACK PREPARED find it later, possibly from a different session.
+//
+// The row is written in its own transaction before the KV transaction is
+// prepared, so that a prepared transaction always has a row, and its prepared
+// time is only set once the transaction is known to be prepared. If PREPARE
+// TRANSACTION fails with an ambiguous result or its gateway fails in between,
+// the row is left with a NULL prepared time, and recoverPreparedTxn later finds
+// out from the transaction record whether the transaction was prepared.
+func (ex *connExecutor) prepareSQLTransactionInternal(
+	ctx context.Context, ast tree.Statement,
+) error {
+	if !ex.server.cfg.Settings.Version.IsActive(ctx, clusterversion.PreparedTransactionsTable) {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"PREPARE TRANSACTION is only available once the cluster is fully upgraded")
+	}
+	if ex.implicitTxn() {
+		return errNoTransactionInProgress
+	}
+	gid := ast.(*tree.PrepareTransaction).Transaction.RawString()
+	if len(gid) > maxPreparedTxnGlobalIDLen {
+		return pgerror.Newf(pgcode.InvalidParameterValue,
+			"transaction identifier %q is too long", gid)
+	}
+
+	// The effects of the transaction on the session cannot be deferred until
+	// the transaction is committed from another session.
+	if ex.extraTxnState.descCollection.HasUncommittedDescriptors() ||
+		len(ex.extraTxnState.schemaChangeJobRecords) > 0 {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"cannot PREPARE a transaction that has modified the schema")
+	}
+	if len(ex.extraTxnState.sqlListeners.pending) > 0 {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"cannot PREPARE a transaction that has executed LISTEN or UNLISTEN")
+	}
+	if ex.extraTxnState.advisoryLocks.heldByTxn() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"cannot PREPARE a transaction that holds transaction-level advisory locks")
+	}
+	for _, c := range ex.extraTxnState.sqlCursors.list() {
+		if c.txn != nil && c.withHold {
+			return pgerror.New(pgcode.FeatureNotSupported,
+				"cannot PREPARE a transaction that has created a cursor WITH HOLD")
+		}
+	}
+
+	// Validate the deferred constraints violated by statements of the
+	// transaction.
+	if err := ex.extraTxnState.deferredConstraints.validatePending(
+		ctx, ex.server.cfg.InternalExecutor, ex.state.mu.txn, &ex.extraTxnState.descCollection, true, /* all */
+	); err != nil {
+		return err
+	}
+
+	txn := ex.state.mu.txn
+	execCfg := ex.server.cfg
+	if err := insertPreparedTxn(
+		ctx, execCfg, gid, txn, ex.sessionData().SessionUser().Normalized(), ex.sessionData().Database,
+	); err != nil {
+		return err
+	}
+
+	ie := execCfg.InternalExecutor
+	if err := txn.Prepare(ctx); err != nil {
+		// If it is known that the transaction was not prepared, it will be rolled
+		// back, and its identifier can be reused right away. Otherwise the row is
+		// left for recoverPreparedTxn.
+		if !errors.HasType(err, (*roachpb.AmbiguousResultError)(nil)) {
+			if delErr := deletePreparedTxn(ctx, ie, nil /* txn */, gid); delErr != nil {
+				err = errors.CombineErrors(err, delErr)
+			}
+		}
+		return pgerror.WithCandidateCode(err, pgcode.FeatureNotSupported)
+	}
+
+	// The transaction is prepared even if its row can't be updated, in which
+	// case recoverPreparedTxn sets the prepared time later.
+	if _, err := ie.ExecEx(
+		ctx, "update-prepared-txn", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`UPDATE system.prepared_transactions SET prepared = now() WHERE global_id = $1`, gid,
+	); err != nil {
+		log.Warningf(ctx, "failed to record that transaction %q was prepared: %v", gid, err)
+	}
+	return nil
+}
+
+// insertPreparedTxn records the given transaction under the global identifier
+// in system.prepared_transactions, with a NULL prepared time. If the identifier
+// is held by a row which recoverPreparedTxn finds to refer to a transaction
+// that was not prepared, it is taken over.
+func insertPreparedTxn(
+	ctx context.Context, execCfg *ExecutorConfig, gid string, txn *kv.Txn, owner, database string,
+) error {
+	key := tree.DNull
+	if k := txn.Key(); len(k) > 0 {
+		key = tree.NewDBytes(tree.DBytes(k))
+	}
+	insert := func() error {
+		_, err := execCfg.InternalExecutor.ExecEx(
+			ctx, "insert-prepared-txn", nil, /* txn */
+			sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+			`INSERT INTO system.prepared_transactions
+  (global_id, transaction_id, transaction_key, prepared, owner, database)
+VALUES ($1, $2, $3, NULL, $4, $5)`,
+			gid, tree.NewDUuid(tree.DUuid{UUID: txn.ID()}), key, owner, database,
+		)
+		return err
+	}
+	err := insert()
+	if pgerror.GetPGCode(err) == pgcode.UniqueViolation {
+		exists, recErr := recoverPreparedTxn(ctx, execCfg, gid)
+		if recErr != nil {
+			return recErr
+		}
+		if !exists {
+			err = insert()
+		}
+	}
+	if pgerror.GetPGCode(err) == pgcode.UniqueViolation {
+		return pgerror.Newf(pgcode.DuplicateObject,
+			"transaction identifier %q is already in use", gid)
+	}
+	return err
+}
+
+// recoverPreparedTxn resolves the row of system.prepared_transactions with the
+// given global identifier if its prepared time is NULL. The prepared time is
+// set if the transaction turns out to be prepared, and the row is deleted if
+// the transaction is finished. It returns whether the row still exists.
+func recoverPreparedTxn(ctx context.Context, execCfg *ExecutorConfig, gid string) (bool, error) {
+	ie := execCfg.InternalExecutor
+	row, err := ie.QueryRowEx(
+		ctx, "select-unprepared-txn", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`SELECT transaction_id, transaction_key, prepared
+FROM system.prepared_transactions WHERE global_id = $1`, gid,
+	)
+	if err != nil || row == nil {
+		return false, err
+	}
+	if row[2] != tree.DNull {
+		return true, nil
+	}
+
+	// A transaction which did not acquire any locks is prepared without a
+	// transaction record, so the gateway must have failed after preparing it.
+	var prepared tree.Datum = tree.MustMakeDTimestampTZ(execCfg.Clock.PhysicalTime(), time.Microsecond)
+	if row[1] != tree.DNull {
+		txnID := row[0].(*tree.DUuid).UUID
+		key := roachpb.Key(tree.MustBeDBytes(row[1]))
+		rec, err := queryPreparedTxnRecord(ctx, execCfg, txnID, key, true /* abortExpired */)
+		if err != nil {
+			return false, err
+		}
+		switch rec.Status {
+		case roachpb.PENDING, roachpb.STAGING:
+			// The transaction is still being prepared.
+			return true, nil
+		case roachpb.PREPARED:
+			prepared = tree.MustMakeDTimestampTZ(rec.WriteTimestamp.GoTime(), time.Microsecond)
+		default:
+			_, err := ie.ExecEx(
+				ctx, "delete-unprepared-txn", nil, /* txn */
+				sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+				`DELETE FROM system.prepared_transactions
+WHERE global_id = $1 AND transaction_id = $2 AND prepared IS NULL`, gid, row[0],
+			)
+			return false, err
+		}
+	}
+	_, err = ie.ExecEx(
+		ctx, "update-unprepared-txn", nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`UPDATE system.prepared_transactions SET prepared = $3
+WHERE global_id = $1 AND transaction_id = $2 AND prepared IS NULL`, gid, row[0], prepared,
+	)
+	return true, err
+}
+
+// queryPreparedTxnRecord returns the record of the transaction with the given
+// ID and anchor key. If the session which was preparing the transaction went
+// away before it could do so, the transaction stops being heartbeated; if
+// abortExpired is set, it is then aborted so that its identifier can be
+// released.
+func queryPreparedTxnRecord(
+	ctx context.Context, execCfg *ExecutorConfig, txnID uuid.UUID, key roachpb.Key, abortExpired bool,
+) (roachpb.Transaction, error) {
+	var b kv.Batch
+	b.AddRawRequest(&roachpb.QueryTxnRequest{
+		RequestHeader: roachpb.RequestHeader{Key: key},
+		Txn:           enginepb.TxnMeta{ID: txnID, Key: key},
+	})
+	if err := execCfg.DB.Run(ctx, &b); err != nil {
+		return roachpb.Transaction{}, err
+	}
+	rec := b.RawResponse().Responses[0].GetQueryTxn().QueriedTxn
+	now := execCfg.Clock.Now()
+	if !abortExpired || rec.Status != roachpb.PENDING || !txnwait.IsExpired(now, &rec) {
+		return rec, nil
+	}
+
+	b = kv.Batch{}
+	b.Header.Timestamp = now
+	b.AddRawRequest(&roachpb.PushTxnRequest{
+		RequestHeader: roachpb.RequestHeader{Key: key},
+		PusherTxn: roachpb.Transaction{
+			TxnMeta: enginepb.TxnMeta{Priority: enginepb.MaxTxnPriority},
+		},
+		PusheeTxn: rec.TxnMeta,
+		PushType:  roachpb.PUSH_ABORT,
+	})
+	if err := execCfg.DB.Run(ctx, &b); err != nil {
+		return roachpb.Transaction{}, err
+	}
+	return b.RawResponse().Responses[0].GetPushTxn().PusheeTxn, nil
+}
+
+// endPreparedTxnNode implements COMMIT PREPARED and ROLLBACK PREPARED.
+type endPreparedTxnNode struct {
+	gid    string
+	commit bool
+}
+
+// CommitPrepared implements the COMMIT PREPARED statement.
+// See https://www.postgresql.org/docs/current/sql-commit-prepared.html for
+// details.
+func (p *planner) CommitPrepared(ctx context.Context, n *tree.CommitPrepared) (planNode, error) {
+	return p.endPreparedTxn(ctx, n.Transaction.RawString(), true /* commit */)
+}
+
+// RollbackPrepared implements the ROLLBACK PREPARED statement.
+// See https://www.postgresql.org/docs/current/sql-rollback-prepared.html for
+// details.
+func (p *planner) RollbackPrepared(
+	ctx context.Context, n *tree.RollbackPrepared,
+) (planNode, error) {
+	return p.endPreparedTxn(ctx, n.Transaction.RawString(), false /* commit */)
+}
+
+func (p *planner) endPreparedTxn(ctx context.Context, gid string, commit bool) (planNode, error) {
+	stmt := "ROLLBACK PREPARED"
+	if commit {
+		stmt = "COMMIT PREPARED"
+	}
+	if !p.ExecCfg().Settings.Version.IsActive(ctx, clusterversion.PreparedTransactionsTable) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"%s is only available once the cluster is fully upgraded", stmt)
+	}
+	if !p.autoCommit {
+		return nil, pgerror.Newf(pgcode.ActiveSQLTransaction,
+			"%s cannot run inside a transaction block", stmt)
+	}
+	return &endPreparedTxnNode{gid: gid, commit: commit}, nil
+}
+
+func (n *endPreparedTxnNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	ie := p.ExecCfg().InternalExecutor
+	row, err := ie.QueryRowEx(
+		ctx, "select-prepared-txn", p.txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`SELECT transaction_id, transaction_key, owner
+FROM system.prepared_transactions WHERE global_id = $1`, n.gid,
+	)
+	if err != nil {
+		return err
+	}
+	if row == nil {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"prepared transaction with identifier %q does not exist", n.gid)
+	}
+	txnID := row[0].(*tree.DUuid).UUID
+	owner := security.MakeSQLUsernameFromPreNormalizedString(string(tree.MustBeDString(row[2])))
+	if p.User() != owner {
+		isAdmin, err := p.HasAdminRole(ctx)
+		if err != nil {
+			return err
+		}
+		if !isAdmin {
+			return errors.WithHint(
+				pgerror.New(pgcode.InsufficientPrivilege,
+					"permission denied to finish prepared transaction"),
+				"Must be superuser or the user that prepared the transaction.")
+		}
+	}
+
+	// A transaction which did not acquire any locks has nothing to finish.
+	if row[1] != tree.DNull {
+		key := roachpb.Key(tree.MustBeDBytes(row[1]))
+		if err := n.finishTxn(ctx, p, txnID, key); err != nil {
+			return err
+		}
+	}
+
+	return deletePreparedTxn(ctx, ie, p.txn, n.gid)
+}
+
+// deletePreparedTxn removes the prepared transaction with the given global
+// identifier from system.prepared_transactions.
+func deletePreparedTxn(ctx context.Context, ie *InternalExecutor, txn *kv.Txn, gid string) error {
+	_, err := ie.ExecEx(
+		ctx, "delete-prepared-txn", txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`DELETE FROM system.prepared_transactions WHERE global_id = $1`, gid,
+	)
+	return err
+}
+
+// finishTxn commits or rolls back the prepared KV transaction with the given
+// ID and anchor key.
+func (n *endPreparedTxnNode) finishTxn(
+	ctx context.Context, p *planner, txnID uuid.UUID, key roachpb.Key,
+) error {
+	rec, err := queryPreparedTxnRecord(ctx, p.ExecCfg(), txnID, key, true /* abortExpired */)
+	if err != nil {
+		return err
+	}
+
+	switch rec.Status {
+	case roachpb.PREPARED:
+		nodeID, _ := p.ExecCfg().NodeID.OptionalNodeID()
+		txn, err := kv.NewPreparedTxn(ctx, p.ExecCfg().DB, nodeID, &rec)
+		if err != nil {
+			return err
+		}
+		if !n.commit {
+			return txn.Rollback(ctx)
+		}
+		if err := txn.Commit(ctx); err != nil {
+			// The commit failed the timestamp checks, and the prepared
+			// transaction can't be retried.
+			if errors.HasType(err, (*roachpb.TransactionRetryWithProtoRefreshError)(nil)) {
+				return errors.WithHint(
+					pgerror.Wrapf(err, pgcode.SerializationFailure,
+						"prepared transaction with identifier %q cannot be committed", n.gid),
+					"Use ROLLBACK PREPARED to roll it back.")
+			}
+			return err
+		}
+		return nil
+
+	case roachpb.PENDING, roachpb.STAGING:
+		return pgerror.Newf(pgcode.ObjectInUse,
+			"prepared transaction with identifier %q is busy", n.gid)
+
+	case roachpb.ABORTED:
+		if !n.commit {
+			return nil
+		}
+		// The transaction was aborted before it could be prepared. The row is
+		// removed in its own transaction, since the statement fails.
+		if err := deletePreparedTxn(ctx, p.ExecCfg().InternalExecutor, nil /* txn */, n.gid); err != nil {
+			return err
+		}
+		return pgerror.Newf(pgcode.TransactionRollback,
+			"prepared transaction with identifier %q was aborted", n.gid)
+
+	default:
+		// The transaction was already committed, but the statement which did so
+		// failed to remove the row.
+		if n.commit {
+			return nil
+		}
+		if err := deletePreparedTxn(ctx, p.ExecCfg().InternalExecutor, nil /* txn */, n.gid); err != nil {
+			return err
+		}
+		return pgerror.Newf(pgcode.InvalidTransactionState,
+			"prepared transaction with identifier %q was already committed", n.gid)
+	}
+}
+
+func (n *endPreparedTxnNode) Next(runParams) (bool, error) { return false, nil }
+func (n *endPreparedTxnNode) Values() tree.Datums          { return nil }
+func (n *endPreparedTxnNode) Close(context.Context)        {}
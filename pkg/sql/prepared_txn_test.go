@@ -0,0 +1,120 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrepareTransactionAmbiguousResult checks that a transaction whose
+// PREPARE TRANSACTION fails with an ambiguous result keeps its row in
+// system.prepared_transactions, and that the row is resolved according to
+// whether the transaction was prepared.
+func TestPrepareTransactionAmbiguousResult(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testutils.RunTrueAndFalse(t, "prepared", func(t *testing.T, prepared bool) {
+		ctx := context.Background()
+		var inject int32
+		var injectedTxnID atomic.Value
+		injectedTxnID.Store(uuid.UUID{})
+		isPrepare := func(ba roachpb.BatchRequest) bool {
+			et, ok := ba.GetArg(roachpb.EndTxn)
+			return ok && et.(*roachpb.EndTxnRequest).Prepare
+		}
+		s, sqlDB, _ := serverutils.StartServer(t, base.TestServerArgs{
+			Knobs: base.TestingKnobs{
+				Store: &kvserver.StoreTestingKnobs{
+					TestingRequestFilter: func(_ context.Context, ba roachpb.BatchRequest) *roachpb.Error {
+						if atomic.LoadInt32(&inject) == 0 {
+							return nil
+						}
+						if !prepared && isPrepare(ba) {
+							return roachpb.NewError(roachpb.NewAmbiguousResultError("injected"))
+						}
+						// Keep the session from rolling back the transaction once it
+						// was prepared, as if its gateway had failed.
+						if et, ok := ba.GetArg(roachpb.EndTxn); ok && !et.(*roachpb.EndTxnRequest).Commit &&
+							ba.Txn.ID == injectedTxnID.Load().(uuid.UUID) {
+							return roachpb.NewErrorf("injected")
+						}
+						return nil
+					},
+					TestingResponseFilter: func(
+						_ context.Context, ba roachpb.BatchRequest, _ *roachpb.BatchResponse,
+					) *roachpb.Error {
+						if atomic.LoadInt32(&inject) == 0 || !prepared || !isPrepare(ba) {
+							return nil
+						}
+						injectedTxnID.Store(ba.Txn.ID)
+						return roachpb.NewError(roachpb.NewAmbiguousResultError("injected"))
+					},
+				},
+			},
+		})
+		defer s.Stopper().Stop(ctx)
+		db := sqlutils.MakeSQLRunner(sqlDB)
+		db.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY)`)
+
+		prepare := func(k int) error {
+			conn, err := sqlDB.Conn(ctx)
+			require.NoError(t, err)
+			defer func() { _ = conn.Close() }()
+			_, err = conn.ExecContext(ctx, `BEGIN`)
+			require.NoError(t, err)
+			_, err = conn.ExecContext(ctx, `INSERT INTO t VALUES ($1)`, k)
+			require.NoError(t, err)
+			_, err = conn.ExecContext(ctx, `PREPARE TRANSACTION 'txn'`)
+			return err
+		}
+
+		atomic.StoreInt32(&inject, 1)
+		require.True(t, testutils.IsError(prepare(1), "result is ambiguous"))
+		atomic.StoreInt32(&inject, 0)
+
+		// It is not known whether the transaction was prepared, so its row is
+		// left without a prepared time.
+		db.CheckQueryResults(t,
+			`SELECT prepared IS NULL FROM system.prepared_transactions WHERE global_id = 'txn'`,
+			[][]string{{"true"}})
+
+		if prepared {
+			db.CheckQueryResults(t, `SELECT gid FROM pg_catalog.pg_prepared_xacts`, [][]string{{"txn"}})
+			require.True(t, testutils.IsError(prepare(2), `transaction identifier "txn" is already in use`))
+			db.CheckQueryResults(t,
+				`SELECT prepared IS NULL FROM system.prepared_transactions WHERE global_id = 'txn'`,
+				[][]string{{"false"}})
+			db.Exec(t, `COMMIT PREPARED 'txn'`)
+			db.CheckQueryResults(t, `SELECT k FROM t`, [][]string{{"1"}})
+		} else {
+			db.CheckQueryResults(t, `SELECT gid FROM pg_catalog.pg_prepared_xacts`, [][]string{})
+			// The identifier is taken over since the transaction was rolled back.
+			require.NoError(t, prepare(2))
+			db.Exec(t, `COMMIT PREPARED 'txn'`)
+			db.CheckQueryResults(t, `SELECT k FROM t`, [][]string{{"2"}})
+		}
+		db.CheckQueryResults(t, `SELECT count(*) FROM system.prepared_transactions`, [][]string{{"0"}})
+	})
+}
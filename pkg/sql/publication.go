@@ -0,0 +1,504 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/dbdesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/resolver"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
+
+// publicationOptionExpectValues are the options of CREATE PUBLICATION and
+// ALTER PUBLICATION ... SET.
+var publicationOptionExpectValues = map[string]KVStringOptValidate{
+	"publish": KVStringOptRequireValue,
+}
+
+// checkLogicalReplicationSupported returns an error until the cluster version
+// which allows publications, replication slots and subscriptions is
+// finalized. Nodes running older versions drop the publications from database
+// descriptors, and cannot adopt the jobs of replication slots and
+// subscriptions.
+func checkLogicalReplicationSupported(ctx context.Context, st *cluster.Settings) error {
+	if !st.Version.IsActive(ctx, clusterversion.LogicalReplication) {
+		return pgerror.Newf(pgcode.FeatureNotSupported,
+			"version %v must be finalized to use logical replication",
+			clusterversion.LogicalReplication)
+	}
+	return nil
+}
+
+type createPublicationNode struct {
+	n       *tree.CreatePublication
+	dbDesc  *dbdesc.Mutable
+	tables  []descpb.ID
+	options func() (map[string]string, error)
+}
+
+var _ planNode = &createPublicationNode{n: nil}
+
+// CreatePublication creates a publication in the current database. The
+// publication is stored in the database descriptor rather than in a
+// descriptor of its own; see descpb.DatabaseDescriptor_PublicationInfo.
+// Privileges: CREATE on the database and ownership of the tables, or the
+// admin role for FOR ALL TABLES.
+func (p *planner) CreatePublication(
+	ctx context.Context, n *tree.CreatePublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"CREATE PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getMutableCurrentDatabaseFor(ctx, "publications")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivilege(ctx, dbDesc, privilege.CREATE); err != nil {
+		return nil, err
+	}
+	if n.AllTables {
+		if err := p.RequireAdminRole(ctx, "create a FOR ALL TABLES publication"); err != nil {
+			return nil, err
+		}
+	}
+	tables, err := p.resolvePublicationTables(ctx, dbDesc, n.Tables)
+	if err != nil {
+		return nil, err
+	}
+	options, err := p.TypeAsStringOpts(ctx, n.Options, publicationOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	return &createPublicationNode{n: n, dbDesc: dbDesc, tables: tables, options: options}, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because CREATE PUBLICATION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *createPublicationNode) ReadingOwnWrites() {}
+
+func (n *createPublicationNode) startExec(params runParams) error {
+	name := string(n.n.Name)
+	if _, ok := n.dbDesc.GetPublication(name); ok {
+		return pgerror.Newf(pgcode.DuplicateObject, "publication %q already exists", name)
+	}
+	pub := descpb.DatabaseDescriptor_PublicationInfo{
+		OwnerProto:      params.p.User().EncodeProto(),
+		AllTables:       n.n.AllTables,
+		TableIDs:        n.tables,
+		PublishInsert:   true,
+		PublishUpdate:   true,
+		PublishDelete:   true,
+		PublishTruncate: true,
+	}
+	opts, err := n.options()
+	if err != nil {
+		return err
+	}
+	if err := setPublicationOptions(&pub, opts); err != nil {
+		return err
+	}
+	n.dbDesc.SetPublication(name, pub)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*createPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*createPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createPublicationNode) Close(context.Context)        {}
+
+type alterPublicationNode struct {
+	n       *tree.AlterPublication
+	dbDesc  *dbdesc.Mutable
+	tables  []descpb.ID
+	options func() (map[string]string, error)
+}
+
+var _ planNode = &alterPublicationNode{n: nil}
+
+// AlterPublication changes the tables or the options of a publication.
+// Privileges: ownership of the publication and of the added tables.
+func (p *planner) AlterPublication(
+	ctx context.Context, n *tree.AlterPublication,
+) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"ALTER PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getMutableCurrentDatabaseFor(ctx, "publications")
+	if err != nil {
+		return nil, err
+	}
+	pub, err := p.getPublicationForChange(ctx, dbDesc, string(n.Name))
+	if err != nil {
+		return nil, err
+	}
+	node := &alterPublicationNode{n: n, dbDesc: dbDesc}
+	if n.Action == tree.AlterPublicationSetOptions {
+		node.options, err = p.TypeAsStringOpts(ctx, n.Options, publicationOptionExpectValues)
+		return node, err
+	}
+	if pub.AllTables {
+		return nil, errors.WithDetail(
+			pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+				"publication %q is defined as FOR ALL TABLES", string(n.Name)),
+			"Tables cannot be added to or dropped from FOR ALL TABLES publications.",
+		)
+	}
+	if n.Action == tree.AlterPublicationDropTables {
+		// Dropping tables from a publication doesn't require ownership of the
+		// tables.
+		node.tables, err = p.resolvePublicationTablesForDrop(ctx, dbDesc, n.Tables)
+	} else {
+		node.tables, err = p.resolvePublicationTables(ctx, dbDesc, n.Tables)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because ALTER PUBLICATION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *alterPublicationNode) ReadingOwnWrites() {}
+
+func (n *alterPublicationNode) startExec(params runParams) error {
+	name := string(n.n.Name)
+	pub, _ := n.dbDesc.GetPublication(name)
+	switch n.n.Action {
+	case tree.AlterPublicationAddTables:
+		for _, id := range n.tables {
+			if publicationHasTable(pub, id) {
+				return pgerror.Newf(pgcode.DuplicateObject,
+					"relation %q is already member of publication %q",
+					n.tableName(params.ctx, params.p, id), name)
+			}
+			pub.TableIDs = append(pub.TableIDs, id)
+		}
+	case tree.AlterPublicationDropTables:
+		for _, id := range n.tables {
+			if !publicationHasTable(pub, id) {
+				return pgerror.Newf(pgcode.UndefinedObject,
+					"relation %q is not part of the publication",
+					n.tableName(params.ctx, params.p, id))
+			}
+			tableIDs := pub.TableIDs[:0:0]
+			for _, existing := range pub.TableIDs {
+				if existing != id {
+					tableIDs = append(tableIDs, existing)
+				}
+			}
+			pub.TableIDs = tableIDs
+		}
+	case tree.AlterPublicationSetTables:
+		pub.TableIDs = n.tables
+	case tree.AlterPublicationSetOptions:
+		opts, err := n.options()
+		if err != nil {
+			return err
+		}
+		if err := setPublicationOptions(&pub, opts); err != nil {
+			return err
+		}
+	default:
+		return errors.AssertionFailedf("unexpected ALTER PUBLICATION action %d", n.n.Action)
+	}
+	n.dbDesc.SetPublication(name, pub)
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+// tableName returns the name of the table with the given ID for use in error
+// messages.
+func (n *alterPublicationNode) tableName(ctx context.Context, p *planner, id descpb.ID) string {
+	desc, err := p.Descriptors().GetImmutableTableByID(ctx, p.txn, id, tree.ObjectLookupFlagsWithRequired())
+	if err != nil {
+		return fmt.Sprintf("[%d]", id)
+	}
+	return desc.GetName()
+}
+
+func (*alterPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*alterPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*alterPublicationNode) Close(context.Context)        {}
+
+type dropPublicationNode struct {
+	n      *tree.DropPublication
+	dbDesc *dbdesc.Mutable
+	toDrop []string
+}
+
+var _ planNode = &dropPublicationNode{n: nil}
+
+// DropPublication drops publications.
+// Privileges: ownership of the publications.
+func (p *planner) DropPublication(ctx context.Context, n *tree.DropPublication) (planNode, error) {
+	if err := checkSchemaChangeEnabled(
+		ctx,
+		p.ExecCfg(),
+		"DROP PUBLICATION",
+	); err != nil {
+		return nil, err
+	}
+
+	dbDesc, err := p.getMutableCurrentDatabaseFor(ctx, "publications")
+	if err != nil {
+		return nil, err
+	}
+	node := &dropPublicationNode{n: n, dbDesc: dbDesc}
+	for _, name := range n.Names {
+		if _, ok := dbDesc.GetPublication(string(name)); !ok && n.IfExists {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"publication %q does not exist, skipping", string(name)))
+			continue
+		}
+		if _, err := p.getPublicationForChange(ctx, dbDesc, string(name)); err != nil {
+			return nil, err
+		}
+		node.toDrop = append(node.toDrop, string(name))
+	}
+	return node, nil
+}
+
+// ReadingOwnWrites implements the planNodeReadingOwnWrites interface.
+// This is because DROP PUBLICATION performs multiple KV operations on
+// descriptors and expects to see its own writes.
+func (n *dropPublicationNode) ReadingOwnWrites() {}
+
+func (n *dropPublicationNode) startExec(params runParams) error {
+	if len(n.toDrop) == 0 {
+		return nil
+	}
+	for _, name := range n.toDrop {
+		n.dbDesc.RemovePublication(name)
+	}
+	return params.p.writeNonDropDatabaseChange(
+		params.ctx, n.dbDesc, tree.AsStringWithFQNames(n.n, params.Ann()),
+	)
+}
+
+func (*dropPublicationNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropPublicationNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropPublicationNode) Close(context.Context)        {}
+
+// getMutableCurrentDatabaseFor returns the current database, in which
+// objects stored in the database descriptor, such as publications and foreign
+// servers, are created. The objects are named in error messages.
+func (p *planner) getMutableCurrentDatabaseFor(
+	ctx context.Context, objects string,
+) (*dbdesc.Mutable, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.Newf(pgcode.InvalidCatalogName,
+			"cannot use %s without a current database", objects)
+	}
+	dbDesc, err := p.Descriptors().GetMutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+	if err != nil {
+		return nil, err
+	}
+	if dbDesc.GetID() == keys.SystemDatabaseID {
+		return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"cannot use %s in the system database", objects)
+	}
+	return dbDesc, nil
+}
+
+// getPublicationForChange returns the publication with the given name, and
+// checks that the user is allowed to alter or drop it.
+func (p *planner) getPublicationForChange(
+	ctx context.Context, dbDesc catalog.DatabaseDescriptor, name string,
+) (descpb.DatabaseDescriptor_PublicationInfo, error) {
+	pub, ok := dbDesc.GetPublication(name)
+	if !ok {
+		return pub, pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+	}
+	hasAdmin, err := p.HasAdminRole(ctx)
+	if err != nil {
+		return pub, err
+	}
+	if hasAdmin {
+		return pub, nil
+	}
+	owner := pub.OwnerProto.Decode()
+	hasOwnership, err := p.checkRolePredicate(ctx, p.User(), func(role security.SQLUsername) bool {
+		return role == owner
+	})
+	if err != nil {
+		return pub, err
+	}
+	if !hasOwnership {
+		return pub, pgerror.Newf(pgcode.InsufficientPrivilege,
+			"must be owner of publication %s", name)
+	}
+	return pub, nil
+}
+
+// resolvePublicationTables resolves the tables added to a publication, and
+// checks that the user owns them.
+func (p *planner) resolvePublicationTables(
+	ctx context.Context, dbDesc catalog.DatabaseDescriptor, names tree.TableNames,
+) ([]descpb.ID, error) {
+	ids := make([]descpb.ID, 0, len(names))
+	for i := range names {
+		desc, err := p.resolvePublicationTable(ctx, dbDesc, &names[i])
+		if err != nil {
+			return nil, err
+		}
+		hasOwnership, err := p.HasOwnership(ctx, desc)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOwnership {
+			return nil, pgerror.Newf(pgcode.InsufficientPrivilege,
+				"must be owner of table %s", desc.GetName())
+		}
+		ids = appendUniqueID(ids, desc.GetID())
+	}
+	return ids, nil
+}
+
+// resolvePublicationTablesForDrop resolves the tables dropped from a
+// publication.
+func (p *planner) resolvePublicationTablesForDrop(
+	ctx context.Context, dbDesc catalog.DatabaseDescriptor, names tree.TableNames,
+) ([]descpb.ID, error) {
+	ids := make([]descpb.ID, 0, len(names))
+	for i := range names {
+		desc, err := p.resolvePublicationTable(ctx, dbDesc, &names[i])
+		if err != nil {
+			return nil, err
+		}
+		ids = appendUniqueID(ids, desc.GetID())
+	}
+	return ids, nil
+}
+
+func (p *planner) resolvePublicationTable(
+	ctx context.Context, dbDesc catalog.DatabaseDescriptor, tn *tree.TableName,
+) (catalog.TableDescriptor, error) {
+	flags := tree.ObjectLookupFlagsWithRequiredTableKind(tree.ResolveRequireTableDesc)
+	_, desc, err := resolver.ResolveExistingTableObject(ctx, p, tn, flags)
+	if err != nil {
+		return nil, err
+	}
+	if desc.GetParentID() != dbDesc.GetID() {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"cannot add table %q to a publication of database %q: tables of other databases cannot be published",
+			desc.GetName(), dbDesc.GetName())
+	}
+	if desc.IsTemporary() {
+		return nil, errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication", desc.GetName()),
+			"Temporary relations cannot be replicated.",
+		)
+	}
+	if desc.IsForeignTable() {
+		return nil, errors.WithDetail(
+			pgerror.Newf(pgcode.InvalidParameterValue,
+				"cannot add relation %q to publication", desc.GetName()),
+			"Foreign tables cannot be replicated.",
+		)
+	}
+	return desc, nil
+}
+
+func appendUniqueID(ids []descpb.ID, id descpb.ID) []descpb.ID {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func publicationHasTable(pub descpb.DatabaseDescriptor_PublicationInfo, id descpb.ID) bool {
+	for _, existing := range pub.TableIDs {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
+
+// setPublicationOptions applies the options of CREATE PUBLICATION or ALTER
+// PUBLICATION ... SET to the publication.
+func setPublicationOptions(
+	pub *descpb.DatabaseDescriptor_PublicationInfo, opts map[string]string,
+) error {
+	if publish, ok := opts["publish"]; ok {
+		pub.PublishInsert = false
+		pub.PublishUpdate = false
+		pub.PublishDelete = false
+		pub.PublishTruncate = false
+		for _, action := range strings.Split(publish, ",") {
+			switch strings.ToLower(strings.TrimSpace(action)) {
+			case "insert":
+				pub.PublishInsert = true
+			case "update":
+				pub.PublishUpdate = true
+			case "delete":
+				pub.PublishDelete = true
+			case "truncate":
+				pub.PublishTruncate = true
+			case "":
+			default:
+				return pgerror.Newf(pgcode.Syntax,
+					"unrecognized %q value: %q", "publish", strings.TrimSpace(action))
+			}
+		}
+	}
+	return nil
+}
+
+// publicationIncludesTable returns whether the changes to the given table are
+// published by the publication.
+func publicationIncludesTable(
+	pub descpb.DatabaseDescriptor_PublicationInfo, table catalog.TableDescriptor,
+) bool {
+	if !table.IsTable() || table.IsVirtualTable() || table.IsTemporary() ||
+		table.IsForeignTable() || table.Dropped() {
+		return false
+	}
+	return pub.AllTables || publicationHasTable(pub, table.GetID())
+}
@@ -28,10 +28,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/bitarray"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uint128"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
@@ -160,6 +162,35 @@ func RandDatumWithNullChance(rng *rand.Rand, typ *types.T, nullChance int) tree.
 			return nil
 		}
 		return &tree.DJSON{JSON: j}
+	case types.TSQueryFamily:
+		return &tree.DTSQuery{TSQuery: tsearch.RandomTSQuery(rng)}
+	case types.TSVectorFamily:
+		return &tree.DTSVector{TSVector: tsearch.RandomTSVector(rng)}
+	case types.JsonpathFamily:
+		return &tree.DJsonpath{Path: jsonpath.RandomPath(rng)}
+	case types.RangeFamily:
+		if rng.Intn(10) == 0 {
+			return tree.NewDEmptyRange(typ)
+		}
+		var bounds [2]tree.RangeBound
+		for i := range bounds {
+			// Leave some of the bounds infinite.
+			if rng.Intn(5) != 0 {
+				bounds[i] = tree.RangeBound{
+					Val:       RandDatum(rng, typ.RangeContents(), false /* nullOk */),
+					Inclusive: rng.Intn(2) == 0,
+				}
+			}
+		}
+		r, err := tree.NewDRange(nil /* ctx */, typ, bounds[0], bounds[1])
+		if err != nil {
+			// The bounds may be out of order.
+			bounds[0].Val, bounds[1].Val = bounds[1].Val, bounds[0].Val
+			if r, err = tree.NewDRange(nil /* ctx */, typ, bounds[0], bounds[1]); err != nil {
+				return tree.NewDEmptyRange(typ)
+			}
+		}
+		return r
 	case types.TupleFamily:
 		tuple := tree.DTuple{D: make(tree.Datums, len(typ.TupleContents()))}
 		for i := range typ.TupleContents() {
@@ -620,6 +651,51 @@ var (
 			}
 			return res
 		}(),
+		types.TSQueryFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`'a' & !'b':*`,
+				`'a':AB <2> ( 'b' | 'c' )`,
+			} {
+				d, err := tree.ParseDTSQuery(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
+		types.TSVectorFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				``,
+				`'a' 'b'`,
+				`'a':1A,16383 'b':2`,
+			} {
+				d, err := tree.ParseDTSVector(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
+		types.JsonpathFamily: func() []tree.Datum {
+			var res []tree.Datum
+			for _, s := range []string{
+				`$`,
+				`strict $.a[*] ? (@ > 1)`,
+				`$."a b".**{1 to last}.datetime()`,
+			} {
+				d, err := tree.ParseDJsonpath(s)
+				if err != nil {
+					panic(err)
+				}
+				res = append(res, d)
+			}
+			return res
+		}(),
 		types.BitFamily: func() []tree.Datum {
 			var res []tree.Datum
 			for _, i := range []int64{
@@ -316,9 +316,12 @@ func randIndexTableDefFromCols(
 		}
 
 		// The last index column can be inverted-indexable, which makes the
-		// index an inverted index.
+		// index an inverted index. Types which are neither indexable nor
+		// inverted-indexable, like TSQUERY, cannot be indexed at all.
 		if colinfo.ColumnTypeIsInvertedIndexable(semType) {
 			def.Inverted = true
+		} else if !colinfo.ColumnTypeIsIndexable(semType) {
+			return tree.IndexTableDef{}, false
 		}
 
 		def.Columns = append(def.Columns, elem)
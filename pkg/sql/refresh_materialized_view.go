@@ -40,6 +40,10 @@ func (p *planner) RefreshMaterializedView(
 	if !desc.MaterializedView() {
 		return nil, pgerror.Newf(pgcode.WrongObjectType, "%q is not a materialized view", desc.Name)
 	}
+	if n.Concurrently && n.RefreshDataOption == tree.RefreshDataClear {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"REFRESH options CONCURRENTLY and WITH NO DATA cannot be used together")
+	}
 	// TODO (rohany): Not sure if this is a real restriction, but let's start with
 	//  it to be safe.
 	for i := range desc.Mutations {
@@ -81,11 +85,25 @@ func (n *refreshMaterializedViewNode) startExec(params runParams) error {
 
 	telemetry.Inc(n.n.TelemetryCounter())
 
-	// Inform the user that CONCURRENTLY is not needed.
+	// With CONCURRENTLY, try to only apply the changes made to the view's base
+	// tables since the last refresh to the view's existing data, in the current
+	// transaction. If that isn't possible, recompute the view as usual.
 	if n.n.Concurrently {
+		r, reason, err := params.p.planIncrementalRefresh(params.ctx, n.desc)
+		if err != nil {
+			return err
+		}
+		if r != nil {
+			reason, err = params.p.refreshMaterializedViewIncrementally(
+				params.ctx, n.desc, r, tree.AsStringWithFQNames(n.n, params.Ann()),
+			)
+			if err != nil || reason == "" {
+				return err
+			}
+		}
 		params.p.BufferClientNotice(
 			params.ctx,
-			pgnotice.Newf("CONCURRENTLY is not required as views are refreshed concurrently"),
+			pgnotice.Newf("recomputing view %q as it cannot be refreshed incrementally: %s", n.desc.Name, reason),
 		)
 	}
 
@@ -0,0 +1,814 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/builtins"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/storage"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/errors"
+)
+
+// An incremental refresh of a materialized view recomputes only the rows of
+// the view that can have been affected by changes made to the view's base
+// tables since the view was last refreshed, instead of recomputing the whole
+// view.
+//
+// This is possible for views whose query is a single SELECT over a set of
+// inner-joined tables, if every row of the view can be identified by a "key"
+// of view columns such that a given row of a base table only contributes to
+// view rows with a known set of keys:
+//
+//  - For views with a GROUP BY clause, the key is formed by the grouping
+//    columns, which must all be part of the view. Any aggregate can be
+//    maintained, as the groups are recomputed as a whole.
+//  - For other views, the key is formed by the primary key columns of every
+//    base table, which must all be part of the view.
+//
+// The refresh finds the primary keys of the base table rows that changed since
+// the last refresh by exporting all the MVCC revisions of the tables' primary
+// indexes written since then. It then computes the keys of the view rows to
+// which those base table rows contribute, both as of the last refresh and as
+// of now, deletes the stored view rows with those keys and inserts their newly
+// computed replacements. All of this happens in the transaction of the REFRESH
+// statement, which finally records its read timestamp as the new last refresh
+// time of the view.
+
+// incrementalRefreshMaxChangedRows limits the number of base table rows that
+// an incremental refresh restricts the view query to. The primary keys of the
+// changed rows are held in memory and spelled out in the queries of the
+// refresh, so past this limit the view is recomputed instead.
+var incrementalRefreshMaxChangedRows = settings.RegisterIntSetting(
+	"sql.materialized_views.incremental_refresh.max_changed_rows",
+	"the maximum number of rows of the base tables of a materialized view that "+
+		"can have changed since its last refresh for REFRESH MATERIALIZED VIEW "+
+		"CONCURRENTLY to apply the changes incrementally instead of recomputing the view",
+	10000,
+	settings.NonNegativeInt,
+)
+
+// incrementalRefresh describes how a materialized view is refreshed
+// incrementally.
+type incrementalRefresh struct {
+	// sel is the SELECT clause of the view query.
+	sel *tree.SelectClause
+	// sources are the base tables that the view query reads from.
+	sources []incrementalRefreshSource
+	// keyOrdinals are the ordinals of the view columns that form the key of
+	// the rows of the view.
+	keyOrdinals []int
+	// keyExprs are the expressions of the view query computing the key
+	// columns of the view.
+	keyExprs tree.Exprs
+}
+
+// incrementalRefreshSource is a base table of a view that is refreshed
+// incrementally.
+type incrementalRefreshSource struct {
+	desc catalog.TableDescriptor
+	// ref is the name by which the view query refers to the table, which is
+	// its alias if one was given.
+	ref *tree.UnresolvedObjectName
+	// pkCols are references to the primary key columns of the table that
+	// can be used in the view query.
+	pkCols tree.Exprs
+}
+
+// planIncrementalRefresh determines whether the given materialized view can be
+// refreshed incrementally. If it can't, a non-empty reason is returned.
+func (p *planner) planIncrementalRefresh(
+	ctx context.Context, view catalog.TableDescriptor,
+) (_ *incrementalRefresh, reason string, _ error) {
+	stmt, err := parser.ParseOne(view.GetViewQuery())
+	if err != nil {
+		return nil, "", err
+	}
+	s, ok := stmt.AST.(*tree.Select)
+	if !ok || s.With != nil || s.OrderBy != nil || s.Limit != nil || s.Locking != nil {
+		return nil, "the view query is not a simple SELECT", nil
+	}
+	sel, ok := s.Select.(*tree.SelectClause)
+	if !ok || sel.TableSelect || sel.Window != nil || sel.DistinctOn != nil || sel.From.AsOf.Expr != nil {
+		return nil, "the view query is not a simple SELECT", nil
+	}
+	if len(view.PartialIndexes()) > 0 {
+		return nil, "the view has partial indexes", nil
+	}
+
+	// Check that the view query doesn't contain expressions that prevent
+	// recomputing parts of the view independently.
+	v := incrementalRefreshVisitor{searchPath: p.CurrentSearchPath()}
+	for _, e := range sel.Exprs {
+		tree.WalkExprConst(&v, e.Expr)
+	}
+	if sel.Where != nil {
+		tree.WalkExprConst(&v, sel.Where.Expr)
+	}
+	if sel.Having != nil {
+		tree.WalkExprConst(&v, sel.Having.Expr)
+	}
+	if v.reason != "" {
+		return nil, v.reason, nil
+	}
+
+	r := &incrementalRefresh{sel: sel}
+	if reason, err := p.collectIncrementalRefreshSources(ctx, r, sel.From.Tables); reason != "" || err != nil {
+		return nil, reason, err
+	}
+	if len(r.sources) == 0 {
+		return nil, "the view query does not read from any table", nil
+	}
+
+	// Every visible column of the view is computed by the expression at the
+	// same position in the view query. The only other column can be the
+	// hidden row ID that makes up the view's primary key.
+	numVisible := 0
+	for _, col := range view.PublicColumns() {
+		if col.IsComputed() || col.IsInaccessible() {
+			return nil, "the view has expression indexes", nil
+		}
+		if !col.IsHidden() {
+			numVisible++
+		}
+	}
+	if numVisible != len(sel.Exprs) {
+		return nil, "the view query does not match the view columns", nil
+	}
+
+	if len(sel.GroupBy) > 0 {
+		reason = r.findGroupingKey()
+	} else if v.hasAggregate || sel.Having != nil {
+		reason = "the view query computes a scalar aggregate"
+	} else {
+		reason = r.findPrimaryKey()
+	}
+	if reason != "" {
+		return nil, reason, nil
+	}
+	return r, "", nil
+}
+
+// collectIncrementalRefreshSources adds the tables of the given FROM clause to
+// the sources of the incremental refresh.
+func (p *planner) collectIncrementalRefreshSources(
+	ctx context.Context, r *incrementalRefresh, tables tree.TableExprs,
+) (reason string, _ error) {
+	for _, t := range tables {
+		switch t := t.(type) {
+		case *tree.ParenTableExpr:
+			if reason, err := p.collectIncrementalRefreshSources(ctx, r, tree.TableExprs{t.Expr}); reason != "" || err != nil {
+				return reason, err
+			}
+
+		case *tree.JoinTableExpr:
+			switch t.JoinType {
+			case "", tree.AstInner, tree.AstCross:
+			default:
+				return fmt.Sprintf("the view query uses a %s JOIN", t.JoinType), nil
+			}
+			if reason, err := p.collectIncrementalRefreshSources(ctx, r, tree.TableExprs{t.Left, t.Right}); reason != "" || err != nil {
+				return reason, err
+			}
+			if on, ok := t.Cond.(*tree.OnJoinCond); ok {
+				v := incrementalRefreshVisitor{searchPath: p.CurrentSearchPath()}
+				tree.WalkExprConst(&v, on.Expr)
+				if v.reason != "" {
+					return v.reason, nil
+				}
+			}
+
+		case *tree.AliasedTableExpr:
+			tn, ok := t.Expr.(*tree.TableName)
+			if !ok || t.Ordinality || t.Lateral || len(t.As.Cols) > 0 || !tn.ExplicitCatalog {
+				return "the view query reads from an expression that is not a table", nil
+			}
+			_, desc, err := p.Descriptors().GetImmutableTableByName(
+				ctx, p.txn, tn, tree.ObjectLookupFlagsWithRequired(),
+			)
+			if err != nil {
+				return "", err
+			}
+			if !desc.IsTable() || desc.IsVirtualTable() {
+				return fmt.Sprintf("the view query reads from %q, which is not a table", tn.Object()), nil
+			}
+			src := incrementalRefreshSource{desc: desc, ref: tn.ToUnresolvedObjectName()}
+			if t.As.Alias != "" {
+				src.ref = tree.NewUnqualifiedTableName(t.As.Alias).ToUnresolvedObjectName()
+			}
+			pk := desc.GetPrimaryIndex()
+			for i := 0; i < pk.NumKeyColumns(); i++ {
+				src.pkCols = append(src.pkCols, &tree.ColumnItem{
+					TableName:  src.ref,
+					ColumnName: tree.Name(pk.GetKeyColumnName(i)),
+				})
+			}
+			r.sources = append(r.sources, src)
+
+		default:
+			return "the view query reads from an expression that is not a table", nil
+		}
+	}
+	return "", nil
+}
+
+// findGroupingKey uses the grouping columns of the view query as the key of
+// the view.
+func (r *incrementalRefresh) findGroupingKey() (reason string) {
+	for _, g := range r.sel.GroupBy {
+		switch g.(type) {
+		case *tree.GroupingSet, *tree.Tuple, *tree.NumVal:
+			return "the view query uses a complex GROUP BY clause"
+		}
+		ord := -1
+		for i, e := range r.sel.Exprs {
+			if tree.AsString(e.Expr) == tree.AsString(g) {
+				ord = i
+				break
+			}
+		}
+		if ord == -1 {
+			// The grouping expression can also refer to a column of the view by
+			// its name.
+			if n, ok := g.(*tree.UnresolvedName); ok && n.NumParts == 1 && !n.Star {
+				for i, e := range r.sel.Exprs {
+					if e.As != "" && string(e.As) == n.Parts[0] {
+						ord = i
+						break
+					}
+				}
+			}
+		}
+		if ord == -1 {
+			return fmt.Sprintf("the grouping column %s is not part of the view", tree.AsString(g))
+		}
+		r.addKeyColumn(ord)
+	}
+	return ""
+}
+
+// findPrimaryKey uses the primary key columns of all the base tables as the key
+// of the view.
+func (r *incrementalRefresh) findPrimaryKey() (reason string) {
+	for i := range r.sources {
+		src := &r.sources[i]
+		pk := src.desc.GetPrimaryIndex()
+		for j := 0; j < pk.NumKeyColumns(); j++ {
+			colName := pk.GetKeyColumnName(j)
+			ord := -1
+			for k, e := range r.sel.Exprs {
+				if r.resolveColumn(e.Expr) == src && r.columnName(e.Expr) == colName {
+					ord = k
+					break
+				}
+			}
+			if ord == -1 {
+				return fmt.Sprintf(
+					"the primary key column %s of %s is not part of the view",
+					colName, src.ref.Object(),
+				)
+			}
+			r.addKeyColumn(ord)
+		}
+	}
+	return ""
+}
+
+func (r *incrementalRefresh) addKeyColumn(ord int) {
+	for _, o := range r.keyOrdinals {
+		if o == ord {
+			return
+		}
+	}
+	r.keyOrdinals = append(r.keyOrdinals, ord)
+	r.keyExprs = append(r.keyExprs, r.sel.Exprs[ord].Expr)
+}
+
+// columnName returns the name of the column that the given expression refers
+// to, or the empty string if it isn't a column reference.
+func (r *incrementalRefresh) columnName(e tree.Expr) string {
+	n, ok := e.(*tree.UnresolvedName)
+	if !ok || n.Star {
+		return ""
+	}
+	return n.Parts[0]
+}
+
+// resolveColumn returns the source of the column that the given expression
+// refers to, or nil if the expression isn't a column reference or the source
+// can't be determined.
+func (r *incrementalRefresh) resolveColumn(e tree.Expr) *incrementalRefreshSource {
+	n, ok := e.(*tree.UnresolvedName)
+	if !ok || n.Star {
+		return nil
+	}
+	var res *incrementalRefreshSource
+	for i := range r.sources {
+		src := &r.sources[i]
+		if n.NumParts > 1 {
+			if n.Parts[1] == src.ref.Object() {
+				if res != nil {
+					return nil
+				}
+				res = src
+			}
+			continue
+		}
+		if _, err := src.desc.FindColumnWithName(tree.Name(n.Parts[0])); err == nil {
+			if res != nil {
+				// The column name is ambiguous.
+				return nil
+			}
+			res = src
+		}
+	}
+	return res
+}
+
+// incrementalRefreshVisitor checks whether the expressions of a view query
+// allow the view to be refreshed incrementally.
+type incrementalRefreshVisitor struct {
+	searchPath   sessiondata.SearchPath
+	hasAggregate bool
+	reason       string
+}
+
+var _ tree.Visitor = &incrementalRefreshVisitor{}
+
+func (v *incrementalRefreshVisitor) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if v.reason != "" {
+		return false, expr
+	}
+	switch t := expr.(type) {
+	case *tree.Subquery:
+		v.reason = "the view query contains a subquery"
+		return false, expr
+
+	case *tree.FuncExpr:
+		if t.WindowDef != nil {
+			v.reason = "the view query contains a window function"
+			return false, expr
+		}
+		def, err := t.Func.Resolve(v.searchPath)
+		if err != nil {
+			v.reason = fmt.Sprintf("the view query contains an unknown function %s", &t.Func)
+			return false, expr
+		}
+		switch def.Class {
+		case tree.AggregateClass:
+			v.hasAggregate = true
+		case tree.WindowClass:
+			v.reason = "the view query contains a window function"
+			return false, expr
+		}
+	}
+	return true, expr
+}
+
+func (v *incrementalRefreshVisitor) VisitPost(expr tree.Expr) tree.Expr { return expr }
+
+// refreshMaterializedViewIncrementally applies the changes made to the base
+// tables of the given view since its last refresh to the stored view data, and
+// records the read timestamp of the current transaction as the new last refresh
+// time of the view. If the changes can't be determined, a non-empty reason is
+// returned and nothing is written.
+func (p *planner) refreshMaterializedViewIncrementally(
+	ctx context.Context, view *tabledesc.Mutable, r *incrementalRefresh, jobDesc string,
+) (reason string, _ error) {
+	from := view.LastRefreshTime
+	if from.IsEmpty() {
+		return "the time of the last refresh of the view is unknown", nil
+	}
+	to := p.txn.ReadTimestamp()
+	log.VEventf(ctx, 2, "refreshing view %q incrementally from %s to %s", view.Name, from, to)
+
+	// Find the primary keys of the base table rows that changed since the
+	// last refresh, and restrict the view query to those rows.
+	maxChanged := int(incrementalRefreshMaxChangedRows.Get(&p.ExecCfg().Settings.SV))
+	numChanged := 0
+	changed := make(map[descpb.ID][]tree.Datums)
+	var deltaFilter tree.Expr
+	for i := range r.sources {
+		src := &r.sources[i]
+		id := src.desc.GetID()
+		if _, ok := changed[id]; !ok {
+			if reason, err := p.checkRevisionsRetained(ctx, src.desc, from, to); reason != "" || err != nil {
+				return reason, err
+			}
+			pks, err := p.changedPrimaryKeys(ctx, src.desc, from, to, maxChanged-numChanged)
+			if err != nil {
+				return "", err
+			}
+			numChanged += len(pks)
+			if numChanged > maxChanged {
+				return fmt.Sprintf(
+					"more than %d rows of the base tables changed since the last refresh of the view",
+					maxChanged,
+				), nil
+			}
+			changed[id] = pks
+		}
+		if pks := changed[id]; len(pks) > 0 {
+			deltaFilter = orExprs(deltaFilter, makeKeyFilter(src.pkCols, pks))
+		}
+	}
+
+	if deltaFilter != nil {
+		// Find the keys of the view rows that the changed rows contributed to
+		// as of the last refresh, and contribute to now.
+		var keys []tree.Datums
+		seen := make(map[string]struct{})
+		for _, asOf := range []*hlc.Timestamp{&from, nil} {
+			rows, err := p.queryAffectedViewKeys(ctx, r, deltaFilter, asOf)
+			if err != nil {
+				if errors.HasType(err, (*roachpb.BatchTimestampBeforeGCError)(nil)) {
+					return "the changes since the last refresh of the view have been garbage collected", nil
+				}
+				return "", err
+			}
+			for _, key := range rows {
+				s := tree.AsStringWithFlags(&tree.Tuple{Exprs: datumsToExprs(key)}, tree.FmtParsable)
+				if _, ok := seen[s]; !ok {
+					seen[s] = struct{}{}
+					keys = append(keys, key)
+				}
+			}
+		}
+		if len(keys) > 0 {
+			if err := p.replaceViewRows(ctx, view, r, keys); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	view.LastRefreshTime = to
+	return "", p.writeSchemaChange(ctx, view, descpb.InvalidMutationID, jobDesc)
+}
+
+// checkRevisionsRetained returns a non-empty reason if the MVCC revisions of
+// the given table between the two timestamps may have been garbage collected.
+func (p *planner) checkRevisionsRetained(
+	ctx context.Context, desc catalog.TableDescriptor, from, to hlc.Timestamp,
+) (reason string, _ error) {
+	_, zone, _, err := GetZoneConfigInTxn(
+		ctx, p.txn, p.ExecCfg().Codec, desc.GetID(), nil /* index */, "" /* partition */, true, /* getInheritedDefault */
+	)
+	if err != nil {
+		return "", err
+	}
+	if zone.GC == nil {
+		return "", nil
+	}
+	ttl := time.Duration(zone.GC.TTLSeconds) * time.Second
+	if to.GoTime().Sub(from.GoTime()) >= ttl {
+		return fmt.Sprintf(
+			"the changes to %q since the last refresh of the view may have been garbage collected",
+			desc.GetName(),
+		), nil
+	}
+	return "", nil
+}
+
+// changedPrimaryKeysPageSize is the target size of the SSTs exported by each
+// request of changedPrimaryKeys.
+const changedPrimaryKeysPageSize = 1 << 20 // 1 MiB
+
+// changedPrimaryKeys returns the primary keys of the rows of the given table
+// that were written in the time interval (from, to]. At most limit+1 keys are
+// returned, so that callers can tell when more than limit rows changed. The
+// revisions are exported one page at a time, so that the export stops once
+// enough rows have been seen.
+func (p *planner) changedPrimaryKeys(
+	ctx context.Context, desc catalog.TableDescriptor, from, to hlc.Timestamp, limit int,
+) ([]tree.Datums, error) {
+	codec := p.ExecCfg().Codec
+	pk := desc.GetPrimaryIndex()
+	colTypes := make([]*types.T, pk.NumKeyColumns())
+	colDirs := make([]descpb.IndexDescriptor_Direction, pk.NumKeyColumns())
+	for i := range colTypes {
+		col, err := desc.FindColumnWithID(pk.GetKeyColumnID(i))
+		if err != nil {
+			return nil, err
+		}
+		colTypes[i] = col.GetType()
+		colDirs[i] = pk.GetKeyColumnDirection(i)
+	}
+
+	var alloc rowenc.DatumAlloc
+	var res []tree.Datums
+	var lastRow roachpb.Key
+	// addKey decodes the primary key of the given key if it belongs to a row
+	// that was not seen yet. Every column family of a row is stored under its
+	// own key, all of which share the same row prefix.
+	addKey := func(key roachpb.Key) error {
+		n, err := keys.GetRowPrefixLength(key)
+		if err != nil {
+			return err
+		}
+		if key[:n].Equal(lastRow) {
+			return nil
+		}
+		lastRow = append(roachpb.Key(nil), key[:n]...)
+		vals := make([]rowenc.EncDatum, len(colTypes))
+		if _, _, _, err := rowenc.DecodeIndexKey(codec, colTypes, vals, colDirs, lastRow); err != nil {
+			return err
+		}
+		datums := make(tree.Datums, len(vals))
+		for i := range vals {
+			if err := vals[i].EnsureDecoded(colTypes[i], &alloc); err != nil {
+				return err
+			}
+			datums[i] = vals[i].Datum
+		}
+		res = append(res, datums)
+		return nil
+	}
+
+	span := desc.PrimaryIndexSpan(codec)
+	for {
+		// TargetBytes forces the export to paginate after a single SST.
+		header := roachpb.Header{Timestamp: to, TargetBytes: 1}
+		req := &roachpb.ExportRequest{
+			RequestHeader:  roachpb.RequestHeaderFromSpan(span),
+			StartTime:      from,
+			MVCCFilter:     roachpb.MVCCFilter_All,
+			ReturnSST:      true,
+			TargetFileSize: changedPrimaryKeysPageSize,
+		}
+		resp, pErr := kv.SendWrappedWith(ctx, p.ExecCfg().DB.NonTransactionalSender(), header, req)
+		if pErr != nil {
+			return nil, pErr.GoError()
+		}
+		exportResp := resp.(*roachpb.ExportResponse)
+		for _, file := range exportResp.Files {
+			if err := func() error {
+				iter, err := storage.NewMemSSTIterator(file.SST, false /* verify */)
+				if err != nil {
+					return err
+				}
+				defer iter.Close()
+				for iter.SeekGE(storage.MVCCKey{Key: span.Key}); len(res) <= limit; iter.Next() {
+					if ok, err := iter.Valid(); err != nil || !ok {
+						return err
+					}
+					if err := addKey(iter.UnsafeKey().Key); err != nil {
+						return err
+					}
+				}
+				return nil
+			}(); err != nil {
+				return nil, err
+			}
+			if len(res) > limit {
+				return res, nil
+			}
+		}
+		if exportResp.ResumeSpan == nil {
+			return res, nil
+		}
+		span = *exportResp.ResumeSpan
+	}
+}
+
+// queryAffectedViewKeys returns the keys of the view rows to which the base
+// table rows matching deltaFilter contribute. If asOf is set, the base tables
+// are read as of that time outside of the current transaction.
+func (p *planner) queryAffectedViewKeys(
+	ctx context.Context, r *incrementalRefresh, deltaFilter tree.Expr, asOf *hlc.Timestamp,
+) ([]tree.Datums, error) {
+	sel := &tree.SelectClause{
+		Distinct: true,
+		From:     tree.From{Tables: r.sel.From.Tables},
+		Where:    tree.NewWhere(tree.AstWhere, deltaFilter),
+	}
+	for _, e := range r.keyExprs {
+		sel.Exprs = append(sel.Exprs, tree.SelectExpr{Expr: e})
+	}
+	if r.sel.Where != nil {
+		sel.Where.Expr = &tree.AndExpr{Left: &tree.ParenExpr{Expr: r.sel.Where.Expr}, Right: &tree.ParenExpr{Expr: deltaFilter}}
+	}
+	txn := p.txn
+	if asOf != nil {
+		sel.From.AsOf = tree.AsOfClause{Expr: tree.NewStrVal(asOf.AsOfSystemTime())}
+		txn = nil
+	}
+	return p.ExecCfg().InternalExecutor.QueryBufferedEx(
+		ctx, "refresh-view-keys", txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		tree.AsStringWithFlags(&tree.Select{Select: sel}, tree.FmtParsable),
+	)
+}
+
+// replaceViewRows deletes the stored rows of the view with the given keys, and
+// inserts the rows with those keys computed by the view query.
+func (p *planner) replaceViewRows(
+	ctx context.Context, view *tabledesc.Mutable, r *incrementalRefresh, keys []tree.Datums,
+) error {
+	cols := view.PublicColumns()
+	var visibleNames tree.NameList
+	var allCols tree.SelectExprs
+	for _, col := range cols {
+		if !col.IsHidden() {
+			visibleNames = append(visibleNames, col.ColName())
+		}
+		allCols = append(allCols, tree.SelectExpr{Expr: &tree.ColumnItem{ColumnName: col.ColName()}})
+	}
+	keyCols := make(tree.Exprs, len(r.keyOrdinals))
+	for i, ord := range r.keyOrdinals {
+		keyCols[i] = &tree.ColumnItem{ColumnName: visibleNames[ord]}
+	}
+	filter := tree.NewWhere(tree.AstWhere, makeKeyFilter(keyCols, keys))
+	override := sessiondata.InternalExecutorOverride{User: security.RootUserName()}
+
+	// Read the stored rows of the view with the given keys.
+	oldRows, err := p.ExecCfg().InternalExecutor.QueryBufferedEx(
+		ctx, "refresh-view-old-rows", p.txn, override,
+		tree.AsStringWithFlags(&tree.Select{Select: &tree.SelectClause{
+			Exprs: allCols,
+			From: tree.From{Tables: tree.TableExprs{&tree.TableRef{
+				TableID: int64(view.GetID()),
+				As:      tree.AliasClause{Alias: "v"},
+			}}},
+			Where: filter,
+		}}, tree.FmtParsable),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Compute the rows of the view with the given keys.
+	newRows, err := p.ExecCfg().InternalExecutor.QueryBufferedEx(
+		ctx, "refresh-view-new-rows", p.txn, override,
+		tree.AsStringWithFlags(&tree.Select{Select: &tree.SelectClause{
+			Exprs: tree.SelectExprs{tree.StarSelectExpr()},
+			From: tree.From{Tables: tree.TableExprs{&tree.AliasedTableExpr{
+				Expr: &tree.Subquery{Select: &tree.ParenSelect{Select: &tree.Select{Select: r.sel}}},
+				As:   tree.AliasClause{Alias: "v", Cols: visibleNames},
+			}}},
+			Where: filter,
+		}}, tree.FmtParsable),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Write out the changes, deleting the old rows before inserting the new
+	// ones so that they don't conflict in unique indexes of the view.
+	internal := p.SessionData().Internal
+	sv := &p.ExecCfg().Settings.SV
+	traceKV := p.ExtendedEvalContext().Tracing.KVTracingEnabled()
+	td := tableDeleter{
+		rd:    row.MakeDeleter(p.ExecCfg().Codec, view, cols, sv, internal, p.ExecCfg().GetRowMetrics(internal)),
+		alloc: p.alloc,
+	}
+	if err := td.init(ctx, p.txn, p.EvalContext(), sv); err != nil {
+		return err
+	}
+	defer td.close(ctx)
+	var pm row.PartialIndexUpdateHelper
+	for _, values := range oldRows {
+		if err := td.row(ctx, values, pm, traceKV); err != nil {
+			return err
+		}
+		if err := maybeFlushTableWriter(ctx, &td.tableWriterBase); err != nil {
+			return err
+		}
+	}
+	if err := td.finalize(ctx); err != nil {
+		return err
+	}
+
+	ri, err := row.MakeInserter(
+		ctx, p.txn, p.ExecCfg().Codec, view, cols, p.alloc, sv, internal, p.ExecCfg().GetRowMetrics(internal),
+	)
+	if err != nil {
+		return err
+	}
+	ti := tableInserter{ri: ri}
+	if err := ti.init(ctx, p.txn, p.EvalContext(), sv); err != nil {
+		return err
+	}
+	defer ti.close(ctx)
+	values := make(tree.Datums, len(cols))
+	for _, newRow := range newRows {
+		next := 0
+		for i, col := range cols {
+			if col.IsHidden() {
+				// The only hidden column of a view is its row ID.
+				values[i] = tree.NewDInt(builtins.GenerateUniqueInt(p.EvalContext().NodeID.SQLInstanceID()))
+				continue
+			}
+			values[i] = newRow[next]
+			next++
+		}
+		if err := ti.row(ctx, values, pm, traceKV); err != nil {
+			return err
+		}
+		if err := maybeFlushTableWriter(ctx, &ti.tableWriterBase); err != nil {
+			return err
+		}
+	}
+	return ti.finalize(ctx)
+}
+
+// maybeFlushTableWriter sends the current batch of the table writer if it
+// has grown large enough.
+func maybeFlushTableWriter(ctx context.Context, tb *tableWriterBase) error {
+	if tb.currentBatchSize >= tb.maxBatchSize ||
+		tb.b.ApproximateMutationBytes() >= tb.maxBatchByteSize {
+		return tb.flushAndStartNewBatch(ctx)
+	}
+	return nil
+}
+
+// makeKeyFilter returns an expression that is true for the rows in which the
+// given columns are equal to one of the given keys. NULL values are considered
+// equal to each other.
+func makeKeyFilter(cols tree.Exprs, keys []tree.Datums) tree.Expr {
+	var filter tree.Expr
+	var inList tree.Exprs
+	for _, key := range keys {
+		hasNull := false
+		for _, d := range key {
+			hasNull = hasNull || d == tree.DNull
+		}
+		if !hasNull {
+			if len(cols) == 1 {
+				inList = append(inList, key[0])
+			} else {
+				inList = append(inList, &tree.Tuple{Exprs: datumsToExprs(key)})
+			}
+			continue
+		}
+		var conj tree.Expr
+		for i, d := range key {
+			cmp := &tree.ComparisonExpr{
+				Operator: tree.MakeComparisonOperator(tree.IsNotDistinctFrom),
+				Left:     cols[i],
+				Right:    d,
+			}
+			if conj == nil {
+				conj = cmp
+			} else {
+				conj = &tree.AndExpr{Left: conj, Right: cmp}
+			}
+		}
+		filter = orExprs(filter, &tree.ParenExpr{Expr: conj})
+	}
+	if len(inList) > 0 {
+		left := cols[0]
+		if len(cols) > 1 {
+			left = &tree.Tuple{Exprs: cols}
+		}
+		filter = orExprs(&tree.ComparisonExpr{
+			Operator: tree.MakeComparisonOperator(tree.In),
+			Left:     left,
+			Right:    &tree.Tuple{Exprs: inList},
+		}, filter)
+	}
+	return filter
+}
+
+func orExprs(left, right tree.Expr) tree.Expr {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	return &tree.OrExpr{Left: left, Right: right}
+}
+
+func datumsToExprs(datums tree.Datums) tree.Exprs {
+	exprs := make(tree.Exprs, len(datums))
+	for i, d := range datums {
+		exprs[i] = d
+	}
+	return exprs
+}
@@ -0,0 +1,343 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobsprotectedts"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/protectedts"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/roleoption"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/uuid"
+	"github.com/cockroachdb/errors"
+)
+
+// pgoutputPlugin is the name of the only supported output plugin of logical
+// replication slots.
+const pgoutputPlugin = "pgoutput"
+
+// replicationSlotNameMaxLength is the maximum length of the name of a
+// replication slot, which is NAMEDATALEN-1 in PostgreSQL.
+const replicationSlotNameMaxLength = 63
+
+// replicationSlot is a logical replication slot. Slots are persisted as jobs
+// of type TypeReplicationSlot, which run until the slot is dropped, so that
+// they survive restarts.
+type replicationSlot struct {
+	jobID    jobspb.JobID
+	details  jobspb.ReplicationSlotDetails
+	progress jobspb.ReplicationSlotProgress
+}
+
+// active returns whether a session is streaming the changes of the slot at
+// the given time.
+func (s *replicationSlot) active(now hlc.Timestamp) bool {
+	return s.progress.ActiveSession != "" && now.Less(s.progress.ActiveExpiration)
+}
+
+// restartPoint returns the time after which the changes of the slot haven't
+// been confirmed by the client yet.
+func (s *replicationSlot) restartPoint() hlc.Timestamp {
+	ts := s.details.ConsistentPoint
+	ts.Forward(s.progress.ConfirmedFlush)
+	return ts
+}
+
+// activeJob is a job which hasn't finished, as returned by getActiveJobs.
+type activeJob struct {
+	id       jobspb.JobID
+	status   jobs.Status
+	payload  *jobspb.Payload
+	progress *jobspb.Progress
+}
+
+// getActiveJobs returns the jobs of the given type which haven't finished,
+// reading the jobs table in the given transaction.
+func getActiveJobs(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn, opName string, typ jobspb.Type,
+) ([]activeJob, error) {
+	rows, err := execCfg.InternalExecutor.QueryBufferedEx(
+		ctx, opName, txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		`SELECT id, status, payload, progress FROM system.jobs WHERE status IN ($1, $2, $3, $4)`,
+		jobs.StatusPending, jobs.StatusRunning, jobs.StatusPaused, jobs.StatusPauseRequested,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var active []activeJob
+	for _, row := range rows {
+		payload, err := jobs.UnmarshalPayload(row[2])
+		if err != nil {
+			return nil, err
+		}
+		if payload.Type() != typ {
+			continue
+		}
+		progress, err := jobs.UnmarshalProgress(row[3])
+		if err != nil {
+			return nil, err
+		}
+		active = append(active, activeJob{
+			id:       jobspb.JobID(tree.MustBeDInt(row[0])),
+			status:   jobs.Status(tree.MustBeDString(row[1])),
+			payload:  payload,
+			progress: progress,
+		})
+	}
+	return active, nil
+}
+
+// getReplicationSlots returns the replication slots which haven't been
+// dropped, reading the jobs table in the given transaction.
+func getReplicationSlots(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn,
+) ([]replicationSlot, error) {
+	active, err := getActiveJobs(ctx, execCfg, txn, "get-replication-slots", jobspb.TypeReplicationSlot)
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]replicationSlot, 0, len(active))
+	for _, j := range active {
+		slot := replicationSlot{jobID: j.id, details: *j.payload.GetReplicationSlot()}
+		if p := j.progress.GetReplicationSlot(); p != nil {
+			slot.progress = *p
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+// lookupReplicationSlot returns the replication slot with the given name, or
+// nil if it doesn't exist.
+func lookupReplicationSlot(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn, slotName string,
+) (*replicationSlot, error) {
+	slots, err := getReplicationSlots(ctx, execCfg, txn)
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		if slots[i].details.SlotName == slotName {
+			return &slots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// CreateReplicationSlot is part of the tree.EvalPlanner interface.
+func (p *planner) CreateReplicationSlot(
+	ctx context.Context, slotName, plugin string, temporary bool,
+) (hlc.Timestamp, error) {
+	if err := checkReplicationSlotName(slotName); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if plugin != pgoutputPlugin {
+		return hlc.Timestamp{}, errors.WithHintf(
+			pgerror.Newf(pgcode.UndefinedObject, "output plugin %q is not supported", plugin),
+			"Only the %q plugin is supported.", pgoutputPlugin,
+		)
+	}
+	if temporary {
+		return hlc.Timestamp{}, unimplemented.New("temporary replication slots",
+			"temporary replication slots are not supported")
+	}
+	if err := p.checkCanUseReplicationSlots(ctx); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	dbName := p.CurrentDatabase()
+	if dbName == "" {
+		return hlc.Timestamp{}, pgerror.New(pgcode.UndefinedDatabase,
+			"cannot create a replication slot without a current database")
+	}
+	dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, dbName, tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	existing, err := lookupReplicationSlot(ctx, p.ExecCfg(), p.txn, slotName)
+	if err != nil {
+		return hlc.Timestamp{}, err
+	}
+	if existing != nil {
+		return hlc.Timestamp{}, pgerror.Newf(pgcode.DuplicateObject,
+			"replication slot %q already exists", slotName)
+	}
+
+	// The changes committed after the read timestamp of the transaction
+	// creating the slot are decoded by the slot. A protected timestamp prevents
+	// them from being garbage collected until the client confirms them.
+	consistentPoint := p.txn.ReadTimestamp()
+	registry := p.ExecCfg().JobRegistry
+	jobID := registry.MakeJobID()
+	details := jobspb.ReplicationSlotDetails{
+		SlotName:                 slotName,
+		DatabaseID:               dbDesc.GetID(),
+		Plugin:                   plugin,
+		ConsistentPoint:          consistentPoint,
+		ProtectedTimestampRecord: uuid.MakeV4(),
+	}
+	rec := jobsprotectedts.MakeRecord(
+		details.ProtectedTimestampRecord, int64(jobID), consistentPoint,
+		replicationSlotSpansToProtect(p.ExecCfg().Codec), jobsprotectedts.Jobs,
+	)
+	if err := p.ExecCfg().ProtectedTimestampProvider.Protect(ctx, p.txn, rec); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	record := jobs.Record{
+		Description: fmt.Sprintf("replication slot %s", tree.NameString(slotName)),
+		Username:    p.User(),
+		Details:     details,
+		Progress:    jobspb.ReplicationSlotProgress{},
+	}
+	if _, err := registry.CreateJobWithTxn(ctx, record, jobID, p.txn); err != nil {
+		return hlc.Timestamp{}, err
+	}
+	return consistentPoint, nil
+}
+
+// DropReplicationSlot is part of the tree.EvalPlanner interface.
+func (p *planner) DropReplicationSlot(ctx context.Context, slotName string) error {
+	if err := p.checkCanUseReplicationSlots(ctx); err != nil {
+		return err
+	}
+	slot, err := lookupReplicationSlot(ctx, p.ExecCfg(), p.txn, slotName)
+	if err != nil {
+		return err
+	}
+	if slot == nil {
+		return pgerror.Newf(pgcode.UndefinedObject,
+			"replication slot %q does not exist", slotName)
+	}
+	if slot.active(p.txn.ReadTimestamp()) {
+		return pgerror.Newf(pgcode.ObjectInUse,
+			"replication slot %q is active", slotName)
+	}
+	// Canceling the job of the slot releases its protected timestamp.
+	return p.ExecCfg().JobRegistry.CancelRequested(ctx, p.txn, slot.jobID)
+}
+
+// checkCanUseReplicationSlots returns an error if the current user can't
+// create, drop or stream from replication slots, or if the cluster can't
+// stream the changes of slots. Like changefeeds, slots require the
+// CONTROLCHANGEFEED role option and rangefeeds.
+func (p *planner) checkCanUseReplicationSlots(ctx context.Context) error {
+	ok, err := p.HasRoleOption(ctx, roleoption.CONTROLCHANGEFEED)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return pgerror.New(pgcode.InsufficientPrivilege,
+			"must be admin or have the CONTROLCHANGEFEED role option to use replication slots")
+	}
+	if !p.ExecCfg().Codec.ForSystemTenant() {
+		return pgerror.New(pgcode.FeatureNotSupported,
+			"replication slots are not supported by tenants")
+	}
+	// The setting is defined in kvserver, which this package doesn't depend
+	// on, so it is looked up by name.
+	if s, ok := settings.Lookup("kv.rangefeed.enabled", settings.LookupForLocalAccess); ok {
+		if enabled, ok := s.(*settings.BoolSetting); ok && !enabled.Get(&p.ExecCfg().Settings.SV) {
+			return pgerror.New(pgcode.ObjectNotInPrerequisiteState,
+				"replication slots require the kv.rangefeed.enabled setting")
+		}
+	}
+	return nil
+}
+
+// checkReplicationSlotName returns an error if the name isn't a valid name
+// for a replication slot. Like in PostgreSQL, names can only contain lower
+// case letters, numbers and underscores.
+func checkReplicationSlotName(name string) error {
+	if name == "" {
+		return pgerror.New(pgcode.InvalidName, "replication slot name \"\" is too short")
+	}
+	if len(name) > replicationSlotNameMaxLength {
+		return pgerror.Newf(pgcode.NameTooLong, "replication slot name %q is too long", name)
+	}
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z') && !(c >= '0' && c <= '9') && c != '_' {
+			return errors.WithHint(
+				pgerror.Newf(pgcode.InvalidName,
+					"replication slot name %q contains invalid character", name),
+				"Replication slot names may only contain lower case letters, numbers, and the underscore character.",
+			)
+		}
+	}
+	return nil
+}
+
+// replicationSlotSpansToProtect returns the spans protected from garbage
+// collection by replication slots: the data of all the user tables, which
+// may be added to publications at any time, and the descriptors, whose
+// history is needed to decode the changes.
+func replicationSlotSpansToProtect(codec keys.SQLCodec) []roachpb.Span {
+	descriptorsPrefix := codec.TablePrefix(keys.DescriptorTableID)
+	return []roachpb.Span{
+		{Key: descriptorsPrefix, EndKey: descriptorsPrefix.PrefixEnd()},
+		{Key: codec.TablePrefix(keys.MinUserDescID), EndKey: codec.TablePrefix(math.MaxUint32).PrefixEnd()},
+	}
+}
+
+// replicationSlotResumer is the resumer of the jobs of replication slots. The
+// job does nothing but keep the slot alive until it is dropped, which cancels
+// the job; the changes are streamed by the sessions which use the slot.
+type replicationSlotResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &replicationSlotResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *replicationSlotResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. It releases the
+// protected timestamp of the slot.
+func (r *replicationSlotResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.ReplicationSlotDetails)
+	return execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		err := execCfg.ProtectedTimestampProvider.Release(ctx, txn, details.ProtectedTimestampRecord)
+		if errors.Is(err, protectedts.ErrNotExists) {
+			return nil
+		}
+		return err
+	})
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeReplicationSlot, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &replicationSlotResumer{job: job}
+	})
+}
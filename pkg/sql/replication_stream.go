@@ -0,0 +1,834 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"container/heap"
+	"context"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/rangefeed"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgwirebase"
+	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
+	"github.com/cockroachdb/cockroach/pkg/sql/row"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
+	"github.com/cockroachdb/cockroach/pkg/util"
+	"github.com/cockroachdb/cockroach/pkg/util/fsm"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+	"github.com/lib/pq/oid"
+)
+
+// replicationSlotLeaseDuration is how long a replication slot remains active
+// after the session streaming its changes last renewed its lease.
+const replicationSlotLeaseDuration = 30 * time.Second
+
+// replicationKeepaliveInterval is the interval at which the session streaming
+// the changes of a replication slot renews its lease, persists the position
+// confirmed by the client and sends a keepalive message.
+const replicationKeepaliveInterval = 10 * time.Second
+
+// execStartReplication handles the START_REPLICATION command of replication
+// connections. Like execCopyIn, it takes control of the network connection,
+// in order to read the status updates sent by the client until the client
+// ends the stream with a CopyDone message. The changes are sent to the client
+// through res.
+func (ex *connExecutor) execStartReplication(
+	ctx context.Context, cmd StartReplication, res ReplicationResult,
+) (fsm.Event, fsm.EventPayload) {
+	s := &replicationStream{
+		execCfg:   ex.server.cfg,
+		cmd:       cmd,
+		res:       res,
+		sessionID: ex.sessionID.String(),
+		p:         planner{execCfg: ex.server.cfg, alloc: &rowenc.DatumAlloc{}},
+		fetchers:  make(map[idVersion]*row.Fetcher),
+		relations: make(map[descpb.ID]descpb.DescriptorVersion),
+		bufferAcc: ex.sessionMon.MakeBoundAccount(),
+	}
+	defer s.bufferAcc.Close(ctx)
+	defer func() {
+		// If the goroutine reading the status updates was started, it hands
+		// control of the connection back once the client ends the stream.
+		if !s.readerStarted {
+			cmd.Done.Done()
+		}
+	}()
+
+	if _, isNoTxn := ex.machine.CurState().(stateNoTxn); !isNoTxn {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{
+			err: pgerror.New(pgcode.ActiveSQLTransaction,
+				"START_REPLICATION cannot be executed inside a transaction")}
+		return ev, payload
+	}
+
+	txnOpt, cleanup := ex.makeCopyTxnOpt(ctx, false /* isOpen */)
+	defer cleanup(ctx)
+	err := s.setup(ctx, txnOpt)
+	if err == nil {
+		err = s.run(ctx)
+	}
+	if err != nil {
+		ev := eventNonRetriableErr{IsCommit: fsm.False}
+		payload := eventNonRetriableErrPayload{err: err}
+		return ev, payload
+	}
+	return nil, nil
+}
+
+// replicationStream streams the changes of a logical replication slot to a
+// replication connection, encoded with the pgoutput plugin.
+//
+// The changes are read with one rangefeed per published table. They are
+// buffered until the frontier of all the rangefeeds passes their timestamp,
+// and are then sent grouped by timestamp, each group forming a transaction.
+// The tables are resolved when streaming starts, so the tables added to the
+// publications afterwards are only streamed after the client reconnects.
+type replicationStream struct {
+	execCfg   *ExecutorConfig
+	cmd       StartReplication
+	res       ReplicationResult
+	sessionID string
+	p         planner
+
+	slot   *replicationSlot
+	tables map[descpb.ID]*replicationTable
+
+	// start is the timestamp after which the changes are streamed.
+	start hlc.Timestamp
+	// frontiers are the resolved timestamps of the rangefeeds, and resolved is
+	// their minimum. All the changes up to resolved have been sent.
+	frontiers []hlc.Timestamp
+	resolved  hlc.Timestamp
+	// buffer holds the changes which haven't been resolved yet, ordered by
+	// timestamp. Its memory is accounted for by bufferAcc.
+	buffer    replicationBuffer
+	bufferAcc mon.BoundAccount
+	// seq is the number of changes which were added to the buffer.
+	seq uint64
+	// txn holds the changes of the transaction being sent.
+	txn []*roachpb.RangeFeedValue
+	// confirmedFlush is the position up to which the client confirmed having
+	// flushed the changes.
+	confirmedFlush hlc.Timestamp
+	// xid is the ID of the last transaction sent to the client.
+	xid uint32
+
+	events chan replicationEvent
+	// readerStarted is set once the goroutine reading the status updates of
+	// the client has been started.
+	readerStarted bool
+
+	// fetchers decode the changes, per version of each table.
+	fetchers map[idVersion]*row.Fetcher
+	alloc    rowenc.DatumAlloc
+	kvs      row.SpanKVFetcher
+	// relations holds the version of the tables described by the last
+	// Relation message sent to the client.
+	relations map[descpb.ID]descpb.DescriptorVersion
+}
+
+// replicationTable is a table published to a replication stream.
+type replicationTable struct {
+	schemaName string
+	span       roachpb.Span
+	// The kinds of changes which are published.
+	insert, update, delete bool
+}
+
+// replicationEvent is an event of the rangefeed of a replication stream.
+// Either value or resolved is set.
+type replicationEvent struct {
+	feed     int
+	value    *roachpb.RangeFeedValue
+	resolved hlc.Timestamp
+}
+
+// bufferedChange is a change held in the buffer of a replication stream.
+type bufferedChange struct {
+	value *roachpb.RangeFeedValue
+	// seq orders the changes received at the same timestamp.
+	seq uint64
+}
+
+// sizeOfBufferedChange is the memory used by a change in the buffer, besides
+// its key and values.
+const sizeOfBufferedChange = int64(unsafe.Sizeof(bufferedChange{})) +
+	int64(unsafe.Sizeof(roachpb.RangeFeedValue{}))
+
+func (c bufferedChange) memUsage() int64 {
+	return sizeOfBufferedChange + int64(len(c.value.Key)) +
+		int64(len(c.value.Value.RawBytes)) + int64(len(c.value.PrevValue.RawBytes))
+}
+
+// replicationBuffer is a min-heap of the changes buffered by a replication
+// stream, ordered by timestamp and then by the order in which they were
+// received.
+type replicationBuffer []bufferedChange
+
+var _ heap.Interface = &replicationBuffer{}
+
+// Len is part of heap.Interface.
+func (b replicationBuffer) Len() int { return len(b) }
+
+// Less is part of heap.Interface.
+func (b replicationBuffer) Less(i, j int) bool {
+	if ts, other := b[i].value.Value.Timestamp, b[j].value.Value.Timestamp; ts != other {
+		return ts.Less(other)
+	}
+	return b[i].seq < b[j].seq
+}
+
+// Swap is part of heap.Interface.
+func (b replicationBuffer) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+
+// Push is part of heap.Interface.
+func (b *replicationBuffer) Push(x interface{}) { *b = append(*b, x.(bufferedChange)) }
+
+// Pop is part of heap.Interface.
+func (b *replicationBuffer) Pop() interface{} {
+	old := *b
+	n := len(old)
+	c := old[n-1]
+	old[n-1] = bufferedChange{}
+	*b = old[:n-1]
+	return c
+}
+
+// idVersion identifies a version of a table.
+type idVersion struct {
+	id      descpb.ID
+	version descpb.DescriptorVersion
+}
+
+// setup resolves the replication slot and the published tables, and claims
+// the slot for the session.
+func (s *replicationStream) setup(ctx context.Context, txnOpt copyTxnOpt) (retErr error) {
+	opts, err := pgrepl.ParsePGOutputOptions(s.cmd.Cmd.Options)
+	if err != nil {
+		return err
+	}
+	p := &s.p
+	cleanup := p.preparePlannerForCopy(ctx, txnOpt)
+	defer func() {
+		retErr = cleanup(ctx, retErr)
+	}()
+
+	if err := p.checkCanUseReplicationSlots(ctx); err != nil {
+		return err
+	}
+	slotName := s.cmd.Cmd.SlotName
+	s.slot, err = lookupReplicationSlot(ctx, s.execCfg, p.txn, slotName)
+	if err != nil {
+		return err
+	}
+	if s.slot == nil {
+		return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q does not exist", slotName)
+	}
+	dbDesc, err := p.Descriptors().GetImmutableDatabaseByName(
+		ctx, p.txn, p.CurrentDatabase(), tree.DatabaseLookupFlags{},
+	)
+	if err != nil {
+		return err
+	}
+	if dbDesc == nil || dbDesc.GetID() != s.slot.details.DatabaseID {
+		return pgerror.Newf(pgcode.ObjectNotInPrerequisiteState,
+			"replication slot %q was not created in this database", slotName)
+	}
+
+	var pubs []descpb.DatabaseDescriptor_PublicationInfo
+	allTables := false
+	for _, name := range opts.PublicationNames {
+		pub, ok := dbDesc.GetPublication(name)
+		if !ok {
+			return pgerror.Newf(pgcode.UndefinedObject, "publication %q does not exist", name)
+		}
+		pubs = append(pubs, pub)
+		allTables = allTables || pub.AllTables
+	}
+	var candidates []catalog.TableDescriptor
+	if allTables {
+		candidates, err = p.Descriptors().GetAllTableDescriptorsInDatabase(ctx, p.txn, dbDesc.GetID())
+		if err != nil {
+			return err
+		}
+	} else {
+		var ids []descpb.ID
+		for _, pub := range pubs {
+			for _, id := range pub.TableIDs {
+				ids = appendUniqueID(ids, id)
+			}
+		}
+		for _, id := range ids {
+			table, err := p.Descriptors().GetImmutableTableByID(
+				ctx, p.txn, id, tree.ObjectLookupFlags{CommonLookupFlags: tree.CommonLookupFlags{Required: true}},
+			)
+			if err != nil {
+				if sqlerrors.IsUndefinedRelationError(err) {
+					continue
+				}
+				return err
+			}
+			candidates = append(candidates, table)
+		}
+	}
+	s.tables = make(map[descpb.ID]*replicationTable)
+	for _, table := range candidates {
+		var t *replicationTable
+		for _, pub := range pubs {
+			if !publicationIncludesTable(pub, table) {
+				continue
+			}
+			if t == nil {
+				t = &replicationTable{span: table.PrimaryIndexSpan(s.execCfg.Codec)}
+			}
+			t.insert = t.insert || pub.PublishInsert
+			t.update = t.update || pub.PublishUpdate
+			t.delete = t.delete || pub.PublishDelete
+		}
+		if t == nil {
+			continue
+		}
+		if err := p.CheckPrivilege(ctx, table, privilege.SELECT); err != nil {
+			return err
+		}
+		if table.NumFamilies() > 1 {
+			return pgerror.Newf(pgcode.FeatureNotSupported,
+				"table %q has multiple column families, which cannot be replicated", table.GetName())
+		}
+		schema, err := p.Descriptors().GetImmutableSchemaByID(
+			ctx, p.txn, table.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		t.schemaName = schema.GetName()
+		s.tables[table.GetID()] = t
+	}
+
+	// Claim the slot, so that no other session streams its changes at the
+	// same time.
+	now := s.execCfg.Clock.Now()
+	if s.slot.active(now) && s.slot.progress.ActiveSession != s.sessionID {
+		return pgerror.Newf(pgcode.ObjectInUse, "replication slot %q is active", slotName)
+	}
+	if err := s.execCfg.JobRegistry.UpdateJobWithTxn(ctx, s.slot.jobID, p.txn, false, /* useReadLock */
+		func(txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+			progress := md.Progress.GetReplicationSlot()
+			progress.ActiveSession = s.sessionID
+			progress.ActiveExpiration = now.Add(replicationSlotLeaseDuration.Nanoseconds(), 0)
+			ju.UpdateProgress(md.Progress)
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	// Changes before the position confirmed by the client are never streamed
+	// again, even if the client asks for an earlier position.
+	s.start = s.slot.restartPoint()
+	s.start.Forward(s.cmd.Cmd.StartLSN.Timestamp())
+	s.resolved = s.start
+	s.confirmedFlush = s.start
+	return nil
+}
+
+// run streams the changes until the client ends the stream, or an error
+// occurs.
+//
+// If an error occurs, the goroutine reading the status updates keeps control
+// of the connection until the client ends the stream or closes the
+// connection, since it can't be interrupted.
+func (s *replicationStream) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		// The session might have been canceled, but the slot is released
+		// nonetheless.
+		s.release(logtags.AddTags(context.Background(), logtags.FromContext(ctx)))
+	}()
+
+	if err := s.res.BeginCopyBoth(ctx); err != nil {
+		return err
+	}
+	statusC := make(chan pgrepl.StandbyStatusUpdate)
+	// doneC receives the result of the reader, which is nil if the client
+	// ended the stream.
+	doneC := make(chan error, 1)
+	if err := s.execCfg.DistSQLSrv.Stopper.RunAsyncTask(ctx, "replication-status-reader",
+		func(context.Context) {
+			defer s.cmd.Done.Done()
+			doneC <- s.readStatusUpdates(ctx, statusC)
+		},
+	); err != nil {
+		return err
+	}
+	s.readerStarted = true
+
+	s.events = make(chan replicationEvent)
+	for id, t := range s.tables {
+		feed := len(s.frontiers)
+		s.frontiers = append(s.frontiers, s.start)
+		rf, err := s.execCfg.RangeFeedFactory.RangeFeed(
+			ctx, "replication-slot-"+s.cmd.Cmd.SlotName, t.span, s.start,
+			func(ctx context.Context, value *roachpb.RangeFeedValue) {
+				select {
+				case s.events <- replicationEvent{feed: feed, value: value}:
+				case <-ctx.Done():
+				}
+			},
+			rangefeed.WithDiff(),
+			rangefeed.WithOnFrontierAdvance(func(ctx context.Context, ts hlc.Timestamp) {
+				select {
+				case s.events <- replicationEvent{feed: feed, resolved: ts}:
+				case <-ctx.Done():
+				}
+			}),
+		)
+		if err != nil {
+			return errors.Wrapf(err, "starting rangefeed on table %d", id)
+		}
+		defer rf.Close()
+	}
+
+	ticker := time.NewTicker(replicationKeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case ev := <-s.events:
+			if err := s.handleEvent(ctx, ev); err != nil {
+				return err
+			}
+		case update := <-statusC:
+			flush := update.FlushLSN.Timestamp()
+			// The client can't confirm changes which haven't been sent.
+			if s.resolved.Less(flush) {
+				flush = s.resolved
+			}
+			s.confirmedFlush.Forward(flush)
+			if update.ReplyRequested {
+				if err := s.sendKeepalive(ctx, false /* replyRequested */); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if len(s.tables) == 0 {
+				// Without any table, nothing is ever sent.
+				s.resolved.Forward(s.execCfg.Clock.Now())
+			}
+			if err := s.checkpoint(ctx); err != nil {
+				return err
+			}
+			if err := s.sendKeepalive(ctx, false /* replyRequested */); err != nil {
+				return err
+			}
+		case err := <-doneC:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// readStatusUpdates reads the messages sent by the client while streaming,
+// until the client ends the stream.
+func (s *replicationStream) readStatusUpdates(
+	ctx context.Context, statusC chan<- pgrepl.StandbyStatusUpdate,
+) error {
+	readBuf := pgwirebase.MakeReadBuffer(
+		pgwirebase.ReadBufferOptionWithClusterSettings(&s.execCfg.Settings.SV),
+	)
+	for {
+		typ, _, err := readBuf.ReadTypedMsg(s.cmd.Conn.Rd())
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case pgwirebase.ClientMsgCopyData:
+			if len(readBuf.Msg) == 0 {
+				return pgerror.New(pgcode.ProtocolViolation, "unexpected empty CopyData message")
+			}
+			switch readBuf.Msg[0] {
+			case pgrepl.StandbyStatusUpdateByte:
+				update, err := pgrepl.ParseStandbyStatusUpdate(readBuf.Msg)
+				if err != nil {
+					return err
+				}
+				select {
+				case statusC <- update:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			case pgrepl.HotStandbyFeedbackByte:
+				// Only used by physical replication.
+			default:
+				return pgerror.Newf(pgcode.ProtocolViolation,
+					"unexpected message type %q in CopyData", readBuf.Msg[0])
+			}
+		case pgwirebase.ClientMsgCopyDone:
+			return nil
+		case pgwirebase.ClientMsgCopyFail:
+			return pgerror.New(pgcode.QueryCanceled, "client canceled replication")
+		case pgwirebase.ClientMsgFlush, pgwirebase.ClientMsgSync:
+			// Ignored in copy mode, like in copy-in mode.
+		default:
+			return pgwirebase.NewUnrecognizedMsgTypeErr(typ)
+		}
+	}
+}
+
+// handleEvent buffers the changes received from the rangefeeds, and sends
+// them once they are resolved.
+func (s *replicationStream) handleEvent(ctx context.Context, ev replicationEvent) error {
+	if ev.value != nil {
+		// The catch-up scans of the rangefeeds include the changes at their
+		// start time, which were already streamed.
+		if s.start.Less(ev.value.Value.Timestamp) {
+			s.seq++
+			c := bufferedChange{value: ev.value, seq: s.seq}
+			if err := s.bufferAcc.Grow(ctx, c.memUsage()); err != nil {
+				return errors.Wrapf(err,
+					"buffering the changes of replication slot %q", s.cmd.Cmd.SlotName)
+			}
+			heap.Push(&s.buffer, c)
+		}
+		return nil
+	}
+	s.frontiers[ev.feed].Forward(ev.resolved)
+	resolved := s.frontiers[0]
+	for _, ts := range s.frontiers[1:] {
+		if ts.Less(resolved) {
+			resolved = ts
+		}
+	}
+	if !s.resolved.Less(resolved) {
+		return nil
+	}
+	for len(s.buffer) > 0 && s.buffer[0].value.Value.Timestamp.LessEq(resolved) {
+		ts := s.buffer[0].value.Value.Timestamp
+		var size int64
+		s.txn = s.txn[:0]
+		for len(s.buffer) > 0 && s.buffer[0].value.Value.Timestamp == ts {
+			c := heap.Pop(&s.buffer).(bufferedChange)
+			s.txn = append(s.txn, c.value)
+			size += c.memUsage()
+		}
+		err := s.sendTxn(ctx, ts, s.txn)
+		for i := range s.txn {
+			s.txn[i] = nil
+		}
+		s.bufferAcc.Shrink(ctx, size)
+		if err != nil {
+			return err
+		}
+	}
+	s.resolved = resolved
+	return nil
+}
+
+// sendTxn sends the changes committed at the given timestamp as a
+// transaction. Nothing is sent if none of the changes is published.
+func (s *replicationStream) sendTxn(
+	ctx context.Context, ts hlc.Timestamp, values []*roachpb.RangeFeedValue,
+) error {
+	lsn := pgrepl.LSNFromTimestamp(ts)
+	began := false
+	for _, v := range values {
+		table, msg, err := s.decodeChange(ctx, v)
+		if err != nil {
+			return err
+		}
+		if msg == nil {
+			continue
+		}
+		if !began {
+			s.xid++
+			if err := s.res.SendXLogData(ctx, lsn, lsn, &pgrepl.Begin{
+				FinalLSN:   lsn,
+				CommitTime: ts.GoTime(),
+				XID:        s.xid,
+			}); err != nil {
+				return err
+			}
+			began = true
+		}
+		if s.relations[table.GetID()] != table.GetVersion() {
+			if err := s.res.SendXLogData(ctx, lsn, lsn, s.makeRelation(table)); err != nil {
+				return err
+			}
+			s.relations[table.GetID()] = table.GetVersion()
+		}
+		if err := s.res.SendXLogData(ctx, lsn, lsn, msg); err != nil {
+			return err
+		}
+	}
+	if !began {
+		return nil
+	}
+	return s.res.SendXLogData(ctx, lsn, lsn, &pgrepl.Commit{
+		CommitLSN:  lsn,
+		EndLSN:     lsn,
+		CommitTime: ts.GoTime(),
+	})
+}
+
+// decodeChange decodes a change received from a rangefeed into a pgoutput
+// message. A nil message is returned if the change isn't published.
+func (s *replicationStream) decodeChange(
+	ctx context.Context, v *roachpb.RangeFeedValue,
+) (catalog.TableDescriptor, pgrepl.PGOutputMessage, error) {
+	key, err := s.execCfg.Codec.StripTenantPrefix(v.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, tableID, _, err := rowenc.DecodePartialTableIDIndexID(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	t := s.tables[descpb.ID(tableID)]
+	if t == nil {
+		return nil, nil, nil
+	}
+	deleted := !v.Value.IsPresent()
+	updated := !deleted && v.PrevValue.IsPresent()
+	switch {
+	case deleted:
+		// Deleting a row which doesn't exist doesn't change anything.
+		if !t.delete || !v.PrevValue.IsPresent() {
+			return nil, nil, nil
+		}
+	case updated:
+		if !t.update {
+			return nil, nil, nil
+		}
+	default:
+		if !t.insert {
+			return nil, nil, nil
+		}
+	}
+
+	table, err := s.tableDescAt(ctx, descpb.ID(tableID), v.Value.Timestamp)
+	if err != nil {
+		return nil, nil, err
+	}
+	rf, err := s.rowFetcher(ctx, table)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.kvs.KVs = append(s.kvs.KVs[:0], roachpb.KeyValue{Key: v.Key, Value: v.Value})
+	if err := rf.StartScanFrom(ctx, &s.kvs); err != nil {
+		return nil, nil, err
+	}
+	datums, _, _, err := rf.NextRowDecoded(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if datums == nil {
+		return nil, nil, errors.AssertionFailedf("unexpected empty datums")
+	}
+	var row tree.Datums
+	for _, col := range table.PublicColumns() {
+		if !col.IsComputed() {
+			row = append(row, datums[col.Ordinal()])
+		}
+	}
+	relID := oid.Oid(table.GetID())
+	switch {
+	case deleted:
+		return table, &pgrepl.Delete{RelationID: relID, OldKey: row}, nil
+	case updated:
+		return table, &pgrepl.Update{RelationID: relID, New: row}, nil
+	default:
+		return table, &pgrepl.Insert{RelationID: relID, New: row}, nil
+	}
+}
+
+// makeRelation returns the Relation message describing the table. Computed
+// columns aren't published, like generated columns in PostgreSQL.
+func (s *replicationStream) makeRelation(table catalog.TableDescriptor) *pgrepl.Relation {
+	keyCols := table.GetPrimaryIndex().CollectKeyColumnIDs()
+	rel := &pgrepl.Relation{
+		ID:        oid.Oid(table.GetID()),
+		Namespace: s.tables[table.GetID()].schemaName,
+		Name:      table.GetName(),
+	}
+	for _, col := range table.PublicColumns() {
+		if col.IsComputed() {
+			continue
+		}
+		rel.Columns = append(rel.Columns, pgrepl.RelationColumn{
+			Key:     keyCols.Contains(col.GetID()),
+			Name:    col.GetName(),
+			TypeOID: col.GetType().Oid(),
+			TypeMod: col.GetType().TypeModifier(),
+		})
+	}
+	return rel
+}
+
+// tableDescAt returns the version of the table at the given timestamp.
+func (s *replicationStream) tableDescAt(
+	ctx context.Context, id descpb.ID, ts hlc.Timestamp,
+) (catalog.TableDescriptor, error) {
+	desc, err := s.execCfg.LeaseManager.Acquire(ctx, ts, id)
+	if err != nil {
+		return nil, err
+	}
+	table := desc.Underlying().(catalog.TableDescriptor)
+	// The lease is only needed at the exact timestamp requested.
+	desc.Release(ctx)
+	if !table.ContainsUserDefinedTypes() {
+		return table, nil
+	}
+	// The types of the columns are only hydrated by the descs.Collection,
+	// which needs a transaction to read at the timestamp.
+	collection := s.execCfg.CollectionFactory.NewCollection(nil /* TemporarySchemaProvider */)
+	defer collection.ReleaseAll(ctx)
+	if err := s.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		if err := txn.SetFixedTimestamp(ctx, ts); err != nil {
+			return err
+		}
+		table, err = collection.GetImmutableTableByID(ctx, txn, id, tree.ObjectLookupFlags{})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// rowFetcher returns a row.Fetcher decoding the rows of the primary index of
+// the given version of the table.
+func (s *replicationStream) rowFetcher(
+	ctx context.Context, table catalog.TableDescriptor,
+) (*row.Fetcher, error) {
+	idVer := idVersion{id: table.GetID(), version: table.GetVersion()}
+	if rf, ok := s.fetchers[idVer]; ok &&
+		catalog.UserDefinedTypeColsHaveSameVersion(table, rf.GetTable().(catalog.TableDescriptor)) {
+		return rf, nil
+	}
+	var colIdxMap catalog.TableColMap
+	var valNeededForCol util.FastIntSet
+	for _, col := range table.PublicColumns() {
+		colIdxMap.Set(col.GetID(), col.Ordinal())
+		valNeededForCol.Add(col.Ordinal())
+	}
+	var rf row.Fetcher
+	if err := rf.Init(
+		ctx,
+		s.execCfg.Codec,
+		s.execCfg.Settings,
+		false, /* reverse */
+		descpb.ScanLockingStrength_FOR_NONE,
+		descpb.ScanLockingWaitPolicy_BLOCK,
+		0,     /* lockTimeout */
+		false, /* isCheck */
+		&s.alloc,
+		nil, /* memMonitor */
+		row.FetcherTableArgs{
+			Desc:            table,
+			Index:           table.GetPrimaryIndex(),
+			ColIdxMap:       colIdxMap,
+			Cols:            table.PublicColumns(),
+			ValNeededForCol: valNeededForCol,
+		},
+	); err != nil {
+		return nil, err
+	}
+	s.fetchers[idVer] = &rf
+	return &rf, nil
+}
+
+// sendKeepalive sends a keepalive message with the position up to which all
+// the changes have been sent.
+func (s *replicationStream) sendKeepalive(ctx context.Context, replyRequested bool) error {
+	return s.res.SendKeepalive(ctx, pgrepl.LSNFromTimestamp(s.resolved), replyRequested)
+}
+
+// checkpoint renews the lease of the session on the slot, and persists the
+// position confirmed by the client. The protected timestamp of the slot
+// follows that position, so that the changes confirmed by the client can be
+// garbage collected.
+func (s *replicationStream) checkpoint(ctx context.Context) error {
+	slotName := s.slot.details.SlotName
+	now := s.execCfg.Clock.Now()
+	return s.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return s.execCfg.JobRegistry.UpdateJobWithTxn(ctx, s.slot.jobID, txn, false, /* useReadLock */
+			func(txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+				switch md.Status {
+				case jobs.StatusPending, jobs.StatusRunning, jobs.StatusPaused, jobs.StatusPauseRequested:
+				default:
+					return pgerror.Newf(pgcode.UndefinedObject, "replication slot %q was dropped", slotName)
+				}
+				progress := md.Progress.GetReplicationSlot()
+				if progress.ActiveSession != s.sessionID {
+					return pgerror.Newf(pgcode.ObjectInUse,
+						"replication slot %q is active for another session", slotName)
+				}
+				progress.ActiveExpiration = now.Add(replicationSlotLeaseDuration.Nanoseconds(), 0)
+				if progress.ConfirmedFlush.Less(s.confirmedFlush) {
+					progress.ConfirmedFlush = s.confirmedFlush
+					if err := s.execCfg.ProtectedTimestampProvider.UpdateTimestamp(
+						ctx, txn, s.slot.details.ProtectedTimestampRecord, s.confirmedFlush,
+					); err != nil {
+						return err
+					}
+				}
+				ju.UpdateProgress(md.Progress)
+				return nil
+			},
+		)
+	})
+}
+
+// release persists the position confirmed by the client and releases the
+// slot, so that another session can stream its changes right away.
+func (s *replicationStream) release(ctx context.Context) {
+	err := s.execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+		return s.execCfg.JobRegistry.UpdateJobWithTxn(ctx, s.slot.jobID, txn, false, /* useReadLock */
+			func(txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+				progress := md.Progress.GetReplicationSlot()
+				if progress.ActiveSession != s.sessionID {
+					return nil
+				}
+				progress.ActiveSession = ""
+				progress.ActiveExpiration = hlc.Timestamp{}
+				if progress.ConfirmedFlush.Less(s.confirmedFlush) {
+					progress.ConfirmedFlush = s.confirmedFlush
+					if err := s.execCfg.ProtectedTimestampProvider.UpdateTimestamp(
+						ctx, txn, s.slot.details.ProtectedTimestampRecord, s.confirmedFlush,
+					); err != nil {
+						return err
+					}
+				}
+				ju.UpdateProgress(md.Progress)
+				return nil
+			},
+		)
+	})
+	if err != nil {
+		log.Warningf(ctx, "failed to release replication slot %q: %v", s.slot.details.SlotName, err)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplicationStreamBuffer checks that the changes buffered by a
+// replication stream are ordered by timestamp, and that their memory is
+// accounted for.
+func TestReplicationStreamBuffer(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	st := cluster.MakeTestingClusterSettings()
+	const limit = 10 << 10
+	monitor := mon.NewMonitorWithLimit(
+		"test-monitor",
+		mon.MemoryResource,
+		limit,
+		nil,           /* curCount */
+		nil,           /* maxHist */
+		1,             /* increment */
+		math.MaxInt64, /* noteworthy */
+		st,
+	)
+	monitor.Start(ctx, nil, mon.MakeStandaloneBudget(math.MaxInt64))
+	defer monitor.Stop(ctx)
+
+	s := &replicationStream{
+		cmd:       StartReplication{Cmd: &pgrepl.StartReplication{SlotName: "s"}},
+		start:     hlc.Timestamp{WallTime: 1},
+		frontiers: make([]hlc.Timestamp, 1),
+		bufferAcc: monitor.MakeBoundAccount(),
+	}
+	defer s.bufferAcc.Close(ctx)
+	addChange := func(key string, wallTime int64, value []byte) error {
+		v := &roachpb.RangeFeedValue{Key: roachpb.Key(key)}
+		v.Value.RawBytes = value
+		v.Value.Timestamp = hlc.Timestamp{WallTime: wallTime}
+		return s.handleEvent(ctx, replicationEvent{value: v})
+	}
+
+	// The changes at or before the start of the stream were already streamed.
+	require.NoError(t, addChange("a", 1, nil))
+	require.Zero(t, s.buffer.Len())
+	require.Zero(t, s.bufferAcc.Used())
+
+	for _, c := range []struct {
+		key      string
+		wallTime int64
+	}{
+		{"a", 4}, {"b", 2}, {"c", 3}, {"d", 2}, {"e", 5}, {"f", 3},
+	} {
+		require.NoError(t, addChange(c.key, c.wallTime, nil))
+	}
+	require.Equal(t, 6, s.buffer.Len())
+	require.Positive(t, s.bufferAcc.Used())
+
+	// The changes are ordered by timestamp, and then in the order in which they
+	// were received.
+	var keys []string
+	var size int64
+	for s.buffer.Len() > 0 {
+		c := heap.Pop(&s.buffer).(bufferedChange)
+		keys = append(keys, string(c.value.Key))
+		size += c.memUsage()
+	}
+	require.Equal(t, []string{"b", "d", "c", "f", "a", "e"}, keys)
+	require.Equal(t, size, s.bufferAcc.Used())
+	s.bufferAcc.Shrink(ctx, size)
+
+	// The buffer can't grow past the memory limit.
+	value := make([]byte, 1<<10)
+	var err error
+	for i := 0; i < limit/len(value) && err == nil; i++ {
+		err = addChange("a", 2, value)
+	}
+	require.Error(t, err)
+	require.Equal(t, pgcode.OutOfMemory, pgerror.GetPGCode(err))
+	require.LessOrEqual(t, s.bufferAcc.Used(), int64(limit))
+}
@@ -1039,6 +1039,8 @@ type internalLookupCtx struct {
 	tbIDs       []descpb.ID
 	typDescs    map[descpb.ID]catalog.TypeDescriptor
 	typIDs      []descpb.ID
+	fnDescs     map[descpb.ID]catalog.FunctionDescriptor
+	fnIDs       []descpb.ID
 
 	// fallback is utilized in GetDesc and GetNamespaceEntry.
 	fallback catalog.DescGetter
@@ -1112,7 +1114,8 @@ func newInternalLookupCtx(
 	}
 	tbDescs := make(map[descpb.ID]catalog.TableDescriptor)
 	typDescs := make(map[descpb.ID]catalog.TypeDescriptor)
-	var tbIDs, typIDs, dbIDs, schemaIDs []descpb.ID
+	fnDescs := make(map[descpb.ID]catalog.FunctionDescriptor)
+	var tbIDs, typIDs, dbIDs, schemaIDs, fnIDs []descpb.ID
 	// Record descriptors for name lookups.
 	for i := range descs {
 		switch desc := descs[i].(type) {
@@ -1135,6 +1138,12 @@ func newInternalLookupCtx(
 				// Only make the type visible for iteration if the prefix was included.
 				typIDs = append(typIDs, desc.GetID())
 			}
+		case catalog.FunctionDescriptor:
+			fnDescs[desc.GetID()] = desc
+			if prefix == nil || prefix.GetID() == desc.GetParentID() {
+				// Only make the function visible for iteration if the prefix was included.
+				fnIDs = append(fnIDs, desc.GetID())
+			}
 		case catalog.SchemaDescriptor:
 			schemaDescs[desc.GetID()] = desc
 			if prefix == nil || prefix.GetID() == desc.GetParentID() {
@@ -1156,6 +1165,8 @@ func newInternalLookupCtx(
 		tbIDs:       tbIDs,
 		dbIDs:       dbIDs,
 		typIDs:      typIDs,
+		fnDescs:     fnDescs,
+		fnIDs:       fnIDs,
 		fallback:    fallback,
 	}
 }
@@ -239,6 +239,7 @@ func DecodeRowInfo(
 	if err := rf.Init(
 		ctx,
 		codec,
+		nil,   /* st */
 		false, /* reverse */
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
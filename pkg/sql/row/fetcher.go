@@ -20,6 +20,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/keys"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
@@ -198,6 +199,9 @@ type Fetcher struct {
 	// codec is used to encode and decode sql keys.
 	codec keys.SQLCodec
 
+	// st is used to check whether the cluster version allows shared locks.
+	st *cluster.Settings
+
 	table tableInfo
 
 	// reverse denotes whether or not the spans should be read in reverse
@@ -289,6 +293,7 @@ func (rf *Fetcher) Close(ctx context.Context) {
 func (rf *Fetcher) Init(
 	ctx context.Context,
 	codec keys.SQLCodec,
+	st *cluster.Settings,
 	reverse bool,
 	lockStrength descpb.ScanLockingStrength,
 	lockWaitPolicy descpb.ScanLockingWaitPolicy,
@@ -299,6 +304,7 @@ func (rf *Fetcher) Init(
 	tableArgs FetcherTableArgs,
 ) error {
 	rf.codec = codec
+	rf.st = st
 	rf.reverse = reverse
 	rf.lockStrength = lockStrength
 	rf.lockWaitPolicy = lockWaitPolicy
@@ -493,6 +499,7 @@ func (rf *Fetcher) StartScan(
 		rf.reverse,
 		batchBytesLimit,
 		rf.rowLimitToKeyLimit(rowLimitHint),
+		rf.st,
 		rf.lockStrength,
 		rf.lockWaitPolicy,
 		rf.lockTimeout,
@@ -594,6 +601,7 @@ func (rf *Fetcher) StartInconsistentScan(
 		rf.reverse,
 		batchBytesLimit,
 		rf.rowLimitToKeyLimit(rowLimitHint),
+		rf.st,
 		rf.lockStrength,
 		rf.lockWaitPolicy,
 		rf.lockTimeout,
@@ -67,6 +67,7 @@ func initFetcher(
 	if err := fetcher.Init(
 		context.Background(),
 		fetcherCodec,
+		nil, /* st */
 		reverseScan,
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
@@ -764,6 +765,7 @@ func TestRowFetcherReset(t *testing.T) {
 	if err := resetFetcher.Init(
 		ctx,
 		keys.SystemSQLCodec,
+		nil,   /* st */
 		false, /*reverse*/
 		descpb.ScanLockingStrength_FOR_NONE,
 		descpb.ScanLockingWaitPolicy_BLOCK,
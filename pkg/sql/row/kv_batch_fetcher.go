@@ -15,9 +15,11 @@ import (
 	"context"
 	"time"
 
+	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catalogkeys"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
@@ -89,6 +91,8 @@ type txnKVFetcher struct {
 	batchBytesLimit rowinfra.BytesLimit
 
 	reverse bool
+	// st is used to check whether the cluster version allows shared locks.
+	st *cluster.Settings
 	// lockStrength represents the locking mode to use when fetching KVs.
 	lockStrength descpb.ScanLockingStrength
 	// lockWaitPolicy represents the policy to be used for handling conflicting
@@ -176,7 +180,7 @@ func (f *txnKVFetcher) getBatchKeyLimitForIdx(batchIdx int) rowinfra.KeyLimit {
 
 // getKeyLockingStrength returns the configured per-key locking strength to use
 // for key-value scans.
-func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
+func (f *txnKVFetcher) getKeyLockingStrength(ctx context.Context) lock.Strength {
 	switch f.lockStrength {
 	case descpb.ScanLockingStrength_FOR_NONE:
 		return lock.None
@@ -185,9 +189,14 @@ func (f *txnKVFetcher) getKeyLockingStrength() lock.Strength {
 		// Promote to FOR_SHARE.
 		fallthrough
 	case descpb.ScanLockingStrength_FOR_SHARE:
-		// We currently perform no per-key locking when FOR_SHARE is used
-		// because Shared locks have not yet been implemented.
-		return lock.None
+		// We perform shared per-key locking when FOR_SHARE is used so that
+		// concurrent readers do not block each other. Nodes running older
+		// versions do not know how to handle shared locks, so until the upgrade
+		// is finalized we perform no per-key locking, as those versions do.
+		if !f.st.Version.IsActive(ctx, clusterversion.SharedLocks) {
+			return lock.None
+		}
+		return lock.Shared
 
 	case descpb.ScanLockingStrength_FOR_NO_KEY_UPDATE:
 		// Promote to FOR_UPDATE.
@@ -210,8 +219,7 @@ func (f *txnKVFetcher) getWaitPolicy() lock.WaitPolicy {
 		return lock.WaitPolicy_Block
 
 	case descpb.ScanLockingWaitPolicy_SKIP:
-		// Should not get here. Query should be rejected during planning.
-		panic(errors.AssertionFailedf("unsupported wait policy %s", f.lockWaitPolicy))
+		return lock.WaitPolicy_SkipLocked
 
 	case descpb.ScanLockingWaitPolicy_ERROR:
 		return lock.WaitPolicy_Error
@@ -256,6 +264,7 @@ func makeKVBatchFetcher(
 	reverse bool,
 	batchBytesLimit rowinfra.BytesLimit,
 	firstBatchKeyLimit rowinfra.KeyLimit,
+	st *cluster.Settings,
 	lockStrength descpb.ScanLockingStrength,
 	lockWaitPolicy descpb.ScanLockingWaitPolicy,
 	lockTimeout time.Duration,
@@ -317,6 +326,7 @@ func makeKVBatchFetcher(
 		reverse:                    reverse,
 		batchBytesLimit:            batchBytesLimit,
 		firstBatchKeyLimit:         firstBatchKeyLimit,
+		st:                         st,
 		lockStrength:               lockStrength,
 		lockWaitPolicy:             lockWaitPolicy,
 		lockTimeout:                lockTimeout,
@@ -365,7 +375,7 @@ func (f *txnKVFetcher) fetch(ctx context.Context) error {
 	ba.Header.MaxSpanRequestKeys = int64(f.getBatchKeyLimit())
 	ba.AdmissionHeader = f.requestAdmissionHeader
 	ba.Requests = make([]roachpb.RequestUnion, len(f.spans))
-	keyLocking := f.getKeyLockingStrength()
+	keyLocking := f.getKeyLockingStrength(ctx)
 
 	// Detect the number of gets vs scans, so we can batch allocate all of the
 	// requests precisely.
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowinfra"
 	"github.com/cockroachdb/cockroach/pkg/storage"
@@ -58,6 +59,7 @@ func NewKVFetcher(
 	reverse bool,
 	batchBytesLimit rowinfra.BytesLimit,
 	firstBatchLimit rowinfra.KeyLimit,
+	st *cluster.Settings,
 	lockStrength descpb.ScanLockingStrength,
 	lockWaitPolicy descpb.ScanLockingWaitPolicy,
 	lockTimeout time.Duration,
@@ -97,6 +99,7 @@ func NewKVFetcher(
 		reverse,
 		batchBytesLimit,
 		firstBatchLimit,
+		st,
 		lockStrength,
 		lockWaitPolicy,
 		lockTimeout,
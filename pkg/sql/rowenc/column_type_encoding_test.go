@@ -78,7 +78,8 @@ func hasKeyEncoding(typ *types.T) bool {
 	// Only some types are round-trip key encodable.
 	switch typ.Family() {
 	case types.JsonFamily, types.CollatedStringFamily, types.TupleFamily, types.DecimalFamily,
-		types.GeographyFamily, types.GeometryFamily:
+		types.GeographyFamily, types.GeometryFamily, types.TSQueryFamily, types.TSVectorFamily,
+		types.JsonpathFamily:
 		return false
 	case types.ArrayFamily:
 		return hasKeyEncoding(typ.ArrayContents())
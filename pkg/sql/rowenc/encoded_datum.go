@@ -321,7 +321,7 @@ func (ed *EncDatum) Fingerprint(
 	var err error
 	memUsageBefore := ed.Size()
 	switch typ.Family() {
-	case types.JsonFamily:
+	case types.JsonFamily, types.TSQueryFamily, types.TSVectorFamily, types.JsonpathFamily:
 		if err = ed.EnsureDecoded(typ, a); err != nil {
 			return nil, err
 		}
@@ -32,6 +32,8 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/json"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/protoutil"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/unique"
 	"github.com/cockroachdb/errors"
 )
@@ -596,10 +598,12 @@ func EncodeInvertedIndexPrefixKeys(
 }
 
 // EncodeInvertedIndexTableKeys produces one inverted index key per element in
-// the input datum, which should be a container (either JSON or Array). For
-// JSON, "element" means unique path through the document. Each output key is
-// prefixed by inKey, and is guaranteed to be lexicographically sortable, but
-// not guaranteed to be round-trippable during decoding. If the input Datum
+// the input datum, which should be a container (either JSON or Array), a
+// tsvector or a string. For JSON, "element" means unique path through the
+// document, and for strings, which are indexed by the gin_trgm_ops operator
+// class, it means trigram. Each output key is prefixed by inKey, and is
+// guaranteed to be lexicographically sortable, but not guaranteed to be
+// round-trippable during decoding. If the input Datum
 // is (SQL) NULL, no inverted index keys will be produced, because inverted
 // indexes cannot and do not need to satisfy the predicate col IS NULL.
 //
@@ -623,6 +627,11 @@ func EncodeInvertedIndexTableKeys(
 		return json.EncodeInvertedIndexKeys(inKey, val.(*tree.DJSON).JSON)
 	case types.ArrayFamily:
 		return encodeArrayInvertedIndexTableKeys(val.(*tree.DArray), inKey, version, false /* excludeNulls */)
+	case types.TSVectorFamily:
+		return tsearch.EncodeInvertedIndexKeys(inKey, val.(*tree.DTSVector).TSVector), nil
+	case types.StringFamily:
+		// Strings can only be indexed by their trigrams.
+		return trigram.EncodeInvertedIndexKeys(inKey, string(tree.MustBeDString(datum))), nil
 	}
 	return nil, errors.AssertionFailedf("trying to apply inverted index to unsupported type %s", datum.ResolvedType())
 }
@@ -688,6 +697,34 @@ func EncodeContainedInvertedIndexSpans(
 	}
 }
 
+// EncodeMatchingInvertedIndexSpans returns the spans that must be scanned in
+// the inverted index to evaluate a text search match (@@) predicate with the
+// given datum, which should be a tsquery. In other words, if we have a
+// predicate x @@ y, this function should use the value of y to find the spans
+// to scan in an inverted index on x.
+//
+// The spans are returned in an inverted.SpanExpression, which represents the
+// set operations that must be applied on the spans read during execution. If
+// the query cannot constrain the index (for example, because it only matches
+// documents that do not contain some lexemes), an
+// inverted.NonInvertedColExpression is returned.
+func EncodeMatchingInvertedIndexSpans(
+	evalCtx *tree.EvalContext, val tree.Datum,
+) (invertedExpr inverted.Expression, err error) {
+	if val == tree.DNull {
+		return nil, nil
+	}
+	datum := tree.UnwrapDatum(evalCtx, val)
+	switch val.ResolvedType().Family() {
+	case types.TSQueryFamily:
+		return tsearch.EncodeInvertedIndexSpans(nil /* inKey */, datum.(*tree.DTSQuery).TSQuery), nil
+	default:
+		return nil, errors.AssertionFailedf(
+			"trying to apply inverted index to unsupported type %s", datum.ResolvedType(),
+		)
+	}
+}
+
 // encodeArrayInvertedIndexTableKeys returns a list of inverted index keys for
 // the given input array, one per entry in the array. The input inKey is
 // prefixed to all returned keys.
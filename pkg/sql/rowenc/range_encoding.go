@@ -0,0 +1,209 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package rowenc
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/errors"
+)
+
+// The key encoding of a range is the following byte string, which is then
+// encoded with the bytes key encoding in the direction of the index column:
+//
+//   empty range:     0x00
+//   non-empty range: 0x01 <lower bound> <upper bound>
+//
+// where a lower bound is either 0x00 (-infinity) or 0x01 followed by the
+// ascending key encoding of its value and 0x00 (inclusive) or 0x01
+// (exclusive), and an upper bound is either 0x01 followed by the ascending key
+// encoding of its value and 0x00 (exclusive) or 0x01 (inclusive), or 0x02
+// (+infinity). This sorts ranges in the same order as DRange.Compare.
+const (
+	rangeKeyEmpty    byte = 0x00
+	rangeKeyNonEmpty byte = 0x01
+
+	rangeKeyLowerInfinite byte = 0x00
+	rangeKeyUpperInfinite byte = 0x02
+	rangeKeyFinite        byte = 0x01
+
+	rangeKeyLowerInclusive byte = 0x00
+	rangeKeyLowerExclusive byte = 0x01
+	rangeKeyUpperExclusive byte = 0x00
+	rangeKeyUpperInclusive byte = 0x01
+)
+
+// The value encoding of a range is a flags byte followed by the value
+// encodings of its finite bounds, lower bound first.
+const (
+	rangeValueEmpty          byte = 1 << 0
+	rangeValueLowerInclusive byte = 1 << 1
+	rangeValueUpperInclusive byte = 1 << 2
+	rangeValueLowerInfinite  byte = 1 << 3
+	rangeValueUpperInfinite  byte = 1 << 4
+)
+
+// encodeRangeKey encodes a range as a table key.
+func encodeRangeKey(b []byte, r *tree.DRange, dir encoding.Direction) ([]byte, error) {
+	data, err := encodeRangeKeyContents(nil, r)
+	if err != nil {
+		return nil, err
+	}
+	if dir == encoding.Ascending {
+		return encoding.EncodeBytesAscending(b, data), nil
+	}
+	return encoding.EncodeBytesDescending(b, data), nil
+}
+
+func encodeRangeKeyContents(b []byte, r *tree.DRange) ([]byte, error) {
+	if r.Empty {
+		return append(b, rangeKeyEmpty), nil
+	}
+	b = append(b, rangeKeyNonEmpty)
+	if r.Lower.IsInfinite() {
+		b = append(b, rangeKeyLowerInfinite)
+	} else {
+		b = append(b, rangeKeyFinite)
+		var err error
+		if b, err = EncodeTableKey(b, r.Lower.Val, encoding.Ascending); err != nil {
+			return nil, err
+		}
+		if r.Lower.Inclusive {
+			b = append(b, rangeKeyLowerInclusive)
+		} else {
+			b = append(b, rangeKeyLowerExclusive)
+		}
+	}
+	if r.Upper.IsInfinite() {
+		return append(b, rangeKeyUpperInfinite), nil
+	}
+	b = append(b, rangeKeyFinite)
+	var err error
+	if b, err = EncodeTableKey(b, r.Upper.Val, encoding.Ascending); err != nil {
+		return nil, err
+	}
+	if r.Upper.Inclusive {
+		return append(b, rangeKeyUpperInclusive), nil
+	}
+	return append(b, rangeKeyUpperExclusive), nil
+}
+
+// decodeRangeKey decodes a range encoded by encodeRangeKey.
+func decodeRangeKey(
+	a *DatumAlloc, typ *types.T, key []byte, dir encoding.Direction,
+) (tree.Datum, []byte, error) {
+	var rkey, data []byte
+	var err error
+	if dir == encoding.Ascending {
+		rkey, data, err = encoding.DecodeBytesAscending(key, nil)
+	} else {
+		rkey, data, err = encoding.DecodeBytesDescending(key, nil)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil, errors.AssertionFailedf("empty range key")
+	}
+	if data[0] == rangeKeyEmpty {
+		return tree.NewDEmptyRange(typ), rkey, nil
+	}
+	data = data[1:]
+	r := &tree.DRange{Typ: typ}
+	for i, b := range []*tree.RangeBound{&r.Lower, &r.Upper} {
+		if len(data) == 0 {
+			return nil, nil, errors.AssertionFailedf("invalid range key")
+		}
+		marker := data[0]
+		data = data[1:]
+		if marker != rangeKeyFinite {
+			continue
+		}
+		if b.Val, data, err = DecodeTableKey(a, typ.RangeContents(), data, encoding.Ascending); err != nil {
+			return nil, nil, err
+		}
+		if len(data) == 0 {
+			return nil, nil, errors.AssertionFailedf("invalid range key")
+		}
+		if i == 0 {
+			b.Inclusive = data[0] == rangeKeyLowerInclusive
+		} else {
+			b.Inclusive = data[0] == rangeKeyUpperInclusive
+		}
+		data = data[1:]
+	}
+	return r, rkey, nil
+}
+
+// encodeRangeValue returns the value encoding of a range, without a value tag.
+func encodeRangeValue(b []byte, r *tree.DRange) ([]byte, error) {
+	var flags byte
+	switch {
+	case r.Empty:
+		flags |= rangeValueEmpty
+	default:
+		if r.Lower.IsInfinite() {
+			flags |= rangeValueLowerInfinite
+		} else if r.Lower.Inclusive {
+			flags |= rangeValueLowerInclusive
+		}
+		if r.Upper.IsInfinite() {
+			flags |= rangeValueUpperInfinite
+		} else if r.Upper.Inclusive {
+			flags |= rangeValueUpperInclusive
+		}
+	}
+	b = append(b, flags)
+	if r.Empty {
+		return b, nil
+	}
+	for _, bound := range []tree.RangeBound{r.Lower, r.Upper} {
+		if bound.IsInfinite() {
+			continue
+		}
+		var err error
+		if b, err = EncodeTableValue(b, descpb.ColumnID(encoding.NoColumnID), bound.Val, nil /* scratch */); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// decodeRangeValue decodes a range encoded by encodeRangeValue.
+func decodeRangeValue(a *DatumAlloc, typ *types.T, data []byte) (tree.Datum, error) {
+	if len(data) == 0 {
+		return nil, errors.AssertionFailedf("empty range value")
+	}
+	flags := data[0]
+	data = data[1:]
+	if flags&rangeValueEmpty != 0 {
+		return tree.NewDEmptyRange(typ), nil
+	}
+	r := &tree.DRange{
+		Typ:   typ,
+		Lower: tree.RangeBound{Inclusive: flags&rangeValueLowerInclusive != 0},
+		Upper: tree.RangeBound{Inclusive: flags&rangeValueUpperInclusive != 0},
+	}
+	var err error
+	if flags&rangeValueLowerInfinite == 0 {
+		if r.Lower.Val, data, err = DecodeTableValue(a, typ.RangeContents(), data); err != nil {
+			return nil, err
+		}
+	}
+	if flags&rangeValueUpperInfinite == 0 {
+		if r.Upper.Val, _, err = DecodeTableValue(a, typ.RangeContents(), data); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
@@ -96,6 +96,7 @@ func initRowFetcher(
 	if err := fetcher.Init(
 		flowCtx.EvalCtx.Context,
 		flowCtx.Codec(),
+		flowCtx.EvalCtx.Settings,
 		reverseScan,
 		lockStrength,
 		lockWaitPolicy,
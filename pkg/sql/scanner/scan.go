@@ -286,6 +286,12 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.CONTAINED_BY)
 			return
+		case '-': // <->
+			if s.peekN(1) == '>' {
+				s.pos += 2
+				lval.SetID(lexbase.DISTANCE)
+				return
+			}
 		}
 		return
 
@@ -366,6 +372,14 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.CONTAINS)
 			return
+		case '@': // @@
+			s.pos++
+			lval.SetID(lexbase.AT_AT)
+			return
+		case '?': // @?
+			s.pos++
+			lval.SetID(lexbase.JSON_PATH_EXISTS)
+			return
 		}
 		return
 
@@ -375,6 +389,14 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.AND_AND)
 			return
+		case '<': // &<
+			s.pos++
+			lval.SetID(lexbase.OVERLEFT)
+			return
+		case '>': // &>
+			s.pos++
+			lval.SetID(lexbase.OVERRIGHT)
+			return
 		}
 		return
 
@@ -390,6 +412,12 @@ func (s *Scanner) Scan(lval ScanSymType) {
 			s.pos++
 			lval.SetID(lexbase.FETCHVAL)
 			return
+		case '|': // -|-
+			if s.peekN(1) == '-' {
+				s.pos += 2
+				lval.SetID(lexbase.ADJACENT)
+				return
+			}
 		}
 		return
 
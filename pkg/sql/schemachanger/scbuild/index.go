@@ -172,6 +172,10 @@ func (b *buildContext) createIndex(ctx context.Context, n *tree.CreateIndex) {
 		panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a table or materialized view", &n.Table))
 	}
 
+	if table.IsForeignTable() {
+		panic(&notImplementedError{n: n, detail: "foreign tables"})
+	}
+
 	if table.MaterializedView() {
 		if n.Sharded != nil {
 			panic(pgerror.New(pgcode.InvalidObjectDefinition,
@@ -206,6 +210,12 @@ func (b *buildContext) createIndex(ctx context.Context, n *tree.CreateIndex) {
 				n:      n,
 			})
 		}
+		if columnNode.OpClass != "" {
+			panic(&notImplementedError{
+				detail: "operator classes are not supported.",
+				n:      n,
+			})
+		}
 		if columnNode.Expr != nil {
 			if !b.ClusterSettings().Version.IsActive(ctx, clusterversion.ExpressionIndexes) {
 				panic(pgerror.Newf(pgcode.FeatureNotSupported,
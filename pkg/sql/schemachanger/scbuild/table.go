@@ -53,6 +53,9 @@ func (b *buildContext) alterTable(ctx context.Context, n *tree.AlterTable) {
 	if HasConcurrentSchemaChanges(table) {
 		panic(&ConcurrentSchemaChangeError{descID: table.GetID()})
 	}
+	if table.IsForeignTable() {
+		panic(&notImplementedError{n: n, detail: "foreign tables"})
+	}
 	for _, cmd := range n.Cmds {
 		b.alterTableCmd(ctx, table, cmd, &tn)
 	}
@@ -726,6 +729,9 @@ func (b *buildContext) maybeCleanTableFKs(
 func (b *buildContext) dropTableDesc(
 	ctx context.Context, table catalog.TableDescriptor, behavior tree.DropBehavior,
 ) {
+	if table.HasRowLevelTTL() {
+		panic(&notImplementedError{n: nil, detail: "dropping tables with row level TTL"})
+	}
 	lastSourceID := b.setSourceElementID(b.newSourceElementID())
 	// Drop dependent views
 	onErrPanic(table.ForeachDependedOnBy(func(dep *descpb.TableDescriptor_Reference) error {
@@ -763,6 +769,9 @@ func (b *buildContext) dropTable(ctx context.Context, n *tree.DropTable) {
 		if !table.IsTable() {
 			panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a table", table.GetName()))
 		}
+		if table.IsForeignTable() || n.IsForeign {
+			panic(&notImplementedError{n: n, detail: "foreign tables"})
+		}
 		onErrPanic(b.AuthorizationAccessor().CheckPrivilege(ctx, table, privilege.DROP))
 		b.dropTableDesc(ctx, table, n.DropBehavior)
 		b.incrementSubWorkID()
@@ -116,6 +116,15 @@ func (b *buildContext) dropType(ctx context.Context, n *tree.DropType) {
 			}
 			panic(sqlerrors.NewUndefinedTypeError(name))
 		}
+		isDomain := typ.GetKind() == descpb.TypeDescriptor_DOMAIN
+		if n.IsDomain && !isDomain {
+			panic(pgerror.Newf(pgcode.WrongObjectType, "%q is not a domain", name))
+		}
+		if !n.IsDomain && isDomain {
+			panic(errors.WithHint(
+				pgerror.Newf(pgcode.WrongObjectType, "%q is not a type", name),
+				"Use DROP DOMAIN to remove a domain."))
+		}
 		onErrPanic(b.AuthorizationAccessor().CheckPrivilege(ctx, typ, privilege.DROP))
 		b.dropTypeDesc(ctx, typ, n.DropBehavior, false /* ignoreAliases */)
 		b.incrementSubWorkID()
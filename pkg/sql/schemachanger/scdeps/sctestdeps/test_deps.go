@@ -553,9 +553,7 @@ func (s *TestState) GetFullyQualifiedName(ctx context.Context, id descpb.ID) (st
 	// 2) Schemas will only have a database name.
 	// 3) Databases should not have either set.
 	switch obj.DescriptorType() {
-	case catalog.Table:
-		fallthrough
-	case catalog.Type:
+	case catalog.Table, catalog.Type, catalog.Function:
 		if scName == "" || dbName == "" {
 			return "", errors.AssertionFailedf("schema or database missing for type/relation %d", id)
 		}
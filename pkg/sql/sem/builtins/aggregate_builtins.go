@@ -15,6 +15,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"unsafe"
 
 	"github.com/cockroachdb/apd/v2"
@@ -406,6 +407,20 @@ var aggregates = map[string]builtinDefinition{
 			"Aggregates values as a JSON or JSONB object.", tree.VolatilityStable),
 	),
 
+	"range_agg": collectOverloads(aggProps(), types.Ranges,
+		func(t *types.T) tree.Overload {
+			return makeAggOverload([]*types.T{t}, types.MakeArray(t), newRangeAggregate,
+				"Calculates the union of the selected ranges, as a sorted array of "+
+					"non-overlapping, non-adjacent ranges. Unlike in PostgreSQL, which "+
+					"returns a multirange, the result is an array since multirange types "+
+					"are not supported.", tree.VolatilityImmutable)
+		}),
+	"range_intersect_agg": collectOverloads(aggProps(), types.Ranges,
+		func(t *types.T) tree.Overload {
+			return makeAggOverload([]*types.T{t}, t, newRangeIntersectAggregate,
+				"Calculates the intersection of the selected ranges.", tree.VolatilityImmutable)
+		}),
+
 	"st_makeline": makeBuiltin(
 		tree.FunctionProperties{
 			Class:                   tree.AggregateClass,
@@ -1030,6 +1045,149 @@ func (agg *stExtentAgg) Size() int64 {
 	return sizeOfSTExtentAggregate
 }
 
+type rangeAggregate struct {
+	ranges  []*tree.DRange
+	typ     *types.T
+	evalCtx *tree.EvalContext
+	acc     mon.BoundAccount
+}
+
+func newRangeAggregate(
+	params []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &rangeAggregate{
+		typ:     params[0],
+		evalCtx: evalCtx,
+		acc:     evalCtx.Mon.MakeBoundAccount(),
+	}
+}
+
+// Add accumulates the passed range.
+func (a *rangeAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	if err := a.acc.Grow(ctx, int64(datum.Size())); err != nil {
+		return err
+	}
+	a.ranges = append(a.ranges, tree.MustBeDRange(datum))
+	return nil
+}
+
+// Result returns the union of all the ranges passed to Add, as a sorted array
+// in which overlapping and adjacent ranges have been merged.
+func (a *rangeAggregate) Result() (tree.Datum, error) {
+	if a.ranges == nil {
+		return tree.DNull, nil
+	}
+	sorted := make([]*tree.DRange, 0, len(a.ranges))
+	for _, r := range a.ranges {
+		if !r.Empty {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Compare(a.evalCtx, sorted[j]) < 0
+	})
+	res := tree.NewDArray(a.typ)
+	var cur *tree.DRange
+	for _, r := range sorted {
+		if cur != nil && (cur.Overlaps(a.evalCtx, r) || cur.Adjacent(a.evalCtx, r)) {
+			merged, err := cur.Merge(a.evalCtx, r)
+			if err != nil {
+				return nil, err
+			}
+			cur = merged
+			continue
+		}
+		if cur != nil {
+			if err := res.Append(cur); err != nil {
+				return nil, err
+			}
+		}
+		cur = r
+	}
+	if cur != nil {
+		if err := res.Append(cur); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *rangeAggregate) Reset(ctx context.Context) {
+	a.ranges = nil
+	a.acc.Empty(ctx)
+}
+
+// Close allows the aggregate to release the memory it requested during
+// operation.
+func (a *rangeAggregate) Close(ctx context.Context) {
+	a.acc.Close(ctx)
+}
+
+// Size implements the AggregateFunc interface.
+func (a *rangeAggregate) Size() int64 {
+	return sizeOfRangeAggregate
+}
+
+type rangeIntersectAggregate struct {
+	singleDatumAggregateBase
+
+	intersection *tree.DRange
+	evalCtx      *tree.EvalContext
+}
+
+func newRangeIntersectAggregate(
+	_ []*types.T, evalCtx *tree.EvalContext, _ tree.Datums,
+) tree.AggregateFunc {
+	return &rangeIntersectAggregate{
+		singleDatumAggregateBase: makeSingleDatumAggregateBase(evalCtx),
+		evalCtx:                  evalCtx,
+	}
+}
+
+// Add intersects the current result with the passed range.
+func (a *rangeIntersectAggregate) Add(ctx context.Context, datum tree.Datum, _ ...tree.Datum) error {
+	if datum == tree.DNull {
+		return nil
+	}
+	r := tree.MustBeDRange(datum)
+	if a.intersection != nil {
+		var err error
+		if r, err = a.intersection.Intersect(a.evalCtx, r); err != nil {
+			return err
+		}
+	}
+	a.intersection = r
+	return a.updateMemoryUsage(ctx, int64(r.Size()))
+}
+
+// Result returns the intersection of all the ranges passed to Add.
+func (a *rangeIntersectAggregate) Result() (tree.Datum, error) {
+	if a.intersection == nil {
+		return tree.DNull, nil
+	}
+	return a.intersection, nil
+}
+
+// Reset implements tree.AggregateFunc interface.
+func (a *rangeIntersectAggregate) Reset(ctx context.Context) {
+	a.intersection = nil
+	a.reset(ctx)
+}
+
+// Close implements tree.AggregateFunc interface.
+func (a *rangeIntersectAggregate) Close(ctx context.Context) {
+	a.close(ctx)
+}
+
+// Size implements the AggregateFunc interface.
+func (a *rangeIntersectAggregate) Size() int64 {
+	return sizeOfRangeIntersectAggregate
+}
+
 func makeVarianceBuiltin() builtinDefinition {
 	return makeBuiltin(aggProps(),
 		makeAggOverload([]*types.T{types.Int}, types.Decimal, newIntVarianceAggregate,
@@ -1094,6 +1252,8 @@ var _ tree.AggregateFunc = &regressionSYYAggregate{}
 var _ tree.AggregateFunc = &regressionCountAggregate{}
 var _ tree.AggregateFunc = &regressionAvgXAggregate{}
 var _ tree.AggregateFunc = &regressionAvgYAggregate{}
+var _ tree.AggregateFunc = &rangeAggregate{}
+var _ tree.AggregateFunc = &rangeIntersectAggregate{}
 
 const sizeOfArrayAggregate = int64(unsafe.Sizeof(arrayAggregate{}))
 const sizeOfAvgAggregate = int64(unsafe.Sizeof(avgAggregate{}))
@@ -1137,6 +1297,8 @@ const sizeOfSTMakeLineAggregate = int64(unsafe.Sizeof(stMakeLineAgg{}))
 const sizeOfSTUnionAggregate = int64(unsafe.Sizeof(stUnionAgg{}))
 const sizeOfSTCollectAggregate = int64(unsafe.Sizeof(stCollectAgg{}))
 const sizeOfSTExtentAggregate = int64(unsafe.Sizeof(stExtentAgg{}))
+const sizeOfRangeAggregate = int64(unsafe.Sizeof(rangeAggregate{}))
+const sizeOfRangeIntersectAggregate = int64(unsafe.Sizeof(rangeIntersectAggregate{}))
 
 // singleDatumAggregateBase is a utility struct that helps aggregate builtins
 // that store a single datum internally track their memory usage related to
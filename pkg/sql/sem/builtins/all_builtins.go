@@ -39,6 +39,10 @@ func init() {
 	initPGBuiltins()
 	initMathBuiltins()
 	initReplicationBuiltins()
+	initTSearchBuiltins()
+	initTrigramBuiltins()
+	initJsonpathBuiltins()
+	initRangeBuiltins()
 
 	AllBuiltinNames = make([]string, 0, len(builtins))
 	AllAggregateBuiltinNames = make([]string, 0, len(aggregates))
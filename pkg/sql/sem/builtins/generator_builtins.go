@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/kv/kvclient"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/protoreflect"
@@ -454,6 +455,39 @@ The output can be used to recreate a database.'
 			tree.VolatilityVolatile,
 		),
 	),
+
+	"pg_create_logical_replication_slot": makeBuiltin(
+		tree.FunctionProperties{
+			Class:            tree.GeneratorClass,
+			Category:         categorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{"slot_name", types.Name},
+				{"plugin", types.Name},
+			},
+			createLogicalReplicationSlotGeneratorType,
+			makeCreateLogicalReplicationSlotGenerator,
+			"Creates a logical replication slot which decodes the changes of the "+
+				"current database with the given output plugin. Only the pgoutput "+
+				"plugin is supported.",
+			tree.VolatilityVolatile,
+		),
+		makeGeneratorOverload(
+			tree.ArgTypes{
+				{"slot_name", types.Name},
+				{"plugin", types.Name},
+				{"temporary", types.Bool},
+			},
+			createLogicalReplicationSlotGeneratorType,
+			makeCreateLogicalReplicationSlotGenerator,
+			"Creates a logical replication slot which decodes the changes of the "+
+				"current database with the given output plugin. Only the pgoutput "+
+				"plugin is supported, and slots can't be temporary.",
+			tree.VolatilityVolatile,
+		),
+	),
 }
 
 var decodePlanGistGeneratorType = types.String
@@ -500,6 +534,75 @@ func makeDecodePlanGistGenerator(
 	return &gistPlanGenerator{gist: gist, p: ctx.Planner}, nil
 }
 
+var createLogicalReplicationSlotGeneratorType = types.MakeLabeledTuple(
+	[]*types.T{types.Name, types.String},
+	[]string{"slot_name", "lsn"},
+)
+
+// createLogicalReplicationSlotGenerator creates a logical replication slot
+// when it is started, and returns a single row with the name of the slot and
+// the LSN after which its changes are decoded.
+type createLogicalReplicationSlotGenerator struct {
+	p         tree.EvalPlanner
+	slotName  string
+	plugin    string
+	temporary bool
+
+	lsn  pgrepl.LSN
+	done bool
+}
+
+var _ tree.ValueGenerator = &createLogicalReplicationSlotGenerator{}
+
+func makeCreateLogicalReplicationSlotGenerator(
+	ctx *tree.EvalContext, args tree.Datums,
+) (tree.ValueGenerator, error) {
+	g := &createLogicalReplicationSlotGenerator{
+		p:        ctx.Planner,
+		slotName: string(tree.MustBeDString(args[0])),
+		plugin:   string(tree.MustBeDString(args[1])),
+	}
+	if len(args) > 2 {
+		g.temporary = bool(tree.MustBeDBool(args[2]))
+	}
+	return g, nil
+}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (g *createLogicalReplicationSlotGenerator) ResolvedType() *types.T {
+	return createLogicalReplicationSlotGeneratorType
+}
+
+// Start implements the tree.ValueGenerator interface.
+func (g *createLogicalReplicationSlotGenerator) Start(ctx context.Context, _ *kv.Txn) error {
+	ts, err := g.p.CreateReplicationSlot(ctx, g.slotName, g.plugin, g.temporary)
+	if err != nil {
+		return err
+	}
+	g.lsn = pgrepl.LSNFromTimestamp(ts)
+	return nil
+}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *createLogicalReplicationSlotGenerator) Next(context.Context) (bool, error) {
+	if g.done {
+		return false, nil
+	}
+	g.done = true
+	return true, nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *createLogicalReplicationSlotGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{
+		tree.NewDName(g.slotName),
+		tree.NewDString(g.lsn.String()),
+	}, nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (g *createLogicalReplicationSlotGenerator) Close(context.Context) {}
+
 func makeGeneratorOverload(
 	in tree.TypeList, ret *types.T, g tree.GeneratorFactory, info string, volatility tree.Volatility,
 ) tree.Overload {
@@ -0,0 +1,278 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"context"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
+)
+
+func initJsonpathBuiltins() {
+	// Add all jsonpathBuiltins to the Builtins map after a sanity check.
+	for k, v := range jsonpathBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+}
+
+// jsonpathFn evaluates path against target, for a jsonb_path function.
+type jsonpathFn func(path jsonpath.Path, target json.JSON, opts jsonpath.EvalOptions) (tree.Datum, error)
+
+// jsonpathArgs returns the arguments of the jsonb_path functions with the
+// given number of optional arguments, among vars and silent.
+func jsonpathArgs(numOptional int) tree.ArgTypes {
+	args := tree.ArgTypes{
+		{"target", types.Jsonb},
+		{"path", types.Jsonpath},
+		{"vars", types.Jsonb},
+		{"silent", types.Bool},
+	}
+	return args[:2+numOptional]
+}
+
+// jsonpathEvalOptions returns the options of the evaluation of a path from the
+// optional arguments of a jsonb_path function. The _tz variants of the
+// functions interpret datetime items without a time zone in the session time
+// zone.
+func jsonpathEvalOptions(evalCtx *tree.EvalContext, optional tree.Datums, tz bool) jsonpath.EvalOptions {
+	var opts jsonpath.EvalOptions
+	if len(optional) > 0 {
+		opts.Vars = tree.MustBeDJSON(optional[0]).JSON
+	}
+	if len(optional) > 1 {
+		opts.Silent = bool(tree.MustBeDBool(optional[1]))
+	}
+	if tz {
+		opts.UseTZ = true
+		opts.Location = evalCtx.GetLocation()
+	}
+	return opts
+}
+
+// jsonpathInfo returns the documentation of a jsonb_path function.
+func jsonpathInfo(info string, tz bool) string {
+	info += " `vars` holds the values of the variables of the path. If `silent` is true, " +
+		"the errors about missing keys, unexpected item types, and numeric and datetime " +
+		"errors are suppressed."
+	if tz {
+		info += " Datetime values without a time zone are compared to values with a time " +
+			"zone in the session time zone."
+	}
+	return info
+}
+
+// jsonpathOverloads returns the overloads of a jsonb_path function, with and
+// without the optional vars and silent arguments.
+func jsonpathOverloads(ret *types.T, fn jsonpathFn, info string, tz bool) []tree.Overload {
+	volatility := tree.VolatilityImmutable
+	if tz {
+		volatility = tree.VolatilityStable
+	}
+	var overloads []tree.Overload
+	for numOptional := 0; numOptional <= 2; numOptional++ {
+		overloads = append(overloads, tree.Overload{
+			Types:      jsonpathArgs(numOptional),
+			ReturnType: tree.FixedReturnType(ret),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return fn(
+					tree.MustBeDJsonpath(args[1]).Path,
+					tree.MustBeDJSON(args[0]).JSON,
+					jsonpathEvalOptions(evalCtx, args[2:], tz),
+				)
+			},
+			Info:       jsonpathInfo(info, tz),
+			Volatility: volatility,
+		})
+	}
+	return overloads
+}
+
+// jsonpathQueryOverloads returns the overloads of jsonb_path_query, which
+// returns the items that the path selects as a set.
+func jsonpathQueryOverloads(tz bool) []tree.Overload {
+	volatility := tree.VolatilityImmutable
+	if tz {
+		volatility = tree.VolatilityStable
+	}
+	var overloads []tree.Overload
+	for numOptional := 0; numOptional <= 2; numOptional++ {
+		overloads = append(overloads, makeGeneratorOverload(
+			jsonpathArgs(numOptional),
+			types.Jsonb,
+			func(evalCtx *tree.EvalContext, args tree.Datums) (tree.ValueGenerator, error) {
+				items, err := tree.MustBeDJsonpath(args[1]).Path.Eval(
+					tree.MustBeDJSON(args[0]).JSON, jsonpathEvalOptions(evalCtx, args[2:], tz),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return &jsonpathQueryGenerator{items: items}, nil
+			},
+			jsonpathInfo("Returns the items of `target` that `path` selects.", tz),
+			volatility,
+		))
+	}
+	return overloads
+}
+
+// jsonpathQueryGenerator supports the execution of jsonb_path_query.
+type jsonpathQueryGenerator struct {
+	items []json.JSON
+	next  int
+}
+
+var _ tree.ValueGenerator = &jsonpathQueryGenerator{}
+
+// ResolvedType implements the tree.ValueGenerator interface.
+func (*jsonpathQueryGenerator) ResolvedType() *types.T { return types.Jsonb }
+
+// Start implements the tree.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Start(_ context.Context, _ *kv.Txn) error {
+	g.next = -1
+	return nil
+}
+
+// Close implements the tree.ValueGenerator interface.
+func (*jsonpathQueryGenerator) Close(_ context.Context) {}
+
+// Next implements the tree.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Next(_ context.Context) (bool, error) {
+	g.next++
+	return g.next < len(g.items), nil
+}
+
+// Values implements the tree.ValueGenerator interface.
+func (g *jsonpathQueryGenerator) Values() (tree.Datums, error) {
+	return tree.Datums{tree.NewDJSON(g.items[g.next])}, nil
+}
+
+func jsonpathExists(path jsonpath.Path, target json.JSON, opts jsonpath.EvalOptions) (tree.Datum, error) {
+	exists, ok, err := path.Exists(target, opts)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(exists)), nil
+}
+
+func jsonpathMatch(path jsonpath.Path, target json.JSON, opts jsonpath.EvalOptions) (tree.Datum, error) {
+	match, ok, err := path.Match(target, opts)
+	if err != nil || !ok {
+		return tree.DNull, err
+	}
+	return tree.MakeDBool(tree.DBool(match)), nil
+}
+
+func jsonpathQueryArray(
+	path jsonpath.Path, target json.JSON, opts jsonpath.EvalOptions,
+) (tree.Datum, error) {
+	items, err := path.Eval(target, opts)
+	if err != nil {
+		return nil, err
+	}
+	b := json.NewArrayBuilder(len(items))
+	for _, item := range items {
+		b.Add(item)
+	}
+	return tree.NewDJSON(b.Build()), nil
+}
+
+func jsonpathQueryFirst(
+	path jsonpath.Path, target json.JSON, opts jsonpath.EvalOptions,
+) (tree.Datum, error) {
+	items, err := path.Eval(target, opts)
+	if err != nil || len(items) == 0 {
+		return tree.DNull, err
+	}
+	return tree.NewDJSON(items[0]), nil
+}
+
+// jsonpathOperatorOverload returns the overload of the function that
+// implements the @? or @@ operator, which suppresses errors.
+func jsonpathOperatorOverload(fn jsonpathFn, info string) tree.Overload {
+	return tree.Overload{
+		Types:      jsonpathArgs(0),
+		ReturnType: tree.FixedReturnType(types.Bool),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			return fn(
+				tree.MustBeDJsonpath(args[1]).Path,
+				tree.MustBeDJSON(args[0]).JSON,
+				jsonpath.EvalOptions{Silent: true},
+			)
+		},
+		Info:       info,
+		Volatility: tree.VolatilityImmutable,
+	}
+}
+
+const (
+	jsonpathExistsInfo = "Returns whether `path` selects any item of `target`."
+	jsonpathMatchInfo  = "Returns the result of the predicate `path` on `target`, or NULL if " +
+		"it is unknown."
+	jsonpathQueryArrayInfo = "Returns the items of `target` that `path` selects as a JSON array."
+	jsonpathQueryFirstInfo = "Returns the first item of `target` that `path` selects, or NULL " +
+		"if there is none."
+)
+
+// jsonpathBuiltins contains the SQL/JSON path built-in functions indexed by
+// name.
+//
+// For use in other packages, see AllBuiltinNames and GetBuiltinProperties().
+var jsonpathBuiltins = map[string]builtinDefinition{
+	"jsonb_path_exists": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Bool, jsonpathExists, jsonpathExistsInfo, false /* tz */)...,
+	),
+	"jsonb_path_exists_tz": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Bool, jsonpathExists, jsonpathExistsInfo, true /* tz */)...,
+	),
+	"jsonb_path_exists_opr": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOperatorOverload(jsonpathExists, jsonpathExistsInfo+" This implements the @? operator."),
+	),
+	"jsonb_path_match": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Bool, jsonpathMatch, jsonpathMatchInfo, false /* tz */)...,
+	),
+	"jsonb_path_match_tz": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Bool, jsonpathMatch, jsonpathMatchInfo, true /* tz */)...,
+	),
+	"jsonb_path_match_opr": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOperatorOverload(jsonpathMatch, jsonpathMatchInfo+" This implements the @@ operator."),
+	),
+	"jsonb_path_query":    makeBuiltin(genProps(), jsonpathQueryOverloads(false /* tz */)...),
+	"jsonb_path_query_tz": makeBuiltin(genProps(), jsonpathQueryOverloads(true /* tz */)...),
+	"jsonb_path_query_array": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Jsonb, jsonpathQueryArray, jsonpathQueryArrayInfo, false /* tz */)...,
+	),
+	"jsonb_path_query_array_tz": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Jsonb, jsonpathQueryArray, jsonpathQueryArrayInfo, true /* tz */)...,
+	),
+	"jsonb_path_query_first": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Jsonb, jsonpathQueryFirst, jsonpathQueryFirstInfo, false /* tz */)...,
+	),
+	"jsonb_path_query_first_tz": makeBuiltin(
+		tree.FunctionProperties{Category: categoryJSON},
+		jsonpathOverloads(types.Jsonb, jsonpathQueryFirst, jsonpathQueryFirstInfo, true /* tz */)...,
+	),
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catconstants"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
 	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
 	"github.com/cockroachdb/cockroach/pkg/sql/privilege"
@@ -29,6 +30,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqlerrors"
 	"github.com/cockroachdb/cockroach/pkg/sql/types"
 	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/ipaddr"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -269,6 +271,100 @@ func makePGGetIndexDef(argTypes tree.ArgTypes) tree.Overload {
 	}
 }
 
+var errAdvisoryLocksUnsupported = pgerror.New(pgcode.FeatureNotSupported,
+	"advisory locks are not supported in this context")
+
+// makeAdvisoryLockBuiltin creates a builtin which acquires an advisory lock.
+// The builtins which don't wait for the lock return whether it was acquired.
+// The others return void in Postgres, and true here.
+func makeAdvisoryLockBuiltin(exclusive, xact, wait bool) builtinDefinition {
+	mode, scope := "a shared", "session"
+	if exclusive {
+		mode = "an exclusive"
+	}
+	if xact {
+		scope = "transaction"
+	}
+	info := fmt.Sprintf("Obtains %s %s-level advisory lock, waiting if necessary.", mode, scope)
+	if !wait {
+		info = fmt.Sprintf("Obtains %s %s-level advisory lock if available. "+
+			"Returns whether the lock was obtained.", mode, scope)
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
+		makeAdvisoryLockOverloads(info, func(
+			ctx *tree.EvalContext, key tree.AdvisoryLockKey,
+		) (bool, error) {
+			return ctx.AdvisoryLocker.AcquireAdvisoryLock(ctx.Ctx(), key, exclusive, xact, wait)
+		})...,
+	)
+}
+
+// makeAdvisoryUnlockBuiltin creates a builtin which releases a session-level
+// advisory lock and returns whether the lock was held.
+func makeAdvisoryUnlockBuiltin(exclusive bool) builtinDefinition {
+	mode := "shared"
+	if exclusive {
+		mode = "exclusive"
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
+		makeAdvisoryLockOverloads(
+			fmt.Sprintf("Releases a previously-acquired %s session-level advisory lock. "+
+				"Returns whether the lock was held.", mode),
+			func(ctx *tree.EvalContext, key tree.AdvisoryLockKey) (bool, error) {
+				return ctx.AdvisoryLocker.ReleaseAdvisoryLock(ctx.Ctx(), key, exclusive)
+			},
+		)...,
+	)
+}
+
+// makeAdvisoryLockOverloads creates the overloads of an advisory lock
+// builtin, which identify the lock with a single INT8 or two INT4 keys.
+func makeAdvisoryLockOverloads(
+	info string, fn func(*tree.EvalContext, tree.AdvisoryLockKey) (bool, error),
+) []tree.Overload {
+	eval := func(ctx *tree.EvalContext, key tree.AdvisoryLockKey) (tree.Datum, error) {
+		if ctx.AdvisoryLocker == nil {
+			return nil, errAdvisoryLocksUnsupported
+		}
+		res, err := fn(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		return tree.MakeDBool(tree.DBool(res)), nil
+	}
+	return []tree.Overload{
+		{
+			Types:      tree.ArgTypes{{"key", types.Int}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				key := uint64(tree.MustBeDInt(args[0]))
+				return eval(ctx, tree.AdvisoryLockKey{
+					ClassID:  uint32(key >> 32),
+					ObjID:    uint32(key),
+					ObjSubID: 1,
+				})
+			},
+			Info:       info,
+			Volatility: tree.VolatilityVolatile,
+		},
+		{
+			Types:      tree.ArgTypes{{"key1", types.Int4}, {"key2", types.Int4}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return eval(ctx, tree.AdvisoryLockKey{
+					ClassID:  uint32(tree.MustBeDInt(args[0])),
+					ObjID:    uint32(tree.MustBeDInt(args[1])),
+					ObjSubID: 2,
+				})
+			},
+			Info:       info,
+			Volatility: tree.VolatilityVolatile,
+		},
+	}
+}
+
 // Make a pg_get_viewdef function with the given arguments.
 func makePGGetViewDef(argTypes tree.ArgTypes) tree.Overload {
 	return tree.Overload{
@@ -1209,26 +1305,34 @@ SELECT description
 		},
 	),
 
-	"pg_try_advisory_lock": makeBuiltin(defProps(),
+	// Advisory lock functions.
+	// https://www.postgresql.org/docs/current/functions-admin.html#FUNCTIONS-ADVISORY-LOCKS
+	"pg_advisory_lock":                 makeAdvisoryLockBuiltin(true /* exclusive */, false /* xact */, true /* wait */),
+	"pg_advisory_lock_shared":          makeAdvisoryLockBuiltin(false /* exclusive */, false /* xact */, true /* wait */),
+	"pg_advisory_xact_lock":            makeAdvisoryLockBuiltin(true /* exclusive */, true /* xact */, true /* wait */),
+	"pg_advisory_xact_lock_shared":     makeAdvisoryLockBuiltin(false /* exclusive */, true /* xact */, true /* wait */),
+	"pg_try_advisory_lock":             makeAdvisoryLockBuiltin(true /* exclusive */, false /* xact */, false /* wait */),
+	"pg_try_advisory_lock_shared":      makeAdvisoryLockBuiltin(false /* exclusive */, false /* xact */, false /* wait */),
+	"pg_try_advisory_xact_lock":        makeAdvisoryLockBuiltin(true /* exclusive */, true /* xact */, false /* wait */),
+	"pg_try_advisory_xact_lock_shared": makeAdvisoryLockBuiltin(false /* exclusive */, true /* xact */, false /* wait */),
+	"pg_advisory_unlock":               makeAdvisoryUnlockBuiltin(true /* exclusive */),
+	"pg_advisory_unlock_shared":        makeAdvisoryUnlockBuiltin(false /* exclusive */),
+
+	"pg_advisory_unlock_all": makeBuiltin(
+		tree.FunctionProperties{DistsqlBlocklist: true},
 		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
-			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
-				return tree.DBoolTrue, nil
-			},
-			Info:       notUsableInfo,
-			Volatility: tree.VolatilityVolatile,
-		},
-	),
-
-	"pg_advisory_unlock": makeBuiltin(defProps(),
-		tree.Overload{
-			Types:      tree.ArgTypes{{"int", types.Int}},
+			Types:      tree.ArgTypes{},
 			ReturnType: tree.FixedReturnType(types.Bool),
-			Fn: func(_ *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				if ctx.AdvisoryLocker == nil {
+					return nil, errAdvisoryLocksUnsupported
+				}
+				if err := ctx.AdvisoryLocker.ReleaseAllAdvisoryLocks(ctx.Ctx()); err != nil {
+					return nil, err
+				}
 				return tree.DBoolTrue, nil
 			},
-			Info:       notUsableInfo,
+			Info:       "Releases all session-level advisory locks held by the current session.",
 			Volatility: tree.VolatilityVolatile,
 		},
 	),
@@ -1435,6 +1539,43 @@ SELECT description
 		},
 	),
 
+	"pg_drop_replication_slot": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categorySystemInfo,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"slot_name", types.Name}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				slotName := string(tree.MustBeDString(args[0]))
+				if err := ctx.Planner.DropReplicationSlot(ctx.Ctx(), slotName); err != nil {
+					return nil, err
+				}
+				return tree.DBoolTrue, nil
+			},
+			Info: "Drops the logical replication slot with the given name. The slot " +
+				"must not be active. Returns true once the slot is dropped.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
+	// pg_current_wal_lsn returns the current position in the stream of
+	// changes of replication slots. Since LSNs are derived from commit
+	// timestamps, this is the LSN of the timestamp of the statement.
+	"pg_current_wal_lsn": makeBuiltin(defProps(),
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				ts := hlc.Timestamp{WallTime: ctx.GetStmtTimestamp().UnixNano()}
+				return tree.NewDString(pgrepl.LSNFromTimestamp(ts).String()), nil
+			},
+			Info:       "Returns the current write-ahead log position, as used by replication slots.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+
 	// pg_is_in_recovery returns true if the Postgres database is currently in
 	// recovery.  This is not applicable so this can always return false.
 	// https://www.postgresql.org/docs/current/static/functions-admin.html#FUNCTIONS-RECOVERY-INFO-TABLE
@@ -0,0 +1,220 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+func initRangeBuiltins() {
+	// Add all rangeBuiltins to the Builtins map. lower and upper are also
+	// string functions, so their range overloads are added to the existing
+	// definitions.
+	for k, v := range rangeBuiltins {
+		if b, exists := builtins[k]; exists {
+			if k != "lower" && k != "upper" {
+				panic("duplicate builtin: " + k)
+			}
+			b.overloads = append(b.overloads, v.overloads...)
+			builtins[k] = b
+			continue
+		}
+		builtins[k] = v
+	}
+	for _, t := range types.Ranges {
+		name := t.Name()
+		if _, exists := builtins[name]; exists {
+			panic("duplicate builtin: " + name)
+		}
+		builtins[name] = makeRangeConstructorBuiltin(t)
+	}
+}
+
+var errInvalidRangeBoundFlags = errors.WithHint(
+	pgerror.New(pgcode.Syntax, "invalid range bound flags"),
+	`Valid values are "[]", "[)", "(]", and "()".`,
+)
+
+// makeRangeConstructorBuiltin returns the builtin that constructs ranges of
+// the given type from their bounds. A NULL bound is infinite.
+func makeRangeConstructorBuiltin(t *types.T) builtinDefinition {
+	elemType := t.RangeContents()
+	construct := func(
+		evalCtx *tree.EvalContext, lower, upper tree.Datum, lowerInc, upperInc bool,
+	) (tree.Datum, error) {
+		var lowerBound, upperBound tree.RangeBound
+		if lower != tree.DNull {
+			lowerBound = tree.RangeBound{Val: lower, Inclusive: lowerInc}
+		}
+		if upper != tree.DNull {
+			upperBound = tree.RangeBound{Val: upper, Inclusive: upperInc}
+		}
+		return tree.NewDRange(evalCtx, t, lowerBound, upperBound)
+	}
+	return makeBuiltin(
+		tree.FunctionProperties{Category: categoryRange, NullableArgs: true},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", elemType}, {"upper", elemType}},
+			ReturnType: tree.FixedReturnType(t),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return construct(evalCtx, args[0], args[1], true /* lowerInc */, false /* upperInc */)
+			},
+			Info: "Constructs a range from `lower` (inclusive) to `upper` (exclusive). " +
+				"A NULL bound is infinite.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lower", elemType}, {"upper", elemType}, {"bounds", types.String}},
+			ReturnType: tree.FixedReturnType(t),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				if args[2] == tree.DNull {
+					return nil, pgerror.New(pgcode.DataException,
+						"range constructor flags argument must not be null")
+				}
+				var lowerInc, upperInc bool
+				switch bounds := string(tree.MustBeDString(args[2])); bounds {
+				case "[]":
+					lowerInc, upperInc = true, true
+				case "[)":
+					lowerInc = true
+				case "(]":
+					upperInc = true
+				case "()":
+				default:
+					return nil, errInvalidRangeBoundFlags
+				}
+				return construct(evalCtx, args[0], args[1], lowerInc, upperInc)
+			},
+			Info: "Constructs a range from `lower` to `upper`, whose inclusivity is given by " +
+				"`bounds`, one of '[]', '[)', '(]' or '()'. A NULL bound is infinite.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	)
+}
+
+// rangeBoundOverload returns the overload of lower or upper for the given range
+// type, which returns the value of the bound, or NULL if it is infinite or the
+// range is empty.
+func rangeBoundOverload(upper bool, info string) func(*types.T) tree.Overload {
+	return func(t *types.T) tree.Overload {
+		return tree.Overload{
+			Types:      tree.ArgTypes{{"range", t}},
+			ReturnType: tree.FixedReturnType(t.RangeContents()),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				r := tree.MustBeDRange(args[0])
+				b := r.Lower
+				if upper {
+					b = r.Upper
+				}
+				if r.Empty || b.IsInfinite() {
+					return tree.DNull, nil
+				}
+				return b.Val, nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		}
+	}
+}
+
+// rangePredicateOverload returns the overload of a predicate on a single range
+// for the given range type.
+func rangePredicateOverload(fn func(r *tree.DRange) bool, info string) func(*types.T) tree.Overload {
+	return func(t *types.T) tree.Overload {
+		return tree.Overload{
+			Types:      tree.ArgTypes{{"range", t}},
+			ReturnType: tree.FixedReturnType(types.Bool),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.MakeDBool(tree.DBool(fn(tree.MustBeDRange(args[0])))), nil
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+		}
+	}
+}
+
+var rangeBuiltins = map[string]builtinDefinition{
+	"lower": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangeBoundOverload(false /* upper */, "Returns the lower bound of `range`."),
+	),
+
+	"upper": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangeBoundOverload(true /* upper */, "Returns the upper bound of `range`."),
+	),
+
+	"isempty": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangePredicateOverload(func(r *tree.DRange) bool {
+			return r.Empty
+		}, "Returns whether `range` is empty."),
+	),
+
+	"lower_inc": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangePredicateOverload(func(r *tree.DRange) bool {
+			return !r.Empty && r.Lower.Inclusive
+		}, "Returns whether the lower bound of `range` is inclusive."),
+	),
+
+	"upper_inc": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangePredicateOverload(func(r *tree.DRange) bool {
+			return !r.Empty && r.Upper.Inclusive
+		}, "Returns whether the upper bound of `range` is inclusive."),
+	),
+
+	"lower_inf": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangePredicateOverload(func(r *tree.DRange) bool {
+			return !r.Empty && r.Lower.IsInfinite()
+		}, "Returns whether the lower bound of `range` is infinite."),
+	),
+
+	"upper_inf": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		rangePredicateOverload(func(r *tree.DRange) bool {
+			return !r.Empty && r.Upper.IsInfinite()
+		}, "Returns whether the upper bound of `range` is infinite."),
+	),
+
+	"range_merge": collectOverloads(
+		tree.FunctionProperties{Category: categoryRange},
+		types.Ranges,
+		func(t *types.T) tree.Overload {
+			return tree.Overload{
+				Types:      tree.ArgTypes{{"left", t}, {"right", t}},
+				ReturnType: tree.FixedReturnType(t),
+				Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+					r, err := tree.MustBeDRange(args[0]).Merge(evalCtx, tree.MustBeDRange(args[1]))
+					if err != nil {
+						return nil, err
+					}
+					return r, nil
+				},
+				Info:       "Returns the smallest range that includes both `left` and `right`.",
+				Volatility: tree.VolatilityImmutable,
+			}
+		},
+	),
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"strconv"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+)
+
+func initTrigramBuiltins() {
+	// Add all trigramBuiltins to the Builtins map after a sanity check.
+	for k, v := range trigramBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+}
+
+// similarityOverload returns the overload of a trigram similarity function.
+func similarityOverload(fn func(l, r string) float32, info string) tree.Overload {
+	return tree.Overload{
+		Types:      tree.ArgTypes{{"left", types.String}, {"right", types.String}},
+		ReturnType: tree.FixedReturnType(types.Float4),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			l, r := string(tree.MustBeDString(args[0])), string(tree.MustBeDString(args[1]))
+			return tree.NewDFloat(tree.DFloat(fn(l, r))), nil
+		},
+		Info:       info,
+		Volatility: tree.VolatilityImmutable,
+	}
+}
+
+var trigramBuiltins = map[string]builtinDefinition{
+	"similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		similarityOverload(trigram.Similarity,
+			"Returns a number between 0 and 1 that indicates how similar `left` and `right` are, "+
+				"based on the number of trigrams they share."),
+	),
+
+	"word_similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		similarityOverload(trigram.WordSimilarity,
+			"Returns the greatest similarity between the trigrams of `left` and any continuous "+
+				"extent of the ordered trigrams of `right`."),
+	),
+
+	"strict_word_similarity": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		similarityOverload(trigram.StrictWordSimilarity,
+			"Like word_similarity, but the extents of `right` are made of whole words."),
+	),
+
+	"show_trgm": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"input", types.String}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, t := range trigram.MakeTrigrams(string(tree.MustBeDString(args[0]))) {
+					if err := arr.Append(tree.NewDString(t)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns the trigrams of `input`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"show_limit": makeBuiltin(
+		tree.FunctionProperties{Category: categoryTrigram},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(ctx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				return tree.NewDFloat(tree.DFloat(ctx.SessionData().TrigramSimilarityThreshold)), nil
+			},
+			Info: "Returns the similarity above which the % operator considers two strings " +
+				"similar. It is the value of the pg_trgm.similarity_threshold session variable.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
+
+	"set_limit": makeBuiltin(
+		tree.FunctionProperties{
+			Category:         categoryTrigram,
+			DistsqlBlocklist: true,
+		},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"threshold", types.Float4}},
+			ReturnType: tree.FixedReturnType(types.Float4),
+			Fn: func(ctx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				threshold := float64(tree.MustBeDFloat(args[0]))
+				if err := setSessionVar(
+					ctx, "pg_trgm.similarity_threshold",
+					strconv.FormatFloat(threshold, 'g', -1, 32), false, /* isLocal */
+				); err != nil {
+					return nil, err
+				}
+				return args[0], nil
+			},
+			Info: "Sets the similarity above which the % operator considers two strings " +
+				"similar, and returns it. It sets the pg_trgm.similarity_threshold session variable.",
+			Volatility: tree.VolatilityVolatile,
+		},
+	),
+}
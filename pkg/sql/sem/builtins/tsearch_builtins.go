@@ -0,0 +1,457 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package builtins
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
+)
+
+func initTSearchBuiltins() {
+	// Add all tsearchBuiltins to the Builtins map after a sanity check.
+	for k, v := range tsearchBuiltins {
+		if _, exists := builtins[k]; exists {
+			panic("duplicate builtin: " + k)
+		}
+		builtins[k] = v
+	}
+}
+
+// currentTSConfig returns the text search configuration named by the
+// default_text_search_config session variable.
+func currentTSConfig(evalCtx *tree.EvalContext) (*tsearch.Config, error) {
+	name := evalCtx.SessionData().DefaultTextSearchConfig
+	if name == "" {
+		name = tsearch.DefaultConfigName
+	}
+	return tsearch.GetConfig(name)
+}
+
+// tsConfigOverloads returns a pair of overloads for a full text search
+// function whose first argument is an optional text search configuration. The
+// overload without a configuration uses the default_text_search_config session
+// variable, so it is only stable.
+func tsConfigOverloads(
+	args tree.ArgTypes,
+	ret *types.T,
+	fn func(evalCtx *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error),
+	info string,
+) []tree.Overload {
+	return []tree.Overload{
+		{
+			Types:      args,
+			ReturnType: tree.FixedReturnType(ret),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				config, err := currentTSConfig(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				return fn(evalCtx, config, args)
+			},
+			Info:       info + " The default_text_search_config session variable names the configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+		{
+			Types:      append(tree.ArgTypes{{"config", types.String}}, args...),
+			ReturnType: tree.FixedReturnType(ret),
+			Fn: func(evalCtx *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				config, err := tsearch.GetConfig(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return fn(evalCtx, config, args[1:])
+			},
+			Info:       info,
+			Volatility: tree.VolatilityImmutable,
+			// In Postgres the configuration is a regconfig, so the overloads
+			// that take it can't be told apart from the stable overloads that
+			// take text arguments (for example, ts_headline(document, query,
+			// options)).
+			IgnoreVolatilityCheck: true,
+		},
+	}
+}
+
+// tsRankOverload returns an overload of ts_rank. If withWeights is set, the
+// first argument is an array of the weights of the D, C, B and A positions.
+// If withNormalization is set, the last argument is the normalization flags.
+func tsRankOverload(withWeights, withNormalization bool) tree.Overload {
+	args := tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}}
+	if withWeights {
+		args = append(tree.ArgTypes{{"weights", types.MakeArray(types.Float4)}}, args...)
+	}
+	if withNormalization {
+		args = append(args, tree.ArgTypes{{"normalization", types.Int}}...)
+	}
+	return tree.Overload{
+		Types:      args,
+		ReturnType: tree.FixedReturnType(types.Float4),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			var weights []float64
+			if withWeights {
+				arr := tree.MustBeDArray(args[0])
+				weights = make([]float64, arr.Len())
+				for i, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed,
+							"array of weight must not contain nulls")
+					}
+					weights[i] = float64(tree.MustBeDFloat(d))
+				}
+				args = args[1:]
+			}
+			var normalization int
+			if withNormalization {
+				normalization = int(tree.MustBeDInt(args[2]))
+			}
+			rank, err := tsearch.Rank(
+				weights, tree.MustBeDTSVector(args[0]).TSVector, tree.MustBeDTSQuery(args[1]).TSQuery,
+				normalization,
+			)
+			if err != nil {
+				return nil, err
+			}
+			return tree.NewDFloat(tree.DFloat(rank)), nil
+		},
+		Info: "Ranks the document `vector` by how well it matches `query`, based on the " +
+			"frequency of its matching lexemes. `weights` overrides the weights of the D, C, B " +
+			"and A positions (defaults {0.1, 0.2, 0.4, 1.0}) and `normalization` is a bit mask " +
+			"that controls how the document length affects the rank.",
+		Volatility: tree.VolatilityImmutable,
+	}
+}
+
+// tsHeadlineOverloads returns the overloads of ts_headline, with and without
+// a configuration.
+func tsHeadlineOverloads(withOptions bool) []tree.Overload {
+	args := tree.ArgTypes{{"document", types.String}, {"query", types.TSQuery}}
+	if withOptions {
+		args = append(args, tree.ArgTypes{{"options", types.String}}...)
+	}
+	return tsConfigOverloads(
+		args,
+		types.String,
+		func(_ *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error) {
+			opts := tsearch.DefaultHeadlineOptions()
+			if withOptions {
+				var err error
+				if opts, err = tsearch.ParseHeadlineOptions(string(tree.MustBeDString(args[2]))); err != nil {
+					return nil, err
+				}
+			}
+			return tree.NewDString(config.Headline(
+				string(tree.MustBeDString(args[0])), tree.MustBeDTSQuery(args[1]).TSQuery, opts,
+			)), nil
+		},
+		"Returns an excerpt of `document` in which the words that match `query` are "+
+			"highlighted. `options` is a comma-separated list of option=value pairs, among "+
+			"StartSel, StopSel, MaxWords, MinWords, ShortWord and HighlightAll.",
+	)
+}
+
+// tsMatchOverload returns the overload of ts_match_vq or ts_match_qv, which
+// implement the @@ operator.
+func tsMatchOverload(queryFirst bool) tree.Overload {
+	args := tree.ArgTypes{{"vector", types.TSVector}, {"query", types.TSQuery}}
+	if queryFirst {
+		args = tree.ArgTypes{{"query", types.TSQuery}, {"vector", types.TSVector}}
+	}
+	return tree.Overload{
+		Types:      args,
+		ReturnType: tree.FixedReturnType(types.Bool),
+		Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+			v, q := args[0], args[1]
+			if queryFirst {
+				v, q = q, v
+			}
+			return tree.MakeDBool(tree.DBool(tsearch.EvalTSQuery(
+				tree.MustBeDTSQuery(q).TSQuery, tree.MustBeDTSVector(v).TSVector,
+			))), nil
+		},
+		Info:       "Returns whether `vector` matches `query`.",
+		Volatility: tree.VolatilityImmutable,
+	}
+}
+
+// tsearchBuiltins contains the full text search built-in functions indexed
+// by name.
+//
+// For use in other packages, see AllBuiltinNames and GetBuiltinProperties().
+var tsearchBuiltins = map[string]builtinDefinition{
+	"to_tsvector": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(
+			tree.ArgTypes{{"document", types.String}},
+			types.TSVector,
+			func(_ *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(config.ToTSVector(string(tree.MustBeDString(args[0])))), nil
+			},
+			"Converts `document` to a tsvector of normalized lexemes and their positions.",
+		)...,
+	),
+
+	"to_tsquery": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(
+			tree.ArgTypes{{"query", types.String}},
+			types.TSQuery,
+			func(_ *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error) {
+				q, err := config.ToTSQuery(string(tree.MustBeDString(args[0])))
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+			"Converts `query`, written in the tsquery syntax, to a tsquery, normalizing its words "+
+				"to lexemes.",
+		)...,
+	),
+
+	"plainto_tsquery": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(
+			tree.ArgTypes{{"query", types.String}},
+			types.TSQuery,
+			func(_ *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSQuery(config.PlainToTSQuery(string(tree.MustBeDString(args[0])))), nil
+			},
+			"Converts the plain text `query` to a tsquery that matches documents containing "+
+				"all of its words.",
+		)...,
+	),
+
+	"phraseto_tsquery": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsConfigOverloads(
+			tree.ArgTypes{{"query", types.String}},
+			types.TSQuery,
+			func(_ *tree.EvalContext, config *tsearch.Config, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSQuery(config.PhraseToTSQuery(string(tree.MustBeDString(args[0])))), nil
+			},
+			"Converts the plain text `query` to a tsquery that matches documents containing "+
+				"its words as a phrase.",
+		)...,
+	),
+
+	"get_current_ts_config": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{},
+			ReturnType: tree.FixedReturnType(types.String),
+			Fn: func(evalCtx *tree.EvalContext, _ tree.Datums) (tree.Datum, error) {
+				config, err := currentTSConfig(evalCtx)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDString(config.Name()), nil
+			},
+			Info:       "Returns the name of the default text search configuration.",
+			Volatility: tree.VolatilityStable,
+		},
+	),
+
+	"ts_rank": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsRankOverload(false /* withWeights */, false /* withNormalization */),
+		tsRankOverload(false /* withWeights */, true /* withNormalization */),
+		tsRankOverload(true /* withWeights */, false /* withNormalization */),
+		tsRankOverload(true /* withWeights */, true /* withNormalization */),
+	),
+
+	"ts_headline": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		append(tsHeadlineOverloads(false /* withOptions */), tsHeadlineOverloads(true /* withOptions */)...)...,
+	),
+
+	"ts_match_vq": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsMatchOverload(false /* queryFirst */),
+	),
+
+	"ts_match_qv": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tsMatchOverload(true /* queryFirst */),
+	),
+
+	"tsvector_concat": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector1", types.TSVector}, {"vector2", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				v := tree.MustBeDTSVector(args[0]).Concat(tree.MustBeDTSVector(args[1]).TSVector)
+				return tree.NewDTSVector(v), nil
+			},
+			Info: "Concatenates two tsvectors. The positions of `vector2` are shifted past the " +
+				"largest position of `vector1`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"setweight": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}, {"weight", types.QChar}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return setWeight(args[0], args[1], nil /* lexemes */)
+			},
+			Info:       "Sets the weight of every position of `vector` to `weight`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"vector", types.TSVector},
+				{"weight", types.QChar},
+				{"lexemes", types.StringArray},
+			},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[2])
+				lexemes := make([]string, 0, arr.Len())
+				for _, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed,
+							"lexeme array may not contain nulls")
+					}
+					lexemes = append(lexemes, string(tree.MustBeDString(d)))
+				}
+				return setWeight(args[0], args[1], lexemes)
+			},
+			Info:       "Sets the weight of the positions of the given `lexemes` in `vector` to `weight`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"strip": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDTSVector(tree.MustBeDTSVector(args[0]).Strip()), nil
+			},
+			Info:       "Removes the positions and weights from `vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"numnode": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.Int),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				return tree.NewDInt(tree.DInt(tree.MustBeDTSQuery(args[0]).NumNodes())), nil
+			},
+			Info:       "Returns the number of lexemes and operators in `query`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"tsquery_phrase": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"query1", types.TSQuery}, {"query2", types.TSQuery}},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				q, err := tree.MustBeDTSQuery(args[0]).Phrase(tree.MustBeDTSQuery(args[1]).TSQuery, 1)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+			Info:       "Returns a query that matches `query1` immediately followed by `query2`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+		tree.Overload{
+			Types: tree.ArgTypes{
+				{"query1", types.TSQuery},
+				{"query2", types.TSQuery},
+				{"distance", types.Int},
+			},
+			ReturnType: tree.FixedReturnType(types.TSQuery),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				q, err := tree.MustBeDTSQuery(args[0]).Phrase(
+					tree.MustBeDTSQuery(args[1]).TSQuery, int(tree.MustBeDInt(args[2])),
+				)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSQuery(q), nil
+			},
+			Info:       "Returns a query that matches `query1` followed by `query2` at `distance`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"tsvector_to_array": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"vector", types.TSVector}},
+			ReturnType: tree.FixedReturnType(types.StringArray),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.NewDArray(types.String)
+				for _, l := range tree.MustBeDTSVector(args[0]).Lexemes() {
+					if err := arr.Append(tree.NewDString(l)); err != nil {
+						return nil, err
+					}
+				}
+				return arr, nil
+			},
+			Info:       "Returns the lexemes of `vector`.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+
+	"array_to_tsvector": makeBuiltin(
+		tree.FunctionProperties{Category: categoryFullTextSearch},
+		tree.Overload{
+			Types:      tree.ArgTypes{{"lexemes", types.StringArray}},
+			ReturnType: tree.FixedReturnType(types.TSVector),
+			Fn: func(_ *tree.EvalContext, args tree.Datums) (tree.Datum, error) {
+				arr := tree.MustBeDArray(args[0])
+				lexemes := make([]string, 0, arr.Len())
+				for _, d := range arr.Array {
+					if d == tree.DNull {
+						return nil, pgerror.New(pgcode.NullValueNotAllowed,
+							"lexeme array may not contain nulls")
+					}
+					lexemes = append(lexemes, string(tree.MustBeDString(d)))
+				}
+				v, err := tsearch.MakeTSVector(lexemes)
+				if err != nil {
+					return nil, err
+				}
+				return tree.NewDTSVector(v), nil
+			},
+			Info:       "Converts an array of lexemes to a tsvector without positions.",
+			Volatility: tree.VolatilityImmutable,
+		},
+	),
+}
+
+// setWeight implements setweight for the given tsvector and "char" weight
+// datums.
+func setWeight(vector, weight tree.Datum, lexemes []string) (tree.Datum, error) {
+	w := string(tree.MustBeDString(weight))
+	if len(w) != 1 {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", w)
+	}
+	v, err := tree.MustBeDTSVector(vector).SetWeight(w[0], lexemes)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewDTSVector(v), nil
+}
@@ -77,6 +77,8 @@ func (*AlterTableSetVisible) alterTableCmd()         {}
 func (*AlterTableValidateConstraint) alterTableCmd() {}
 func (*AlterTablePartitionByTable) alterTableCmd()   {}
 func (*AlterTableInjectStats) alterTableCmd()        {}
+func (*AlterTableSetStorageParams) alterTableCmd()   {}
+func (*AlterTableResetStorageParams) alterTableCmd() {}
 
 var _ AlterTableCmd = &AlterTableAddColumn{}
 var _ AlterTableCmd = &AlterTableAddConstraint{}
@@ -95,6 +97,8 @@ var _ AlterTableCmd = &AlterTableSetVisible{}
 var _ AlterTableCmd = &AlterTableValidateConstraint{}
 var _ AlterTableCmd = &AlterTablePartitionByTable{}
 var _ AlterTableCmd = &AlterTableInjectStats{}
+var _ AlterTableCmd = &AlterTableSetStorageParams{}
+var _ AlterTableCmd = &AlterTableResetStorageParams{}
 
 // ColumnMutationCmd is the subset of AlterTableCmds that modify an
 // existing column.
@@ -604,6 +608,45 @@ func (node *AlterTableInjectStats) Format(ctx *FmtCtx) {
 	ctx.FormatNode(node.Stats)
 }
 
+// AlterTableSetStorageParams represents an ALTER TABLE SET command.
+type AlterTableSetStorageParams struct {
+	StorageParams StorageParams
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableSetStorageParams) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "set_storage_param")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableSetStorageParams) Format(ctx *FmtCtx) {
+	ctx.WriteString(" SET (")
+	ctx.FormatNode(&node.StorageParams)
+	ctx.WriteString(")")
+}
+
+// AlterTableResetStorageParams represents an ALTER TABLE RESET command.
+type AlterTableResetStorageParams struct {
+	Params []string
+}
+
+// TelemetryCounter implements the AlterTableCmd interface.
+func (node *AlterTableResetStorageParams) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("table", "reset_storage_param")
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTableResetStorageParams) Format(ctx *FmtCtx) {
+	ctx.WriteString(" RESET (")
+	for i, param := range node.Params {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNameP(&param)
+	}
+	ctx.WriteString(")")
+}
+
 // AlterTableLocality represents an ALTER TABLE LOCALITY command.
 type AlterTableLocality struct {
 	Name     *UnresolvedObjectName
@@ -37,12 +37,14 @@ type AlterTypeCmd interface {
 	TelemetryCounter() telemetry.Counter
 }
 
-func (*AlterTypeAddValue) alterTypeCmd()    {}
-func (*AlterTypeRenameValue) alterTypeCmd() {}
-func (*AlterTypeRename) alterTypeCmd()      {}
-func (*AlterTypeSetSchema) alterTypeCmd()   {}
-func (*AlterTypeOwner) alterTypeCmd()       {}
-func (*AlterTypeDropValue) alterTypeCmd()   {}
+func (*AlterTypeAddValue) alterTypeCmd()        {}
+func (*AlterTypeRenameValue) alterTypeCmd()     {}
+func (*AlterTypeRename) alterTypeCmd()          {}
+func (*AlterTypeSetSchema) alterTypeCmd()       {}
+func (*AlterTypeOwner) alterTypeCmd()           {}
+func (*AlterTypeDropValue) alterTypeCmd()       {}
+func (*AlterTypeRenameAttribute) alterTypeCmd() {}
+func (*AlterTypeAlterAttributes) alterTypeCmd() {}
 
 var _ AlterTypeCmd = &AlterTypeAddValue{}
 var _ AlterTypeCmd = &AlterTypeRenameValue{}
@@ -50,6 +52,8 @@ var _ AlterTypeCmd = &AlterTypeRename{}
 var _ AlterTypeCmd = &AlterTypeSetSchema{}
 var _ AlterTypeCmd = &AlterTypeOwner{}
 var _ AlterTypeCmd = &AlterTypeDropValue{}
+var _ AlterTypeCmd = &AlterTypeRenameAttribute{}
+var _ AlterTypeCmd = &AlterTypeAlterAttributes{}
 
 // AlterTypeAddValue represents an ALTER TYPE ADD VALUE command.
 type AlterTypeAddValue struct {
@@ -169,3 +173,110 @@ func (node *AlterTypeOwner) Format(ctx *FmtCtx) {
 func (node *AlterTypeOwner) TelemetryCounter() telemetry.Counter {
 	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "owner")
 }
+
+// AlterTypeRenameAttribute represents an ALTER TYPE RENAME ATTRIBUTE command.
+type AlterTypeRenameAttribute struct {
+	Name    Name
+	NewName Name
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeRenameAttribute) Format(ctx *FmtCtx) {
+	ctx.WriteString(" RENAME ATTRIBUTE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" TO ")
+	ctx.FormatNode(&node.NewName)
+}
+
+// TelemetryCounter implements the AlterTypeCmd interface.
+func (node *AlterTypeRenameAttribute) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "rename_attribute")
+}
+
+// AlterTypeAlterAttributes represents an ALTER TYPE command consisting of a
+// list of ADD, DROP and ALTER ATTRIBUTE actions.
+type AlterTypeAlterAttributes struct {
+	Actions AlterTypeAttributeActions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAlterAttributes) Format(ctx *FmtCtx) {
+	ctx.WriteByte(' ')
+	ctx.FormatNode(&node.Actions)
+}
+
+// TelemetryCounter implements the AlterTypeCmd interface.
+func (node *AlterTypeAlterAttributes) TelemetryCounter() telemetry.Counter {
+	return sqltelemetry.SchemaChangeAlterCounterWithExtra("type", "alter_attributes")
+}
+
+// AlterTypeAttributeAction represents an action on an attribute of a
+// composite type.
+type AlterTypeAttributeAction interface {
+	NodeFormatter
+	alterTypeAttributeAction()
+}
+
+func (*AlterTypeAddAttribute) alterTypeAttributeAction()       {}
+func (*AlterTypeDropAttribute) alterTypeAttributeAction()      {}
+func (*AlterTypeAlterAttributeType) alterTypeAttributeAction() {}
+
+var _ AlterTypeAttributeAction = &AlterTypeAddAttribute{}
+var _ AlterTypeAttributeAction = &AlterTypeDropAttribute{}
+var _ AlterTypeAttributeAction = &AlterTypeAlterAttributeType{}
+
+// AlterTypeAttributeActions represents a list of attribute actions.
+type AlterTypeAttributeActions []AlterTypeAttributeAction
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAttributeActions) Format(ctx *FmtCtx) {
+	for i, action := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(action)
+	}
+}
+
+// AlterTypeAddAttribute represents an ADD ATTRIBUTE action.
+type AlterTypeAddAttribute struct {
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAddAttribute) Format(ctx *FmtCtx) {
+	ctx.WriteString("ADD ATTRIBUTE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteByte(' ')
+	ctx.FormatTypeReference(node.Type)
+}
+
+// AlterTypeDropAttribute represents a DROP ATTRIBUTE action.
+type AlterTypeDropAttribute struct {
+	Name     Name
+	IfExists bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeDropAttribute) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP ATTRIBUTE ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+}
+
+// AlterTypeAlterAttributeType represents an ALTER ATTRIBUTE ... TYPE action.
+type AlterTypeAlterAttributeType struct {
+	Name Name
+	Type ResolvableTypeReference
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterTypeAlterAttributeType) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER ATTRIBUTE ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" TYPE ")
+	ctx.FormatTypeReference(node.Type)
+}
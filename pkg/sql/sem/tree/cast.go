@@ -179,6 +179,7 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oidext.T_box2d:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_bytea:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_date:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_daterange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float4:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float8:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geography: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -186,10 +187,14 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_inet:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int2:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int4:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int4range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int8:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int8range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_interval:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_jsonb:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_numeric:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_numrange:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_oid:          {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regclass:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regnamespace: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -201,6 +206,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timestamptz:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timetz:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsquery:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsrange:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tstzrange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsvector:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_varbit:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 	},
 	oid.T_bytea: {
@@ -228,16 +237,21 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oidext.T_box2d:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_bytea:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_date:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_daterange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float4:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float8:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geography: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geometry:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_inet:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int2:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int4range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int8:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int8range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_interval:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_jsonb:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_numeric:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_numrange:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_oid:          {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regclass:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regnamespace: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -249,6 +263,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timestamptz:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timetz:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsquery:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsrange:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tstzrange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsvector:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_varbit:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 	},
 	oid.T_date: {
@@ -262,6 +280,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oid.T_daterange: {
+		oid.T_daterange: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_float4: {
 		oid.T_float4:  {maxContext: CastContextImplicit, origin: contextOriginSameType},
 		oid.T_float8:  {maxContext: CastContextImplicit, origin: contextOriginPgCast},
@@ -367,6 +394,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oid.T_int4range: {
+		oid.T_int4range: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_int8: {
 		oid.T_bit:          {maxContext: CastContextExplicit, origin: contextOriginPgCast},
 		oid.T_float4:       {maxContext: CastContextImplicit, origin: contextOriginPgCast},
@@ -389,6 +425,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oid.T_int8range: {
+		oid.T_int8range: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_interval: {
 		oid.T_interval: {maxContext: CastContextImplicit, origin: contextOriginPgCast},
 		oid.T_time:     {maxContext: CastContextAssignment, origin: contextOriginPgCast},
@@ -415,6 +460,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oidext.T_jsonpath: {
+		oidext.T_jsonpath: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_name: {
 		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginPgCast},
 		oid.T_name:    {maxContext: CastContextImplicit, origin: contextOriginSameType},
@@ -428,6 +482,7 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oidext.T_box2d:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_bytea:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_date:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_daterange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float4:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float8:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geography: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -435,10 +490,14 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_inet:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int2:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int4:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int4range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int8:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int8range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_interval:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_jsonb:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_numeric:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_numrange:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_oid:          {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regclass:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regnamespace: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -450,6 +509,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timestamptz:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timetz:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsquery:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsrange:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tstzrange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsvector:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_varbit:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 	},
 	oid.T_numeric: {
@@ -466,6 +529,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oid.T_numrange: {
+		oid.T_numrange: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_oid: {
 		oid.T_int4:         {maxContext: CastContextAssignment, origin: contextOriginPgCast},
 		oid.T_int8:         {maxContext: CastContextAssignment, origin: contextOriginPgCast},
@@ -571,16 +643,21 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oidext.T_box2d:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_bytea:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_date:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_daterange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float4:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float8:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geography: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_inet:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int2:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int4:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int4range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int8:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int8range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_interval:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_jsonb:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_numeric:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_numrange:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_oid:          {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regnamespace: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regproc:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -591,6 +668,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timestamptz:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timetz:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsquery:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsrange:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tstzrange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsvector:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_varbit:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 	},
 	oid.T_time: {
@@ -639,6 +720,42 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
 	},
+	oid.T_tsquery: {
+		oid.T_tsquery: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
+	oid.T_tsrange: {
+		oid.T_tsrange: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
+	oid.T_tstzrange: {
+		oid.T_tstzrange: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
+	oid.T_tsvector: {
+		oid.T_tsvector: {maxContext: CastContextImplicit, origin: contextOriginSameType},
+		// Automatic I/O conversions to string types.
+		oid.T_bpchar:  {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_char:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_name:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_text:    {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+		oid.T_varchar: {maxContext: CastContextAssignment, origin: contextOriginAutomaticIOConversion},
+	},
 	oid.T_unknown: {
 		// Unknown is the type of an expression that statically evaluates to
 		// NULL. NULL can be implicitly cast to any type.
@@ -649,6 +766,7 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_bytea:        {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_char:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_date:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_daterange:    {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_float4:       {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_float8:       {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oidext.T_geography: {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
@@ -656,11 +774,15 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_inet:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_int2:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_int4:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_int4range:    {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_int8:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_int8range:    {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_interval:     {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_jsonb:        {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_name:         {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_numeric:      {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_numrange:     {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_oid:          {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_regclass:     {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_regnamespace: {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
@@ -673,6 +795,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_timestamptz:  {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_timetz:       {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_tsquery:      {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_tsrange:      {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_tstzrange:    {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
+		oid.T_tsvector:     {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_varbit:       {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 		oid.T_varchar:      {maxContext: CastContextImplicit, origin: contextOriginNullConversion},
 	},
@@ -699,6 +825,7 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oidext.T_box2d:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_bytea:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_date:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_daterange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float4:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_float8:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oidext.T_geography: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -706,10 +833,14 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_inet:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int2:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int4:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int4range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_int8:         {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_int8range:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_interval:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_jsonb:        {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oidext.T_jsonpath:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_numeric:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_numrange:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_oid:          {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regnamespace: {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_regproc:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
@@ -720,6 +851,10 @@ var castMap = map[oid.Oid]map[oid.Oid]cast{
 		oid.T_timestamp:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timestamptz:  {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_timetz:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsquery:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsrange:      {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tstzrange:    {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
+		oid.T_tsvector:     {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 		oid.T_varbit:       {maxContext: CastContextExplicit, origin: contextOriginAutomaticIOConversion},
 	},
 }
@@ -836,6 +971,14 @@ func ForEachCast(fn func(src, tgt oid.Oid)) {
 // ValidCast returns true if a valid cast exists from src to tgt in the given
 // context.
 func ValidCast(src, tgt *types.T, ctx CastContext) bool {
+	// Casts to and from domains are valid if they are valid for the base types
+	// of the domains.
+	if src.IsDomain() {
+		src = src.DomainBaseType()
+	}
+	if tgt.IsDomain() {
+		tgt = tgt.DomainBaseType()
+	}
 	srcFamily := src.Family()
 	tgtFamily := tgt.Family()
 
@@ -1054,6 +1197,10 @@ var validCasts = []castInfo{
 	{from: types.INetFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.StringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.JsonpathFamily, to: types.StringFamily, volatility: VolatilityImmutable},
+	{from: types.RangeFamily, to: types.StringFamily, volatility: VolatilityStable},
 
 	// Casts to CollatedStringFamily.
 	{from: types.UnknownFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
@@ -1081,6 +1228,10 @@ var validCasts = []castInfo{
 	{from: types.INetFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.JsonFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
 	{from: types.EnumFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.JsonpathFamily, to: types.CollatedStringFamily, volatility: VolatilityImmutable},
+	{from: types.RangeFamily, to: types.CollatedStringFamily, volatility: VolatilityStable},
 
 	// Casts to BytesFamily.
 	{from: types.UnknownFamily, to: types.BytesFamily, volatility: VolatilityImmutable},
@@ -1207,6 +1358,18 @@ var validCasts = []castInfo{
 	{from: types.GeometryFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 	{from: types.GeographyFamily, to: types.JsonFamily, volatility: VolatilityImmutable},
 
+	// Casts to JsonpathFamily.
+	{from: types.UnknownFamily, to: types.JsonpathFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.JsonpathFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.JsonpathFamily, volatility: VolatilityImmutable},
+	{from: types.JsonpathFamily, to: types.JsonpathFamily, volatility: VolatilityImmutable},
+
+	// Casts to RangeFamily.
+	{from: types.UnknownFamily, to: types.RangeFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.RangeFamily, volatility: VolatilityStable},
+	{from: types.CollatedStringFamily, to: types.RangeFamily, volatility: VolatilityStable},
+	{from: types.RangeFamily, to: types.RangeFamily, volatility: VolatilityImmutable},
+
 	// Casts to EnumFamily.
 	{from: types.UnknownFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
 	{from: types.StringFamily, to: types.EnumFamily, volatility: VolatilityImmutable},
@@ -1217,6 +1380,18 @@ var validCasts = []castInfo{
 	{from: types.UnknownFamily, to: types.TupleFamily, volatility: VolatilityImmutable},
 	{from: types.TupleFamily, to: types.TupleFamily, volatility: VolatilityStable},
 	{from: types.StringFamily, to: types.TupleFamily, volatility: VolatilityStable},
+
+	// Casts to TSQueryFamily.
+	{from: types.UnknownFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+	{from: types.TSQueryFamily, to: types.TSQueryFamily, volatility: VolatilityImmutable},
+
+	// Casts to TSVectorFamily.
+	{from: types.UnknownFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.StringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.CollatedStringFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
+	{from: types.TSVectorFamily, to: types.TSVectorFamily, volatility: VolatilityImmutable},
 }
 
 type castsMapKey struct {
@@ -1400,6 +1575,11 @@ func PerformAssignmentCast(ctx *EvalContext, d Datum, t *types.T) (Datum, error)
 // the width of the value is wider than a single character. For this exception,
 // AdjustValueToType performs the truncation itself.
 func AdjustValueToType(typ *types.T, inVal Datum) (outVal Datum, err error) {
+	// Values of a domain are adjusted to the base type of the domain. The
+	// constraints of the domain are checked separately.
+	if typ.IsDomain() {
+		typ = typ.DomainBaseType()
+	}
 	switch typ.Family() {
 	case types.StringFamily, types.CollatedStringFamily:
 		var sv string
@@ -1609,6 +1789,10 @@ func performCastWithoutPrecisionTruncation(
 	// Note that we pass in nil as the first argument since we're not interested
 	// in evaluating the placeholders.
 	d = UnwrapDatum(nil /* evalCtx */, d)
+	// Casts to a domain produce a value of the base type of the domain.
+	if t.IsDomain() {
+		t = t.DomainBaseType()
+	}
 	switch t.Family() {
 	case types.BitFamily:
 		var ba *DBitArray
@@ -1948,6 +2132,18 @@ func performCastWithoutPrecisionTruncation(
 			s = t.JSON.String()
 		case *DEnum:
 			s = t.LogicalRep
+		case *DTSQuery:
+			s = t.TSQuery.String()
+		case *DTSVector:
+			s = t.TSVector.String()
+		case *DJsonpath:
+			s = t.Path.String()
+		case *DRange:
+			s = AsStringWithFlags(
+				t.InLocation(ctx.GetLocation()),
+				FmtPgwireText,
+				FmtDataConversionConfig(ctx.SessionData().DataConversionConfig),
+			)
 		}
 		switch t.Family() {
 		case types.StringFamily:
@@ -2293,6 +2489,50 @@ func performCastWithoutPrecisionTruncation(
 		case *DInterval:
 			return NewDInterval(v.Duration, itm), nil
 		}
+	case types.TSQueryFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSQuery(string(*v))
+		case *DCollatedString:
+			return ParseDTSQuery(v.Contents)
+		case *DTSQuery:
+			return d, nil
+		}
+
+	case types.TSVectorFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDTSVector(string(*v))
+		case *DCollatedString:
+			return ParseDTSVector(v.Contents)
+		case *DTSVector:
+			return d, nil
+		}
+
+	case types.JsonpathFamily:
+		switch v := d.(type) {
+		case *DString:
+			return ParseDJsonpath(string(*v))
+		case *DCollatedString:
+			return ParseDJsonpath(v.Contents)
+		case *DJsonpath:
+			return d, nil
+		}
+
+	case types.RangeFamily:
+		switch v := d.(type) {
+		case *DString:
+			r, _, err := ParseDRangeFromString(ctx, string(*v), t)
+			return r, err
+		case *DCollatedString:
+			r, _, err := ParseDRangeFromString(ctx, v.Contents, t)
+			return r, err
+		case *DRange:
+			if v.Typ.Oid() == t.Oid() {
+				return d, nil
+			}
+		}
+
 	case types.JsonFamily:
 		switch v := d.(type) {
 		case *DString:
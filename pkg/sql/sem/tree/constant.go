@@ -491,6 +491,15 @@ var (
 		types.INet,
 		types.Jsonb,
 		types.VarBit,
+		types.TSQuery,
+		types.TSVector,
+		types.Jsonpath,
+		types.Int8Range,
+		types.Int4Range,
+		types.NumRange,
+		types.DateRange,
+		types.TSRange,
+		types.TSTZRange,
 		types.AnyEnum,
 		types.AnyEnumArray,
 		types.INetArray,
@@ -215,6 +215,13 @@ func TestStringConstantVerifyAvailableTypes(t *testing.T) {
 			if availType.Family() == types.EnumFamily {
 				continue
 			}
+			// Almost any string is a valid tsvector or tsquery, so they aren't
+			// interesting to resolve here. Many strings are also valid jsonpath
+			// expressions, such as 'true' or '2010-09-28'.
+			switch availType.Family() {
+			case types.TSVectorFamily, types.TSQueryFamily, types.JsonpathFamily:
+				continue
+			}
 
 			semaCtx := tree.MakeSemaContext()
 			if _, err := test.c.ResolveAsType(context.Background(), &semaCtx, availType); err != nil {
@@ -600,6 +607,13 @@ func TestStringConstantResolveAvailableTypes(t *testing.T) {
 			if availType.Family() == types.EnumFamily {
 				continue
 			}
+			// Almost any string is a valid tsvector or tsquery, so they aren't
+			// interesting to resolve here. Many strings are also valid jsonpath
+			// expressions, such as 'true' or '2010-09-28'.
+			switch availType.Family() {
+			case types.TSVectorFamily, types.TSQueryFamily, types.JsonpathFamily:
+				continue
+			}
 
 			semaCtx := tree.MakeSemaContext()
 			typedExpr, err := test.c.ResolveAsType(context.Background(), &semaCtx, availType)
@@ -20,12 +20,23 @@ type CopyFrom struct {
 	Options CopyOptions
 }
 
+// CopyTo represents a COPY TO statement. Exactly one of Table and Statement
+// is set: COPY table TO STDOUT copies the (optionally restricted) columns of
+// a table, while COPY (statement) TO STDOUT copies the result of a query.
+type CopyTo struct {
+	Table     TableName
+	Columns   NameList
+	Statement Statement
+	Options   CopyOptions
+}
+
 // CopyOptions describes options for COPY execution.
 type CopyOptions struct {
 	Destination Expr
 	CopyFormat  CopyFormat
 	Delimiter   Expr
 	Null        Expr
+	Header      bool
 }
 
 var _ NodeFormatter = &CopyOptions{}
@@ -49,6 +60,28 @@ func (node *CopyFrom) Format(ctx *FmtCtx) {
 	}
 }
 
+// Format implements the NodeFormatter interface.
+func (node *CopyTo) Format(ctx *FmtCtx) {
+	ctx.WriteString("COPY ")
+	if node.Statement != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(node.Statement)
+		ctx.WriteString(")")
+	} else {
+		ctx.FormatNode(&node.Table)
+		if len(node.Columns) > 0 {
+			ctx.WriteString(" (")
+			ctx.FormatNode(&node.Columns)
+			ctx.WriteString(")")
+		}
+	}
+	ctx.WriteString(" TO STDOUT")
+	if !node.Options.IsDefault() {
+		ctx.WriteString(" WITH ")
+		ctx.FormatNode(&node.Options)
+	}
+}
+
 // Format implements the NodeFormatter interface
 func (o *CopyOptions) Format(ctx *FmtCtx) {
 	var addSep bool
@@ -79,6 +112,10 @@ func (o *CopyOptions) Format(ctx *FmtCtx) {
 		ctx.FormatNode(o.Null)
 		addSep = true
 	}
+	if o.Header {
+		maybeAddSep()
+		ctx.WriteString("HEADER")
+	}
 	if o.Destination != nil {
 		maybeAddSep()
 		// Lowercase because that's what has historically been produced
@@ -122,6 +159,12 @@ func (o *CopyOptions) CombineWith(other *CopyOptions) error {
 		}
 		o.Null = other.Null
 	}
+	if other.Header {
+		if o.Header {
+			return errors.New("header option specified multiple times")
+		}
+		o.Header = true
+	}
 	return nil
 }
 
@@ -123,7 +123,11 @@ type IndexElem struct {
 	Column Name
 	// Expr is set if the index element is an expression (part of an expression
 	// index). If set, Column is empty.
-	Expr       Expr
+	Expr Expr
+	// OpClass is the operator class of the element, if one was specified.
+	// gin_trgm_ops, which indexes the trigrams of a string in an inverted
+	// index, is the only operator class that is supported.
+	OpClass    Name
 	Direction  Direction
 	NullsOrder NullsOrder
 }
@@ -144,6 +148,12 @@ func (node *IndexElem) Format(ctx *FmtCtx) {
 			ctx.WriteByte(')')
 		}
 	}
+	if node.OpClass != "" {
+		// The operator class is not anonymized, since it does not name a
+		// user object.
+		ctx.WriteByte(' ')
+		lexbase.EncodeRestrictedSQLIdent(&ctx.Buffer, string(node.OpClass), lexbase.EncNoFlags)
+	}
 	if node.Direction != DefaultDirection {
 		ctx.WriteByte(' ')
 		ctx.WriteString(node.Direction.String())
@@ -166,6 +176,9 @@ func (node *IndexElem) doc(p *PrettyCfg) pretty.Doc {
 			d = p.bracket("(", d, ")")
 		}
 	}
+	if node.OpClass != "" {
+		d = pretty.ConcatSpace(d, pretty.Text(string(node.OpClass)))
+	}
 	if node.Direction != DefaultDirection {
 		d = pretty.ConcatSpace(d, pretty.Keyword(node.Direction.String()))
 	}
@@ -326,12 +339,25 @@ func (l *EnumValueList) Format(ctx *FmtCtx) {
 	}
 }
 
+// CompositeTypeElem is a single attribute in a composite type definition.
+type CompositeTypeElem struct {
+	Label Name
+	Type  ResolvableTypeReference
+}
+
 // CreateType represents a CREATE TYPE statement.
 type CreateType struct {
 	TypeName *UnresolvedObjectName
 	Variety  CreateTypeVariety
 	// EnumLabels is set when this represents a CREATE TYPE ... AS ENUM statement.
 	EnumLabels EnumValueList
+	// CompositeTypeList is set when this represents a CREATE TYPE ... AS (...)
+	// statement.
+	CompositeTypeList []CompositeTypeElem
+	// DomainBaseType is set when this represents a CREATE DOMAIN statement.
+	DomainBaseType ResolvableTypeReference
+	// DomainConstraints is set when this represents a CREATE DOMAIN statement.
+	DomainConstraints DomainConstraintList
 	// IfNotExists is true if IF NOT EXISTS was requested.
 	IfNotExists bool
 }
@@ -340,7 +366,11 @@ var _ Statement = &CreateType{}
 
 // Format implements the NodeFormatter interface.
 func (node *CreateType) Format(ctx *FmtCtx) {
-	ctx.WriteString("CREATE TYPE ")
+	if node.Variety == Domain {
+		ctx.WriteString("CREATE DOMAIN ")
+	} else {
+		ctx.WriteString("CREATE TYPE ")
+	}
 	if node.IfNotExists {
 		ctx.WriteString("IF NOT EXISTS ")
 	}
@@ -351,6 +381,25 @@ func (node *CreateType) Format(ctx *FmtCtx) {
 		ctx.WriteString("AS ENUM (")
 		ctx.FormatNode(&node.EnumLabels)
 		ctx.WriteString(")")
+	case Composite:
+		ctx.WriteString("AS (")
+		for i := range node.CompositeTypeList {
+			elem := &node.CompositeTypeList[i]
+			if i > 0 {
+				ctx.WriteString(", ")
+			}
+			ctx.FormatNode(&elem.Label)
+			ctx.WriteByte(' ')
+			ctx.FormatTypeReference(elem.Type)
+		}
+		ctx.WriteString(")")
+	case Domain:
+		ctx.WriteString("AS ")
+		ctx.FormatTypeReference(node.DomainBaseType)
+		for i := range node.DomainConstraints {
+			ctx.WriteByte(' ')
+			ctx.FormatNode(&node.DomainConstraints[i])
+		}
 	}
 }
 
@@ -358,6 +407,37 @@ func (node *CreateType) String() string {
 	return AsString(node)
 }
 
+// DomainConstraint represents a constraint in a CREATE DOMAIN or ALTER DOMAIN
+// ... ADD statement. Exactly one of Check and NotNull is set, unless the
+// constraint is an explicit NULL.
+type DomainConstraint struct {
+	Name    Name
+	Check   Expr
+	NotNull bool
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DomainConstraint) Format(ctx *FmtCtx) {
+	if node.Name != "" {
+		ctx.WriteString("CONSTRAINT ")
+		ctx.FormatNode(&node.Name)
+		ctx.WriteByte(' ')
+	}
+	switch {
+	case node.Check != nil:
+		ctx.WriteString("CHECK (")
+		ctx.FormatNode(node.Check)
+		ctx.WriteByte(')')
+	case node.NotNull:
+		ctx.WriteString("NOT NULL")
+	default:
+		ctx.WriteString("NULL")
+	}
+}
+
+// DomainConstraintList represents a list of domain constraints.
+type DomainConstraintList []DomainConstraint
+
 // TableDef represents a column, index or constraint definition within a CREATE
 // TABLE statement.
 type TableDef interface {
@@ -1001,6 +1081,7 @@ type UniqueConstraintTableDef struct {
 	PrimaryKey   bool
 	WithoutIndex bool
 	IfNotExists  bool
+	Deferrable   ConstraintDeferrability
 }
 
 // SetName implements the TableDef interface.
@@ -1045,12 +1126,43 @@ func (node *UniqueConstraintTableDef) Format(ctx *FmtCtx) {
 	if node.PartitionByIndex != nil {
 		ctx.FormatNode(node.PartitionByIndex)
 	}
+	ctx.FormatNode(node.Deferrable)
 	if node.Predicate != nil {
 		ctx.WriteString(" WHERE ")
 		ctx.FormatNode(node.Predicate)
 	}
 }
 
+// ConstraintDeferrability specifies whether the checks of a constraint can be
+// deferred until the end of the transaction, and whether they are deferred by
+// default.
+type ConstraintDeferrability int
+
+// The values for ConstraintDeferrability.
+const (
+	NotDeferrableConstraint ConstraintDeferrability = iota
+	DeferrableInitiallyImmediate
+	DeferrableInitiallyDeferred
+)
+
+var constraintDeferrabilityName = [...]string{
+	NotDeferrableConstraint:      "NOT DEFERRABLE",
+	DeferrableInitiallyImmediate: "DEFERRABLE",
+	DeferrableInitiallyDeferred:  "DEFERRABLE INITIALLY DEFERRED",
+}
+
+func (d ConstraintDeferrability) String() string {
+	return constraintDeferrabilityName[d]
+}
+
+// Format implements the NodeFormatter interface.
+func (d ConstraintDeferrability) Format(ctx *FmtCtx) {
+	if d != NotDeferrableConstraint {
+		ctx.WriteByte(' ')
+		ctx.WriteString(d.String())
+	}
+}
+
 // ReferenceAction is the method used to maintain referential integrity through
 // foreign keys.
 type ReferenceAction int
@@ -1127,6 +1239,7 @@ type ForeignKeyConstraintTableDef struct {
 	Actions     ReferenceActions
 	Match       CompositeKeyMatchMethod
 	IfNotExists bool
+	Deferrable  ConstraintDeferrability
 }
 
 // Format implements the NodeFormatter interface.
@@ -1157,6 +1270,7 @@ func (node *ForeignKeyConstraintTableDef) Format(ctx *FmtCtx) {
 	}
 
 	ctx.FormatNode(&node.Actions)
+	ctx.FormatNode(node.Deferrable)
 }
 
 // SetName implements the ConstraintTableDef interface.
@@ -1482,6 +1596,11 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 			ctx.FormatNode(&node.Defs)
 			ctx.WriteByte(')')
 		}
+		if node.StorageParams != nil {
+			ctx.WriteString(` WITH (`)
+			ctx.FormatNode(&node.StorageParams)
+			ctx.WriteByte(')')
+		}
 		ctx.WriteString(" AS ")
 		ctx.FormatNode(node.AsSource)
 	} else {
@@ -1491,8 +1610,11 @@ func (node *CreateTable) FormatBody(ctx *FmtCtx) {
 		if node.PartitionByTable != nil {
 			ctx.FormatNode(node.PartitionByTable)
 		}
-		// No storage parameters are implemented, so we never list the storage
-		// parameters in the output format.
+		if node.StorageParams != nil {
+			ctx.WriteString(` WITH (`)
+			ctx.FormatNode(&node.StorageParams)
+			ctx.WriteByte(')')
+		}
 		if node.Locality != nil {
 			ctx.WriteString(" ")
 			ctx.FormatNode(node.Locality)
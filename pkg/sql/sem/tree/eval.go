@@ -44,11 +44,14 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/cockroach/pkg/util/jsonpath"
 	"github.com/cockroachdb/cockroach/pkg/util/mon"
 	"github.com/cockroachdb/cockroach/pkg/util/timeofday"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
 	"github.com/cockroachdb/cockroach/pkg/util/tracing"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/cockroach/pkg/util/uuid"
 	"github.com/cockroachdb/errors"
 	"github.com/lib/pq/oid"
@@ -511,10 +514,54 @@ func initNonArrayToNonArrayConcatenation() {
 	}
 }
 
+// initRangeOperators initializes the binary operators on range types: the
+// positional operators (<<, >>, &<, &>, -|-), which return bools, and union
+// (+), intersection (*) and difference (-), which return ranges.
+func initRangeOperators() {
+	for _, t := range types.Ranges {
+		typ := t
+		addBoolOp := func(op BinaryOperatorSymbol, fn func(*DRange, *EvalContext, *DRange) bool) {
+			BinOps[op] = append(BinOps[op], &BinOp{
+				LeftType:   typ,
+				RightType:  typ,
+				ReturnType: types.Bool,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					return MakeDBool(DBool(fn(MustBeDRange(left), ctx, MustBeDRange(right)))), nil
+				},
+				Volatility: VolatilityImmutable,
+			})
+		}
+		addRangeOp := func(op BinaryOperatorSymbol, fn func(*DRange, *EvalContext, *DRange) (*DRange, error)) {
+			BinOps[op] = append(BinOps[op], &BinOp{
+				LeftType:   typ,
+				RightType:  typ,
+				ReturnType: typ,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					r, err := fn(MustBeDRange(left), ctx, MustBeDRange(right))
+					if err != nil {
+						return nil, err
+					}
+					return r, nil
+				},
+				Volatility: VolatilityImmutable,
+			})
+		}
+		addBoolOp(LShift, (*DRange).Before)
+		addBoolOp(RShift, (*DRange).After)
+		addBoolOp(OverLeft, (*DRange).OverLeft)
+		addBoolOp(OverRight, (*DRange).OverRight)
+		addBoolOp(Adjacent, (*DRange).Adjacent)
+		addRangeOp(Plus, (*DRange).Union)
+		addRangeOp(Mult, (*DRange).Intersect)
+		addRangeOp(Minus, (*DRange).Difference)
+	}
+}
+
 func init() {
 	initArrayElementConcatenation()
 	initArrayToArrayConcatenation()
 	initNonArrayToNonArrayConcatenation()
+	initRangeOperators()
 }
 
 func init() {
@@ -1685,6 +1732,33 @@ var BinOps = map[BinaryOperatorSymbol]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		&BinOp{
+			// The trigram similarity operator of pg_trgm.
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Bool,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				l, r := string(MustBeDString(left)), string(MustBeDString(right))
+				threshold := ctx.SessionData().TrigramSimilarityThreshold
+				return MakeDBool(DBool(float64(trigram.Similarity(l, r)) >= threshold)), nil
+			},
+			// The operator depends on the pg_trgm.similarity_threshold session
+			// variable.
+			Volatility: VolatilityStable,
+		},
+	},
+
+	Distance: {
+		&BinOp{
+			LeftType:   types.String,
+			RightType:  types.String,
+			ReturnType: types.Float4,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				l, r := string(MustBeDString(left)), string(MustBeDString(right))
+				return NewDFloat(DFloat(1 - trigram.Similarity(l, r))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
 	},
 
 	Concat: {
@@ -1732,6 +1806,24 @@ var BinOps = map[BinaryOperatorSymbol]binOpOverload{
 			},
 			Volatility: VolatilityImmutable,
 		},
+		&BinOp{
+			LeftType:   types.TSVector,
+			RightType:  types.TSVector,
+			ReturnType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return NewDTSVector(MustBeDTSVector(left).Concat(MustBeDTSVector(right).TSVector)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&BinOp{
+			LeftType:   types.TSQuery,
+			RightType:  types.TSQuery,
+			ReturnType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return NewDTSQuery(MustBeDTSQuery(left).Or(MustBeDTSQuery(right).TSQuery)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
 	},
 
 	// TODO(pmattis): Check that the shift is valid.
@@ -2067,7 +2159,7 @@ func cmpOpFixups(
 	}
 
 	// Array equality comparisons.
-	for _, t := range append(types.Scalar, types.AnyEnum) {
+	for _, t := range append(append(types.Scalar, types.Ranges...), types.AnyEnum) {
 		cmpOps[EQ] = append(cmpOps[EQ], &CmpOp{
 			LeftType:   types.MakeArray(t),
 			RightType:  types.MakeArray(t),
@@ -2173,6 +2265,15 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		makeEqFn(types.String, types.String, VolatilityLeakProof),
 		makeEqFn(types.Time, types.Time, VolatilityLeakProof),
 		makeEqFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
+		makeEqFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeEqFn(types.TSVector, types.TSVector, VolatilityImmutable),
+		makeEqFn(types.Jsonpath, types.Jsonpath, VolatilityImmutable),
+		makeEqFn(types.DateRange, types.DateRange, VolatilityImmutable),
+		makeEqFn(types.Int4Range, types.Int4Range, VolatilityImmutable),
+		makeEqFn(types.Int8Range, types.Int8Range, VolatilityImmutable),
+		makeEqFn(types.NumRange, types.NumRange, VolatilityImmutable),
+		makeEqFn(types.TSRange, types.TSRange, VolatilityImmutable),
+		makeEqFn(types.TSTZRange, types.TSTZRange, VolatilityImmutable),
 		makeEqFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeEqFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
 		makeEqFn(types.Uuid, types.Uuid, VolatilityLeakProof),
@@ -2229,6 +2330,15 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		makeLtFn(types.String, types.String, VolatilityLeakProof),
 		makeLtFn(types.Time, types.Time, VolatilityLeakProof),
 		makeLtFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
+		makeLtFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLtFn(types.TSVector, types.TSVector, VolatilityImmutable),
+		makeLtFn(types.Jsonpath, types.Jsonpath, VolatilityImmutable),
+		makeLtFn(types.DateRange, types.DateRange, VolatilityImmutable),
+		makeLtFn(types.Int4Range, types.Int4Range, VolatilityImmutable),
+		makeLtFn(types.Int8Range, types.Int8Range, VolatilityImmutable),
+		makeLtFn(types.NumRange, types.NumRange, VolatilityImmutable),
+		makeLtFn(types.TSRange, types.TSRange, VolatilityImmutable),
+		makeLtFn(types.TSTZRange, types.TSTZRange, VolatilityImmutable),
 		makeLtFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLtFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
 		makeLtFn(types.Uuid, types.Uuid, VolatilityLeakProof),
@@ -2285,6 +2395,15 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		makeLeFn(types.String, types.String, VolatilityLeakProof),
 		makeLeFn(types.Time, types.Time, VolatilityLeakProof),
 		makeLeFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
+		makeLeFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeLeFn(types.TSVector, types.TSVector, VolatilityImmutable),
+		makeLeFn(types.Jsonpath, types.Jsonpath, VolatilityImmutable),
+		makeLeFn(types.DateRange, types.DateRange, VolatilityImmutable),
+		makeLeFn(types.Int4Range, types.Int4Range, VolatilityImmutable),
+		makeLeFn(types.Int8Range, types.Int8Range, VolatilityImmutable),
+		makeLeFn(types.NumRange, types.NumRange, VolatilityImmutable),
+		makeLeFn(types.TSRange, types.TSRange, VolatilityImmutable),
+		makeLeFn(types.TSTZRange, types.TSTZRange, VolatilityImmutable),
 		makeLeFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeLeFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
 		makeLeFn(types.Uuid, types.Uuid, VolatilityLeakProof),
@@ -2358,6 +2477,15 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		makeIsFn(types.String, types.String, VolatilityLeakProof),
 		makeIsFn(types.Time, types.Time, VolatilityLeakProof),
 		makeIsFn(types.TimeTZ, types.TimeTZ, VolatilityLeakProof),
+		makeIsFn(types.TSQuery, types.TSQuery, VolatilityImmutable),
+		makeIsFn(types.TSVector, types.TSVector, VolatilityImmutable),
+		makeIsFn(types.Jsonpath, types.Jsonpath, VolatilityImmutable),
+		makeIsFn(types.DateRange, types.DateRange, VolatilityImmutable),
+		makeIsFn(types.Int4Range, types.Int4Range, VolatilityImmutable),
+		makeIsFn(types.Int8Range, types.Int8Range, VolatilityImmutable),
+		makeIsFn(types.NumRange, types.NumRange, VolatilityImmutable),
+		makeIsFn(types.TSRange, types.TSRange, VolatilityImmutable),
+		makeIsFn(types.TSTZRange, types.TSTZRange, VolatilityImmutable),
 		makeIsFn(types.Timestamp, types.Timestamp, VolatilityLeakProof),
 		makeIsFn(types.TimestampTZ, types.TimestampTZ, VolatilityLeakProof),
 		makeIsFn(types.Uuid, types.Uuid, VolatilityLeakProof),
@@ -2416,6 +2544,15 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		makeEvalTupleIn(types.String, VolatilityLeakProof),
 		makeEvalTupleIn(types.Time, VolatilityLeakProof),
 		makeEvalTupleIn(types.TimeTZ, VolatilityLeakProof),
+		makeEvalTupleIn(types.TSQuery, VolatilityImmutable),
+		makeEvalTupleIn(types.TSVector, VolatilityImmutable),
+		makeEvalTupleIn(types.Jsonpath, VolatilityImmutable),
+		makeEvalTupleIn(types.DateRange, VolatilityImmutable),
+		makeEvalTupleIn(types.Int4Range, VolatilityImmutable),
+		makeEvalTupleIn(types.Int8Range, VolatilityImmutable),
+		makeEvalTupleIn(types.NumRange, VolatilityImmutable),
+		makeEvalTupleIn(types.TSRange, VolatilityImmutable),
+		makeEvalTupleIn(types.TSTZRange, VolatilityImmutable),
 		makeEvalTupleIn(types.Timestamp, VolatilityLeakProof),
 		makeEvalTupleIn(types.TimestampTZ, VolatilityLeakProof),
 		makeEvalTupleIn(types.Uuid, VolatilityLeakProof),
@@ -2540,56 +2677,62 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 		},
 	},
 
-	Contains: {
-		&CmpOp{
-			LeftType:  types.AnyArray,
-			RightType: types.AnyArray,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				haystack := MustBeDArray(left)
-				needles := MustBeDArray(right)
-				return ArrayContains(ctx, haystack, needles)
+	Contains: append(
+		cmpOpOverload{
+			&CmpOp{
+				LeftType:  types.AnyArray,
+				RightType: types.AnyArray,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					haystack := MustBeDArray(left)
+					needles := MustBeDArray(right)
+					return ArrayContains(ctx, haystack, needles)
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
-		},
-		&CmpOp{
-			LeftType:  types.Jsonb,
-			RightType: types.Jsonb,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				c, err := json.Contains(left.(*DJSON).JSON, right.(*DJSON).JSON)
-				if err != nil {
-					return nil, err
-				}
-				return MakeDBool(DBool(c)), nil
+			&CmpOp{
+				LeftType:  types.Jsonb,
+				RightType: types.Jsonb,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					c, err := json.Contains(left.(*DJSON).JSON, right.(*DJSON).JSON)
+					if err != nil {
+						return nil, err
+					}
+					return MakeDBool(DBool(c)), nil
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
 		},
-	},
+		makeRangeContainsOperators()...,
+	),
 
-	ContainedBy: {
-		&CmpOp{
-			LeftType:  types.AnyArray,
-			RightType: types.AnyArray,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				needles := MustBeDArray(left)
-				haystack := MustBeDArray(right)
-				return ArrayContains(ctx, haystack, needles)
+	ContainedBy: append(
+		cmpOpOverload{
+			&CmpOp{
+				LeftType:  types.AnyArray,
+				RightType: types.AnyArray,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					needles := MustBeDArray(left)
+					haystack := MustBeDArray(right)
+					return ArrayContains(ctx, haystack, needles)
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
-		},
-		&CmpOp{
-			LeftType:  types.Jsonb,
-			RightType: types.Jsonb,
-			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
-				c, err := json.Contains(right.(*DJSON).JSON, left.(*DJSON).JSON)
-				if err != nil {
-					return nil, err
-				}
-				return MakeDBool(DBool(c)), nil
+			&CmpOp{
+				LeftType:  types.Jsonb,
+				RightType: types.Jsonb,
+				Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+					c, err := json.Contains(right.(*DJSON).JSON, left.(*DJSON).JSON)
+					if err != nil {
+						return nil, err
+					}
+					return MakeDBool(DBool(c)), nil
+				},
+				Volatility: VolatilityImmutable,
 			},
-			Volatility: VolatilityImmutable,
 		},
-	},
-	Overlaps: append(
+		makeRangeContainedByOperators()...,
+	),
+	Overlaps: append(append(
 		cmpOpOverload{
 			&CmpOp{
 				LeftType:  types.AnyArray,
@@ -2630,8 +2773,67 @@ var CmpOps = cmpOpFixups(map[ComparisonOperatorSymbol]cmpOpOverload{
 			func(lhs, rhs *geo.CartesianBoundingBox) bool {
 				return lhs.Intersects(rhs)
 			},
-		)...,
+		)...),
+		makeRangeComparisonOperators((*DRange).Overlaps)...,
 	),
+
+	TSMatches: {
+		&CmpOp{
+			LeftType:  types.TSVector,
+			RightType: types.TSQuery,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(tsearch.EvalTSQuery(
+					MustBeDTSQuery(right).TSQuery, MustBeDTSVector(left).TSVector,
+				))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&CmpOp{
+			LeftType:  types.TSQuery,
+			RightType: types.TSVector,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(tsearch.EvalTSQuery(
+					MustBeDTSQuery(left).TSQuery, MustBeDTSVector(right).TSVector,
+				))), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+		&CmpOp{
+			LeftType:  types.Jsonb,
+			RightType: types.Jsonpath,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				// Errors are suppressed, as with the silent argument of
+				// jsonb_path_match.
+				match, ok, err := MustBeDJsonpath(right).Match(
+					left.(*DJSON).JSON, jsonpath.EvalOptions{Silent: true},
+				)
+				if err != nil || !ok {
+					return DNull, err
+				}
+				return MakeDBool(DBool(match)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
+
+	JSONPathExists: {
+		&CmpOp{
+			LeftType:  types.Jsonb,
+			RightType: types.Jsonpath,
+			Fn: func(_ *EvalContext, left Datum, right Datum) (Datum, error) {
+				// Errors are suppressed, as with the silent argument of
+				// jsonb_path_exists.
+				exists, ok, err := MustBeDJsonpath(right).Exists(
+					left.(*DJSON).JSON, jsonpath.EvalOptions{Silent: true},
+				)
+				if err != nil || !ok {
+					return DNull, err
+				}
+				return MakeDBool(DBool(exists)), nil
+			},
+			Volatility: VolatilityImmutable,
+		},
+	},
 })
 
 const experimentalBox2DClusterSettingName = "sql.spatial.experimental_box2d_comparison_operators.enabled"
@@ -2656,6 +2858,59 @@ func checkExperimentalBox2DComparisonOperatorEnabled(ctx *EvalContext) error {
 	return nil
 }
 
+// makeRangeComparisonOperators returns an overload of a boolean operator
+// between two ranges of the same type for each range type.
+func makeRangeComparisonOperators(op func(*DRange, *EvalContext, *DRange) bool) cmpOpOverload {
+	var overloads cmpOpOverload
+	for _, t := range types.Ranges {
+		overloads = append(overloads, &CmpOp{
+			LeftType:  t,
+			RightType: t,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(op(MustBeDRange(left), ctx, MustBeDRange(right)))), nil
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeContainsOperators returns the overloads of @> between a range and
+// either a range of the same type or a value of its element type.
+func makeRangeContainsOperators() cmpOpOverload {
+	overloads := makeRangeComparisonOperators((*DRange).ContainsRange)
+	for _, t := range types.Ranges {
+		overloads = append(overloads, &CmpOp{
+			LeftType:  t,
+			RightType: t.RangeContents(),
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDRange(left).ContainsElem(ctx, right))), nil
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	return overloads
+}
+
+// makeRangeContainedByOperators returns the overloads of <@ between either a
+// range or a value of its element type and a range.
+func makeRangeContainedByOperators() cmpOpOverload {
+	overloads := makeRangeComparisonOperators(func(left *DRange, ctx *EvalContext, right *DRange) bool {
+		return right.ContainsRange(ctx, left)
+	})
+	for _, t := range types.Ranges {
+		overloads = append(overloads, &CmpOp{
+			LeftType:  t.RangeContents(),
+			RightType: t,
+			Fn: func(ctx *EvalContext, left Datum, right Datum) (Datum, error) {
+				return MakeDBool(DBool(MustBeDRange(right).ContainsElem(ctx, left))), nil
+			},
+			Volatility: VolatilityImmutable,
+		})
+	}
+	return overloads
+}
+
 func makeBox2DComparisonOperators(op func(lhs, rhs *geo.CartesianBoundingBox) bool) cmpOpOverload {
 	return cmpOpOverload{
 		&CmpOp{
@@ -3228,6 +3483,16 @@ type EvalPlanner interface {
 
 	// DecodeGist exposes gist functionality to the builtin functions.
 	DecodeGist(gist string) ([]string, error)
+
+	// CreateReplicationSlot creates a logical replication slot which decodes
+	// the changes of the current database with the given output plugin. It
+	// returns the time after which the changes are decoded.
+	CreateReplicationSlot(
+		ctx context.Context, slotName, plugin string, temporary bool,
+	) (hlc.Timestamp, error)
+
+	// DropReplicationSlot drops a logical replication slot.
+	DropReplicationSlot(ctx context.Context, slotName string) error
 }
 
 // CompactEngineSpanFunc is used to compact an engine key span at the given
@@ -3413,6 +3678,36 @@ type JoinTokenCreator interface {
 	CreateJoinToken(ctx context.Context) (string, error)
 }
 
+// AdvisoryLockKey identifies an advisory lock in the current database. Locks
+// acquired with a single INT8 key have ObjSubID 1, ClassID set to the high
+// and ObjID set to the low 32 bits of the key, while locks acquired with two
+// INT4 keys have ObjSubID 2, as in Postgres.
+type AdvisoryLockKey struct {
+	ClassID  uint32
+	ObjID    uint32
+	ObjSubID uint32
+}
+
+// AdvisoryLocker acquires and releases advisory locks on behalf of the
+// session, for the pg_advisory_lock family of builtin functions.
+type AdvisoryLocker interface {
+	// AcquireAdvisoryLock acquires the lock with the given key in exclusive or
+	// shared mode. Session-level locks are held until they are released, and
+	// transaction-level locks until the end of the current transaction. If
+	// wait is false and the lock cannot be acquired immediately, false is
+	// returned.
+	AcquireAdvisoryLock(
+		ctx context.Context, key AdvisoryLockKey, exclusive, xact, wait bool,
+	) (bool, error)
+	// ReleaseAdvisoryLock releases one session-level hold on the lock with the
+	// given key in the given mode. It returns false if the session did not
+	// hold the lock.
+	ReleaseAdvisoryLock(ctx context.Context, key AdvisoryLockKey, exclusive bool) (bool, error)
+	// ReleaseAllAdvisoryLocks releases all session-level locks held by the
+	// session.
+	ReleaseAllAdvisoryLocks(ctx context.Context) error
+}
+
 // EvalContextTestingKnobs contains test knobs.
 type EvalContextTestingKnobs struct {
 	// AssertFuncExprReturnTypes indicates whether FuncExpr evaluations
@@ -3568,6 +3863,8 @@ type EvalContext struct {
 
 	JoinTokenCreator JoinTokenCreator
 
+	AdvisoryLocker AdvisoryLocker
+
 	PreparedStatementState PreparedStatementState
 
 	// The transaction in which the statement is executing.
@@ -4099,6 +4396,9 @@ func (expr *ColumnAccessExpr) Eval(ctx *EvalContext) (Datum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if d == DNull {
+		return d, nil
+	}
 	return d.(*DTuple).D[expr.ColIndex], nil
 }
 
@@ -4204,9 +4504,13 @@ func (expr *FuncExpr) evalArgs(ctx *EvalContext) (bool, Datums, error) {
 // MaybeWrapError updates non-nil error depending on the FuncExpr to provide
 // more context.
 func (expr *FuncExpr) MaybeWrapError(err error) error {
-	// If we are facing an explicit error, propagate it unchanged.
+	// If we are facing an explicit error, propagate it unchanged. Domain
+	// constraint violations are reported as if they were raised by the cast
+	// which the assertions implement.
 	fName := expr.Func.String()
-	if fName == `crdb_internal.force_error` {
+	switch fName {
+	case `crdb_internal.force_error`,
+		`crdb_internal.assert_domain_not_null`, `crdb_internal.assert_domain_check`:
 		return err
 	}
 	// Otherwise, wrap it with context.
@@ -4636,6 +4940,26 @@ func (t *DJSON) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
 }
 
+// Eval implements the TypedExpr interface.
+func (t *DTSVector) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DTSQuery) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DJsonpath) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
+// Eval implements the TypedExpr interface.
+func (t *DRange) Eval(_ *EvalContext) (Datum, error) {
+	return t, nil
+}
+
 // Eval implements the TypedExpr interface.
 func (t dNull) Eval(_ *EvalContext) (Datum, error) {
 	return t, nil
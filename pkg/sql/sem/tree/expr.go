@@ -404,6 +404,8 @@ const (
 	JSONSomeExists
 	JSONAllExists
 	Overlaps
+	TSMatches
+	JSONPathExists
 
 	// The following operators will always be used with an associated SubOperator.
 	// If Go had algebraic data types they would be defined in a self-contained
@@ -455,6 +457,8 @@ var comparisonOpName = [...]string{
 	JSONSomeExists:    "?|",
 	JSONAllExists:     "?&",
 	Overlaps:          "&&",
+	TSMatches:         "@@",
+	JSONPathExists:    "@?",
 	Any:               "ANY",
 	Some:              "SOME",
 	All:               "ALL",
@@ -1165,6 +1169,10 @@ const (
 	JSONFetchText
 	JSONFetchValPath
 	JSONFetchTextPath
+	Distance
+	OverLeft
+	OverRight
+	Adjacent
 
 	NumBinaryOperatorSymbols
 )
@@ -1189,6 +1197,10 @@ var binaryOpName = [...]string{
 	JSONFetchText:     "->>",
 	JSONFetchValPath:  "#>",
 	JSONFetchTextPath: "#>>",
+	Distance:          "<->",
+	OverLeft:          "&<",
+	OverRight:         "&>",
+	Adjacent:          "-|-",
 }
 
 // binaryOpPrio follows the precedence order in the grammar. Used for pretty-printing.
@@ -1196,7 +1208,7 @@ var binaryOpPrio = [...]int{
 	Pow:  1,
 	Mult: 2, Div: 2, FloorDiv: 2, Mod: 2,
 	Plus: 3, Minus: 3,
-	LShift: 4, RShift: 4,
+	LShift: 4, RShift: 4, Distance: 4, OverLeft: 4, OverRight: 4, Adjacent: 4,
 	Bitand: 5,
 	Bitxor: 6,
 	Bitor:  7,
@@ -1209,7 +1221,7 @@ var binaryOpFullyAssoc = [...]bool{
 	Pow:  false,
 	Mult: true, Div: false, FloorDiv: false, Mod: false,
 	Plus: true, Minus: false,
-	LShift: false, RShift: false,
+	LShift: false, RShift: false, Distance: false, OverLeft: false, OverRight: false, Adjacent: false,
 	Bitand: true,
 	Bitxor: true,
 	Bitor:  true,
@@ -1861,6 +1873,10 @@ func (node *DGeometry) String() string        { return AsString(node) }
 func (node *DInt) String() string             { return AsString(node) }
 func (node *DInterval) String() string        { return AsString(node) }
 func (node *DJSON) String() string            { return AsString(node) }
+func (node *DTSVector) String() string        { return AsString(node) }
+func (node *DTSQuery) String() string         { return AsString(node) }
+func (node *DJsonpath) String() string        { return AsString(node) }
+func (node *DRange) String() string           { return AsString(node) }
 func (node *DUuid) String() string            { return AsString(node) }
 func (node *DIPAddr) String() string          { return AsString(node) }
 func (node *DString) String() string          { return AsString(node) }
@@ -1874,6 +1890,7 @@ func (node *DOidWrapper) String() string      { return AsString(node) }
 func (node *Exprs) String() string            { return AsString(node) }
 func (node *ArrayFlatten) String() string     { return AsString(node) }
 func (node *FuncExpr) String() string         { return AsString(node) }
+func (node *GroupingSet) String() string      { return AsString(node) }
 func (node *IfExpr) String() string           { return AsString(node) }
 func (node *IfErrExpr) String() string        { return AsString(node) }
 func (node *IndexedVar) String() string       { return AsString(node) }
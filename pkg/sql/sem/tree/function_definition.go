@@ -174,6 +174,25 @@ func NewFunctionDefinition(
 	}
 }
 
+// NewUDFFunctionDefinition constructs a FunctionDefinition for the given
+// overloads of a user-defined function. Unlike builtins, user-defined
+// functions are given the chance to see NULL arguments, since the optimizer
+// handles their null input behavior when inlining them.
+func NewUDFFunctionDefinition(name string, def []Overload) *FunctionDefinition {
+	overloads := make([]overloadImpl, len(def))
+	for i := range def {
+		overloads[i] = &def[i]
+	}
+	return &FunctionDefinition{
+		Name:       name,
+		Definition: overloads,
+		FunctionProperties: FunctionProperties{
+			NullableArgs: true,
+			Class:        NormalClass,
+		},
+	}
+}
+
 // FunDefs holds pre-allocated FunctionDefinition instances
 // for every builtin function. Initialized by builtins.init().
 var FunDefs map[string]*FunctionDefinition
@@ -188,3 +207,8 @@ func (fd *FunctionDefinition) Format(ctx *FmtCtx) {
 	ctx.WriteString(fd.Name)
 }
 func (fd *FunctionDefinition) String() string { return AsString(fd) }
+
+// IsUDF returns true if this is the definition of a user-defined function.
+func (fd *FunctionDefinition) IsUDF() bool {
+	return len(fd.Definition) > 0 && fd.Definition[0].(*Overload).IsUDF
+}
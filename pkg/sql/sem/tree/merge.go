@@ -0,0 +1,132 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// Merge represents a MERGE statement.
+type Merge struct {
+	With   *With
+	Table  TableExpr
+	Source TableExpr
+	On     Expr
+	Whens  MergeWhens
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Merge) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.With)
+	ctx.WriteString("MERGE INTO ")
+	ctx.FormatNode(node.Table)
+	ctx.WriteString(" USING ")
+	ctx.FormatNode(node.Source)
+	ctx.WriteString(" ON ")
+	ctx.FormatNode(node.On)
+	for _, w := range node.Whens {
+		ctx.WriteByte(' ')
+		ctx.FormatNode(w)
+	}
+}
+
+// MergeWhens represents the list of WHEN clauses of a MERGE statement. The
+// clauses are evaluated in order, and the first one whose condition holds
+// determines the action taken for a row.
+type MergeWhens []*MergeWhen
+
+// MergeWhen represents a single WHEN [NOT] MATCHED clause of a MERGE
+// statement.
+type MergeWhen struct {
+	// Matched is true for WHEN MATCHED clauses, which apply to target rows
+	// that join with a source row, and false for WHEN NOT MATCHED clauses,
+	// which apply to source rows without a matching target row.
+	Matched bool
+	// Cond is the optional AND condition of the clause; nil if omitted.
+	Cond   Expr
+	Action MergeAction
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeWhen) Format(ctx *FmtCtx) {
+	ctx.WriteString("WHEN ")
+	if !node.Matched {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("MATCHED")
+	if node.Cond != nil {
+		ctx.WriteString(" AND ")
+		ctx.FormatNode(node.Cond)
+	}
+	ctx.WriteString(" THEN ")
+	ctx.FormatNode(node.Action)
+}
+
+// MergeAction is the action taken by a WHEN clause of a MERGE statement.
+type MergeAction interface {
+	NodeFormatter
+	mergeAction()
+}
+
+func (*MergeUpdate) mergeAction()    {}
+func (*MergeDelete) mergeAction()    {}
+func (*MergeInsert) mergeAction()    {}
+func (*MergeDoNothing) mergeAction() {}
+
+// MergeUpdate represents the UPDATE SET action of a WHEN MATCHED clause.
+type MergeUpdate struct {
+	Exprs UpdateExprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeUpdate) Format(ctx *FmtCtx) {
+	ctx.WriteString("UPDATE SET ")
+	ctx.FormatNode(&node.Exprs)
+}
+
+// MergeDelete represents the DELETE action of a WHEN MATCHED clause.
+type MergeDelete struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDelete) Format(ctx *FmtCtx) {
+	ctx.WriteString("DELETE")
+}
+
+// MergeInsert represents the INSERT action of a WHEN NOT MATCHED clause.
+type MergeInsert struct {
+	// Columns is the optional list of target columns; if empty, the values
+	// are assigned to the table's columns in order.
+	Columns NameList
+	// Exprs is the list of values to insert; if empty, the statement uses
+	// INSERT DEFAULT VALUES.
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeInsert) Format(ctx *FmtCtx) {
+	ctx.WriteString("INSERT")
+	if len(node.Exprs) == 0 {
+		ctx.WriteString(" DEFAULT VALUES")
+		return
+	}
+	if len(node.Columns) > 0 {
+		ctx.WriteString(" (")
+		ctx.FormatNode(&node.Columns)
+		ctx.WriteByte(')')
+	}
+	ctx.WriteString(" VALUES (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
+// MergeDoNothing represents the DO NOTHING action of a WHEN clause.
+type MergeDoNothing struct{}
+
+// Format implements the NodeFormatter interface.
+func (node *MergeDoNothing) Format(ctx *FmtCtx) {
+	ctx.WriteString("DO NOTHING")
+}
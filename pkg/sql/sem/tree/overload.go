@@ -110,6 +110,20 @@ type Overload struct {
 	// DistSQL. One example is when the type information for function arguments
 	// cannot be recovered.
 	DistsqlBlocklist bool
+
+	// IsUDF is set to true when this is an overload of a user-defined function
+	// rather than a builtin. User-defined functions have no Go implementation;
+	// their SQL Body is inlined into the calling query by the optimizer.
+	IsUDF bool
+
+	// Body is the SQL body of a user-defined function. It is only set if IsUDF
+	// is true.
+	Body string
+
+	// ReturnsNullOnNullInput is set to true if the user-defined function
+	// returns NULL, without evaluating its body, whenever any of its arguments
+	// is NULL. It is only used if IsUDF is true.
+	ReturnsNullOnNullInput bool
 }
 
 // params implements the overloadImpl interface.
@@ -0,0 +1,165 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+)
+
+var malformedRangeError = pgerror.Newf(pgcode.InvalidTextRepresentation, "malformed range literal")
+
+type rangeParseState struct {
+	s                string
+	ctx              ParseTimeContext
+	dependsOnContext bool
+	t                *types.T
+}
+
+func (p *rangeParseState) advance() {
+	_, l := utf8.DecodeRuneInString(p.s)
+	p.s = p.s[l:]
+}
+
+func (p *rangeParseState) eatWhitespace() {
+	for unicode.IsSpace(p.peek()) {
+		p.advance()
+	}
+}
+
+func (p *rangeParseState) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.s)
+	return r
+}
+
+func (p *rangeParseState) eof() bool {
+	return len(p.s) == 0
+}
+
+// parseBound parses the text of a single bound, up to but not including the
+// character that terminates it. Like in tuples, double quotes may be used to
+// include special characters in the bound, within which a doubled double
+// quote stands for one double quote, and a backslash escapes the following
+// character anywhere. An unquoted empty bound is infinite.
+func (p *rangeParseState) parseBound(isTerminatingChar func(byte) bool) (RangeBound, error) {
+	var result bytes.Buffer
+	quoted := false
+	inQuote := false
+	i := 0
+	for ; i < len(p.s) && (inQuote || !isTerminatingChar(p.s[i])); i++ {
+		switch ch := p.s[i]; {
+		case ch == '\\':
+			i++
+			if i < len(p.s) {
+				result.WriteByte(p.s[i])
+			}
+		case ch == '"':
+			if inQuote && i+1 < len(p.s) && p.s[i+1] == '"' {
+				result.WriteByte('"')
+				i++
+			} else {
+				quoted = true
+				inQuote = !inQuote
+			}
+		default:
+			result.WriteByte(ch)
+		}
+	}
+	if i >= len(p.s) || inQuote {
+		return RangeBound{}, errors.WithDetail(malformedRangeError, "Unexpected end of input.")
+	}
+	p.s = p.s[i:]
+	if !quoted && result.Len() == 0 {
+		return RangeBound{}, nil
+	}
+	d, dependsOnContext, err := ParseAndRequireString(p.t.RangeContents(), result.String(), p.ctx)
+	if err != nil {
+		return RangeBound{}, err
+	}
+	if dependsOnContext {
+		p.dependsOnContext = true
+	}
+	return RangeBound{Val: d}, nil
+}
+
+// ParseDRangeFromString parses the text representation of a range of the
+// given type, such as `[1,10)` or `empty`. The result is in canonical form.
+//
+// The dependsOnContext return value indicates if we had to consult the
+// ParseTimeContext (either for the time or the local timezone).
+func ParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	ret, dependsOnContext, err := doParseDRangeFromString(ctx, s, t)
+	if err != nil {
+		return nil, false, MakeParseError(s, t, err)
+	}
+	return ret, dependsOnContext, nil
+}
+
+// doParseDRangeFromString does most of the work of ParseDRangeFromString,
+// except the error it returns isn't prettified as a parsing error.
+func doParseDRangeFromString(
+	ctx ParseTimeContext, s string, t *types.T,
+) (_ *DRange, dependsOnContext bool, _ error) {
+	if t.RangeContents() == nil {
+		return nil, false, errors.AssertionFailedf("not a range type %s (%T)", t, t)
+	}
+	if strings.EqualFold(strings.TrimSpace(s), "empty") {
+		return NewDEmptyRange(t), false, nil
+	}
+	parser := rangeParseState{s: s, ctx: ctx, t: t}
+
+	parser.eatWhitespace()
+	var lowerInc bool
+	switch parser.peek() {
+	case '[':
+		lowerInc = true
+	case '(':
+	default:
+		return nil, false, errors.WithDetail(malformedRangeError, "Missing left parenthesis or bracket.")
+	}
+	parser.advance()
+	lower, err := parser.parseBound(func(ch byte) bool { return ch == ',' })
+	if err != nil {
+		return nil, false, err
+	}
+	parser.advance()
+	upper, err := parser.parseBound(func(ch byte) bool { return ch == ')' || ch == ']' })
+	if err != nil {
+		return nil, false, err
+	}
+	upperInc := parser.peek() == ']'
+	parser.advance()
+	parser.eatWhitespace()
+	if !parser.eof() {
+		return nil, false, errors.WithDetail(malformedRangeError, "Junk after right parenthesis or bracket.")
+	}
+	lower.Inclusive = lowerInc
+	upper.Inclusive = upperInc
+
+	var evalCtx *EvalContext
+	if c, ok := ctx.(*EvalContext); ok {
+		evalCtx = c
+	}
+	r, err := NewDRange(evalCtx, t, lower, upper)
+	if err != nil {
+		return nil, false, err
+	}
+	return r, parser.dependsOnContext, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree_test
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/randgen"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/randutil"
+	"github.com/stretchr/testify/require"
+)
+
+const randomRangeIterations = 1000
+
+func TestParseRange(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	testData := []struct {
+		str      string
+		typ      *types.T
+		expected string
+	}{
+		{`[1,10)`, types.Int4Range, `[1,10)`},
+		{`[1,10]`, types.Int4Range, `[1,11)`},
+		{`(1,10)`, types.Int4Range, `[2,10)`},
+		{`  ( 1 , 10 ]  `, types.Int8Range, `[2,11)`},
+		{`(,10]`, types.Int8Range, `(,11)`},
+		{`[1,]`, types.Int8Range, `[1,)`},
+		{`(,)`, types.Int8Range, `(,)`},
+		{`[1,1)`, types.Int4Range, `empty`},
+		{`(1,2)`, types.Int4Range, `empty`},
+		{`EMPTY`, types.Int4Range, `empty`},
+		{`[1.5,1.5]`, types.NumRange, `[1.5,1.5]`},
+		{`(1.5,1.5]`, types.NumRange, `empty`},
+		{`["1.0",2.00)`, types.NumRange, `[1.0,2.00)`},
+		{`[2020-01-01,2020-01-31]`, types.DateRange, `[2020-01-01,2020-02-01)`},
+		{
+			`["2020-01-01 10:00","2020-01-01 11:00")`,
+			types.TSRange,
+			`["2020-01-01 10:00:00","2020-01-01 11:00:00")`,
+		},
+	}
+	for _, td := range testData {
+		t.Run(td.str, func(t *testing.T) {
+			evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+			actual, _, err := tree.ParseDRangeFromString(evalCtx, td.str, td.typ)
+			require.NoError(t, err)
+			require.Equal(t, td.expected, tree.AsStringWithFlags(actual, tree.FmtPgwireText))
+		})
+	}
+}
+
+func TestParseRangeRandomDatums(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	rng, _ := randutil.NewTestRand()
+	for i := 0; i < randomRangeIterations; i++ {
+		typ := randgen.RandTypeFromSlice(rng, types.Ranges)
+		r := randgen.RandDatum(rng, typ, false /* nullOk */)
+		rangeString := tree.AsStringWithFlags(r, tree.FmtPgwireText)
+
+		evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+		parsed, _, err := tree.ParseDRangeFromString(evalCtx, rangeString, typ)
+		if err != nil {
+			t.Fatalf("got error: %s\n range: %s\n type: %s", err, rangeString, typ)
+		}
+		if r.Compare(evalCtx, parsed) != 0 {
+			t.Fatalf(`iteration %d: range "%s", got %s, expected %s`, i, rangeString, parsed, r)
+		}
+	}
+}
+
+func TestParseRangeError(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+	testData := []struct {
+		str           string
+		typ           *types.T
+		expectedError string
+	}{
+		{``, types.Int4Range, `malformed range literal`},
+		{`1,2`, types.Int4Range, `malformed range literal`},
+		{`[1,2`, types.Int4Range, `malformed range literal`},
+		{`[1,2,3)`, types.Int4Range, `strconv.ParseInt: parsing "2,3": invalid syntax`},
+		{`[1)`, types.Int4Range, `malformed range literal`},
+		{`[1,2) x`, types.Int4Range, `malformed range literal`},
+		{`[2,1)`, types.Int4Range, `range lower bound must be less than or equal to range upper bound`},
+		{`[1,hello)`, types.Int4Range, `strconv.ParseInt: parsing "hello": invalid syntax`},
+		{`[1,3000000000)`, types.Int4Range, `integer out of range for type int4`},
+	}
+	for _, td := range testData {
+		t.Run(td.str, func(t *testing.T) {
+			evalCtx := tree.NewTestingEvalContext(cluster.MakeTestingClusterSettings())
+			_, _, err := tree.ParseDRangeFromString(evalCtx, td.str, td.typ)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), td.expectedError)
+		})
+	}
+}
@@ -148,7 +148,54 @@ func (d *DArray) pgwireFormat(ctx *FmtCtx) {
 	}
 }
 
-var tupleQuoteSet, arrayQuoteSet asciiSet
+func (d *DRange) pgwireFormat(ctx *FmtCtx) {
+	// Range bounds are printed in "postgres mode" and then quoted like tuple
+	// elements, except that brackets also require quoting.
+	if d.Empty {
+		ctx.WriteString("empty")
+		return
+	}
+	if d.Lower.Inclusive {
+		ctx.WriteByte('[')
+	} else {
+		ctx.WriteByte('(')
+	}
+	for i, b := range []RangeBound{d.Lower, d.Upper} {
+		if i > 0 {
+			ctx.WriteByte(',')
+		}
+		if b.Val != nil {
+			s := AsStringWithFlags(b.Val, ctx.flags, FmtDataConversionConfig(ctx.dataConversionConfig))
+			pgwireFormatStringInRange(&ctx.Buffer, s)
+		}
+	}
+	if d.Upper.Inclusive {
+		ctx.WriteByte(']')
+	} else {
+		ctx.WriteByte(')')
+	}
+}
+
+func pgwireFormatStringInRange(buf *bytes.Buffer, in string) {
+	quote := in == "" || rangeQuoteSet.in(in)
+	if quote {
+		buf.WriteByte('"')
+	}
+	for _, r := range in {
+		if r == '"' || r == '\\' {
+			// Strings in ranges double " and \.
+			buf.WriteByte(byte(r))
+			buf.WriteByte(byte(r))
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	if quote {
+		buf.WriteByte('"')
+	}
+}
+
+var tupleQuoteSet, arrayQuoteSet, rangeQuoteSet asciiSet
 
 func init() {
 	var ok bool
@@ -160,6 +207,10 @@ func init() {
 	if !ok {
 		panic("array asciiset")
 	}
+	rangeQuoteSet, ok = makeASCIISet(" \t\v\f\r\n()[],\"\\")
+	if !ok {
+		panic("range asciiset")
+	}
 }
 
 func pgwireQuoteStringInTuple(in string) bool {
@@ -0,0 +1,147 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+// CreatePublication represents a CREATE PUBLICATION statement.
+type CreatePublication struct {
+	Name Name
+	// AllTables is set for FOR ALL TABLES, in which case Tables is empty.
+	AllTables bool
+	Tables    TableNames
+	Options   KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreatePublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE PUBLICATION ")
+	ctx.FormatNode(&node.Name)
+	if node.AllTables {
+		ctx.WriteString(" FOR ALL TABLES")
+	} else if len(node.Tables) > 0 {
+		ctx.WriteString(" FOR TABLE ")
+		ctx.FormatNode(&node.Tables)
+	}
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// AlterPublicationAction is the action performed by an ALTER PUBLICATION
+// statement.
+type AlterPublicationAction int
+
+// AlterPublicationAction values.
+const (
+	// AlterPublicationAddTables adds tables to the publication.
+	AlterPublicationAddTables AlterPublicationAction = iota
+	// AlterPublicationDropTables removes tables from the publication.
+	AlterPublicationDropTables
+	// AlterPublicationSetTables replaces the tables of the publication.
+	AlterPublicationSetTables
+	// AlterPublicationSetOptions changes the options of the publication.
+	AlterPublicationSetOptions
+)
+
+// AlterPublication represents an ALTER PUBLICATION statement.
+type AlterPublication struct {
+	Name   Name
+	Action AlterPublicationAction
+	// Tables is set for the ADD, DROP and SET TABLE actions.
+	Tables TableNames
+	// Options is set for the SET action.
+	Options KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *AlterPublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("ALTER PUBLICATION ")
+	ctx.FormatNode(&node.Name)
+	switch node.Action {
+	case AlterPublicationAddTables:
+		ctx.WriteString(" ADD TABLE ")
+		ctx.FormatNode(&node.Tables)
+	case AlterPublicationDropTables:
+		ctx.WriteString(" DROP TABLE ")
+		ctx.FormatNode(&node.Tables)
+	case AlterPublicationSetTables:
+		ctx.WriteString(" SET TABLE ")
+		ctx.FormatNode(&node.Tables)
+	case AlterPublicationSetOptions:
+		ctx.WriteString(" SET (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// DropPublication represents a DROP PUBLICATION statement.
+type DropPublication struct {
+	Names        NameList
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropPublication) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP PUBLICATION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Names)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteString(" ")
+		ctx.WriteString(dropBehaviorName[node.DropBehavior])
+	}
+}
+
+// CreateSubscription represents a CREATE SUBSCRIPTION statement.
+type CreateSubscription struct {
+	Name         Name
+	Connection   Expr
+	Publications NameList
+	Options      KVOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE SUBSCRIPTION ")
+	ctx.FormatNode(&node.Name)
+	ctx.WriteString(" CONNECTION ")
+	ctx.FormatNode(node.Connection)
+	ctx.WriteString(" PUBLICATION ")
+	ctx.FormatNode(&node.Publications)
+	if len(node.Options) > 0 {
+		ctx.WriteString(" WITH (")
+		ctx.FormatNode(&node.Options)
+		ctx.WriteString(")")
+	}
+}
+
+// DropSubscription represents a DROP SUBSCRIPTION statement.
+type DropSubscription struct {
+	Name         Name
+	IfExists     bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropSubscription) Format(ctx *FmtCtx) {
+	ctx.WriteString("DROP SUBSCRIPTION ")
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Name)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteString(" ")
+		ctx.WriteString(dropBehaviorName[node.DropBehavior])
+	}
+}
@@ -0,0 +1,542 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import (
+	"math"
+	"time"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/errors"
+	"github.com/lib/pq/oid"
+)
+
+// RangeBound is one of the two bounds of a DRange.
+type RangeBound struct {
+	// Val is the value of the bound, which has the range's element type. It is
+	// nil if the bound is infinite.
+	Val Datum
+	// Inclusive is true if Val is itself contained in the range. It is always
+	// false for infinite bounds.
+	Inclusive bool
+}
+
+// IsInfinite returns true if the bound does not limit the range.
+func (b RangeBound) IsInfinite() bool {
+	return b.Val == nil
+}
+
+// DRange is the Datum representation of the built-in range types. Ranges are
+// always stored in their canonical form: the bounds of discrete ranges
+// (int4range, int8range and daterange) are inclusive below and exclusive
+// above, and ranges that contain no values are represented by the empty range.
+type DRange struct {
+	Typ   *types.T
+	Lower RangeBound
+	Upper RangeBound
+	// Empty is true for the range that contains no values. Lower and Upper are
+	// unset for the empty range.
+	Empty bool
+}
+
+var errRangeBoundsOrder = pgerror.New(pgcode.DataException,
+	"range lower bound must be less than or equal to range upper bound")
+
+// NewDRange returns a range of the given type between the given bounds, in
+// canonical form. An error is returned if the lower bound is greater than the
+// upper bound.
+func NewDRange(ctx *EvalContext, typ *types.T, lower, upper RangeBound) (*DRange, error) {
+	if lower.IsInfinite() {
+		lower.Inclusive = false
+	}
+	if upper.IsInfinite() {
+		upper.Inclusive = false
+	}
+	for _, b := range []*RangeBound{&lower, &upper} {
+		if err := checkRangeBoundValue(typ, b.Val); err != nil {
+			return nil, err
+		}
+	}
+	if isDiscreteRange(typ) {
+		var err error
+		if !lower.IsInfinite() && !lower.Inclusive {
+			if lower.Val, err = rangeSuccessor(typ, lower.Val); err != nil {
+				return nil, err
+			}
+			lower.Inclusive = true
+		}
+		if !upper.IsInfinite() && upper.Inclusive {
+			if upper.Val, err = rangeSuccessor(typ, upper.Val); err != nil {
+				return nil, err
+			}
+			upper.Inclusive = false
+		}
+	}
+	if !lower.IsInfinite() && !upper.IsInfinite() {
+		c := compareRangeValues(ctx, lower.Val, upper.Val)
+		if c > 0 {
+			return nil, errRangeBoundsOrder
+		}
+		if c == 0 && !(lower.Inclusive && upper.Inclusive) {
+			return NewDEmptyRange(typ), nil
+		}
+	}
+	return &DRange{Typ: typ, Lower: lower, Upper: upper}, nil
+}
+
+// NewDEmptyRange returns the empty range of the given type.
+func NewDEmptyRange(typ *types.T) *DRange {
+	return &DRange{Typ: typ, Empty: true}
+}
+
+// isDiscreteRange returns true if the given range type has a discrete element
+// type, in which case its ranges are canonicalized to have an inclusive lower
+// bound and an exclusive upper bound.
+func isDiscreteRange(typ *types.T) bool {
+	switch typ.Oid() {
+	case oid.T_int4range, oid.T_int8range, oid.T_daterange:
+		return true
+	}
+	return false
+}
+
+// checkRangeBoundValue verifies that a bound value fits the element type of
+// the range, which matters for int4range since integer datums are 64 bits
+// wide.
+func checkRangeBoundValue(typ *types.T, val Datum) error {
+	if val == nil || typ.Oid() != oid.T_int4range {
+		return nil
+	}
+	if i := MustBeDInt(val); i < math.MinInt32 || i > math.MaxInt32 {
+		return ErrInt4OutOfRange
+	}
+	return nil
+}
+
+// rangeSuccessor returns the value that follows val in the element type of a
+// discrete range.
+func rangeSuccessor(typ *types.T, val Datum) (Datum, error) {
+	switch v := val.(type) {
+	case *DInt:
+		if (typ.Oid() == oid.T_int4range && *v == math.MaxInt32) || *v == math.MaxInt64 {
+			return nil, ErrIntOutOfRange
+		}
+		return NewDInt(*v + 1), nil
+	case *DDate:
+		if !v.IsFinite() {
+			return v, nil
+		}
+		d, err := v.AddDays(1)
+		if err != nil {
+			return nil, err
+		}
+		return NewDDate(d), nil
+	}
+	return nil, errors.AssertionFailedf("unexpected range bound %T", val)
+}
+
+// AsDRange attempts to retrieve a *DRange from an Expr, returning a *DRange and
+// a flag signifying whether the assertion was successful. The function should
+// be used instead of direct type assertions wherever a *DRange wrapped by a
+// *DOidWrapper is possible.
+func AsDRange(e Expr) (*DRange, bool) {
+	switch t := e.(type) {
+	case *DRange:
+		return t, true
+	case *DOidWrapper:
+		return AsDRange(t.Wrapped)
+	}
+	return nil, false
+}
+
+// MustBeDRange attempts to retrieve a *DRange from an Expr, panicking if the
+// assertion fails.
+func MustBeDRange(e Expr) *DRange {
+	r, ok := AsDRange(e)
+	if !ok {
+		panic(errors.AssertionFailedf("expected *DRange, found %T", e))
+	}
+	return r
+}
+
+// ResolvedType implements the TypedExpr interface.
+func (d *DRange) ResolvedType() *types.T {
+	return d.Typ
+}
+
+// Compare implements the Datum interface.
+func (d *DRange) Compare(ctx *EvalContext, other Datum) int {
+	res, err := d.CompareError(ctx, other)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// CompareError implements the Datum interface. Ranges are ordered by their
+// lower bounds and then by their upper bounds; the empty range sorts before
+// all other ranges.
+func (d *DRange) CompareError(ctx *EvalContext, other Datum) (int, error) {
+	if other == DNull {
+		// NULL is less than any non-NULL value.
+		return 1, nil
+	}
+	v, ok := UnwrapDatum(ctx, other).(*DRange)
+	if !ok || !d.Typ.Equivalent(v.Typ) {
+		return 0, makeUnsupportedComparisonMessage(d, other)
+	}
+	switch {
+	case d.Empty && v.Empty:
+		return 0, nil
+	case d.Empty:
+		return -1, nil
+	case v.Empty:
+		return 1, nil
+	}
+	if c := compareRangeBounds(ctx, d.Lower, true, v.Lower, true); c != 0 {
+		return c, nil
+	}
+	return compareRangeBounds(ctx, d.Upper, false, v.Upper, false), nil
+}
+
+// compareRangeValues compares two values of the element type of a range.
+// Decimals and timestamps are compared directly rather than through Compare,
+// which needs scratch space from the EvalContext and consults the session time
+// zone, so that ranges can be built and compared without an EvalContext (e.g.
+// while parsing or decoding them).
+func compareRangeValues(ctx *EvalContext, a, b Datum) int {
+	if da, ok := UnwrapDatum(ctx, a).(*DDecimal); ok {
+		if db, ok := UnwrapDatum(ctx, b).(*DDecimal); ok {
+			return CompareDecimals(&da.Decimal, &db.Decimal)
+		}
+	}
+	var ta, tb time.Time
+	switch t := UnwrapDatum(ctx, a).(type) {
+	case *DTimestamp:
+		ta = t.Time
+	case *DTimestampTZ:
+		ta = t.Time
+	default:
+		return a.Compare(ctx, b)
+	}
+	switch t := UnwrapDatum(ctx, b).(type) {
+	case *DTimestamp:
+		tb = t.Time
+	case *DTimestampTZ:
+		tb = t.Time
+	default:
+		return a.Compare(ctx, b)
+	}
+	switch {
+	case ta.Before(tb):
+		return -1
+	case ta.After(tb):
+		return 1
+	}
+	return 0
+}
+
+// compareRangeBounds compares two range bounds, each of which is either a
+// lower or an upper bound. A bound compares as the smallest point it admits
+// into its range, so an inclusive lower bound sorts before an exclusive one
+// with the same value, while an inclusive upper bound sorts after an exclusive
+// one.
+func compareRangeBounds(ctx *EvalContext, b1 RangeBound, lower1 bool, b2 RangeBound, lower2 bool) int {
+	if b1.IsInfinite() && b2.IsInfinite() {
+		if lower1 == lower2 {
+			return 0
+		}
+		if lower1 {
+			return -1
+		}
+		return 1
+	}
+	if b1.IsInfinite() {
+		if lower1 {
+			return -1
+		}
+		return 1
+	}
+	if b2.IsInfinite() {
+		if lower2 {
+			return 1
+		}
+		return -1
+	}
+	if c := compareRangeValues(ctx, b1.Val, b2.Val); c != 0 {
+		return c
+	}
+	switch {
+	case !b1.Inclusive && !b2.Inclusive:
+		if lower1 == lower2 {
+			return 0
+		}
+		if lower1 {
+			return 1
+		}
+		return -1
+	case !b1.Inclusive:
+		if lower1 {
+			return 1
+		}
+		return -1
+	case !b2.Inclusive:
+		if lower2 {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// Prev implements the Datum interface.
+func (d *DRange) Prev(ctx *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Next implements the Datum interface.
+func (d *DRange) Next(ctx *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// IsMax implements the Datum interface.
+func (d *DRange) IsMax(_ *EvalContext) bool {
+	return false
+}
+
+// IsMin implements the Datum interface.
+func (d *DRange) IsMin(_ *EvalContext) bool {
+	return d.Empty
+}
+
+// Max implements the Datum interface.
+func (d *DRange) Max(_ *EvalContext) (Datum, bool) {
+	return nil, false
+}
+
+// Min implements the Datum interface.
+func (d *DRange) Min(_ *EvalContext) (Datum, bool) {
+	return NewDEmptyRange(d.Typ), true
+}
+
+// AmbiguousFormat implements the Datum interface.
+func (*DRange) AmbiguousFormat() bool { return true }
+
+// Format implements the NodeFormatter interface.
+func (d *DRange) Format(ctx *FmtCtx) {
+	if ctx.HasFlags(fmtPgwireFormat) {
+		d.pgwireFormat(ctx)
+		return
+	}
+	formatTextDatum(ctx, AsStringWithFlags(d, FmtPgwireText))
+}
+
+// Size implements the Datum interface.
+func (d *DRange) Size() uintptr {
+	sz := unsafe.Sizeof(*d)
+	if d.Lower.Val != nil {
+		sz += d.Lower.Val.Size()
+	}
+	if d.Upper.Val != nil {
+		sz += d.Upper.Val.Size()
+	}
+	return sz
+}
+
+// InLocation returns d with any timestamptz bounds converted to the given
+// location, so that they are displayed in the session time zone.
+func (d *DRange) InLocation(loc *time.Location) *DRange {
+	if d.Typ.Oid() != oid.T_tstzrange || d.Empty {
+		return d
+	}
+	ret := *d
+	for _, b := range []*RangeBound{&ret.Lower, &ret.Upper} {
+		if ts, ok := b.Val.(*DTimestampTZ); ok {
+			b.Val = &DTimestampTZ{Time: ts.Time.In(loc)}
+		}
+	}
+	return &ret
+}
+
+// IsComposite implements the CompositeDatum interface.
+func (d *DRange) IsComposite() bool {
+	for _, b := range []RangeBound{d.Lower, d.Upper} {
+		if cdatum, ok := b.Val.(CompositeDatum); ok && cdatum.IsComposite() {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsRange returns true if every value of other is contained in d.
+func (d *DRange) ContainsRange(ctx *EvalContext, other *DRange) bool {
+	if other.Empty {
+		return true
+	}
+	if d.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.Lower, true, other.Lower, true) <= 0 &&
+		compareRangeBounds(ctx, d.Upper, false, other.Upper, false) >= 0
+}
+
+// ContainsElem returns true if the given value of the range's element type is
+// contained in d.
+func (d *DRange) ContainsElem(ctx *EvalContext, elem Datum) bool {
+	if d.Empty {
+		return false
+	}
+	if !d.Lower.IsInfinite() {
+		c := compareRangeValues(ctx, d.Lower.Val, elem)
+		if c > 0 || (c == 0 && !d.Lower.Inclusive) {
+			return false
+		}
+	}
+	if !d.Upper.IsInfinite() {
+		c := compareRangeValues(ctx, d.Upper.Val, elem)
+		if c < 0 || (c == 0 && !d.Upper.Inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// Overlaps returns true if d and other have a value in common.
+func (d *DRange) Overlaps(ctx *EvalContext, other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.Lower, true, other.Upper, false) <= 0 &&
+		compareRangeBounds(ctx, other.Lower, true, d.Upper, false) <= 0
+}
+
+// Before returns true if every value of d is less than every value of other.
+func (d *DRange) Before(ctx *EvalContext, other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.Upper, false, other.Lower, true) < 0
+}
+
+// After returns true if every value of d is greater than every value of other.
+func (d *DRange) After(ctx *EvalContext, other *DRange) bool {
+	return other.Before(ctx, d)
+}
+
+// OverLeft returns true if d does not extend to the right of other.
+func (d *DRange) OverLeft(ctx *EvalContext, other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.Upper, false, other.Upper, false) <= 0
+}
+
+// OverRight returns true if d does not extend to the left of other.
+func (d *DRange) OverRight(ctx *EvalContext, other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return compareRangeBounds(ctx, d.Lower, true, other.Lower, true) >= 0
+}
+
+// Adjacent returns true if d and other do not overlap, but there are no values
+// between them.
+func (d *DRange) Adjacent(ctx *EvalContext, other *DRange) bool {
+	if d.Empty || other.Empty {
+		return false
+	}
+	return rangeBoundsAdjacent(ctx, d.Upper, other.Lower) ||
+		rangeBoundsAdjacent(ctx, other.Upper, d.Lower)
+}
+
+// rangeBoundsAdjacent returns true if the upper bound of one range meets the
+// lower bound of another with no gap or overlap between them. Discrete ranges
+// are canonical, so this only needs to consider equal bound values.
+func rangeBoundsAdjacent(ctx *EvalContext, upper, lower RangeBound) bool {
+	if upper.IsInfinite() || lower.IsInfinite() {
+		return false
+	}
+	return compareRangeValues(ctx, upper.Val, lower.Val) == 0 && upper.Inclusive != lower.Inclusive
+}
+
+// Merge returns the smallest range that contains both d and other.
+func (d *DRange) Merge(ctx *EvalContext, other *DRange) (*DRange, error) {
+	if d.Empty {
+		return other, nil
+	}
+	if other.Empty {
+		return d, nil
+	}
+	lower, upper := d.Lower, d.Upper
+	if compareRangeBounds(ctx, other.Lower, true, lower, true) < 0 {
+		lower = other.Lower
+	}
+	if compareRangeBounds(ctx, other.Upper, false, upper, false) > 0 {
+		upper = other.Upper
+	}
+	return NewDRange(ctx, d.Typ, lower, upper)
+}
+
+// Union returns the range containing the values of both d and other, which
+// must overlap or be adjacent.
+func (d *DRange) Union(ctx *EvalContext, other *DRange) (*DRange, error) {
+	if !d.Empty && !other.Empty && !d.Overlaps(ctx, other) && !d.Adjacent(ctx, other) {
+		return nil, pgerror.New(pgcode.DataException,
+			"result of range union would not be contiguous")
+	}
+	return d.Merge(ctx, other)
+}
+
+// Intersect returns the range of values contained in both d and other.
+func (d *DRange) Intersect(ctx *EvalContext, other *DRange) (*DRange, error) {
+	if !d.Overlaps(ctx, other) {
+		return NewDEmptyRange(d.Typ), nil
+	}
+	lower, upper := d.Lower, d.Upper
+	if compareRangeBounds(ctx, other.Lower, true, lower, true) > 0 {
+		lower = other.Lower
+	}
+	if compareRangeBounds(ctx, other.Upper, false, upper, false) < 0 {
+		upper = other.Upper
+	}
+	return NewDRange(ctx, d.Typ, lower, upper)
+}
+
+// Difference returns the range of values contained in d but not in other. An
+// error is returned if the result would consist of two disjoint ranges.
+func (d *DRange) Difference(ctx *EvalContext, other *DRange) (*DRange, error) {
+	if d.Empty || other.Empty {
+		return d, nil
+	}
+	cmpL1L2 := compareRangeBounds(ctx, d.Lower, true, other.Lower, true)
+	cmpL1U2 := compareRangeBounds(ctx, d.Lower, true, other.Upper, false)
+	cmpU1L2 := compareRangeBounds(ctx, d.Upper, false, other.Lower, true)
+	cmpU1U2 := compareRangeBounds(ctx, d.Upper, false, other.Upper, false)
+	switch {
+	case cmpL1L2 < 0 && cmpU1U2 > 0:
+		return nil, pgerror.New(pgcode.DataException,
+			"result of range difference would not be contiguous")
+	case cmpL1U2 > 0 || cmpU1L2 < 0:
+		return d, nil
+	case cmpL1L2 >= 0 && cmpU1U2 <= 0:
+		return NewDEmptyRange(d.Typ), nil
+	case cmpL1L2 <= 0 && cmpU1L2 >= 0 && cmpU1U2 <= 0:
+		upper := RangeBound{Val: other.Lower.Val, Inclusive: !other.Lower.Inclusive}
+		return NewDRange(ctx, d.Typ, d.Lower, upper)
+	case cmpL1L2 >= 0 && cmpU1U2 >= 0 && cmpL1U2 <= 0:
+		lower := RangeBound{Val: other.Upper.Val, Inclusive: !other.Upper.Inclusive}
+		return NewDRange(ctx, d.Typ, lower, d.Upper)
+	}
+	return nil, errors.AssertionFailedf("unexpected case in range difference")
+}
@@ -695,6 +695,45 @@ func (node *GroupBy) Format(ctx *FmtCtx) {
 	}
 }
 
+// GroupingSetType represents the kind of a GroupingSet.
+type GroupingSetType int
+
+const (
+	// RollupGroupingSet represents ROLLUP (...).
+	RollupGroupingSet GroupingSetType = iota
+	// CubeGroupingSet represents CUBE (...).
+	CubeGroupingSet
+	// GroupingSetsGroupingSet represents GROUPING SETS (...).
+	GroupingSetsGroupingSet
+)
+
+var groupingSetTypeName = [...]string{
+	RollupGroupingSet:       "ROLLUP",
+	CubeGroupingSet:         "CUBE",
+	GroupingSetsGroupingSet: "GROUPING SETS",
+}
+
+func (t GroupingSetType) String() string {
+	return groupingSetTypeName[t]
+}
+
+// GroupingSet represents a ROLLUP, CUBE or GROUPING SETS item of a GROUP BY
+// clause. A Tuple element groups its expressions together; for GROUPING SETS,
+// an empty Tuple denotes the empty grouping set, and an element can itself be a
+// GroupingSet.
+type GroupingSet struct {
+	Type  GroupingSetType
+	Exprs Exprs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *GroupingSet) Format(ctx *FmtCtx) {
+	ctx.WriteString(node.Type.String())
+	ctx.WriteString(" (")
+	ctx.FormatNode(&node.Exprs)
+	ctx.WriteByte(')')
+}
+
 // DistinctOn represents a DISTINCT ON clause.
 type DistinctOn []Expr
 
@@ -88,6 +88,19 @@ func SampleDatum(t *types.T) Datum {
 		return NewDGeography(geo.MustParseGeographyFromEWKB([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\x3f")))
 	case types.GeometryFamily:
 		return NewDGeometry(geo.MustParseGeometryFromEWKB([]byte("\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\xf0\x3f\x00\x00\x00\x00\x00\x00\xf0\x3f")))
+	case types.TSQueryFamily:
+		q, _ := ParseDTSQuery(`fat & rat`)
+		return q
+	case types.TSVectorFamily:
+		v, _ := ParseDTSVector(`a:1 fat:2 rat:3`)
+		return v
+	case types.JsonpathFamily:
+		p, _ := ParseDJsonpath(`$.a[*] ? (@ > 1)`)
+		return p
+	case types.RangeFamily:
+		lower := RangeBound{Val: SampleDatum(t.RangeContents()), Inclusive: true}
+		r, _ := NewDRange(nil /* ctx */, t, lower, RangeBound{})
+		return r
 	default:
 		panic(errors.AssertionFailedf("SampleDatum not implemented for %s", t))
 	}
@@ -25,17 +25,21 @@ type IsolationLevel int
 const (
 	UnspecifiedIsolation IsolationLevel = iota
 	SerializableIsolation
+	ReadCommittedIsolation
 )
 
 var isolationLevelNames = [...]string{
-	UnspecifiedIsolation:  "UNSPECIFIED",
-	SerializableIsolation: "SERIALIZABLE",
+	UnspecifiedIsolation:   "UNSPECIFIED",
+	SerializableIsolation:  "SERIALIZABLE",
+	ReadCommittedIsolation: "READ COMMITTED",
 }
 
 // IsolationLevelMap is a map from string isolation level name to isolation
 // level, in the lowercase format that set isolation_level supports.
 var IsolationLevelMap = map[string]IsolationLevel{
-	"serializable": SerializableIsolation,
+	"read uncommitted": ReadCommittedIsolation,
+	"read committed":   ReadCommittedIsolation,
+	"serializable":     SerializableIsolation,
 }
 
 func (i IsolationLevel) String() string {
@@ -246,6 +250,39 @@ func (node *RollbackTransaction) Format(ctx *FmtCtx) {
 	ctx.WriteString("ROLLBACK TRANSACTION")
 }
 
+// PrepareTransaction represents a PREPARE TRANSACTION <id> statement.
+type PrepareTransaction struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *PrepareTransaction) Format(ctx *FmtCtx) {
+	ctx.WriteString("PREPARE TRANSACTION ")
+	ctx.FormatNode(node.Transaction)
+}
+
+// CommitPrepared represents a COMMIT PREPARED <id> statement.
+type CommitPrepared struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CommitPrepared) Format(ctx *FmtCtx) {
+	ctx.WriteString("COMMIT PREPARED ")
+	ctx.FormatNode(node.Transaction)
+}
+
+// RollbackPrepared represents a ROLLBACK PREPARED <id> statement.
+type RollbackPrepared struct {
+	Transaction *StrVal
+}
+
+// Format implements the NodeFormatter interface.
+func (node *RollbackPrepared) Format(ctx *FmtCtx) {
+	ctx.WriteString("ROLLBACK PREPARED ")
+	ctx.FormatNode(node.Transaction)
+}
+
 // Savepoint represents a SAVEPOINT <name> statement.
 type Savepoint struct {
 	Name Name
@@ -0,0 +1,283 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tree
+
+import "github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+
+// CreateFunction represents a CREATE FUNCTION or CREATE PROCEDURE statement.
+// ReturnType is nil for procedures.
+type CreateFunction struct {
+	Replace     bool
+	IsProcedure bool
+	FuncName    *UnresolvedObjectName
+	Args        FuncArgs
+	ReturnType  ResolvableTypeReference
+	Options     FunctionOptions
+}
+
+// Format implements the NodeFormatter interface.
+func (node *CreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("CREATE ")
+	if node.Replace {
+		ctx.WriteString("OR REPLACE ")
+	}
+	if node.IsProcedure {
+		ctx.WriteString("PROCEDURE ")
+	} else {
+		ctx.WriteString("FUNCTION ")
+	}
+	ctx.FormatNode(node.FuncName)
+	ctx.WriteString("(")
+	ctx.FormatNode(&node.Args)
+	ctx.WriteString(")")
+	if !node.IsProcedure {
+		ctx.WriteString(" RETURNS ")
+		ctx.FormatTypeReference(node.ReturnType)
+	}
+	for _, option := range node.Options {
+		ctx.WriteString(" ")
+		ctx.FormatNode(option)
+	}
+}
+
+// FuncArg represents an argument in a CREATE FUNCTION or DROP FUNCTION
+// statement. Name is empty for unnamed arguments.
+type FuncArg struct {
+	Name  Name
+	Type  ResolvableTypeReference
+	Class FuncArgClass
+}
+
+// FuncArgClass indicates whether an argument is an input argument, an output
+// argument, or both.
+type FuncArgClass int
+
+const (
+	// FuncArgIn is an input argument. This is the default.
+	FuncArgIn FuncArgClass = iota
+	// FuncArgOut is an output argument.
+	FuncArgOut
+	// FuncArgInOut is both an input and an output argument.
+	FuncArgInOut
+)
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArg) Format(ctx *FmtCtx) {
+	switch node.Class {
+	case FuncArgOut:
+		ctx.WriteString("OUT ")
+	case FuncArgInOut:
+		ctx.WriteString("INOUT ")
+	}
+	if node.Name != "" {
+		ctx.FormatNode(&node.Name)
+		ctx.WriteString(" ")
+	}
+	ctx.FormatTypeReference(node.Type)
+}
+
+// FuncArgs represents a list of function arguments.
+type FuncArgs []FuncArg
+
+// Format implements the NodeFormatter interface.
+func (node *FuncArgs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// FunctionOption is an interface representing the options of a CREATE
+// FUNCTION statement.
+type FunctionOption interface {
+	NodeFormatter
+	functionOption()
+}
+
+func (FunctionNullInputBehavior) functionOption() {}
+func (FunctionVolatility) functionOption()        {}
+func (FunctionLeakproof) functionOption()         {}
+func (FunctionLanguage) functionOption()          {}
+func (FunctionBodyStr) functionOption()           {}
+
+// FunctionOptions represents a list of function options.
+type FunctionOptions []FunctionOption
+
+// FunctionNullInputBehavior represents the behavior of a function when it is
+// called with NULL arguments.
+type FunctionNullInputBehavior int
+
+const (
+	// FunctionCalledOnNullInput indicates that the function is called normally
+	// when some of its arguments are NULL. This is the default.
+	FunctionCalledOnNullInput FunctionNullInputBehavior = iota
+	// FunctionReturnsNullOnNullInput indicates that the function returns NULL
+	// without being evaluated whenever any of its arguments is NULL.
+	FunctionReturnsNullOnNullInput
+	// FunctionStrict is the same as FunctionReturnsNullOnNullInput.
+	FunctionStrict
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionNullInputBehavior) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionCalledOnNullInput:
+		ctx.WriteString("CALLED ON NULL INPUT")
+	case FunctionReturnsNullOnNullInput:
+		ctx.WriteString("RETURNS NULL ON NULL INPUT")
+	case FunctionStrict:
+		ctx.WriteString("STRICT")
+	}
+}
+
+// FunctionVolatility represents the volatility marking of a function.
+type FunctionVolatility int
+
+const (
+	// FunctionVolatile indicates that the function may have side effects and
+	// that its result can change even within a single table scan. This is the
+	// default.
+	FunctionVolatile FunctionVolatility = iota
+	// FunctionImmutable indicates that the function cannot modify the database
+	// and always returns the same result given the same arguments.
+	FunctionImmutable
+	// FunctionStable indicates that the function cannot modify the database
+	// and returns the same result given the same arguments within a single
+	// statement.
+	FunctionStable
+)
+
+// Format implements the NodeFormatter interface.
+func (node FunctionVolatility) Format(ctx *FmtCtx) {
+	switch node {
+	case FunctionVolatile:
+		ctx.WriteString("VOLATILE")
+	case FunctionImmutable:
+		ctx.WriteString("IMMUTABLE")
+	case FunctionStable:
+		ctx.WriteString("STABLE")
+	}
+}
+
+// FunctionLeakproof indicates whether a function is leakproof, i.e. whether
+// it reveals no information about its arguments other than through its
+// return value.
+type FunctionLeakproof bool
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLeakproof) Format(ctx *FmtCtx) {
+	if !node {
+		ctx.WriteString("NOT ")
+	}
+	ctx.WriteString("LEAKPROOF")
+}
+
+// FunctionLanguage is the language the function body is written in.
+type FunctionLanguage string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionLanguage) Format(ctx *FmtCtx) {
+	ctx.WriteString("LANGUAGE ")
+	ctx.FormatNameP((*string)(&node))
+}
+
+// FunctionBodyStr is the body of a function, as given in the AS clause.
+type FunctionBodyStr string
+
+// Format implements the NodeFormatter interface.
+func (node FunctionBodyStr) Format(ctx *FmtCtx) {
+	ctx.WriteString("AS ")
+	if ctx.flags.HasFlags(FmtHideConstants) {
+		ctx.WriteString("'_'")
+	} else {
+		lexbase.EncodeSQLStringWithFlags(&ctx.Buffer, string(node), ctx.flags.EncodeFlags())
+	}
+}
+
+// DropFunction represents a DROP FUNCTION or DROP PROCEDURE statement.
+type DropFunction struct {
+	Functions    FuncObjs
+	IfExists     bool
+	IsProcedure  bool
+	DropBehavior DropBehavior
+}
+
+// Format implements the NodeFormatter interface.
+func (node *DropFunction) Format(ctx *FmtCtx) {
+	if node.IsProcedure {
+		ctx.WriteString("DROP PROCEDURE ")
+	} else {
+		ctx.WriteString("DROP FUNCTION ")
+	}
+	if node.IfExists {
+		ctx.WriteString("IF EXISTS ")
+	}
+	ctx.FormatNode(&node.Functions)
+	if node.DropBehavior != DropDefault {
+		ctx.WriteString(" ")
+		ctx.WriteString(node.DropBehavior.String())
+	}
+}
+
+// FuncObj represents a function, optionally with its argument types, as
+// referenced by a DROP FUNCTION statement. Args is nil if no argument list
+// was given.
+type FuncObj struct {
+	FuncName *UnresolvedObjectName
+	Args     FuncArgs
+}
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObj) Format(ctx *FmtCtx) {
+	ctx.FormatNode(node.FuncName)
+	if node.Args != nil {
+		ctx.WriteString("(")
+		ctx.FormatNode(&node.Args)
+		ctx.WriteString(")")
+	}
+}
+
+// FuncObjs is a slice of FuncObj.
+type FuncObjs []FuncObj
+
+// Format implements the NodeFormatter interface.
+func (node *FuncObjs) Format(ctx *FmtCtx) {
+	for i := range *node {
+		if i > 0 {
+			ctx.WriteString(", ")
+		}
+		ctx.FormatNode(&(*node)[i])
+	}
+}
+
+// ShowCreateFunction represents a SHOW CREATE FUNCTION statement.
+type ShowCreateFunction struct {
+	Name *UnresolvedObjectName
+}
+
+// Format implements the NodeFormatter interface.
+func (node *ShowCreateFunction) Format(ctx *FmtCtx) {
+	ctx.WriteString("SHOW CREATE FUNCTION ")
+	ctx.FormatNode(node.Name)
+}
+
+// Call represents a CALL statement, which invokes a procedure.
+type Call struct {
+	Proc *FuncExpr
+}
+
+// Format implements the NodeFormatter interface.
+func (node *Call) Format(ctx *FmtCtx) {
+	ctx.WriteString("CALL ")
+	ctx.FormatNode(node.Proc)
+}
@@ -648,6 +648,16 @@ func walkKVOptions(v Visitor, opts KVOptions) (KVOptions, bool) {
 	return opts, copied
 }
 
+// Walk implements the Expr interface.
+func (expr *GroupingSet) Walk(v Visitor) Expr {
+	if exprs, changed := walkExprSlice(v, expr.Exprs); changed {
+		exprCopy := *expr
+		exprCopy.Exprs = exprs
+		return &exprCopy
+	}
+	return expr
+}
+
 // Walk implements the Expr interface.
 func (expr *Tuple) Walk(v Visitor) Expr {
 	exprs, changed := walkExprSlice(v, expr.Exprs)
@@ -758,6 +768,18 @@ func (expr *DGeometry) Walk(_ Visitor) Expr { return expr }
 // Walk implements the Expr interface.
 func (expr *DJSON) Walk(_ Visitor) Expr { return expr }
 
+// Walk implements the Expr interface.
+func (expr *DTSVector) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DTSQuery) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DJsonpath) Walk(_ Visitor) Expr { return expr }
+
+// Walk implements the Expr interface.
+func (expr *DRange) Walk(_ Visitor) Expr { return expr }
+
 // Walk implements the Expr interface.
 func (expr *DUuid) Walk(_ Visitor) Expr { return expr }
 
@@ -990,6 +1012,71 @@ func (stmt *Insert) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement without recursing in any child Statements.
+func (stmt *Merge) copyNode() *Merge {
+	stmtCopy := *stmt
+	stmtCopy.Whens = make(MergeWhens, len(stmt.Whens))
+	for i, w := range stmt.Whens {
+		wCopy := *w
+		switch t := w.Action.(type) {
+		case *MergeUpdate:
+			exprs := make(UpdateExprs, len(t.Exprs))
+			for j, e := range t.Exprs {
+				eCopy := *e
+				exprs[j] = &eCopy
+			}
+			wCopy.Action = &MergeUpdate{Exprs: exprs}
+		case *MergeInsert:
+			insCopy := *t
+			insCopy.Exprs = append(Exprs(nil), t.Exprs...)
+			wCopy.Action = &insCopy
+		}
+		stmtCopy.Whens[i] = &wCopy
+	}
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Merge) walkStmt(v Visitor) Statement {
+	ret := stmt
+	if e, changed := WalkExpr(v, stmt.On); changed {
+		ret = stmt.copyNode()
+		ret.On = e
+	}
+	for i, w := range stmt.Whens {
+		if w.Cond != nil {
+			e, changed := WalkExpr(v, w.Cond)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Cond = e
+			}
+		}
+		switch t := w.Action.(type) {
+		case *MergeUpdate:
+			for j, expr := range t.Exprs {
+				e, changed := WalkExpr(v, expr.Expr)
+				if changed {
+					if ret == stmt {
+						ret = stmt.copyNode()
+					}
+					ret.Whens[i].Action.(*MergeUpdate).Exprs[j].Expr = e
+				}
+			}
+		case *MergeInsert:
+			exprs, changed := walkExprSlice(v, t.Exprs)
+			if changed {
+				if ret == stmt {
+					ret = stmt.copyNode()
+				}
+				ret.Whens[i].Action.(*MergeInsert).Exprs = exprs
+			}
+		}
+	}
+	return ret
+}
+
 // copyNode makes a copy of this Statement without recursing in any child Statements.
 func (stmt *CreateTable) copyNode() *CreateTable {
 	stmtCopy := *stmt
@@ -1554,11 +1641,32 @@ func (stmt *BeginTransaction) walkStmt(v Visitor) Statement {
 	return ret
 }
 
+// copyNode makes a copy of this Statement.
+func (stmt *Call) copyNode() *Call {
+	stmtCopy := *stmt
+	return &stmtCopy
+}
+
+// walkStmt is part of the walkableStmt interface.
+func (stmt *Call) walkStmt(v Visitor) Statement {
+	ret := stmt
+	e, changed := WalkExpr(v, stmt.Proc)
+	if changed {
+		if f, ok := e.(*FuncExpr); ok {
+			ret = stmt.copyNode()
+			ret.Proc = f
+		}
+	}
+	return ret
+}
+
 var _ walkableStmt = &CreateTable{}
+var _ walkableStmt = &Call{}
 var _ walkableStmt = &Backup{}
 var _ walkableStmt = &Delete{}
 var _ walkableStmt = &Explain{}
 var _ walkableStmt = &Insert{}
+var _ walkableStmt = &Merge{}
 var _ walkableStmt = &Import{}
 var _ walkableStmt = &ParenSelect{}
 var _ walkableStmt = &Restore{}
@@ -13,12 +13,15 @@ package sql
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/catformat"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/tabledesc"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
 	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
@@ -79,11 +82,19 @@ func ShowCreateTable(
 	if desc.IsTemporary() {
 		f.WriteString("TEMP ")
 	}
+	if desc.IsForeignTable() {
+		f.WriteString("FOREIGN ")
+	}
 	f.WriteString("TABLE ")
 	f.FormatNode(tn)
 	f.WriteString(" (")
 	// Inaccessible columns are not displayed in SHOW CREATE TABLE.
-	for i, col := range desc.AccessibleColumns() {
+	cols := desc.AccessibleColumns()
+	if desc.IsForeignTable() {
+		// The hidden rowid column of foreign tables is implicit.
+		cols = desc.VisibleColumns()
+	}
+	for i, col := range cols {
 		if i != 0 {
 			f.WriteString(",")
 		}
@@ -97,6 +108,19 @@ func ShowCreateTable(
 		f.WriteString(colstr)
 	}
 
+	if desc.IsForeignTable() {
+		// Foreign tables have no indexes nor constraints, and their primary key
+		// is always the hidden rowid column.
+		f.WriteString("\n)")
+		showForeignTableClause(desc, f)
+		if !displayOptions.IgnoreComments {
+			if err := showComments(tn, desc, selectComment(ctx, p, desc.GetID()), &f.Buffer); err != nil {
+				return "", err
+			}
+		}
+		return f.CloseAndGetString(), nil
+	}
+
 	if desc.IsPhysicalTable() {
 		f.WriteString(",\n\tCONSTRAINT ")
 		formatQuoteNames(&f.Buffer, desc.GetPrimaryIndex().GetName())
@@ -174,6 +198,12 @@ func ShowCreateTable(
 		return "", err
 	}
 
+	if ttl := desc.GetRowLevelTTL(); ttl != nil {
+		f.WriteString(" WITH (")
+		f.WriteString(strings.Join(showRowLevelTTLStorageParams(ttl), ", "))
+		f.WriteString(")")
+	}
+
 	if err := showCreateLocality(desc, f); err != nil {
 		return "", err
 	}
@@ -187,6 +217,34 @@ func ShowCreateTable(
 	return f.CloseAndGetString(), nil
 }
 
+// showRowLevelTTLStorageParams returns the storage parameters which
+// reproduce the given row-level TTL configuration.
+func showRowLevelTTLStorageParams(ttl *descpb.TableDescriptor_RowLevelTTL) []string {
+	params := []string{
+		`ttl = 'on'`,
+		fmt.Sprintf(`ttl_expire_after = %s`, ttl.DurationExpr),
+	}
+	if cron := ttl.DeletionCron; cron != "" {
+		params = append(params, fmt.Sprintf(`ttl_job_cron = %s`, lexbase.EscapeSQLString(cron)))
+	}
+	if bs := ttl.SelectBatchSize; bs != 0 {
+		params = append(params, fmt.Sprintf(`ttl_select_batch_size = %d`, bs))
+	}
+	if bs := ttl.DeleteBatchSize; bs != 0 {
+		params = append(params, fmt.Sprintf(`ttl_delete_batch_size = %d`, bs))
+	}
+	if rc := ttl.RangeConcurrency; rc != 0 {
+		params = append(params, fmt.Sprintf(`ttl_range_concurrency = %d`, rc))
+	}
+	if rl := ttl.DeleteRateLimit; rl != 0 {
+		params = append(params, fmt.Sprintf(`ttl_delete_rate_limit = %d`, rl))
+	}
+	if ttl.Pause {
+		params = append(params, `ttl_pause = true`)
+	}
+	return params
+}
+
 // formatQuoteNames quotes and adds commas between names.
 func formatQuoteNames(buf *bytes.Buffer, names ...string) {
 	f := tree.NewFmtCtx(tree.FmtSimple)
@@ -14,6 +14,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
@@ -21,6 +22,7 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/multiregion"
 	"github.com/cockroachdb/cockroach/pkg/sql/catalog/schemaexpr"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
 	"github.com/cockroachdb/cockroach/pkg/sql/parser"
 	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
 	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
@@ -325,6 +327,10 @@ func showForeignKeyConstraint(
 		buf.WriteString(" ON UPDATE ")
 		buf.WriteString(fk.OnUpdate.String())
 	}
+	if d := fk.Deferrability(); d != tree.NotDeferrableConstraint {
+		buf.WriteByte(' ')
+		buf.WriteString(d.String())
+	}
 	if fk.Validity != descpb.ConstraintValidity_Validated {
 		buf.WriteString(" NOT VALID")
 	}
@@ -382,6 +388,32 @@ func showFamilyClause(desc catalog.TableDescriptor, f *tree.FmtCtx) {
 	}
 }
 
+// showForeignTableClause creates the SERVER and OPTIONS clauses of a CREATE
+// FOREIGN TABLE statement, writing them to tree.FmtCtx f.
+func showForeignTableClause(desc catalog.TableDescriptor, f *tree.FmtCtx) {
+	foreign := desc.GetForeignTable()
+	f.WriteString(" SERVER ")
+	f.FormatNameP(&foreign.Server)
+	if len(foreign.Options) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(foreign.Options))
+	for k := range foreign.Options {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	f.WriteString(" OPTIONS (")
+	for i, k := range keys {
+		if i > 0 {
+			f.WriteString(", ")
+		}
+		f.FormatNameP(&keys[i])
+		f.WriteByte(' ')
+		lexbase.EncodeSQLString(&f.Buffer, foreign.Options[k])
+	}
+	f.WriteString(")")
+}
+
 // showCreateLocality creates the LOCALITY clauses for a CREATE statement, writing them
 // to tree.FmtCtx f.
 func showCreateLocality(desc catalog.TableDescriptor, f *tree.FmtCtx) error {
@@ -562,6 +594,10 @@ func showConstraintClause(
 		}
 		f.WriteString(strings.Join(colNames, ", "))
 		f.WriteString(")")
+		if d := c.Deferrability(); d != tree.NotDeferrableConstraint {
+			f.WriteString(" ")
+			f.WriteString(d.String())
+		}
 		if c.IsPartial() {
 			f.WriteString(" WHERE ")
 			pred, err := schemaexpr.FormatExprForDisplay(ctx, desc, c.Predicate, semaCtx, sessionData, tree.FmtParsable)
@@ -0,0 +1,646 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"context"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondatapb"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/mon"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/cockroachdb/logtags"
+)
+
+// DeclareCursor implements the DECLARE statement.
+// See https://www.postgresql.org/docs/current/sql-declare.html for details.
+func (p *planner) DeclareCursor(ctx context.Context, s *tree.DeclareCursor) (planNode, error) {
+	if s.Binary {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE BINARY CURSOR")
+	}
+	if s.Scroll == tree.Scroll {
+		return nil, unimplemented.NewWithIssue(41412, "DECLARE SCROLL CURSOR")
+	}
+	if hasDataModifyingCTE(s.Select) {
+		return nil, pgerror.Newf(pgcode.FeatureNotSupported,
+			"DECLARE CURSOR must not contain data-modifying statements in WITH")
+	}
+
+	return &delayedNode{
+		name: s.String(),
+		constructor: func(ctx context.Context, p *planner) (_ planNode, retErr error) {
+			if p.extendedEvalCtx.TxnImplicit && !s.Hold {
+				return nil, pgerror.Newf(pgcode.NoActiveSQLTransaction,
+					"DECLARE CURSOR can only be used in transaction blocks")
+			}
+			name := string(s.Name)
+			if cursor := p.sqlCursors.getCursor(name); cursor != nil {
+				return nil, pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+			}
+
+			// Substitute placeholders with their values, since the query is
+			// re-parsed and run by an internal executor.
+			fmtCtx := p.EvalContext().FmtCtx(
+				tree.FmtParsable,
+				tree.FmtPlaceholderFormat(func(ctx *tree.FmtCtx, placeholder *tree.Placeholder) {
+					d, err := placeholder.Eval(p.EvalContext())
+					if err != nil {
+						panic(errors.AssertionFailedf("failed to serialize placeholder: %s", err))
+					}
+					d.Format(ctx)
+				}),
+			)
+			fmtCtx.FormatNode(s.Select)
+			statement := fmtCtx.CloseAndGetString()
+
+			// The cursor's query is run by an internal executor in the session's
+			// transaction, and is pulled from one row at a time by FETCH and MOVE,
+			// possibly interleaved with other statements. It therefore can't be
+			// distributed, and it must not be subject to the session's statement
+			// timeout, since it outlives the DECLARE statement.
+			sd := p.SessionData().Clone()
+			sd.DistSQLMode = sessiondatapb.DistSQLOff
+			sd.StmtTimeout = 0
+			ie := MakeInternalExecutor(ctx, p.execCfg.InternalExecutor.s, MemoryMetrics{}, p.execCfg.Settings)
+			ie.SetSessionData(sd)
+
+			// The query's context must outlive the DECLARE statement, so it is
+			// detached from the statement's cancellation and tracing span.
+			queryCtx := logtags.AddTags(context.Background(), logtags.FromContext(ctx))
+			rows, err := ie.execInternal(
+				queryCtx, "sql-cursor", newSyncIEResultChannel(), p.txn,
+				sessiondata.InternalExecutorOverride{}, statement,
+			)
+			if err != nil {
+				return nil, err
+			}
+			// Errors encountered by the query are reported as they are, rather than
+			// prefixed with the name of the internal operation.
+			rows.errCallback = nil
+			defer func() {
+				if retErr != nil {
+					_ = rows.Close()
+				}
+			}()
+			// A query that fails during planning doesn't produce any result columns;
+			// its error is returned by the first call to Next.
+			if rows.Types() == nil {
+				if _, err := rows.Next(ctx); err != nil {
+					return nil, err
+				}
+				return nil, errors.AssertionFailedf("cursor query produced no result columns")
+			}
+
+			cursor := &sqlCursor{
+				rows:       rows,
+				cols:       rows.Types(),
+				txn:        p.txn,
+				readSeqNum: p.txn.GetReadSeqNum(),
+				statement:  tree.AsString(s),
+				created:    timeutil.Now(),
+				withHold:   s.Hold,
+			}
+			if err := p.sqlCursors.addCursor(name, cursor); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// hasDataModifyingCTE returns true if the given SELECT statement contains a
+// data-modifying statement in a top-level WITH clause. Data-modifying
+// statements are not permitted in nested WITH clauses, so these are the only
+// ones that need to be checked.
+func hasDataModifyingCTE(s *tree.Select) bool {
+	for s.With == nil {
+		paren, ok := s.Select.(*tree.ParenSelect)
+		if !ok {
+			return false
+		}
+		s = paren.Select
+	}
+	for _, cte := range s.With.CTEList {
+		if tree.CanWriteData(cte.Stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchCursor implements the FETCH statement.
+// See https://www.postgresql.org/docs/current/sql-fetch.html for details.
+func (p *planner) FetchCursor(_ context.Context, s *tree.FetchCursor) (planNode, error) {
+	cursor, err := p.getCursorForFetch(&s.CursorStmt)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchNode{
+		cursor:    cursor,
+		fetchType: s.FetchType,
+		count:     s.Count,
+		columns:   cursor.cols,
+	}, nil
+}
+
+// MoveCursor implements the MOVE statement.
+// See https://www.postgresql.org/docs/current/sql-move.html for details.
+func (p *planner) MoveCursor(_ context.Context, s *tree.MoveCursor) (planNode, error) {
+	cursor, err := p.getCursorForFetch(&s.CursorStmt)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchNode{
+		cursor:    cursor,
+		fetchType: s.FetchType,
+		count:     s.Count,
+	}, nil
+}
+
+// getCursorForFetch looks up the cursor targeted by a FETCH or MOVE statement,
+// and checks that the statement doesn't need to scan the cursor backwards.
+func (p *planner) getCursorForFetch(s *tree.CursorStmt) (*sqlCursor, error) {
+	cursor := p.sqlCursors.getCursor(string(s.Name))
+	if cursor == nil {
+		return nil, pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", s.Name)
+	}
+	switch s.FetchType {
+	case tree.FetchBackwardAll:
+		return nil, errBackwardScan()
+	case tree.FetchNormal, tree.FetchRelative:
+		if s.Count < 0 {
+			return nil, errBackwardScan()
+		}
+	case tree.FetchAbsolute:
+		// ABSOLUTE -1 refers to the last row; other negative positions would
+		// require buffering the end of the result set.
+		if s.Count < -1 {
+			return nil, errBackwardScan()
+		}
+	}
+	return cursor, nil
+}
+
+func errBackwardScan() error {
+	return errors.WithHint(
+		pgerror.New(pgcode.ObjectNotInPrerequisiteState, "cursor can only scan forward"),
+		"Declare it with SCROLL option to enable backward scan.",
+	)
+}
+
+// CloseCursor implements the CLOSE statement.
+// See https://www.postgresql.org/docs/current/sql-close.html for details.
+func (p *planner) CloseCursor(ctx context.Context, s *tree.CloseCursor) (planNode, error) {
+	return &delayedNode{
+		name: s.String(),
+		constructor: func(ctx context.Context, p *planner) (planNode, error) {
+			if s.All {
+				p.sqlCursors.closeAll(ctx)
+			} else if err := p.sqlCursors.closeCursor(ctx, string(s.Name)); err != nil {
+				return nil, err
+			}
+			return newZeroNode(nil /* columns */), nil
+		},
+	}, nil
+}
+
+// fetchNode implements the FETCH and MOVE statements. For MOVE, the rows are
+// skipped over without being returned, and only counted.
+type fetchNode struct {
+	cursor    *sqlCursor
+	fetchType tree.FetchType
+	count     int64
+	// columns is nil for MOVE.
+	columns colinfo.ResultColumns
+
+	// n is the number of rows returned so far, for fetch types that may return
+	// more than one row.
+	n    int64
+	done bool
+
+	// origReadSeqNum is the read sequence number of the transaction before the
+	// statement started, restored once the statement completes.
+	origReadSeqNum enginepb.TxnSeq
+	pinnedSeqNum   bool
+}
+
+func (f *fetchNode) startExec(params runParams) error {
+	// A cursor that is still backed by its query reads at the sequence number
+	// at which it was declared, so that it doesn't observe writes performed by
+	// the transaction since.
+	if txn := f.cursor.txn; txn != nil && !f.cursor.persisted {
+		f.origReadSeqNum = txn.GetReadSeqNum()
+		if err := txn.SetReadSeqNum(f.cursor.readSeqNum); err != nil {
+			return err
+		}
+		f.pinnedSeqNum = true
+	}
+	return nil
+}
+
+func (f *fetchNode) Next(params runParams) (bool, error) {
+	if f.done {
+		return false, nil
+	}
+	c := f.cursor
+	switch f.fetchType {
+	case tree.FetchAll:
+		return c.next(params.ctx)
+	case tree.FetchNormal:
+		if f.count == 0 {
+			// FETCH 0 returns the current row, like FETCH RELATIVE 0.
+			break
+		}
+		if f.n >= f.count {
+			f.done = true
+			return false, nil
+		}
+		f.n++
+		return c.next(params.ctx)
+	}
+
+	// The remaining fetch types return at most one row.
+	f.done = true
+	switch f.fetchType {
+	case tree.FetchFirst:
+		return c.seek(params.ctx, 1)
+	case tree.FetchLast:
+		return c.seekLast(params.ctx)
+	case tree.FetchAbsolute:
+		if f.count == -1 {
+			return c.seekLast(params.ctx)
+		}
+		return c.seek(params.ctx, f.count)
+	case tree.FetchNormal, tree.FetchRelative:
+		return c.seek(params.ctx, c.pos+f.count)
+	}
+	return false, errors.AssertionFailedf("unhandled fetch type %d", f.fetchType)
+}
+
+func (f *fetchNode) Values() tree.Datums {
+	if f.columns == nil {
+		// MOVE doesn't return any rows.
+		return nil
+	}
+	return f.cursor.cur
+}
+
+func (f *fetchNode) Close(ctx context.Context) {
+	if f.pinnedSeqNum {
+		if err := f.cursor.txn.SetReadSeqNum(f.origReadSeqNum); err != nil {
+			log.Warningf(ctx, "failed to restore read sequence number: %v", err)
+		}
+		f.pinnedSeqNum = false
+	}
+}
+
+// sqlCursor is a named cursor declared with DECLARE. Its rows are produced by
+// an internal executor running the cursor's query in the transaction that
+// declared it, and are pulled incrementally by FETCH and MOVE. The remaining
+// rows of a WITH HOLD cursor are materialized in memory when that transaction
+// commits, so that the cursor can be used by subsequent transactions.
+type sqlCursor struct {
+	rows sqlutil.InternalRows
+	cols colinfo.ResultColumns
+
+	// txn is the transaction in which the cursor was declared. It is cleared
+	// once that transaction commits.
+	txn *kv.Txn
+	// readSeqNum is the read sequence number of txn at the time the cursor was
+	// declared.
+	readSeqNum enginepb.TxnSeq
+	// savepointDepth is the number of savepoints of txn that were active when
+	// the cursor was declared. Rolling back to any savepoint created before
+	// the cursor was declared closes it.
+	savepointDepth int
+
+	statement string
+	created   time.Time
+	withHold  bool
+	// persisted is set once the remaining rows of the cursor have been
+	// materialized, in which case rows is a *persistedRows.
+	persisted bool
+
+	// pos is the position of the cursor: 0 before the first row, i when on the
+	// i-th row, and one past the number of rows once the cursor moved past the
+	// last row.
+	pos int64
+	// eof is set once the cursor's rows are exhausted, at which point numRows
+	// is the total number of rows.
+	eof     bool
+	numRows int64
+	// cur is the row that the cursor is on, if any.
+	cur tree.Datums
+}
+
+// next moves the cursor forward by one row, and returns whether it is now on
+// a row.
+func (c *sqlCursor) next(ctx context.Context) (bool, error) {
+	if c.eof {
+		c.pos = c.numRows + 1
+		return false, nil
+	}
+	ok, err := c.rows.Next(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		c.eof = true
+		c.numRows = c.pos
+		c.pos++
+		return false, nil
+	}
+	c.pos++
+	c.cur = c.rows.Cur()
+	return true, nil
+}
+
+// onRow returns whether the cursor is positioned on a row.
+func (c *sqlCursor) onRow() bool {
+	return c.pos > 0 && !(c.eof && c.pos > c.numRows)
+}
+
+// seek moves the cursor forward to the given position, and returns whether it
+// is now on a row.
+func (c *sqlCursor) seek(ctx context.Context, pos int64) (bool, error) {
+	if pos < c.pos {
+		return false, errBackwardScan()
+	}
+	for c.pos < pos {
+		if ok, err := c.next(ctx); !ok || err != nil {
+			return false, err
+		}
+	}
+	return c.onRow(), nil
+}
+
+// seekLast moves the cursor to the last row, and returns whether there was
+// one.
+func (c *sqlCursor) seekLast(ctx context.Context) (bool, error) {
+	for {
+		ok, err := c.next(ctx)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			break
+		}
+	}
+	if c.numRows == 0 {
+		return false, nil
+	}
+	c.pos = c.numRows
+	return true, nil
+}
+
+// persist materializes the remaining rows of the cursor, so that it no longer
+// depends on the transaction in which it was declared.
+func (c *sqlCursor) persist(ctx context.Context, m *mon.BytesMonitor) (retErr error) {
+	origReadSeqNum := c.txn.GetReadSeqNum()
+	if err := c.txn.SetReadSeqNum(c.readSeqNum); err != nil {
+		return err
+	}
+	defer func() {
+		if err := c.txn.SetReadSeqNum(origReadSeqNum); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+
+	persisted := &persistedRows{acc: m.MakeBoundAccount()}
+	for {
+		ok, err := c.rows.Next(ctx)
+		if err != nil {
+			persisted.acc.Close(ctx)
+			return err
+		}
+		if !ok {
+			break
+		}
+		row := c.rows.Cur()
+		var size int64
+		for i := range row {
+			size += int64(row[i].Size())
+		}
+		if err := persisted.acc.Grow(ctx, size); err != nil {
+			persisted.acc.Close(ctx)
+			return err
+		}
+		persisted.rows = append(persisted.rows, row)
+	}
+	if err := c.rows.Close(); err != nil {
+		persisted.acc.Close(ctx)
+		return err
+	}
+	persisted.cols = c.cols
+	c.rows = persisted
+	c.persisted = true
+	return nil
+}
+
+func (c *sqlCursor) close(ctx context.Context) {
+	_ = c.rows.Close()
+	if persisted, ok := c.rows.(*persistedRows); ok {
+		persisted.acc.Close(ctx)
+	}
+}
+
+// persistedRows is an implementation of sqlutil.InternalRows over the
+// materialized rows of a WITH HOLD cursor.
+type persistedRows struct {
+	rows []tree.Datums
+	cols colinfo.ResultColumns
+	cur  tree.Datums
+	// acc accounts for the memory used by rows. It is closed along with the
+	// cursor.
+	acc mon.BoundAccount
+}
+
+var _ sqlutil.InternalRows = &persistedRows{}
+
+// Next is part of the sqlutil.InternalRows interface.
+func (r *persistedRows) Next(context.Context) (bool, error) {
+	if len(r.rows) == 0 {
+		return false, nil
+	}
+	r.cur, r.rows = r.rows[0], r.rows[1:]
+	return true, nil
+}
+
+// Cur is part of the sqlutil.InternalRows interface.
+func (r *persistedRows) Cur() tree.Datums {
+	return r.cur
+}
+
+// Close is part of the sqlutil.InternalRows interface.
+func (r *persistedRows) Close() error {
+	r.rows = nil
+	return nil
+}
+
+// Types is part of the sqlutil.InternalRows interface.
+func (r *persistedRows) Types() colinfo.ResultColumns {
+	return r.cols
+}
+
+// sqlCursors contains a set of active cursors for a session.
+type sqlCursors interface {
+	// getCursor returns the cursor with the given name, or nil if none exists.
+	getCursor(name string) *sqlCursor
+	// addCursor adds a new cursor with the given name to the set.
+	addCursor(name string, c *sqlCursor) error
+	// closeCursor closes the cursor with the given name.
+	closeCursor(ctx context.Context, name string) error
+	// closeAll closes all cursors in the set.
+	closeAll(ctx context.Context)
+	// list returns all open cursors in the set.
+	list() map[string]*sqlCursor
+}
+
+// cursorMap is a sqlCursors that holds the cursors of a connExecutor.
+type cursorMap struct {
+	cursors map[string]*sqlCursor
+	// savepoints is the savepoint stack of the connExecutor, which is used to
+	// record the savepoint depth of new cursors.
+	savepoints *savepointStack
+}
+
+var _ sqlCursors = &cursorMap{}
+
+func (c *cursorMap) getCursor(name string) *sqlCursor {
+	return c.cursors[name]
+}
+
+func (c *cursorMap) addCursor(name string, cursor *sqlCursor) error {
+	if c.cursors == nil {
+		c.cursors = make(map[string]*sqlCursor)
+	}
+	if _, ok := c.cursors[name]; ok {
+		return pgerror.Newf(pgcode.DuplicateCursor, "cursor %q already exists", name)
+	}
+	if c.savepoints != nil {
+		cursor.savepointDepth = len(*c.savepoints)
+	}
+	c.cursors[name] = cursor
+	return nil
+}
+
+func (c *cursorMap) closeCursor(ctx context.Context, name string) error {
+	cursor, ok := c.cursors[name]
+	if !ok {
+		return pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+	}
+	cursor.close(ctx)
+	delete(c.cursors, name)
+	return nil
+}
+
+func (c *cursorMap) closeAll(ctx context.Context) {
+	for name, cursor := range c.cursors {
+		cursor.close(ctx)
+		delete(c.cursors, name)
+	}
+}
+
+func (c *cursorMap) list() map[string]*sqlCursor {
+	return c.cursors
+}
+
+// prepareForCommit is called before the transaction that the cursors were
+// declared in commits. WITH HOLD cursors are materialized so that they outlive
+// the transaction, and all other cursors are closed.
+func (c *cursorMap) prepareForCommit(ctx context.Context, m *mon.BytesMonitor) error {
+	for name, cursor := range c.cursors {
+		if cursor.txn == nil || cursor.persisted {
+			continue
+		}
+		if !cursor.withHold {
+			cursor.close(ctx)
+			delete(c.cursors, name)
+			continue
+		}
+		if err := cursor.persist(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// onTxnFinish is called when the transaction that the cursors were declared
+// in finishes. If the transaction committed, WITH HOLD cursors are detached
+// from it; all other cursors declared in the transaction are closed.
+func (c *cursorMap) onTxnFinish(ctx context.Context, committed bool) {
+	for name, cursor := range c.cursors {
+		if cursor.txn == nil {
+			continue
+		}
+		if committed && cursor.persisted {
+			cursor.txn = nil
+			continue
+		}
+		cursor.close(ctx)
+		delete(c.cursors, name)
+	}
+}
+
+// onRollbackToSavepoint is called when the transaction rolls back to the
+// savepoint at the given index of the savepoint stack. The cursors declared
+// after that savepoint was created are closed.
+func (c *cursorMap) onRollbackToSavepoint(ctx context.Context, idx int) {
+	for name, cursor := range c.cursors {
+		if cursor.txn == nil || cursor.savepointDepth <= idx {
+			continue
+		}
+		cursor.close(ctx)
+		delete(c.cursors, name)
+	}
+}
+
+// onReleaseSavepoint is called when the savepoint at the given index of the
+// savepoint stack is released. The cursors declared after that savepoint was
+// created now belong to the enclosing savepoint, if any.
+func (c *cursorMap) onReleaseSavepoint(idx int) {
+	for _, cursor := range c.cursors {
+		if cursor.txn != nil && cursor.savepointDepth > idx {
+			cursor.savepointDepth = idx
+		}
+	}
+}
+
+// emptySQLCursors is the sqlCursors used by planners that aren't associated
+// with a session.
+type emptySQLCursors struct{}
+
+var _ sqlCursors = emptySQLCursors{}
+
+func (e emptySQLCursors) getCursor(string) *sqlCursor { return nil }
+
+func (e emptySQLCursors) addCursor(string, *sqlCursor) error {
+	return errors.AssertionFailedf("cannot add cursor to emptySQLCursors")
+}
+
+func (e emptySQLCursors) closeCursor(_ context.Context, name string) error {
+	return pgerror.Newf(pgcode.InvalidCursorName, "cursor %q does not exist", name)
+}
+
+func (e emptySQLCursors) closeAll(context.Context) {}
+
+func (e emptySQLCursors) list() map[string]*sqlCursor { return nil }
@@ -49,6 +49,20 @@ func NewNonNullViolationError(columnName string) error {
 	return pgerror.Newf(pgcode.NotNullViolation, "null value in column %q violates not-null constraint", columnName)
 }
 
+// NewDomainNotNullViolationError creates an error for a violation of the NOT
+// NULL constraint of a domain.
+func NewDomainNotNullViolationError(domainName string) error {
+	return pgerror.Newf(pgcode.NotNullViolation, "domain %s does not allow null values", domainName)
+}
+
+// NewDomainCheckViolationError creates an error for a violation of a CHECK
+// constraint of a domain.
+func NewDomainCheckViolationError(domainName, constraintName string) error {
+	return pgerror.WithConstraintName(pgerror.Newf(pgcode.CheckViolation,
+		"value for domain %s violates check constraint %q", domainName, constraintName,
+	), constraintName)
+}
+
 // NewGeneratedAlwaysAsIdentityColumnOverrideError creates an error for
 // explicitly writing a column created with `GENERATED ALWAYS AS IDENTITY`
 // syntax.
@@ -145,6 +159,18 @@ func NewUndefinedRelationError(name tree.NodeFormatter) error {
 		"relation %q does not exist", tree.ErrString(name))
 }
 
+// NewUndefinedFunctionError creates an error that represents a missing
+// function.
+func NewUndefinedFunctionError(name string) error {
+	return pgerror.Newf(pgcode.UndefinedFunction, "function %s does not exist", name)
+}
+
+// NewFunctionAlreadyExistsError creates an error for a preexisting function
+// with the same name and argument types.
+func NewFunctionAlreadyExistsError(name string) error {
+	return pgerror.Newf(pgcode.DuplicateFunction, "function %s already exists", name)
+}
+
 // NewColumnAlreadyExistsError creates an error for a preexisting column.
 func NewColumnAlreadyExistsError(name, relation string) error {
 	return pgerror.Newf(pgcode.DuplicateColumn, "column %q of relation %q already exists", name, relation)
@@ -179,6 +205,8 @@ func MakeObjectAlreadyExistsError(collidingObject *descpb.Descriptor, name strin
 		return NewDatabaseAlreadyExistsError(name)
 	case *descpb.Descriptor_Schema:
 		return NewSchemaAlreadyExistsError(name)
+	case *descpb.Descriptor_Function:
+		return NewFunctionAlreadyExistsError(name)
 	default:
 		return errors.AssertionFailedf("unknown type %T exists with name %v", collidingObject.Union, name)
 	}
@@ -87,6 +87,12 @@ func BuildStmtMetadataJSON(statistics *roachpb.CollectedStatementStatistics) (js
 //            "type": "int",
 //          },
 //        },
+//        "index_recommendations": {
+//          "type": "array",
+//          "items": {
+//            "type": "string",
+//          },
+//        },
 //        "statistics": {
 //          "type": "object",
 //          "properties": {
@@ -103,6 +109,7 @@ func BuildStmtMetadataJSON(statistics *roachpb.CollectedStatementStatistics) (js
 //            "firstExecAt":       { "type": "string" },
 //            "lastExecAt":        { "type": "string" },
 //            "nodes":             { "type": "node_ids" },
+//            "indexRecommendations": { "type": "index_recommendations" },
 //          },
 //          "required": [
 //            "firstAttemptCnt",
@@ -115,7 +122,8 @@ func BuildStmtMetadataJSON(statistics *roachpb.CollectedStatementStatistics) (js
 //            "ovhLat",
 //            "bytesRead",
 //            "rowsRead",
-//            "nodes"
+//            "nodes",
+//            "indexRecommendations"
 //          ]
 //        },
 //        "execution_statistics": {
@@ -96,7 +96,8 @@ func TestSQLStatsJsonEncoding(t *testing.T) {
            "mean": {{.Float}},
            "sqDiff": {{.Float}}
          },
-         "nodes": [{{joinInts .IntArray}}]
+         "nodes": [{{joinInts .IntArray}}],
+         "indexRecommendations": [{{range $i, $s := .StringArray}}{{if $i}},{{end}}"{{$s}}"{{end}}]
        },
        "execution_statistics": {
          "cnt": {{.Int64}},
@@ -210,7 +211,8 @@ func TestSQLStatsJsonEncoding(t *testing.T) {
            "mean": {{.Float}},
            "sqDiff": {{.Float}}
          },
-         "nodes": [{{joinInts .IntArray}}]
+         "nodes": [{{joinInts .IntArray}}],
+         "indexRecommendations": [{{range $i, $s := .StringArray}}{{if $i}},{{end}}"{{$s}}"{{end}}]
        },
        "execution_statistics": {
          "cnt": {{.Int64}},
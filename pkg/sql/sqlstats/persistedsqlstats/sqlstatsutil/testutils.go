@@ -36,12 +36,13 @@ func GetRandomizedCollectedStatementStatisticsForTest(
 }
 
 type randomData struct {
-	Bool     bool
-	String   string
-	Int64    int64
-	Float    float64
-	IntArray []int64
-	Time     time.Time
+	Bool        bool
+	String      string
+	Int64       int64
+	Float       float64
+	IntArray    []int64
+	StringArray []string
+	Time        time.Time
 }
 
 var alphabet = []rune("abcdefghijklmkopqrstuvwxyz")
@@ -66,6 +67,10 @@ func genRandomData() randomData {
 	for i := 0; i < arrLen; i++ {
 		r.IntArray[i] = rand.Int63()
 	}
+	r.StringArray = make([]string, arrLen)
+	for i := 0; i < arrLen; i++ {
+		r.StringArray[i] = r.String
+	}
 
 	r.Time = timeutil.Now()
 	return r
@@ -133,8 +138,15 @@ func fillObject(t *testing.T, val reflect.Value, data *randomData) {
 	case reflect.Bool:
 		val.SetBool(data.Bool)
 	case reflect.Slice:
-		for _, randInt := range data.IntArray {
-			val.Set(reflect.Append(val, reflect.ValueOf(randInt)))
+		switch val.Type().Elem().Kind() {
+		case reflect.String:
+			for _, randString := range data.StringArray {
+				val.Set(reflect.Append(val, reflect.ValueOf(randString)))
+			}
+		default:
+			for _, randInt := range data.IntArray {
+				val.Set(reflect.Append(val, reflect.ValueOf(randInt)))
+			}
 		}
 	case reflect.Struct:
 		switch val.Type().Name() {
@@ -275,6 +275,7 @@ func NewTempContainerFromExistingStmtStats(
 		if stmtStats.mu.data.SensitiveInfo.MostRecentPlanTimestamp.Before(statistics[i].Stats.SensitiveInfo.MostRecentPlanTimestamp) {
 			stmtStats.mu.data.SensitiveInfo.MostRecentPlanDescription = statistics[i].Stats.SensitiveInfo.MostRecentPlanDescription
 			stmtStats.mu.data.SensitiveInfo.MostRecentPlanTimestamp = statistics[i].Stats.SensitiveInfo.MostRecentPlanTimestamp
+			stmtStats.mu.data.IndexRecommendations = statistics[i].Stats.IndexRecommendations
 		}
 
 		stmtStats.mu.vectorized = statistics[i].Key.KeyData.Vec
@@ -456,6 +457,7 @@ func (s *stmtStats) mergeStatsLocked(statistics *roachpb.CollectedStatementStati
 	if s.mu.data.SensitiveInfo.MostRecentPlanTimestamp.Before(statistics.Stats.SensitiveInfo.MostRecentPlanTimestamp) {
 		s.mu.data.SensitiveInfo.MostRecentPlanDescription = statistics.Stats.SensitiveInfo.MostRecentPlanDescription
 		s.mu.data.SensitiveInfo.MostRecentPlanTimestamp = statistics.Stats.SensitiveInfo.MostRecentPlanTimestamp
+		s.mu.data.IndexRecommendations = statistics.Stats.IndexRecommendations
 	}
 
 	s.mu.vectorized = statistics.Key.Vec
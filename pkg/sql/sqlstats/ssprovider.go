@@ -183,20 +183,21 @@ type Provider interface {
 
 // RecordedStmtStats stores the statistics of a statement to be recorded.
 type RecordedStmtStats struct {
-	AutoRetryCount  int
-	RowsAffected    int
-	ParseLatency    float64
-	PlanLatency     float64
-	RunLatency      float64
-	ServiceLatency  float64
-	OverheadLatency float64
-	BytesRead       int64
-	RowsRead        int64
-	RowsWritten     int64
-	Nodes           []int64
-	StatementType   tree.StatementType
-	Plan            *roachpb.ExplainTreePlanNode
-	StatementError  error
+	AutoRetryCount       int
+	RowsAffected         int
+	ParseLatency         float64
+	PlanLatency          float64
+	RunLatency           float64
+	ServiceLatency       float64
+	OverheadLatency      float64
+	BytesRead            int64
+	RowsRead             int64
+	RowsWritten          int64
+	Nodes                []int64
+	StatementType        tree.StatementType
+	Plan                 *roachpb.ExplainTreePlanNode
+	IndexRecommendations []string
+	StatementError       error
 }
 
 // RecordedTxnStats stores the statistics of a transaction to be recorded.
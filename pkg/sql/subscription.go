@@ -0,0 +1,818 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/lexbase"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgrepl"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgnotice"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/retry"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/lib/pq/oid"
+)
+
+// subscriptionOptionExpectValues are the options of CREATE SUBSCRIPTION.
+var subscriptionOptionExpectValues = map[string]KVStringOptValidate{
+	"copy_data":   KVStringOptAny,
+	"create_slot": KVStringOptAny,
+	"slot_name":   KVStringOptRequireValue,
+}
+
+// subscriptionCopyBatchSize is the number of rows upserted per transaction
+// by the initial copy of the published tables.
+const subscriptionCopyBatchSize = 1000
+
+// subscriptionRetryOptions are the backoff options of the reconnections of
+// subscriptions to their publishers.
+var subscriptionRetryOptions = retry.Options{
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+}
+
+// subscription is a logical replication subscription. Subscriptions are
+// persisted as jobs of type TypeSubscription, which apply the changes of the
+// publisher until the subscription is dropped.
+type subscription struct {
+	jobID    jobspb.JobID
+	status   jobs.Status
+	owner    security.SQLUsername
+	details  jobspb.SubscriptionDetails
+	progress jobspb.SubscriptionProgress
+}
+
+// getSubscriptions returns the subscriptions which haven't been dropped,
+// reading the jobs table in the given transaction.
+func getSubscriptions(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn,
+) ([]subscription, error) {
+	active, err := getActiveJobs(ctx, execCfg, txn, "get-subscriptions", jobspb.TypeSubscription)
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]subscription, 0, len(active))
+	for _, j := range active {
+		sub := subscription{
+			jobID:   j.id,
+			status:  j.status,
+			owner:   j.payload.UsernameProto.Decode(),
+			details: *j.payload.GetSubscription(),
+		}
+		if p := j.progress.GetSubscription(); p != nil {
+			sub.progress = *p
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// lookupSubscription returns the subscription of the given database with the
+// given name, or nil if it doesn't exist.
+func lookupSubscription(
+	ctx context.Context, execCfg *ExecutorConfig, txn *kv.Txn, dbDesc catalog.DatabaseDescriptor, name string,
+) (*subscription, error) {
+	subs, err := getSubscriptions(ctx, execCfg, txn)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		if subs[i].details.DatabaseID == dbDesc.GetID() && subs[i].details.SubscriptionName == name {
+			return &subs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// getCurrentDatabaseForSubscriptions returns the current database, which
+// subscriptions belong to like in PostgreSQL.
+func (p *planner) getCurrentDatabaseForSubscriptions(
+	ctx context.Context,
+) (catalog.DatabaseDescriptor, error) {
+	if p.CurrentDatabase() == "" {
+		return nil, pgerror.New(pgcode.InvalidCatalogName,
+			"cannot use subscriptions without a current database")
+	}
+	return p.Descriptors().GetImmutableDatabaseByName(ctx, p.txn, p.CurrentDatabase(),
+		tree.DatabaseLookupFlags{Required: true})
+}
+
+type createSubscriptionNode struct {
+	n          *tree.CreateSubscription
+	connection func() (string, error)
+	options    func() (map[string]string, error)
+}
+
+// CreateSubscription creates a subscription in the current database. The job
+// of the subscription creates the replication slot on the publisher, copies
+// the existing rows of the published tables, and then applies their changes
+// to the tables with the same names in the current database.
+// Privileges: admin.
+func (p *planner) CreateSubscription(
+	ctx context.Context, n *tree.CreateSubscription,
+) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, "CREATE SUBSCRIPTION"); err != nil {
+		return nil, err
+	}
+	if err := checkLogicalReplicationSupported(ctx, p.ExecCfg().Settings); err != nil {
+		return nil, err
+	}
+	connection, err := p.TypeAsString(ctx, n.Connection, "CREATE SUBSCRIPTION")
+	if err != nil {
+		return nil, err
+	}
+	options, err := p.TypeAsStringOpts(ctx, n.Options, subscriptionOptionExpectValues)
+	if err != nil {
+		return nil, err
+	}
+	return &createSubscriptionNode{n: n, connection: connection, options: options}, nil
+}
+
+func (n *createSubscriptionNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	dbDesc, err := p.getCurrentDatabaseForSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	name := string(n.n.Name)
+	existing, err := lookupSubscription(ctx, p.ExecCfg(), p.txn, dbDesc, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return pgerror.Newf(pgcode.DuplicateObject, "subscription %q already exists", name)
+	}
+	connection, err := n.connection()
+	if err != nil {
+		return err
+	}
+	if _, err := pgconn.ParseConfig(connection); err != nil {
+		return pgerror.Wrap(err, pgcode.InvalidParameterValue, "invalid connection string")
+	}
+	details := jobspb.SubscriptionDetails{
+		SubscriptionName: name,
+		DatabaseID:       dbDesc.GetID(),
+		Connection:       connection,
+		SlotName:         name,
+		CreateSlot:       true,
+		CopyData:         true,
+	}
+	for _, pub := range n.n.Publications {
+		details.Publications = append(details.Publications, string(pub))
+	}
+	opts, err := n.options()
+	if err != nil {
+		return err
+	}
+	if err := setSubscriptionOptions(&details, opts); err != nil {
+		return err
+	}
+	if err := checkReplicationSlotName(details.SlotName); err != nil {
+		return err
+	}
+
+	registry := p.ExecCfg().JobRegistry
+	record := jobs.Record{
+		Description: fmt.Sprintf("subscription %s", tree.NameString(name)),
+		Username:    p.User(),
+		Details:     details,
+		Progress:    jobspb.SubscriptionProgress{},
+	}
+	_, err = registry.CreateJobWithTxn(ctx, record, registry.MakeJobID(), p.txn)
+	return err
+}
+
+func (*createSubscriptionNode) Next(runParams) (bool, error) { return false, nil }
+func (*createSubscriptionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*createSubscriptionNode) Close(context.Context)        {}
+
+// setSubscriptionOptions applies the options of CREATE SUBSCRIPTION to the
+// details of a subscription. Like in PostgreSQL, boolean options given
+// without a value are true.
+func setSubscriptionOptions(details *jobspb.SubscriptionDetails, opts map[string]string) error {
+	for k, v := range opts {
+		switch k {
+		case "copy_data", "create_slot":
+			b := true
+			if v != "" {
+				var err error
+				if b, err = tree.ParseBool(v); err != nil {
+					return pgerror.Newf(pgcode.InvalidParameterValue, "%s requires a Boolean value", k)
+				}
+			}
+			if k == "copy_data" {
+				details.CopyData = b
+			} else {
+				details.CreateSlot = b
+			}
+		case "slot_name":
+			details.SlotName = v
+		}
+	}
+	return nil
+}
+
+type dropSubscriptionNode struct {
+	n *tree.DropSubscription
+}
+
+// DropSubscription drops a subscription. Canceling its job stops the
+// replication, and drops the replication slot on the publisher if the
+// subscription created it.
+// Privileges: admin.
+func (p *planner) DropSubscription(
+	ctx context.Context, n *tree.DropSubscription,
+) (planNode, error) {
+	if err := p.RequireAdminRole(ctx, "DROP SUBSCRIPTION"); err != nil {
+		return nil, err
+	}
+	return &dropSubscriptionNode{n: n}, nil
+}
+
+func (n *dropSubscriptionNode) startExec(params runParams) error {
+	ctx, p := params.ctx, params.p
+	dbDesc, err := p.getCurrentDatabaseForSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	name := string(n.n.Name)
+	sub, err := lookupSubscription(ctx, p.ExecCfg(), p.txn, dbDesc, name)
+	if err != nil {
+		return err
+	}
+	if sub == nil {
+		if n.n.IfExists {
+			p.BufferClientNotice(ctx, pgnotice.Newf(
+				"subscription %q does not exist, skipping", name))
+			return nil
+		}
+		return pgerror.Newf(pgcode.UndefinedObject, "subscription %q does not exist", name)
+	}
+	return p.ExecCfg().JobRegistry.CancelRequested(ctx, p.txn, sub.jobID)
+}
+
+func (*dropSubscriptionNode) Next(runParams) (bool, error) { return false, nil }
+func (*dropSubscriptionNode) Values() tree.Datums          { return tree.Datums{} }
+func (*dropSubscriptionNode) Close(context.Context)        {}
+
+// connectToPublisher opens a replication connection to the publisher of a
+// subscription. Replication connections also accept SQL queries.
+func connectToPublisher(ctx context.Context, connection string) (*pgconn.PgConn, error) {
+	cfg, err := pgconn.ParseConfig(connection)
+	if err != nil {
+		return nil, err
+	}
+	cfg.RuntimeParams["replication"] = "database"
+	return pgconn.ConnectConfig(ctx, cfg)
+}
+
+// subscriptionResumer is the resumer of the jobs of subscriptions. It applies
+// the changes of the publisher until the job is canceled, and reconnects to
+// the publisher after errors.
+type subscriptionResumer struct {
+	job *jobs.Job
+}
+
+var _ jobs.Resumer = &subscriptionResumer{}
+
+// Resume is part of the jobs.Resumer interface.
+func (r *subscriptionResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	execCfg := execCtx.(JobExecContext).ExecCfg()
+	details := r.job.Details().(jobspb.SubscriptionDetails)
+	for rt := retry.StartWithCtx(ctx, subscriptionRetryOptions); rt.Next(); {
+		w := subscriptionWorker{
+			job:       r.job,
+			execCfg:   execCfg,
+			details:   details,
+			relations: make(map[oid.Oid]*pgrepl.Relation),
+		}
+		err := w.run(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		log.Warningf(ctx, "subscription %q: %v", details.SubscriptionName, err)
+		if w.applied {
+			rt.Reset()
+		}
+	}
+	return ctx.Err()
+}
+
+// OnFailOrCancel is part of the jobs.Resumer interface. It drops the
+// replication slot on the publisher if the subscription created it. Failures
+// are only logged, since the slot can still be dropped on the publisher.
+func (r *subscriptionResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	details := r.job.Details().(jobspb.SubscriptionDetails)
+	if !subscriptionJobProgress(r.job).CreatedSlot {
+		return nil
+	}
+	// The publisher may not have noticed yet that the connection which
+	// streamed from the slot was closed, in which case the slot is active.
+	var err error
+	opts := retry.Options{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 5 * time.Second, MaxRetries: 10}
+	for rt := retry.StartWithCtx(ctx, opts); rt.Next(); {
+		if err = dropPublisherSlot(ctx, details); err == nil {
+			return nil
+		}
+	}
+	log.Warningf(ctx, "subscription %q: could not drop replication slot %q on the publisher: %v",
+		details.SubscriptionName, details.SlotName, err)
+	return nil
+}
+
+// subscriptionJobProgress returns the progress of the job of a subscription.
+func subscriptionJobProgress(job *jobs.Job) jobspb.SubscriptionProgress {
+	progress := job.Progress()
+	if p := progress.GetSubscription(); p != nil {
+		return *p
+	}
+	return jobspb.SubscriptionProgress{}
+}
+
+// dropPublisherSlot drops the replication slot of a subscription on its
+// publisher.
+func dropPublisherSlot(ctx context.Context, details jobspb.SubscriptionDetails) error {
+	conn, err := connectToPublisher(ctx, details.Connection)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close(ctx) }()
+	_, err = conn.Exec(ctx, fmt.Sprintf("DROP_REPLICATION_SLOT %s", details.SlotName)).ReadAll()
+	return err
+}
+
+// subscriptionChange is a change of a row of a table of the publisher.
+type subscriptionChange struct {
+	rel *pgrepl.Relation
+	// msg is an *Insert, *Update, *Delete or *Truncate message.
+	msg pgrepl.PGOutputMessage
+}
+
+// subscriptionWorker connects to the publisher of a subscription and applies
+// its changes, for one attempt of the job.
+type subscriptionWorker struct {
+	job     *jobs.Job
+	execCfg *ExecutorConfig
+	details jobspb.SubscriptionDetails
+	conn    *pgconn.PgConn
+
+	// relations are the tables of the publisher, by relation ID.
+	relations map[oid.Oid]*pgrepl.Relation
+	// inTxn is set between the Begin and Commit messages of a transaction,
+	// whose changes are buffered in changes until the commit.
+	inTxn   bool
+	changes []subscriptionChange
+	// confirmed is the position of the publisher up to which all the changes
+	// were applied. It is the end of the last applied transaction, or the end
+	// of the stream as of the last keepalive received between transactions.
+	confirmed pgrepl.LSN
+	// applied is set once a transaction was applied.
+	applied bool
+}
+
+// run connects to the publisher, creates the slot and copies the tables if
+// this wasn't done yet, and then applies the changes streamed from the slot
+// until an error occurs.
+func (w *subscriptionWorker) run(ctx context.Context) error {
+	conn, err := connectToPublisher(ctx, w.details.Connection)
+	if err != nil {
+		return errors.Wrap(err, "connecting to the publisher")
+	}
+	defer func() { _ = conn.Close(ctx) }()
+	w.conn = conn
+
+	progress := subscriptionJobProgress(w.job)
+	if w.details.CreateSlot && !progress.CreatedSlot {
+		if _, err := conn.Exec(ctx, fmt.Sprintf("CREATE_REPLICATION_SLOT %s LOGICAL %s NOEXPORT_SNAPSHOT",
+			w.details.SlotName, pgoutputPlugin)).ReadAll(); err != nil {
+			return errors.Wrapf(err, "creating replication slot %q", w.details.SlotName)
+		}
+		progress.CreatedSlot = true
+		if err := w.updateProgress(ctx, nil /* txn */, progress); err != nil {
+			return err
+		}
+	}
+	if w.details.CopyData && !progress.CopiedData {
+		if err := w.copyTables(ctx); err != nil {
+			return errors.Wrap(err, "copying the published tables")
+		}
+		progress.CopiedData = true
+		if err := w.updateProgress(ctx, nil /* txn */, progress); err != nil {
+			return err
+		}
+	}
+	w.confirmed = pgrepl.LSN(progress.FlushedLSN)
+	return w.stream(ctx)
+}
+
+// updateProgress persists the progress of the subscription.
+func (w *subscriptionWorker) updateProgress(
+	ctx context.Context, txn *kv.Txn, progress jobspb.SubscriptionProgress,
+) error {
+	return w.job.Update(ctx, txn, func(txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater) error {
+		*md.Progress.GetSubscription() = progress
+		ju.UpdateProgress(md.Progress)
+		return nil
+	})
+}
+
+// copyTables copies the rows of the published tables. The copy isn't
+// consistent with the position of the slot, but the changes streamed from the
+// slot are applied as upserts and deletes, which makes the tables converge.
+func (w *subscriptionWorker) copyTables(ctx context.Context) error {
+	pubs := make([]string, len(w.details.Publications))
+	for i, pub := range w.details.Publications {
+		pubs[i] = lexbase.EscapeSQLString(pub)
+	}
+	results, err := w.conn.Exec(ctx, fmt.Sprintf(
+		`SELECT DISTINCT schemaname, tablename FROM pg_catalog.pg_publication_tables WHERE pubname IN (%s)`,
+		strings.Join(pubs, ", "),
+	)).ReadAll()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		for _, row := range result.Rows {
+			if err := w.copyTable(ctx, string(row[0]), string(row[1])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyTable copies the rows of a table of the publisher, in batches of
+// subscriptionCopyBatchSize rows per transaction.
+func (w *subscriptionWorker) copyTable(ctx context.Context, schema, table string) error {
+	mrr := w.conn.Exec(ctx, fmt.Sprintf("SELECT * FROM %s.%s",
+		tree.NameString(schema), tree.NameString(table)))
+	for mrr.NextResult() {
+		rr := mrr.ResultReader()
+		rel := &pgrepl.Relation{Namespace: schema, Name: table}
+		for _, f := range rr.FieldDescriptions() {
+			rel.Columns = append(rel.Columns, pgrepl.RelationColumn{Name: string(f.Name)})
+		}
+		var batch []subscriptionChange
+		for rr.NextRow() {
+			values := rr.Values()
+			row := make(tree.Datums, len(values))
+			for i, v := range values {
+				if v == nil {
+					row[i] = tree.DNull
+				} else {
+					row[i] = tree.NewDString(string(v))
+				}
+			}
+			batch = append(batch, subscriptionChange{rel: rel, msg: &pgrepl.Insert{New: row}})
+			if len(batch) == subscriptionCopyBatchSize {
+				if err := w.apply(ctx, batch, pgrepl.InvalidLSN); err != nil {
+					_, _ = rr.Close()
+					_ = mrr.Close()
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+		if _, err := rr.Close(); err != nil {
+			_ = mrr.Close()
+			return err
+		}
+		if err := w.apply(ctx, batch, pgrepl.InvalidLSN); err != nil {
+			_ = mrr.Close()
+			return err
+		}
+	}
+	return mrr.Close()
+}
+
+// stream starts the replication from the slot at the confirmed position, and
+// applies the transactions it receives.
+func (w *subscriptionWorker) stream(ctx context.Context) error {
+	var pubs bytes.Buffer
+	for i, pub := range w.details.Publications {
+		if i > 0 {
+			pubs.WriteByte(',')
+		}
+		lexbase.EncodeEscapedSQLIdent(&pubs, pub)
+	}
+	query := fmt.Sprintf("START_REPLICATION SLOT %s LOGICAL %s (proto_version '%d', publication_names %s)",
+		w.details.SlotName, w.confirmed, pgrepl.PGOutputProtoVersion,
+		lexbase.EscapeSQLString(pubs.String()))
+	if err := w.conn.SendBytes(ctx, (&pgproto3.Query{String: query}).Encode(nil)); err != nil {
+		return err
+	}
+	for {
+		msg, err := w.conn.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.CopyData:
+			if err := w.handleCopyData(ctx, msg.Data); err != nil {
+				return err
+			}
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.CopyDone, *pgproto3.ReadyForQuery:
+			return errors.New("the publisher ended the replication")
+		}
+	}
+}
+
+// handleCopyData handles a message of the replication stream.
+func (w *subscriptionWorker) handleCopyData(ctx context.Context, data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty replication message")
+	}
+	switch data[0] {
+	case pgrepl.XLogDataByte:
+		xlog, err := pgrepl.ParseXLogData(data)
+		if err != nil {
+			return err
+		}
+		msg, err := pgrepl.ParsePGOutputMessage(xlog.Data)
+		if err != nil {
+			return err
+		}
+		return w.handleMessage(ctx, msg)
+	case pgrepl.PrimaryKeepaliveByte:
+		keepalive, err := pgrepl.ParsePrimaryKeepalive(data)
+		if err != nil {
+			return err
+		}
+		// The transactions which ended before the end of the stream were
+		// already received, so the publisher may release them unless a
+		// transaction is being received.
+		if !w.inTxn && w.confirmed < keepalive.WALEnd {
+			w.confirmed = keepalive.WALEnd
+			return w.sendStatus(ctx)
+		}
+		if keepalive.ReplyRequested {
+			return w.sendStatus(ctx)
+		}
+		return nil
+	default:
+		return errors.Newf("unexpected replication message of type %q", data[0])
+	}
+}
+
+// handleMessage handles a pgoutput message. The changes of a transaction are
+// buffered, and applied in a single transaction when its commit is received.
+func (w *subscriptionWorker) handleMessage(ctx context.Context, msg pgrepl.PGOutputMessage) error {
+	switch msg := msg.(type) {
+	case *pgrepl.Begin:
+		w.inTxn = true
+		w.changes = w.changes[:0]
+	case *pgrepl.Relation:
+		w.relations[msg.ID] = msg
+	case *pgrepl.Insert:
+		return w.addChange(msg.RelationID, msg)
+	case *pgrepl.Update:
+		return w.addChange(msg.RelationID, msg)
+	case *pgrepl.Delete:
+		return w.addChange(msg.RelationID, msg)
+	case *pgrepl.Truncate:
+		for _, id := range msg.RelationIDs {
+			if err := w.addChange(id, msg); err != nil {
+				return err
+			}
+		}
+	case *pgrepl.Commit:
+		if err := w.apply(ctx, w.changes, msg.EndLSN); err != nil {
+			return err
+		}
+		w.inTxn = false
+		w.changes = w.changes[:0]
+		w.applied = true
+		if w.confirmed < msg.EndLSN {
+			w.confirmed = msg.EndLSN
+		}
+		return w.sendStatus(ctx)
+	}
+	return nil
+}
+
+// addChange buffers a change of the transaction being received.
+func (w *subscriptionWorker) addChange(relID oid.Oid, msg pgrepl.PGOutputMessage) error {
+	rel, ok := w.relations[relID]
+	if !ok {
+		return errors.Newf("change of unknown relation %d", relID)
+	}
+	w.changes = append(w.changes, subscriptionChange{rel: rel, msg: msg})
+	return nil
+}
+
+// sendStatus reports the confirmed position to the publisher, which may then
+// release the older changes of the slot.
+func (w *subscriptionWorker) sendStatus(ctx context.Context) error {
+	update := pgrepl.StandbyStatusUpdate{
+		WriteLSN:   w.confirmed,
+		FlushLSN:   w.confirmed,
+		ApplyLSN:   w.confirmed,
+		ClientTime: timeutil.Now(),
+	}
+	return w.conn.SendBytes(ctx, (&pgproto3.CopyData{Data: update.Encode(nil)}).Encode(nil))
+}
+
+// apply applies changes to the tables of the subscription in a transaction,
+// as the owner of the subscription. If flushed is valid, the position of the
+// subscription is advanced to it in the same transaction.
+func (w *subscriptionWorker) apply(
+	ctx context.Context, changes []subscriptionChange, flushed pgrepl.LSN,
+) error {
+	if len(changes) == 0 && flushed == pgrepl.InvalidLSN {
+		return nil
+	}
+	return DescsTxn(ctx, w.execCfg, func(ctx context.Context, txn *kv.Txn, col *descs.Collection) error {
+		_, dbDesc, err := col.GetImmutableDatabaseByID(ctx, txn, w.details.DatabaseID,
+			tree.DatabaseLookupFlags{Required: true})
+		if err != nil {
+			return err
+		}
+		a := subscriptionApplier{
+			w:        w,
+			txn:      txn,
+			col:      col,
+			dbName:   tree.Name(dbDesc.GetName()),
+			override: sessiondata.InternalExecutorOverride{User: w.job.Payload().UsernameProto.Decode()},
+			parseCtx: tree.NewParseTimeContext(timeutil.Now()),
+		}
+		for _, c := range changes {
+			if err := a.applyChange(ctx, c); err != nil {
+				return err
+			}
+		}
+		if flushed == pgrepl.InvalidLSN {
+			return nil
+		}
+		progress := subscriptionJobProgress(w.job)
+		progress.FlushedLSN = uint64(flushed)
+		return w.updateProgress(ctx, txn, progress)
+	})
+}
+
+// subscriptionApplier applies the changes of the publisher in a transaction.
+type subscriptionApplier struct {
+	w        *subscriptionWorker
+	txn      *kv.Txn
+	col      *descs.Collection
+	dbName   tree.Name
+	override sessiondata.InternalExecutorOverride
+	parseCtx tree.ParseTimeContext
+}
+
+// applyChange applies a change to the table with the same name as the table
+// of the publisher. Inserts and updates are applied as upserts, and truncates
+// as deletes of all the rows.
+func (a *subscriptionApplier) applyChange(ctx context.Context, c subscriptionChange) error {
+	tn := tree.MakeTableNameWithSchema(a.dbName, tree.Name(c.rel.Namespace), tree.Name(c.rel.Name))
+	_, table, err := a.col.GetImmutableTableByName(ctx, a.txn, &tn, tree.ObjectLookupFlagsWithRequired())
+	if err != nil {
+		return err
+	}
+	switch msg := c.msg.(type) {
+	case *pgrepl.Insert:
+		return a.upsert(ctx, &tn, table, c.rel, msg.New)
+	case *pgrepl.Update:
+		// The old key is only sent when the update changed it.
+		if msg.OldKey != nil {
+			if err := a.delete(ctx, &tn, table, c.rel, msg.OldKey); err != nil {
+				return err
+			}
+		}
+		return a.upsert(ctx, &tn, table, c.rel, msg.New)
+	case *pgrepl.Delete:
+		return a.delete(ctx, &tn, table, c.rel, msg.OldKey)
+	case *pgrepl.Truncate:
+		return a.exec(ctx, "subscription-truncate", fmt.Sprintf("DELETE FROM %s", tn.FQString()))
+	}
+	return errors.AssertionFailedf("unexpected change %T", c.msg)
+}
+
+// upsert upserts a row. The unchanged values which the publisher didn't send
+// are left as is.
+func (a *subscriptionApplier) upsert(
+	ctx context.Context,
+	tn *tree.TableName,
+	table catalog.TableDescriptor,
+	rel *pgrepl.Relation,
+	row tree.Datums,
+) error {
+	var cols tree.NameList
+	var placeholders []string
+	var args []interface{}
+	for i, v := range row {
+		if v == nil {
+			continue
+		}
+		d, err := a.parseValue(tn, table, rel, i, v)
+		if err != nil {
+			return err
+		}
+		cols = append(cols, tree.Name(rel.Columns[i].Name))
+		args = append(args, d)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	return a.exec(ctx, "subscription-upsert",
+		fmt.Sprintf("UPSERT INTO %s (%s) VALUES (%s)",
+			tn.FQString(), cols.String(), strings.Join(placeholders, ", ")),
+		args...)
+}
+
+// delete deletes the row with the given key.
+func (a *subscriptionApplier) delete(
+	ctx context.Context,
+	tn *tree.TableName,
+	table catalog.TableDescriptor,
+	rel *pgrepl.Relation,
+	key tree.Datums,
+) error {
+	var conds []string
+	var args []interface{}
+	for i, c := range rel.Columns {
+		if !c.Key {
+			continue
+		}
+		if i >= len(key) || key[i] == nil {
+			return errors.Newf("missing key column %q of logical replication relation %s.%s",
+				c.Name, rel.Namespace, rel.Name)
+		}
+		d, err := a.parseValue(tn, table, rel, i, key[i])
+		if err != nil {
+			return err
+		}
+		args = append(args, d)
+		conds = append(conds, fmt.Sprintf("%s = $%d", tree.NameString(c.Name), len(args)))
+	}
+	if len(conds) == 0 {
+		return errors.Newf("logical replication relation %s.%s has no replica identity",
+			rel.Namespace, rel.Name)
+	}
+	return a.exec(ctx, "subscription-delete",
+		fmt.Sprintf("DELETE FROM %s WHERE %s", tn.FQString(), strings.Join(conds, " AND ")),
+		args...)
+}
+
+// parseValue parses the text value of the i-th column of a relation of the
+// publisher as a value of the local column with the same name.
+func (a *subscriptionApplier) parseValue(
+	tn *tree.TableName, table catalog.TableDescriptor, rel *pgrepl.Relation, i int, v tree.Datum,
+) (tree.Datum, error) {
+	if i >= len(rel.Columns) {
+		return nil, errors.Newf("too many values for logical replication relation %s.%s",
+			rel.Namespace, rel.Name)
+	}
+	col, err := table.FindColumnWithName(tree.Name(rel.Columns[i].Name))
+	if err != nil {
+		return nil, pgerror.Newf(pgcode.UndefinedColumn,
+			"logical replication target relation %q is missing some replicated columns",
+			tn.FQString())
+	}
+	if v == tree.DNull {
+		return tree.DNull, nil
+	}
+	d, _, err := tree.ParseAndRequireString(col.GetType(), string(tree.MustBeDString(v)), a.parseCtx)
+	return d, err
+}
+
+// exec executes a statement in the transaction of the applier.
+func (a *subscriptionApplier) exec(
+	ctx context.Context, opName string, stmt string, args ...interface{},
+) error {
+	_, err := a.w.execCfg.InternalExecutor.ExecEx(ctx, opName, a.txn, a.override, stmt, args...)
+	return err
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeSubscription, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &subscriptionResumer{job: job}
+	})
+}
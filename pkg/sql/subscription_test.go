@@ -0,0 +1,91 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package sql_test
+
+import (
+	"context"
+	gosql "database/sql"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubscription subscribes a database to a publication of another
+// database of the same cluster, and checks that the existing rows are copied
+// and that the changes are applied.
+func TestSubscription(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	s, _, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals()},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	openDB := func(dbName string) (_ *gosql.DB, _ url.URL, cleanup func()) {
+		pgURL, cleanupURL := sqlutils.PGUrl(t, s.ServingSQLAddr(), t.Name(), url.User(security.RootUser))
+		pgURL.Path = dbName
+		db, err := gosql.Open("postgres", pgURL.String())
+		require.NoError(t, err)
+		return db, pgURL, func() {
+			_ = db.Close()
+			cleanupURL()
+		}
+	}
+	pubConn, pubURL, cleanupPub := openDB("pub")
+	defer cleanupPub()
+	subConn, _, cleanupSub := openDB("sub")
+	defer cleanupSub()
+	pubDB := sqlutils.MakeSQLRunner(pubConn)
+	subDB := sqlutils.MakeSQLRunner(subConn)
+
+	pubDB.Exec(t, `SET CLUSTER SETTING kv.rangefeed.enabled = true`)
+	pubDB.Exec(t, `SET CLUSTER SETTING kv.closed_timestamp.target_duration = '100ms'`)
+	pubDB.Exec(t, `CREATE DATABASE pub`)
+	pubDB.Exec(t, `CREATE DATABASE sub`)
+	pubDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	pubDB.Exec(t, `INSERT INTO t VALUES (1, 'a'), (2, 'b')`)
+	pubDB.Exec(t, `CREATE PUBLICATION p FOR TABLE t`)
+
+	subDB.Exec(t, `CREATE TABLE t (k INT PRIMARY KEY, v STRING)`)
+	subDB.Exec(t, fmt.Sprintf(`CREATE SUBSCRIPTION s CONNECTION '%s' PUBLICATION p`, pubURL.String()))
+	subDB.CheckQueryResultsRetry(t, `SELECT * FROM t ORDER BY k`, [][]string{
+		{"1", "a"},
+		{"2", "b"},
+	})
+	pubDB.CheckQueryResults(t, `SELECT slot_name, database FROM pg_replication_slots`, [][]string{
+		{"s", "pub"},
+	})
+
+	pubDB.Exec(t, `INSERT INTO t VALUES (3, 'c')`)
+	pubDB.Exec(t, `UPDATE t SET v = 'B' WHERE k = 2`)
+	pubDB.Exec(t, `UPDATE t SET k = 4 WHERE k = 3`)
+	pubDB.Exec(t, `DELETE FROM t WHERE k = 1`)
+	subDB.CheckQueryResultsRetry(t, `SELECT * FROM t ORDER BY k`, [][]string{
+		{"2", "B"},
+		{"4", "c"},
+	})
+
+	// Dropping the subscription drops the slot it created on the publisher.
+	subDB.Exec(t, `DROP SUBSCRIPTION s`)
+	subDB.CheckQueryResults(t, `SELECT count(*) FROM pg_subscription`, [][]string{{"0"}})
+	pubDB.CheckQueryResultsRetry(t, `SELECT count(*) FROM pg_replication_slots`, [][]string{{"0"}})
+}
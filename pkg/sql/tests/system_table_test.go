@@ -57,11 +57,12 @@ func TestInitialKeys(t *testing.T) {
 			t.Fatalf("Wrong number of initial sql kv pairs: %d, wanted %d", actual, expected)
 		}
 
-		// Add an additional table.
+		// Add an additional table. All reserved IDs outside of the system config
+		// range are taken, so use the unused ID at the end of that range.
 		desc, err := sql.CreateTestTableDescriptor(
 			context.Background(),
 			keys.SystemDatabaseID,
-			keys.MaxReservedDescID,
+			keys.MaxSystemConfigDescID,
 			"CREATE TABLE system.x (val INTEGER PRIMARY KEY)",
 			descpb.NewBasePrivilegeDescriptor(security.NodeUserName()),
 		)
@@ -173,7 +174,7 @@ func TestSystemTableLiterals(t *testing.T) {
 		}
 	}
 
-	const expectedNumberOfSystemTables = 37
+	const expectedNumberOfSystemTables = 39
 	require.Equal(t, expectedNumberOfSystemTables, len(testcases))
 
 	for name, test := range testcases {
@@ -70,6 +70,9 @@ func (t *truncateNode) startExec(params runParams) error {
 		if err != nil {
 			return err
 		}
+		if err := checkNotForeignTable(tableDesc, "TRUNCATE"); err != nil {
+			return err
+		}
 
 		if err := p.CheckPrivilege(ctx, tableDesc, privilege.DROP); err != nil {
 			return err
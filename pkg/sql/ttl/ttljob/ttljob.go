@@ -0,0 +1,445 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvclient/kvcoord"
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
+	"github.com/cockroachdb/cockroach/pkg/settings"
+	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descs"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/rowenc"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/sql/types"
+	"github.com/cockroachdb/cockroach/pkg/util/ctxgroup"
+	"github.com/cockroachdb/cockroach/pkg/util/quotapool"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	defaultSelectBatchSize = settings.RegisterIntSetting(
+		"sql.ttl.default_select_batch_size",
+		"default amount of rows to select in a single query during a TTL job",
+		500,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteBatchSize = settings.RegisterIntSetting(
+		"sql.ttl.default_delete_batch_size",
+		"default amount of rows to delete in a single query during a TTL job",
+		100,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultRangeConcurrency = settings.RegisterIntSetting(
+		"sql.ttl.default_range_concurrency",
+		"default amount of ranges to process at once during a TTL delete",
+		1,
+		settings.PositiveInt,
+	).WithPublic()
+	defaultDeleteRateLimit = settings.RegisterIntSetting(
+		"sql.ttl.default_delete_rate_limit",
+		"default delete rate limit for all TTL jobs. Use 0 to signify no rate limit.",
+		0,
+		settings.NonNegativeInt,
+	).WithPublic()
+
+	jobEnabled = settings.RegisterBoolSetting(
+		"sql.ttl.job.enabled",
+		"whether the TTL job is enabled",
+		true,
+	).WithPublic()
+)
+
+// defaultAOSTDuration is how far in the past the expired rows are read, so
+// that the reads do not contend with the foreground traffic of the table.
+const defaultAOSTDuration = 30 * time.Second
+
+type rowLevelTTLResumer struct {
+	job *jobs.Job
+	st  *cluster.Settings
+}
+
+var _ jobs.Resumer = (*rowLevelTTLResumer)(nil)
+
+// rangeToProcess is a range of the primary index of a table, bounded by the
+// (possibly partial) primary keys it starts and ends at. A nil bound means
+// the range extends to the start or end of the index.
+type rangeToProcess struct {
+	startPK, endPK tree.Datums
+}
+
+// Resume implements the jobs.Resumer interface.
+func (t rowLevelTTLResumer) Resume(ctx context.Context, execCtx interface{}) error {
+	p := execCtx.(sql.JobExecContext)
+	execCfg := p.ExecCfg()
+	ie := execCfg.InternalExecutor
+
+	if enabled := jobEnabled.Get(execCfg.SV()); !enabled {
+		return errors.Newf("ttl jobs are currently disabled by CLUSTER SETTING sql.ttl.job.enabled")
+	}
+
+	telemetry.Inc(sqltelemetry.RowLevelTTLExecuted)
+
+	details := t.job.Details().(jobspb.RowLevelTTLDetails)
+	// Rows are read at a recent timestamp rather than as of the cutoff, which
+	// may be older than the GC threshold if the job was delayed. The queries
+	// only select the rows which had expired at the cutoff.
+	aostDuration := defaultAOSTDuration
+	if knobs := execCfg.TTLTestingKnobs; knobs != nil && knobs.AOSTDuration != nil {
+		aostDuration = *knobs.AOSTDuration
+	}
+	aost := timeutil.Now().Add(-aostDuration)
+
+	var ttlSettings descpb.TableDescriptor_RowLevelTTL
+	var pkColumns []string
+	var pkTypes []*types.T
+	var tableSpan roachpb.Span
+	var tableName string
+	if err := sql.DescsTxn(ctx, execCfg, func(
+		ctx context.Context, txn *kv.Txn, descsCol *descs.Collection,
+	) error {
+		desc, err := descsCol.GetImmutableTableByID(
+			ctx,
+			txn,
+			details.TableID,
+			tree.ObjectLookupFlagsWithRequired(),
+		)
+		if err != nil {
+			return err
+		}
+		ttl := desc.GetRowLevelTTL()
+		if ttl == nil {
+			return errors.Newf("unable to find TTL on table %s", desc.GetName())
+		}
+		if ttl.Pause {
+			return pgerror.Newf(
+				pgcode.OperatorIntervention,
+				"ttl jobs on table %s are currently paused",
+				tree.Name(desc.GetName()),
+			)
+		}
+		ttlSettings = *ttl
+
+		primaryIndex := desc.GetPrimaryIndex()
+		pkColumns = primaryIndex.IndexDesc().KeyColumnNames
+		for i := 0; i < primaryIndex.NumKeyColumns(); i++ {
+			col, err := desc.FindColumnWithID(primaryIndex.GetKeyColumnID(i))
+			if err != nil {
+				return err
+			}
+			pkTypes = append(pkTypes, col.GetType())
+		}
+		tableSpan = desc.PrimaryIndexSpan(execCfg.Codec)
+
+		_, dbDesc, err := descsCol.GetImmutableDatabaseByID(
+			ctx, txn, desc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		scDesc, err := descsCol.GetImmutableSchemaByID(
+			ctx, txn, desc.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+		)
+		if err != nil {
+			return err
+		}
+		tableName = tree.NewTableNameWithSchema(
+			tree.Name(dbDesc.GetName()),
+			tree.Name(scDesc.GetName()),
+			tree.Name(desc.GetName()),
+		).FQString()
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	selectBatchSize := getSelectBatchSize(execCfg.SV(), ttlSettings)
+	deleteBatchSize := getDeleteBatchSize(execCfg.SV(), ttlSettings)
+	rangeConcurrency := getRangeConcurrency(execCfg.SV(), ttlSettings)
+	deleteRateLimit := getDeleteRateLimit(execCfg.SV(), ttlSettings)
+	deleteRateLimiter := quotapool.NewRateLimiter(
+		"ttl-delete",
+		quotapool.Limit(deleteRateLimit),
+		deleteRateLimit,
+	)
+
+	metrics := execCfg.JobRegistry.MetricsStruct().RowLevelTTL.(*RowLevelTTLMetrics)
+
+	ch := make(chan rangeToProcess, rangeConcurrency)
+	g := ctxgroup.WithContext(ctx)
+	for i := 0; i < rangeConcurrency; i++ {
+		g.GoCtx(func(ctx context.Context) error {
+			for r := range ch {
+				start := timeutil.Now()
+				metrics.NumActiveRanges.Inc(1)
+				rowCount, err := runTTLOnRange(
+					ctx,
+					execCfg,
+					ie,
+					metrics,
+					deleteRateLimiter,
+					r,
+					tableName,
+					pkColumns,
+					aost,
+					details.Cutoff,
+					selectBatchSize,
+					deleteBatchSize,
+				)
+				metrics.NumActiveRanges.Dec(1)
+				metrics.RangeTotalDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+				if err != nil {
+					// Drain the channel so the producer is not blocked.
+					for range ch {
+					}
+					return err
+				}
+				if err := t.updateRowCount(ctx, rowCount); err != nil {
+					for range ch {
+					}
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := func() error {
+		defer close(ch)
+		ri := kvcoord.NewRangeIterator(execCfg.DistSender)
+		var alloc rowenc.DatumAlloc
+		rs, err := keys.SpanAddr(tableSpan)
+		if err != nil {
+			return err
+		}
+		for ri.Seek(ctx, rs.Key, kvcoord.Ascending); ri.Valid(); ri.Next(ctx) {
+			rangeDesc := ri.Desc()
+			var r rangeToProcess
+			startKey := rangeDesc.StartKey
+			if startKey.Less(rs.Key) {
+				startKey = rs.Key
+			}
+			endKey := rangeDesc.EndKey
+			if rs.EndKey.Less(endKey) {
+				endKey = rs.EndKey
+			}
+			if r.startPK, err = keyToDatums(startKey, execCfg.Codec, pkTypes, &alloc); err != nil {
+				return err
+			}
+			if r.endPK, err = keyToDatums(endKey, execCfg.Codec, pkTypes, &alloc); err != nil {
+				return err
+			}
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			if !ri.NeedAnother(rs) {
+				break
+			}
+		}
+		return ri.Error()
+	}(); err != nil {
+		return errors.CombineErrors(err, g.Wait())
+	}
+	return g.Wait()
+}
+
+// runTTLOnRange deletes the expired rows in the given range, returning the
+// number of rows deleted.
+func runTTLOnRange(
+	ctx context.Context,
+	execCfg *sql.ExecutorConfig,
+	ie sqlutil.InternalExecutor,
+	metrics *RowLevelTTLMetrics,
+	deleteRateLimiter *quotapool.RateLimiter,
+	r rangeToProcess,
+	tableName string,
+	pkColumns []string,
+	aost, cutoff time.Time,
+	selectBatchSize, deleteBatchSize int,
+) (int64, error) {
+	selectBuilder := makeSelectQueryBuilder(
+		tableName,
+		pkColumns,
+		r.startPK,
+		r.endPK,
+		aost,
+		cutoff,
+		selectBatchSize,
+	)
+	deleteBuilder := makeDeleteQueryBuilder(tableName, pkColumns, cutoff)
+
+	var rowCount int64
+	for {
+		start := timeutil.Now()
+		expiredRowsPKs, err := selectBuilder.run(ctx, ie)
+		metrics.SelectDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+		if err != nil {
+			return rowCount, err
+		}
+		metrics.RowSelections.Inc(int64(len(expiredRowsPKs)))
+
+		for startRowIdx := 0; startRowIdx < len(expiredRowsPKs); startRowIdx += deleteBatchSize {
+			until := startRowIdx + deleteBatchSize
+			if until > len(expiredRowsPKs) {
+				until = len(expiredRowsPKs)
+			}
+			deleteBatch := expiredRowsPKs[startRowIdx:until]
+			// Acquire the quota for the whole batch before the transaction
+			// starts, so that the transaction is not held open while waiting
+			// for quota, and retries of the transaction don't consume quota
+			// again.
+			if err := deleteRateLimiter.WaitN(ctx, int64(len(deleteBatch))); err != nil {
+				return rowCount, err
+			}
+			var numDeleted int
+			if err := execCfg.DB.Txn(ctx, func(ctx context.Context, txn *kv.Txn) error {
+				start := timeutil.Now()
+				var err error
+				numDeleted, err = deleteBuilder.run(ctx, ie, txn, deleteBatch)
+				metrics.DeleteDuration.RecordValue(timeutil.Since(start).Nanoseconds())
+				return err
+			}); err != nil {
+				return rowCount, errors.Wrapf(err, "error during row deletion")
+			}
+			metrics.RowDeletions.Inc(int64(numDeleted))
+			rowCount += int64(numDeleted)
+		}
+
+		// If we selected less than the batch size, we have selected every row.
+		if len(expiredRowsPKs) < selectBatchSize {
+			break
+		}
+	}
+	return rowCount, nil
+}
+
+// updateRowCount adds the given number of rows to the progress of the job.
+func (t rowLevelTTLResumer) updateRowCount(ctx context.Context, rowCount int64) error {
+	if rowCount == 0 {
+		return nil
+	}
+	return t.job.Update(ctx, nil /* txn */, func(
+		txn *kv.Txn, md jobs.JobMetadata, ju *jobs.JobUpdater,
+	) error {
+		progress := md.Progress
+		progress.Details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL.RowCount += rowCount
+		ju.UpdateProgress(progress)
+		return nil
+	})
+}
+
+// keyToDatums translates a key at the boundary of a range into the datums of
+// the (possibly partial) primary key it corresponds to. Keys which do not
+// contain any primary key columns, such as the start or end of the index,
+// return nil.
+func keyToDatums(
+	key roachpb.RKey, codec keys.SQLCodec, pkTypes []*types.T, alloc *rowenc.DatumAlloc,
+) (tree.Datums, error) {
+	rKey, err := codec.StripTenantPrefix(key.AsRawKey())
+	if err != nil {
+		return nil, nil //nolint:returnerrcheck
+	}
+	rKey, _, _, err = rowenc.DecodePartialTableIDIndexID(rKey)
+	if err != nil {
+		return nil, nil //nolint:returnerrcheck
+	}
+	// Decode the datums ourselves, as the key of a range may only contain a
+	// prefix of the primary key (e.g. a PK (a, b) may only be split on (a)).
+	// All primary key columns are ascending, as validated on the descriptor.
+	encDatums := make([]rowenc.EncDatum, 0, len(pkTypes))
+	for len(rKey) > 0 && len(encDatums) < len(pkTypes) {
+		var val rowenc.EncDatum
+		val, rKey, err = rowenc.EncDatumFromBuffer(
+			pkTypes[len(encDatums)], descpb.DatumEncoding_ASCENDING_KEY, rKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+		encDatums = append(encDatums, val)
+	}
+	if len(encDatums) == 0 {
+		return nil, nil
+	}
+	datums := make(tree.Datums, len(encDatums))
+	for i, encDatum := range encDatums {
+		if err := encDatum.EnsureDecoded(pkTypes[i], alloc); err != nil {
+			return nil, err
+		}
+		datums[i] = encDatum.Datum
+	}
+	return datums, nil
+}
+
+func getSelectBatchSize(sv *settings.Values, ttl descpb.TableDescriptor_RowLevelTTL) int {
+	if bs := ttl.SelectBatchSize; bs != 0 {
+		return int(bs)
+	}
+	return int(defaultSelectBatchSize.Get(sv))
+}
+
+func getDeleteBatchSize(sv *settings.Values, ttl descpb.TableDescriptor_RowLevelTTL) int {
+	if bs := ttl.DeleteBatchSize; bs != 0 {
+		return int(bs)
+	}
+	return int(defaultDeleteBatchSize.Get(sv))
+}
+
+func getRangeConcurrency(sv *settings.Values, ttl descpb.TableDescriptor_RowLevelTTL) int {
+	if rc := ttl.RangeConcurrency; rc != 0 {
+		return int(rc)
+	}
+	return int(defaultRangeConcurrency.Get(sv))
+}
+
+func getDeleteRateLimit(sv *settings.Values, ttl descpb.TableDescriptor_RowLevelTTL) int64 {
+	val := func() int64 {
+		if rl := ttl.DeleteRateLimit; rl != 0 {
+			return rl
+		}
+		return defaultDeleteRateLimit.Get(sv)
+	}()
+	// Treat 0 as no limit.
+	if val == 0 {
+		return math.MaxInt64
+	}
+	return val
+}
+
+// OnFailOrCancel implements the jobs.Resumer interface.
+func (t rowLevelTTLResumer) OnFailOrCancel(ctx context.Context, execCtx interface{}) error {
+	return nil
+}
+
+func init() {
+	jobs.RegisterConstructor(jobspb.TypeRowLevelTTL, func(job *jobs.Job, settings *cluster.Settings) jobs.Resumer {
+		return &rowLevelTTLResumer{
+			job: job,
+			st:  settings,
+		}
+	})
+}
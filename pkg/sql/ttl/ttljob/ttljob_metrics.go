@@ -0,0 +1,99 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+)
+
+// RowLevelTTLMetrics are the metrics associated with the row-level TTL job.
+type RowLevelTTLMetrics struct {
+	RangeTotalDuration *metric.Histogram
+	SelectDuration     *metric.Histogram
+	DeleteDuration     *metric.Histogram
+	RowSelections      *metric.Counter
+	RowDeletions       *metric.Counter
+	NumActiveRanges    *metric.Gauge
+}
+
+// MetricStruct implements the metric.Struct interface.
+func (m *RowLevelTTLMetrics) MetricStruct() {}
+
+var _ metric.Struct = (*RowLevelTTLMetrics)(nil)
+
+func makeRowLevelTTLMetrics(histogramWindowInterval time.Duration) metric.Struct {
+	return &RowLevelTTLMetrics{
+		RangeTotalDuration: metric.NewHistogram(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.range_total_duration",
+				Help:        "Duration for processing a range during row level TTL.",
+				Measurement: "nanoseconds",
+				Unit:        metric.Unit_NANOSECONDS,
+			},
+			histogramWindowInterval,
+			time.Hour.Nanoseconds(),
+			3,
+		),
+		SelectDuration: metric.NewHistogram(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.select_duration",
+				Help:        "Duration for select requests during row level TTL.",
+				Measurement: "nanoseconds",
+				Unit:        metric.Unit_NANOSECONDS,
+			},
+			histogramWindowInterval,
+			time.Minute.Nanoseconds(),
+			1,
+		),
+		DeleteDuration: metric.NewHistogram(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.delete_duration",
+				Help:        "Duration for delete requests during row level TTL.",
+				Measurement: "nanoseconds",
+				Unit:        metric.Unit_NANOSECONDS,
+			},
+			histogramWindowInterval,
+			time.Minute.Nanoseconds(),
+			1,
+		),
+		RowSelections: metric.NewCounter(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.rows_selected",
+				Help:        "Number of rows selected for deletion by the row level TTL job.",
+				Measurement: "num_rows",
+				Unit:        metric.Unit_COUNT,
+			},
+		),
+		RowDeletions: metric.NewCounter(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.rows_deleted",
+				Help:        "Number of rows deleted by the row level TTL job.",
+				Measurement: "num_rows",
+				Unit:        metric.Unit_COUNT,
+			},
+		),
+		NumActiveRanges: metric.NewGauge(
+			metric.Metadata{
+				Name:        "jobs.row_level_ttl.num_active_ranges",
+				Help:        "Number of active workers attempting to delete for row level TTL.",
+				Measurement: "num_active_workers",
+				Unit:        metric.Unit_COUNT,
+			},
+		),
+	}
+}
+
+func init() {
+	jobs.MakeRowLevelTTLMetricsHook = makeRowLevelTTLMetrics
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/colinfo"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sessiondata"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/errors"
+)
+
+// selectQueryBuilder is responsible for maintaining state around the SELECT
+// portion of the TTL job, paginating through the expired rows of a range.
+type selectQueryBuilder struct {
+	tableName string
+	pkColumns []string
+	startPK   tree.Datums
+	endPK     tree.Datums
+	aost      time.Time
+	cutoff    time.Time
+	batchSize int
+
+	// lastPK is the primary key of the last row returned by the previous
+	// page, if any. Subsequent pages start strictly after it.
+	lastPK tree.Datums
+}
+
+func makeSelectQueryBuilder(
+	tableName string,
+	pkColumns []string,
+	startPK, endPK tree.Datums,
+	aost, cutoff time.Time,
+	batchSize int,
+) selectQueryBuilder {
+	return selectQueryBuilder{
+		tableName: tableName,
+		pkColumns: pkColumns,
+		startPK:   startPK,
+		endPK:     endPK,
+		aost:      aost,
+		cutoff:    cutoff,
+		batchSize: batchSize,
+	}
+}
+
+// buildQuery returns the SELECT statement and its arguments for the next
+// page of expired rows.
+func (b *selectQueryBuilder) buildQuery() (string, []interface{}) {
+	var buf bytes.Buffer
+	args := []interface{}{tree.MustMakeDTimestampTZ(b.cutoff, time.Microsecond)}
+	pkColumnsSQL := makeColumnNamesSQL(b.pkColumns)
+	fmt.Fprintf(
+		&buf,
+		"SELECT %s FROM %s AS OF SYSTEM TIME %d WHERE %s <= $1",
+		pkColumnsSQL,
+		b.tableName,
+		b.aost.UnixNano(),
+		tree.NameString(colinfo.TTLDefaultExpirationColumnName),
+	)
+	if b.lastPK != nil {
+		args = writeTupleComparison(&buf, b.pkColumns, ">", b.lastPK, args)
+	} else if len(b.startPK) > 0 {
+		args = writeTupleComparison(&buf, b.pkColumns, ">=", b.startPK, args)
+	}
+	if len(b.endPK) > 0 {
+		args = writeTupleComparison(&buf, b.pkColumns, "<", b.endPK, args)
+	}
+	fmt.Fprintf(&buf, " ORDER BY %s LIMIT %d", pkColumnsSQL, b.batchSize)
+	return buf.String(), args
+}
+
+// run fetches the next page of expired rows. An empty result means the range
+// has been exhausted.
+func (b *selectQueryBuilder) run(
+	ctx context.Context, ie sqlutil.InternalExecutor,
+) ([]tree.Datums, error) {
+	query, args := b.buildQuery()
+	rows, err := ie.QueryBufferedEx(
+		ctx,
+		"ttl_select",
+		nil, /* txn */
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		query,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error selecting rows to delete")
+	}
+	if len(rows) > 0 {
+		b.lastPK = rows[len(rows)-1]
+	}
+	return rows, nil
+}
+
+// deleteQueryBuilder is responsible for building the DELETE statements which
+// remove the expired rows found by a selectQueryBuilder.
+type deleteQueryBuilder struct {
+	tableName string
+	pkColumns []string
+	cutoff    time.Time
+}
+
+func makeDeleteQueryBuilder(
+	tableName string, pkColumns []string, cutoff time.Time,
+) deleteQueryBuilder {
+	return deleteQueryBuilder{
+		tableName: tableName,
+		pkColumns: pkColumns,
+		cutoff:    cutoff,
+	}
+}
+
+// buildQuery returns the DELETE statement and its arguments for the given
+// primary keys. The expiration is checked again so that rows which have been
+// updated since they were selected are not deleted.
+func (b *deleteQueryBuilder) buildQuery(rows []tree.Datums) (string, []interface{}) {
+	var buf bytes.Buffer
+	args := make([]interface{}, 0, 1+len(rows)*len(b.pkColumns))
+	args = append(args, tree.MustMakeDTimestampTZ(b.cutoff, time.Microsecond))
+	fmt.Fprintf(
+		&buf,
+		"DELETE FROM %s WHERE %s <= $1 AND (%s) IN (",
+		b.tableName,
+		tree.NameString(colinfo.TTLDefaultExpirationColumnName),
+		makeColumnNamesSQL(b.pkColumns),
+	)
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("(")
+		for j, d := range row {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			args = append(args, d)
+			fmt.Fprintf(&buf, "$%d", len(args))
+		}
+		buf.WriteString(")")
+	}
+	buf.WriteString(")")
+	return buf.String(), args
+}
+
+// run deletes the given rows, returning the number of rows deleted.
+func (b *deleteQueryBuilder) run(
+	ctx context.Context, ie sqlutil.InternalExecutor, txn *kv.Txn, rows []tree.Datums,
+) (int, error) {
+	query, args := b.buildQuery(rows)
+	return ie.ExecEx(
+		ctx,
+		"ttl_delete",
+		txn,
+		sessiondata.InternalExecutorOverride{User: security.RootUserName()},
+		query,
+		args...,
+	)
+}
+
+// writeTupleComparison writes a comparison between the leading columns of
+// the primary key and the given datums, e.g. `AND (a, b) > ($2, $3)`, and
+// returns the args with the datums appended.
+func writeTupleComparison(
+	buf *bytes.Buffer, pkColumns []string, op string, datums tree.Datums, args []interface{},
+) []interface{} {
+	fmt.Fprintf(buf, " AND (%s) %s (", makeColumnNamesSQL(pkColumns[:len(datums)]), op)
+	for i, d := range datums {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		args = append(args, d)
+		fmt.Fprintf(buf, "$%d", len(args))
+	}
+	buf.WriteString(")")
+	return args
+}
+
+func makeColumnNamesSQL(columns []string) string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = tree.NameString(col)
+	}
+	return strings.Join(names, ", ")
+}
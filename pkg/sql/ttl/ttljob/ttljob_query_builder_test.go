@@ -0,0 +1,119 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectQueryBuilder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mockTime := time.Date(2000, 1, 1, 13, 30, 45, 0, time.UTC)
+	mockTimestampTZ := tree.MustMakeDTimestampTZ(mockTime, time.Microsecond)
+
+	b := makeSelectQueryBuilder(
+		"relation_name",
+		[]string{"col1", "col2"},
+		tree.Datums{tree.NewDInt(100)},
+		tree.Datums{tree.NewDInt(200), tree.NewDInt(5)},
+		mockTime,
+		mockTime,
+		2,
+	)
+
+	// The first page is bounded by the start and end of the range.
+	query, args := b.buildQuery()
+	require.Equal(
+		t,
+		`SELECT col1, col2 FROM relation_name AS OF SYSTEM TIME 946733445000000000 `+
+			`WHERE crdb_internal_expiration <= $1 AND (col1) >= ($2) AND (col1, col2) < ($3, $4) `+
+			`ORDER BY col1, col2 LIMIT 2`,
+		query,
+	)
+	require.Equal(
+		t,
+		[]interface{}{mockTimestampTZ, tree.NewDInt(100), tree.NewDInt(200), tree.NewDInt(5)},
+		args,
+	)
+
+	// Subsequent pages start after the last row returned.
+	b.lastPK = tree.Datums{tree.NewDInt(150), tree.NewDInt(1)}
+	query, args = b.buildQuery()
+	require.Equal(
+		t,
+		`SELECT col1, col2 FROM relation_name AS OF SYSTEM TIME 946733445000000000 `+
+			`WHERE crdb_internal_expiration <= $1 AND (col1, col2) > ($2, $3) AND (col1, col2) < ($4, $5) `+
+			`ORDER BY col1, col2 LIMIT 2`,
+		query,
+	)
+	require.Equal(
+		t,
+		[]interface{}{
+			mockTimestampTZ,
+			tree.NewDInt(150), tree.NewDInt(1),
+			tree.NewDInt(200), tree.NewDInt(5),
+		},
+		args,
+	)
+
+	// An unbounded range has no primary key conditions.
+	b = makeSelectQueryBuilder(
+		"relation_name",
+		[]string{"col1"},
+		nil,
+		nil,
+		mockTime,
+		mockTime,
+		2,
+	)
+	query, args = b.buildQuery()
+	require.Equal(
+		t,
+		`SELECT col1 FROM relation_name AS OF SYSTEM TIME 946733445000000000 `+
+			`WHERE crdb_internal_expiration <= $1 ORDER BY col1 LIMIT 2`,
+		query,
+	)
+	require.Equal(t, []interface{}{mockTimestampTZ}, args)
+}
+
+func TestDeleteQueryBuilder(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+
+	mockTime := time.Date(2000, 1, 1, 13, 30, 45, 0, time.UTC)
+	mockTimestampTZ := tree.MustMakeDTimestampTZ(mockTime, time.Microsecond)
+
+	b := makeDeleteQueryBuilder("relation_name", []string{"col1", "col2"}, mockTime)
+	query, args := b.buildQuery([]tree.Datums{
+		{tree.NewDInt(10), tree.NewDInt(15)},
+		{tree.NewDInt(12), tree.NewDInt(16)},
+	})
+	require.Equal(
+		t,
+		`DELETE FROM relation_name WHERE crdb_internal_expiration <= $1 `+
+			`AND (col1, col2) IN (($2, $3), ($4, $5))`,
+		query,
+	)
+	require.Equal(
+		t,
+		[]interface{}{
+			mockTimestampTZ,
+			tree.NewDInt(10), tree.NewDInt(15),
+			tree.NewDInt(12), tree.NewDInt(16),
+		},
+		args,
+	)
+}
@@ -0,0 +1,108 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttljob_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/base"
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/testutils/serverutils"
+	"github.com/cockroachdb/cockroach/pkg/testutils/sqlutils"
+	"github.com/cockroachdb/cockroach/pkg/util/leaktest"
+	"github.com/cockroachdb/cockroach/pkg/util/log"
+	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowLevelTTLJob(t *testing.T) {
+	defer leaktest.AfterTest(t)()
+	defer log.Scope(t).Close(t)
+
+	ctx := context.Background()
+	// Read the rows at the current time, so that the rows inserted by the test
+	// are visible to the job.
+	zeroDuration := time.Duration(0)
+	s, db, _ := serverutils.StartServer(t, base.TestServerArgs{
+		Knobs: base.TestingKnobs{
+			JobsTestingKnobs: jobs.NewTestingKnobsWithShortIntervals(),
+			TTL:              &sql.TTLTestingKnobs{AOSTDuration: &zeroDuration},
+		},
+	})
+	defer s.Stopper().Stop(ctx)
+
+	sqlDB := sqlutils.MakeSQLRunner(db)
+	sqlDB.Exec(t, `CREATE TABLE t (
+	id INT,
+	other INT,
+	PRIMARY KEY (id, other)
+) WITH (
+	ttl_expire_after = '10 minutes',
+	ttl_select_batch_size = 3,
+	ttl_delete_batch_size = 2,
+	ttl_range_concurrency = 2
+)`)
+	// Split the table into several ranges, including on a partial primary key.
+	sqlDB.Exec(t, `ALTER TABLE t SPLIT AT VALUES (10)`)
+	sqlDB.Exec(t, `ALTER TABLE t SPLIT AT VALUES (20, 5)`)
+	// Expire every row with an even id.
+	sqlDB.Exec(t, `INSERT INTO t (id, other, crdb_internal_expiration)
+SELECT
+	i,
+	i % 7,
+	CASE WHEN i % 2 = 0 THEN now() - '1 hour'::INTERVAL ELSE now() + '1 hour'::INTERVAL END
+FROM generate_series(1, 30) AS g(i)`)
+
+	var tableID descpb.ID
+	sqlDB.QueryRow(t, `SELECT 't'::regclass::oid`).Scan(&tableID)
+
+	execCfg := s.ExecutorConfig().(sql.ExecutorConfig)
+	record := jobs.Record{
+		Description: "ttl for t",
+		Username:    security.RootUserName(),
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: tableID,
+			Cutoff:  timeutil.Now(),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+	}
+	job, err := execCfg.JobRegistry.CreateAdoptableJobWithTxn(
+		ctx, record, execCfg.JobRegistry.MakeJobID(), nil, /* txn */
+	)
+	require.NoError(t, err)
+
+	sqlDB.CheckQueryResultsRetry(
+		t,
+		fmt.Sprintf(`SELECT status FROM [SHOW JOBS] WHERE job_id = %d`, job.ID()),
+		[][]string{{"succeeded"}},
+	)
+
+	sqlDB.CheckQueryResults(
+		t,
+		`SELECT count(1), count(1) FILTER (WHERE id % 2 = 0) FROM t`,
+		[][]string{{"15", "0"}},
+	)
+
+	job, err = execCfg.JobRegistry.LoadJob(ctx, job.ID())
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		int64(15),
+		job.Progress().Details.(*jobspb.Progress_RowLevelTTL).RowLevelTTL.RowCount,
+	)
+}
@@ -0,0 +1,220 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package ttlschedule
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/cockroach/pkg/jobs"
+	"github.com/cockroachdb/cockroach/pkg/jobs/jobspb"
+	"github.com/cockroachdb/cockroach/pkg/kv"
+	"github.com/cockroachdb/cockroach/pkg/scheduledjobs"
+	"github.com/cockroachdb/cockroach/pkg/security"
+	"github.com/cockroachdb/cockroach/pkg/sql"
+	"github.com/cockroachdb/cockroach/pkg/sql/catalog/descpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/sql/sem/tree"
+	"github.com/cockroachdb/cockroach/pkg/sql/sqlutil"
+	"github.com/cockroachdb/cockroach/pkg/util/metric"
+	"github.com/cockroachdb/errors"
+	pbtypes "github.com/gogo/protobuf/types"
+)
+
+type rowLevelTTLExecutor struct {
+	metrics rowLevelTTLMetric
+}
+
+var _ jobs.ScheduledJobExecutor = (*rowLevelTTLExecutor)(nil)
+var _ jobs.ScheduledJobController = (*rowLevelTTLExecutor)(nil)
+
+type rowLevelTTLMetric struct {
+	*jobs.ExecutorMetrics
+}
+
+var _ metric.Struct = &rowLevelTTLMetric{}
+
+// MetricStruct implements metric.Struct interface.
+func (m *rowLevelTTLMetric) MetricStruct() {}
+
+// OnDrop implements the jobs.ScheduledJobController interface.
+func (s *rowLevelTTLExecutor) OnDrop(
+	ctx context.Context,
+	scheduleControllerEnv scheduledjobs.ScheduleControllerEnv,
+	env scheduledjobs.JobSchedulerEnv,
+	schedule *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	return errors.WithHintf(
+		pgerror.Newf(
+			pgcode.InvalidTableDefinition,
+			"cannot drop a row level TTL schedule",
+		),
+		`use ALTER TABLE ... RESET (ttl) on the table associated with the schedule instead`,
+	)
+}
+
+// ExecuteJob implements the jobs.ScheduledJobExecutor interface.
+func (s *rowLevelTTLExecutor) ExecuteJob(
+	ctx context.Context,
+	cfg *scheduledjobs.JobExecutionConfig,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	txn *kv.Txn,
+) error {
+	args := &jobspb.ScheduledRowLevelTTLArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return err
+	}
+
+	p, cleanup := cfg.PlanHookMaker(
+		fmt.Sprintf("invoke-row-level-ttl-%d", args.TableID),
+		txn,
+		security.NodeUserName(),
+	)
+	defer cleanup()
+
+	execCfg := p.(sql.PlanHookState).ExecCfg()
+	tn, err := getTableName(ctx, execCfg, txn, args.TableID)
+	if err != nil {
+		s.metrics.NumFailed.Inc(1)
+		return err
+	}
+	record := jobs.Record{
+		Description: fmt.Sprintf("ttl for %s", tn.FQString()),
+		Username:    security.NodeUserName(),
+		Details: jobspb.RowLevelTTLDetails{
+			TableID: args.TableID,
+			Cutoff:  env.Now(),
+		},
+		Progress: jobspb.RowLevelTTLProgress{},
+		CreatedBy: &jobs.CreatedByInfo{
+			ID:   sj.ScheduleID(),
+			Name: jobs.CreatedByScheduledJobs,
+		},
+	}
+
+	jobRegistry := execCfg.JobRegistry
+	if _, err := jobRegistry.CreateAdoptableJobWithTxn(
+		ctx,
+		record,
+		jobRegistry.MakeJobID(),
+		txn,
+	); err != nil {
+		s.metrics.NumFailed.Inc(1)
+		return err
+	}
+	s.metrics.NumStarted.Inc(1)
+	return nil
+}
+
+// NotifyJobTermination implements the jobs.ScheduledJobExecutor interface.
+func (s *rowLevelTTLExecutor) NotifyJobTermination(
+	ctx context.Context,
+	jobID jobspb.JobID,
+	jobStatus jobs.Status,
+	details jobspb.Details,
+	env scheduledjobs.JobSchedulerEnv,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+	txn *kv.Txn,
+) error {
+	if jobStatus == jobs.StatusFailed {
+		jobs.DefaultHandleFailedRun(
+			sj,
+			"row level ttl for table [%d] job failed",
+			details.(jobspb.RowLevelTTLDetails).TableID,
+		)
+		s.metrics.NumFailed.Inc(1)
+		return nil
+	}
+
+	if jobStatus == jobs.StatusSucceeded {
+		s.metrics.NumSucceeded.Inc(1)
+	}
+
+	sj.SetScheduleStatus(string(jobStatus))
+	return nil
+}
+
+// Metrics implements the jobs.ScheduledJobExecutor interface.
+func (s *rowLevelTTLExecutor) Metrics() metric.Struct {
+	return &s.metrics
+}
+
+// GetCreateScheduleStatement implements the jobs.ScheduledJobExecutor interface.
+func (s *rowLevelTTLExecutor) GetCreateScheduleStatement(
+	ctx context.Context,
+	env scheduledjobs.JobSchedulerEnv,
+	txn *kv.Txn,
+	sj *jobs.ScheduledJob,
+	ex sqlutil.InternalExecutor,
+) (string, error) {
+	args := &jobspb.ScheduledRowLevelTTLArgs{}
+	if err := pbtypes.UnmarshalAny(sj.ExecutionArgs().Args, args); err != nil {
+		return "", err
+	}
+	// The schedule is created alongside the table, so there is no statement
+	// which creates it directly.
+	return fmt.Sprintf(
+		"ALTER TABLE [%d AS t] SET (ttl = 'on', ttl_job_cron = %s)",
+		args.TableID,
+		tree.NewDString(sj.ScheduleExpr()).String(),
+	), nil
+}
+
+// getTableName returns the fully qualified name of the table with the
+// given ID.
+func getTableName(
+	ctx context.Context, execCfg *sql.ExecutorConfig, txn *kv.Txn, tableID descpb.ID,
+) (*tree.TableName, error) {
+	col := execCfg.CollectionFactory.NewCollection(nil /* temporarySchemaProvider */)
+	defer col.ReleaseAll(ctx)
+
+	desc, err := col.GetImmutableTableByID(
+		ctx, txn, tableID, tree.ObjectLookupFlagsWithRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, dbDesc, err := col.GetImmutableDatabaseByID(
+		ctx, txn, desc.GetParentID(), tree.DatabaseLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	scDesc, err := col.GetImmutableSchemaByID(
+		ctx, txn, desc.GetParentSchemaID(), tree.SchemaLookupFlags{Required: true},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tree.NewTableNameWithSchema(
+		tree.Name(dbDesc.GetName()),
+		tree.Name(scDesc.GetName()),
+		tree.Name(desc.GetName()),
+	), nil
+}
+
+func init() {
+	jobs.RegisterScheduledJobExecutorFactory(
+		tree.ScheduledRowLevelTTLExecutor.InternalName(),
+		func() (jobs.ScheduledJobExecutor, error) {
+			m := jobs.MakeExecutorMetrics(tree.ScheduledRowLevelTTLExecutor.InternalName())
+			return &rowLevelTTLExecutor{
+				metrics: rowLevelTTLMetric{
+					ExecutorMetrics: &m,
+				},
+			}, nil
+		},
+	)
+}
@@ -21,7 +21,17 @@ const regroleTypeVersion = clusterversion.MarkerDataKeysRegistry
 // Note: please do not remove this map or IsTypeSupportedInVersion even
 // if the map becomes empty temporarily.
 var minimumTypeUsageVersions = map[*T]clusterversion.Key{
-	RegRole: regroleTypeVersion,
+	RegRole:  regroleTypeVersion,
+	TSVector: clusterversion.TextSearchTypes,
+	TSQuery:  clusterversion.TextSearchTypes,
+	Jsonpath: clusterversion.JsonpathType,
+
+	Int4Range: clusterversion.RangeTypes,
+	Int8Range: clusterversion.RangeTypes,
+	NumRange:  clusterversion.RangeTypes,
+	TSRange:   clusterversion.RangeTypes,
+	TSTZRange: clusterversion.RangeTypes,
+	DateRange: clusterversion.RangeTypes,
 }
 
 // IsTypeSupportedInVersion returns whether a given type is supported in the given version.
@@ -34,6 +34,16 @@ func TestIsTypeSupportedInVersion(t *testing.T) {
 		{regroleTypeVersion - 1, RegRole, false},
 		{regroleTypeVersion, MakeArray(RegRole), true},
 		{regroleTypeVersion - 1, MakeArray(RegRole), false},
+		{clusterversion.TextSearchTypes, TSVector, true},
+		{clusterversion.TextSearchTypes - 1, TSVector, false},
+		{clusterversion.TextSearchTypes, MakeArray(TSQuery), true},
+		{clusterversion.TextSearchTypes - 1, MakeArray(TSQuery), false},
+		{clusterversion.JsonpathType, Jsonpath, true},
+		{clusterversion.JsonpathType - 1, Jsonpath, false},
+		{clusterversion.RangeTypes, Int8Range, true},
+		{clusterversion.RangeTypes - 1, Int8Range, false},
+		{clusterversion.RangeTypes, MakeArray(DateRange), true},
+		{clusterversion.RangeTypes - 1, MakeArray(DateRange), false},
 	}
 
 	for _, tc := range testCases {
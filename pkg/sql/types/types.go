@@ -196,6 +196,9 @@ type UserDefinedTypeMetadata struct {
 
 	// enumData is non-nil iff the metadata is for an ENUM type.
 	EnumData *EnumMetadata
+
+	// DomainData is non-nil iff the metadata is for a DOMAIN type.
+	DomainData *DomainMetadata
 }
 
 // EnumMetadata is metadata about an ENUM needed for evaluation.
@@ -222,6 +225,17 @@ func (e *EnumMetadata) debugString() string {
 	)
 }
 
+// DomainMetadata is metadata about a DOMAIN needed for evaluation.
+type DomainMetadata struct {
+	// NotNull is true if the domain does not allow NULL values.
+	NotNull bool
+	// CheckNames holds the names of the CHECK constraints of the domain.
+	CheckNames []string
+	// CheckExprs holds the serialized expressions of the CHECK constraints of
+	// the domain, in which the value being checked is referred to as VALUE.
+	CheckExprs []string
+}
+
 // UserDefinedTypeName is a struct representing a qualified user defined
 // type name. We redefine a common struct from higher level packages. We
 // do so because proto will panic if any members of a proto struct are
@@ -454,6 +468,99 @@ var (
 		},
 	}
 
+	// TSVector is the type of a document preprocessed for full text search.
+	TSVector = &T{
+		InternalType: InternalType{
+			Family: TSVectorFamily,
+			Oid:    oid.T_tsvector,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSQuery is the type of a full text search query.
+	TSQuery = &T{
+		InternalType: InternalType{
+			Family: TSQueryFamily,
+			Oid:    oid.T_tsquery,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Jsonpath is the type of a SQL/JSON path expression, which selects items
+	// of a JSON value.
+	Jsonpath = &T{
+		InternalType: InternalType{
+			Family: JsonpathFamily,
+			Oid:    oidext.T_jsonpath,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int4Range is the type of a range of Int4 values.
+	Int4Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int4range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Int8Range is the type of a range of Int values.
+	Int8Range = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_int8range,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// NumRange is the type of a range of Decimal values.
+	NumRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_numrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSRange is the type of a range of Timestamp values.
+	TSRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tsrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// TSTZRange is the type of a range of TimestampTZ values.
+	TSTZRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_tstzrange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// DateRange is the type of a range of Date values.
+	DateRange = &T{
+		InternalType: InternalType{
+			Family: RangeFamily,
+			Oid:    oid.T_daterange,
+			Locale: &emptyLocale,
+		},
+	}
+
+	// Ranges contains all of the built-in range types. RangeFamily has no
+	// canonical type, so these are not included in Scalar.
+	Ranges = []*T{
+		Int4Range,
+		Int8Range,
+		NumRange,
+		TSRange,
+		TSTZRange,
+		DateRange,
+	}
+
 	// Scalar contains all types that meet this criteria:
 	//
 	//   1. Scalar type (no ArrayFamily or TupleFamily types).
@@ -481,6 +588,9 @@ var (
 		TimeTZ,
 		Jsonb,
 		VarBit,
+		TSVector,
+		TSQuery,
+		Jsonpath,
 	}
 
 	// Any is a special type used only during static analysis as a wildcard type
@@ -503,6 +613,12 @@ var (
 	AnyEnum = &T{InternalType: InternalType{
 		Family: EnumFamily, Locale: &emptyLocale, Oid: oid.T_anyenum}}
 
+	// AnyRange is a special type only used during static analysis as a
+	// wildcard type that matches any range value. Execution-time values should
+	// never have this type.
+	AnyRange = &T{InternalType: InternalType{
+		Family: RangeFamily, Locale: &emptyLocale, Oid: oid.T_anyrange}}
+
 	// AnyTuple is a special type used only during static analysis as a wildcard
 	// type that matches a tuple with any number of fields of any type (including
 	// tuple types). Execution-time values should never have this type.
@@ -1055,6 +1171,33 @@ func MakeEnum(typeOID, arrayTypeOID oid.Oid) *T {
 	}}
 }
 
+// MakeDomain constructs a new instance of a domain type over the given base
+// type, with the given stable type ID. The domain has the same family and
+// attributes as its base type. Note that it does not hydrate cached fields on
+// the type.
+func MakeDomain(typeOID, arrayTypeOID oid.Oid, base *T) *T {
+	baseOID := base.Oid()
+	internalType := base.InternalType
+	internalType.Oid = typeOID
+	internalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID:  arrayTypeOID,
+		DomainBaseOID: &baseOID,
+	}
+	return &T{InternalType: internalType}
+}
+
+// MakeComposite constructs a new instance of a user defined composite type,
+// which is a labeled tuple with the given stable type ID. Note that it does
+// not hydrate cached fields on the type.
+func MakeComposite(typeOID, arrayTypeOID oid.Oid, contents []*T, labels []string) *T {
+	typ := MakeLabeledTuple(contents, labels)
+	typ.InternalType.Oid = typeOID
+	typ.InternalType.UDTMetadata = &PersistentUserDefinedTypeMetadata{
+		ArrayTypeOID: arrayTypeOID,
+	}
+	return typ
+}
+
 // MakeArray constructs a new instance of an ArrayFamily type with the given
 // element type (which may itself be an ArrayFamily type).
 func MakeArray(typ *T) *T {
@@ -1194,8 +1337,9 @@ func (t *T) TypeModifier() int32 {
 	if t.Family() == ArrayFamily {
 		return t.ArrayContents().TypeModifier()
 	}
-	// The type modifier for "char" is always -1.
-	if t.Oid() == oid.T_char {
+	// The type modifier for "char" is always -1. Domains record the type
+	// modifier of their base type on the domain itself, so it is -1 as well.
+	if t.Oid() == oid.T_char || t.IsDomain() {
 		return int32(-1)
 	}
 
@@ -1247,6 +1391,29 @@ func (t *T) TupleLabels() []string {
 	return t.InternalType.TupleLabels
 }
 
+// RangeContents returns the type of the bounds of a range. This is nil for
+// types that are not in the RangeFamily, and for the AnyRange wildcard.
+func (t *T) RangeContents() *T {
+	if t.Family() != RangeFamily {
+		return nil
+	}
+	switch t.Oid() {
+	case oid.T_int4range:
+		return Int4
+	case oid.T_int8range:
+		return Int
+	case oid.T_numrange:
+		return Decimal
+	case oid.T_tsrange:
+		return Timestamp
+	case oid.T_tstzrange:
+		return TimestampTZ
+	case oid.T_daterange:
+		return Date
+	}
+	return nil
+}
+
 // UserDefinedArrayOID returns the OID of the array type that corresponds to
 // this user defined type. This function only can only be called on user
 // defined types and returns non-zero data only for user defined types that
@@ -1258,6 +1425,39 @@ func (t *T) UserDefinedArrayOID() oid.Oid {
 	return t.InternalType.UDTMetadata.ArrayTypeOID
 }
 
+// IsDomain returns whether or not t is a domain type.
+func (t *T) IsDomain() bool {
+	return t.InternalType.UDTMetadata != nil && t.InternalType.UDTMetadata.DomainBaseOID != nil
+}
+
+// IsComposite returns whether or not t is a user defined composite type.
+// Unlike the implicit record types of tables, composite types have their own
+// type descriptor and array type.
+func (t *T) IsComposite() bool {
+	return t.Family() == TupleFamily && t.InternalType.UDTMetadata != nil
+}
+
+// DomainBaseType returns the base type of a domain type. It must only be
+// called on domain types.
+func (t *T) DomainBaseType() *T {
+	internalType := t.InternalType
+	internalType.Oid = *t.InternalType.UDTMetadata.DomainBaseOID
+	internalType.UDTMetadata = nil
+	return &T{InternalType: internalType}
+}
+
+// withDomainBaseType runs fn on the domain type t as if it were its base type,
+// and then restores the OID and metadata of the domain. It is used to reuse
+// the base type's serialization logic, since domains share the representation
+// of their base type.
+func (t *T) withDomainBaseType(fn func(*T) error) error {
+	domainOID, udtMetadata := t.InternalType.Oid, t.InternalType.UDTMetadata
+	t.InternalType.Oid, t.InternalType.UDTMetadata = *udtMetadata.DomainBaseOID, nil
+	err := fn(t)
+	t.InternalType.Oid, t.InternalType.UDTMetadata = domainOID, udtMetadata
+	return err
+}
+
 // RemapUserDefinedTypeOIDs is used to remap OIDs stored within a types.T
 // that is a user defined type. The newArrayOID argument is ignored if the
 // input type is an Array type. It mutates the input types.T and should only
@@ -1302,12 +1502,16 @@ var familyNames = map[Family]string{
 	IntFamily:            "int",
 	IntervalFamily:       "interval",
 	JsonFamily:           "jsonb",
+	JsonpathFamily:       "jsonpath",
 	OidFamily:            "oid",
+	RangeFamily:          "range",
 	StringFamily:         "string",
 	TimeFamily:           "time",
 	TimestampFamily:      "timestamp",
 	TimestampTZFamily:    "timestamptz",
 	TimeTZFamily:         "timetz",
+	TSQueryFamily:        "tsquery",
+	TSVectorFamily:       "tsvector",
 	TupleFamily:          "tuple",
 	UnknownFamily:        "unknown",
 	UuidFamily:           "uuid",
@@ -1375,6 +1579,10 @@ func (t *T) CanonicalType() *T {
 	case TimeTZFamily:
 		return TimeTZ
 	case TupleFamily:
+		if t.IsComposite() {
+			// Composite types have no canonical type.
+			return t
+		}
 		isCanonical := true
 		oldContents := t.TupleContents()
 		for i := range oldContents {
@@ -1400,8 +1608,17 @@ func (t *T) CanonicalType() *T {
 	case EnumFamily:
 		// EnumFamily has no canonical type.
 		return t
+	case RangeFamily:
+		// RangeFamily has no canonical type.
+		return t
 	case Box2DFamily:
 		return Box2D
+	case TSVectorFamily:
+		return TSVector
+	case TSQueryFamily:
+		return TSQuery
+	case JsonpathFamily:
+		return Jsonpath
 	case AnyFamily:
 		return Any
 	default:
@@ -1416,6 +1633,13 @@ func (t *T) CanonicalType() *T {
 //
 // TODO(andyk): Should these be changed to be the same as SQLStandardName?
 func (t *T) Name() string {
+	if t.IsDomain() {
+		// This can be nil during unit testing.
+		if t.TypeMeta.Name == nil {
+			return t.DomainBaseType().Name()
+		}
+		return t.TypeMeta.Name.Basename()
+	}
 	switch fam := t.Family(); fam {
 	case AnyFamily:
 		return "anyelement"
@@ -1477,6 +1701,10 @@ func (t *T) Name() string {
 		panic(errors.AssertionFailedf("unexpected OID: %d", t.Oid()))
 
 	case TupleFamily:
+		// This can be nil during unit testing.
+		if t.IsComposite() && t.TypeMeta.Name != nil {
+			return t.TypeMeta.Name.Basename()
+		}
 		return t.SQLStandardName()
 
 	case EnumFamily:
@@ -1489,6 +1717,9 @@ func (t *T) Name() string {
 		}
 		return t.TypeMeta.Name.Basename()
 
+	case RangeFamily:
+		return t.PGName()
+
 	default:
 		return fam.Name()
 	}
@@ -1555,6 +1786,9 @@ func (t *T) TelemetryName() string {
 // This function is full of special cases. See backend/utils/adt/format_type.c
 // in Postgres.
 func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
+	if t.IsDomain() {
+		return t.TypeMeta.Name.Basename()
+	}
 	var buf strings.Builder
 	switch t.Family() {
 	case AnyFamily:
@@ -1633,6 +1867,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 	case JsonFamily:
 		// Only binary JSON is currently supported.
 		return "jsonb"
+	case JsonpathFamily:
+		return "jsonpath"
+	case RangeFamily:
+		return t.PGName()
 	case OidFamily:
 		switch t.Oid() {
 		case oid.T_oid:
@@ -1715,6 +1953,10 @@ func (t *T) SQLStandardNameWithTypmod(haveTypmod bool, typmod int) string {
 			return t.TypeMeta.Name.Basename()
 		}
 		return "record"
+	case TSQueryFamily:
+		return "tsquery"
+	case TSVectorFamily:
+		return "tsvector"
 	case UnknownFamily:
 		return "unknown"
 	case UuidFamily:
@@ -1736,6 +1978,10 @@ func (t *T) InformationSchemaName() string {
 	if t.Family() == ArrayFamily {
 		return "ARRAY"
 	}
+	// Columns of a domain type report the data type of the domain's base type.
+	if t.IsDomain() {
+		return t.DomainBaseType().InformationSchemaName()
+	}
 	// TypeMeta attributes are populated only when it is user defined type.
 	if t.TypeMeta.Name != nil {
 		return "USER-DEFINED"
@@ -1747,6 +1993,9 @@ func (t *T) InformationSchemaName() string {
 // reproduce the type via parsing the string as a type. It is used in error
 // messages and also to produce the output of SHOW CREATE.
 func (t *T) SQLString() string {
+	if t.IsDomain() {
+		return t.TypeMeta.Name.FQName()
+	}
 	switch t.Family() {
 	case BitFamily:
 		o := t.Oid()
@@ -1841,6 +2090,10 @@ func (t *T) SQLString() string {
 			return "anyenum"
 		}
 		return t.TypeMeta.Name.FQName()
+	case TupleFamily:
+		if t.IsComposite() {
+			return t.TypeMeta.Name.FQName()
+		}
 	}
 	return strings.ToUpper(t.Name())
 }
@@ -1904,6 +2157,15 @@ func (t *T) Equivalent(other *T) bool {
 		if t.Oid() != other.Oid() {
 			return false
 		}
+
+	case RangeFamily:
+		// Likewise, anyrange matches a range of any element type.
+		if t.Oid() == oid.T_anyrange || other.Oid() == oid.T_anyrange {
+			return true
+		}
+		if t.Oid() != other.Oid() {
+			return false
+		}
 	}
 
 	return true
@@ -2053,6 +2315,13 @@ func (t *InternalType) Identical(other *InternalType) bool {
 		if t.UDTMetadata.ArrayTypeOID != other.UDTMetadata.ArrayTypeOID {
 			return false
 		}
+		if (t.UDTMetadata.DomainBaseOID == nil) != (other.UDTMetadata.DomainBaseOID == nil) {
+			return false
+		}
+		if t.UDTMetadata.DomainBaseOID != nil &&
+			*t.UDTMetadata.DomainBaseOID != *other.UDTMetadata.DomainBaseOID {
+			return false
+		}
 	} else if t.UDTMetadata != nil {
 		return false
 	} else if other.UDTMetadata != nil {
@@ -2085,6 +2354,9 @@ func (t *T) Unmarshal(data []byte) error {
 // setting required values. This is necessary to preserve backwards-
 // compatibility with older formats (e.g. restoring database from old backup).
 func (t *T) upgradeType() error {
+	if t.IsDomain() {
+		return t.withDomainBaseType((*T).upgradeType)
+	}
 	switch t.Family() {
 	case IntFamily:
 		// Check VisibleType field that was populated in previous versions.
@@ -2323,6 +2595,9 @@ func (t *T) MarshalTo(data []byte) (int, error) {
 // CRDB. This is necessary to preserve backwards-compatibility in mixed-version
 // scenarios, such as during upgrade.
 func (t *T) downgradeType() error {
+	if t.IsDomain() {
+		return t.withDomainBaseType((*T).downgradeType)
+	}
 	// Set Family and VisibleType for 19.1 backwards-compatibility.
 	switch t.Family() {
 	case BitFamily:
@@ -2409,6 +2684,9 @@ func (t *T) String() string {
 		return t.ArrayContents().String() + "[]"
 
 	case TupleFamily:
+		if t.IsComposite() && t.TypeMeta.Name != nil {
+			return t.Name()
+		}
 		var buf bytes.Buffer
 		buf.WriteString("tuple")
 		if len(t.TupleContents()) != 0 && !IsWildcardTupleType(t) {
@@ -2487,6 +2765,8 @@ func (t *T) IsAmbiguous() bool {
 		return t.ArrayContents().IsAmbiguous()
 	case EnumFamily:
 		return t.Oid() == oid.T_anyenum
+	case RangeFamily:
+		return t.Oid() == oid.T_anyrange
 	}
 	return false
 }
@@ -2742,6 +3022,7 @@ var unreservedTypeTokens = map[string]*T{
 	"int2vector": Int2Vector,
 	"json":       Jsonb,
 	"jsonb":      Jsonb,
+	"jsonpath":   Jsonpath,
 	"name":       Name,
 	"oid":        Oid,
 	"oidvector":  OidVector,
@@ -2759,8 +3040,10 @@ var unreservedTypeTokens = map[string]*T{
 	"smallserial": &Serial2Type,
 	"bigserial":   &Serial8Type,
 
-	"string": String,
-	"uuid":   Uuid,
+	"string":   String,
+	"tsquery":  TSQuery,
+	"tsvector": TSVector,
+	"uuid":     Uuid,
 }
 
 // The following map must include all types predefined in PostgreSQL
@@ -2771,7 +3054,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"box":           21286,
 	"cidr":          18846,
 	"circle":        21286,
-	"jsonpath":      22513,
 	"line":          21286,
 	"lseg":          21286,
 	"macaddr":       -1,
@@ -2779,8 +3061,6 @@ var postgresPredefinedTypeIssues = map[string]int{
 	"money":         -1,
 	"path":          21286,
 	"pg_lsn":        -1,
-	"tsquery":       7821,
-	"tsvector":      7821,
 	"txid_snapshot": -1,
 	"xml":           -1,
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/build"
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
+	"github.com/cockroachdb/cockroach/pkg/kv"
 	"github.com/cockroachdb/cockroach/pkg/security"
 	"github.com/cockroachdb/cockroach/pkg/server/telemetry"
 	"github.com/cockroachdb/cockroach/pkg/settings"
@@ -38,9 +39,12 @@ import (
 	"github.com/cockroachdb/cockroach/pkg/sql/sqltelemetry"
 	"github.com/cockroachdb/cockroach/pkg/util/duration"
 	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/hlc"
 	"github.com/cockroachdb/cockroach/pkg/util/humanizeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil"
 	"github.com/cockroachdb/cockroach/pkg/util/timeutil/pgdate"
+	"github.com/cockroachdb/cockroach/pkg/util/trigram"
+	"github.com/cockroachdb/cockroach/pkg/util/tsearch"
 	"github.com/cockroachdb/errors"
 )
 
@@ -385,19 +389,52 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: func(sv *settings.Values) string { return "" },
 	},
 
+	// See https://www.postgresql.org/docs/current/runtime-config-client.html#GUC-DEFAULT-TEXT-SEARCH-CONFIG
+	`default_text_search_config`: {
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			config, err := tsearch.GetConfig(s)
+			if err != nil {
+				return err
+			}
+			m.SetDefaultTextSearchConfig(config.Name())
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) (string, error) {
+			name := evalCtx.SessionData().DefaultTextSearchConfig
+			if name == "" {
+				name = tsearch.DefaultConfigName
+			}
+			return "pg_catalog." + name, nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return "pg_catalog." + tsearch.DefaultConfigName
+		},
+	},
+
 	// See https://www.postgresql.org/docs/10/static/runtime-config-client.html#GUC-DEFAULT-TRANSACTION-ISOLATION
 	`default_transaction_isolation`: {
 		Set: func(_ context.Context, m sessionDataMutator, s string) error {
 			switch strings.ToUpper(s) {
-			case `READ UNCOMMITTED`, `READ COMMITTED`, `SNAPSHOT`, `REPEATABLE READ`, `SERIALIZABLE`, `DEFAULT`:
-				// Do nothing. All transactions execute with serializable isolation.
+			case `READ UNCOMMITTED`, `READ COMMITTED`:
+				m.SetDefaultTransactionIsolationLevel(tree.ReadCommittedIsolation)
+			case `SNAPSHOT`, `REPEATABLE READ`, `SERIALIZABLE`:
+				// SNAPSHOT and REPEATABLE READ transactions execute with serializable
+				// isolation.
+				m.SetDefaultTransactionIsolationLevel(tree.SerializableIsolation)
+			case `DEFAULT`:
+				m.SetDefaultTransactionIsolationLevel(tree.UnspecifiedIsolation)
 			default:
-				return newVarValueError(`default_transaction_isolation`, s, "serializable")
+				return newVarValueError(`default_transaction_isolation`, s, "read committed", "serializable")
 			}
 
 			return nil
 		},
 		Get: func(evalCtx *extendedEvalContext) (string, error) {
+			level := tree.IsolationLevel(evalCtx.SessionData().DefaultTxnIsolationLevel)
+			if level == tree.ReadCommittedIsolation &&
+				readCommittedIsolationEnabled.Get(&evalCtx.Settings.SV) {
+				return "read committed", nil
+			}
 			return "serializable", nil
 		},
 		GlobalDefault: func(sv *settings.Values) string { return "default" },
@@ -1047,6 +1084,30 @@ var varGen = map[string]sessionVar{
 		GlobalDefault: globalFalse,
 	},
 
+	// See https://www.postgresql.org/docs/current/pgtrgm.html#id-1.11.7.42.8
+	`pg_trgm.similarity_threshold`: {
+		GetStringVal: makeFloatGetStringValFn(`pg_trgm.similarity_threshold`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return wrapSetVarError(err, "pg_trgm.similarity_threshold", s)
+			}
+			// Note: this is the range allowed by PostgreSQL.
+			if f < 0 || f > 1 {
+				return pgerror.Newf(pgcode.InvalidParameterValue,
+					`%s is outside the valid range for parameter "pg_trgm.similarity_threshold" (0 .. 1)`, s)
+			}
+			m.SetTrigramSimilarityThreshold(f)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) (string, error) {
+			return formatFloatAsPostgresSetting(evalCtx.SessionData().TrigramSimilarityThreshold), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatFloatAsPostgresSetting(trigram.DefaultSimilarityThreshold)
+		},
+	},
+
 	// CockroachDB extension.
 	`prefer_lookup_joins_for_fks`: {
 		Get: func(evalCtx *extendedEvalContext) (string, error) {
@@ -1279,14 +1340,24 @@ var varGen = map[string]sessionVar{
 	// See https://github.com/postgres/postgres/blob/REL_10_STABLE/src/backend/utils/misc/guc.c#L3401-L3409
 	`transaction_isolation`: {
 		Get: func(evalCtx *extendedEvalContext) (string, error) {
+			if evalCtx.Txn != nil && evalCtx.Txn.IsolationLevel() == kv.ReadCommitted {
+				return "read committed", nil
+			}
 			return "serializable", nil
 		},
 		RuntimeSet: func(_ context.Context, evalCtx *extendedEvalContext, local bool, s string) error {
-			_, ok := tree.IsolationLevelMap[s]
+			level, ok := tree.IsolationLevelMap[s]
 			if !ok {
-				return newVarValueError(`transaction_isolation`, s, "serializable")
+				return newVarValueError(`transaction_isolation`, s, "read committed", "serializable")
 			}
-			return nil
+			if evalCtx.TxnImplicit || evalCtx.TxnModesSetter == nil {
+				// Setting the isolation level of an implicit transaction has no
+				// effect beyond the SET statement itself.
+				return nil
+			}
+			return evalCtx.TxnModesSetter.setTransactionModes(
+				tree.TransactionModes{Isolation: level}, hlc.Timestamp{},
+			)
 		},
 		GlobalDefault: func(_ *settings.Values) string { return "serializable" },
 	},
@@ -1670,6 +1741,25 @@ var varGen = map[string]sessionVar{
 		},
 	},
 
+	// CockroachDB extension.
+	`index_recommendations_enabled`: {
+		GetStringVal: makePostgresBoolGetStringValFn(`index_recommendations_enabled`),
+		Set: func(_ context.Context, m sessionDataMutator, s string) error {
+			b, err := paramparse.ParseBoolVar(`index_recommendations_enabled`, s)
+			if err != nil {
+				return err
+			}
+			m.SetIndexRecommendationsEnabled(b)
+			return nil
+		},
+		Get: func(evalCtx *extendedEvalContext) (string, error) {
+			return formatBoolAsPostgresSetting(evalCtx.SessionData().IndexRecommendationsEnabled), nil
+		},
+		GlobalDefault: func(sv *settings.Values) string {
+			return formatBoolAsPostgresSetting(indexRecommendationsEnabled.Get(sv))
+		},
+	},
+
 	// CockroachDB extension.
 	`transaction_rows_written_log`: {
 		GetStringVal: makeIntGetStringValFn(`transaction_rows_written_log`),
@@ -1817,7 +1907,9 @@ func init() {
 		res := make([]string, 0, len(varGen))
 		for vName := range varGen {
 			res = append(res, vName)
-			if strings.Contains(vName, ".") {
+			// The variables of the pg_trgm extension are the only ones that
+			// are allowed to have a namespace.
+			if strings.Contains(vName, ".") && !strings.HasPrefix(vName, "pg_trgm.") {
 				panic(fmt.Sprintf(`no session variables with "." can be created as they are reserved for custom options, found %s`, vName))
 			}
 		}
@@ -960,8 +960,8 @@ CREATE TABLE pg_catalog.pg_available_extension_versions (
 	version STRING
 )`
 
-// PgCatalogPublicationRel is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationRel describes the schema of pg_catalog.pg_publication_rel
+// https://www.postgresql.org/docs/13/catalog-pg-publication-rel.html
 const PgCatalogPublicationRel = `
 CREATE TABLE pg_catalog.pg_publication_rel (
 	oid OID,
@@ -1010,8 +1010,9 @@ CREATE TABLE pg_catalog.pg_timezone_names (
 	utc_offset INTERVAL
 )`
 
-// PgCatalogPublicationTables is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublicationTables describes the schema of
+// pg_catalog.pg_publication_tables
+// https://www.postgresql.org/docs/13/view-pg-publication-tables.html
 const PgCatalogPublicationTables = `
 CREATE TABLE pg_catalog.pg_publication_tables (
 	pubname NAME,
@@ -1067,8 +1068,8 @@ CREATE TABLE pg_catalog.pg_shadow (
 	useconfig STRING[]
 )`
 
-// PgCatalogPublication is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogPublication describes the schema of pg_catalog.pg_publication
+// https://www.postgresql.org/docs/13/catalog-pg-publication.html
 const PgCatalogPublication = `
 CREATE TABLE pg_catalog.pg_publication (
 	pubupdate BOOL,
@@ -1117,8 +1118,8 @@ CREATE TABLE pg_catalog.pg_ts_parser (
 	prsheadline REGPROC
 )`
 
-// PgCatalogSubscription is an empty table created by pg_catalog_test
-// and is currently unimplemented.
+// PgCatalogSubscription describes the schema of pg_catalog.pg_subscription
+// https://www.postgresql.org/docs/13/catalog-pg-subscription.html
 const PgCatalogSubscription = `
 CREATE TABLE pg_catalog.pg_subscription (
 	subname NAME,
@@ -1306,7 +1307,9 @@ CREATE TABLE pg_catalog.pg_replication_origin_status (
 	local_id OID
 )`
 
-//PgCatalogReplicationSlots is an empty table in the pg_catalog that is not implemented yet
+// PgCatalogReplicationSlots describes the schema of
+// pg_catalog.pg_replication_slots
+// https://www.postgresql.org/docs/13/view-pg-replication-slots.html
 const PgCatalogReplicationSlots = `
 CREATE TABLE pg_catalog.pg_replication_slots (
 	safe_wal_size INT,
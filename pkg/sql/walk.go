@@ -336,6 +336,7 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterDatabaseSurvivalGoalNode{}):  "alter database survive",
 	reflect.TypeOf(&alterDatabaseDropRegionNode{}):    "alter database drop region",
 	reflect.TypeOf(&alterDefaultPrivilegesNode{}):     "alter default privileges",
+	reflect.TypeOf(&alterDomainNode{}):                "alter domain",
 	reflect.TypeOf(&alterIndexNode{}):                 "alter index",
 	reflect.TypeOf(&alterSequenceNode{}):              "alter sequence",
 	reflect.TypeOf(&alterSchemaNode{}):                "alter schema",
@@ -344,10 +345,12 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&alterTableSetLocalityNode{}):      "alter table set locality",
 	reflect.TypeOf(&alterTableSetSchemaNode{}):        "alter table set schema",
 	reflect.TypeOf(&alterTypeNode{}):                  "alter type",
+	reflect.TypeOf(&alterPublicationNode{}):           "alter publication",
 	reflect.TypeOf(&alterRoleNode{}):                  "alter role",
 	reflect.TypeOf(&alterRoleSetNode{}):               "alter role set var",
 	reflect.TypeOf(&applyJoinNode{}):                  "apply join",
 	reflect.TypeOf(&bufferNode{}):                     "buffer",
+	reflect.TypeOf(&callNode{}):                       "call",
 	reflect.TypeOf(&cancelQueriesNode{}):              "cancel queries",
 	reflect.TypeOf(&cancelSessionsNode{}):             "cancel sessions",
 	reflect.TypeOf(&changePrivilegesNode{}):           "change privileges",
@@ -361,10 +364,14 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&controlSchedulesNode{}):           "control schedules",
 	reflect.TypeOf(&createDatabaseNode{}):             "create database",
 	reflect.TypeOf(&createExtensionNode{}):            "create extension",
+	reflect.TypeOf(&createFunctionNode{}):             "create function",
 	reflect.TypeOf(&createIndexNode{}):                "create index",
+	reflect.TypeOf(&createPublicationNode{}):          "create publication",
 	reflect.TypeOf(&createSequenceNode{}):             "create sequence",
 	reflect.TypeOf(&createSchemaNode{}):               "create schema",
+	reflect.TypeOf(&createServerNode{}):               "create server",
 	reflect.TypeOf(&createStatsNode{}):                "create statistics",
+	reflect.TypeOf(&createSubscriptionNode{}):         "create subscription",
 	reflect.TypeOf(&createTableNode{}):                "create table",
 	reflect.TypeOf(&createTypeNode{}):                 "create type",
 	reflect.TypeOf(&CreateRoleNode{}):                 "create user/role",
@@ -374,18 +381,24 @@ var planNodeNames = map[reflect.Type]string{
 	reflect.TypeOf(&deleteRangeNode{}):                "delete range",
 	reflect.TypeOf(&distinctNode{}):                   "distinct",
 	reflect.TypeOf(&dropDatabaseNode{}):               "drop database",
+	reflect.TypeOf(&dropFunctionNode{}):               "drop function",
 	reflect.TypeOf(&dropIndexNode{}):                  "drop index",
+	reflect.TypeOf(&dropPublicationNode{}):            "drop publication",
 	reflect.TypeOf(&dropSequenceNode{}):               "drop sequence",
 	reflect.TypeOf(&dropSchemaNode{}):                 "drop schema",
+	reflect.TypeOf(&dropServerNode{}):                 "drop server",
+	reflect.TypeOf(&dropSubscriptionNode{}):           "drop subscription",
 	reflect.TypeOf(&dropTableNode{}):                  "drop table",
 	reflect.TypeOf(&dropTypeNode{}):                   "drop type",
 	reflect.TypeOf(&DropRoleNode{}):                   "drop user/role",
 	reflect.TypeOf(&dropViewNode{}):                   "drop view",
+	reflect.TypeOf(&endPreparedTxnNode{}):             "end prepared txn",
 	reflect.TypeOf(&errorIfRowsNode{}):                "error if rows",
 	reflect.TypeOf(&explainPlanNode{}):                "explain plan",
 	reflect.TypeOf(&explainVecNode{}):                 "explain vectorized",
 	reflect.TypeOf(&explainDDLNode{}):                 "explain ddl",
 	reflect.TypeOf(&exportNode{}):                     "export",
+	reflect.TypeOf(&fetchNode{}):                      "fetch",
 	reflect.TypeOf(&filterNode{}):                     "filter",
 	reflect.TypeOf(&GrantRoleNode{}):                  "grant role",
 	reflect.TypeOf(&groupNode{}):                      "group",
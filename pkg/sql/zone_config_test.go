@@ -36,7 +36,7 @@ import (
 )
 
 var configID = descpb.ID(1)
-var configDescKey = catalogkeys.MakeDescMetadataKey(keys.SystemSQLCodec, keys.MaxReservedDescID)
+var configDescKey = catalogkeys.MakeDescMetadataKey(keys.SystemSQLCodec, keys.MaxSystemConfigDescID)
 
 // forceNewConfig forces a system config update by writing a bogus descriptor with an
 // incremented value inside. It then repeatedly fetches the gossip config until the
@@ -74,7 +74,7 @@ func waitForConfigChange(t testing.TB, s *server.TestServer) *config.SystemConfi
 				if err := val.GetProto(&foundDesc); err != nil {
 					t.Fatal(err)
 				}
-				_, db, _, _ := descpb.FromDescriptor(&foundDesc)
+				_, db, _, _, _ := descpb.FromDescriptor(&foundDesc)
 				if db.ID != configID {
 					return errors.Errorf("expected database id %d; got %d", configID, db.ID)
 				}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -781,6 +782,7 @@ func MVCCBlindPutProto(
 type MVCCGetOptions struct {
 	// See the documentation for MVCCGet for information on these parameters.
 	Inconsistent     bool
+	SkipLocked       bool
 	Tombstones       bool
 	FailOnMoreRecent bool
 	Txn              *roachpb.Transaction
@@ -806,6 +808,9 @@ type MVCCGetOptions struct {
 	LocalUncertaintyLimit hlc.Timestamp
 	// MemoryAccount is used for tracking memory allocations.
 	MemoryAccount *mon.BoundAccount
+	// LockTable is used to determine whether keys are locked in the in-memory
+	// lock table when scanning with the SkipLocked option.
+	LockTable LockTableView
 }
 
 func (opts *MVCCGetOptions) validate() error {
@@ -815,9 +820,27 @@ func (opts *MVCCGetOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
+	if opts.SkipLocked && opts.LockTable == nil {
+		return errors.Errorf("cannot allow skip locked reads without a lock table")
+	}
 	return nil
 }
 
+// LockTableView is a transaction-bound view into an in-memory collection of
+// key-level locks. It is consulted by reads that skip over keys which are
+// locked by conflicting transactions.
+type LockTableView interface {
+	// IsKeyLockedByConflictingTxn returns whether the specified key is locked
+	// or reserved by a conflicting transaction, given the strength with which
+	// the caller intends to access the key. If the key is locked, the lock
+	// holder is also returned. If the key is only reserved, the returned
+	// holder is nil.
+	IsKeyLockedByConflictingTxn(roachpb.Key, lock.Strength) (bool, *enginepb.TxnMeta)
+}
+
 func newMVCCIterator(reader Reader, inlineMeta bool, opts IterOptions) MVCCIterator {
 	iterKind := MVCCKeyAndIntentsIterKind
 	if inlineMeta {
@@ -850,6 +873,12 @@ func newMVCCIterator(reader Reader, inlineMeta bool, opts IterOptions) MVCCItera
 // timestamp. Similarly, a WriteIntentError will be returned if the read
 // observes another transaction's intent, even if it has a timestamp above
 // the read timestamp.
+//
+// When reading in "skip locked" mode, a key that is locked by a transaction
+// other than the reader is not included in the result and does not result in
+// a WriteIntentError. In this mode, the LockTableView provided in the options
+// is consulted to determine whether the key is locked with an unreplicated
+// lock.
 func MVCCGet(
 	ctx context.Context, reader Reader, key roachpb.Key, timestamp hlc.Timestamp, opts MVCCGetOptions,
 ) (*roachpb.Value, *roachpb.Intent, error) {
@@ -889,8 +918,10 @@ func mvccGet(
 		ts:               timestamp,
 		maxKeys:          1,
 		inconsistent:     opts.Inconsistent,
+		skipLocked:       opts.SkipLocked,
 		tombstones:       opts.Tombstones,
 		failOnMoreRecent: opts.FailOnMoreRecent,
+		lockTable:        opts.LockTable,
 		keyBuf:           mvccScanner.keyBuf,
 	}
 
@@ -2467,8 +2498,10 @@ func mvccScanToBytes(
 		targetBytesAllowEmpty:  opts.TargetBytesAllowEmpty,
 		maxIntents:             opts.MaxIntents,
 		inconsistent:           opts.Inconsistent,
+		skipLocked:             opts.SkipLocked,
 		tombstones:             opts.Tombstones,
 		failOnMoreRecent:       opts.FailOnMoreRecent,
+		lockTable:              opts.LockTable,
 		keyBuf:                 mvccScanner.keyBuf,
 	}
 
@@ -2565,6 +2598,7 @@ func buildScanIntents(data []byte) ([]roachpb.Intent, error) {
 type MVCCScanOptions struct {
 	// See the documentation for MVCCScan for information on these parameters.
 	Inconsistent     bool
+	SkipLocked       bool
 	Tombstones       bool
 	Reverse          bool
 	FailOnMoreRecent bool
@@ -2624,6 +2658,9 @@ type MVCCScanOptions struct {
 	MaxIntents int64
 	// MemoryAccount is used for tracking memory allocations.
 	MemoryAccount *mon.BoundAccount
+	// LockTable is used to determine whether keys are locked in the in-memory
+	// lock table when scanning with the SkipLocked option.
+	LockTable LockTableView
 }
 
 func (opts *MVCCScanOptions) validate() error {
@@ -2633,6 +2670,12 @@ func (opts *MVCCScanOptions) validate() error {
 	if opts.Inconsistent && opts.FailOnMoreRecent {
 		return errors.Errorf("cannot allow inconsistent reads with fail on more recent option")
 	}
+	if opts.Inconsistent && opts.SkipLocked {
+		return errors.Errorf("cannot allow inconsistent reads with skip locked option")
+	}
+	if opts.SkipLocked && opts.LockTable == nil {
+		return errors.Errorf("cannot allow skip locked reads without a lock table")
+	}
 	return nil
 }
 
@@ -2687,6 +2730,12 @@ type MVCCScanResult struct {
 // the read timestamp, the maximum will be returned in the WriteTooOldError.
 // Similarly, a WriteIntentError will be returned if the scan observes another
 // transaction's intent, even if it has a timestamp above the read timestamp.
+//
+// When scanning in "skip locked" mode, keys that are locked by transactions
+// other than the reader are not included in the result set and do not result
+// in a WriteIntentError. In this mode, the LockTableView provided in the
+// options is consulted for each key to determine whether it is locked with an
+// unreplicated lock.
 func MVCCScan(
 	ctx context.Context,
 	reader Reader,
@@ -20,6 +20,7 @@ import (
 
 	"github.com/cockroachdb/cockroach/pkg/clusterversion"
 	"github.com/cockroachdb/cockroach/pkg/keys"
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/settings/cluster"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -50,14 +51,15 @@ import (
 //
 // resolve_intent t=<name> k=<key> [status=<txnstatus>]
 // check_intent   k=<key> [none]
+// add_lock       t=<name> k=<key>
 //
 // cput      [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> v=<string> [raw] [cond=<string>]
 // del       [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key>
 // del_range [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [end=<key>] [max=<max>] [returnKeys]
-// get       [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [inconsistent] [tombstones] [failOnMoreRecent] [localUncertaintyLimit=<int>[,<int>]]
+// get       [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [inconsistent] [skipLocked] [tombstones] [failOnMoreRecent] [localUncertaintyLimit=<int>[,<int>]]
 // increment [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [inc=<val>]
 // put       [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> v=<string> [raw]
-// scan      [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [end=<key>] [inconsistent] [tombstones] [reverse] [failOnMoreRecent] [localUncertaintyLimit=<int>[,<int>]] [max=<max>] [targetbytes=<target>] [avoidExcess] [allowEmpty]
+// scan      [t=<name>] [ts=<int>[,<int>]] [resolve [status=<txnstatus>]] k=<key> [end=<key>] [inconsistent] [skipLocked] [tombstones] [reverse] [failOnMoreRecent] [localUncertaintyLimit=<int>[,<int>]] [max=<max>] [targetbytes=<target>] [avoidExcess] [allowEmpty]
 //
 // merge     [ts=<int>[,<int>]] k=<key> v=<string> [raw]
 //
@@ -423,6 +425,7 @@ var commands = map[string]cmd{
 	"resolve_intent": {typDataUpdate, cmdResolveIntent},
 	// TODO(nvanbenschoten): test "resolve_intent_range".
 	"check_intent": {typReadOnly, cmdCheckIntent},
+	"add_lock":     {typReadOnly, cmdAddLock},
 
 	"clear_range": {typDataUpdate, cmdClearRange},
 	"cput":        {typDataUpdate, cmdCPut},
@@ -693,6 +696,13 @@ func cmdDeleteRange(e *evalCtx) error {
 	})
 }
 
+func cmdAddLock(e *evalCtx) error {
+	txn := e.getTxn(mandatory)
+	key := e.getKey()
+	e.locks[string(key)] = txn
+	return nil
+}
+
 func cmdGet(e *evalCtx) error {
 	txn := e.getTxn(optional)
 	key := e.getKey()
@@ -702,6 +712,10 @@ func cmdGet(e *evalCtx) error {
 		opts.Inconsistent = true
 		opts.Txn = nil
 	}
+	if e.hasArg("skipLocked") {
+		opts.SkipLocked = true
+		opts.LockTable = e.newLockTableView(txn, ts)
+	}
 	if e.hasArg("tombstones") {
 		opts.Tombstones = true
 	}
@@ -798,6 +812,10 @@ func cmdScan(e *evalCtx) error {
 		opts.Inconsistent = true
 		opts.Txn = nil
 	}
+	if e.hasArg("skipLocked") {
+		opts.SkipLocked = true
+		opts.LockTable = e.newLockTableView(txn, ts)
+	}
 	if e.hasArg("tombstones") {
 		opts.Tombstones = true
 	}
@@ -862,6 +880,7 @@ type evalCtx struct {
 	td         *datadriven.TestData
 	txns       map[string]*roachpb.Transaction
 	txnCounter uint128.Uint128
+	locks      map[string]*roachpb.Transaction
 }
 
 func newEvalCtx(ctx context.Context, engine Engine) *evalCtx {
@@ -870,6 +889,7 @@ func newEvalCtx(ctx context.Context, engine Engine) *evalCtx {
 		engine:     engine,
 		txns:       make(map[string]*roachpb.Transaction),
 		txnCounter: uint128.FromInts(0, 1),
+		locks:      make(map[string]*roachpb.Transaction),
 	}
 }
 
@@ -1058,6 +1078,34 @@ func (e *evalCtx) lookupTxn(txnName string) (*roachpb.Transaction, error) {
 	return txn, nil
 }
 
+func (e *evalCtx) newLockTableView(txn *roachpb.Transaction, ts hlc.Timestamp) LockTableView {
+	return &mockLockTableView{locks: e.locks, txn: txn, ts: ts}
+}
+
+// mockLockTableView is a mock implementation of LockTableView that is backed
+// by the unreplicated locks added with the add_lock command.
+type mockLockTableView struct {
+	locks map[string]*roachpb.Transaction
+	txn   *roachpb.Transaction
+	ts    hlc.Timestamp
+}
+
+func (lt *mockLockTableView) IsKeyLockedByConflictingTxn(
+	k roachpb.Key, s lock.Strength,
+) (bool, *enginepb.TxnMeta) {
+	holder, ok := lt.locks[string(k)]
+	if !ok {
+		return false, nil
+	}
+	if lt.txn != nil && lt.txn.ID == holder.ID {
+		return false, nil
+	}
+	if s == lock.None && lt.ts.Less(holder.WriteTimestamp) {
+		return false, nil
+	}
+	return true, &holder.TxnMeta
+}
+
 func toKey(s string) roachpb.Key {
 	switch {
 	case len(s) > 0 && s[0] == '+':
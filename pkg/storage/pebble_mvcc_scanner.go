@@ -17,6 +17,7 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/concurrency/lock"
 	"github.com/cockroachdb/cockroach/pkg/kv/kvserver/observedts"
 	"github.com/cockroachdb/cockroach/pkg/roachpb"
 	"github.com/cockroachdb/cockroach/pkg/storage/enginepb"
@@ -160,9 +161,13 @@ type pebbleMVCCScanner struct {
 	// package level MVCCScan for what these mean.
 	inconsistent, tombstones bool
 	failOnMoreRecent         bool
+	skipLocked               bool
 	isGet                    bool
 	keyBuf                   []byte
 	savedBuf                 []byte
+	// lockTable is consulted to determine whether keys are locked by
+	// conflicting transactions. Only used if skipLocked is true.
+	lockTable LockTableView
 	// cur* variables store the "current" record we're pointing to. Updated in
 	// updateCurrent. Note that the timestamp can be clobbered in the case of
 	// adding an intent from the intent history but is otherwise meaningful.
@@ -376,6 +381,17 @@ func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
 		// ts == read_ts
 		if p.curUnsafeKey.Timestamp.EqOrdering(p.ts) {
 			if p.failOnMoreRecent {
+				if p.skipLocked {
+					if locked, ok := p.isKeyLockedByConflictingTxn(p.curRawKey); !ok {
+						return false
+					} else if locked {
+						// 2a. The key is locked by a conflicting transaction and
+						// the scanner has been configured to skip locked keys.
+						// Skip the key without throwing a write too old error.
+						return p.advanceKey()
+					}
+				}
+
 				// 2. Our txn's read timestamp is equal to the most recent
 				// version's timestamp and the scanner has been configured to
 				// throw a write too old error on equal or more recent versions.
@@ -393,6 +409,17 @@ func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
 
 		// ts > read_ts
 		if p.failOnMoreRecent {
+			if p.skipLocked {
+				if locked, ok := p.isKeyLockedByConflictingTxn(p.curRawKey); !ok {
+					return false
+				} else if locked {
+					// 4a. The key is locked by a conflicting transaction and the
+					// scanner has been configured to skip locked keys. Skip the
+					// key without throwing a write too old error.
+					return p.advanceKey()
+				}
+			}
+
 			// 4. Our txn's read timestamp is less than the most recent
 			// version's timestamp and the scanner has been configured to
 			// throw a write too old error on equal or more recent versions.
@@ -501,6 +528,14 @@ func (p *pebbleMVCCScanner) getAndAdvance(ctx context.Context) bool {
 		return p.seekVersion(ctx, prevTS, false)
 	}
 
+	if !ownIntent && p.skipLocked {
+		// 10a. The key contains an intent which was not written by our
+		// transaction and conflicts with our read, but the scanner has been
+		// configured to skip locked keys. Skip the key without returning the
+		// intent.
+		return p.advanceKey()
+	}
+
 	if !ownIntent {
 		// 10. The key contains an intent which was not written by our
 		// transaction and either:
@@ -716,6 +751,15 @@ func (p *pebbleMVCCScanner) addAndAdvance(ctx context.Context, rawKey []byte, va
 	// Don't include deleted versions len(val) == 0, unless we've been instructed
 	// to include tombstones in the results.
 	if len(val) > 0 || p.tombstones {
+		// If the scanner has been configured to skip locked keys, check whether
+		// the key is locked by a conflicting transaction before including it.
+		if p.skipLocked {
+			if locked, ok := p.isKeyLockedByConflictingTxn(rawKey); !ok {
+				return false
+			} else if locked {
+				return p.advanceKey()
+			}
+		}
 		// Check if we should apply the targetBytes limit at all. We do this either
 		// if this is not the first result or if targetBytesAllowEmpty is true.
 		if p.targetBytes > 0 && (p.results.count > 0 || p.targetBytesAllowEmpty) {
@@ -741,6 +785,26 @@ func (p *pebbleMVCCScanner) addAndAdvance(ctx context.Context, rawKey []byte, va
 	return p.advanceKey()
 }
 
+// Consults the lock table to determine whether the key is locked or reserved
+// by a transaction that conflicts with the scanner's transaction. Locking
+// scans (which fail on more recent values) conflict with any lock held by
+// another transaction, while non-locking scans only conflict with locks held
+// at or below the read timestamp. Returns ok = false if the key could not be
+// decoded, in which case p.err is set.
+func (p *pebbleMVCCScanner) isKeyLockedByConflictingTxn(rawKey []byte) (locked, ok bool) {
+	key, err := DecodeMVCCKey(rawKey)
+	if err != nil {
+		p.err = err
+		return false, false
+	}
+	str := lock.None
+	if p.failOnMoreRecent {
+		str = lock.Exclusive
+	}
+	locked, _ = p.lockTable.IsKeyLockedByConflictingTxn(key.Key, str)
+	return locked, true
+}
+
 // Seeks to the latest revision of the current key that's still less than or
 // equal to the specified timestamp, adds it to the result set, then moves onto
 // the next user key.
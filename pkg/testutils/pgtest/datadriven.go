@@ -16,6 +16,8 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cockroachdb/cockroach/pkg/testutils/skip"
 	"github.com/cockroachdb/datadriven"
@@ -215,6 +217,22 @@ func MsgsToJSONWithIgnore(msgs []pgproto3.BackendMessage, args *datadriven.TestD
 			}); err != nil {
 				panic(err)
 			}
+		} else if copyData, ok := msg.(*pgproto3.CopyData); ok {
+			// Use the same representation as the one used to send CopyData
+			// messages, so that text data is readable.
+			data := struct {
+				Type       string
+				Data       string `json:",omitempty"`
+				BinaryData []byte `json:",omitempty"`
+			}{Type: "CopyData"}
+			if isPrintable(copyData.Data) {
+				data.Data = string(copyData.Data)
+			} else {
+				data.BinaryData = copyData.Data
+			}
+			if err := enc.Encode(data); err != nil {
+				panic(err)
+			}
 		} else if err := enc.Encode(msg); err != nil {
 			panic(err)
 		}
@@ -222,6 +240,20 @@ func MsgsToJSONWithIgnore(msgs []pgproto3.BackendMessage, args *datadriven.TestD
 	return sb.String()
 }
 
+// isPrintable returns whether b is valid UTF-8 text containing no control
+// characters other than tabs and newlines.
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
 func toMessage(typ string) interface{} {
 	switch typ {
 	case "Bind":
@@ -80,8 +80,9 @@ func NewPGTest(ctx context.Context, addr, user string) (*PGTest, error) {
 	if backendKeyData == nil {
 		return nil, errors.Errorf("did not receive BackendKeyData")
 	}
-	if foundCrdb && (backendKeyData.ProcessID != 0 || backendKeyData.SecretKey != 0) {
-		return nil, errors.Errorf("unexpected BackendKeyData: %+v", d)
+	// CockroachDB sends a random, positive, process ID.
+	if foundCrdb && int32(backendKeyData.ProcessID) <= 0 {
+		return nil, errors.Errorf("unexpected BackendKeyData: %+v", *backendKeyData)
 	}
 	p.isCockroachDB = foundCrdb
 	success = err == nil
@@ -99,8 +99,11 @@ func InjectDescriptors(
 				return errors.Wrapf(err, "failed to inject descriptor %d", id)
 			}
 		}
-		// Inject the namespace entries.
+		// Inject the namespace entries. Functions do not have any.
 		for _, d := range others {
+			if _, isFunction := d.Union.(*descpb.Descriptor_Function); isFunction {
+				continue
+			}
 			id, _, name, _, _, err := descpb.GetDescriptorMetadata(d)
 			if err != nil {
 				return err
@@ -145,5 +148,7 @@ func resetVersionAndModificationTime(d *descpb.Descriptor) {
 		d.Type.Version = 1
 	case *descpb.Descriptor_Table:
 		d.Table.Version = 1
+	case *descpb.Descriptor_Function:
+		d.Function.Version = 1
 	}
 }
@@ -1004,6 +1004,10 @@ var charts = []sectionDescription{
 				Title:   "Auto-Retries",
 				Metrics: []string{"txn.refresh.auto_retries"},
 			},
+			{
+				Title:   "Statement Retries",
+				Metrics: []string{"txn.statement_retries"},
+			},
 			{
 				Title: "Commits",
 				Metrics: []string{
@@ -1655,6 +1659,24 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{SQLLayer, "Notifications"}},
+		Charts: []chartDescription{
+			{
+				Title: "Delivered and Dropped",
+				Metrics: []string{
+					"sql.notifications.delivered",
+					"sql.notifications.dropped",
+				},
+			},
+			{
+				Title: "Rangefeed Restarts",
+				Metrics: []string{
+					"sql.notifications.rangefeed_restarts",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{SQLLayer, "SQL Liveness"}},
 		Charts: []chartDescription{
@@ -2538,6 +2560,19 @@ var charts = []sectionDescription{
 			},
 		},
 	},
+	{
+		Organization: [][]string{{Jobs, "Schedules", "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Counts",
+				Metrics: []string{
+					"schedules.scheduled-row-level-ttl-executor.started",
+					"schedules.scheduled-row-level-ttl-executor.succeeded",
+					"schedules.scheduled-row-level-ttl-executor.failed",
+				},
+			},
+		},
+	},
 	{
 		Organization: [][]string{{Jobs, "Execution"}},
 		Charts: []chartDescription{
@@ -2559,6 +2594,9 @@ var charts = []sectionDescription{
 					"jobs.auto_span_config_reconciliation.currently_running",
 					"jobs.auto_sql_stats_compaction.currently_running",
 					"jobs.stream_replication.currently_running",
+					"jobs.row_level_ttl.currently_running",
+					"jobs.replication_slot.currently_running",
+					"jobs.subscription.currently_running",
 				},
 			},
 			{
@@ -2735,6 +2773,81 @@ var charts = []sectionDescription{
 					"jobs.auto_sql_stats_compaction.resume_retry_error",
 				},
 			},
+			{
+				Title: "Row Level TTL",
+				Metrics: []string{
+					"jobs.row_level_ttl.fail_or_cancel_completed",
+					"jobs.row_level_ttl.fail_or_cancel_failed",
+					"jobs.row_level_ttl.fail_or_cancel_retry_error",
+					"jobs.row_level_ttl.resume_completed",
+					"jobs.row_level_ttl.resume_failed",
+					"jobs.row_level_ttl.resume_retry_error",
+				},
+			},
+			{
+				Title: "Replication Slot",
+				Metrics: []string{
+					"jobs.replication_slot.fail_or_cancel_completed",
+					"jobs.replication_slot.fail_or_cancel_failed",
+					"jobs.replication_slot.fail_or_cancel_retry_error",
+					"jobs.replication_slot.resume_completed",
+					"jobs.replication_slot.resume_failed",
+					"jobs.replication_slot.resume_retry_error",
+				},
+			},
+			{
+				Title: "Subscription",
+				Metrics: []string{
+					"jobs.subscription.fail_or_cancel_completed",
+					"jobs.subscription.fail_or_cancel_failed",
+					"jobs.subscription.fail_or_cancel_retry_error",
+					"jobs.subscription.resume_completed",
+					"jobs.subscription.resume_failed",
+					"jobs.subscription.resume_retry_error",
+				},
+			},
+		},
+	},
+	{
+		Organization: [][]string{{Jobs, "Row Level TTL"}},
+		Charts: []chartDescription{
+			{
+				Title: "Rows Processed",
+				Metrics: []string{
+					"jobs.row_level_ttl.rows_selected",
+					"jobs.row_level_ttl.rows_deleted",
+				},
+				AxisLabel: "Rows",
+				Rate:      DescribeDerivative_NON_NEGATIVE_DERIVATIVE,
+			},
+			{
+				Title: "Active Ranges",
+				Metrics: []string{
+					"jobs.row_level_ttl.num_active_ranges",
+				},
+				AxisLabel: "Ranges",
+			},
+			{
+				Title: "Range Processing Duration",
+				Metrics: []string{
+					"jobs.row_level_ttl.range_total_duration",
+				},
+				AxisLabel: "Duration",
+			},
+			{
+				Title: "Select Duration",
+				Metrics: []string{
+					"jobs.row_level_ttl.select_duration",
+				},
+				AxisLabel: "Duration",
+			},
+			{
+				Title: "Delete Duration",
+				Metrics: []string{
+					"jobs.row_level_ttl.delete_duration",
+				},
+				AxisLabel: "Duration",
+			},
 		},
 	},
 	{
@@ -90,6 +90,9 @@ var histogramMetricsNames = map[string]struct{}{
 	"txnwaitqueue.pusher.wait_time":             {},
 	"txnwaitqueue.query.wait_time":              {},
 	"raft.process.applycommitted.latency":       {},
+	"jobs.row_level_ttl.range_total_duration":   {},
+	"jobs.row_level_ttl.select_duration":        {},
+	"jobs.row_level_ttl.delete_duration":        {},
 }
 
 func allInternalTSMetricsNames() []string {
@@ -507,6 +507,7 @@ export class StatementDetails extends React.Component<
     const explainPlan =
       stats.sensitive_info && stats.sensitive_info.most_recent_plan_description;
     const explainGlobalProps = { distribution: distSQL, vectorized: vec };
+    const indexRecommendations = stats.index_recommendations || [];
     const duration = (v: number) => Duration(v * 1e9);
     const hasDiagnosticReports = diagnosticsReports.length > 0;
     const lastExec =
@@ -814,6 +815,14 @@ export class StatementDetails extends React.Component<
               globalProperties={explainGlobalProps}
             />
           </SummaryCard>
+          {indexRecommendations.length > 0 && (
+            <SummaryCard>
+              <Heading type="h5">Index recommendations</Heading>
+              {indexRecommendations.map(rec => (
+                <SqlBox value={rec} key={rec} />
+              ))}
+            </SummaryCard>
+          )}
         </TabPane>
         <TabPane
           tab="Execution Stats"
@@ -15,6 +15,7 @@ import {
   TimestampToNumber,
   DurationToNumber,
   uniqueLong,
+  unique,
 } from "src/util";
 
 export type StatementStatistics = protos.cockroach.sql.IStatementStatistics;
@@ -174,6 +175,10 @@ export function addStatementStats(
         ? a.last_exec_timestamp
         : b.last_exec_timestamp,
     nodes: uniqueLong([...a.nodes, ...b.nodes]),
+    index_recommendations: unique([
+      ...(a.index_recommendations || []),
+      ...(b.index_recommendations || []),
+    ]),
   };
 }
 
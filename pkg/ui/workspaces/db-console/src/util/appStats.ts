@@ -15,7 +15,7 @@
 import _ from "lodash";
 import * as protos from "src/js/protos";
 import { FixLong } from "src/util/fixLong";
-import { uniqueLong } from "src/util/arrays";
+import { unique, uniqueLong } from "src/util/arrays";
 import { util } from "@cockroachlabs/cluster-ui";
 
 export type ISensitiveInfo = protos.cockroach.sql.ISensitiveInfo;
@@ -99,6 +99,10 @@ export function addStatementStats(
         ? a.last_exec_timestamp
         : b.last_exec_timestamp,
     nodes: uniqueLong([...a.nodes, ...b.nodes]),
+    index_recommendations: unique([
+      ...(a.index_recommendations || []),
+      ...(b.index_recommendations || []),
+    ]),
   };
 }
 
@@ -0,0 +1,226 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/errors"
+)
+
+// datetimeKind is the SQL type of a datetime item.
+type datetimeKind uint8
+
+const (
+	date datetimeKind = iota
+	timeOfDay
+	timeOfDayTZ
+	timestamp
+	timestampTZ
+)
+
+// typeName returns the name of the kind, as returned by .type().
+func (k datetimeKind) typeName() string {
+	switch k {
+	case date:
+		return "date"
+	case timeOfDay:
+		return "time without time zone"
+	case timeOfDayTZ:
+		return "time with time zone"
+	case timestamp:
+		return "timestamp without time zone"
+	}
+	return "timestamp with time zone"
+}
+
+// hasTZ returns whether values of the kind have a time zone.
+func (k datetimeKind) hasTZ() bool {
+	return k == timeOfDayTZ || k == timestampTZ
+}
+
+// hasDate returns whether values of the kind have a date.
+func (k datetimeKind) hasDate() bool {
+	return k == date || k == timestamp || k == timestampTZ
+}
+
+// datetime is an item produced by the .datetime() method. Items without a
+// time zone are stored in UTC, and times of day on January 1st of year 0.
+type datetime struct {
+	kind datetimeKind
+	t    time.Time
+}
+
+// String returns the ISO 8601 representation of the item, which is how it is
+// converted to JSON.
+func (d datetime) String() string {
+	switch d.kind {
+	case date:
+		return d.t.Format("2006-01-02")
+	case timeOfDay:
+		return d.t.Format("15:04:05.999999")
+	case timeOfDayTZ:
+		return d.t.Format("15:04:05.999999-07:00")
+	case timestamp:
+		return d.t.Format("2006-01-02T15:04:05.999999")
+	}
+	return d.t.Format("2006-01-02T15:04:05.999999-07:00")
+}
+
+var (
+	dateRegexp = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})`)
+	timeRegexp = regexp.MustCompile(
+		`^(\d{2}):(\d{2}):(\d{2})(?:\.(\d{1,6}))?(?:([+-])(\d{1,2})(?::(\d{2}))?)?$`)
+)
+
+// parseDatetime parses s as a date, a time of day or a timestamp, with or
+// without a time zone offset, in one of the ISO 8601 formats that .datetime()
+// recognizes when no template is given.
+func parseDatetime(s string) (datetime, error) {
+	notRecognized := func() error {
+		return errors.WithHint(
+			pgerror.Newf(pgcode.InvalidArgumentForSQLJSONDatetimeFunction,
+				"datetime format is not recognized: %q", s),
+			"Use a datetime template argument to specify the input data format.")
+	}
+	var d datetime
+	year, month, day := 0, 1, 1
+	rest := s
+	if m := dateRegexp.FindStringSubmatch(s); m != nil {
+		year, month, day = atoi(m[1]), atoi(m[2]), atoi(m[3])
+		rest = s[len(m[0]):]
+		if rest == "" {
+			d.kind = date
+		} else if rest[0] == ' ' || rest[0] == 'T' {
+			rest = rest[1:]
+			d.kind = timestamp
+		} else {
+			return datetime{}, notRecognized()
+		}
+	} else {
+		d.kind = timeOfDay
+	}
+	var hour, minute, second, nanos int
+	loc := time.UTC
+	if d.kind != date {
+		m := timeRegexp.FindStringSubmatch(rest)
+		if m == nil {
+			return datetime{}, notRecognized()
+		}
+		hour, minute, second = atoi(m[1]), atoi(m[2]), atoi(m[3])
+		if m[4] != "" {
+			nanos = atoi(m[4])
+			for i := len(m[4]); i < 9; i++ {
+				nanos *= 10
+			}
+		}
+		if m[5] != "" {
+			offset := atoi(m[6]) * 3600
+			if m[7] != "" {
+				offset += atoi(m[7]) * 60
+			}
+			if m[5] == "-" {
+				offset = -offset
+			}
+			loc = time.FixedZone("", offset)
+			d.kind++
+		}
+	}
+	d.t = time.Date(year, time.Month(month), day, hour, minute, second, nanos, loc)
+	// time.Date normalizes out of range fields, which are errors here.
+	if d.t.Year() != year || d.t.Month() != time.Month(month) || d.t.Day() != day ||
+		d.t.Hour() != hour || d.t.Minute() != minute || d.t.Second() != second {
+		return datetime{}, pgerror.Newf(pgcode.DatetimeFieldOverflow,
+			"date/time field value out of range: %q", s)
+	}
+	return d, nil
+}
+
+func atoi(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
+
+// compareDatetimes compares two datetime items. It returns ok=false if the
+// items are not comparable, which is the case of dates with times of day.
+// Comparing an item with a time zone to one without requires a time zone to
+// interpret the latter in, which is loc if useTZ is set; otherwise it is an
+// error.
+func compareDatetimes(
+	l, r datetime, useTZ bool, loc *time.Location,
+) (cmp int, ok bool, err error) {
+	if l.kind.hasDate() != r.kind.hasDate() {
+		return 0, false, nil
+	}
+	lt, rt := l.t, r.t
+	if l.kind.hasTZ() != r.kind.hasTZ() {
+		if !useTZ {
+			from, to := l.kind, r.kind
+			if from.hasTZ() {
+				from, to = to, from
+			}
+			return 0, false, errors.WithHint(
+				pgerror.Newf(pgcode.FeatureNotSupported,
+					"cannot convert value from %s to %s without time zone usage",
+					from.sqlName(), to.sqlName()),
+				"Use *_tz() function for time zone support.")
+		}
+		// Interpret the item without a time zone in loc.
+		if !l.kind.hasTZ() {
+			lt = inLocation(lt, loc)
+		} else {
+			rt = inLocation(rt, loc)
+		}
+	}
+	if !l.kind.hasDate() {
+		// Compare times of day by their time in UTC. The date of times of day
+		// with a time zone might change when converted to UTC, so it is
+		// ignored.
+		lt, rt = timeOfDayUTC(lt), timeOfDayUTC(rt)
+	}
+	switch {
+	case lt.Before(rt):
+		return -1, true, nil
+	case lt.After(rt):
+		return 1, true, nil
+	}
+	return 0, true, nil
+}
+
+// sqlName returns the SQL name of the type of the kind, as used in errors.
+func (k datetimeKind) sqlName() string {
+	switch k {
+	case date:
+		return "date"
+	case timeOfDay:
+		return "time"
+	case timeOfDayTZ:
+		return "timetz"
+	case timestamp:
+		return "timestamp"
+	}
+	return "timestamptz"
+}
+
+// inLocation returns the time with the same wall clock as t, in loc.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// timeOfDayUTC returns the time of day of t in UTC, on January 1st of year 0.
+func timeOfDayUTC(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(0, time.January, 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// encodingVersion is the version of the binary encoding of paths. Like in
+// Postgres, whose binary wire format is the same, a path is encoded as the
+// version followed by its canonical text representation.
+const encodingVersion = 1
+
+// EncodePath appends the binary encoding of the path to appendTo.
+func EncodePath(appendTo []byte, p Path) []byte {
+	appendTo = append(appendTo, encodingVersion)
+	return append(appendTo, p.String()...)
+}
+
+// DecodePath decodes a path encoded with EncodePath.
+func DecodePath(b []byte) (Path, error) {
+	if len(b) == 0 || b[0] != encodingVersion {
+		return Path{}, pgerror.New(pgcode.InvalidBinaryRepresentation,
+			"unsupported jsonpath version number")
+	}
+	return Parse(string(b[1:]))
+}
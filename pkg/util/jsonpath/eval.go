@@ -0,0 +1,961 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/errorutil/unimplemented"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// decimalCtx is the context of divisions, which can have an infinite
+	// number of digits. It matches tree.DecimalCtx.
+	decimalCtx = &apd.Context{
+		Precision:   20,
+		Rounding:    apd.RoundHalfUp,
+		MaxExponent: 2000,
+		MinExponent: -2000,
+		Traps:       apd.DefaultTraps,
+	}
+	// exactCtx is the context of the other arithmetic operations.
+	exactCtx = decimalCtx.WithPrecision(0)
+	// highPrecisionCtx is the context of remainders, which need the quotient
+	// to fit in the precision. It matches tree.HighPrecisionCtx.
+	highPrecisionCtx = decimalCtx.WithPrecision(2000)
+	// truncateCtx truncates array subscripts to integers.
+	truncateCtx = func() *apd.Context {
+		c := exactCtx.WithPrecision(0)
+		c.Rounding = apd.RoundDown
+		return c
+	}()
+)
+
+// errNotSilenceable marks the errors that are raised even in silent mode, and
+// that don't make predicates unknown.
+var errNotSilenceable = errors.New("not silenceable")
+
+// IsSilenceable returns whether an error returned by Eval would have been
+// suppressed by silent mode.
+func IsSilenceable(err error) bool {
+	return !errors.Is(err, errNotSilenceable)
+}
+
+// EvalOptions holds the arguments of the evaluation of a path.
+type EvalOptions struct {
+	// Vars is an object holding the values of the variables of the path, or
+	// nil if there are none.
+	Vars json.JSON
+	// Silent suppresses the errors about missing keys, unexpected item types,
+	// and numeric and datetime errors. A path that runs into one of them
+	// evaluates to an empty sequence.
+	Silent bool
+	// UseTZ allows comparisons of datetime items with and without time zones,
+	// which interpret the latter in Location.
+	UseTZ    bool
+	Location *time.Location
+}
+
+// Eval evaluates the path against the given document, and returns the
+// sequence of items that it selects. Datetime items are returned as strings.
+func (p Path) Eval(doc json.JSON, opts EvalOptions) ([]json.JSON, error) {
+	if opts.Vars != nil && opts.Vars.Type() != json.ObjectJSONType && opts.Vars.Type() != json.NullJSONType {
+		return nil, errors.Mark(pgerror.New(pgcode.InvalidParameterValue,
+			`"vars" argument is not an object`), errNotSilenceable)
+	}
+	e := evaluator{
+		strict:                 p.strict,
+		root:                   doc,
+		opts:                   opts,
+		lastSize:               -1,
+		ignoreStructuralErrors: !p.strict,
+	}
+	items, err := e.exec(p.root, item{j: doc}, nil)
+	if err != nil {
+		if opts.Silent && IsSilenceable(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	res := make([]json.JSON, len(items))
+	for i := range items {
+		res[i] = items[i].toJSON()
+	}
+	return res, nil
+}
+
+// Exists returns whether the path selects any item of the document. If the
+// evaluation runs into an error in silent mode, the result is unknown, and ok
+// is false.
+func (p Path) Exists(doc json.JSON, opts EvalOptions) (exists bool, ok bool, err error) {
+	silent := opts.Silent
+	opts.Silent = false
+	items, err := p.Eval(doc, opts)
+	if err != nil {
+		if silent && IsSilenceable(err) {
+			return false, false, nil
+		}
+		return false, false, err
+	}
+	return len(items) > 0, true, nil
+}
+
+// Match returns the result of a path that is a predicate. A null result is
+// unknown, and ok is false. It is an error for the path to select anything
+// other than a single boolean or null, unless in silent mode, where the result
+// is unknown.
+func (p Path) Match(doc json.JSON, opts EvalOptions) (match bool, ok bool, err error) {
+	items, err := p.Eval(doc, opts)
+	if err != nil {
+		return false, false, err
+	}
+	if len(items) == 1 {
+		if b, isBool := items[0].AsBool(); isBool {
+			return b, true, nil
+		}
+		if items[0].Type() == json.NullJSONType {
+			return false, false, nil
+		}
+	}
+	if opts.Silent {
+		return false, false, nil
+	}
+	return false, false, pgerror.New(pgcode.SingletonSQLJSONItemRequired,
+		"single boolean result is expected")
+}
+
+// item is an item of a sequence produced by the evaluation of a path: a JSON
+// value, or a datetime produced by .datetime().
+type item struct {
+	j  json.JSON
+	dt *datetime
+}
+
+func (it item) toJSON() json.JSON {
+	if it.dt != nil {
+		return json.FromString(it.dt.String())
+	}
+	return it.j
+}
+
+// isArray returns whether the item is a JSON array.
+func (it item) isArray() bool {
+	return it.dt == nil && it.j.Type() == json.ArrayJSONType
+}
+
+// isType returns whether the item is a JSON value of the given type.
+func (it item) isType(typ json.Type) bool {
+	return it.dt == nil && it.j.Type() == typ
+}
+
+// typeName returns the name of the type of the item, as returned by .type().
+func (it item) typeName() string {
+	if it.dt != nil {
+		return it.dt.kind.typeName()
+	}
+	switch it.j.Type() {
+	case json.NullJSONType:
+		return "null"
+	case json.StringJSONType:
+		return "string"
+	case json.NumberJSONType:
+		return "number"
+	case json.FalseJSONType, json.TrueJSONType:
+		return "boolean"
+	case json.ArrayJSONType:
+		return "array"
+	}
+	return "object"
+}
+
+// elements returns the elements of an array item.
+func (it item) elements() ([]item, error) {
+	n := it.j.Len()
+	elems := make([]item, n)
+	for i := range elems {
+		j, err := it.j.FetchValIdx(i)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = item{j: j}
+	}
+	return elems, nil
+}
+
+// values returns the values of an object item, in key order.
+func (it item) values() ([]item, error) {
+	iter, err := it.j.ObjectIter()
+	if err != nil || iter == nil {
+		return nil, err
+	}
+	var values []item
+	for iter.Next() {
+		values = append(values, item{j: iter.Value()})
+	}
+	return values, nil
+}
+
+// predResult is the result of a predicate, in three-valued logic.
+type predResult uint8
+
+const (
+	predFalse predResult = iota
+	predTrue
+	predUnknown
+)
+
+func (r predResult) toJSON() json.JSON {
+	switch r {
+	case predFalse:
+		return json.FalseJSONValue
+	case predTrue:
+		return json.TrueJSONValue
+	}
+	return json.NullJSONValue
+}
+
+type evaluator struct {
+	strict bool
+	root   json.JSON
+	opts   EvalOptions
+	// current is the item that @ refers to.
+	current item
+	// lastSize is the size of the array in whose subscripts the evaluation
+	// is, or -1 outside of subscripts.
+	lastSize int
+	// keyValueID is the id of the next object that .keyvalue() is applied to.
+	keyValueID int
+	// ignoreStructuralErrors is set in lax mode, and while evaluating the
+	// accessors that follow .**.
+	ignoreStructuralErrors bool
+}
+
+// structuralError returns an error about the structure of the document, such
+// as a missing key, or nil if such errors are ignored.
+func (e *evaluator) structuralError(code pgcode.Code, msg string, args ...interface{}) error {
+	if e.ignoreStructuralErrors {
+		return nil
+	}
+	return pgerror.Newf(code, msg, args...)
+}
+
+// next applies the accessors that follow n to it, and appends the resulting
+// items to out.
+func (e *evaluator) next(n *node, it item, out []item) ([]item, error) {
+	if n.next == nil {
+		return append(out, it), nil
+	}
+	return e.exec(n.next, it, out)
+}
+
+// execUnwrapped applies n to each of the elements of the array item.
+func (e *evaluator) execUnwrapped(n *node, it item, out []item) ([]item, error) {
+	elems, err := it.elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, elem := range elems {
+		if out, err = e.execOpt(n, elem, false /* unwrap */, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// evalOperand evaluates an operand of an operator or a predicate. In lax
+// mode, the arrays that it evaluates to are unwrapped.
+func (e *evaluator) evalOperand(n *node, it item) ([]item, error) {
+	items, err := e.exec(n, it, nil)
+	if err != nil || e.strict {
+		return items, err
+	}
+	var unwrapped []item
+	for _, it := range items {
+		if !it.isArray() {
+			unwrapped = append(unwrapped, it)
+			continue
+		}
+		elems, err := it.elements()
+		if err != nil {
+			return nil, err
+		}
+		unwrapped = append(unwrapped, elems...)
+	}
+	return unwrapped, nil
+}
+
+// exec evaluates n and its accessors, where it is the item that the accessor
+// n is applied to, and appends the resulting items to out.
+func (e *evaluator) exec(n *node, it item, out []item) ([]item, error) {
+	return e.execOpt(n, it, !e.strict /* unwrap */, out)
+}
+
+// execOpt is like exec. If unwrap is set, the accessors that expect an object
+// or a scalar are applied to each of the elements of an array item.
+func (e *evaluator) execOpt(n *node, it item, unwrap bool, out []item) ([]item, error) {
+	switch n.kind {
+	case literal:
+		return e.next(n, item{j: n.value}, out)
+
+	case root:
+		return e.next(n, item{j: e.root}, out)
+
+	case current:
+		return e.next(n, e.current, out)
+
+	case variable:
+		var val json.JSON
+		if e.opts.Vars != nil && e.opts.Vars.Type() == json.ObjectJSONType {
+			var err error
+			if val, err = e.opts.Vars.FetchValKey(n.str); err != nil {
+				return nil, err
+			}
+		}
+		if val == nil {
+			return nil, errors.Mark(pgerror.Newf(pgcode.UndefinedObject,
+				"could not find jsonpath variable %q", n.str), errNotSilenceable)
+		}
+		return e.next(n, item{j: val}, out)
+
+	case last:
+		if e.lastSize < 0 {
+			return nil, errors.AssertionFailedf("evaluating jsonpath LAST outside of array subscript")
+		}
+		return e.next(n, item{j: json.FromInt(e.lastSize - 1)}, out)
+
+	case and, or, not, isUnknown, equal, notEqual, less, lessOrEqual, greater, greaterOrEqual,
+		startsWith, likeRegex, exists:
+		res, err := e.evalPredicate(n, it)
+		if err != nil {
+			return nil, err
+		}
+		return e.next(n, item{j: res.toJSON()}, out)
+
+	case add, sub, mul, div, mod:
+		return e.execBinaryArithmetic(n, it, out)
+
+	case plus, minus:
+		operands, err := e.evalOperand(n.left, it)
+		if err != nil {
+			return nil, err
+		}
+		for _, operand := range operands {
+			d, ok := operand.j.AsDecimal()
+			if !ok || operand.dt != nil {
+				return nil, pgerror.Newf(pgcode.SQLJSONNumberNotFound,
+					"operand of unary jsonpath operator %s is not a numeric value", operatorNames[n.kind])
+			}
+			if n.kind == minus {
+				var neg apd.Decimal
+				neg.Neg(d)
+				operand = item{j: json.FromDecimal(neg)}
+			}
+			if out, err = e.next(n, operand, out); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	case key:
+		switch {
+		case it.isType(json.ObjectJSONType):
+			val, err := it.j.FetchValKey(n.str)
+			if err != nil {
+				return nil, err
+			}
+			if val == nil {
+				return out, e.structuralError(pgcode.SQLJSONMemberNotFound,
+					"JSON object does not contain key %q", n.str)
+			}
+			return e.next(n, item{j: val}, out)
+		case unwrap && it.isArray():
+			return e.execUnwrapped(n, it, out)
+		}
+		return out, e.structuralError(pgcode.SQLJSONMemberNotFound,
+			"jsonpath member accessor can only be applied to an object")
+
+	case anyKey:
+		switch {
+		case it.isType(json.ObjectJSONType):
+			values, err := it.values()
+			if err != nil {
+				return nil, err
+			}
+			for _, val := range values {
+				if out, err = e.next(n, val, out); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		case unwrap && it.isArray():
+			return e.execUnwrapped(n, it, out)
+		}
+		return out, e.structuralError(pgcode.SQLJSONObjectNotFound,
+			"jsonpath wildcard member accessor can only be applied to an object")
+
+	case anyArray:
+		if !it.isArray() {
+			if e.strict {
+				return out, e.structuralError(pgcode.SQLJSONArrayNotFound,
+					"jsonpath wildcard array accessor can only be applied to an array")
+			}
+			return e.next(n, it, out)
+		}
+		elems, err := it.elements()
+		if err != nil {
+			return nil, err
+		}
+		for _, elem := range elems {
+			if out, err = e.next(n, elem, out); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	case indexArray:
+		return e.execIndexArray(n, it, out)
+
+	case anyPath:
+		var err error
+		if n.first == 0 {
+			if out, err = e.nextIgnoringStructuralErrors(n, it, out); err != nil {
+				return nil, err
+			}
+		}
+		return e.execAnyPath(n, it, 1 /* level */, out)
+
+	case filter:
+		if unwrap && it.isArray() {
+			return e.execUnwrapped(n, it, out)
+		}
+		res, err := e.evalFilter(n.left, it)
+		if err != nil {
+			return nil, err
+		}
+		if res != predTrue {
+			return out, nil
+		}
+		return e.next(n, it, out)
+
+	default:
+		return e.execMethod(n, it, unwrap, out)
+	}
+}
+
+// execIndexArray evaluates an array accessor with subscripts. In lax mode, an
+// item that isn't an array is accessed as an array of one element.
+func (e *evaluator) execIndexArray(n *node, it item, out []item) ([]item, error) {
+	var elems []item
+	if it.isArray() {
+		var err error
+		if elems, err = it.elements(); err != nil {
+			return nil, err
+		}
+	} else if e.strict {
+		return out, e.structuralError(pgcode.SQLJSONArrayNotFound,
+			"jsonpath array accessor can only be applied to an array")
+	} else {
+		elems = []item{it}
+	}
+	prevLastSize := e.lastSize
+	e.lastSize = len(elems)
+	defer func() { e.lastSize = prevLastSize }()
+	for _, s := range n.subscripts {
+		from, err := e.evalSubscript(s.from, it)
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if s.to != nil {
+			if to, err = e.evalSubscript(s.to, it); err != nil {
+				return nil, err
+			}
+		}
+		if from < 0 || from > to || to >= len(elems) {
+			if err := e.structuralError(pgcode.InvalidSQLJSONSubscript,
+				"jsonpath array subscript is out of bounds"); err != nil {
+				return nil, err
+			}
+			if from < 0 {
+				from = 0
+			}
+			if to >= len(elems) {
+				to = len(elems) - 1
+			}
+		}
+		for i := from; i <= to; i++ {
+			if out, err = e.next(n, elems[i], out); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return out, nil
+}
+
+// evalSubscript evaluates a subscript, which must be a single number. It is
+// truncated to an integer.
+func (e *evaluator) evalSubscript(n *node, it item) (int, error) {
+	items, err := e.evalOperand(n, it)
+	if err != nil {
+		return 0, err
+	}
+	var d *apd.Decimal
+	if len(items) == 1 && items[0].dt == nil {
+		d, _ = items[0].j.AsDecimal()
+	}
+	if d == nil {
+		return 0, pgerror.New(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is not a single numeric value")
+	}
+	var truncated apd.Decimal
+	if _, err := truncateCtx.RoundToIntegralValue(&truncated, d); err != nil {
+		return 0, err
+	}
+	i, err := truncated.Int64()
+	if err != nil || i > math.MaxInt32 || i < math.MinInt32 {
+		return 0, pgerror.New(pgcode.InvalidSQLJSONSubscript,
+			"jsonpath array subscript is out of integer range")
+	}
+	return int(i), nil
+}
+
+// execAnyPath applies the accessors that follow a .** accessor to the items
+// that are nested in it at the given level and below, within the bounds of
+// the accessor.
+func (e *evaluator) execAnyPath(n *node, it item, level uint32, out []item) ([]item, error) {
+	if level > n.last {
+		return out, nil
+	}
+	var children []item
+	var err error
+	switch {
+	case it.isArray():
+		children, err = it.elements()
+	case it.isType(json.ObjectJSONType):
+		children, err = it.values()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if level >= n.first {
+			if out, err = e.nextIgnoringStructuralErrors(n, child, out); err != nil {
+				return nil, err
+			}
+		}
+		if out, err = e.execAnyPath(n, child, level+1, out); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// nextIgnoringStructuralErrors is like next, but ignores structural errors,
+// so that .** selects only the items that the accessors after it apply to.
+func (e *evaluator) nextIgnoringStructuralErrors(n *node, it item, out []item) ([]item, error) {
+	prev := e.ignoreStructuralErrors
+	e.ignoreStructuralErrors = true
+	defer func() { e.ignoreStructuralErrors = prev }()
+	return e.next(n, it, out)
+}
+
+// execBinaryArithmetic evaluates a binary arithmetic operator, whose operands
+// must both be single numbers.
+func (e *evaluator) execBinaryArithmetic(n *node, it item, out []item) ([]item, error) {
+	operand := func(operand *node, side string) (*apd.Decimal, error) {
+		items, err := e.evalOperand(operand, it)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 1 && items[0].dt == nil {
+			if d, ok := items[0].j.AsDecimal(); ok {
+				return d, nil
+			}
+		}
+		return nil, pgerror.Newf(pgcode.SingletonSQLJSONItemRequired,
+			"%s operand of jsonpath operator %s is not a single numeric value", side, operatorNames[n.kind])
+	}
+	l, err := operand(n.left, "left")
+	if err != nil {
+		return nil, err
+	}
+	r, err := operand(n.right, "right")
+	if err != nil {
+		return nil, err
+	}
+	var res apd.Decimal
+	switch n.kind {
+	case add:
+		_, err = exactCtx.Add(&res, l, r)
+	case sub:
+		_, err = exactCtx.Sub(&res, l, r)
+	case mul:
+		_, err = exactCtx.Mul(&res, l, r)
+	case div, mod:
+		if r.IsZero() {
+			return nil, pgerror.New(pgcode.DivisionByZero, "division by zero")
+		}
+		if n.kind == div {
+			_, err = decimalCtx.Quo(&res, l, r)
+		} else {
+			_, err = highPrecisionCtx.Rem(&res, l, r)
+		}
+	}
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.NumericValueOutOfRange, "")
+	}
+	return e.next(n, item{j: json.FromDecimal(res)}, out)
+}
+
+// execMethod evaluates an item method.
+func (e *evaluator) execMethod(n *node, it item, unwrap bool, out []item) ([]item, error) {
+	methodError := func(code pgcode.Code, expected string) error {
+		return pgerror.Newf(code,
+			"jsonpath item method .%s() can only be applied to %s", methodNames[n.kind], expected)
+	}
+	switch n.kind {
+	case typeMethod:
+		return e.next(n, item{j: json.FromString(it.typeName())}, out)
+
+	case sizeMethod:
+		if !it.isArray() {
+			if e.strict {
+				return out, e.structuralError(pgcode.SQLJSONArrayNotFound,
+					"jsonpath item method .size() can only be applied to an array")
+			}
+			return e.next(n, item{j: json.FromInt(1)}, out)
+		}
+		return e.next(n, item{j: json.FromInt(it.j.Len())}, out)
+	}
+
+	if unwrap && it.isArray() {
+		return e.execUnwrapped(n, it, out)
+	}
+	var res json.JSON
+	switch n.kind {
+	case doubleMethod:
+		var f float64
+		switch {
+		case it.isType(json.NumberJSONType):
+			d, _ := it.j.AsDecimal()
+			var err error
+			if f, err = d.Float64(); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, pgerror.New(pgcode.NonNumericSQLJSONItem,
+					"numeric argument of jsonpath item method .double() is out of range for type double precision")
+			}
+		case it.isType(json.StringJSONType):
+			s, _ := it.j.AsText()
+			var err error
+			if f, err = strconv.ParseFloat(*s, 64); err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, pgerror.New(pgcode.NonNumericSQLJSONItem,
+					"string argument of jsonpath item method .double() is not a valid representation of a double precision number")
+			}
+		default:
+			return nil, methodError(pgcode.NonNumericSQLJSONItem, "a string or numeric value")
+		}
+		var err error
+		if res, err = json.FromFloat64(f); err != nil {
+			return nil, err
+		}
+
+	case ceilingMethod, floorMethod, absMethod:
+		d, ok := it.j.AsDecimal()
+		if !ok || it.dt != nil {
+			return nil, methodError(pgcode.NonNumericSQLJSONItem, "a numeric value")
+		}
+		var r apd.Decimal
+		var err error
+		switch n.kind {
+		case ceilingMethod:
+			_, err = exactCtx.Ceil(&r, d)
+		case floorMethod:
+			_, err = exactCtx.Floor(&r, d)
+		default:
+			_, err = exactCtx.Abs(&r, d)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = json.FromDecimal(r)
+
+	case keyValueMethod:
+		if !it.isType(json.ObjectJSONType) {
+			return nil, methodError(pgcode.SQLJSONObjectNotFound, "an object")
+		}
+		iter, err := it.j.ObjectIter()
+		if err != nil {
+			return nil, err
+		}
+		id := json.FromInt(e.keyValueID)
+		e.keyValueID++
+		for iter.Next() {
+			b := json.NewObjectBuilder(3)
+			b.Add("id", id)
+			b.Add("key", json.FromString(iter.Key()))
+			b.Add("value", iter.Value())
+			if out, err = e.next(n, item{j: b.Build()}, out); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+
+	case datetimeMethod:
+		if !it.isType(json.StringJSONType) {
+			return nil, methodError(pgcode.InvalidArgumentForSQLJSONDatetimeFunction, "a string")
+		}
+		if n.hasTemplate {
+			return nil, errors.Mark(unimplemented.NewWithIssue(22513,
+				"datetime templates in jsonpath are not supported"), errNotSilenceable)
+		}
+		s, _ := it.j.AsText()
+		dt, err := parseDatetime(*s)
+		if err != nil {
+			return nil, err
+		}
+		return e.next(n, item{dt: &dt}, out)
+	}
+	return e.next(n, item{j: res}, out)
+}
+
+// evalFilter evaluates the predicate of a filter, with it as the current
+// item.
+func (e *evaluator) evalFilter(pred *node, it item) (predResult, error) {
+	prev := e.current
+	e.current = it
+	defer func() { e.current = prev }()
+	return e.evalPredicate(pred, it)
+}
+
+// evalPredicate evaluates a predicate. Errors make the result unknown, except
+// for the errors that are not silenceable, which are returned.
+func (e *evaluator) evalPredicate(n *node, it item) (predResult, error) {
+	switch n.kind {
+	case and, or:
+		l, err := e.evalPredicate(n.left, it)
+		if err != nil {
+			return predUnknown, err
+		}
+		// Evaluate the right operand only if it can change the result.
+		if (n.kind == and && l == predFalse) || (n.kind == or && l == predTrue) {
+			return l, nil
+		}
+		r, err := e.evalPredicate(n.right, it)
+		if err != nil {
+			return predUnknown, err
+		}
+		switch {
+		case r == predUnknown:
+			return predUnknown, nil
+		case l == predUnknown:
+			if (n.kind == and && r == predFalse) || (n.kind == or && r == predTrue) {
+				return r, nil
+			}
+			return predUnknown, nil
+		}
+		return r, nil
+
+	case not:
+		res, err := e.evalPredicate(n.left, it)
+		switch {
+		case err != nil || res == predUnknown:
+			return predUnknown, err
+		case res == predTrue:
+			return predFalse, nil
+		}
+		return predTrue, nil
+
+	case isUnknown:
+		res, err := e.evalPredicate(n.left, it)
+		if err != nil {
+			return predUnknown, err
+		}
+		if res == predUnknown {
+			return predTrue, nil
+		}
+		return predFalse, nil
+
+	case exists:
+		items, err := e.exec(n.left, it, nil)
+		if err != nil {
+			return e.unknown(err)
+		}
+		if len(items) > 0 {
+			return predTrue, nil
+		}
+		return predFalse, nil
+
+	case likeRegex:
+		return e.evalComparison(n, it, func(l, _ item) (predResult, error) {
+			if !l.isType(json.StringJSONType) {
+				return predUnknown, nil
+			}
+			s, _ := l.j.AsText()
+			if n.regex.MatchString(*s) {
+				return predTrue, nil
+			}
+			return predFalse, nil
+		})
+
+	case startsWith:
+		return e.evalComparison(n, it, func(l, r item) (predResult, error) {
+			if !l.isType(json.StringJSONType) || !r.isType(json.StringJSONType) {
+				return predUnknown, nil
+			}
+			s, _ := l.j.AsText()
+			prefix, _ := r.j.AsText()
+			if len(*s) >= len(*prefix) && (*s)[:len(*prefix)] == *prefix {
+				return predTrue, nil
+			}
+			return predFalse, nil
+		})
+
+	case equal, notEqual, less, lessOrEqual, greater, greaterOrEqual:
+		return e.evalComparison(n, it, func(l, r item) (predResult, error) {
+			return e.compareItems(n.kind, l, r)
+		})
+	}
+	return predUnknown, errors.AssertionFailedf("%v is not a predicate", n.kind)
+}
+
+// unknown returns the result of a predicate whose evaluation ran into err.
+func (e *evaluator) unknown(err error) (predResult, error) {
+	if !IsSilenceable(err) {
+		return predUnknown, err
+	}
+	return predUnknown, nil
+}
+
+// evalComparison evaluates a predicate that compares the items of its left
+// operand to those of its right operand, if any. The predicate is true if the
+// comparison of any pair of items is true. If any comparison is unknown, so is
+// the predicate, except that lax mode stops at the first true comparison.
+func (e *evaluator) evalComparison(
+	n *node, it item, cmp func(l, r item) (predResult, error),
+) (predResult, error) {
+	left, err := e.evalOperand(n.left, it)
+	if err != nil {
+		return e.unknown(err)
+	}
+	right := []item{{}}
+	if n.right != nil {
+		if right, err = e.evalOperand(n.right, it); err != nil {
+			return e.unknown(err)
+		}
+	}
+	var found, unknown bool
+	for _, l := range left {
+		for _, r := range right {
+			res, err := cmp(l, r)
+			if err != nil {
+				return e.unknown(err)
+			}
+			switch res {
+			case predUnknown:
+				if e.strict {
+					return predUnknown, nil
+				}
+				unknown = true
+			case predTrue:
+				if !e.strict {
+					return predTrue, nil
+				}
+				found = true
+			}
+		}
+	}
+	switch {
+	case found:
+		return predTrue, nil
+	case unknown:
+		return predUnknown, nil
+	}
+	return predFalse, nil
+}
+
+// compareItems applies a comparison operator to two items. Items of different
+// types can't be compared, except that null is not equal to anything else.
+// Arrays and objects can't be compared either.
+func (e *evaluator) compareItems(op nodeKind, l, r item) (predResult, error) {
+	var cmp int
+	switch {
+	case l.dt != nil && r.dt != nil:
+		var ok bool
+		var err error
+		cmp, ok, err = compareDatetimes(*l.dt, *r.dt, e.opts.UseTZ, e.location())
+		if err != nil {
+			return predUnknown, errors.Mark(err, errNotSilenceable)
+		}
+		if !ok {
+			return predUnknown, nil
+		}
+	case l.dt != nil || r.dt != nil:
+		if l.isType(json.NullJSONType) || r.isType(json.NullJSONType) {
+			return boolResult(op == notEqual), nil
+		}
+		return predUnknown, nil
+	default:
+		lt, rt := l.j.Type(), r.j.Type()
+		// Booleans have a type per value.
+		if lt == json.TrueJSONType {
+			lt = json.FalseJSONType
+		}
+		if rt == json.TrueJSONType {
+			rt = json.FalseJSONType
+		}
+		if lt != rt {
+			if lt == json.NullJSONType || rt == json.NullJSONType {
+				return boolResult(op == notEqual), nil
+			}
+			return predUnknown, nil
+		}
+		if lt == json.ArrayJSONType || lt == json.ObjectJSONType {
+			return predUnknown, nil
+		}
+		var err error
+		if cmp, err = l.j.Compare(r.j); err != nil {
+			return predUnknown, err
+		}
+	}
+	switch op {
+	case equal:
+		return boolResult(cmp == 0), nil
+	case notEqual:
+		return boolResult(cmp != 0), nil
+	case less:
+		return boolResult(cmp < 0), nil
+	case lessOrEqual:
+		return boolResult(cmp <= 0), nil
+	case greater:
+		return boolResult(cmp > 0), nil
+	}
+	return boolResult(cmp >= 0), nil
+}
+
+func (e *evaluator) location() *time.Location {
+	if e.opts.Location == nil {
+		return time.UTC
+	}
+	return e.opts.Location
+}
+
+func boolResult(b bool) predResult {
+	if b {
+		return predTrue
+	}
+	return predFalse
+}
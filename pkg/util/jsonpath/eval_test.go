@@ -0,0 +1,206 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	const doc = `{
+		"a": 1,
+		"b": [1, 2, 3],
+		"c": {"d": "x", "e": [{"f": 1}, {"f": 2}]},
+		"g": null,
+		"s": "hello",
+		"t": "2022-03-10 12:34:56+02",
+		"u": "2022-03-10"
+	}`
+	testCases := []struct {
+		path     string
+		vars     string
+		expected string
+		err      string
+	}{
+		{path: `$.a`, expected: `1`},
+		{path: `$.z`, expected: ``},
+		{path: `strict $.z`, err: `JSON object does not contain key "z"`},
+		{path: `$.b[*]`, expected: `1, 2, 3`},
+		{path: `$.b[0, 2]`, expected: `1, 3`},
+		{path: `$.b[1 to last]`, expected: `2, 3`},
+		{path: `$.b[last - 1]`, expected: `2`},
+		{path: `$.b[5]`, expected: ``},
+		{path: `strict $.b[5]`, err: `jsonpath array subscript is out of bounds`},
+		{path: `$.b[1.9]`, expected: `2`},
+		{path: `$.a[0]`, expected: `1`},
+		{path: `strict $.a[0]`, err: `jsonpath array accessor can only be applied to an array`},
+		{path: `$.a[*]`, expected: `1`},
+		{path: `$.c.*`, expected: `"x", [{"f": 1}, {"f": 2}]`},
+		{path: `$.c.e.f`, expected: `1, 2`},
+		{path: `strict $.c.e.f`, err: `jsonpath member accessor can only be applied to an object`},
+		{path: `strict $.c.e[*].f`, expected: `1, 2`},
+		{path: `$.c.**.f`, expected: `1, 2, 1, 2`},
+		{path: `strict $.c.**.f`, expected: `1, 2`},
+		{path: `$.c.**{1}`, expected: `"x", [{"f": 1}, {"f": 2}]`},
+		{path: `$.b ? (@ > 1)`, expected: `2, 3`},
+		{path: `strict $.b ? (@ > 1)`, expected: ``},
+		{path: `strict $.b[*] ? (@ > 1)`, expected: `2, 3`},
+		{path: `$.c.e ? (@.f == 2)`, expected: `{"f": 2}`},
+		{path: `$ ? (@.s starts with "he").a`, expected: `1`},
+		{path: `$ ? (@.s like_regex "^H" flag "i").a`, expected: `1`},
+		{path: `$ ? (@.s like_regex "^H").a`, expected: ``},
+		{path: `$ ? (exists (@.c.e ? (@.f > 1))).a`, expected: `1`},
+		{path: `$ ? (@.z == 1).a`, expected: ``},
+		{path: `$ ? ((@.s > 1) is unknown).a`, expected: `1`},
+		{path: `$ ? (!(@.a == 1)).a`, expected: ``},
+		{path: `$ ? (@.g == null).a`, expected: `1`},
+		{path: `$ ? (@.g != 1).a`, expected: `1`},
+		{path: `$.a == 1`, expected: `true`},
+		{path: `$.b == 2`, expected: `true`},
+		{path: `strict $.b == 2`, expected: `null`},
+		{path: `$.s > 1`, expected: `null`},
+		{path: `$.s == "hello" && $.a == 2`, expected: `false`},
+		{path: `$.s > 1 || $.a == 1`, expected: `true`},
+		{path: `$.a + 2 * 3`, expected: `7`},
+		{path: `$.a / 3`, expected: `0.33333333333333333333`},
+		{path: `7 % 3`, expected: `1`},
+		{path: `-$.b`, expected: `-1, -2, -3`},
+		{path: `$.a / 0`, err: `division by zero`},
+		{path: `$.s + 1`, err: `left operand of jsonpath operator + is not a single numeric value`},
+		{path: `$.b + 1`, err: `left operand of jsonpath operator + is not a single numeric value`},
+		{path: `$.*.type()`, expected: `"number", "array", "object", "null", "string", "string", "string"`},
+		{path: `$.b.size()`, expected: `3`},
+		{path: `$.a.size()`, expected: `1`},
+		{path: `strict $.a.size()`, err: `jsonpath item method .size() can only be applied to an array`},
+		{path: `$.b.abs()`, expected: `1, 2, 3`},
+		{path: `(-1.5).abs()`, expected: `1.5`},
+		{path: `(-1.5).floor()`, expected: `-2`},
+		{path: `(-1.5).ceiling()`, expected: `-1`},
+		{path: `"1.5".double()`, expected: `1.5`},
+		{path: `"abc".double()`,
+			err: `string argument of jsonpath item method .double() is not a valid representation of a double precision number`},
+		{path: `$.s.abs()`, err: `jsonpath item method .abs() can only be applied to a numeric value`},
+		{path: `$.c.keyvalue()`, expected: `{"id": 0, "key": "d", "value": "x"}, ` +
+			`{"id": 0, "key": "e", "value": [{"f": 1}, {"f": 2}]}`},
+		{path: `$.a.keyvalue()`, err: `jsonpath item method .keyvalue() can only be applied to an object`},
+		{path: `$.t.datetime()`, expected: `"2022-03-10T12:34:56+02:00"`},
+		{path: `$.t.datetime().type()`, expected: `"timestamp with time zone"`},
+		{path: `$.u.datetime().type()`, expected: `"date"`},
+		{path: `"12:34:56.5".datetime()`, expected: `"12:34:56.5"`},
+		{path: `$.s.datetime()`, err: `datetime format is not recognized: "hello"`},
+		{path: `"2022-13-01".datetime()`, err: `date/time field value out of range: "2022-13-01"`},
+		{path: `$.a.datetime()`, err: `jsonpath item method .datetime() can only be applied to a string`},
+		{path: `$.u.datetime() < "2022-03-11".datetime()`, expected: `true`},
+		{path: `$.u.datetime() < $.t.datetime()`,
+			err: `cannot convert value from date to timestamptz without time zone usage`},
+		{path: `$.u.datetime() < "12:00:00".datetime()`, expected: `null`},
+		{path: `$.u.datetime("YYYY-MM-DD")`, err: `unimplemented: datetime templates in jsonpath are not supported`},
+		{path: `$.a ? (@ == $x)`, vars: `{"x": 1}`, expected: `1`},
+		{path: `$.a ? (@ == $x)`, vars: `{"x": 2}`, expected: ``},
+		{path: `$x`, vars: `{}`, err: `could not find jsonpath variable "x"`},
+		{path: `$x`, vars: `[]`, err: `"vars" argument is not an object`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			var opts EvalOptions
+			if tc.vars != "" {
+				opts.Vars, err = json.ParseJSON(tc.vars)
+				require.NoError(t, err)
+			}
+			res, err := p.Eval(mustParseJSON(t, doc), opts)
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			strs := make([]string, len(res))
+			for i := range res {
+				strs[i] = res[i].String()
+			}
+			require.Equal(t, tc.expected, strings.Join(strs, ", "))
+		})
+	}
+}
+
+func mustParseJSON(t *testing.T, s string) json.JSON {
+	j, err := json.ParseJSON(s)
+	require.NoError(t, err)
+	return j
+}
+
+func TestEvalSilent(t *testing.T) {
+	doc := mustParseJSON(t, `{"a": [1, 2], "s": "x"}`)
+	testCases := []struct {
+		path string
+		// The results of Exists and Match, without and with silent mode.
+		exists, existsSilent, match, matchSilent string
+	}{
+		{`$.a`, `true`, `true`, `error`, `null`},
+		{`$.z`, `false`, `false`, `error`, `null`},
+		{`strict $.z`, `error`, `null`, `error`, `null`},
+		{`$.a[*] ? (@ > 1)`, `true`, `true`, `error`, `null`},
+		{`$.a[*] > 1`, `true`, `true`, `true`, `true`},
+		{`$.s > 1`, `true`, `true`, `null`, `null`},
+		{`$.s + 1`, `error`, `null`, `error`, `null`},
+	}
+	format := func(b, ok bool, err error) string {
+		switch {
+		case err != nil:
+			return "error"
+		case !ok:
+			return "null"
+		case b:
+			return "true"
+		}
+		return "false"
+	}
+	for _, tc := range testCases {
+		p, err := Parse(tc.path)
+		require.NoError(t, err)
+		exists, ok, err := p.Exists(doc, EvalOptions{})
+		require.Equal(t, tc.exists, format(exists, ok, err), tc.path)
+		exists, ok, err = p.Exists(doc, EvalOptions{Silent: true})
+		require.Equal(t, tc.existsSilent, format(exists, ok, err), tc.path)
+		match, ok, err := p.Match(doc, EvalOptions{})
+		require.Equal(t, tc.match, format(match, ok, err), tc.path)
+		match, ok, err = p.Match(doc, EvalOptions{Silent: true})
+		require.Equal(t, tc.matchSilent, format(match, ok, err), tc.path)
+	}
+}
+
+func TestEvalTZ(t *testing.T) {
+	p, err := Parse(`"2022-03-10 12:00:00".datetime() < "2022-03-10 12:30:00+01".datetime()`)
+	require.NoError(t, err)
+	_, err = p.Eval(json.NullJSONValue, EvalOptions{})
+	require.EqualError(t, err, "cannot convert value from timestamp to timestamptz without time zone usage")
+
+	for _, tc := range []struct {
+		offset   int
+		expected string
+	}{
+		{0, "false"},
+		{2 * 60 * 60, "true"},
+	} {
+		res, err := p.Eval(json.NullJSONValue, EvalOptions{
+			UseTZ: true, Location: time.FixedZone("", tc.offset),
+		})
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+		require.Equal(t, tc.expected, res[0].String())
+	}
+}
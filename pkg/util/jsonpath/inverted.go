@@ -0,0 +1,229 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// maxContainmentAlternatives bounds the number of JSON documents that a
+// comparison is turned into. In lax mode, every accessor can also go through
+// an array, which doubles the number of documents that can satisfy it.
+const maxContainmentAlternatives = 16
+
+// EncodeExistsInvertedIndexSpans takes in a key prefix and returns the spans
+// that must be scanned in a JSON inverted index to find the documents that
+// the path selects items of (jsonb @? jsonpath).
+//
+// Only the equality of an item reached through keys and arrays to a constant
+// can be evaluated with the index: it is turned into the JSON documents that
+// must be contained in the matching documents. For example, the documents
+// that $.a ? (@.b == 1) selects items of contain {"a": {"b": 1}}, or, since
+// lax mode unwraps arrays, one of {"a": [{"b": 1}]}, [{"a": {"b": 1}}], etc.
+// The returned expression is never tight.
+//
+// If the path doesn't constrain the index,
+// inverted.NonInvertedColExpression is returned.
+func (p Path) EncodeExistsInvertedIndexSpans(inKey []byte) (inverted.Expression, error) {
+	a := containmentAnalyzer{strict: p.strict, inKey: inKey}
+	return a.filtersExpr(p.root, []containmentPath{nil})
+}
+
+// EncodeMatchInvertedIndexSpans is like EncodeExistsInvertedIndexSpans, for
+// documents that the path, which must be a predicate, matches (jsonb @@
+// jsonpath).
+func (p Path) EncodeMatchInvertedIndexSpans(inKey []byte) (inverted.Expression, error) {
+	if !p.root.kind.isPredicate() || p.root.next != nil {
+		return inverted.NonInvertedColExpression{}, nil
+	}
+	a := containmentAnalyzer{strict: p.strict, inKey: inKey}
+	return a.predicateExpr(p.root, nil /* current */)
+}
+
+// containmentStep is a step from an item to one of the items nested in it:
+// the value of the key of an object if key is set, otherwise an element of an
+// array.
+type containmentStep struct {
+	key    string
+	hasKey bool
+}
+
+// containmentPath is a sequence of steps from the root of the document.
+type containmentPath []containmentStep
+
+// document returns the JSON document that contains val at the end of the
+// path.
+func (p containmentPath) document(val json.JSON) json.JSON {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].hasKey {
+			b := json.NewObjectBuilder(1)
+			b.Add(p[i].key, val)
+			val = b.Build()
+		} else {
+			b := json.NewArrayBuilder(1)
+			b.Add(val)
+			val = b.Build()
+		}
+	}
+	return val
+}
+
+type containmentAnalyzer struct {
+	strict bool
+	inKey  []byte
+}
+
+// withStep returns the paths extended with the given step. In lax mode, the
+// step can first go through an array, so there are twice as many paths.
+func (a *containmentAnalyzer) withStep(
+	paths []containmentPath, step containmentStep, unwrap bool,
+) []containmentPath {
+	var res []containmentPath
+	for _, p := range paths {
+		res = append(res, append(p[:len(p):len(p)], step))
+		if unwrap && !a.strict {
+			res = append(res, append(p[:len(p):len(p)], containmentStep{}, step))
+		}
+	}
+	return res
+}
+
+// walk follows the accessors of n, where currentPaths holds the paths of @, and
+// returns the paths of the items that n selects. It calls onFilter, if set,
+// with the predicate of each filter and the paths of the items it is applied
+// to. It returns nil if n isn't made of key, array and filter accessors, or if
+// there are too many paths, after calling onFilter on the filters that come
+// first.
+func (a *containmentAnalyzer) walk(
+	n *node,
+	currentPaths []containmentPath,
+	onFilter func(pred *node, paths []containmentPath) error,
+) ([]containmentPath, error) {
+	var paths []containmentPath
+	switch n.kind {
+	case root:
+		paths = []containmentPath{nil}
+	case current:
+		paths = currentPaths
+	}
+	for n = n.next; n != nil && paths != nil; n = n.next {
+		switch n.kind {
+		case key:
+			paths = a.withStep(paths, containmentStep{key: n.str, hasKey: true}, true /* unwrap */)
+		case anyArray, filter:
+			elemPaths := a.withStep(paths, containmentStep{}, false /* unwrap */)
+			switch {
+			case n.kind == filter && a.strict:
+			case a.strict:
+				paths = elemPaths
+			default:
+				// In lax mode, an item that isn't an array is its own element,
+				// and filters unwrap arrays.
+				paths = append(paths, elemPaths...)
+			}
+			if n.kind == filter && onFilter != nil && len(paths) <= maxContainmentAlternatives {
+				if err := onFilter(n.left, paths); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			paths = nil
+		}
+		if len(paths) > maxContainmentAlternatives {
+			paths = nil
+		}
+	}
+	return paths, nil
+}
+
+// filtersExpr returns the spans of the documents for which the filters of the
+// accessors of n can be true, where current holds the paths of @.
+func (a *containmentAnalyzer) filtersExpr(
+	n *node, current []containmentPath,
+) (inverted.Expression, error) {
+	var res inverted.Expression = inverted.NonInvertedColExpression{}
+	_, err := a.walk(n, current, func(pred *node, paths []containmentPath) error {
+		expr, err := a.predicateExpr(pred, paths)
+		if err != nil {
+			return err
+		}
+		res = inverted.And(res, expr)
+		return nil
+	})
+	return res, err
+}
+
+// predicateExpr returns the spans of the documents for which the predicate
+// can be true, where current holds the paths of @.
+func (a *containmentAnalyzer) predicateExpr(
+	n *node, current []containmentPath,
+) (inverted.Expression, error) {
+	switch n.kind {
+	case and, or:
+		l, err := a.predicateExpr(n.left, current)
+		if err != nil {
+			return nil, err
+		}
+		r, err := a.predicateExpr(n.right, current)
+		if err != nil {
+			return nil, err
+		}
+		if n.kind == and {
+			return inverted.And(l, r), nil
+		}
+		return inverted.Or(l, r), nil
+
+	case exists:
+		return a.filtersExpr(n.left, current)
+
+	case equal:
+		// One operand must be a constant, and the other reach the items that
+		// are compared to it through keys and arrays.
+		operand, constant := n.left, n.right
+		if operand.kind == literal {
+			operand, constant = constant, operand
+		}
+		if constant.kind != literal || constant.next != nil {
+			return inverted.NonInvertedColExpression{}, nil
+		}
+		paths, err := a.walk(operand, current, nil /* onFilter */)
+		if err != nil || paths == nil {
+			return inverted.NonInvertedColExpression{}, err
+		}
+		var res inverted.Expression
+		for _, p := range paths {
+			docs := []json.JSON{p.document(constant.value)}
+			if !a.strict {
+				// Comparisons unwrap arrays in lax mode.
+				docs = append(docs, append(p[:len(p):len(p)], containmentStep{}).document(constant.value))
+			}
+			for _, doc := range docs {
+				expr, err := json.EncodeContainingInvertedIndexSpans(a.inKey, doc)
+				if err != nil {
+					return nil, err
+				}
+				if res == nil {
+					res = expr
+				} else {
+					res = inverted.Or(res, expr)
+				}
+			}
+		}
+		if res == nil {
+			return inverted.NonInvertedColExpression{}, nil
+		}
+		// The comparison also has to be checked on the documents.
+		res.SetNotTight()
+		return res, nil
+	}
+	return inverted.NonInvertedColExpression{}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeInvertedIndexSpans(t *testing.T) {
+	docs := []string{
+		`{}`,
+		`[]`,
+		`1`,
+		`{"a": 1}`,
+		`{"a": 2}`,
+		`{"a": [1, 2]}`,
+		`[{"a": 1}]`,
+		`{"a": {"b": 1}}`,
+		`{"a": [{"b": 1}, {"b": 2}]}`,
+		`{"a": {"b": [1]}}`,
+		`{"a": {"b": 2}, "c": "x"}`,
+		`{"a": {"b": 1}, "c": "x"}`,
+		`{"a": [[1]]}`,
+	}
+	testCases := []struct {
+		path string
+		// match is set if the path is evaluated with jsonb @@ jsonpath rather
+		// than jsonb @? jsonpath.
+		match bool
+		// constrained is set if the path constrains the index.
+		constrained bool
+	}{
+		{path: `$.a`},
+		{path: `$.a ? (@ == 1)`, constrained: true},
+		{path: `strict $.a ? (@ == 1)`, constrained: true},
+		{path: `$.a[*] ? (@ == 1)`, constrained: true},
+		{path: `$ ? (@.a.b == 1)`, constrained: true},
+		{path: `$.a ? (@.b == 1 && @.b == 2)`, constrained: true},
+		{path: `$ ? (@.a.b == 1 || @.c == "x")`, constrained: true},
+		{path: `$ ? (@.a.b == 1 || @.c != "x")`},
+		{path: `$ ? (exists (@.a ? (1 == @.b)))`, constrained: true},
+		{path: `$.a ? (@.b > 1)`},
+		{path: `$.a ? (@.b == $x)`},
+		{path: `$.a.size() ? (@ == 1)`},
+		{path: `$.a == 1`, match: true, constrained: true},
+		{path: `strict $.a.b == 1`, match: true, constrained: true},
+		{path: `$.a.b == 1 && $.c == "x"`, match: true, constrained: true},
+		{path: `$.a.b > 1`, match: true},
+		{path: `$.a`, match: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			p, err := Parse(tc.path)
+			require.NoError(t, err)
+			var expr inverted.Expression
+			if tc.match {
+				expr, err = p.EncodeMatchInvertedIndexSpans(nil /* inKey */)
+			} else {
+				expr, err = p.EncodeExistsInvertedIndexSpans(nil /* inKey */)
+			}
+			require.NoError(t, err)
+			spanExpr, ok := expr.(*inverted.SpanExpression)
+			require.Equal(t, tc.constrained, ok)
+			if !ok {
+				return
+			}
+			require.False(t, spanExpr.Tight)
+			// Every document that satisfies the path must be found in the index.
+			for _, s := range docs {
+				doc, err := json.ParseJSON(s)
+				require.NoError(t, err)
+				var satisfied bool
+				opts := EvalOptions{Silent: true}
+				if tc.match {
+					satisfied, _, err = p.Match(doc, opts)
+				} else {
+					satisfied, _, err = p.Exists(doc, opts)
+				}
+				require.NoError(t, err)
+				keys, err := json.EncodeInvertedIndexKeys(nil, doc)
+				require.NoError(t, err)
+				found, err := spanExpr.ContainsKeys(keys)
+				require.NoError(t, err)
+				if satisfied && !found {
+					t.Errorf("%s satisfies %s but is not found in the index", s, tc.path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package jsonpath implements the SQL/JSON path language of Postgres: the
+// jsonpath type, which selects items of a JSON document, and its evaluation.
+//
+// A path is an expression over the items of the document. It starts at the
+// root item $, the current item @ of a filter, a variable, or a literal, and
+// is followed by a chain of accessors that each map an item to a sequence of
+// items: .key, .*, [subscripts], [*], .**, item methods like .size(), and
+// filters ?(predicate). Paths can be combined with arithmetic operators, and
+// predicates compare paths, with three-valued logic.
+//
+// In lax mode (the default), arrays are automatically unwrapped and wrapped
+// where needed, and structural errors such as missing keys produce empty
+// results. In strict mode, they are errors.
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// nodeKind is the kind of a node of a path.
+type nodeKind uint8
+
+const (
+	// literal is a null, boolean, number or string constant.
+	literal nodeKind = iota
+	// root is $, the document the path is evaluated against.
+	root
+	// current is @, the item being tested by the innermost filter.
+	current
+	// variable is a $name variable, whose value is passed in with the vars
+	// argument of the jsonb_path functions.
+	variable
+	// last is the last index of the array in a subscript.
+	last
+
+	// Predicates.
+	and
+	or
+	not
+	isUnknown
+	equal
+	notEqual
+	less
+	lessOrEqual
+	greater
+	greaterOrEqual
+	startsWith
+	likeRegex
+	exists
+
+	// Arithmetic operators.
+	add
+	sub
+	mul
+	div
+	mod
+	plus
+	minus
+
+	// Accessors.
+	key
+	anyKey
+	anyArray
+	indexArray
+	anyPath
+	filter
+
+	// Item methods.
+	typeMethod
+	sizeMethod
+	doubleMethod
+	ceilingMethod
+	floorMethod
+	absMethod
+	keyValueMethod
+	datetimeMethod
+)
+
+// isPredicate returns whether nodes of the kind evaluate to a truth value.
+func (k nodeKind) isPredicate() bool {
+	return k >= and && k <= exists
+}
+
+// isComparison returns whether the kind is a comparison operator.
+func (k nodeKind) isComparison() bool {
+	return k >= equal && k <= greaterOrEqual
+}
+
+var operatorNames = map[nodeKind]string{
+	and:            "&&",
+	or:             "||",
+	equal:          "==",
+	notEqual:       "!=",
+	less:           "<",
+	lessOrEqual:    "<=",
+	greater:        ">",
+	greaterOrEqual: ">=",
+	startsWith:     "starts with",
+	add:            "+",
+	sub:            "-",
+	mul:            "*",
+	div:            "/",
+	mod:            "%",
+	plus:           "+",
+	minus:          "-",
+}
+
+var methodNames = map[nodeKind]string{
+	typeMethod:     "type",
+	sizeMethod:     "size",
+	doubleMethod:   "double",
+	ceilingMethod:  "ceiling",
+	floorMethod:    "floor",
+	absMethod:      "abs",
+	keyValueMethod: "keyvalue",
+	datetimeMethod: "datetime",
+}
+
+// priority returns the binding strength of operators, used to decide where
+// parentheses are needed when formatting. It matches the grammar of the
+// parser.
+func (k nodeKind) priority() int {
+	switch k {
+	case or:
+		return 0
+	case and:
+		return 1
+	case equal, notEqual, less, lessOrEqual, greater, greaterOrEqual, startsWith:
+		return 2
+	case add, sub:
+		return 3
+	case mul, div, mod:
+		return 4
+	case plus, minus:
+		return 5
+	}
+	return 6
+}
+
+// lastLevel is the level of a .** accessor written as last, which is the
+// deepest level of the item.
+const lastLevel = ^uint32(0)
+
+// regexFlags holds the flags of a like_regex predicate.
+type regexFlags uint8
+
+const (
+	regexCaseInsensitive regexFlags = 1 << iota
+	regexDotAll
+	regexMultiLine
+	regexQuote
+)
+
+// node is a node of a path. Every node can be followed by the accessor in
+// next, which is applied to each of the items that the node evaluates to.
+type node struct {
+	kind nodeKind
+	// left is the only operand of unary operators, not, is unknown, exists
+	// and filters, and the left operand of binary operators.
+	left  *node
+	right *node
+	// value is the value of a literal.
+	value json.JSON
+	// str is the key of a key accessor, the name of a variable, the pattern
+	// of like_regex, or the template of .datetime().
+	str string
+	// hasTemplate is set if .datetime() has a template argument.
+	hasTemplate bool
+	flags       regexFlags
+	regex       *regexp.Regexp
+	subscripts  []subscript
+	// first and last are the levels of a .** accessor.
+	first, last uint32
+	next        *node
+}
+
+// subscript is a subscript of an array accessor: an index, or the range of
+// indexes from from to to if to is not nil.
+type subscript struct {
+	from, to *node
+}
+
+// Path is a parsed SQL/JSON path expression.
+type Path struct {
+	strict bool
+	root   *node
+}
+
+// Strict returns whether the path is evaluated in strict mode.
+func (p Path) Strict() bool {
+	return p.strict
+}
+
+// String returns the canonical text representation of the path, which can be
+// parsed back with Parse.
+func (p Path) String() string {
+	var b strings.Builder
+	if p.strict {
+		b.WriteString("strict ")
+	}
+	p.root.format(&b, false /* inKey */, true /* parens */)
+	return b.String()
+}
+
+// Size returns the approximate size of the path in memory, in bytes.
+func (p Path) Size() uintptr {
+	var size func(n *node) uintptr
+	size = func(n *node) uintptr {
+		if n == nil {
+			return 0
+		}
+		sz := unsafe.Sizeof(*n) + uintptr(len(n.str)) + size(n.left) + size(n.right) + size(n.next)
+		if n.value != nil {
+			sz += n.value.Size()
+		}
+		for _, s := range n.subscripts {
+			sz += unsafe.Sizeof(s) + size(s.from) + size(s.to)
+		}
+		return sz
+	}
+	return unsafe.Sizeof(p) + size(p.root)
+}
+
+// format writes the text representation of n and its accessors to b. If
+// inKey is true, n is an accessor that follows another node. If parens is
+// true, operators are enclosed in parentheses.
+func (n *node) format(b *strings.Builder, inKey bool, parens bool) {
+	parens = parens || n.next != nil
+	switch n.kind {
+	case literal:
+		formatLiteral(b, n.value)
+	case root:
+		b.WriteByte('$')
+	case current:
+		b.WriteByte('@')
+	case variable:
+		b.WriteByte('$')
+		writeString(b, n.str)
+	case last:
+		b.WriteString("last")
+	case and, or, equal, notEqual, less, lessOrEqual, greater, greaterOrEqual, startsWith,
+		add, sub, mul, div, mod:
+		if parens {
+			b.WriteByte('(')
+		}
+		n.formatOperand(b, n.left)
+		b.WriteByte(' ')
+		b.WriteString(operatorNames[n.kind])
+		b.WriteByte(' ')
+		n.formatOperand(b, n.right)
+		if parens {
+			b.WriteByte(')')
+		}
+	case likeRegex:
+		if parens {
+			b.WriteByte('(')
+		}
+		n.formatOperand(b, n.left)
+		b.WriteString(" like_regex ")
+		writeString(b, n.str)
+		if n.flags != 0 {
+			b.WriteString(" flag \"")
+			for _, f := range []struct {
+				flag regexFlags
+				c    byte
+			}{{regexCaseInsensitive, 'i'}, {regexDotAll, 's'}, {regexMultiLine, 'm'}, {regexQuote, 'q'}} {
+				if n.flags&f.flag != 0 {
+					b.WriteByte(f.c)
+				}
+			}
+			b.WriteByte('"')
+		}
+		if parens {
+			b.WriteByte(')')
+		}
+	case plus, minus:
+		if parens {
+			b.WriteByte('(')
+		}
+		b.WriteString(operatorNames[n.kind])
+		n.formatOperand(b, n.left)
+		if parens {
+			b.WriteByte(')')
+		}
+	case not:
+		b.WriteString("!(")
+		n.left.format(b, false /* inKey */, false /* parens */)
+		b.WriteByte(')')
+	case isUnknown:
+		b.WriteByte('(')
+		n.left.format(b, false /* inKey */, false /* parens */)
+		b.WriteString(") is unknown")
+	case exists:
+		b.WriteString("exists (")
+		n.left.format(b, false /* inKey */, false /* parens */)
+		b.WriteByte(')')
+	case key:
+		if inKey {
+			b.WriteByte('.')
+		}
+		writeString(b, n.str)
+	case anyKey:
+		if inKey {
+			b.WriteByte('.')
+		}
+		b.WriteByte('*')
+	case anyArray:
+		b.WriteString("[*]")
+	case indexArray:
+		b.WriteByte('[')
+		for i, s := range n.subscripts {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			s.from.format(b, false /* inKey */, false /* parens */)
+			if s.to != nil {
+				b.WriteString(" to ")
+				s.to.format(b, false /* inKey */, false /* parens */)
+			}
+		}
+		b.WriteByte(']')
+	case anyPath:
+		if inKey {
+			b.WriteByte('.')
+		}
+		b.WriteString("**")
+		switch {
+		case n.first == 0 && n.last == lastLevel:
+		case n.first == n.last:
+			b.WriteByte('{')
+			writeLevel(b, n.first)
+			b.WriteByte('}')
+		default:
+			b.WriteByte('{')
+			writeLevel(b, n.first)
+			b.WriteString(" to ")
+			writeLevel(b, n.last)
+			b.WriteByte('}')
+		}
+	case filter:
+		b.WriteString("?(")
+		n.left.format(b, false /* inKey */, false /* parens */)
+		b.WriteByte(')')
+	default:
+		b.WriteByte('.')
+		b.WriteString(methodNames[n.kind])
+		b.WriteByte('(')
+		if n.hasTemplate {
+			writeString(b, n.str)
+		}
+		b.WriteByte(')')
+	}
+	if n.next != nil {
+		n.next.format(b, true /* inKey */, true /* parens */)
+	}
+}
+
+// formatOperand formats an operand of n, enclosing it in parentheses if it
+// binds less strongly than n.
+func (n *node) formatOperand(b *strings.Builder, operand *node) {
+	operand.format(b, false /* inKey */, operand.kind.priority() <= n.kind.priority())
+}
+
+func writeLevel(b *strings.Builder, level uint32) {
+	if level == lastLevel {
+		b.WriteString("last")
+	} else {
+		b.WriteString(strconv.FormatUint(uint64(level), 10))
+	}
+}
+
+// formatLiteral writes a literal. Numbers are written without exponents.
+func formatLiteral(b *strings.Builder, j json.JSON) {
+	if j.Type() == json.NumberJSONType {
+		d, _ := j.AsDecimal()
+		b.WriteString(d.Text('f'))
+		return
+	}
+	b.WriteString(j.String())
+}
+
+// writeString writes s as a double-quoted string.
+func writeString(b *strings.Builder, s string) {
+	b.WriteString(json.FromString(s).String())
+}
@@ -0,0 +1,857 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd/v2"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+	"github.com/cockroachdb/cockroach/pkg/util/json"
+)
+
+// tokenKind is the kind of a token of a path.
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	// tokIdent is an unquoted word, which can be a keyword or a key.
+	tokIdent
+	tokString
+	tokNumber
+	// tokVariable is a $name variable; tokRoot is a $ on its own.
+	tokVariable
+	tokRoot
+	// tokPunct is an operator or a punctuation character.
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	// str is the word, the unescaped string, the number, the name of the
+	// variable, or the operator.
+	str string
+}
+
+// lexer splits a path into tokens.
+type lexer struct {
+	input string
+	pos   int
+	// tokStart is the position of the last token, where syntax errors are
+	// reported.
+	tokStart int
+}
+
+// punctuation holds the operators and punctuation of paths, longest first.
+var punctuation = []string{
+	"**", "==", "!=", "<>", "<=", ">=", "&&", "||",
+	"$", "@", ".", ",", "[", "]", "(", ")", "{", "}", "?", "!", "<", ">",
+	"+", "-", "*", "/", "%",
+}
+
+func (l *lexer) syntaxError(detail string) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in jsonpath: %s at or near %q",
+		detail, l.input[l.tokStart:])
+}
+
+// isIdentStart returns whether r can start an unquoted word.
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// isIdentChar returns whether r can be part of an unquoted word.
+func isIdentChar(r rune) bool {
+	return isIdentStart(r) || unicode.IsDigit(r)
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(rune(l.input[l.pos])) {
+		l.pos++
+	}
+	l.tokStart = l.pos
+	if l.pos == len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+	c := l.input[l.pos]
+	switch {
+	case c == '"':
+		s, err := l.scanString()
+		return token{kind: tokString, str: s}, err
+	case isDigit(c):
+		return l.scanNumber()
+	case c == '$':
+		l.pos++
+		if l.pos < len(l.input) && l.input[l.pos] == '"' {
+			s, err := l.scanString()
+			return token{kind: tokVariable, str: s}, err
+		}
+		if r, _ := utf8.DecodeRuneInString(l.input[l.pos:]); l.pos < len(l.input) && isIdentChar(r) {
+			return token{kind: tokVariable, str: l.scanWord()}, nil
+		}
+		return token{kind: tokRoot, str: "$"}, nil
+	}
+	if r, _ := utf8.DecodeRuneInString(l.input[l.pos:]); isIdentStart(r) {
+		return token{kind: tokIdent, str: l.scanWord()}, nil
+	}
+	for _, p := range punctuation {
+		if strings.HasPrefix(l.input[l.pos:], p) {
+			l.pos += len(p)
+			return token{kind: tokPunct, str: p}, nil
+		}
+	}
+	return token{}, l.syntaxError("unexpected character")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (l *lexer) scanWord() string {
+	start := l.pos
+	for l.pos < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos:])
+		if !isIdentChar(r) {
+			break
+		}
+		l.pos += size
+	}
+	return l.input[start:l.pos]
+}
+
+// scanNumber scans a numeric literal. A period that is followed by a word is
+// the start of an accessor rather than a decimal point, so that 1.type() is
+// the type method applied to 1.
+func (l *lexer) scanNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+		l.pos++
+	}
+	if l.pos < len(l.input) && l.input[l.pos] == '.' {
+		if r, _ := utf8.DecodeRuneInString(l.input[l.pos+1:]); !isIdentStart(r) {
+			l.pos++
+			for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+				l.pos++
+			}
+		}
+	}
+	if l.pos < len(l.input) && (l.input[l.pos] == 'e' || l.input[l.pos] == 'E') {
+		l.pos++
+		if l.pos < len(l.input) && (l.input[l.pos] == '+' || l.input[l.pos] == '-') {
+			l.pos++
+		}
+		if l.pos == len(l.input) || !isDigit(l.input[l.pos]) {
+			return token{}, l.syntaxError("invalid numeric literal")
+		}
+		for l.pos < len(l.input) && isDigit(l.input[l.pos]) {
+			l.pos++
+		}
+	}
+	if r, _ := utf8.DecodeRuneInString(l.input[l.pos:]); l.pos < len(l.input) && isIdentStart(r) {
+		return token{}, l.syntaxError("trailing junk after numeric literal")
+	}
+	return token{kind: tokNumber, str: l.input[start:l.pos]}, nil
+}
+
+// scanString scans a double-quoted string, with the escapes of JSON strings
+// and \v, \xNN and \u{N...}.
+func (l *lexer) scanString() (string, error) {
+	var b strings.Builder
+	l.pos++
+	for {
+		if l.pos >= len(l.input) {
+			return "", l.syntaxError("unterminated quoted string")
+		}
+		c := l.input[l.pos]
+		l.pos++
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+		default:
+			b.WriteByte(c)
+			continue
+		}
+		if l.pos >= len(l.input) {
+			return "", l.syntaxError("unterminated quoted string")
+		}
+		c = l.input[l.pos]
+		l.pos++
+		switch c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x', 'u':
+			r, err := l.scanCodePoint(c)
+			if err != nil {
+				return "", err
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// scanCodePoint scans the hexadecimal digits of a \x or \u escape.
+func (l *lexer) scanCodePoint(escape byte) (rune, error) {
+	digits := 2
+	if escape == 'u' {
+		digits = 4
+		if l.pos < len(l.input) && l.input[l.pos] == '{' {
+			end := strings.IndexByte(l.input[l.pos:], '}')
+			if end < 2 || end > 7 {
+				return 0, l.syntaxError("invalid unicode sequence")
+			}
+			r, err := strconv.ParseUint(l.input[l.pos+1:l.pos+end], 16, 32)
+			l.pos += end + 1
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return 0, l.syntaxError("invalid unicode sequence")
+			}
+			return rune(r), nil
+		}
+	}
+	if l.pos+digits > len(l.input) {
+		return 0, l.syntaxError("invalid escape sequence")
+	}
+	r, err := strconv.ParseUint(l.input[l.pos:l.pos+digits], 16, 32)
+	l.pos += digits
+	if err != nil || r == 0 || !utf8.ValidRune(rune(r)) {
+		return 0, l.syntaxError("invalid escape sequence")
+	}
+	return rune(r), nil
+}
+
+// Parse parses the text representation of a path, for example:
+//
+//   strict $.events[*] ? (@.type == "click" && @.ts > $since).target
+//
+func Parse(input string) (Path, error) {
+	p := parser{l: lexer{input: input}}
+	if err := p.advance(); err != nil {
+		return Path{}, err
+	}
+	var path Path
+	if p.isKeyword("strict") {
+		path.strict = true
+		if err := p.advance(); err != nil {
+			return Path{}, err
+		}
+	} else if p.isKeyword("lax") {
+		if err := p.advance(); err != nil {
+			return Path{}, err
+		}
+	}
+	var err error
+	if path.root, err = p.parseOr(); err != nil {
+		return Path{}, err
+	}
+	if p.tok.kind != tokEOF {
+		return Path{}, p.l.syntaxError("unexpected input")
+	}
+	return path, nil
+}
+
+// parser is a recursive descent parser of paths. Its methods are named after
+// the productions of the grammar, from the loosest to the tightest binding.
+type parser struct {
+	l lexer
+	// tok is the current token.
+	tok token
+	// filterDepth and subscriptDepth track whether @ and last are allowed.
+	filterDepth    int
+	subscriptDepth int
+}
+
+func (p *parser) advance() error {
+	var err error
+	p.tok, err = p.l.next()
+	return err
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.tok.kind == tokPunct && p.tok.str == s
+}
+
+func (p *parser) isKeyword(s string) bool {
+	return p.tok.kind == tokIdent && p.tok.str == s
+}
+
+// expect consumes the given punctuation.
+func (p *parser) expect(s string) error {
+	if !p.isPunct(s) {
+		return p.l.syntaxError("expected " + s)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseOr() (*node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("||") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makePredicate(or, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (*node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("&&") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makePredicate(and, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// makePredicate makes a node for a logical operator, whose operands must be
+// predicates.
+func (p *parser) makePredicate(kind nodeKind, left, right *node) (*node, error) {
+	if !left.kind.isPredicate() || (right != nil && !right.kind.isPredicate()) {
+		return nil, p.l.syntaxError("expected a predicate")
+	}
+	return &node{kind: kind, left: left, right: right}, nil
+}
+
+// parseNot parses the negation of a parenthesized predicate or of exists.
+func (p *parser) parseNot() (*node, error) {
+	if !p.isPunct("!") {
+		return p.parseComparison()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if !p.isPunct("(") && !p.isKeyword("exists") {
+		return nil, p.l.syntaxError("expected ( or exists")
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	return p.makePredicate(not, operand, nil)
+}
+
+var comparisonOperators = map[string]nodeKind{
+	"==": equal,
+	"!=": notEqual,
+	"<>": notEqual,
+	"<":  less,
+	"<=": lessOrEqual,
+	">":  greater,
+	">=": greaterOrEqual,
+}
+
+func (p *parser) parseComparison() (*node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if kind, ok := comparisonOperators[p.tok.str]; ok && p.tok.kind == tokPunct {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: kind, left: left, right: right}, nil
+	}
+	switch {
+	case p.isKeyword("starts"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("with") {
+			return nil, p.l.syntaxError("expected with")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var right *node
+		switch p.tok.kind {
+		case tokString:
+			right = &node{kind: literal, value: json.FromString(p.tok.str)}
+		case tokVariable:
+			right = &node{kind: variable, str: p.tok.str}
+		default:
+			return nil, p.l.syntaxError("expected a string or a variable")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &node{kind: startsWith, left: left, right: right}, nil
+
+	case p.isKeyword("like_regex"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokString {
+			return nil, p.l.syntaxError("expected a pattern")
+		}
+		n := &node{kind: likeRegex, left: left, str: p.tok.str}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("flag") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokString {
+				return nil, p.l.syntaxError("expected flags")
+			}
+			if n.flags, err = parseRegexFlags(p.tok.str); err != nil {
+				return nil, err
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		if n.regex, err = compileRegex(n.str, n.flags); err != nil {
+			return nil, err
+		}
+		return n, nil
+	}
+	return left, nil
+}
+
+func parseRegexFlags(s string) (regexFlags, error) {
+	var flags regexFlags
+	for _, c := range s {
+		switch c {
+		case 'i':
+			flags |= regexCaseInsensitive
+		case 's':
+			flags |= regexDotAll
+		case 'm':
+			flags |= regexMultiLine
+		case 'q':
+			flags |= regexQuote
+		case 'x':
+			return 0, pgerror.New(pgcode.FeatureNotSupported,
+				"XQuery \"x\" flag (expanded regular expressions) is not implemented")
+		default:
+			return 0, pgerror.Newf(pgcode.Syntax,
+				"invalid input syntax for type jsonpath: unrecognized flag character %q in LIKE_REGEX predicate", c)
+		}
+	}
+	return flags, nil
+}
+
+func compileRegex(pattern string, flags regexFlags) (*regexp.Regexp, error) {
+	if flags&regexQuote != 0 {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	var prefix string
+	if flags&regexCaseInsensitive != 0 {
+		prefix += "i"
+	}
+	if flags&regexDotAll != 0 {
+		prefix += "s"
+	}
+	if flags&regexMultiLine != 0 {
+		prefix += "m"
+	}
+	if prefix != "" {
+		pattern = "(?" + prefix + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, pgerror.Wrap(err, pgcode.InvalidRegularExpression, "invalid regular expression")
+	}
+	return re, nil
+}
+
+func (p *parser) parseAdditive() (*node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		kind := add
+		if p.tok.str == "-" {
+			kind = sub
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makeArithmetic(kind, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (*node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		kind := map[string]nodeKind{"*": mul, "/": div, "%": mod}[p.tok.str]
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.makeArithmetic(kind, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// makeArithmetic makes a node for an arithmetic operator, whose operands
+// can't be predicates.
+func (p *parser) makeArithmetic(kind nodeKind, left, right *node) (*node, error) {
+	if left.kind.isPredicate() || (right != nil && right.kind.isPredicate()) {
+		return nil, p.l.syntaxError("unexpected predicate")
+	}
+	return &node{kind: kind, left: left, right: right}, nil
+}
+
+// parseUnary parses unary plus and minus. Like in Postgres, they are folded
+// into numeric literals.
+func (p *parser) parseUnary() (*node, error) {
+	if !p.isPunct("+") && !p.isPunct("-") {
+		return p.parseAccessorExpr()
+	}
+	kind := plus
+	if p.tok.str == "-" {
+		kind = minus
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if operand.kind == literal && operand.next == nil && operand.value.Type() == json.NumberJSONType {
+		if kind == minus {
+			d, _ := operand.value.AsDecimal()
+			var neg apd.Decimal
+			neg.Neg(d)
+			operand.value = json.FromDecimal(neg)
+		}
+		return operand, nil
+	}
+	return p.makeArithmetic(kind, operand, nil)
+}
+
+// parseAccessorExpr parses a primary item followed by its accessors.
+func (p *parser) parseAccessorExpr() (*node, error) {
+	first, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	tail := first
+	for {
+		var n *node
+		switch {
+		case p.isPunct("."):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if n, err = p.parseDotAccessor(); err != nil {
+				return nil, err
+			}
+		case p.isPunct("["):
+			if n, err = p.parseArrayAccessor(); err != nil {
+				return nil, err
+			}
+		case p.isPunct("?"):
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			p.filterDepth++
+			pred, err := p.parseOr()
+			p.filterDepth--
+			if err != nil {
+				return nil, err
+			}
+			if !pred.kind.isPredicate() {
+				return nil, p.l.syntaxError("expected a predicate")
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			n = &node{kind: filter, left: pred}
+		default:
+			return first, nil
+		}
+		tail.next = n
+		tail = n
+	}
+}
+
+func (p *parser) parsePrimary() (*node, error) {
+	var n *node
+	switch p.tok.kind {
+	case tokRoot:
+		n = &node{kind: root}
+	case tokVariable:
+		n = &node{kind: variable, str: p.tok.str}
+	case tokString:
+		n = &node{kind: literal, value: json.FromString(p.tok.str)}
+	case tokNumber:
+		var d apd.Decimal
+		if _, _, err := d.SetString(p.tok.str); err != nil {
+			return nil, p.l.syntaxError("invalid numeric literal")
+		}
+		// Store the number without its exponent, like Postgres does.
+		if _, _, err := d.SetString(d.Text('f')); err != nil {
+			return nil, p.l.syntaxError("invalid numeric literal")
+		}
+		n = &node{kind: literal, value: json.FromDecimal(d)}
+	case tokIdent:
+		switch p.tok.str {
+		case "true":
+			n = &node{kind: literal, value: json.TrueJSONValue}
+		case "false":
+			n = &node{kind: literal, value: json.FalseJSONValue}
+		case "null":
+			n = &node{kind: literal, value: json.NullJSONValue}
+		case "last":
+			if p.subscriptDepth == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "LAST is allowed only in array subscripts")
+			}
+			n = &node{kind: last}
+		case "exists":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			operand, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return &node{kind: exists, left: operand}, nil
+		default:
+			return nil, p.l.syntaxError("unexpected " + p.tok.str)
+		}
+	case tokPunct:
+		switch p.tok.str {
+		case "@":
+			if p.filterDepth == 0 {
+				return nil, pgerror.New(pgcode.Syntax, "@ is not allowed in root expressions")
+			}
+			n = &node{kind: current}
+		case "(":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			if !p.isKeyword("is") {
+				return inner, nil
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if !p.isKeyword("unknown") {
+				return nil, p.l.syntaxError("expected unknown")
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			return p.makePredicate(isUnknown, inner, nil)
+		}
+	}
+	if n == nil {
+		return nil, p.l.syntaxError("unexpected input")
+	}
+	return n, p.advance()
+}
+
+// parseDotAccessor parses the accessor after a period: a key, .*, .** or an
+// item method.
+func (p *parser) parseDotAccessor() (*node, error) {
+	switch {
+	case p.isPunct("*"):
+		return &node{kind: anyKey}, p.advance()
+	case p.isPunct("**"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n := &node{kind: anyPath, last: lastLevel}
+		if !p.isPunct("{") {
+			return n, nil
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		var err error
+		if n.first, err = p.parseLevel(); err != nil {
+			return nil, err
+		}
+		n.last = n.first
+		if p.isKeyword("to") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if n.last, err = p.parseLevel(); err != nil {
+				return nil, err
+			}
+		}
+		return n, p.expect("}")
+	case p.tok.kind == tokString:
+		n := &node{kind: key, str: p.tok.str}
+		return n, p.advance()
+	case p.tok.kind == tokIdent, p.tok.kind == tokNumber:
+		name := p.tok.str
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if !p.isPunct("(") {
+			return &node{kind: key, str: name}, nil
+		}
+		n := &node{kind: literal}
+		for kind, methodName := range methodNames {
+			if methodName == name {
+				n.kind = kind
+			}
+		}
+		if n.kind == literal {
+			return nil, p.l.syntaxError("unknown item method " + name)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if n.kind == datetimeMethod && p.tok.kind == tokString {
+			n.str = p.tok.str
+			n.hasTemplate = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+		return n, p.expect(")")
+	}
+	return nil, p.l.syntaxError("expected a key")
+}
+
+// parseLevel parses a level of a .** accessor.
+func (p *parser) parseLevel() (uint32, error) {
+	if p.isKeyword("last") {
+		return lastLevel, p.advance()
+	}
+	if p.tok.kind != tokNumber {
+		return 0, p.l.syntaxError("expected a level")
+	}
+	level, err := strconv.ParseUint(p.tok.str, 10, 32)
+	if err != nil || level == math.MaxUint32 {
+		return 0, p.l.syntaxError("invalid level")
+	}
+	return uint32(level), p.advance()
+}
+
+// parseArrayAccessor parses [*] or a list of subscripts.
+func (p *parser) parseArrayAccessor() (*node, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.isPunct("*") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return &node{kind: anyArray}, p.expect("]")
+	}
+	p.subscriptDepth++
+	defer func() { p.subscriptDepth-- }()
+	n := &node{kind: indexArray}
+	for {
+		var s subscript
+		var err error
+		if s.from, err = p.parseSubscriptExpr(); err != nil {
+			return nil, err
+		}
+		if p.isKeyword("to") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			if s.to, err = p.parseSubscriptExpr(); err != nil {
+				return nil, err
+			}
+		}
+		n.subscripts = append(n.subscripts, s)
+		if !p.isPunct(",") {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return n, p.expect("]")
+}
+
+func (p *parser) parseSubscriptExpr() (*node, error) {
+	n, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if n.kind.isPredicate() {
+		return nil, p.l.syntaxError("unexpected predicate")
+	}
+	return n, nil
+}
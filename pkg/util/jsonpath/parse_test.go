@@ -0,0 +1,102 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		input, expected, expectedErr string
+	}{
+		{`$`, `$`, ``},
+		{`lax $`, `$`, ``},
+		{`strict $.a`, `strict $."a"`, ``},
+		{`$.a.b`, `$."a"."b"`, ``},
+		{`$."key with spaces"."a\"b"`, `$."key with spaces"."a\"b"`, ``},
+		{`$.a[*]`, `$."a"[*]`, ``},
+		{`$.*`, `$.*`, ``},
+		{`$[1, 2 to 3, last - 1 to last]`, `$[1,2 to 3,last - 1 to last]`, ``},
+		{`$.**`, `$.**`, ``},
+		{`$.**{2}`, `$.**{2}`, ``},
+		{`$.**{1 to last}.a`, `$.**{1 to last}."a"`, ``},
+		{`$.a ? (@ > 1)`, `$."a"?(@ > 1)`, ``},
+		{`$ ? (@.a == 1 && (@.b < 2 || !(@.c != 3)))`,
+			`$?(@."a" == 1 && (@."b" < 2 || !(@."c" != 3)))`, ``},
+		{`$ ? (exists (@.a))`, `$?(exists (@."a"))`, ``},
+		{`$ ? ((@.a > 1) is unknown)`, `$?((@."a" > 1) is unknown)`, ``},
+		{`$ ? (@ starts with "abc")`, `$?(@ starts with "abc")`, ``},
+		{`$ ? (@ like_regex "^a.c" flag "iq")`, `$?(@ like_regex "^a.c" flag "iq")`, ``},
+		{`$.a + 1`, `($."a" + 1)`, ``},
+		{`$.a + 2 * $.b`, `($."a" + 2 * $."b")`, ``},
+		{`($.a + 2) * $.b`, `(($."a" + 2) * $."b")`, ``},
+		{`-$.a`, `(-$."a")`, ``},
+		{`-1`, `-1`, ``},
+		{`- - 1.5`, `1.5`, ``},
+		{`1e3`, `1000`, ``},
+		{`$.a == 1`, `($."a" == 1)`, ``},
+		{`$.a.type()`, `$."a".type()`, ``},
+		{`$.a.size().double()`, `$."a".size().double()`, ``},
+		{`$.datetime("YYYY")`, `$.datetime("YYYY")`, ``},
+		{`$.datetime`, `$."datetime"`, ``},
+		{`($.a + 1).abs()`, `($."a" + 1).abs()`, ``},
+		{`$a`, `$"a"`, ``},
+		{`$"with space"`, `$"with space"`, ``},
+		{`"ab\x63"`, `"abc"`, ``},
+		{`true`, `true`, ``},
+		{`null`, `null`, ``},
+		{`1.type()`, `1.type()`, ``},
+		{`$ ? (@.a == $x)`, `$?(@."a" == $"x")`, ``},
+		{``, ``, `syntax error in jsonpath: unexpected input at or near ""`},
+		{`$.`, ``, `syntax error in jsonpath: expected a key at or near ""`},
+		{`$[`, ``, `syntax error in jsonpath: unexpected input at or near ""`},
+		{`$.a ? (@.b)`, ``, `syntax error in jsonpath: expected a predicate at or near ")"`},
+		{`@.a`, ``, `@ is not allowed in root expressions`},
+		{`last`, ``, `LAST is allowed only in array subscripts`},
+		{`$.a.foo()`, ``, `syntax error in jsonpath: unknown item method foo at or near "()"`},
+		{`1a`, ``, `syntax error in jsonpath: trailing junk after numeric literal at or near "1a"`},
+		{`"abc`, ``, `syntax error in jsonpath: unterminated quoted string at or near "\"abc"`},
+		{`$ ? (@ like_regex "(")`, ``, "invalid regular expression: error parsing regexp: " +
+			"missing closing ): `(`"},
+		{`$ ? (@ like_regex "a" flag "z")`, ``,
+			`invalid input syntax for type jsonpath: unrecognized flag character 'z' in LIKE_REGEX predicate`},
+		{`$.a + ($.b > 1)`, ``, `syntax error in jsonpath: unexpected predicate at or near ""`},
+	}
+	for _, tc := range testCases {
+		p, err := Parse(tc.input)
+		if tc.expectedErr != "" {
+			require.EqualError(t, err, tc.expectedErr, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, p.String(), tc.input)
+		// The output must round trip.
+		p2, err := Parse(p.String())
+		require.NoError(t, err)
+		require.Equal(t, p.String(), p2.String())
+	}
+}
+
+func TestEncodePath(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 100; i++ {
+		p := RandomPath(rng)
+		decoded, err := DecodePath(EncodePath(nil, p))
+		require.NoError(t, err)
+		require.Equal(t, p.String(), decoded.String())
+	}
+	_, err := DecodePath([]byte("\x02$"))
+	require.EqualError(t, err, "unsupported jsonpath version number")
+}
@@ -0,0 +1,56 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package jsonpath
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// randomKeys are the keys of random paths. They are few, so that random paths
+// often select items of random JSON documents.
+var randomKeys = []string{"a", "b", "c", "foo"}
+
+// RandomPath returns a random Path, for testing.
+func RandomPath(rng *rand.Rand) Path {
+	s := "$" + randomAccessors(rng, 3 /* depth */)
+	if rng.Intn(4) == 0 {
+		s = "strict " + s
+	}
+	p, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("invalid random path %q: %v", s, err))
+	}
+	return p
+}
+
+func randomAccessors(rng *rand.Rand, depth int) string {
+	var s string
+	for i, n := 0, rng.Intn(4); i < n; i++ {
+		switch rng.Intn(6) {
+		case 0:
+			s += "[*]"
+		case 1:
+			s += fmt.Sprintf("[%d]", rng.Intn(3))
+		case 2:
+			s += ".*"
+		case 3:
+			if depth > 0 {
+				s += fmt.Sprintf(" ? (@%s == %d)", randomAccessors(rng, depth-1), rng.Intn(3))
+				continue
+			}
+			fallthrough
+		default:
+			s += "." + randomKeys[rng.Intn(len(randomKeys))]
+		}
+	}
+	return s
+}
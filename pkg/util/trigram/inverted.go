@@ -0,0 +1,106 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeInvertedIndexKeys takes in a key prefix and returns a slice of
+// inverted index keys, one per trigram of the string.
+func EncodeInvertedIndexKeys(inKey []byte, s string) [][]byte {
+	trigrams := MakeTrigrams(s)
+	keys := make([][]byte, 0, len(trigrams))
+	for _, t := range trigrams {
+		// Make sure each key has a separate backing array.
+		key := make([]byte, len(inKey), len(inKey)+len(t)+2)
+		copy(key, inKey)
+		keys = append(keys, encoding.EncodeStringAscending(key, t))
+	}
+	return keys
+}
+
+// EncodeInvertedIndexSpans takes in a key prefix and returns the spans that
+// must be scanned in the inverted index to find the strings that contain the
+// given trigrams. If allMustMatch is true, a string must contain every
+// trigram, so the spans of the trigrams are intersected; otherwise a single
+// trigram is enough, and the spans are unioned.
+//
+// Sharing trigrams is a necessary but not a sufficient condition for the
+// predicates that are evaluated with trigrams, so the returned expression is
+// never tight. If there are no trigrams, inverted.NonInvertedColExpression is
+// returned.
+func EncodeInvertedIndexSpans(
+	inKey []byte, trigrams []string, allMustMatch bool,
+) inverted.Expression {
+	var expr inverted.Expression
+	for _, t := range trigrams {
+		key := encoding.EncodeStringAscending(append([]byte(nil), inKey...), t)
+		spanExpr := inverted.ExprForSpan(inverted.MakeSingleValSpan(key), false /* tight */)
+		// Each trigram is indexed at most once per row.
+		spanExpr.Unique = true
+		switch {
+		case expr == nil:
+			expr = spanExpr
+		case allMustMatch:
+			expr = inverted.And(expr, spanExpr)
+		default:
+			expr = inverted.Or(expr, spanExpr)
+		}
+	}
+	if expr == nil {
+		return inverted.NonInvertedColExpression{}
+	}
+	return expr
+}
+
+// MakeLikePatternTrigrams returns the sorted, deduplicated trigrams that every
+// string matching the given LIKE or ILIKE pattern must contain. Backslash is
+// the escape character of the pattern.
+//
+// A word of the pattern is padded at a side only if that side is the end of
+// the pattern or a character that can't be part of a word; next to a
+// wildcard, the matching string may continue the word.
+func MakeLikePatternTrigrams(pattern string) []string {
+	var trigrams []string
+	var word strings.Builder
+	// padStart is true if the word being built is known to begin a word of the
+	// matching strings.
+	padStart := true
+	endWord := func(padEnd bool) {
+		if word.Len() > 0 {
+			trigrams = appendWordTrigrams(trigrams, word.String(), padStart, padEnd)
+			word.Reset()
+		}
+	}
+	escaped := false
+	for _, r := range strings.ToLower(pattern) {
+		switch {
+		case !escaped && r == '\\':
+			escaped = true
+			continue
+		case !escaped && (r == '%' || r == '_'):
+			endWord(false /* padEnd */)
+			padStart = false
+		case isWordChar(r):
+			word.WriteRune(r)
+		default:
+			endWord(true /* padEnd */)
+			padStart = true
+		}
+		escaped = false
+	}
+	endWord(true /* padEnd */)
+	return sortAndDedup(trigrams)
+}
@@ -0,0 +1,185 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package trigram implements the trigram matching of the Postgres pg_trgm
+// extension. A string is lowercased and split into words made of letters and
+// digits, and every word is padded with two spaces in front and one behind, so
+// that the trigrams of a string also record where its words begin and end.
+// The similarity of two strings is the number of trigrams they share divided
+// by the number of distinct trigrams in both.
+package trigram
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultSimilarityThreshold is the default value of the
+// pg_trgm.similarity_threshold session variable, above which two strings are
+// considered similar by the % operator.
+const DefaultSimilarityThreshold = 0.3
+
+// MakeTrigrams returns the sorted, deduplicated trigrams of s.
+func MakeTrigrams(s string) []string {
+	var trigrams []string
+	for _, w := range splitWords(s) {
+		trigrams = appendWordTrigrams(trigrams, w, true /* padStart */, true /* padEnd */)
+	}
+	return sortAndDedup(trigrams)
+}
+
+// Similarity returns the similarity of the two strings, a number between 0
+// (no trigrams in common) and 1 (the same set of trigrams). Like in Postgres,
+// similarities are single precision.
+func Similarity(l, r string) float32 {
+	return similarity(MakeTrigrams(l), MakeTrigrams(r))
+}
+
+// WordSimilarity returns the greatest similarity between the trigrams of l
+// and any continuous extent of the ordered trigrams of r.
+func WordSimilarity(l, r string) float32 {
+	return wordSimilarity(l, r, false /* strict */)
+}
+
+// StrictWordSimilarity is like WordSimilarity, but the extents of r are only
+// allowed to span whole words.
+func StrictWordSimilarity(l, r string) float32 {
+	return wordSimilarity(l, r, true /* strict */)
+}
+
+// similarity returns the similarity of two sorted, deduplicated sets of
+// trigrams.
+func similarity(l, r []string) float32 {
+	if len(l) == 0 || len(r) == 0 {
+		return 0
+	}
+	var common int
+	for i, j := 0, 0; i < len(l) && j < len(r); {
+		switch c := strings.Compare(l[i], r[j]); {
+		case c < 0:
+			i++
+		case c > 0:
+			j++
+		default:
+			common++
+			i++
+			j++
+		}
+	}
+	return float32(common) / float32(len(l)+len(r)-common)
+}
+
+func wordSimilarity(l, r string, strict bool) float32 {
+	needle := make(map[string]struct{})
+	for _, t := range MakeTrigrams(l) {
+		needle[t] = struct{}{}
+	}
+	if len(needle) == 0 {
+		return 0
+	}
+	// Split r into the units that an extent is made of: single trigrams, or
+	// the trigrams of whole words in strict mode.
+	var units [][]string
+	for _, w := range splitWords(r) {
+		trigrams := appendWordTrigrams(nil, w, true /* padStart */, true /* padEnd */)
+		if strict {
+			units = append(units, trigrams)
+			continue
+		}
+		for _, t := range trigrams {
+			units = append(units, []string{t})
+		}
+	}
+	contains := func(unit []string) bool {
+		for _, t := range unit {
+			if _, ok := needle[t]; ok {
+				return true
+			}
+		}
+		return false
+	}
+	var best float32
+	for i := range units {
+		// Extending an extent with units that share nothing with l can only
+		// lower the similarity, so extents begin and end with a shared unit.
+		if !contains(units[i]) {
+			continue
+		}
+		extent := make(map[string]struct{})
+		var common int
+		for j := i; j < len(units); j++ {
+			for _, t := range units[j] {
+				if _, ok := extent[t]; ok {
+					continue
+				}
+				extent[t] = struct{}{}
+				if _, ok := needle[t]; ok {
+					common++
+				}
+			}
+			if !contains(units[j]) {
+				continue
+			}
+			if sim := float32(common) / float32(len(needle)+len(extent)-common); sim > best {
+				best = sim
+				if best == 1 {
+					return best
+				}
+			}
+		}
+	}
+	return best
+}
+
+// isWordChar returns true if r can be part of a word.
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// splitWords returns the lowercased words of s.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !isWordChar(r)
+	})
+}
+
+// appendWordTrigrams appends the trigrams of the given word to trigrams. The
+// word is padded with two spaces in front if padStart is true, and with one
+// space behind if padEnd is true.
+func appendWordTrigrams(trigrams []string, word string, padStart, padEnd bool) []string {
+	runes := make([]rune, 0, len(word)+3)
+	if padStart {
+		runes = append(runes, ' ', ' ')
+	}
+	runes = append(runes, []rune(word)...)
+	if padEnd {
+		runes = append(runes, ' ')
+	}
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+3]))
+	}
+	return trigrams
+}
+
+func sortAndDedup(trigrams []string) []string {
+	if len(trigrams) == 0 {
+		return nil
+	}
+	sort.Strings(trigrams)
+	n := 1
+	for i := 1; i < len(trigrams); i++ {
+		if trigrams[i] != trigrams[n-1] {
+			trigrams[n] = trigrams[i]
+			n++
+		}
+	}
+	return trigrams[:n]
+}
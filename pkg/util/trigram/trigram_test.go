@@ -0,0 +1,111 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package trigram
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMakeTrigrams(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected []string
+	}{
+		{``, nil},
+		{`!!`, nil},
+		{`a`, []string{"  a", " a "}},
+		{`cat`, []string{"  c", " ca", "at ", "cat"}},
+		{`Cat cAT`, []string{"  c", " ca", "at ", "cat"}},
+		{`foo|bar`, []string{"  b", "  f", " ba", " fo", "ar ", "bar", "foo", "oo "}},
+		{`née`, []string{"  n", " né", "née", "ée "}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			require.Equal(t, tc.expected, MakeTrigrams(tc.input))
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	testCases := []struct {
+		l, r                               string
+		similarity, wordSimilarity, strict float32
+	}{
+		{`word`, `two words`, 4.0 / 11, 0.8, 4.0 / 7},
+		{`word`, `word`, 1, 1, 1},
+		{`word`, `WORD!`, 1, 1, 1},
+		{`word`, `other`, 0, 0, 0},
+		{``, `word`, 0, 0, 0},
+		{`word`, ``, 0, 0, 0},
+		{`two words`, `word`, 4.0 / 11, 0.4, 4.0 / 11},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.l+"/"+tc.r, func(t *testing.T) {
+			require.InDelta(t, tc.similarity, Similarity(tc.l, tc.r), 1e-6)
+			require.InDelta(t, tc.wordSimilarity, WordSimilarity(tc.l, tc.r), 1e-6)
+			require.InDelta(t, tc.strict, StrictWordSimilarity(tc.l, tc.r), 1e-6)
+		})
+	}
+}
+
+func TestMakeLikePatternTrigrams(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		expected []string
+	}{
+		{`%`, nil},
+		{`%ab%`, nil},
+		{`%abc%`, []string{"abc"}},
+		{`abc`, []string{"  a", " ab", "abc", "bc "}},
+		{`Abc%`, []string{"  a", " ab", "abc"}},
+		{`%abc`, []string{"abc", "bc "}},
+		{`%ab cd%`, []string{"ab ", "  c", " cd"}},
+		{`a_c`, []string{"  a"}},
+		{`%a\%c%`, []string{"  c"}},
+		{`%a\_bc\d%`, []string{"  b", " bc", "bcd"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			expected := sortAndDedup(tc.expected)
+			require.Equal(t, expected, MakeLikePatternTrigrams(tc.pattern))
+		})
+	}
+}
+
+func TestEncodeInvertedIndexSpans(t *testing.T) {
+	trigrams := MakeTrigrams(`cat`)
+	keys := EncodeInvertedIndexKeys(nil, `the cat`)
+
+	for _, allMustMatch := range []bool{true, false} {
+		expr := EncodeInvertedIndexSpans(nil, trigrams, allMustMatch)
+		spanExpr, ok := expr.(*inverted.SpanExpression)
+		require.True(t, ok)
+		require.False(t, spanExpr.IsTight())
+		ok, err := spanExpr.ContainsKeys(keys)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	// Only some of the trigrams of "cat" are in "category".
+	keys = EncodeInvertedIndexKeys(nil, `category`)
+	for _, allMustMatch := range []bool{true, false} {
+		expr := EncodeInvertedIndexSpans(nil, trigrams, allMustMatch)
+		ok, err := expr.(*inverted.SpanExpression).ContainsKeys(keys)
+		require.NoError(t, err)
+		require.Equal(t, !allMustMatch, ok)
+	}
+
+	_, ok := EncodeInvertedIndexSpans(nil, nil, true).(inverted.NonInvertedColExpression)
+	require.True(t, ok)
+}
@@ -0,0 +1,211 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// Dictionary turns the words of a document or query into lexemes.
+type Dictionary interface {
+	// Lexize returns the lexeme for the given lower case word, or false if the
+	// word is a stop word that should not be indexed.
+	Lexize(word string) (lexeme string, ok bool)
+}
+
+// simpleDictionary returns words unchanged and has no stop words.
+type simpleDictionary struct{}
+
+// Lexize implements the Dictionary interface.
+func (simpleDictionary) Lexize(word string) (string, bool) {
+	return word, true
+}
+
+// stemmingDictionary drops stop words and reduces the other words to their
+// stems.
+type stemmingDictionary struct {
+	stopWords map[string]struct{}
+	stem      func(word string) string
+}
+
+// Lexize implements the Dictionary interface.
+func (d stemmingDictionary) Lexize(word string) (string, bool) {
+	if _, ok := d.stopWords[word]; ok {
+		return "", false
+	}
+	return d.stem(word), true
+}
+
+// englishStopWords is the list of English stop words used by Postgres.
+var englishStopWords = makeStopWords(
+	"i", "me", "my", "myself", "we", "our", "ours", "ourselves", "you", "your",
+	"yours", "yourself", "yourselves", "he", "him", "his", "himself", "she",
+	"her", "hers", "herself", "it", "its", "itself", "they", "them", "their",
+	"theirs", "themselves", "what", "which", "who", "whom", "this", "that",
+	"these", "those", "am", "is", "are", "was", "were", "be", "been", "being",
+	"have", "has", "had", "having", "do", "does", "did", "doing", "a", "an",
+	"the", "and", "but", "if", "or", "because", "as", "until", "while", "of",
+	"at", "by", "for", "with", "about", "against", "between", "into", "through",
+	"during", "before", "after", "above", "below", "to", "from", "up", "down",
+	"in", "out", "on", "off", "over", "under", "again", "further", "then",
+	"once", "here", "there", "when", "where", "why", "how", "all", "any",
+	"both", "each", "few", "more", "most", "other", "some", "such", "no", "nor",
+	"not", "only", "own", "same", "so", "than", "too", "very", "s", "t", "can",
+	"will", "just", "don", "should", "now",
+)
+
+func makeStopWords(words ...string) map[string]struct{} {
+	res := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		res[w] = struct{}{}
+	}
+	return res
+}
+
+// Config is a text search configuration, which determines how documents and
+// queries are split into words and how the words are normalized into lexemes.
+type Config struct {
+	name string
+	dict Dictionary
+}
+
+// Name returns the name of the configuration.
+func (c *Config) Name() string {
+	return c.name
+}
+
+// DefaultConfigName is the name of the configuration used when none is given
+// and the default_text_search_config session variable is not set.
+const DefaultConfigName = "english"
+
+var configs = map[string]*Config{
+	"simple": {name: "simple", dict: simpleDictionary{}},
+	"english": {name: "english", dict: stemmingDictionary{
+		stopWords: englishStopWords,
+		stem:      stemEnglish,
+	}},
+}
+
+// ConfigNames returns the names of the supported configurations, in sorted
+// order.
+func ConfigNames() []string {
+	res := make([]string, 0, len(configs))
+	for name := range configs {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// GetConfig returns the configuration with the given name, which can be
+// qualified with the pg_catalog schema.
+func GetConfig(name string) (*Config, error) {
+	if c, ok := configs[strings.TrimPrefix(strings.ToLower(name), "pg_catalog.")]; ok {
+		return c, nil
+	}
+	return nil, pgerror.Newf(pgcode.UndefinedObject,
+		"text search configuration %q does not exist", name)
+}
+
+// Lexize returns the lexeme for the given word, or false if the word is a
+// stop word.
+func (c *Config) Lexize(word string) (string, bool) {
+	return c.dict.Lexize(strings.ToLower(word))
+}
+
+// tokenize splits text into words: maximal runs of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ToTSVector returns the tsvector for the given document. Positions count
+// every word of the document, including stop words, starting at 1.
+func (c *Config) ToTSVector(document string) TSVector {
+	var terms []tsTerm
+	for i, word := range tokenize(document) {
+		lexeme, ok := c.Lexize(word)
+		if !ok {
+			continue
+		}
+		pos := i + 1
+		if pos > maxPosition {
+			pos = maxPosition
+		}
+		terms = append(terms, tsTerm{
+			lexeme:    lexeme,
+			positions: []tsPosition{{position: uint16(pos)}},
+		})
+	}
+	return normalizeTerms(terms)
+}
+
+// ToTSQuery parses a query in the tsquery syntax, normalizing its words with
+// the configuration. A quoted term that normalizes into several lexemes is
+// turned into a phrase, and stop words are removed from the query.
+func (c *Config) ToTSQuery(input string) (TSQuery, error) {
+	return parseTSQuery(input, func(term queryTerm) (*tsNode, error) {
+		return c.phrase(term, tokenize(term.lexeme), followedBy), nil
+	})
+}
+
+// PlainToTSQuery returns a query that matches documents that contain all of
+// the words of the given text, ignoring punctuation and operators.
+func (c *Config) PlainToTSQuery(text string) TSQuery {
+	return TSQuery{root: c.phrase(queryTerm{}, tokenize(text), and)}
+}
+
+// PhraseToTSQuery returns a query that matches documents that contain the
+// words of the given text in the same order, ignoring punctuation and
+// operators. Stop words are not matched, but still count in the distance
+// between the words around them.
+func (c *Config) PhraseToTSQuery(text string) TSQuery {
+	return TSQuery{root: c.phrase(queryTerm{}, tokenize(text), followedBy)}
+}
+
+// phrase combines the lexemes of the given words with op, using the weights
+// and prefix flag of the given template term. For the followed by operator,
+// the distance between two lexemes is the number of words between them, so
+// that the stop words that were removed are accounted for.
+func (c *Config) phrase(template queryTerm, words []string, op tsOperator) *tsNode {
+	var root *tsNode
+	distance := 1
+	for _, word := range words {
+		lexeme, ok := c.Lexize(word)
+		if !ok {
+			if root != nil {
+				distance++
+			}
+			continue
+		}
+		term := template
+		term.lexeme = lexeme
+		leaf := &tsNode{term: term}
+		if root == nil {
+			root = leaf
+		} else if op == followedBy {
+			if distance > maxDistance {
+				distance = maxDistance
+			}
+			root = makeBinaryNode(op, root, leaf, uint16(distance))
+		} else {
+			root = makeBinaryNode(op, root, leaf, 0)
+		}
+		distance = 1
+	}
+	return root
+}
@@ -0,0 +1,159 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStemEnglish(t *testing.T) {
+	testCases := []struct {
+		word, stem string
+	}{
+		{"a", "a"},
+		{"cats", "cat"},
+		{"rats", "rat"},
+		{"running", "run"},
+		{"caresses", "caress"},
+		{"ponies", "poni"},
+		{"ties", "tie"},
+		{"agreed", "agre"},
+		{"hopping", "hop"},
+		{"hoping", "hope"},
+		{"happily", "happili"},
+		{"generously", "generous"},
+		{"consistency", "consist"},
+		{"ability", "abil"},
+		{"abilities", "abil"},
+		{"relational", "relat"},
+		{"conditional", "condit"},
+		{"national", "nation"},
+		{"hopeful", "hope"},
+		{"goodness", "good"},
+		{"electrical", "electr"},
+		{"adjustment", "adjust"},
+		{"communication", "communic"},
+		{"generate", "generat"},
+		{"sky", "sky"},
+		{"skies", "sky"},
+		{"dying", "die"},
+		{"news", "news"},
+		{"succeeding", "succeed"},
+		{"yelling", "yell"},
+		{"supernovae", "supernova"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.word, func(t *testing.T) {
+			require.Equal(t, tc.stem, stemEnglish(tc.word))
+		})
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	c, err := GetConfig("English")
+	require.NoError(t, err)
+	require.Equal(t, "english", c.Name())
+	c, err = GetConfig("pg_catalog.simple")
+	require.NoError(t, err)
+	require.Equal(t, "simple", c.Name())
+	_, err = GetConfig("klingon")
+	require.EqualError(t, err, `text search configuration "klingon" does not exist`)
+	require.Equal(t, []string{"english", "simple"}, ConfigNames())
+}
+
+func TestToTSVector(t *testing.T) {
+	testCases := []struct {
+		config, document, expected string
+	}{
+		{"english", "The Fat Rats", `'fat':2 'rat':3`},
+		{"english", "a fat  cat sat on a mat - it ate a fat rats",
+			`'ate':9 'cat':3 'fat':2,11 'mat':7 'rat':12 'sat':4`},
+		{"simple", "The Fat Rats", `'fat':2 'rats':3 'the':1`},
+		{"english", "", ``},
+		{"english", "the and of", ``},
+	}
+	for _, tc := range testCases {
+		c, err := GetConfig(tc.config)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, c.ToTSVector(tc.document).String(), tc.document)
+	}
+}
+
+func TestToTSQuery(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		input, expected, expectedErr string
+	}{
+		{"The & Fat & Rats", `'fat' & 'rat'`, ``},
+		{"Fat | Rats:AB", `'fat' | 'rat':AB`, ``},
+		{"supernovae:*A & star:A*B", `'supernova':*A & 'star':*AB`, ``},
+		{"!(cats <-> rats)", `!( 'cat' <-> 'rat' )`, ``},
+		{"'fat cats' & rat", `'fat' <-> 'cat' & 'rat'`, ``},
+		{"'the fat' <2> rats", `'fat' <2> 'rat'`, ``},
+		{"the", ``, ``},
+		{"fat &", ``, `syntax error in tsquery: "fat &"`},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.input)
+		if tc.expectedErr != "" {
+			require.EqualError(t, err, tc.expectedErr, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, q.String(), tc.input)
+	}
+}
+
+func TestPlainAndPhraseToTSQuery(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+	require.Equal(t, `'fat' & 'rat'`, english.PlainToTSQuery("The Fat Rats").String())
+	require.Equal(t, `'fat' & 'rat'`, english.PlainToTSQuery("fat & | rats!").String())
+	require.Equal(t, `'fat' <-> 'rat'`, english.PhraseToTSQuery("The Fat Rats").String())
+	require.Equal(t, `'cat' <2> 'rat'`, english.PhraseToTSQuery("The Cat and Rats").String())
+	require.Equal(t, ``, english.PhraseToTSQuery("the and").String())
+}
+
+func TestHeadline(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+	doc := "The most common type of search is to find all documents containing " +
+		"given query terms and return them in order of their similarity to the query."
+
+	q, err := english.ToTSQuery("query & similarity")
+	require.NoError(t, err)
+	require.Equal(t,
+		"containing given <b>query</b> terms and return them in order of their "+
+			"<b>similarity</b> to the <b>query</b>",
+		english.Headline(doc, q, DefaultHeadlineOptions()))
+
+	opts, err := ParseHeadlineOptions("StartSel = <, StopSel = >, HighlightAll=true")
+	require.NoError(t, err)
+	require.Equal(t,
+		"The most common type of search is to find all documents containing "+
+			"given <query> terms and return them in order of their <similarity> to the <query>.",
+		english.Headline(doc, q, opts))
+
+	q, err = english.ToTSQuery("unicorn")
+	require.NoError(t, err)
+	opts, err = ParseHeadlineOptions("MinWords=3, MaxWords=5")
+	require.NoError(t, err)
+	require.Equal(t, "most common", english.Headline(doc, q, opts))
+
+	_, err = ParseHeadlineOptions("MinWords=10, MaxWords=5")
+	require.EqualError(t, err, "MinWords should be less than MaxWords")
+	_, err = ParseHeadlineOptions("Colour=red")
+	require.EqualError(t, err, `unrecognized headline parameter: "Colour"`)
+}
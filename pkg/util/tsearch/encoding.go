@@ -0,0 +1,251 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/cockroachdb/errors"
+)
+
+// The binary encodings of tsvectors and tsqueries are the ones used by the
+// binary format of the Postgres wire protocol (see tsvectorsend and
+// tsquerysend), which are also used to store the values on disk.
+
+// EncodeTSVector appends the binary encoding of the tsvector to appendTo:
+//
+//   int32 number of lexemes, then for each lexeme:
+//     null-terminated lexeme
+//     uint16 number of positions
+//     uint16 for each position: the weight in the top 2 bits, then the position
+//
+func EncodeTSVector(appendTo []byte, v TSVector) []byte {
+	appendTo = appendUint32(appendTo, uint32(len(v)))
+	for i := range v {
+		appendTo = append(appendTo, v[i].lexeme...)
+		appendTo = append(appendTo, 0)
+		appendTo = appendUint16(appendTo, uint16(len(v[i].positions)))
+		for _, p := range v[i].positions {
+			appendTo = appendUint16(appendTo, uint16(p.weight)<<14|p.position)
+		}
+	}
+	return appendTo
+}
+
+// DecodeTSVector decodes a tsvector encoded with EncodeTSVector.
+func DecodeTSVector(b []byte) (TSVector, error) {
+	d := decoder{b: b}
+	n := d.uint32()
+	terms := make([]tsTerm, 0, n)
+	for i := uint32(0); i < n && d.err == nil; i++ {
+		t := tsTerm{lexeme: d.cstring()}
+		numPositions := d.uint16()
+		if numPositions > 0 {
+			t.positions = make([]tsPosition, 0, numPositions)
+		}
+		for j := uint16(0); j < numPositions && d.err == nil; j++ {
+			p := d.uint16()
+			t.positions = append(t.positions, tsPosition{
+				position: p & maxPosition,
+				weight:   tsWeight(p >> 14),
+			})
+		}
+		terms = append(terms, t)
+	}
+	if err := d.finish("tsvector"); err != nil {
+		return nil, err
+	}
+	return normalizeTerms(terms), nil
+}
+
+// The item types and operator codes of the binary encoding of tsqueries.
+const (
+	queryItemVal  = 1
+	queryItemOper = 2
+
+	queryOpNot    = 1
+	queryOpAnd    = 2
+	queryOpOr     = 3
+	queryOpPhrase = 4
+)
+
+// EncodeTSQuery appends the binary encoding of the tsquery to appendTo:
+//
+//   int32 number of items, then for each item, in prefix order with the
+//   right operand of an operator before its left operand:
+//     byte item type (1 for lexemes, 2 for operators)
+//     for lexemes: byte weight mask, byte prefix flag, null-terminated lexeme
+//     for operators: byte operator, and for phrases the int16 distance
+//
+func EncodeTSQuery(appendTo []byte, q TSQuery) []byte {
+	appendTo = appendUint32(appendTo, uint32(q.NumNodes()))
+	var encode func(n *tsNode)
+	encode = func(n *tsNode) {
+		if n == nil {
+			return
+		}
+		if n.op == invalid {
+			appendTo = append(appendTo, queryItemVal, byte(n.term.weights))
+			if n.term.prefix {
+				appendTo = append(appendTo, 1)
+			} else {
+				appendTo = append(appendTo, 0)
+			}
+			appendTo = append(appendTo, n.term.lexeme...)
+			appendTo = append(appendTo, 0)
+			return
+		}
+		appendTo = append(appendTo, queryItemOper)
+		switch n.op {
+		case not:
+			appendTo = append(appendTo, queryOpNot)
+		case and:
+			appendTo = append(appendTo, queryOpAnd)
+		case or:
+			appendTo = append(appendTo, queryOpOr)
+		case followedBy:
+			appendTo = append(appendTo, queryOpPhrase)
+			appendTo = appendUint16(appendTo, n.distance)
+		}
+		encode(n.r)
+		encode(n.l)
+	}
+	encode(q.root)
+	return appendTo
+}
+
+// DecodeTSQuery decodes a tsquery encoded with EncodeTSQuery.
+func DecodeTSQuery(b []byte) (TSQuery, error) {
+	d := decoder{b: b}
+	remaining := d.uint32()
+	var decode func() *tsNode
+	decode = func() *tsNode {
+		if d.err != nil {
+			return nil
+		}
+		if remaining == 0 {
+			d.err = errors.New("missing operand")
+			return nil
+		}
+		remaining--
+		switch d.byte() {
+		case queryItemVal:
+			n := &tsNode{}
+			n.term.weights = weightMask(d.byte())
+			n.term.prefix = d.byte() != 0
+			n.term.lexeme = d.cstring()
+			return n
+		case queryItemOper:
+			n := &tsNode{}
+			switch d.byte() {
+			case queryOpNot:
+				n.op = not
+				n.l = decode()
+				return n
+			case queryOpAnd:
+				n.op = and
+			case queryOpOr:
+				n.op = or
+			case queryOpPhrase:
+				n.op = followedBy
+				n.distance = d.uint16()
+			default:
+				d.err = errors.New("invalid operator")
+				return nil
+			}
+			n.r = decode()
+			n.l = decode()
+			return n
+		}
+		d.err = errors.New("invalid item type")
+		return nil
+	}
+	var q TSQuery
+	if remaining > 0 {
+		q.root = decode()
+	}
+	if remaining > 0 && d.err == nil {
+		d.err = errors.New("unexpected trailing items")
+	}
+	if err := d.finish("tsquery"); err != nil {
+		return TSQuery{}, err
+	}
+	return q, nil
+}
+
+// decoder reads the binary encodings, remembering the first error.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) need(n int) bool {
+	if d.err == nil && len(d.b) < n {
+		d.err = errors.New("unexpected end of input")
+	}
+	return d.err == nil
+}
+
+func (d *decoder) byte() byte {
+	if !d.need(1) {
+		return 0
+	}
+	res := d.b[0]
+	d.b = d.b[1:]
+	return res
+}
+
+func (d *decoder) uint16() uint16 {
+	if !d.need(2) {
+		return 0
+	}
+	res := binary.BigEndian.Uint16(d.b)
+	d.b = d.b[2:]
+	return res
+}
+
+func (d *decoder) uint32() uint32 {
+	if !d.need(4) {
+		return 0
+	}
+	res := binary.BigEndian.Uint32(d.b)
+	d.b = d.b[4:]
+	return res
+}
+
+func (d *decoder) cstring() string {
+	i := bytes.IndexByte(d.b, 0)
+	if i < 0 {
+		if d.err == nil {
+			d.err = errors.New("unterminated string")
+		}
+		return ""
+	}
+	res := string(d.b[:i])
+	d.b = d.b[i+1:]
+	return res
+}
+
+func (d *decoder) finish(typ string) error {
+	if d.err == nil && len(d.b) > 0 {
+		d.err = errors.New("unexpected trailing bytes")
+	}
+	return errors.Wrapf(d.err, "decoding %s", typ)
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v>>8), byte(v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
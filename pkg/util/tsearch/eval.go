@@ -0,0 +1,237 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "sort"
+
+// EvalTSQuery returns whether the given tsvector matches the given tsquery,
+// which is the semantics of the @@ operator. An empty query matches nothing.
+func EvalTSQuery(q TSQuery, v TSVector) bool {
+	if q.root == nil {
+		return false
+	}
+	return q.root.eval(v)
+}
+
+func (n *tsNode) eval(v TSVector) bool {
+	switch n.op {
+	case invalid:
+		return n.term.matches(v)
+	case and:
+		return n.l.eval(v) && n.r.eval(v)
+	case or:
+		return n.l.eval(v) || n.r.eval(v)
+	case not:
+		return !n.l.eval(v)
+	case followedBy:
+		return n.evalPhrase(v).matches()
+	}
+	return false
+}
+
+// matches returns whether the term occurs in the tsvector with one of the
+// weights it asks for. Lexemes without position information match regardless
+// of the weights, since their weights are unknown.
+func (t *queryTerm) matches(v TSVector) bool {
+	start, end := t.entries(v)
+	for i := start; i < end; i++ {
+		if len(v[i].positions) == 0 {
+			return true
+		}
+		for _, p := range v[i].positions {
+			if t.weights.matches(p.weight) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// entries returns the range [start, end) of the entries of the tsvector that
+// the term matches.
+func (t *queryTerm) entries(v TSVector) (start, end int) {
+	if t.prefix {
+		return v.findPrefix(t.lexeme)
+	}
+	if i := v.find(t.lexeme); i >= 0 {
+		return i, i + 1
+	}
+	return 0, 0
+}
+
+// phraseResult describes where a subexpression of a phrase matches in a
+// document.
+type phraseResult struct {
+	// positions is the sorted set of positions at which the subexpression
+	// matches. For phrases, these are the positions of the end of the phrase.
+	positions []uint16
+	// negated is set if the subexpression matches at every position except
+	// the ones listed.
+	negated bool
+	// unknown is set if the subexpression matches lexemes that have no
+	// position information. Such matches are considered successful, since
+	// their positions can't be checked.
+	unknown bool
+}
+
+func (r phraseResult) matches() bool {
+	return r.unknown || r.negated || len(r.positions) > 0
+}
+
+// evalPhrase evaluates a subexpression of a followed by operator, returning
+// the positions at which it matches.
+func (n *tsNode) evalPhrase(v TSVector) phraseResult {
+	switch n.op {
+	case invalid:
+		var res phraseResult
+		start, end := n.term.entries(v)
+		for i := start; i < end; i++ {
+			if len(v[i].positions) == 0 {
+				res.unknown = true
+			}
+			for _, p := range v[i].positions {
+				if n.term.weights.matches(p.weight) {
+					res.positions = append(res.positions, p.position)
+				}
+			}
+		}
+		res.positions = sortedUnique(res.positions)
+		return res
+	case not:
+		res := n.l.evalPhrase(v)
+		if !res.unknown {
+			res.negated = !res.negated
+		}
+		return res
+	}
+
+	l, r := n.l.evalPhrase(v), n.r.evalPhrase(v)
+	if l.unknown || r.unknown {
+		if n.op == or {
+			return phraseResult{unknown: l.matches() || r.matches()}
+		}
+		return phraseResult{unknown: l.matches() && r.matches()}
+	}
+	switch n.op {
+	case and:
+		switch {
+		case !l.negated && !r.negated:
+			return phraseResult{positions: intersect(l.positions, r.positions)}
+		case l.negated && r.negated:
+			return phraseResult{positions: union(l.positions, r.positions), negated: true}
+		case l.negated:
+			return phraseResult{positions: subtract(r.positions, l.positions)}
+		default:
+			return phraseResult{positions: subtract(l.positions, r.positions)}
+		}
+	case or:
+		switch {
+		case !l.negated && !r.negated:
+			return phraseResult{positions: union(l.positions, r.positions)}
+		case l.negated && r.negated:
+			return phraseResult{positions: intersect(l.positions, r.positions), negated: true}
+		case l.negated:
+			return phraseResult{positions: subtract(l.positions, r.positions), negated: true}
+		default:
+			return phraseResult{positions: subtract(r.positions, l.positions), negated: true}
+		}
+	case followedBy:
+		shifted := shift(l.positions, n.distance)
+		switch {
+		case !l.negated && !r.negated:
+			return phraseResult{positions: intersect(shifted, r.positions)}
+		case l.negated && r.negated:
+			return phraseResult{positions: union(shifted, r.positions), negated: true}
+		case l.negated:
+			return phraseResult{positions: subtract(r.positions, shifted)}
+		default:
+			return phraseResult{positions: subtract(shifted, r.positions)}
+		}
+	}
+	return phraseResult{}
+}
+
+func sortedUnique(positions []uint16) []uint16 {
+	sort.Slice(positions, func(i, j int) bool { return positions[i] < positions[j] })
+	res := positions[:0]
+	for i, p := range positions {
+		if i == 0 || p != positions[i-1] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// shift returns the positions that are the given distance after the input
+// positions.
+func shift(positions []uint16, distance uint16) []uint16 {
+	res := make([]uint16, 0, len(positions))
+	for _, p := range positions {
+		if int(p)+int(distance) <= maxPosition {
+			res = append(res, p+distance)
+		}
+	}
+	return res
+}
+
+func intersect(a, b []uint16) []uint16 {
+	var res []uint16
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	return res
+}
+
+func union(a, b []uint16) []uint16 {
+	res := make([]uint16, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			res = append(res, a[i])
+			i++
+		case a[i] > b[j]:
+			res = append(res, b[j])
+			j++
+		default:
+			res = append(res, a[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, a[i:]...)
+	return append(res, b[j:]...)
+}
+
+// subtract returns the positions of a that are not in b.
+func subtract(a, b []uint16) []uint16 {
+	var res []uint16
+	j := 0
+	for _, p := range a {
+		for j < len(b) && b[j] < p {
+			j++
+		}
+		if j < len(b) && b[j] == p {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
@@ -0,0 +1,226 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// HeadlineOptions control the excerpt returned by Headline. They mirror the
+// options of the ts_headline function of Postgres.
+type HeadlineOptions struct {
+	// StartSel and StopSel delimit the words of the document that match the
+	// query.
+	StartSel, StopSel string
+	// MaxWords and MinWords bound the number of words in the excerpt.
+	MaxWords, MinWords int
+	// ShortWord is the length of the words that are dropped at the start and
+	// end of the excerpt, unless they match the query.
+	ShortWord int
+	// HighlightAll, if set, returns the whole document.
+	HighlightAll bool
+}
+
+// DefaultHeadlineOptions returns the options used by Headline when none are
+// given.
+func DefaultHeadlineOptions() HeadlineOptions {
+	return HeadlineOptions{
+		StartSel:  "<b>",
+		StopSel:   "</b>",
+		MaxWords:  35,
+		MinWords:  15,
+		ShortWord: 3,
+	}
+}
+
+// ParseHeadlineOptions parses a comma-separated list of option=value pairs,
+// for example "MaxWords=10, StartSel=<<, StopSel=>>", overriding the
+// defaults.
+func ParseHeadlineOptions(input string) (HeadlineOptions, error) {
+	opts := DefaultHeadlineOptions()
+	for _, pair := range strings.Split(input, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		eq := strings.IndexByte(pair, '=')
+		if eq < 0 {
+			return opts, pgerror.Newf(pgcode.Syntax, "invalid headline parameter: %q", pair)
+		}
+		name := strings.TrimSpace(pair[:eq])
+		value := strings.Trim(strings.TrimSpace(pair[eq+1:]), `"`)
+		var err error
+		switch strings.ToLower(name) {
+		case "startsel":
+			opts.StartSel = value
+		case "stopsel":
+			opts.StopSel = value
+		case "maxwords":
+			opts.MaxWords, err = strconv.Atoi(value)
+		case "minwords":
+			opts.MinWords, err = strconv.Atoi(value)
+		case "shortword":
+			opts.ShortWord, err = strconv.Atoi(value)
+		case "highlightall":
+			switch strings.ToLower(value) {
+			case "1", "on", "t", "true", "y", "yes":
+				opts.HighlightAll = true
+			default:
+				opts.HighlightAll = false
+			}
+		default:
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"unrecognized headline parameter: %q", name)
+		}
+		if err != nil {
+			return opts, pgerror.Newf(pgcode.InvalidParameterValue,
+				"invalid value for headline parameter %q: %q", name, value)
+		}
+	}
+	if !opts.HighlightAll {
+		if opts.MinWords >= opts.MaxWords {
+			return opts, pgerror.New(pgcode.InvalidParameterValue,
+				"MinWords should be less than MaxWords")
+		}
+		if opts.MinWords <= 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "MinWords should be positive")
+		}
+		if opts.ShortWord < 0 {
+			return opts, pgerror.New(pgcode.InvalidParameterValue, "ShortWord should be >= 0")
+		}
+	}
+	return opts, nil
+}
+
+// word is a word of a document, along with its byte offsets in the document.
+type word struct {
+	start, end int
+	text       string
+	// match is set if the word matches a term of the query.
+	match bool
+}
+
+// Headline returns an excerpt of the document in which the words that match
+// the query are highlighted. The excerpt contains the first words that match
+// the query, or the beginning of the document if none do.
+func (c *Config) Headline(document string, q TSQuery, opts HeadlineOptions) string {
+	terms := q.root.positiveTerms(false /* negated */, nil)
+	words := splitWords(document)
+	for i := range words {
+		lexeme, ok := c.Lexize(words[i].text)
+		if !ok {
+			continue
+		}
+		for _, t := range terms {
+			if lexeme == t.lexeme || (t.prefix && strings.HasPrefix(lexeme, t.lexeme)) {
+				words[i].match = true
+				break
+			}
+		}
+	}
+	if len(words) == 0 {
+		return document
+	}
+
+	first, last := 0, len(words)-1
+	if !opts.HighlightAll {
+		first, last = selectExcerpt(words, opts)
+	}
+	var b strings.Builder
+	prev := words[first].start
+	if opts.HighlightAll {
+		prev = 0
+	}
+	for _, w := range words[first : last+1] {
+		b.WriteString(document[prev:w.start])
+		if w.match {
+			b.WriteString(opts.StartSel)
+			b.WriteString(w.text)
+			b.WriteString(opts.StopSel)
+		} else {
+			b.WriteString(w.text)
+		}
+		prev = w.end
+	}
+	if opts.HighlightAll {
+		b.WriteString(document[prev:])
+	}
+	return b.String()
+}
+
+// selectExcerpt returns the indexes of the first and last words of the
+// excerpt. It starts at the first word that matches the query and contains
+// as many matches as fit in MaxWords words, extended to at least MinWords
+// words. Short words that don't match are then trimmed from both ends.
+func selectExcerpt(words []word, opts HeadlineOptions) (first, last int) {
+	first = -1
+	for i := range words {
+		if words[i].match {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		first, last = 0, opts.MinWords-1
+	} else {
+		last = first
+		for i := first; i < len(words) && i-first < opts.MaxWords; i++ {
+			if words[i].match {
+				last = i
+			}
+		}
+		for last-first+1 < opts.MinWords && last < len(words)-1 {
+			last++
+		}
+		for last-first+1 < opts.MinWords && first > 0 {
+			first--
+		}
+	}
+	if last >= len(words) {
+		last = len(words) - 1
+	}
+	isShort := func(w *word) bool {
+		return !w.match && utf8.RuneCountInString(w.text) <= opts.ShortWord
+	}
+	for first < last && isShort(&words[first]) {
+		first++
+	}
+	for last > first && isShort(&words[last]) {
+		last--
+	}
+	return first, last
+}
+
+// splitWords splits a document into words in the same way as tokenize,
+// keeping track of their offsets.
+func splitWords(document string) []word {
+	var res []word
+	start := -1
+	for i, r := range document {
+		isWordChar := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWordChar && start < 0:
+			start = i
+		case !isWordChar && start >= 0:
+			res = append(res, word{start: start, end: i, text: document[start:i]})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		res = append(res, word{start: start, end: len(document), text: document[start:]})
+	}
+	return res
+}
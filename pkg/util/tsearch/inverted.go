@@ -0,0 +1,81 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"github.com/cockroachdb/cockroach/pkg/roachpb"
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/cockroachdb/cockroach/pkg/util/encoding"
+)
+
+// EncodeInvertedIndexKeys takes in a key prefix and returns a slice of
+// inverted index keys, one per lexeme of the tsvector.
+func EncodeInvertedIndexKeys(inKey []byte, v TSVector) [][]byte {
+	keys := make([][]byte, 0, len(v))
+	for i := range v {
+		// Make sure each key has a separate backing array.
+		key := make([]byte, len(inKey), len(inKey)+len(v[i].lexeme)+2)
+		copy(key, inKey)
+		keys = append(keys, encoding.EncodeStringAscending(key, v[i].lexeme))
+	}
+	return keys
+}
+
+// EncodeInvertedIndexSpans takes in a key prefix and returns the spans that
+// must be scanned in the inverted index to evaluate a match (@@) of the given
+// tsquery against the indexed tsvectors.
+//
+// A document can only match the query if it contains the lexemes that the
+// query requires, so the spans are built from the lexemes that aren't
+// negated: a lexeme matches its single key, a prefix matches the range of keys
+// that start with it, & intersects the spans of its operands and | unions
+// them. Negated subexpressions can't be evaluated with the index, and make the
+// returned expression not tight. The same goes for phrases and weight
+// restrictions, since the index doesn't store positions or weights.
+//
+// If the query doesn't constrain the index at all (for example, !'cat'),
+// inverted.NonInvertedColExpression is returned.
+func EncodeInvertedIndexSpans(inKey []byte, q TSQuery) inverted.Expression {
+	if q.root == nil {
+		// An empty query doesn't match anything, but that can't be represented
+		// by a span expression.
+		return inverted.NonInvertedColExpression{}
+	}
+	return q.root.invertedExpr(inKey)
+}
+
+func (n *tsNode) invertedExpr(inKey []byte) inverted.Expression {
+	switch n.op {
+	case invalid:
+		key := encoding.EncodeStringAscending(append([]byte(nil), inKey...), n.term.lexeme)
+		tight := n.term.weights == 0
+		if !n.term.prefix {
+			expr := inverted.ExprForSpan(inverted.MakeSingleValSpan(key), tight)
+			// Each lexeme is indexed at most once per row.
+			expr.Unique = true
+			return expr
+		}
+		// Remove the terminator of the encoded string, so that the span covers
+		// every string that starts with the prefix.
+		start := key[:len(key)-2]
+		span := inverted.Span{Start: start, End: inverted.EncVal(roachpb.Key(start).PrefixEnd())}
+		return inverted.ExprForSpan(span, tight)
+	case and:
+		return inverted.And(n.l.invertedExpr(inKey), n.r.invertedExpr(inKey))
+	case or:
+		return inverted.Or(n.l.invertedExpr(inKey), n.r.invertedExpr(inKey))
+	case followedBy:
+		expr := inverted.And(n.l.invertedExpr(inKey), n.r.invertedExpr(inKey))
+		expr.SetNotTight()
+		return expr
+	}
+	return inverted.NonInvertedColExpression{}
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/inverted"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvertedIndexSpans(t *testing.T) {
+	testCases := []struct {
+		query string
+		// indexable is false if the query doesn't constrain the index.
+		indexable bool
+		tight     bool
+		unique    bool
+	}{
+		{`fat`, true, true, true},
+		{`fat & rat`, true, true, true},
+		{`fat | rat`, true, true, false},
+		{`fa:*`, true, true, false},
+		{`fat:A`, true, false, true},
+		{`fat <-> rat`, true, false, true},
+		{`fat & !rat`, true, false, true},
+		{`!fat`, false, false, false},
+		{`fat | !rat`, false, false, false},
+		{``, false, false, false},
+	}
+	vectors := []string{
+		`fat`, `rat`, `cat`, `fat rat`, `fat cat`, `fast`, `fa`, `f`,
+		`fat:1 rat:2`, `rat:1 fat:2`, `fat:1A`,
+	}
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			q, err := ParseTSQuery(tc.query)
+			require.NoError(t, err)
+			expr := EncodeInvertedIndexSpans(nil /* inKey */, q)
+			spanExpr, ok := expr.(*inverted.SpanExpression)
+			require.Equal(t, tc.indexable, ok)
+			if !ok {
+				return
+			}
+			require.Equal(t, tc.tight, spanExpr.Tight)
+			require.Equal(t, tc.unique, spanExpr.Unique)
+
+			// Every document that matches the query must be found by the spans,
+			// and if the spans are tight, only those documents.
+			for _, s := range vectors {
+				v, err := ParseTSVector(s)
+				require.NoError(t, err)
+				found, err := spanExpr.ContainsKeys(EncodeInvertedIndexKeys(nil /* inKey */, v))
+				require.NoError(t, err)
+				if EvalTSQuery(q, v) {
+					require.True(t, found, s)
+				} else if tc.tight {
+					require.False(t, found, s)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "math/rand"
+
+// randomLexeme returns a short random lexeme. Lexemes are drawn from a small
+// alphabet so that random tsvectors and tsqueries often have lexemes in
+// common.
+func randomLexeme(rng *rand.Rand) string {
+	const alphabet = "abcde"
+	b := make([]byte, 1+rng.Intn(3))
+	for i := range b {
+		b[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return string(b)
+}
+
+// RandomTSVector returns a random TSVector, for testing.
+func RandomTSVector(rng *rand.Rand) TSVector {
+	terms := make([]tsTerm, rng.Intn(10))
+	for i := range terms {
+		terms[i].lexeme = randomLexeme(rng)
+		if rng.Intn(2) == 0 {
+			continue
+		}
+		terms[i].positions = make([]tsPosition, 1+rng.Intn(3))
+		for j := range terms[i].positions {
+			terms[i].positions[j] = tsPosition{
+				position: uint16(1 + rng.Intn(maxPosition)),
+				weight:   tsWeight(rng.Intn(4)),
+			}
+		}
+	}
+	return normalizeTerms(terms)
+}
+
+// RandomTSQuery returns a random TSQuery, for testing.
+func RandomTSQuery(rng *rand.Rand) TSQuery {
+	if rng.Intn(10) == 0 {
+		return TSQuery{}
+	}
+	return TSQuery{root: randomTSNode(rng, 3 /* depth */)}
+}
+
+func randomTSNode(rng *rand.Rand, depth int) *tsNode {
+	if depth == 0 || rng.Intn(2) == 0 {
+		n := &tsNode{term: queryTerm{lexeme: randomLexeme(rng)}}
+		if rng.Intn(4) == 0 {
+			n.term.weights = weightMask(1 + rng.Intn(15))
+		}
+		n.term.prefix = rng.Intn(4) == 0
+		return n
+	}
+	n := &tsNode{op: tsOperator(1 + rng.Intn(int(followedBy)))}
+	n.l = randomTSNode(rng, depth-1)
+	if n.op != not {
+		n.r = randomTSNode(rng, depth-1)
+	}
+	if n.op == followedBy {
+		n.distance = uint16(rng.Intn(5))
+	}
+	return n
+}
@@ -0,0 +1,222 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// defaultRankWeights are the weights of the D, C, B and A positions used
+// when ranking, unless others are given.
+var defaultRankWeights = [4]float64{0.1, 0.2, 0.4, 1.0}
+
+// The flags of the normalization argument of Rank, which can be combined. See
+// https://www.postgresql.org/docs/current/textsearch-controls.html#TEXTSEARCH-RANKING.
+const (
+	// rankNormLogLength divides the rank by 1 + the logarithm of the document
+	// length.
+	rankNormLogLength = 0x01
+	// rankNormLength divides the rank by the document length.
+	rankNormLength = 0x02
+	// rankNormUniq divides the rank by the number of unique words in the
+	// document.
+	rankNormUniq = 0x08
+	// rankNormLogUniq divides the rank by 1 + the logarithm of the number of
+	// unique words in the document.
+	rankNormLogUniq = 0x10
+	// rankNormRDivRPlus1 divides the rank by itself + 1.
+	rankNormRDivRPlus1 = 0x20
+)
+
+// Rank returns the relevance of the document v for the query q, as computed
+// by the ts_rank function of Postgres. weights, if not nil, overrides the
+// weights of the D, C, B and A positions (in that order), and normalization
+// is a combination of flags that control how the document length affects the
+// rank.
+func Rank(weights []float64, v TSVector, q TSQuery, normalization int) (float32, error) {
+	w := defaultRankWeights
+	if weights != nil {
+		if len(weights) < len(w) {
+			return 0, pgerror.New(pgcode.ArraySubscript, "array of weight is too short")
+		}
+		for i := range w {
+			if weights[i] > 1 {
+				return 0, pgerror.New(pgcode.InvalidParameterValue, "weight out of range")
+			}
+			if weights[i] >= 0 {
+				w[i] = weights[i]
+			}
+		}
+	}
+	if len(v) == 0 || q.root == nil {
+		return 0, nil
+	}
+
+	terms := q.uniqueTerms()
+	var res float64
+	if (q.root.op == and || q.root.op == followedBy) && len(terms) > 1 {
+		res = rankAnd(&w, v, terms)
+	} else {
+		res = rankOr(&w, v, terms)
+	}
+	if res < 0 {
+		res = 1e-20
+	}
+
+	if normalization&rankNormLogLength != 0 {
+		res /= math.Log2(float64(v.length() + 1))
+	}
+	if normalization&rankNormLength != 0 {
+		if l := v.length(); l > 0 {
+			res /= float64(l)
+		}
+	}
+	if normalization&rankNormUniq != 0 {
+		res /= float64(len(v))
+	}
+	if normalization&rankNormLogUniq != 0 {
+		res /= math.Log2(float64(len(v) + 1))
+	}
+	if normalization&rankNormRDivRPlus1 != 0 {
+		res /= res + 1
+	}
+	return float32(res), nil
+}
+
+// uniqueTerms returns the distinct terms of the query.
+func (q TSQuery) uniqueTerms() []queryTerm {
+	var res []queryTerm
+	seen := make(map[queryTerm]struct{})
+	q.root.forEachTerm(func(t *queryTerm) {
+		key := queryTerm{lexeme: t.lexeme, prefix: t.prefix}
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			res = append(res, key)
+		}
+	})
+	return res
+}
+
+// length returns the number of positions in the tsvector, counting lexemes
+// without positions once.
+func (v TSVector) length() int {
+	var res int
+	for i := range v {
+		if n := len(v[i].positions); n > 0 {
+			res += n
+		} else {
+			res++
+		}
+	}
+	return res
+}
+
+// termPositions returns the positions of the lexemes of the tsvector that the
+// term matches. Lexemes without positions are treated as occurring once, with
+// weight D, at the unknown position 0.
+func (t *queryTerm) termPositions(v TSVector) []tsPosition {
+	var res []tsPosition
+	start, end := t.entries(v)
+	for i := start; i < end; i++ {
+		if len(v[i].positions) == 0 {
+			res = append(res, tsPosition{})
+		}
+		res = append(res, v[i].positions...)
+	}
+	return res
+}
+
+// wordDistance returns the factor by which a pair of matched lexemes
+// contributes to the rank, given the distance between them.
+func wordDistance(distance int) float64 {
+	if distance > 100 {
+		return 1e-30
+	}
+	return 1.0 / (1.005 + 0.05*math.Exp(float64(distance)/1.5-2))
+}
+
+// rankOr computes the rank of a query whose terms are independent of each
+// other: every occurrence of every term increases the rank, with decreasing
+// returns for repeated occurrences.
+func rankOr(weights *[4]float64, v TSVector, terms []queryTerm) float64 {
+	// The sum of 1/i^2 for i >= 1, which normalizes the contributions of the
+	// occurrences of a term.
+	const sumOfInverseSquares = math.Pi * math.Pi / 6
+	var res float64
+	for i := range terms {
+		positions := terms[i].termPositions(v)
+		if len(positions) == 0 {
+			continue
+		}
+		var sum, maxWeight float64
+		maxWeight = -1
+		maxIdx := 0
+		for j, p := range positions {
+			w := weights[p.weight]
+			sum += w / float64((j+1)*(j+1))
+			if w > maxWeight {
+				maxWeight = w
+				maxIdx = j
+			}
+		}
+		res += (maxWeight + sum - maxWeight/float64((maxIdx+1)*(maxIdx+1))) / sumOfInverseSquares
+	}
+	return res / float64(len(terms))
+}
+
+// rankAnd computes the rank of a query whose terms must all occur: every pair
+// of occurrences of different terms increases the rank, more so when they are
+// close to each other.
+func rankAnd(weights *[4]float64, v TSVector, terms []queryTerm) float64 {
+	positions := make([][]tsPosition, len(terms))
+	for i := range terms {
+		positions[i] = terms[i].termPositions(v)
+	}
+	res := -1.0
+	for i := range positions {
+		for k := 0; k < i; k++ {
+			for _, p := range positions[i] {
+				for _, q := range positions[k] {
+					distance := rankPosition(p) - rankPosition(q)
+					if distance < 0 {
+						distance = -distance
+					}
+					if distance == 0 {
+						if p.position != 0 && q.position != 0 {
+							continue
+						}
+						// Unknown positions are considered far apart.
+						distance = maxPosition + 1
+					}
+					cur := math.Sqrt(weights[p.weight] * weights[q.weight] * wordDistance(distance))
+					if res < 0 {
+						res = cur
+					} else {
+						res = 1 - (1-res)*(1-cur)
+					}
+				}
+			}
+		}
+	}
+	return res
+}
+
+// rankPosition returns the position used to compute distances when ranking.
+// Unknown positions are placed at the end of the document, as in Postgres.
+func rankPosition(p tsPosition) int {
+	if p.position == 0 {
+		return maxPosition
+	}
+	return int(p.position)
+}
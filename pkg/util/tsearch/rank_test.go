@@ -0,0 +1,49 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRank(t *testing.T) {
+	english, err := GetConfig("english")
+	require.NoError(t, err)
+	testCases := []struct {
+		document, query string
+		weights         []float64
+		normalization   int
+		expected        float32
+	}{
+		{"The quick brown fox", "fox", nil, 0, 0.06079271},
+		{"The quick brown fox", "dog", nil, 0, 0},
+		{"The quick brown fox", "quick & fox", nil, 0, 0.098500855},
+		{"The quick brown fox", "quick | fox", nil, 0, 0.06079271},
+		{"fox", "quick & fox", nil, 0, 1e-20},
+		{"The quick brown fox", "fox", []float64{1, 1, 1, 1}, 0, 0.6079271},
+		{"The quick brown fox", "fox", nil, rankNormLength, 0.020264236},
+		{"The quick brown fox", "fox", nil, rankNormRDivRPlus1, 0.057308756},
+	}
+	for _, tc := range testCases {
+		q, err := english.ToTSQuery(tc.query)
+		require.NoError(t, err)
+		rank, err := Rank(tc.weights, english.ToTSVector(tc.document), q, tc.normalization)
+		require.NoError(t, err)
+		require.InDelta(t, tc.expected, rank, 1e-6, "%s @@ %s", tc.document, tc.query)
+	}
+
+	_, err = Rank([]float64{1, 1}, TSVector{}, TSQuery{}, 0)
+	require.EqualError(t, err, "array of weight is too short")
+	_, err = Rank([]float64{1, 1, 1, 2}, TSVector{}, TSQuery{}, 0)
+	require.EqualError(t, err, "weight out of range")
+}
@@ -0,0 +1,409 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import "strings"
+
+// This file implements the English stemming algorithm of the Snowball project
+// (also known as Porter2), which is the stemmer used by the english text
+// search configuration of Postgres. See
+// https://snowballstem.org/algorithms/english/stemmer.html for a description
+// of the algorithm and the definitions of the terms used in the comments
+// below (R1, R2, short syllables, etc.).
+
+// englishExceptions are words that are not stemmed by the algorithm but
+// mapped to a fixed stem instead.
+var englishExceptions = map[string]string{
+	"skis":   "ski",
+	"skies":  "sky",
+	"dying":  "die",
+	"lying":  "lie",
+	"tying":  "tie",
+	"idly":   "idl",
+	"gently": "gentl",
+	"ugly":   "ugli",
+	"early":  "earli",
+	"only":   "onli",
+	"singly": "singl",
+	"sky":    "sky",
+	"news":   "news",
+	"howe":   "howe",
+	"atlas":  "atlas",
+	"cosmos": "cosmos",
+	"bias":   "bias",
+	"andes":  "andes",
+}
+
+// englishStep1aExceptions are words that are left unchanged after step 1a.
+var englishStep1aExceptions = map[string]struct{}{
+	"inning":  {},
+	"outing":  {},
+	"canning": {},
+	"herring": {},
+	"earring": {},
+	"proceed": {},
+	"exceed":  {},
+	"succeed": {},
+}
+
+// stemEnglish returns the stem of a lower case English word.
+func stemEnglish(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+	s := englishStemmer{w: []rune(strings.TrimPrefix(word, "'"))}
+	if len(s.w) <= 2 {
+		return string(s.w)
+	}
+	s.markConsonantYs()
+	s.computeRegions()
+	s.step0()
+	s.step1a()
+	if _, ok := englishStep1aExceptions[string(s.w)]; ok {
+		return string(s.w)
+	}
+	s.step1b()
+	s.step1c()
+	s.step2()
+	s.step3()
+	s.step4()
+	s.step5()
+	for i, c := range s.w {
+		if c == 'Y' {
+			s.w[i] = 'y'
+		}
+	}
+	return string(s.w)
+}
+
+type englishStemmer struct {
+	w []rune
+	// r1 and r2 are the start offsets of the R1 and R2 regions.
+	r1, r2 int
+}
+
+func isEnglishVowel(c rune) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+// markConsonantYs replaces an initial y, and every y that follows a vowel, by
+// Y, so that they are treated as consonants.
+func (s *englishStemmer) markConsonantYs() {
+	for i, c := range s.w {
+		if c == 'y' && (i == 0 || isEnglishVowel(s.w[i-1])) {
+			s.w[i] = 'Y'
+		}
+	}
+}
+
+func (s *englishStemmer) computeRegions() {
+	s.r1 = len(s.w)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if s.hasPrefix(prefix) {
+			s.r1 = len(prefix)
+			break
+		}
+	}
+	if s.r1 == len(s.w) {
+		s.r1 = s.regionStart(0)
+	}
+	s.r2 = s.regionStart(s.r1)
+}
+
+// regionStart returns the offset following the first non-vowel that follows a
+// vowel, starting the search at the given offset.
+func (s *englishStemmer) regionStart(from int) int {
+	for i := from + 1; i < len(s.w); i++ {
+		if !isEnglishVowel(s.w[i]) && isEnglishVowel(s.w[i-1]) {
+			return i + 1
+		}
+	}
+	return len(s.w)
+}
+
+func (s *englishStemmer) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(s.w), prefix)
+}
+
+func (s *englishStemmer) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(s.w), suffix)
+}
+
+// longestSuffix returns the longest of the given suffixes that the word ends
+// with, or the empty string.
+func (s *englishStemmer) longestSuffix(suffixes ...string) string {
+	var res string
+	for _, suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
+
+// suffixStart returns the offset at which the given suffix starts.
+func (s *englishStemmer) suffixStart(suffix string) int {
+	return len(s.w) - len([]rune(suffix))
+}
+
+func (s *englishStemmer) inR1(suffix string) bool {
+	return s.suffixStart(suffix) >= s.r1
+}
+
+func (s *englishStemmer) inR2(suffix string) bool {
+	return s.suffixStart(suffix) >= s.r2
+}
+
+// replace replaces the given suffix of the word.
+func (s *englishStemmer) replace(suffix, with string) {
+	s.w = append(s.w[:s.suffixStart(suffix)], []rune(with)...)
+}
+
+// containsVowel returns whether the word contains a vowel before the given
+// offset.
+func (s *englishStemmer) containsVowel(end int) bool {
+	for _, c := range s.w[:end] {
+		if isEnglishVowel(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// endsWithShortSyllable returns whether the word ends with a short syllable:
+// either a non-vowel followed by a vowel followed by a non-vowel other than w,
+// x or Y, or a vowel at the beginning of the word followed by a non-vowel.
+func (s *englishStemmer) endsWithShortSyllable() bool {
+	n := len(s.w)
+	if n == 2 {
+		return isEnglishVowel(s.w[0]) && !isEnglishVowel(s.w[1])
+	}
+	if n < 3 {
+		return false
+	}
+	c := s.w[n-1]
+	return !isEnglishVowel(s.w[n-3]) && isEnglishVowel(s.w[n-2]) &&
+		!isEnglishVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+}
+
+// isShort returns whether the word is short: it ends with a short syllable
+// and R1 is empty.
+func (s *englishStemmer) isShort() bool {
+	return s.r1 >= len(s.w) && s.endsWithShortSyllable()
+}
+
+func (s *englishStemmer) step0() {
+	if suffix := s.longestSuffix("'", "'s", "'s'"); suffix != "" {
+		s.replace(suffix, "")
+	}
+}
+
+func (s *englishStemmer) step1a() {
+	switch suffix := s.longestSuffix("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		s.replace(suffix, "ss")
+	case "ied", "ies":
+		if s.suffixStart(suffix) > 1 {
+			s.replace(suffix, "i")
+		} else {
+			s.replace(suffix, "ie")
+		}
+	case "s":
+		// Delete the s if the preceding word part contains a vowel that is not
+		// immediately before the s.
+		if s.containsVowel(len(s.w) - 2) {
+			s.replace(suffix, "")
+		}
+	}
+}
+
+func (s *englishStemmer) step1b() {
+	switch suffix := s.longestSuffix("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if s.inR1(suffix) {
+			s.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !s.containsVowel(s.suffixStart(suffix)) {
+			return
+		}
+		s.replace(suffix, "")
+		switch {
+		case s.hasSuffix("at") || s.hasSuffix("bl") || s.hasSuffix("iz"):
+			s.w = append(s.w, 'e')
+		case s.endsWithDouble():
+			s.w = s.w[:len(s.w)-1]
+		case s.isShort():
+			s.w = append(s.w, 'e')
+		}
+	}
+}
+
+// endsWithDouble returns whether the word ends with one of the doubled
+// consonants bb, dd, ff, gg, mm, nn, pp, rr or tt.
+func (s *englishStemmer) endsWithDouble() bool {
+	n := len(s.w)
+	if n < 2 || s.w[n-1] != s.w[n-2] {
+		return false
+	}
+	switch s.w[n-1] {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func (s *englishStemmer) step1c() {
+	n := len(s.w)
+	if n > 2 && (s.w[n-1] == 'y' || s.w[n-1] == 'Y') && !isEnglishVowel(s.w[n-2]) {
+		s.w[n-1] = 'i'
+	}
+}
+
+// englishStep2Suffixes maps the suffixes handled by step 2 to their
+// replacements.
+var englishStep2Suffixes = map[string]string{
+	"tional":  "tion",
+	"enci":    "ence",
+	"anci":    "ance",
+	"abli":    "able",
+	"entli":   "ent",
+	"izer":    "ize",
+	"ization": "ize",
+	"ational": "ate",
+	"ation":   "ate",
+	"ator":    "ate",
+	"alism":   "al",
+	"aliti":   "al",
+	"alli":    "al",
+	"fulness": "ful",
+	"ousli":   "ous",
+	"ousness": "ous",
+	"iveness": "ive",
+	"iviti":   "ive",
+	"biliti":  "ble",
+	"bli":     "ble",
+	"ogi":     "og",
+	"fulli":   "ful",
+	"lessli":  "less",
+	"li":      "",
+}
+
+func (s *englishStemmer) step2() {
+	suffix := s.longestSuffixIn(englishStep2Suffixes)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	start := s.suffixStart(suffix)
+	switch suffix {
+	case "ogi":
+		if start == 0 || s.w[start-1] != 'l' {
+			return
+		}
+	case "li":
+		if start == 0 || !isValidLiEnding(s.w[start-1]) {
+			return
+		}
+	}
+	s.replace(suffix, englishStep2Suffixes[suffix])
+}
+
+func isValidLiEnding(c rune) bool {
+	switch c {
+	case 'c', 'd', 'e', 'g', 'h', 'k', 'm', 'n', 'r', 't':
+		return true
+	}
+	return false
+}
+
+// englishStep3Suffixes maps the suffixes handled by step 3 to their
+// replacements.
+var englishStep3Suffixes = map[string]string{
+	"tional":  "tion",
+	"ational": "ate",
+	"alize":   "al",
+	"icate":   "ic",
+	"iciti":   "ic",
+	"ical":    "ic",
+	"ful":     "",
+	"ness":    "",
+	"ative":   "",
+}
+
+func (s *englishStemmer) step3() {
+	suffix := s.longestSuffixIn(englishStep3Suffixes)
+	if suffix == "" || !s.inR1(suffix) {
+		return
+	}
+	if suffix == "ative" && !s.inR2(suffix) {
+		return
+	}
+	s.replace(suffix, englishStep3Suffixes[suffix])
+}
+
+// englishStep4Suffixes are the suffixes deleted by step 4.
+var englishStep4Suffixes = map[string]string{
+	"al": "", "ance": "", "ence": "", "er": "", "ic": "", "able": "", "ible": "",
+	"ant": "", "ement": "", "ment": "", "ent": "", "ism": "", "ate": "", "iti": "",
+	"ous": "", "ive": "", "ize": "", "ion": "",
+}
+
+func (s *englishStemmer) step4() {
+	suffix := s.longestSuffixIn(englishStep4Suffixes)
+	if suffix == "" || !s.inR2(suffix) {
+		return
+	}
+	if suffix == "ion" {
+		start := s.suffixStart(suffix)
+		if start == 0 || (s.w[start-1] != 's' && s.w[start-1] != 't') {
+			return
+		}
+	}
+	s.replace(suffix, "")
+}
+
+func (s *englishStemmer) step5() {
+	n := len(s.w)
+	switch s.w[n-1] {
+	case 'e':
+		if s.inR2("e") {
+			s.w = s.w[:n-1]
+			return
+		}
+		if s.inR1("e") {
+			s.w = s.w[:n-1]
+			if s.endsWithShortSyllable() {
+				s.w = append(s.w, 'e')
+			}
+		}
+	case 'l':
+		if s.inR2("l") && n > 1 && s.w[n-2] == 'l' {
+			s.w = s.w[:n-1]
+		}
+	}
+}
+
+func (s *englishStemmer) longestSuffixIn(suffixes map[string]string) string {
+	var res string
+	for suffix := range suffixes {
+		if len(suffix) > len(res) && s.hasSuffix(suffix) {
+			res = suffix
+		}
+	}
+	return res
+}
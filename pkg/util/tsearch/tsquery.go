@@ -0,0 +1,439 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// tsOperator is the operator of an interior node of a tsquery.
+type tsOperator uint8
+
+const (
+	// invalid is the operator of leaf (term) nodes.
+	invalid tsOperator = iota
+	// and is the & operator: both operands must match.
+	and
+	// or is the | operator: at least one operand must match.
+	or
+	// not is the ! operator: the operand must not match.
+	not
+	// followedBy is the <-> (or <N>) operator: the left operand must be
+	// followed by the right one at exactly the given distance.
+	followedBy
+)
+
+// priority returns the binding strength of the operator, used to decide where
+// parentheses are needed when formatting.
+func (o tsOperator) priority() int {
+	switch o {
+	case or:
+		return 1
+	case and:
+		return 2
+	case followedBy:
+		return 3
+	case not:
+		return 4
+	}
+	return 5
+}
+
+// queryTerm is a lexeme in a tsquery.
+type queryTerm struct {
+	lexeme string
+	// weights restricts the positions that the term matches.
+	weights weightMask
+	// prefix is set if the term matches every lexeme that starts with it
+	// (written as 'lexeme':*).
+	prefix bool
+}
+
+func (t queryTerm) String() string {
+	var b strings.Builder
+	writeLexeme(&b, t.lexeme)
+	if t.prefix || t.weights != 0 {
+		b.WriteByte(':')
+		if t.prefix {
+			b.WriteByte('*')
+		}
+		b.WriteString(t.weights.String())
+	}
+	return b.String()
+}
+
+// tsNode is a node of a tsquery expression tree. Leaves hold a term and have
+// the invalid operator; interior nodes hold an operator and one (for not) or
+// two operands.
+type tsNode struct {
+	term queryTerm
+	op   tsOperator
+	// distance is the distance between the operands of a followedBy node.
+	distance uint16
+	l, r     *tsNode
+}
+
+func (n *tsNode) String() string {
+	var b strings.Builder
+	n.format(&b)
+	return b.String()
+}
+
+func (n *tsNode) format(b *strings.Builder) {
+	switch n.op {
+	case invalid:
+		b.WriteString(n.term.String())
+	case not:
+		b.WriteByte('!')
+		n.formatOperand(b, n.l, false /* right */)
+	default:
+		n.formatOperand(b, n.l, false /* right */)
+		switch n.op {
+		case and:
+			b.WriteString(" & ")
+		case or:
+			b.WriteString(" | ")
+		case followedBy:
+			b.WriteString(" <")
+			if n.distance == 1 {
+				b.WriteByte('-')
+			} else {
+				b.WriteString(strconv.Itoa(int(n.distance)))
+			}
+			b.WriteString("> ")
+		}
+		n.formatOperand(b, n.r, true /* right */)
+	}
+}
+
+// formatOperand formats an operand of n, enclosing it in parentheses if it
+// binds less strongly than n.
+func (n *tsNode) formatOperand(b *strings.Builder, operand *tsNode, right bool) {
+	parens := operand.op.priority() < n.op.priority() ||
+		(right && n.op == followedBy && operand.op == followedBy)
+	if parens {
+		b.WriteString("( ")
+	}
+	operand.format(b)
+	if parens {
+		b.WriteString(" )")
+	}
+}
+
+// TSQuery is a full text search query: a boolean combination of lexemes,
+// which can be matched against a TSVector.
+type TSQuery struct {
+	root *tsNode
+}
+
+// String returns the text representation of the tsquery, which can be parsed
+// back with ParseTSQuery.
+func (q TSQuery) String() string {
+	if q.root == nil {
+		return ""
+	}
+	return q.root.String()
+}
+
+// IsEmpty returns whether the query has no terms, which happens when all of
+// the words of the query text were stop words. An empty query matches nothing.
+func (q TSQuery) IsEmpty() bool {
+	return q.root == nil
+}
+
+// NumNodes returns the number of lexemes and operators in the query.
+func (q TSQuery) NumNodes() int {
+	var count func(n *tsNode) int
+	count = func(n *tsNode) int {
+		if n == nil {
+			return 0
+		}
+		return 1 + count(n.l) + count(n.r)
+	}
+	return count(q.root)
+}
+
+// Size returns the approximate size of the tsquery in memory, in bytes.
+func (q TSQuery) Size() uintptr {
+	sz := uintptr(q.NumNodes()) * unsafe.Sizeof(tsNode{})
+	q.root.forEachTerm(func(t *queryTerm) {
+		sz += uintptr(len(t.lexeme))
+	})
+	return sz
+}
+
+// ParseTSQuery parses the text representation of a tsquery, for example:
+//
+//   'fat' & ( 'rat':AB | !'cat' ) <-> 'sup':*
+//
+// The lexemes are taken as is; see Config.ToTSQuery for parsing a query whose
+// words need to be normalized.
+func ParseTSQuery(input string) (TSQuery, error) {
+	return parseTSQuery(input, nil /* normalize */)
+}
+
+// normalizeFn turns a word of a query into the tsquery expression that should
+// replace it. It returns nil if the word is a stop word.
+type normalizeFn func(term queryTerm) (*tsNode, error)
+
+func parseTSQuery(input string, normalize normalizeFn) (TSQuery, error) {
+	p := tsQueryParser{l: tsLexer{input: input}, normalize: normalize}
+	p.l.skipSpace()
+	if p.l.eof() {
+		return TSQuery{}, nil
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return TSQuery{}, err
+	}
+	p.l.skipSpace()
+	if !p.l.eof() {
+		return TSQuery{}, p.l.syntaxError("tsquery")
+	}
+	return TSQuery{root: root}, nil
+}
+
+type tsQueryParser struct {
+	l         tsLexer
+	normalize normalizeFn
+}
+
+// The parse methods return a nil node for subexpressions that consist only of
+// stop words. Such subexpressions are removed from the query, and the
+// operators that had them as operands are replaced by their other operand.
+
+func (p *tsQueryParser) parseOr() (*tsNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.l.skipSpace()
+		if p.l.peek() != '|' {
+			return left, nil
+		}
+		p.l.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinaryNode(or, left, right, 0)
+	}
+}
+
+func (p *tsQueryParser) parseAnd() (*tsNode, error) {
+	left, err := p.parsePhrase()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.l.skipSpace()
+		if p.l.peek() != '&' {
+			return left, nil
+		}
+		p.l.pos++
+		right, err := p.parsePhrase()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinaryNode(and, left, right, 0)
+	}
+}
+
+func (p *tsQueryParser) parsePhrase() (*tsNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.l.skipSpace()
+		if p.l.peek() != '<' {
+			return left, nil
+		}
+		distance, err := p.parseDistance()
+		if err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = makeBinaryNode(followedBy, left, right, distance)
+	}
+}
+
+// maxDistance is the largest distance allowed in a followed by operator.
+const maxDistance = maxPosition
+
+// parseDistance parses a followed by operator, either <-> or <N>.
+func (p *tsQueryParser) parseDistance() (uint16, error) {
+	l := &p.l
+	l.pos++ // '<'
+	if l.peek() == '-' {
+		l.pos++
+		if l.peek() != '>' {
+			return 0, l.syntaxError("tsquery")
+		}
+		l.pos++
+		return 1, nil
+	}
+	start := l.pos
+	for !l.eof() && l.peek() >= '0' && l.peek() <= '9' {
+		l.pos++
+	}
+	if start == l.pos || l.peek() != '>' {
+		return 0, l.syntaxError("tsquery")
+	}
+	n, err := strconv.Atoi(l.input[start:l.pos])
+	if err != nil || n > maxDistance {
+		return 0, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxDistance)
+	}
+	l.pos++ // '>'
+	return uint16(n), nil
+}
+
+func (p *tsQueryParser) parseNot() (*tsNode, error) {
+	p.l.skipSpace()
+	if p.l.peek() != '!' {
+		return p.parsePrimary()
+	}
+	p.l.pos++
+	operand, err := p.parseNot()
+	if err != nil || operand == nil {
+		return nil, err
+	}
+	return &tsNode{op: not, l: operand}, nil
+}
+
+func (p *tsQueryParser) parsePrimary() (*tsNode, error) {
+	l := &p.l
+	l.skipSpace()
+	if l.eof() {
+		return nil, l.syntaxError("tsquery")
+	}
+	if l.peek() == '(' {
+		l.pos++
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		l.skipSpace()
+		if l.peek() != ')' {
+			return nil, l.syntaxError("tsquery")
+		}
+		l.pos++
+		return n, nil
+	}
+	if isQueryOperator(l.peek()) {
+		return nil, l.syntaxError("tsquery")
+	}
+	lexeme, err := l.lexeme(true /* inQuery */)
+	if err != nil {
+		return nil, err
+	}
+	term := queryTerm{lexeme: lexeme}
+	if l.peek() == ':' {
+		l.pos++
+		for !l.eof() {
+			if l.peek() == '*' {
+				term.prefix = true
+			} else if w, ok := parseWeight(l.peek()); ok {
+				term.weights |= w.mask()
+			} else {
+				break
+			}
+			l.pos++
+		}
+	}
+	if p.normalize != nil {
+		return p.normalize(term)
+	}
+	return &tsNode{term: term}, nil
+}
+
+// makeBinaryNode combines two operands with the given operator, dropping the
+// operands that were removed because they only contained stop words.
+func makeBinaryNode(op tsOperator, l, r *tsNode, distance uint16) *tsNode {
+	if l == nil {
+		return r
+	}
+	if r == nil {
+		return l
+	}
+	return &tsNode{op: op, l: l, r: r, distance: distance}
+}
+
+// And returns a query that matches documents that match both q and other.
+func (q TSQuery) And(other TSQuery) TSQuery {
+	return TSQuery{root: makeBinaryNode(and, q.root, other.root, 0)}
+}
+
+// Or returns a query that matches documents that match either q or other.
+func (q TSQuery) Or(other TSQuery) TSQuery {
+	return TSQuery{root: makeBinaryNode(or, q.root, other.root, 0)}
+}
+
+// Not returns a query that matches documents that don't match q.
+func (q TSQuery) Not() TSQuery {
+	if q.root == nil {
+		return q
+	}
+	return TSQuery{root: &tsNode{op: not, l: q.root}}
+}
+
+// Phrase returns a query that matches documents in which a match of q is
+// followed by a match of other at the given distance.
+func (q TSQuery) Phrase(other TSQuery, distance int) (TSQuery, error) {
+	if distance < 0 || distance > maxDistance {
+		return TSQuery{}, pgerror.Newf(pgcode.InvalidParameterValue,
+			"distance in phrase operator must be an integer value between zero and %d inclusive",
+			maxDistance)
+	}
+	return TSQuery{root: makeBinaryNode(followedBy, q.root, other.root, uint16(distance))}, nil
+}
+
+// forEachTerm calls fn on every term of the query, in order.
+func (n *tsNode) forEachTerm(fn func(t *queryTerm)) {
+	if n == nil {
+		return
+	}
+	if n.op == invalid {
+		fn(&n.term)
+		return
+	}
+	n.l.forEachTerm(fn)
+	n.r.forEachTerm(fn)
+}
+
+// positiveTerms returns the terms of the query that are not negated.
+func (n *tsNode) positiveTerms(negated bool, res []queryTerm) []queryTerm {
+	if n == nil {
+		return res
+	}
+	switch n.op {
+	case invalid:
+		if !negated {
+			res = append(res, n.term)
+		}
+		return res
+	case not:
+		return n.l.positiveTerms(!negated, res)
+	}
+	res = n.l.positiveTerms(negated, res)
+	return n.r.positiveTerms(negated, res)
+}
@@ -0,0 +1,182 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+package tsearch
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTSVector(t *testing.T) {
+	testCases := []struct {
+		input, expected, expectedErr string
+	}{
+		{`a fat cat sat on a mat and ate a fat rat`,
+			`'a' 'and' 'ate' 'cat' 'fat' 'mat' 'on' 'rat' 'sat'`, ``},
+		{`a:1 fat:2 cat:3 fat:11`, `'a':1 'cat':3 'fat':2,11`, ``},
+		{`a:1A fat:2B,4C cat:5D`, `'a':1A 'cat':5 'fat':2B,4C`, ``},
+		{`'    ' 'with space' 'Joe''s'`, `'    ' 'Joe''s' 'with space'`, ``},
+		{`a:3,1,3A`, `'a':1,3A`, ``},
+		{`a:0`, ``, `wrong position info in tsvector: "a:0"`},
+		{`'unterminated`, ``, `unterminated quoted string in tsvector: "'unterminated"`},
+		{``, ``, ``},
+	}
+	for _, tc := range testCases {
+		v, err := ParseTSVector(tc.input)
+		if tc.expectedErr != "" {
+			require.EqualError(t, err, tc.expectedErr, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, v.String(), tc.input)
+		// The output must round trip.
+		v2, err := ParseTSVector(v.String())
+		require.NoError(t, err)
+		require.Equal(t, v.String(), v2.String())
+	}
+}
+
+func TestParseTSQuery(t *testing.T) {
+	testCases := []struct {
+		input, expected, expectedErr string
+	}{
+		{`fat & rat`, `'fat' & 'rat'`, ``},
+		{`fat & (rat | cat)`, `'fat' & ( 'rat' | 'cat' )`, ``},
+		{`fat & rat & ! cat`, `'fat' & 'rat' & !'cat'`, ``},
+		{`fat | rat & cat`, `'fat' | 'rat' & 'cat'`, ``},
+		{`fat:ab & cat`, `'fat':AB & 'cat'`, ``},
+		{`super:*`, `'super':*`, ``},
+		{`fat <-> rat`, `'fat' <-> 'rat'`, ``},
+		{`fat <2> (rat <-> cat)`, `'fat' <2> ( 'rat' <-> 'cat' )`, ``},
+		{`(fat <-> rat) <-> cat`, `'fat' <-> 'rat' <-> 'cat'`, ``},
+		{`!(fat | rat)`, `!( 'fat' | 'rat' )`, ``},
+		{`fat <0> rat`, `'fat' <0> 'rat'`, ``},
+		{`fat <20000> rat`, ``,
+			`distance in phrase operator must be an integer value between zero and 16383 inclusive`},
+		{`fat & `, ``, `syntax error in tsquery: "fat & "`},
+		{`(fat`, ``, `syntax error in tsquery: "(fat"`},
+		{``, ``, ``},
+	}
+	for _, tc := range testCases {
+		q, err := ParseTSQuery(tc.input)
+		if tc.expectedErr != "" {
+			require.EqualError(t, err, tc.expectedErr, tc.input)
+			continue
+		}
+		require.NoError(t, err, tc.input)
+		require.Equal(t, tc.expected, q.String(), tc.input)
+		// The output must round trip.
+		q2, err := ParseTSQuery(q.String())
+		require.NoError(t, err)
+		require.Equal(t, q.String(), q2.String())
+	}
+}
+
+func TestEvalTSQuery(t *testing.T) {
+	testCases := []struct {
+		vector, query string
+		expected      bool
+	}{
+		{`a fat cat`, `cat`, true},
+		{`a fat cat`, `rat`, false},
+		{`a fat cat`, `fat & cat`, true},
+		{`a fat cat`, `fat & rat`, false},
+		{`a fat cat`, `rat | cat`, true},
+		{`a fat cat`, `!rat`, true},
+		{`a fat cat`, `fat & !cat`, false},
+		{`a fat cat`, `fa:*`, true},
+		{`a fat cat`, `fatter:*`, false},
+		// Lexemes without positions match phrases and weights.
+		{`a fat cat`, `fat <-> cat`, true},
+		{`a fat cat`, `fat:A`, true},
+		{`a:1 fat:2 cat:3`, `fat <-> cat`, true},
+		{`a:1 fat:2 cat:3`, `cat <-> fat`, false},
+		{`a:1 fat:2 cat:3`, `a <2> cat`, true},
+		{`a:1 fat:2 cat:3`, `a <-> cat`, false},
+		{`a:1 fat:2 cat:3`, `a <-> (fat | rat)`, true},
+		{`a:1 fat:2 cat:3`, `a <-> !cat`, true},
+		{`a:1 fat:2 cat:3`, `a <-> !fat`, false},
+		{`a:1 fat:2 cat:3`, `a <-> fat <-> cat`, true},
+		{`a:1 fat:2 cat:3`, `fat <0> fat`, true},
+		{`a:1 fat:2A cat:3`, `fat:A`, true},
+		{`a:1 fat:2A cat:3`, `fat:BC`, false},
+		{`a:1 fat:2A cat:3`, `fat:A <-> cat`, true},
+		{`a:1 fat:2A cat:3`, `fat:B <-> cat`, false},
+		{`a fat cat`, ``, false},
+	}
+	for _, tc := range testCases {
+		v, err := ParseTSVector(tc.vector)
+		require.NoError(t, err)
+		q, err := ParseTSQuery(tc.query)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, EvalTSQuery(q, v), "%s @@ %s", tc.vector, tc.query)
+	}
+}
+
+func TestTSQueryCombinators(t *testing.T) {
+	fat, err := ParseTSQuery(`fat`)
+	require.NoError(t, err)
+	rat, err := ParseTSQuery(`rat | cat`)
+	require.NoError(t, err)
+	require.Equal(t, `'fat' & ( 'rat' | 'cat' )`, fat.And(rat).String())
+	require.Equal(t, `'fat' | 'rat' | 'cat'`, fat.Or(rat).String())
+	require.Equal(t, `!'fat'`, fat.Not().String())
+	p, err := fat.Phrase(rat, 10)
+	require.NoError(t, err)
+	require.Equal(t, `'fat' <10> ( 'rat' | 'cat' )`, p.String())
+	require.Equal(t, 5, p.NumNodes())
+	_, err = fat.Phrase(rat, -1)
+	require.Error(t, err)
+}
+
+func TestEncoding(t *testing.T) {
+	for _, s := range []string{``, `a`, `a:1A fat:2B,4C cat:5D 'Joe''s':16383`} {
+		v, err := ParseTSVector(s)
+		require.NoError(t, err)
+		decoded, err := DecodeTSVector(EncodeTSVector(nil, v))
+		require.NoError(t, err)
+		require.Equal(t, v.String(), decoded.String())
+	}
+	for _, s := range []string{``, `a`, `'fat':AB & !( 'rat' | 'cat':* ) <3> 'mat'`} {
+		q, err := ParseTSQuery(s)
+		require.NoError(t, err)
+		decoded, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+		require.NoError(t, err)
+		require.Equal(t, q.String(), decoded.String())
+	}
+	_, err := DecodeTSVector([]byte{0, 0, 0, 1, 'a'})
+	require.Error(t, err)
+	_, err = DecodeTSQuery([]byte{0, 0, 0, 3, queryItemOper, queryOpAnd, queryItemVal, 0, 0, 'a', 0})
+	require.Error(t, err)
+}
+
+func TestRandomRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		v := RandomTSVector(rng)
+		v2, err := ParseTSVector(v.String())
+		require.NoError(t, err)
+		require.Equal(t, v.String(), v2.String())
+		decoded, err := DecodeTSVector(EncodeTSVector(nil, v))
+		require.NoError(t, err)
+		require.Equal(t, v.String(), decoded.String())
+
+		q := RandomTSQuery(rng)
+		q2, err := ParseTSQuery(q.String())
+		require.NoError(t, err, q.String())
+		require.Equal(t, q.String(), q2.String())
+		decodedQ, err := DecodeTSQuery(EncodeTSQuery(nil, q))
+		require.NoError(t, err)
+		require.Equal(t, q.String(), decodedQ.String())
+	}
+}
@@ -0,0 +1,502 @@
+// Copyright 2021 The Cockroach Authors.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.txt.
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0, included in the file
+// licenses/APL.txt.
+
+// Package tsearch implements the tsvector and tsquery types used for full text
+// search, along with the text search configurations that turn documents and
+// queries into them.
+package tsearch
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgcode"
+	"github.com/cockroachdb/cockroach/pkg/sql/pgwire/pgerror"
+)
+
+// tsWeight is the weight of a lexeme position, used to mark lexemes that come
+// from different parts of a document (for example, a title versus a body).
+// The weights are, in decreasing order of importance, A, B, C and D. A
+// position without an explicit weight has weight D.
+type tsWeight uint8
+
+const (
+	weightD tsWeight = iota
+	weightC
+	weightB
+	weightA
+)
+
+// weightMask is a set of weights, used by tsquery terms to restrict the
+// positions they match. The zero value matches every weight.
+type weightMask uint8
+
+func (w tsWeight) mask() weightMask {
+	return 1 << w
+}
+
+func (w tsWeight) String() string {
+	switch w {
+	case weightA:
+		return "A"
+	case weightB:
+		return "B"
+	case weightC:
+		return "C"
+	default:
+		return "D"
+	}
+}
+
+func parseWeight(c byte) (tsWeight, bool) {
+	switch c {
+	case 'a', 'A':
+		return weightA, true
+	case 'b', 'B':
+		return weightB, true
+	case 'c', 'C':
+		return weightC, true
+	case 'd', 'D':
+		return weightD, true
+	}
+	return 0, false
+}
+
+func (m weightMask) matches(w tsWeight) bool {
+	return m == 0 || m&w.mask() != 0
+}
+
+func (m weightMask) String() string {
+	var b strings.Builder
+	for w := weightA; ; w-- {
+		if m&w.mask() != 0 {
+			b.WriteString(w.String())
+		}
+		if w == weightD {
+			break
+		}
+	}
+	return b.String()
+}
+
+// maxPosition is the largest position that can be stored in a tsvector.
+// Larger positions are silently clamped to it, as in Postgres.
+const maxPosition = 16383
+
+// maxPositionsPerLexeme is the maximum number of positions that are kept for a
+// single lexeme. Additional positions are silently dropped, as in Postgres.
+const maxPositionsPerLexeme = 256
+
+// tsPosition is a position of a lexeme in a document, along with its weight.
+type tsPosition struct {
+	position uint16
+	weight   tsWeight
+}
+
+// tsTerm is a lexeme in a tsvector along with the positions at which it occurs
+// in the document. The positions are sorted and unique.
+type tsTerm struct {
+	lexeme    string
+	positions []tsPosition
+}
+
+// TSVector is a sorted list of distinct lexemes, each with an optional list of
+// positions. It is the representation of a document that is optimized for
+// full text search.
+type TSVector []tsTerm
+
+// String returns the text representation of the tsvector, which can be
+// parsed back with ParseTSVector.
+func (v TSVector) String() string {
+	var b strings.Builder
+	for i := range v {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		writeLexeme(&b, v[i].lexeme)
+		for j, p := range v[i].positions {
+			if j == 0 {
+				b.WriteByte(':')
+			} else {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.Itoa(int(p.position)))
+			if p.weight != weightD {
+				b.WriteString(p.weight.String())
+			}
+		}
+	}
+	return b.String()
+}
+
+// writeLexeme writes the quoted form of a lexeme, escaping quotes and
+// backslashes.
+func writeLexeme(b *strings.Builder, lexeme string) {
+	b.WriteByte('\'')
+	for i := 0; i < len(lexeme); i++ {
+		switch c := lexeme[i]; c {
+		case '\'':
+			b.WriteString("''")
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+}
+
+// Lexemes returns the lexemes of the tsvector, in sorted order.
+func (v TSVector) Lexemes() []string {
+	res := make([]string, len(v))
+	for i := range v {
+		res[i] = v[i].lexeme
+	}
+	return res
+}
+
+// NumLexemes returns the number of distinct lexemes in the tsvector.
+func (v TSVector) NumLexemes() int {
+	return len(v)
+}
+
+// Strip returns a copy of the tsvector without position information.
+func (v TSVector) Strip() TSVector {
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i].lexeme = v[i].lexeme
+	}
+	return res
+}
+
+// SetWeight returns a copy of the tsvector in which every position has the
+// given weight. If lexemes is non-nil, only the positions of the listed
+// lexemes are changed.
+func (v TSVector) SetWeight(weight byte, lexemes []string) (TSVector, error) {
+	w, ok := parseWeight(weight)
+	if !ok {
+		return nil, pgerror.Newf(pgcode.InvalidParameterValue, "unrecognized weight: %q", weight)
+	}
+	var filter map[string]struct{}
+	if lexemes != nil {
+		filter = make(map[string]struct{}, len(lexemes))
+		for _, l := range lexemes {
+			filter[l] = struct{}{}
+		}
+	}
+	res := make(TSVector, len(v))
+	for i := range v {
+		res[i].lexeme = v[i].lexeme
+		res[i].positions = append([]tsPosition(nil), v[i].positions...)
+		if _, ok := filter[v[i].lexeme]; filter != nil && !ok {
+			continue
+		}
+		for j := range res[i].positions {
+			res[i].positions[j].weight = w
+		}
+	}
+	return res, nil
+}
+
+// Concat returns the concatenation of two tsvectors. The positions of the
+// right tsvector are shifted past the largest position of the left one, as if
+// the two documents had been concatenated.
+func (v TSVector) Concat(other TSVector) TSVector {
+	var maxPos uint16
+	for i := range v {
+		for _, p := range v[i].positions {
+			if p.position > maxPos {
+				maxPos = p.position
+			}
+		}
+	}
+	terms := make([]tsTerm, 0, len(v)+len(other))
+	for i := range v {
+		terms = append(terms, tsTerm{
+			lexeme:    v[i].lexeme,
+			positions: append([]tsPosition(nil), v[i].positions...),
+		})
+	}
+	for i := range other {
+		t := tsTerm{lexeme: other[i].lexeme}
+		for _, p := range other[i].positions {
+			pos := int(p.position) + int(maxPos)
+			if pos > maxPosition {
+				pos = maxPosition
+			}
+			t.positions = append(t.positions, tsPosition{position: uint16(pos), weight: p.weight})
+		}
+		terms = append(terms, t)
+	}
+	return normalizeTerms(terms)
+}
+
+// MakeTSVector returns a tsvector containing the given lexemes, without
+// positions. Duplicate lexemes are removed.
+func MakeTSVector(lexemes []string) (TSVector, error) {
+	terms := make([]tsTerm, len(lexemes))
+	for i, l := range lexemes {
+		if l == "" {
+			return nil, pgerror.New(pgcode.ZeroLengthCharacterString,
+				"lexeme array may not contain empty strings")
+		}
+		terms[i].lexeme = l
+	}
+	return normalizeTerms(terms), nil
+}
+
+// find returns the index of the given lexeme in the tsvector, or -1 if it is
+// not present.
+func (v TSVector) find(lexeme string) int {
+	i := sort.Search(len(v), func(i int) bool { return v[i].lexeme >= lexeme })
+	if i < len(v) && v[i].lexeme == lexeme {
+		return i
+	}
+	return -1
+}
+
+// findPrefix returns the range [start, end) of indexes of the lexemes in the
+// tsvector that start with the given prefix.
+func (v TSVector) findPrefix(prefix string) (start, end int) {
+	start = sort.Search(len(v), func(i int) bool { return v[i].lexeme >= prefix })
+	end = start
+	for end < len(v) && strings.HasPrefix(v[end].lexeme, prefix) {
+		end++
+	}
+	return start, end
+}
+
+// Size returns the approximate size of the tsvector in memory, in bytes.
+func (v TSVector) Size() uintptr {
+	sz := uintptr(cap(v)) * unsafe.Sizeof(tsTerm{})
+	for i := range v {
+		sz += uintptr(len(v[i].lexeme)) + uintptr(cap(v[i].positions))*unsafe.Sizeof(tsPosition{})
+	}
+	return sz
+}
+
+// normalizeTerms sorts the given terms by lexeme and merges the positions of
+// duplicate lexemes, returning a valid TSVector.
+func normalizeTerms(terms []tsTerm) TSVector {
+	if len(terms) == 0 {
+		return TSVector{}
+	}
+	sort.SliceStable(terms, func(i, j int) bool { return terms[i].lexeme < terms[j].lexeme })
+	res := terms[:1]
+	for _, t := range terms[1:] {
+		last := &res[len(res)-1]
+		if t.lexeme == last.lexeme {
+			last.positions = append(last.positions, t.positions...)
+			continue
+		}
+		res = append(res, t)
+	}
+	for i := range res {
+		res[i].positions = normalizePositions(res[i].positions)
+	}
+	return res
+}
+
+// normalizePositions sorts positions and removes duplicates. When a position
+// appears several times, the highest weight is kept.
+func normalizePositions(positions []tsPosition) []tsPosition {
+	if len(positions) == 0 {
+		return nil
+	}
+	sort.SliceStable(positions, func(i, j int) bool { return positions[i].position < positions[j].position })
+	res := positions[:1]
+	for _, p := range positions[1:] {
+		last := &res[len(res)-1]
+		if p.position == last.position {
+			if p.weight > last.weight {
+				last.weight = p.weight
+			}
+			continue
+		}
+		res = append(res, p)
+	}
+	if len(res) > maxPositionsPerLexeme {
+		res = res[:maxPositionsPerLexeme]
+	}
+	return res
+}
+
+// ParseTSVector parses the text representation of a tsvector. The input is a
+// whitespace-separated list of lexemes, each of which can be quoted and
+// followed by a colon and a comma-separated list of positions with optional
+// weights, for example:
+//
+//   'a' 'fat':2A,4 cat:3
+//
+func ParseTSVector(input string) (TSVector, error) {
+	l := tsLexer{input: input}
+	var terms []tsTerm
+	for {
+		l.skipSpace()
+		if l.eof() {
+			break
+		}
+		lexeme, err := l.lexeme(false /* inQuery */)
+		if err != nil {
+			return nil, err
+		}
+		t := tsTerm{lexeme: lexeme}
+		if l.peek() == ':' {
+			l.pos++
+			for {
+				p, err := l.position()
+				if err != nil {
+					return nil, err
+				}
+				t.positions = append(t.positions, p)
+				if l.peek() != ',' {
+					break
+				}
+				l.pos++
+			}
+		}
+		if !l.eof() && !isSpace(l.peek()) {
+			return nil, l.syntaxError("tsvector")
+		}
+		terms = append(terms, t)
+	}
+	return normalizeTerms(terms), nil
+}
+
+// position parses a position and its optional weight.
+func (l *tsLexer) position() (tsPosition, error) {
+	start := l.pos
+	for !l.eof() && l.peek() >= '0' && l.peek() <= '9' {
+		l.pos++
+	}
+	if start == l.pos {
+		return tsPosition{}, l.syntaxError("tsvector")
+	}
+	n, err := strconv.Atoi(l.input[start:l.pos])
+	if err != nil || n > maxPosition {
+		n = maxPosition
+	}
+	if n == 0 {
+		return tsPosition{}, pgerror.Newf(pgcode.Syntax,
+			"wrong position info in tsvector: %q", l.input)
+	}
+	p := tsPosition{position: uint16(n)}
+	if !l.eof() {
+		if w, ok := parseWeight(l.peek()); ok {
+			p.weight = w
+			l.pos++
+		}
+	}
+	return p, nil
+}
+
+// tsLexer tokenizes the text representations of tsvectors and tsqueries.
+type tsLexer struct {
+	input string
+	pos   int
+}
+
+func (l *tsLexer) eof() bool {
+	return l.pos >= len(l.input)
+}
+
+func (l *tsLexer) peek() byte {
+	if l.eof() {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func (l *tsLexer) skipSpace() {
+	for !l.eof() && isSpace(l.peek()) {
+		l.pos++
+	}
+}
+
+func (l *tsLexer) syntaxError(typ string) error {
+	return pgerror.Newf(pgcode.Syntax, "syntax error in %s: %q", typ, l.input)
+}
+
+// isQueryOperator returns whether c is a character with a special meaning in
+// the tsquery syntax, which terminates unquoted lexemes.
+func isQueryOperator(c byte) bool {
+	switch c {
+	case '&', '|', '!', '(', ')', '<':
+		return true
+	}
+	return false
+}
+
+// lexeme parses a possibly quoted lexeme. Backslashes escape the following
+// character, and inside quotes a doubled quote stands for a single one.
+// Unquoted lexemes end at whitespace, at a colon and, in tsqueries, at
+// operator characters.
+func (l *tsLexer) lexeme(inQuery bool) (string, error) {
+	typ := "tsvector"
+	if inQuery {
+		typ = "tsquery"
+	}
+	var b strings.Builder
+	if l.peek() == '\'' {
+		l.pos++
+		for {
+			if l.eof() {
+				return "", pgerror.Newf(pgcode.Syntax, "unterminated quoted string in %s: %q", typ, l.input)
+			}
+			c := l.peek()
+			l.pos++
+			switch c {
+			case '\\':
+				if l.eof() {
+					return "", pgerror.Newf(pgcode.Syntax, "there is no escaped character: %q", l.input)
+				}
+				b.WriteByte(l.peek())
+				l.pos++
+				continue
+			case '\'':
+				if l.peek() == '\'' {
+					b.WriteByte('\'')
+					l.pos++
+					continue
+				}
+			default:
+				b.WriteByte(c)
+				continue
+			}
+			break
+		}
+	} else {
+		for !l.eof() {
+			c := l.peek()
+			if isSpace(c) || c == ':' || (inQuery && isQueryOperator(c)) {
+				break
+			}
+			if c == '\\' {
+				l.pos++
+				if l.eof() {
+					return "", pgerror.Newf(pgcode.Syntax, "there is no escaped character: %q", l.input)
+				}
+				c = l.peek()
+			}
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	if b.Len() == 0 {
+		return "", l.syntaxError(typ)
+	}
+	return b.String(), nil
+}